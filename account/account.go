@@ -75,6 +75,12 @@ func GetAccount(ctx context.Context, cfg *config.Configuration, fetcher stored_r
 			errs = append(errs, err)
 			return nil, errs
 		}
+		if err := validateAccount(account, cfg); err != nil {
+			return nil, []error{&errortypes.MalformedAcct{
+				Message: fmt.Sprintf("The prebid-server account config for account id \"%s\" is malformed: %v. Please reach out to the prebid server host.", accountID, err),
+			}}
+		}
+
 		// Fill in ID if needed, so it can be left out of account definition
 		if len(account.ID) == 0 {
 			account.ID = accountID
@@ -99,6 +105,29 @@ var TCF2Enforcements = map[string]config.TCF2EnforcementAlgo{
 	config.TCF2EnforceAlgoFull:  config.TCF2FullEnforcement,
 }
 
+// validateAccount checks the account settings which can't be fixed up at runtime
+func validateAccount(account *config.Account, cfg *config.Configuration) error {
+	if err := account.Activities.Validate(); err != nil {
+		return err
+	}
+	if err := account.Auction.Validate(); err != nil {
+		return err
+	}
+	if err := account.BidValidations.Validate(); err != nil {
+		return err
+	}
+	if err := account.Blocking.Validate(); err != nil {
+		return err
+	}
+	if err := account.Targeting.Validate(); err != nil {
+		return err
+	}
+	if err := account.Cookie.Validate(cfg.HostCookie.Domain); err != nil {
+		return err
+	}
+	return account.CookieSync.Validate()
+}
+
 // setDerivedConfig modifies an account object by setting fields derived from other fields set in the account configuration
 func setDerivedConfig(account *config.Account) {
 	account.GDPR.PurposeConfigs = map[consentconstants.Purpose]*config.AccountGDPRPurpose{
@@ -19,6 +19,10 @@ var mockAccountData = map[string]json.RawMessage{
 	"valid_acct":        json.RawMessage(`{"disabled":false}`),
 	"disabled_acct":     json.RawMessage(`{"disabled":true}`),
 	"malformed_acct":    json.RawMessage(`{"disabled":"invalid type"}`),
+	"bad_activity_acct": json.RawMessage(`{"disabled":false,"activities":{"syncUser":{"rules":[{"condition":{"componentType":["publisher"]}}]}}}`),
+	"bad_auction_acct":  json.RawMessage(`{"disabled":false,"auction":{"type":"third_price"}}`),
+	"bad_bidval_acct":   json.RawMessage(`{"disabled":false,"bidvalidations":{"secure_markup":"reject"}}`),
+	"bad_blocking_acct": json.RawMessage(`{"disabled":false,"blocking":{"bidder_modes":{"appnexus":"log"}}}`),
 	"gdpr_convert_acct": json.RawMessage(`{"disabled":false,"gdpr":{"purpose5":{"enforce_purpose":"full"}}}`),
 }
 
@@ -82,6 +86,12 @@ func TestGetAccount(t *testing.T) {
 		{accountID: "malformed_acct", required: false, disabled: true, err: &errortypes.MalformedAcct{}},
 		{accountID: "malformed_acct", required: true, disabled: true, err: &errortypes.MalformedAcct{}},
 
+		// pubID given and matches a host account with invalid activity rules
+		{accountID: "bad_activity_acct", required: false, disabled: false, err: &errortypes.MalformedAcct{}},
+		{accountID: "bad_auction_acct", required: false, disabled: false, err: &errortypes.MalformedAcct{}},
+		{accountID: "bad_bidval_acct", required: false, disabled: false, err: &errortypes.MalformedAcct{}},
+		{accountID: "bad_blocking_acct", required: false, disabled: false, err: &errortypes.MalformedAcct{}},
+
 		// account not provided (does not exist)
 		{accountID: "", required: false, disabled: false, err: nil},
 		{accountID: "", required: true, disabled: false, err: nil},
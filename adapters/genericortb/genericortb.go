@@ -0,0 +1,277 @@
+package genericortb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/macros"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+type adapter struct {
+	endpoint          *template.Template
+	impParamsLocation string
+	headers           map[string]string
+	bidType           string
+	bidTypeExtPath    []string
+	currency          string
+	allowedHosts      map[string]struct{}
+}
+
+// Builder builds a new instance of the generic OpenRTB adapter for the given bidder with the given config.
+// The bidder is described by the genericOrtb section of its bidder info.
+func Builder(bidderName openrtb_ext.BidderName, config config.Adapter, server config.Server) (adapters.Bidder, error) {
+	template, err := template.New("endpointTemplate").Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse endpoint url template: %v", err)
+	}
+
+	bidder := &adapter{
+		endpoint: template,
+	}
+	if info := config.GenericORTB; info != nil {
+		bidder.impParamsLocation = info.ImpParamsLocation
+		bidder.headers = info.Headers
+		bidder.bidType = info.BidType
+		if info.BidTypeExtPath != "" {
+			bidder.bidTypeExtPath = strings.Split(info.BidTypeExtPath, ".")
+		}
+		bidder.currency = info.Currency
+		bidder.allowedHosts = make(map[string]struct{}, len(info.AllowedHosts))
+		for _, host := range info.AllowedHosts {
+			bidder.allowedHosts[host] = struct{}{}
+		}
+	}
+	return bidder, nil
+}
+
+func (a *adapter) MakeRequests(request *openrtb2.BidRequest, requestInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	var errs []error
+	var urls []string
+	impsByURL := make(map[string][]openrtb2.Imp)
+	for _, imp := range request.Imp {
+		params, err := a.prepareImp(&imp)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		url, err := a.buildEndpointURL(params)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, ok := impsByURL[url]; !ok {
+			urls = append(urls, url)
+		}
+		impsByURL[url] = append(impsByURL[url], imp)
+	}
+
+	// the imps are sent to the endpoint their own params resolve to
+	requests := make([]*adapters.RequestData, 0, len(urls))
+	for _, url := range urls {
+		requestCopy := *request
+		requestCopy.Imp = impsByURL[url]
+		requestJSON, err := json.Marshal(requestCopy)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		requests = append(requests, &adapters.RequestData{
+			Method:  http.MethodPost,
+			Uri:     url,
+			Body:    requestJSON,
+			Headers: a.getHeaders(),
+		})
+	}
+	return requests, errs
+}
+
+func (a *adapter) buildEndpointURL(params *openrtb_ext.ExtImpGenericORTB) (string, error) {
+	if params.Host != "" {
+		if _, ok := a.allowedHosts[params.Host]; !ok {
+			return "", &errortypes.BadInput{
+				Message: fmt.Sprintf("host %s is not allowed", params.Host),
+			}
+		}
+	}
+
+	return macros.ResolveMacros(a.endpoint, macros.EndpointTemplateParams{
+		Host:        params.Host,
+		PublisherID: params.PublisherID,
+		ZoneID:      params.ZoneID,
+		SourceId:    params.SourceID,
+		AccountID:   params.AccountID,
+		AdUnit:      params.AdUnit,
+	})
+}
+
+// prepareImp moves the bidder params of the imp to the configured location and returns the endpoint macros
+// they hold
+func (a *adapter) prepareImp(imp *openrtb2.Imp) (*openrtb_ext.ExtImpGenericORTB, error) {
+	var bidderExt adapters.ExtImpBidder
+	if err := json.Unmarshal(imp.Ext, &bidderExt); err != nil {
+		return nil, &errortypes.BadInput{
+			Message: fmt.Sprintf("imp %s: ext.bidder not provided", imp.ID),
+		}
+	}
+
+	var params openrtb_ext.ExtImpGenericORTB
+	if err := json.Unmarshal(bidderExt.Bidder, &params); err != nil {
+		return nil, &errortypes.BadInput{
+			Message: fmt.Sprintf("imp %s: invalid ext.bidder: %v", imp.ID, err),
+		}
+	}
+
+	if a.impParamsLocation == config.GenericORTBImpParamsExt {
+		impExt, err := mergeBidderParams(imp.Ext, bidderExt.Bidder)
+		if err != nil {
+			return nil, &errortypes.BadInput{
+				Message: fmt.Sprintf("imp %s: invalid ext.bidder: %v", imp.ID, err),
+			}
+		}
+		imp.Ext = impExt
+	}
+	return &params, nil
+}
+
+// mergeBidderParams moves the bidder params from imp.ext.bidder to imp.ext itself. The other fields of
+// imp.ext, such as prebid, gpid and data, are kept and take precedence over params with the same name.
+func mergeBidderParams(impExt, bidderParams json.RawMessage) (json.RawMessage, error) {
+	var ext map[string]json.RawMessage
+	if err := json.Unmarshal(impExt, &ext); err != nil {
+		return nil, err
+	}
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(bidderParams, &params); err != nil {
+		return nil, err
+	}
+
+	delete(ext, "bidder")
+	for name, value := range params {
+		if _, ok := ext[name]; !ok {
+			ext[name] = value
+		}
+	}
+	return json.Marshal(ext)
+}
+
+func (a *adapter) getHeaders() http.Header {
+	headers := http.Header{}
+	headers.Add("Content-Type", "application/json;charset=utf-8")
+	headers.Add("Accept", "application/json")
+	for name, value := range a.headers {
+		headers.Set(name, value)
+	}
+	return headers
+}
+
+func (a *adapter) MakeBids(request *openrtb2.BidRequest, requestData *adapters.RequestData, responseData *adapters.ResponseData) (*adapters.BidderResponse, []error) {
+	if responseData.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+
+	if responseData.StatusCode == http.StatusBadRequest {
+		return nil, []error{&errortypes.BadInput{
+			Message: fmt.Sprintf("Unexpected status code: %d. Run with request.debug = 1 for more info", responseData.StatusCode),
+		}}
+	}
+
+	if responseData.StatusCode != http.StatusOK {
+		return nil, []error{&errortypes.BadServerResponse{
+			Message: fmt.Sprintf("Unexpected status code: %d. Run with request.debug = 1 for more info", responseData.StatusCode),
+		}}
+	}
+
+	var response openrtb2.BidResponse
+	if err := json.Unmarshal(responseData.Body, &response); err != nil {
+		return nil, []error{&errortypes.BadServerResponse{
+			Message: fmt.Sprintf("Bad server response: %v", err),
+		}}
+	}
+
+	bidResponse := adapters.NewBidderResponseWithBidsCapacity(len(request.Imp))
+	if response.Cur != "" {
+		bidResponse.Currency = response.Cur
+	} else if a.currency != "" {
+		bidResponse.Currency = a.currency
+	}
+
+	var errs []error
+	for _, seatBid := range response.SeatBid {
+		for i := range seatBid.Bid {
+			bid := &seatBid.Bid[i]
+			bidType, err := a.getBidType(bid, request.Imp)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			bidResponse.Bids = append(bidResponse.Bids, &adapters.TypedBid{
+				Bid:     bid,
+				BidType: bidType,
+			})
+		}
+	}
+	return bidResponse, errs
+}
+
+func (a *adapter) getBidType(bid *openrtb2.Bid, imps []openrtb2.Imp) (openrtb_ext.BidType, error) {
+	switch a.bidType {
+	case config.GenericORTBBidTypeExt:
+		value, err := jsonparser.GetString(bid.Ext, a.bidTypeExtPath...)
+		if err != nil {
+			return "", &errortypes.BadServerResponse{
+				Message: fmt.Sprintf("Failed to find the bid type in bid.ext.%s of bid %s", strings.Join(a.bidTypeExtPath, "."), bid.ID),
+			}
+		}
+		bidType, err := openrtb_ext.ParseBidType(value)
+		if err != nil {
+			return "", &errortypes.BadServerResponse{
+				Message: fmt.Sprintf("Unsupported bid type %s in bid.ext.%s of bid %s", value, strings.Join(a.bidTypeExtPath, "."), bid.ID),
+			}
+		}
+		return bidType, nil
+	case config.GenericORTBBidTypeImp:
+		for _, imp := range imps {
+			if imp.ID != bid.ImpID {
+				continue
+			}
+			switch {
+			case imp.Banner != nil:
+				return openrtb_ext.BidTypeBanner, nil
+			case imp.Video != nil:
+				return openrtb_ext.BidTypeVideo, nil
+			case imp.Audio != nil:
+				return openrtb_ext.BidTypeAudio, nil
+			case imp.Native != nil:
+				return openrtb_ext.BidTypeNative, nil
+			}
+		}
+		return "", &errortypes.BadServerResponse{
+			Message: fmt.Sprintf("Failed to find the media type of impression %s for bid %s", bid.ImpID, bid.ID),
+		}
+	}
+
+	switch bid.MType {
+	case openrtb2.MarkupBanner:
+		return openrtb_ext.BidTypeBanner, nil
+	case openrtb2.MarkupVideo:
+		return openrtb_ext.BidTypeVideo, nil
+	case openrtb2.MarkupAudio:
+		return openrtb_ext.BidTypeAudio, nil
+	case openrtb2.MarkupNative:
+		return openrtb_ext.BidTypeNative, nil
+	}
+	return "", &errortypes.BadServerResponse{
+		Message: fmt.Sprintf("Unsupported mtype %d for bid %s", bid.MType, bid.ID),
+	}
+}
@@ -0,0 +1,142 @@
+package genericortb
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/adapters/adapterstest"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonSamples(t *testing.T) {
+	bidder, buildErr := Builder(openrtb_ext.BidderGenericORTB, config.Adapter{
+		Endpoint: "http://test.example.com/bid?account={{.AccountID}}"}, config.Server{ExternalUrl: "http://hosturl.com", GvlID: 1, DataCenter: "2"})
+
+	if buildErr != nil {
+		t.Fatalf("Builder returned unexpected error %v", buildErr)
+	}
+
+	adapterstest.RunJSONBidderTest(t, "genericortbtest", bidder)
+}
+
+func TestEndpointTemplateMalformed(t *testing.T) {
+	_, buildErr := Builder(openrtb_ext.BidderGenericORTB, config.Adapter{
+		Endpoint: "{{Malformed}}"}, config.Server{ExternalUrl: "http://hosturl.com", GvlID: 1, DataCenter: "2"})
+
+	assert.Error(t, buildErr)
+}
+
+func TestMakeRequestsWithConfig(t *testing.T) {
+	bidder, buildErr := Builder(openrtb_ext.BidderGenericORTB, config.Adapter{
+		Endpoint: "https://{{.Host}}.example.com/bid",
+		GenericORTB: &config.GenericORTBInfo{
+			ImpParamsLocation: config.GenericORTBImpParamsExt,
+			Headers:           map[string]string{"X-Api-Key": "key", "Accept": "*/*"},
+			AllowedHosts:      []string{"east", "west"},
+		},
+	}, config.Server{})
+	if !assert.NoError(t, buildErr) {
+		return
+	}
+
+	request := &openrtb2.BidRequest{
+		ID: "request",
+		Imp: []openrtb2.Imp{
+			{ID: "imp1", Ext: json.RawMessage(`{"bidder":{"host":"east","placement":"abc","gpid":"param"},"prebid":{"is_rewarded_inventory":1},"gpid":"/1/home","data":{"pbadslot":"home"}}`)},
+			{ID: "imp2", Ext: json.RawMessage(`{"bidder":{"host":"west","placement":"def"}}`)},
+			{ID: "imp3", Ext: json.RawMessage(`{"bidder":{"host":"attacker.com/","placement":"ghi"}}`)},
+			{ID: "imp4", Ext: json.RawMessage(`{"bidder":{"host":"east","placement":"jkl"}}`)},
+		},
+	}
+
+	requests, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+
+	assert.Equal(t, []error{&errortypes.BadInput{Message: "host attacker.com/ is not allowed"}}, errs)
+	if assert.Len(t, requests, 2) {
+		assert.Equal(t, "https://east.example.com/bid", requests[0].Uri)
+		assert.Equal(t, "key", requests[0].Headers.Get("X-Api-Key"))
+		assert.Equal(t, "*/*", requests[0].Headers.Get("Accept"))
+		assert.Equal(t, "application/json;charset=utf-8", requests[0].Headers.Get("Content-Type"))
+		assert.JSONEq(t, `{"id":"request","imp":[
+			{"id":"imp1","ext":{"host":"east","placement":"abc","prebid":{"is_rewarded_inventory":1},"gpid":"/1/home","data":{"pbadslot":"home"}}},
+			{"id":"imp4","ext":{"host":"east","placement":"jkl"}}
+		]}`, string(requests[0].Body))
+
+		assert.Equal(t, "https://west.example.com/bid", requests[1].Uri)
+		assert.JSONEq(t, `{"id":"request","imp":[{"id":"imp2","ext":{"host":"west","placement":"def"}}]}`, string(requests[1].Body))
+	}
+}
+
+func TestMakeBidsWithConfig(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		Imp: []openrtb2.Imp{
+			{ID: "banner", Banner: &openrtb2.Banner{}},
+			{ID: "video", Video: &openrtb2.Video{}},
+		},
+	}
+	response := `{"seatbid":[{"bid":[
+		{"id":"bid1","impid":"banner","price":1,"ext":{"prebid":{"type":"banner"}}},
+		{"id":"bid2","impid":"video","price":1,"ext":{"prebid":{"type":"video"}}},
+		{"id":"bid3","impid":"unknown","price":1,"ext":{"prebid":{"type":"html"}}}
+	]}]}`
+
+	testCases := []struct {
+		description      string
+		genericORTB      *config.GenericORTBInfo
+		expectedTypes    []openrtb_ext.BidType
+		expectedCurrency string
+		expectedErrors   []string
+	}{
+		{
+			description:      "Bid type from ext",
+			genericORTB:      &config.GenericORTBInfo{BidType: config.GenericORTBBidTypeExt, BidTypeExtPath: "prebid.type", Currency: "EUR"},
+			expectedTypes:    []openrtb_ext.BidType{openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeVideo},
+			expectedCurrency: "EUR",
+			expectedErrors:   []string{"Unsupported bid type html in bid.ext.prebid.type of bid bid3"},
+		},
+		{
+			description:      "Bid type from missing ext field",
+			genericORTB:      &config.GenericORTBInfo{BidType: config.GenericORTBBidTypeExt, BidTypeExtPath: "type"},
+			expectedCurrency: "USD",
+			expectedErrors: []string{
+				"Failed to find the bid type in bid.ext.type of bid bid1",
+				"Failed to find the bid type in bid.ext.type of bid bid2",
+				"Failed to find the bid type in bid.ext.type of bid bid3",
+			},
+		},
+		{
+			description:      "Bid type from imp",
+			genericORTB:      &config.GenericORTBInfo{BidType: config.GenericORTBBidTypeImp},
+			expectedTypes:    []openrtb_ext.BidType{openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeVideo},
+			expectedCurrency: "USD",
+			expectedErrors:   []string{"Failed to find the media type of impression unknown for bid bid3"},
+		},
+	}
+
+	for _, test := range testCases {
+		bidder, buildErr := Builder(openrtb_ext.BidderGenericORTB, config.Adapter{Endpoint: "https://example.com", GenericORTB: test.genericORTB}, config.Server{})
+		if !assert.NoError(t, buildErr, test.description) {
+			continue
+		}
+
+		bidResponse, errs := bidder.MakeBids(request, nil, &adapters.ResponseData{StatusCode: http.StatusOK, Body: []byte(response)})
+
+		var types []openrtb_ext.BidType
+		for _, bid := range bidResponse.Bids {
+			types = append(types, bid.BidType)
+		}
+		var errMessages []string
+		for _, err := range errs {
+			errMessages = append(errMessages, err.Error())
+		}
+		assert.Equal(t, test.expectedTypes, types, test.description)
+		assert.Equal(t, test.expectedCurrency, bidResponse.Currency, test.description)
+		assert.Equal(t, test.expectedErrors, errMessages, test.description)
+	}
+}
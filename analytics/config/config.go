@@ -3,11 +3,13 @@ package config
 import (
 	"github.com/benbjohnson/clock"
 	"github.com/golang/glog"
+	"github.com/prebid/openrtb/v17/openrtb2"
 	"github.com/prebid/prebid-server/analytics"
 	"github.com/prebid/prebid-server/analytics/clients"
 	"github.com/prebid/prebid-server/analytics/filesystem"
 	"github.com/prebid/prebid-server/analytics/pubstack"
 	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/privacy"
 )
 
 // Modules that need to be logged to need to be initialized here
@@ -15,7 +17,7 @@ func NewPBSAnalytics(analytics *config.Analytics) analytics.PBSAnalyticsModule {
 	modules := make(enabledAnalytics, 0)
 	if len(analytics.File.Filename) > 0 {
 		if mod, err := filesystem.NewFileLogger(analytics.File.Filename); err == nil {
-			modules = append(modules, mod)
+			modules = append(modules, namedAnalyticsModule{name: "filelogger", module: mod})
 		} else {
 			glog.Fatalf("Could not initialize FileLogger for file %v :%v", analytics.File.Filename, err)
 		}
@@ -32,7 +34,7 @@ func NewPBSAnalytics(analytics *config.Analytics) analytics.PBSAnalyticsModule {
 			analytics.Pubstack.Buffers.Timeout,
 			clock.New())
 		if err == nil {
-			modules = append(modules, pubstackModule)
+			modules = append(modules, namedAnalyticsModule{name: "pubstack", module: pubstackModule})
 		} else {
 			glog.Errorf("Could not initialize PubstackModule: %v", err)
 		}
@@ -40,41 +42,65 @@ func NewPBSAnalytics(analytics *config.Analytics) analytics.PBSAnalyticsModule {
 	return modules
 }
 
-// Collection of all the correctly configured analytics modules - implements the PBSAnalyticsModule interface
-type enabledAnalytics []analytics.PBSAnalyticsModule
+// namedAnalyticsModule is an analytics module along with the name the reportAnalytics activity controls refer to it by
+type namedAnalyticsModule struct {
+	name   string
+	module analytics.PBSAnalyticsModule
+}
+
+// Collection of all the correctly configured analytics modules, in configuration order - implements the PBSAnalyticsModule interface
+type enabledAnalytics []namedAnalyticsModule
 
 func (ea enabledAnalytics) LogAuctionObject(ao *analytics.AuctionObject) {
-	for _, module := range ea {
-		module.LogAuctionObject(ao)
+	for _, m := range ea {
+		if reportAllowed(m.name, ao.Account, ao.Request) {
+			m.module.LogAuctionObject(ao)
+		}
 	}
 }
 
 func (ea enabledAnalytics) LogVideoObject(vo *analytics.VideoObject) {
-	for _, module := range ea {
-		module.LogVideoObject(vo)
+	for _, m := range ea {
+		if reportAllowed(m.name, vo.Account, vo.Request) {
+			m.module.LogVideoObject(vo)
+		}
 	}
 }
 
 func (ea enabledAnalytics) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
-	for _, module := range ea {
-		module.LogCookieSyncObject(cso)
+	for _, m := range ea {
+		m.module.LogCookieSyncObject(cso)
 	}
 }
 
 func (ea enabledAnalytics) LogSetUIDObject(so *analytics.SetUIDObject) {
-	for _, module := range ea {
-		module.LogSetUIDObject(so)
+	for _, m := range ea {
+		m.module.LogSetUIDObject(so)
 	}
 }
 
 func (ea enabledAnalytics) LogAmpObject(ao *analytics.AmpObject) {
-	for _, module := range ea {
-		module.LogAmpObject(ao)
+	for _, m := range ea {
+		if reportAllowed(m.name, ao.Account, ao.Request) {
+			m.module.LogAmpObject(ao)
+		}
 	}
 }
 
 func (ea enabledAnalytics) LogNotificationEventObject(ne *analytics.NotificationEvent) {
-	for _, module := range ea {
-		module.LogNotificationEventObject(ne)
+	for _, m := range ea {
+		if reportAllowed(m.name, ne.Account, nil) {
+			m.module.LogNotificationEventObject(ne)
+		}
+	}
+}
+
+// reportAllowed applies the reportAnalytics activity controls of the account to the named module
+func reportAllowed(name string, account *config.Account, request *openrtb2.BidRequest) bool {
+	if account == nil {
+		return true
 	}
+	activityControl := privacy.NewActivityControl(account.Activities)
+	component := privacy.Component{Type: config.ComponentTypeAnalytics, Name: name}
+	return activityControl.Allow(privacy.ActivityReportAnalytics, component, privacy.NewActivityRequest(request))
 }
@@ -77,10 +77,72 @@ func (m *sampleModule) LogNotificationEventObject(ne *analytics.NotificationEven
 
 func initAnalytics(count *int) analytics.PBSAnalyticsModule {
 	modules := make(enabledAnalytics, 0)
-	modules = append(modules, &sampleModule{count})
+	modules = append(modules, namedAnalyticsModule{name: "sampleModule", module: &sampleModule{count}})
 	return &modules
 }
 
+func TestSampleModuleActivitiesDenied(t *testing.T) {
+	var count int
+	am := initAnalytics(&count)
+
+	denySampleModule := &config.Account{Activities: config.AccountActivities{
+		ReportAnalytics: config.Activity{Rules: []config.ActivityRule{
+			{Condition: config.ActivityCondition{ComponentType: []string{"analytics"}, ComponentName: []string{"sampleModule"}}, Allow: false},
+		}},
+	}}
+	denyOtherModule := &config.Account{Activities: config.AccountActivities{
+		ReportAnalytics: config.Activity{Rules: []config.ActivityRule{
+			{Condition: config.ActivityCondition{ComponentName: []string{"otherModule"}}, Allow: false},
+		}},
+	}}
+
+	am.LogAuctionObject(&analytics.AuctionObject{Account: denySampleModule})
+	am.LogVideoObject(&analytics.VideoObject{Account: denySampleModule})
+	am.LogAmpObject(&analytics.AmpObject{Account: denySampleModule})
+	am.LogNotificationEventObject(&analytics.NotificationEvent{Account: denySampleModule})
+	assert.Equal(t, 0, count, "denied")
+
+	am.LogAuctionObject(&analytics.AuctionObject{Account: denyOtherModule})
+	am.LogVideoObject(&analytics.VideoObject{Account: denyOtherModule})
+	am.LogAmpObject(&analytics.AmpObject{Account: denyOtherModule})
+	am.LogNotificationEventObject(&analytics.NotificationEvent{Account: denyOtherModule})
+	assert.Equal(t, 4, count, "allowed")
+}
+
+type orderModule struct {
+	name   string
+	logged *[]string
+}
+
+func (m *orderModule) LogAuctionObject(ao *analytics.AuctionObject) {
+	*m.logged = append(*m.logged, m.name)
+}
+
+func (m *orderModule) LogVideoObject(vo *analytics.VideoObject) {}
+
+func (m *orderModule) LogCookieSyncObject(cso *analytics.CookieSyncObject) {}
+
+func (m *orderModule) LogSetUIDObject(so *analytics.SetUIDObject) {}
+
+func (m *orderModule) LogAmpObject(ao *analytics.AmpObject) {}
+
+func (m *orderModule) LogNotificationEventObject(ne *analytics.NotificationEvent) {}
+
+func TestModulesLoggedInConfigurationOrder(t *testing.T) {
+	var logged []string
+	modules := enabledAnalytics{
+		{name: "first", module: &orderModule{name: "first", logged: &logged}},
+		{name: "second", module: &orderModule{name: "second", logged: &logged}},
+		{name: "third", module: &orderModule{name: "third", logged: &logged}},
+	}
+
+	for i := 0; i < 10; i++ {
+		logged = nil
+		modules.LogAuctionObject(&analytics.AuctionObject{})
+		assert.Equal(t, []string{"first", "second", "third"}, logged)
+	}
+}
+
 func TestNewPBSAnalytics(t *testing.T) {
 	pbsAnalytics := NewPBSAnalytics(&config.Analytics{})
 	instance := pbsAnalytics.(enabledAnalytics)
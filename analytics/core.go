@@ -33,6 +33,8 @@ type AuctionObject struct {
 	Response  *openrtb2.BidResponse
 	Account   *config.Account
 	StartTime time.Time
+	// SeatNonBid lists the imps each seat did not bid on and the bids rejected during the auction
+	SeatNonBid []openrtb_ext.SeatNonBid
 }
 
 // Loggable object of a transaction at /openrtb2/amp endpoint
@@ -43,6 +45,7 @@ type AmpObject struct {
 	AuctionResponse    *openrtb2.BidResponse
 	AmpTargetingValues map[string]string
 	Origin             string
+	Account            *config.Account
 	StartTime          time.Time
 }
 
@@ -54,6 +57,7 @@ type VideoObject struct {
 	Response      *openrtb2.BidResponse
 	VideoRequest  *openrtb_ext.BidRequestVideo
 	VideoResponse *openrtb_ext.BidResponseVideo
+	Account       *config.Account
 	StartTime     time.Time
 }
 
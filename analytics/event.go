@@ -50,4 +50,5 @@ type EventRequest struct {
 	Timestamp   int64          `json:"timestamp,omitempty"`
 	Integration string         `json:"integration,omitempty"`
 	VType       VastType       `json:"vtype,omitempty"`
+	Price       float64        `json:"price,omitempty"`
 }
@@ -0,0 +1,107 @@
+package bidadjustment
+
+import (
+	"math"
+
+	"github.com/prebid/openrtb/v17/adcom1"
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// BidInfo holds the attributes of a bid used to select and apply the adjustment rules. Bidders lists
+// the codes to look up in order of preference, usually the alternate bidder code followed by the
+// adapter code.
+type BidInfo struct {
+	MediaType string
+	Bidders   []string
+	DealID    string
+	Price     float64
+	Currency  string
+}
+
+// MediaType returns the bid adjustment media type of a bid of type bidType placed on imp. Video bids
+// are in-stream when the imp declares an in-stream placement, and out-stream otherwise.
+func MediaType(bidType openrtb_ext.BidType, imp *openrtb2.Imp) string {
+	switch bidType {
+	case openrtb_ext.BidTypeBanner:
+		return openrtb_ext.AdjustmentMediaTypeBanner
+	case openrtb_ext.BidTypeVideo:
+		if imp != nil && imp.Video != nil && imp.Video.Placement == adcom1.VideoInStream {
+			return openrtb_ext.AdjustmentMediaTypeVideoInstream
+		}
+		return openrtb_ext.AdjustmentMediaTypeVideoOutstream
+	case openrtb_ext.BidTypeAudio:
+		return openrtb_ext.AdjustmentMediaTypeAudio
+	case openrtb_ext.BidTypeNative:
+		return openrtb_ext.AdjustmentMediaTypeNative
+	}
+	return ""
+}
+
+// Apply adjusts the bid price, in the bid currency, with the adjustments of the most specific matching
+// rule. Media type takes precedence over bidder, which takes precedence over deal id. The second return
+// value is false when no rule matched the bid.
+func (r Rules) Apply(bid BidInfo, conversions currency.Conversions) (float64, bool) {
+	adjustments, found := r.find(bid)
+	if !found {
+		return bid.Price, false
+	}
+
+	price := bid.Price
+	for _, adjustment := range adjustments {
+		switch adjustment.Type {
+		case openrtb_ext.AdjustmentTypeMultiplier:
+			price = price * adjustment.Value
+		case openrtb_ext.AdjustmentTypeCPM:
+			if value, ok := convert(adjustment, bid.Currency, conversions); ok {
+				price = math.Max(price-value, 0)
+			}
+		case openrtb_ext.AdjustmentTypeStatic:
+			if value, ok := convert(adjustment, bid.Currency, conversions); ok {
+				price = value
+			}
+		}
+	}
+	return price, true
+}
+
+func (r Rules) find(bid BidInfo) ([]openrtb_ext.Adjustment, bool) {
+	if len(r) == 0 {
+		return nil, false
+	}
+
+	bidders := make([]string, 0, len(bid.Bidders)+1)
+	bidders = append(bidders, bid.Bidders...)
+	bidders = append(bidders, openrtb_ext.AdjustmentWildCard)
+	dealIDs := []string{openrtb_ext.AdjustmentWildCard}
+	if bid.DealID != "" {
+		dealIDs = []string{bid.DealID, openrtb_ext.AdjustmentWildCard}
+	}
+
+	for _, mediaType := range []string{bid.MediaType, openrtb_ext.AdjustmentWildCard} {
+		for _, bidder := range bidders {
+			for _, dealID := range dealIDs {
+				if adjustments, ok := r[ruleKey(mediaType, bidder, dealID)]; ok {
+					return adjustments, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// convert expresses the adjustment value in the bid currency
+func convert(adjustment openrtb_ext.Adjustment, bidCurrency string, conversions currency.Conversions) (float64, bool) {
+	if bidCurrency == "" || adjustment.Currency == bidCurrency {
+		return adjustment.Value, true
+	}
+	if conversions == nil {
+		return 0, false
+	}
+	rate, err := conversions.GetRate(adjustment.Currency, bidCurrency)
+	if err != nil {
+		return 0, false
+	}
+	return adjustment.Value * rate, true
+}
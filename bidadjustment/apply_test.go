@@ -0,0 +1,153 @@
+package bidadjustment
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v17/adcom1"
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediaType(t *testing.T) {
+	testCases := []struct {
+		description       string
+		givenBidType      openrtb_ext.BidType
+		givenImp          *openrtb2.Imp
+		expectedMediaType string
+	}{
+		{
+			description:       "Banner",
+			givenBidType:      openrtb_ext.BidTypeBanner,
+			expectedMediaType: openrtb_ext.AdjustmentMediaTypeBanner,
+		},
+		{
+			description:       "Video In-Stream",
+			givenBidType:      openrtb_ext.BidTypeVideo,
+			givenImp:          &openrtb2.Imp{Video: &openrtb2.Video{Placement: adcom1.VideoInStream}},
+			expectedMediaType: openrtb_ext.AdjustmentMediaTypeVideoInstream,
+		},
+		{
+			description:       "Video Out-Stream",
+			givenBidType:      openrtb_ext.BidTypeVideo,
+			givenImp:          &openrtb2.Imp{Video: &openrtb2.Video{Placement: adcom1.VideoInArticle}},
+			expectedMediaType: openrtb_ext.AdjustmentMediaTypeVideoOutstream,
+		},
+		{
+			description:       "Video Imp Unknown",
+			givenBidType:      openrtb_ext.BidTypeVideo,
+			expectedMediaType: openrtb_ext.AdjustmentMediaTypeVideoOutstream,
+		},
+		{
+			description:       "Audio",
+			givenBidType:      openrtb_ext.BidTypeAudio,
+			expectedMediaType: openrtb_ext.AdjustmentMediaTypeAudio,
+		},
+		{
+			description:       "Native",
+			givenBidType:      openrtb_ext.BidTypeNative,
+			expectedMediaType: openrtb_ext.AdjustmentMediaTypeNative,
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expectedMediaType, MediaType(test.givenBidType, test.givenImp), test.description)
+	}
+}
+
+func TestApply(t *testing.T) {
+	conversions := currency.NewRates(map[string]map[string]float64{
+		"EUR": {"USD": 1.2},
+	})
+
+	rules := Rules{
+		"banner|bidderA|*":     {{Type: openrtb_ext.AdjustmentTypeMultiplier, Value: 0.5}},
+		"banner|bidderA|dealA": {{Type: openrtb_ext.AdjustmentTypeStatic, Value: 3, Currency: "USD"}},
+		"banner|seatA|*":       {{Type: openrtb_ext.AdjustmentTypeMultiplier, Value: 0.8}},
+		"native|*|*":           {{Type: openrtb_ext.AdjustmentTypeCPM, Value: 1, Currency: "EUR"}},
+		"audio|*|*":            {{Type: openrtb_ext.AdjustmentTypeCPM, Value: 1, Currency: "JPY"}},
+		"*|bidderB|*": {
+			{Type: openrtb_ext.AdjustmentTypeMultiplier, Value: 2},
+			{Type: openrtb_ext.AdjustmentTypeCPM, Value: 1, Currency: "USD"},
+		},
+	}
+
+	testCases := []struct {
+		description      string
+		givenRules       Rules
+		givenBid         BidInfo
+		expectedPrice    float64
+		expectedAdjusted bool
+	}{
+		{
+			description:      "No Rules",
+			givenRules:       nil,
+			givenBid:         BidInfo{MediaType: "banner", Bidders: []string{"bidderA"}, Price: 2, Currency: "USD"},
+			expectedPrice:    2,
+			expectedAdjusted: false,
+		},
+		{
+			description:      "No Match",
+			givenRules:       rules,
+			givenBid:         BidInfo{MediaType: "banner", Bidders: []string{"bidderC"}, Price: 2, Currency: "USD"},
+			expectedPrice:    2,
+			expectedAdjusted: false,
+		},
+		{
+			description:      "Multiplier",
+			givenRules:       rules,
+			givenBid:         BidInfo{MediaType: "banner", Bidders: []string{"bidderA"}, Price: 2, Currency: "USD"},
+			expectedPrice:    1,
+			expectedAdjusted: true,
+		},
+		{
+			description:      "Deal Takes Precedence",
+			givenRules:       rules,
+			givenBid:         BidInfo{MediaType: "banner", Bidders: []string{"bidderA"}, DealID: "dealA", Price: 2, Currency: "USD"},
+			expectedPrice:    3,
+			expectedAdjusted: true,
+		},
+		{
+			description:      "Alternate Bidder Code Takes Precedence",
+			givenRules:       rules,
+			givenBid:         BidInfo{MediaType: "banner", Bidders: []string{"seatA", "bidderA"}, Price: 2, Currency: "USD"},
+			expectedPrice:    1.6,
+			expectedAdjusted: true,
+		},
+		{
+			description:      "CPM Converted",
+			givenRules:       rules,
+			givenBid:         BidInfo{MediaType: "native", Bidders: []string{"bidderA"}, Price: 2, Currency: "USD"},
+			expectedPrice:    0.8,
+			expectedAdjusted: true,
+		},
+		{
+			description:      "CPM Floored At Zero",
+			givenRules:       rules,
+			givenBid:         BidInfo{MediaType: "native", Bidders: []string{"bidderA"}, Price: 1, Currency: "USD"},
+			expectedPrice:    0,
+			expectedAdjusted: true,
+		},
+		{
+			description:      "CPM Conversion Unavailable",
+			givenRules:       rules,
+			givenBid:         BidInfo{MediaType: "audio", Bidders: []string{"bidderA"}, Price: 2, Currency: "USD"},
+			expectedPrice:    2,
+			expectedAdjusted: true,
+		},
+		{
+			description:      "Media Type Wildcard In Sequence",
+			givenRules:       rules,
+			givenBid:         BidInfo{MediaType: "video-instream", Bidders: []string{"bidderB"}, Price: 2, Currency: "USD"},
+			expectedPrice:    3,
+			expectedAdjusted: true,
+		},
+	}
+
+	for _, test := range testCases {
+		price, adjusted := test.givenRules.Apply(test.givenBid, conversions)
+		assert.InDelta(t, test.expectedPrice, price, 0.0001, test.description+":price")
+		assert.Equal(t, test.expectedAdjusted, adjusted, test.description+":adjusted")
+	}
+}
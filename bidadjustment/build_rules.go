@@ -0,0 +1,104 @@
+package bidadjustment
+
+import (
+	"fmt"
+
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// Rules maps the "mediatype|bidder|dealid" keys to the adjustments to apply in order
+type Rules map[string][]openrtb_ext.Adjustment
+
+const delimiter = "|"
+
+// Merge combines the adjustments sent on the request with the ones configured on the account. For the same
+// media type, bidder and deal id the request adjustments take precedence. Invalid adjustments from either
+// source are discarded as a whole and reported as warnings.
+func Merge(requestAdjustments, accountAdjustments *openrtb_ext.ExtRequestPrebidBidAdjustments) (*openrtb_ext.ExtRequestPrebidBidAdjustments, []error) {
+	var warnings []error
+	if err := Validate(requestAdjustments); err != nil {
+		warnings = append(warnings, toWarning(fmt.Errorf("request ext.prebid.bidadjustments ignored: %v", err)))
+		requestAdjustments = nil
+	}
+	if err := Validate(accountAdjustments); err != nil {
+		warnings = append(warnings, toWarning(fmt.Errorf("account bidadjustments ignored: %v", err)))
+		accountAdjustments = nil
+	}
+
+	if requestAdjustments == nil {
+		return accountAdjustments, warnings
+	}
+	if accountAdjustments == nil {
+		return requestAdjustments, warnings
+	}
+
+	merged := &openrtb_ext.ExtRequestPrebidBidAdjustments{}
+	merged.MediaType.Banner = mergeBidders(requestAdjustments.MediaType.Banner, accountAdjustments.MediaType.Banner)
+	merged.MediaType.VideoInstream = mergeBidders(requestAdjustments.MediaType.VideoInstream, accountAdjustments.MediaType.VideoInstream)
+	merged.MediaType.VideoOutstream = mergeBidders(requestAdjustments.MediaType.VideoOutstream, accountAdjustments.MediaType.VideoOutstream)
+	merged.MediaType.Audio = mergeBidders(requestAdjustments.MediaType.Audio, accountAdjustments.MediaType.Audio)
+	merged.MediaType.Native = mergeBidders(requestAdjustments.MediaType.Native, accountAdjustments.MediaType.Native)
+	merged.MediaType.WildCard = mergeBidders(requestAdjustments.MediaType.WildCard, accountAdjustments.MediaType.WildCard)
+	return merged, warnings
+}
+
+func mergeBidders(request, account map[string]openrtb_ext.AdjustmentsByDealID) map[string]openrtb_ext.AdjustmentsByDealID {
+	if len(request) == 0 {
+		return account
+	}
+	if len(account) == 0 {
+		return request
+	}
+
+	merged := make(map[string]openrtb_ext.AdjustmentsByDealID, len(request)+len(account))
+	for bidder, adjustmentsByDealID := range account {
+		merged[bidder] = make(openrtb_ext.AdjustmentsByDealID, len(adjustmentsByDealID))
+		for dealID, adjustments := range adjustmentsByDealID {
+			merged[bidder][dealID] = adjustments
+		}
+	}
+	for bidder, adjustmentsByDealID := range request {
+		if _, ok := merged[bidder]; !ok {
+			merged[bidder] = make(openrtb_ext.AdjustmentsByDealID, len(adjustmentsByDealID))
+		}
+		for dealID, adjustments := range adjustmentsByDealID {
+			merged[bidder][dealID] = adjustments
+		}
+	}
+	return merged
+}
+
+// BuildRules flattens the adjustments into Rules for fast lookups while processing bids
+func BuildRules(bidAdjustments *openrtb_ext.ExtRequestPrebidBidAdjustments) Rules {
+	if bidAdjustments == nil {
+		return nil
+	}
+
+	rules := make(Rules)
+	for _, mediaType := range openrtb_ext.AdjustmentMediaTypes() {
+		for bidder, adjustmentsByDealID := range bidAdjustments.MediaType.ByMediaType(mediaType) {
+			for dealID, adjustments := range adjustmentsByDealID {
+				if len(adjustments) > 0 {
+					rules[ruleKey(mediaType, bidder, dealID)] = adjustments
+				}
+			}
+		}
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+	return rules
+}
+
+func ruleKey(mediaType, bidder, dealID string) string {
+	return mediaType + delimiter + bidder + delimiter + dealID
+}
+
+func toWarning(err error) error {
+	return &errortypes.Warning{
+		Message:     err.Error(),
+		WarningCode: errortypes.BidAdjustmentWarningCode,
+	}
+}
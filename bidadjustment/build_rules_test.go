@@ -0,0 +1,139 @@
+package bidadjustment
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	multiplier := []openrtb_ext.Adjustment{{Type: openrtb_ext.AdjustmentTypeMultiplier, Value: 0.9}}
+	static := []openrtb_ext.Adjustment{{Type: openrtb_ext.AdjustmentTypeStatic, Value: 2, Currency: "USD"}}
+	invalid := []openrtb_ext.Adjustment{{Type: openrtb_ext.AdjustmentTypeMultiplier, Value: -1}}
+
+	testCases := []struct {
+		description         string
+		givenRequest        *openrtb_ext.ExtRequestPrebidBidAdjustments
+		givenAccount        *openrtb_ext.ExtRequestPrebidBidAdjustments
+		expectedAdjustments *openrtb_ext.ExtRequestPrebidBidAdjustments
+		expectedWarnings    []error
+	}{
+		{
+			description:         "None",
+			expectedAdjustments: nil,
+		},
+		{
+			description: "Request Only",
+			givenRequest: &openrtb_ext.ExtRequestPrebidBidAdjustments{MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+				Banner: map[string]openrtb_ext.AdjustmentsByDealID{"bidderA": {"*": multiplier}},
+			}},
+			expectedAdjustments: &openrtb_ext.ExtRequestPrebidBidAdjustments{MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+				Banner: map[string]openrtb_ext.AdjustmentsByDealID{"bidderA": {"*": multiplier}},
+			}},
+		},
+		{
+			description: "Account Only",
+			givenAccount: &openrtb_ext.ExtRequestPrebidBidAdjustments{MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+				Native: map[string]openrtb_ext.AdjustmentsByDealID{"bidderA": {"*": multiplier}},
+			}},
+			expectedAdjustments: &openrtb_ext.ExtRequestPrebidBidAdjustments{MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+				Native: map[string]openrtb_ext.AdjustmentsByDealID{"bidderA": {"*": multiplier}},
+			}},
+		},
+		{
+			description: "Request Takes Precedence",
+			givenRequest: &openrtb_ext.ExtRequestPrebidBidAdjustments{MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+				Banner: map[string]openrtb_ext.AdjustmentsByDealID{"bidderA": {"*": multiplier}},
+			}},
+			givenAccount: &openrtb_ext.ExtRequestPrebidBidAdjustments{MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+				Banner:   map[string]openrtb_ext.AdjustmentsByDealID{"bidderA": {"*": static, "dealA": static}, "bidderB": {"*": static}},
+				WildCard: map[string]openrtb_ext.AdjustmentsByDealID{"*": {"*": static}},
+			}},
+			expectedAdjustments: &openrtb_ext.ExtRequestPrebidBidAdjustments{MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+				Banner:   map[string]openrtb_ext.AdjustmentsByDealID{"bidderA": {"*": multiplier, "dealA": static}, "bidderB": {"*": static}},
+				WildCard: map[string]openrtb_ext.AdjustmentsByDealID{"*": {"*": static}},
+			}},
+		},
+		{
+			description: "Invalid Request Ignored",
+			givenRequest: &openrtb_ext.ExtRequestPrebidBidAdjustments{MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+				Banner: map[string]openrtb_ext.AdjustmentsByDealID{"bidderA": {"*": invalid}},
+			}},
+			givenAccount: &openrtb_ext.ExtRequestPrebidBidAdjustments{MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+				Banner: map[string]openrtb_ext.AdjustmentsByDealID{"bidderA": {"*": static}},
+			}},
+			expectedAdjustments: &openrtb_ext.ExtRequestPrebidBidAdjustments{MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+				Banner: map[string]openrtb_ext.AdjustmentsByDealID{"bidderA": {"*": static}},
+			}},
+			expectedWarnings: []error{&errortypes.Warning{
+				Message:     "request ext.prebid.bidadjustments ignored: bid adjustment for mediatype banner, bidder bidderA and deal * is invalid: multiplier value -1 must be between 0 and 100",
+				WarningCode: errortypes.BidAdjustmentWarningCode,
+			}},
+		},
+		{
+			description: "Invalid Account Ignored",
+			givenRequest: &openrtb_ext.ExtRequestPrebidBidAdjustments{MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+				Banner: map[string]openrtb_ext.AdjustmentsByDealID{"bidderA": {"*": static}},
+			}},
+			givenAccount: &openrtb_ext.ExtRequestPrebidBidAdjustments{MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+				Banner: map[string]openrtb_ext.AdjustmentsByDealID{"bidderA": {"*": invalid}},
+			}},
+			expectedAdjustments: &openrtb_ext.ExtRequestPrebidBidAdjustments{MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+				Banner: map[string]openrtb_ext.AdjustmentsByDealID{"bidderA": {"*": static}},
+			}},
+			expectedWarnings: []error{&errortypes.Warning{
+				Message:     "account bidadjustments ignored: bid adjustment for mediatype banner, bidder bidderA and deal * is invalid: multiplier value -1 must be between 0 and 100",
+				WarningCode: errortypes.BidAdjustmentWarningCode,
+			}},
+		},
+	}
+
+	for _, test := range testCases {
+		adjustments, warnings := Merge(test.givenRequest, test.givenAccount)
+		assert.Equal(t, test.expectedAdjustments, adjustments, test.description+":adjustments")
+		assert.Equal(t, test.expectedWarnings, warnings, test.description+":warnings")
+	}
+}
+
+func TestBuildRules(t *testing.T) {
+	multiplier := []openrtb_ext.Adjustment{{Type: openrtb_ext.AdjustmentTypeMultiplier, Value: 0.9}}
+	static := []openrtb_ext.Adjustment{{Type: openrtb_ext.AdjustmentTypeStatic, Value: 2, Currency: "USD"}}
+
+	testCases := []struct {
+		description         string
+		givenBidAdjustments *openrtb_ext.ExtRequestPrebidBidAdjustments
+		expectedRules       Rules
+	}{
+		{
+			description:         "Nil",
+			givenBidAdjustments: nil,
+			expectedRules:       nil,
+		},
+		{
+			description: "No Adjustments",
+			givenBidAdjustments: &openrtb_ext.ExtRequestPrebidBidAdjustments{MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+				Banner: map[string]openrtb_ext.AdjustmentsByDealID{"bidderA": {"*": nil}},
+			}},
+			expectedRules: nil,
+		},
+		{
+			description: "Multiple Media Types",
+			givenBidAdjustments: &openrtb_ext.ExtRequestPrebidBidAdjustments{MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+				Banner:         map[string]openrtb_ext.AdjustmentsByDealID{"bidderA": {"*": multiplier}},
+				VideoOutstream: map[string]openrtb_ext.AdjustmentsByDealID{"bidderB": {"dealA": static}},
+				WildCard:       map[string]openrtb_ext.AdjustmentsByDealID{"*": {"*": multiplier}},
+			}},
+			expectedRules: Rules{
+				"banner|bidderA|*":              multiplier,
+				"video-outstream|bidderB|dealA": static,
+				"*|*|*":                         multiplier,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expectedRules, BuildRules(test.givenBidAdjustments), test.description)
+	}
+}
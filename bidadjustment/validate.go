@@ -0,0 +1,50 @@
+package bidadjustment
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// maxMultiplier caps the multiplier adjustments to catch misconfigured values
+const maxMultiplier float64 = 100
+
+// Validate returns an error describing the first invalid adjustment found
+func Validate(bidAdjustments *openrtb_ext.ExtRequestPrebidBidAdjustments) error {
+	if bidAdjustments == nil {
+		return nil
+	}
+
+	for _, mediaType := range openrtb_ext.AdjustmentMediaTypes() {
+		for bidder, adjustmentsByDealID := range bidAdjustments.MediaType.ByMediaType(mediaType) {
+			for dealID, adjustments := range adjustmentsByDealID {
+				for _, adjustment := range adjustments {
+					if err := validateAdjustment(adjustment); err != nil {
+						return fmt.Errorf("bid adjustment for mediatype %s, bidder %s and deal %s is invalid: %v", mediaType, bidder, dealID, err)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func validateAdjustment(adjustment openrtb_ext.Adjustment) error {
+	switch adjustment.Type {
+	case openrtb_ext.AdjustmentTypeMultiplier:
+		if adjustment.Value < 0 || adjustment.Value >= maxMultiplier {
+			return fmt.Errorf("multiplier value %v must be between 0 and %v", adjustment.Value, maxMultiplier)
+		}
+	case openrtb_ext.AdjustmentTypeCPM, openrtb_ext.AdjustmentTypeStatic:
+		if adjustment.Value < 0 || math.IsInf(adjustment.Value, 0) || math.IsNaN(adjustment.Value) {
+			return fmt.Errorf("%s value %v must be a positive number", adjustment.Type, adjustment.Value)
+		}
+		if adjustment.Currency == "" {
+			return fmt.Errorf("%s adjustment requires a currency", adjustment.Type)
+		}
+	default:
+		return fmt.Errorf("adjtype %q is not supported", adjustment.Type)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package bidadjustment
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		description         string
+		givenBidAdjustments *openrtb_ext.ExtRequestPrebidBidAdjustments
+		expectedError       string
+	}{
+		{
+			description:         "Nil",
+			givenBidAdjustments: nil,
+		},
+		{
+			description:         "Empty",
+			givenBidAdjustments: &openrtb_ext.ExtRequestPrebidBidAdjustments{},
+		},
+		{
+			description: "Valid",
+			givenBidAdjustments: &openrtb_ext.ExtRequestPrebidBidAdjustments{
+				MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+					Banner: map[string]openrtb_ext.AdjustmentsByDealID{
+						"bidderA": {"*": {
+							{Type: openrtb_ext.AdjustmentTypeMultiplier, Value: 0.9},
+							{Type: openrtb_ext.AdjustmentTypeCPM, Value: 0.5, Currency: "USD"},
+						}},
+					},
+					WildCard: map[string]openrtb_ext.AdjustmentsByDealID{
+						"*": {"dealA": {{Type: openrtb_ext.AdjustmentTypeStatic, Value: 2, Currency: "EUR"}}},
+					},
+				},
+			},
+		},
+		{
+			description: "Multiplier Negative",
+			givenBidAdjustments: &openrtb_ext.ExtRequestPrebidBidAdjustments{
+				MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+					Native: map[string]openrtb_ext.AdjustmentsByDealID{
+						"bidderA": {"*": {{Type: openrtb_ext.AdjustmentTypeMultiplier, Value: -1}}},
+					},
+				},
+			},
+			expectedError: "bid adjustment for mediatype native, bidder bidderA and deal * is invalid: multiplier value -1 must be between 0 and 100",
+		},
+		{
+			description: "Multiplier Too Large",
+			givenBidAdjustments: &openrtb_ext.ExtRequestPrebidBidAdjustments{
+				MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+					Banner: map[string]openrtb_ext.AdjustmentsByDealID{
+						"bidderA": {"*": {{Type: openrtb_ext.AdjustmentTypeMultiplier, Value: 100}}},
+					},
+				},
+			},
+			expectedError: "bid adjustment for mediatype banner, bidder bidderA and deal * is invalid: multiplier value 100 must be between 0 and 100",
+		},
+		{
+			description: "CPM Without Currency",
+			givenBidAdjustments: &openrtb_ext.ExtRequestPrebidBidAdjustments{
+				MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+					VideoInstream: map[string]openrtb_ext.AdjustmentsByDealID{
+						"bidderA": {"dealA": {{Type: openrtb_ext.AdjustmentTypeCPM, Value: 1}}},
+					},
+				},
+			},
+			expectedError: "bid adjustment for mediatype video-instream, bidder bidderA and deal dealA is invalid: cpm adjustment requires a currency",
+		},
+		{
+			description: "Static Infinite",
+			givenBidAdjustments: &openrtb_ext.ExtRequestPrebidBidAdjustments{
+				MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+					Audio: map[string]openrtb_ext.AdjustmentsByDealID{
+						"bidderA": {"*": {{Type: openrtb_ext.AdjustmentTypeStatic, Value: math.Inf(1), Currency: "USD"}}},
+					},
+				},
+			},
+			expectedError: "bid adjustment for mediatype audio, bidder bidderA and deal * is invalid: static value +Inf must be a positive number",
+		},
+		{
+			description: "Unknown Type",
+			givenBidAdjustments: &openrtb_ext.ExtRequestPrebidBidAdjustments{
+				MediaType: openrtb_ext.BidAdjustmentsByMediaType{
+					VideoOutstream: map[string]openrtb_ext.AdjustmentsByDealID{
+						"bidderA": {"*": {{Type: "percent", Value: 1}}},
+					},
+				},
+			},
+			expectedError: `bid adjustment for mediatype video-outstream, bidder bidderA and deal * is invalid: adjtype "percent" is not supported`,
+		},
+	}
+
+	for _, test := range testCases {
+		err := Validate(test.givenBidAdjustments)
+		if test.expectedError == "" {
+			assert.NoError(t, err, test.description)
+		} else {
+			assert.EqualError(t, err, test.expectedError, test.description)
+		}
+	}
+}
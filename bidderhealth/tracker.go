@@ -0,0 +1,241 @@
+package bidderhealth
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/metrics"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/prebid/prebid-server/util/timeutil"
+)
+
+// Outcome is the result of a request sent to a bidder
+type Outcome int
+
+const (
+	Success Outcome = iota
+	Error
+	Timeout
+)
+
+// OutcomeFromErrors classifies the errors returned by a bidder request. Timeouts take precedence over other
+// errors. Warnings, bad input errors and requests the adapter failed to build are not caused by the bidder
+// and count as a success.
+func OutcomeFromErrors(errs []error) Outcome {
+	outcome := Success
+	for _, err := range errortypes.FatalOnly(errs) {
+		switch errortypes.ReadCode(err) {
+		case errortypes.TimeoutErrorCode:
+			return Timeout
+		case errortypes.BadInputErrorCode, errortypes.FailedToRequestBidsErrorCode:
+		default:
+			outcome = Error
+		}
+	}
+	return outcome
+}
+
+// Tracker decides whether requests should be sent to a bidder based on the outcome of its previous requests
+type Tracker interface {
+	// Allow returns false when the request should not be sent to the bidder
+	Allow(bidder openrtb_ext.BidderName) bool
+	// Record reports the outcome of a request sent to the bidder
+	Record(bidder openrtb_ext.BidderName, outcome Outcome)
+	// Status returns a snapshot of the health of the bidders which received requests
+	Status() map[openrtb_ext.BidderName]Status
+}
+
+// Status describes the health of a bidder. The counters cover the current window when the bidder is healthy,
+// and the window which tripped the circuit otherwise.
+type Status struct {
+	State    metrics.AdapterHealthState `json:"state"`
+	Since    time.Time                  `json:"since"`
+	Requests int                        `json:"requests"`
+	Errors   int                        `json:"errors"`
+	Timeouts int                        `json:"timeouts"`
+}
+
+// NewTracker returns a Tracker for the bidder_health config, or a NilTracker which allows every request when
+// the feature is disabled
+func NewTracker(cfg config.BidderHealth, me metrics.MetricsEngine) Tracker {
+	if !cfg.Enabled {
+		return &NilTracker{}
+	}
+
+	return &tracker{
+		cfg:          cfg,
+		window:       time.Duration(cfg.WindowSeconds) * time.Second,
+		openDuration: time.Duration(cfg.OpenSeconds) * time.Second,
+		bidders:      make(map[openrtb_ext.BidderName]*bidderHealth),
+		me:           me,
+		time:         &timeutil.RealTime{},
+		randomIntn:   rand.Intn,
+	}
+}
+
+// NilTracker allows every request and tracks nothing
+type NilTracker struct{}
+
+func (t *NilTracker) Allow(bidder openrtb_ext.BidderName) bool {
+	return true
+}
+
+func (t *NilTracker) Record(bidder openrtb_ext.BidderName, outcome Outcome) {}
+
+func (t *NilTracker) Status() map[openrtb_ext.BidderName]Status {
+	return map[openrtb_ext.BidderName]Status{}
+}
+
+type tracker struct {
+	cfg          config.BidderHealth
+	window       time.Duration
+	openDuration time.Duration
+
+	mutex   sync.Mutex
+	bidders map[openrtb_ext.BidderName]*bidderHealth
+
+	me         metrics.MetricsEngine
+	time       timeutil.Time
+	randomIntn func(n int) int
+}
+
+type bidderHealth struct {
+	status      Status
+	windowStart time.Time
+	probesSent  int
+	probesOK    int
+}
+
+func (t *tracker) Allow(bidder openrtb_ext.BidderName) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.time.Now()
+	health := t.get(bidder, now)
+
+	switch health.status.State {
+	case metrics.AdapterHealthOpen, metrics.AdapterHealthSampled:
+		if now.Sub(health.status.Since) >= t.openDuration {
+			t.setState(bidder, health, metrics.AdapterHealthHalfOpen, now)
+			return t.allowProbe(bidder, health)
+		}
+		if health.status.State == metrics.AdapterHealthSampled && t.randomIntn(100) < t.cfg.SamplePercentage {
+			return true
+		}
+		t.me.RecordAdapterHealthBlocked(bidder)
+		return false
+	case metrics.AdapterHealthHalfOpen:
+		// probes which never reported back, such as requests which panicked, are sent again after openDuration
+		if now.Sub(health.status.Since) >= t.openDuration {
+			t.setState(bidder, health, metrics.AdapterHealthHalfOpen, now)
+		}
+		return t.allowProbe(bidder, health)
+	}
+	return true
+}
+
+func (t *tracker) allowProbe(bidder openrtb_ext.BidderName, health *bidderHealth) bool {
+	if health.probesSent < t.cfg.ProbeRequests {
+		health.probesSent++
+		return true
+	}
+	t.me.RecordAdapterHealthBlocked(bidder)
+	return false
+}
+
+func (t *tracker) Record(bidder openrtb_ext.BidderName, outcome Outcome) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.time.Now()
+	health := t.get(bidder, now)
+
+	switch health.status.State {
+	case metrics.AdapterHealthClosed:
+		if now.Sub(health.windowStart) >= t.window {
+			health.resetWindow(now)
+		}
+		health.status.Requests++
+		switch outcome {
+		case Error:
+			health.status.Errors++
+		case Timeout:
+			health.status.Timeouts++
+		}
+		if health.status.Requests >= t.cfg.MinRequests && t.exceedsThresholds(health.status) {
+			t.trip(bidder, health, now)
+		}
+	case metrics.AdapterHealthHalfOpen:
+		if outcome != Success {
+			t.trip(bidder, health, now)
+			return
+		}
+		health.probesOK++
+		if health.probesOK >= t.cfg.ProbeRequests {
+			health.resetWindow(now)
+			t.setState(bidder, health, metrics.AdapterHealthClosed, now)
+		}
+	}
+}
+
+func (t *tracker) Status() map[openrtb_ext.BidderName]Status {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	status := make(map[openrtb_ext.BidderName]Status, len(t.bidders))
+	for bidder, health := range t.bidders {
+		status[bidder] = health.status
+	}
+	return status
+}
+
+func (t *tracker) get(bidder openrtb_ext.BidderName, now time.Time) *bidderHealth {
+	health, ok := t.bidders[bidder]
+	if !ok {
+		health = &bidderHealth{
+			status: Status{
+				State: metrics.AdapterHealthClosed,
+				Since: now,
+			},
+			windowStart: now,
+		}
+		t.bidders[bidder] = health
+	}
+	return health
+}
+
+func (t *tracker) exceedsThresholds(status Status) bool {
+	if status.Requests == 0 {
+		return false
+	}
+	requests := float64(status.Requests)
+	return float64(status.Errors)/requests >= t.cfg.ErrorRateThreshold ||
+		float64(status.Timeouts)/requests >= t.cfg.TimeoutRateThreshold
+}
+
+// trip stops sending requests to the bidder, or only a sample of them when a sample percentage is configured
+func (t *tracker) trip(bidder openrtb_ext.BidderName, health *bidderHealth, now time.Time) {
+	if t.cfg.SamplePercentage > 0 {
+		t.setState(bidder, health, metrics.AdapterHealthSampled, now)
+	} else {
+		t.setState(bidder, health, metrics.AdapterHealthOpen, now)
+	}
+}
+
+func (t *tracker) setState(bidder openrtb_ext.BidderName, health *bidderHealth, state metrics.AdapterHealthState, now time.Time) {
+	health.status.State = state
+	health.status.Since = now
+	health.probesSent = 0
+	health.probesOK = 0
+	t.me.RecordAdapterHealthState(bidder, state)
+}
+
+func (h *bidderHealth) resetWindow(now time.Time) {
+	h.windowStart = now
+	h.status.Requests = 0
+	h.status.Errors = 0
+	h.status.Timeouts = 0
+}
@@ -0,0 +1,239 @@
+package bidderhealth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/metrics"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const bidder openrtb_ext.BidderName = "appnexus"
+
+type fakeTime struct {
+	time time.Time
+}
+
+func (f *fakeTime) Now() time.Time {
+	return f.time
+}
+
+func newTestTracker(cfg config.BidderHealth, randomValue int) (*tracker, *fakeTime, *metrics.MetricsEngineMock) {
+	cfg.Enabled = true
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAdapterHealthState", mock.Anything, mock.Anything).Return()
+	me.On("RecordAdapterHealthBlocked", mock.Anything).Return()
+
+	clock := &fakeTime{time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	t := NewTracker(cfg, me).(*tracker)
+	t.time = clock
+	t.randomIntn = func(n int) int { return randomValue }
+	return t, clock, me
+}
+
+func testConfig() config.BidderHealth {
+	return config.BidderHealth{
+		WindowSeconds:        60,
+		MinRequests:          4,
+		ErrorRateThreshold:   0.5,
+		TimeoutRateThreshold: 0.5,
+		OpenSeconds:          30,
+		ProbeRequests:        2,
+	}
+}
+
+func TestNewTrackerDisabled(t *testing.T) {
+	tracker := NewTracker(config.BidderHealth{Enabled: false}, &metrics.MetricsEngineMock{})
+
+	tracker.Record(bidder, Timeout)
+
+	assert.IsType(t, &NilTracker{}, tracker)
+	assert.True(t, tracker.Allow(bidder))
+	assert.Empty(t, tracker.Status())
+}
+
+func TestOutcomeFromErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		errs        []error
+		expected    Outcome
+	}{
+		{
+			description: "No errors",
+			expected:    Success,
+		},
+		{
+			description: "Warnings and bad input",
+			errs:        []error{&errortypes.Warning{Message: "warning"}, &errortypes.BadInput{Message: "bad input"}, &errortypes.FailedToRequestBids{Message: "no requests"}},
+			expected:    Success,
+		},
+		{
+			description: "Bad server response",
+			errs:        []error{&errortypes.BadServerResponse{Message: "bad response"}},
+			expected:    Error,
+		},
+		{
+			description: "Connection error",
+			errs:        []error{errors.New("connection refused")},
+			expected:    Error,
+		},
+		{
+			description: "Timeout takes precedence",
+			errs:        []error{errors.New("connection refused"), &errortypes.Timeout{Message: "timeout"}},
+			expected:    Timeout,
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, OutcomeFromErrors(test.errs), test.description)
+	}
+}
+
+func TestTrackerTripsOnErrorRate(t *testing.T) {
+	tracker, _, me := newTestTracker(testConfig(), 0)
+
+	tracker.Record(bidder, Error)
+	tracker.Record(bidder, Success)
+	tracker.Record(bidder, Error)
+	assert.Equal(t, metrics.AdapterHealthClosed, tracker.Status()[bidder].State, "min requests not reached")
+
+	tracker.Record(bidder, Success)
+
+	assert.Equal(t, Status{State: metrics.AdapterHealthOpen, Since: tracker.time.Now(), Requests: 4, Errors: 2}, tracker.Status()[bidder])
+	assert.False(t, tracker.Allow(bidder))
+	me.AssertCalled(t, "RecordAdapterHealthState", bidder, metrics.AdapterHealthOpen)
+	me.AssertCalled(t, "RecordAdapterHealthBlocked", bidder)
+}
+
+func TestTrackerTripsOnTimeoutRate(t *testing.T) {
+	cfg := testConfig()
+	cfg.ErrorRateThreshold = 1
+	tracker, _, _ := newTestTracker(cfg, 0)
+
+	tracker.Record(bidder, Timeout)
+	tracker.Record(bidder, Error)
+	tracker.Record(bidder, Timeout)
+	tracker.Record(bidder, Success)
+
+	assert.Equal(t, metrics.AdapterHealthOpen, tracker.Status()[bidder].State)
+}
+
+func TestTrackerWindowReset(t *testing.T) {
+	tracker, clock, _ := newTestTracker(testConfig(), 0)
+
+	tracker.Record(bidder, Error)
+	tracker.Record(bidder, Error)
+	tracker.Record(bidder, Error)
+	clock.time = clock.time.Add(time.Minute)
+	tracker.Record(bidder, Error)
+
+	assert.Equal(t, Status{State: metrics.AdapterHealthClosed, Since: clock.time.Add(-time.Minute), Requests: 1, Errors: 1}, tracker.Status()[bidder])
+	assert.True(t, tracker.Allow(bidder))
+}
+
+func TestTrackerSampled(t *testing.T) {
+	testCases := []struct {
+		description   string
+		randomValue   int
+		expectedAllow bool
+	}{
+		{
+			description:   "Sampled in",
+			randomValue:   9,
+			expectedAllow: true,
+		},
+		{
+			description:   "Sampled out",
+			randomValue:   10,
+			expectedAllow: false,
+		},
+	}
+
+	for _, test := range testCases {
+		cfg := testConfig()
+		cfg.MinRequests = 1
+		cfg.SamplePercentage = 10
+		tracker, _, _ := newTestTracker(cfg, test.randomValue)
+
+		tracker.Record(bidder, Timeout)
+
+		assert.Equal(t, metrics.AdapterHealthSampled, tracker.Status()[bidder].State, test.description)
+		assert.Equal(t, test.expectedAllow, tracker.Allow(bidder), test.description)
+	}
+}
+
+func TestTrackerRecovery(t *testing.T) {
+	cfg := testConfig()
+	cfg.MinRequests = 1
+	tracker, clock, me := newTestTracker(cfg, 0)
+
+	tracker.Record(bidder, Error)
+	clock.time = clock.time.Add(29 * time.Second)
+	assert.False(t, tracker.Allow(bidder), "circuit still open")
+
+	clock.time = clock.time.Add(time.Second)
+	assert.True(t, tracker.Allow(bidder), "first probe")
+	assert.True(t, tracker.Allow(bidder), "second probe")
+	assert.False(t, tracker.Allow(bidder), "probes exhausted")
+	assert.Equal(t, metrics.AdapterHealthHalfOpen, tracker.Status()[bidder].State)
+
+	tracker.Record(bidder, Success)
+	tracker.Record(bidder, Success)
+
+	assert.Equal(t, Status{State: metrics.AdapterHealthClosed, Since: clock.time}, tracker.Status()[bidder])
+	assert.True(t, tracker.Allow(bidder))
+	me.AssertCalled(t, "RecordAdapterHealthState", bidder, metrics.AdapterHealthHalfOpen)
+	me.AssertCalled(t, "RecordAdapterHealthState", bidder, metrics.AdapterHealthClosed)
+}
+
+func TestTrackerFailedProbe(t *testing.T) {
+	cfg := testConfig()
+	cfg.MinRequests = 1
+	tracker, clock, _ := newTestTracker(cfg, 0)
+
+	tracker.Record(bidder, Error)
+	clock.time = clock.time.Add(30 * time.Second)
+	assert.True(t, tracker.Allow(bidder))
+
+	tracker.Record(bidder, Timeout)
+
+	assert.Equal(t, metrics.AdapterHealthOpen, tracker.Status()[bidder].State)
+	assert.Equal(t, clock.time, tracker.Status()[bidder].Since)
+	assert.False(t, tracker.Allow(bidder))
+}
+
+func TestTrackerLostProbes(t *testing.T) {
+	cfg := testConfig()
+	cfg.MinRequests = 1
+	tracker, clock, _ := newTestTracker(cfg, 0)
+
+	tracker.Record(bidder, Error)
+	clock.time = clock.time.Add(30 * time.Second)
+	assert.True(t, tracker.Allow(bidder), "first probe")
+	assert.True(t, tracker.Allow(bidder), "second probe")
+	assert.False(t, tracker.Allow(bidder), "probes pending")
+
+	clock.time = clock.time.Add(29 * time.Second)
+	assert.False(t, tracker.Allow(bidder), "probes still pending")
+
+	clock.time = clock.time.Add(time.Second)
+	assert.True(t, tracker.Allow(bidder), "probe sent again")
+	assert.Equal(t, metrics.AdapterHealthHalfOpen, tracker.Status()[bidder].State)
+	assert.Equal(t, clock.time, tracker.Status()[bidder].Since)
+}
+
+func TestTrackerIgnoresResultsWhileOpen(t *testing.T) {
+	cfg := testConfig()
+	cfg.MinRequests = 1
+	tracker, _, _ := newTestTracker(cfg, 0)
+
+	tracker.Record(bidder, Error)
+	tracker.Record(bidder, Success)
+
+	assert.Equal(t, Status{State: metrics.AdapterHealthOpen, Since: tracker.time.Now(), Requests: 1, Errors: 1}, tracker.Status()[bidder])
+}
@@ -2,10 +2,13 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/prebid/go-gdpr/consentconstants"
+	"github.com/prebid/openrtb/v17/adcom1"
 	"github.com/prebid/prebid-server/openrtb_ext"
 )
 
@@ -22,26 +25,308 @@ const (
 
 // Account represents a publisher account configuration
 type Account struct {
-	ID                      string                               `mapstructure:"id" json:"id"`
-	Disabled                bool                                 `mapstructure:"disabled" json:"disabled"`
-	CacheTTL                DefaultTTLs                          `mapstructure:"cache_ttl" json:"cache_ttl"`
-	EventsEnabled           bool                                 `mapstructure:"events_enabled" json:"events_enabled"`
-	CCPA                    AccountCCPA                          `mapstructure:"ccpa" json:"ccpa"`
-	GDPR                    AccountGDPR                          `mapstructure:"gdpr" json:"gdpr"`
-	DebugAllow              bool                                 `mapstructure:"debug_allow" json:"debug_allow"`
-	DefaultIntegration      string                               `mapstructure:"default_integration" json:"default_integration"`
-	CookieSync              CookieSync                           `mapstructure:"cookie_sync" json:"cookie_sync"`
-	Events                  Events                               `mapstructure:"events" json:"events"` // Don't enable this feature. It is still under developmment - https://github.com/prebid/prebid-server/issues/1725
-	TruncateTargetAttribute *int                                 `mapstructure:"truncate_target_attr" json:"truncate_target_attr"`
-	AlternateBidderCodes    *openrtb_ext.ExtAlternateBidderCodes `mapstructure:"alternatebiddercodes" json:"alternatebiddercodes"`
-	Hooks                   AccountHooks                         `mapstructure:"hooks" json:"hooks"`
-}
-
-// CookieSync represents the account-level defaults for the cookie sync endpoint.
+	ID                      string                                      `mapstructure:"id" json:"id"`
+	Disabled                bool                                        `mapstructure:"disabled" json:"disabled"`
+	CacheTTL                DefaultTTLs                                 `mapstructure:"cache_ttl" json:"cache_ttl"`
+	EventsEnabled           bool                                        `mapstructure:"events_enabled" json:"events_enabled"`
+	CCPA                    AccountCCPA                                 `mapstructure:"ccpa" json:"ccpa"`
+	GDPR                    AccountGDPR                                 `mapstructure:"gdpr" json:"gdpr"`
+	DebugAllow              bool                                        `mapstructure:"debug_allow" json:"debug_allow"`
+	DefaultIntegration      string                                      `mapstructure:"default_integration" json:"default_integration"`
+	CookieSync              CookieSync                                  `mapstructure:"cookie_sync" json:"cookie_sync"`
+	Events                  Events                                      `mapstructure:"events" json:"events"` // Don't enable this feature. It is still under developmment - https://github.com/prebid/prebid-server/issues/1725
+	TruncateTargetAttribute *int                                        `mapstructure:"truncate_target_attr" json:"truncate_target_attr"`
+	AlternateBidderCodes    *openrtb_ext.ExtAlternateBidderCodes        `mapstructure:"alternatebiddercodes" json:"alternatebiddercodes"`
+	Hooks                   AccountHooks                                `mapstructure:"hooks" json:"hooks"`
+	PriceFloors             AccountPriceFloors                          `mapstructure:"price_floors" json:"price_floors"`
+	Activities              AccountActivities                           `mapstructure:"activities" json:"activities"`
+	BidAdjustments          *openrtb_ext.ExtRequestPrebidBidAdjustments `mapstructure:"bidadjustments" json:"bidadjustments"`
+	Auction                 AccountAuction                              `mapstructure:"auction" json:"auction"`
+	BidValidations          AccountBidValidations                       `mapstructure:"bidvalidations" json:"bidvalidations"`
+	Blocking                AccountBlocking                             `mapstructure:"blocking" json:"blocking"`
+	Currency                AccountCurrency                             `mapstructure:"currency" json:"currency"`
+	Targeting               AccountTargeting                            `mapstructure:"targeting" json:"targeting"`
+	Cookie                  AccountCookie                               `mapstructure:"cookie" json:"cookie"`
+}
+
+// AccountCookie overrides the host_cookie attributes of the cookies set for the publisher's pages. Unset values
+// keep the host config.
+type AccountCookie struct {
+	Domain      string `mapstructure:"domain" json:"domain,omitempty"`
+	SameSite    string `mapstructure:"samesite" json:"samesite,omitempty"`
+	Partitioned *bool  `mapstructure:"partitioned" json:"partitioned,omitempty"`
+}
+
+// Validate returns an error if the SameSite mode is not supported, or if the domain isn't the host cookie domain
+// nor one of its subdomains, which would let an account set the cookies of the host on the domain of someone else.
+func (c *AccountCookie) Validate(hostDomain string) error {
+	if err := ValidateSameSite(c.SameSite); err != nil {
+		return fmt.Errorf("cookie.%v", err)
+	}
+	if c.Domain != "" {
+		if hostDomain == "" {
+			return fmt.Errorf("cookie.domain requires host_cookie.domain to be set. Got %s", c.Domain)
+		}
+		if !cookieDomainMatches(c.Domain, hostDomain) {
+			return fmt.Errorf("cookie.domain must be %s or one of its subdomains. Got %s", hostDomain, c.Domain)
+		}
+	}
+	return nil
+}
+
+// cookieDomainMatches returns true if the domain is the parent domain or one of its subdomains. A leading dot
+// is ignored, as browsers do.
+func cookieDomainMatches(domain string, parent string) bool {
+	domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+	parent = strings.ToLower(strings.TrimPrefix(parent, "."))
+	return domain == parent || strings.HasSuffix(domain, "."+parent)
+}
+
+// AccountCurrency holds the conversion rates of the account, which take precedence over the rates of the
+// currency converter
+type AccountCurrency struct {
+	Rates map[string]map[string]float64 `mapstructure:"rates" json:"rates"`
+}
+
+// AccountAuction selects the auction type and reserve handling used for the account. The request
+// ext.prebid.auction takes precedence over these values.
+type AccountAuction struct {
+	Type      string  `mapstructure:"type" json:"type"`
+	Increment float64 `mapstructure:"increment" json:"increment"`
+	Reserve   string  `mapstructure:"reserve" json:"reserve"`
+}
+
+// Validate returns an error if the auction type, reserve type or increment is not supported
+func (a *AccountAuction) Validate() error {
+	if err := openrtb_ext.ValidateAuction(a.Type, a.Reserve, &a.Increment); err != nil {
+		return fmt.Errorf("auction.%v", err)
+	}
+	return nil
+}
+
+// Bid validation modes. Skip disables the validation, warn reports the invalid bids and enforce rejects them.
+const (
+	ValidationSkip    = "skip"
+	ValidationWarn    = "warn"
+	ValidationEnforce = "enforce"
+)
+
+// AccountBidValidations selects how the creative validations are applied to the bids of the account.
+// A bid banner size must match one of the imp banner sizes, unless a max creative size is set, in which case
+// the bid banner size must only fit within it. A bid on a secure imp must not load insecure resources.
+type AccountBidValidations struct {
+	BannerCreativeSize string `mapstructure:"banner_creative_size" json:"banner_creative_size"`
+	SecureMarkup       string `mapstructure:"secure_markup" json:"secure_markup"`
+	MaxCreativeWidth   int64  `mapstructure:"max_creative_width" json:"max_creative_width"`
+	MaxCreativeHeight  int64  `mapstructure:"max_creative_height" json:"max_creative_height"`
+}
+
+// Validate returns an error if a validation mode is not supported or a max creative size is negative
+func (v *AccountBidValidations) Validate() error {
+	if !isValidationMode(v.BannerCreativeSize) {
+		return fmt.Errorf("bidvalidations.banner_creative_size must be one of skip, warn or enforce. Got %q", v.BannerCreativeSize)
+	}
+	if !isValidationMode(v.SecureMarkup) {
+		return fmt.Errorf("bidvalidations.secure_markup must be one of skip, warn or enforce. Got %q", v.SecureMarkup)
+	}
+	if v.MaxCreativeWidth < 0 || v.MaxCreativeHeight < 0 {
+		return fmt.Errorf("bidvalidations.max_creative_width and max_creative_height must be >= 0. Got %dx%d", v.MaxCreativeWidth, v.MaxCreativeHeight)
+	}
+	return nil
+}
+
+// isValidationMode returns true for the supported modes. An empty mode is treated as skip.
+func isValidationMode(mode string) bool {
+	switch mode {
+	case "", ValidationSkip, ValidationWarn, ValidationEnforce:
+		return true
+	}
+	return false
+}
+
+// AccountBlocking lists the advertiser domains, categories, apps and creative attributes blocked on the account.
+// The lists are added to the requests sent to the bidders. Mode selects whether the bids violating the blocking
+// lists of the request are skipped, only reported (warn) or rejected (enforce). BidderModes overrides it per bidder.
+type AccountBlocking struct {
+	BAdv        []string                   `mapstructure:"badv" json:"badv"`
+	BCat        []string                   `mapstructure:"bcat" json:"bcat"`
+	BApp        []string                   `mapstructure:"bapp" json:"bapp"`
+	BAttr       []adcom1.CreativeAttribute `mapstructure:"battr" json:"battr"`
+	Mode        string                     `mapstructure:"mode" json:"mode"`
+	BidderModes map[string]string          `mapstructure:"bidder_modes" json:"bidder_modes"`
+}
+
+// Validate returns an error if the mode or one of the bidder modes is not supported
+func (b *AccountBlocking) Validate() error {
+	if !isValidationMode(b.Mode) {
+		return fmt.Errorf("blocking.mode must be one of skip, warn or enforce. Got %q", b.Mode)
+	}
+	for bidder, mode := range b.BidderModes {
+		if !isValidationMode(mode) {
+			return fmt.Errorf("blocking.bidder_modes.%s must be one of skip, warn or enforce. Got %q", bidder, mode)
+		}
+	}
+	return nil
+}
+
+// ModeForBidder returns the blocking mode of the bidder, falling back to the account mode. Bidder names are
+// case insensitive.
+func (b *AccountBlocking) ModeForBidder(bidder string) string {
+	for name, mode := range b.BidderModes {
+		if strings.EqualFold(name, bidder) && mode != "" {
+			return mode
+		}
+	}
+	if b.Mode == "" {
+		return ValidationSkip
+	}
+	return b.Mode
+}
+
+// AccountTargeting holds the targeting settings of the requests which omit ext.prebid.targeting, like most AMP
+// requests. Unset values keep the request defaults: medium price granularity with winner and bidder keys.
+// MediaTypePriceGranularity also applies to the requests whose targeting doesn't set one.
+type AccountTargeting struct {
+	PriceGranularity          *openrtb_ext.PriceGranularity          `mapstructure:"pricegranularity" json:"pricegranularity,omitempty"`
+	MediaTypePriceGranularity *openrtb_ext.MediaTypePriceGranularity `mapstructure:"mediatypepricegranularity" json:"mediatypepricegranularity,omitempty"`
+	IncludeWinners            *bool                                  `mapstructure:"includewinners" json:"includewinners,omitempty"`
+	IncludeBidderKeys         *bool                                  `mapstructure:"includebidderkeys" json:"includebidderkeys,omitempty"`
+	IncludeFormat             *bool                                  `mapstructure:"includeformat" json:"includeformat,omitempty"`
+	PreferDeals               *bool                                  `mapstructure:"preferdeals" json:"preferdeals,omitempty"`
+}
+
+// Validate returns an error if both the winner and the bidder keys are disabled
+func (t *AccountTargeting) Validate() error {
+	if t.IncludeWinners != nil && !*t.IncludeWinners && t.IncludeBidderKeys != nil && !*t.IncludeBidderKeys {
+		return errors.New("targeting: at least one of includewinners or includebidderkeys must be enabled")
+	}
+	return nil
+}
+
+// Enabled returns true when the account sets any targeting value, which turns on targeting for the requests
+// which omit ext.prebid.targeting
+func (t *AccountTargeting) Enabled() bool {
+	return t.PriceGranularity != nil || t.MediaTypePriceGranularity != nil || t.IncludeWinners != nil ||
+		t.IncludeBidderKeys != nil || t.IncludeFormat != nil || t.PreferDeals != nil
+}
+
+// RequestTargeting returns the targeting of the requests which omit ext.prebid.targeting
+func (t *AccountTargeting) RequestTargeting() *openrtb_ext.ExtRequestTargeting {
+	targeting := &openrtb_ext.ExtRequestTargeting{
+		PriceGranularity:          openrtb_ext.PriceGranularityFromString("med"),
+		MediaTypePriceGranularity: t.MediaTypePriceGranularity,
+		IncludeWinners:            true,
+		IncludeBidderKeys:         true,
+	}
+	if t.PriceGranularity != nil {
+		targeting.PriceGranularity = *t.PriceGranularity
+	}
+	if t.IncludeWinners != nil {
+		targeting.IncludeWinners = *t.IncludeWinners
+	}
+	if t.IncludeBidderKeys != nil {
+		targeting.IncludeBidderKeys = *t.IncludeBidderKeys
+	}
+	if t.IncludeFormat != nil {
+		targeting.IncludeFormat = *t.IncludeFormat
+	}
+	if t.PreferDeals != nil {
+		targeting.PreferDeals = *t.PreferDeals
+	}
+	return targeting
+}
+
+// AccountPriceFloors represents account-specific price floors configuration
+type AccountPriceFloors struct {
+	Enabled           bool                        `mapstructure:"enabled" json:"enabled"`
+	EnforceFloorsRate int                         `mapstructure:"enforce_floors_rate" json:"enforce_floors_rate"`
+	EnforceDealFloors bool                        `mapstructure:"enforce_deal_floors" json:"enforce_deal_floors"`
+	UseDynamicData    bool                        `mapstructure:"use_dynamic_data" json:"use_dynamic_data"`
+	MaxRule           int                         `mapstructure:"max_rules" json:"max_rules"`
+	MaxSchemaDims     int                         `mapstructure:"max_schema_dims" json:"max_schema_dims"`
+	Data              *openrtb_ext.PriceFloorData `mapstructure:"data" json:"data,omitempty"`
+	Fetcher           AccountFloorFetch           `mapstructure:"fetch" json:"fetch"`
+}
+
+// AccountFloorFetch defines the configuration for periodically loading floor rules from a remote or local source.
+// URL may use the http, https or file scheme.
+type AccountFloorFetch struct {
+	Enabled     bool   `mapstructure:"enabled" json:"enabled"`
+	URL         string `mapstructure:"url" json:"url"`
+	Timeout     int    `mapstructure:"timeout_ms" json:"timeout_ms"`
+	MaxFileSize int    `mapstructure:"max_file_size_kb" json:"max_file_size_kb"`
+	MaxRules    int    `mapstructure:"max_rules" json:"max_rules"`
+	MaxAge      int    `mapstructure:"max_age_sec" json:"max_age_sec"`
+	Period      int    `mapstructure:"period_sec" json:"period_sec"`
+}
+
+func (pf *AccountPriceFloors) validate(errs []error) []error {
+	if pf.EnforceFloorsRate < 0 || pf.EnforceFloorsRate > 100 {
+		errs = append(errs, fmt.Errorf("account_defaults.price_floors.enforce_floors_rate should be between 0 and 100"))
+	}
+	if pf.MaxRule < 0 || pf.MaxRule > math.MaxInt32 {
+		errs = append(errs, fmt.Errorf("account_defaults.price_floors.max_rules should be between 0 and %v", math.MaxInt32))
+	}
+	if pf.MaxSchemaDims < 0 || pf.MaxSchemaDims > 6 {
+		errs = append(errs, fmt.Errorf("account_defaults.price_floors.max_schema_dims should be between 0 and 6"))
+	}
+	if pf.Fetcher.Period > 0 && pf.Fetcher.Period < 300 {
+		errs = append(errs, fmt.Errorf("account_defaults.price_floors.fetch.period_sec should not be less than 300 seconds"))
+	}
+	if pf.Fetcher.MaxAge > 0 && pf.Fetcher.MaxAge < pf.Fetcher.Period {
+		errs = append(errs, fmt.Errorf("account_defaults.price_floors.fetch.max_age_sec should not be less than period_sec"))
+	}
+	if pf.Fetcher.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("account_defaults.price_floors.fetch.timeout_ms should be a positive value"))
+	}
+	return errs
+}
+
+// CookieSync represents the account-level defaults and rules for the cookie sync endpoint.
 type CookieSync struct {
 	DefaultLimit    *int  `mapstructure:"default_limit" json:"default_limit"`
 	MaxLimit        *int  `mapstructure:"max_limit" json:"max_limit"`
 	DefaultCoopSync *bool `mapstructure:"default_coop_sync" json:"default_coop_sync"`
+	// IncludeBidders restricts the syncs to these bidders when set. ExcludeBidders are never synced, whatever the
+	// request asks for.
+	IncludeBidders []string `mapstructure:"include_bidders" json:"include_bidders"`
+	ExcludeBidders []string `mapstructure:"exclude_bidders" json:"exclude_bidders"`
+	// PriorityGroups are synced before the other bidders, a group at a time, even without cooperative syncing.
+	PriorityGroups [][]string `mapstructure:"priority_groups" json:"priority_groups"`
+	// PreferredSyncType is the sync type, iframe or redirect, used for the bidders which support both.
+	PreferredSyncType string `mapstructure:"preferred_sync_type" json:"preferred_sync_type"`
+}
+
+// Cookie sync types an account can prefer
+const (
+	SyncTypeIFrame   = "iframe"
+	SyncTypeRedirect = "redirect"
+)
+
+// Validate returns an error if the preferred sync type is not supported or an excluded bidder is also included
+// or in a priority group
+func (cs *CookieSync) Validate() error {
+	switch cs.PreferredSyncType {
+	case "", SyncTypeIFrame, SyncTypeRedirect:
+	default:
+		return fmt.Errorf("cookie_sync.preferred_sync_type must be one of %s or %s. Got %q", SyncTypeIFrame, SyncTypeRedirect, cs.PreferredSyncType)
+	}
+
+	for _, excluded := range cs.ExcludeBidders {
+		for _, included := range cs.IncludeBidders {
+			if included == excluded {
+				return fmt.Errorf("cookie_sync: bidder %s is both included and excluded", included)
+			}
+		}
+		for _, group := range cs.PriorityGroups {
+			for _, prioritized := range group {
+				if prioritized == excluded {
+					return fmt.Errorf("cookie_sync: bidder %s is both in a priority group and excluded", prioritized)
+				}
+			}
+		}
+	}
+	return nil
 }
 
 // AccountCCPA represents account-specific CCPA configuration
@@ -824,3 +824,295 @@ func TestModulesGetConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestAccountAuctionValidate(t *testing.T) {
+	testCases := []struct {
+		description   string
+		givenAuction  AccountAuction
+		expectedError string
+	}{
+		{
+			description:  "Empty",
+			givenAuction: AccountAuction{},
+		},
+		{
+			description:  "Valid",
+			givenAuction: AccountAuction{Type: "second_price", Increment: 0.01, Reserve: "hard"},
+		},
+		{
+			description:   "Invalid Type",
+			givenAuction:  AccountAuction{Type: "vickrey"},
+			expectedError: `auction.type "vickrey" is not supported`,
+		},
+		{
+			description:   "Invalid Reserve",
+			givenAuction:  AccountAuction{Reserve: "strict"},
+			expectedError: `auction.reserve "strict" is not supported`,
+		},
+		{
+			description:   "Negative Increment",
+			givenAuction:  AccountAuction{Increment: -0.01},
+			expectedError: `auction.increment -0.01 must be a positive number`,
+		},
+	}
+
+	for _, test := range testCases {
+		err := test.givenAuction.Validate()
+		if test.expectedError == "" {
+			assert.NoError(t, err, test.description)
+		} else {
+			assert.EqualError(t, err, test.expectedError, test.description)
+		}
+	}
+}
+
+func TestAccountBidValidationsValidate(t *testing.T) {
+	testCases := []struct {
+		description     string
+		givenValidation AccountBidValidations
+		expectedError   string
+	}{
+		{
+			description:     "Empty",
+			givenValidation: AccountBidValidations{},
+		},
+		{
+			description:     "Valid",
+			givenValidation: AccountBidValidations{BannerCreativeSize: "enforce", SecureMarkup: "warn", MaxCreativeWidth: 300, MaxCreativeHeight: 250},
+		},
+		{
+			description:     "Invalid Banner Creative Size Mode",
+			givenValidation: AccountBidValidations{BannerCreativeSize: "reject"},
+			expectedError:   `bidvalidations.banner_creative_size must be one of skip, warn or enforce. Got "reject"`,
+		},
+		{
+			description:     "Invalid Secure Markup Mode",
+			givenValidation: AccountBidValidations{SecureMarkup: "Warn"},
+			expectedError:   `bidvalidations.secure_markup must be one of skip, warn or enforce. Got "Warn"`,
+		},
+		{
+			description:     "Negative Max Creative Size",
+			givenValidation: AccountBidValidations{MaxCreativeWidth: -1},
+			expectedError:   `bidvalidations.max_creative_width and max_creative_height must be >= 0. Got -1x0`,
+		},
+	}
+
+	for _, test := range testCases {
+		err := test.givenValidation.Validate()
+		if test.expectedError == "" {
+			assert.NoError(t, err, test.description)
+		} else {
+			assert.EqualError(t, err, test.expectedError, test.description)
+		}
+	}
+}
+
+func TestAccountBlockingValidate(t *testing.T) {
+	testCases := []struct {
+		description   string
+		givenBlocking AccountBlocking
+		expectedError string
+	}{
+		{
+			description:   "Empty",
+			givenBlocking: AccountBlocking{},
+		},
+		{
+			description:   "Valid",
+			givenBlocking: AccountBlocking{Mode: "warn", BidderModes: map[string]string{"appnexus": "enforce"}},
+		},
+		{
+			description:   "Invalid Mode",
+			givenBlocking: AccountBlocking{Mode: "log"},
+			expectedError: `blocking.mode must be one of skip, warn or enforce. Got "log"`,
+		},
+		{
+			description:   "Invalid Bidder Mode",
+			givenBlocking: AccountBlocking{BidderModes: map[string]string{"appnexus": "block"}},
+			expectedError: `blocking.bidder_modes.appnexus must be one of skip, warn or enforce. Got "block"`,
+		},
+	}
+
+	for _, test := range testCases {
+		err := test.givenBlocking.Validate()
+		if test.expectedError == "" {
+			assert.NoError(t, err, test.description)
+		} else {
+			assert.EqualError(t, err, test.expectedError, test.description)
+		}
+	}
+}
+
+func TestAccountBlockingModeForBidder(t *testing.T) {
+	blocking := AccountBlocking{Mode: "warn", BidderModes: map[string]string{"AppNexus": "enforce", "rubicon": ""}}
+
+	assert.Equal(t, "enforce", blocking.ModeForBidder("appnexus"), "bidder override")
+	assert.Equal(t, "warn", blocking.ModeForBidder("rubicon"), "empty bidder override")
+	assert.Equal(t, "warn", blocking.ModeForBidder("pubmatic"), "account mode")
+	assert.Equal(t, "skip", (&AccountBlocking{}).ModeForBidder("pubmatic"), "default")
+}
+
+func TestAccountTargetingValidate(t *testing.T) {
+	enabled, disabled := true, false
+
+	testCases := []struct {
+		description    string
+		givenTargeting AccountTargeting
+		expectedError  string
+	}{
+		{
+			description:    "Empty",
+			givenTargeting: AccountTargeting{},
+		},
+		{
+			description:    "Bidder keys only",
+			givenTargeting: AccountTargeting{IncludeWinners: &disabled, IncludeBidderKeys: &enabled},
+		},
+		{
+			description:    "Winner and bidder keys disabled",
+			givenTargeting: AccountTargeting{IncludeWinners: &disabled, IncludeBidderKeys: &disabled},
+			expectedError:  "targeting: at least one of includewinners or includebidderkeys must be enabled",
+		},
+	}
+
+	for _, test := range testCases {
+		err := test.givenTargeting.Validate()
+		if test.expectedError == "" {
+			assert.NoError(t, err, test.description)
+		} else {
+			assert.EqualError(t, err, test.expectedError, test.description)
+		}
+	}
+}
+
+func TestAccountCookieValidate(t *testing.T) {
+	testCases := []struct {
+		description   string
+		givenCookie   AccountCookie
+		hostDomain    string
+		expectedError string
+	}{
+		{
+			description: "Empty",
+			givenCookie: AccountCookie{},
+		},
+		{
+			description: "Valid",
+			givenCookie: AccountCookie{Domain: "publisher.host.com", SameSite: "lax"},
+			hostDomain:  "host.com",
+		},
+		{
+			description: "Host domain",
+			givenCookie: AccountCookie{Domain: ".Host.com"},
+			hostDomain:  "host.com",
+		},
+		{
+			description:   "Invalid SameSite",
+			givenCookie:   AccountCookie{SameSite: "always"},
+			expectedError: "cookie.samesite must be one of [auto, none, lax, strict]. Got always",
+		},
+		{
+			description:   "Domain outside the host domain",
+			givenCookie:   AccountCookie{Domain: "publisher.com"},
+			hostDomain:    "host.com",
+			expectedError: "cookie.domain must be host.com or one of its subdomains. Got publisher.com",
+		},
+		{
+			description:   "Domain ending like the host domain",
+			givenCookie:   AccountCookie{Domain: "evilhost.com"},
+			hostDomain:    "host.com",
+			expectedError: "cookie.domain must be host.com or one of its subdomains. Got evilhost.com",
+		},
+		{
+			description:   "Domain without host domain",
+			givenCookie:   AccountCookie{Domain: "publisher.com"},
+			expectedError: "cookie.domain requires host_cookie.domain to be set. Got publisher.com",
+		},
+	}
+
+	for _, test := range testCases {
+		err := test.givenCookie.Validate(test.hostDomain)
+		if test.expectedError == "" {
+			assert.NoError(t, err, test.description)
+		} else {
+			assert.EqualError(t, err, test.expectedError, test.description)
+		}
+	}
+}
+
+func TestCookieSyncValidate(t *testing.T) {
+	testCases := []struct {
+		description     string
+		givenCookieSync CookieSync
+		expectedError   string
+	}{
+		{
+			description:     "Empty",
+			givenCookieSync: CookieSync{},
+		},
+		{
+			description: "Valid",
+			givenCookieSync: CookieSync{
+				IncludeBidders:    []string{"appnexus", "rubicon"},
+				ExcludeBidders:    []string{"pubmatic"},
+				PriorityGroups:    [][]string{{"appnexus"}},
+				PreferredSyncType: "redirect",
+			},
+		},
+		{
+			description:     "Invalid preferred sync type",
+			givenCookieSync: CookieSync{PreferredSyncType: "image"},
+			expectedError:   `cookie_sync.preferred_sync_type must be one of iframe or redirect. Got "image"`,
+		},
+		{
+			description:     "Bidder included and excluded",
+			givenCookieSync: CookieSync{IncludeBidders: []string{"appnexus", "rubicon"}, ExcludeBidders: []string{"rubicon"}},
+			expectedError:   "cookie_sync: bidder rubicon is both included and excluded",
+		},
+		{
+			description:     "Bidder in a priority group and excluded",
+			givenCookieSync: CookieSync{ExcludeBidders: []string{"pubmatic"}, PriorityGroups: [][]string{{"appnexus"}, {"rubicon", "pubmatic"}}},
+			expectedError:   "cookie_sync: bidder pubmatic is both in a priority group and excluded",
+		},
+	}
+
+	for _, test := range testCases {
+		err := test.givenCookieSync.Validate()
+		if test.expectedError == "" {
+			assert.NoError(t, err, test.description)
+		} else {
+			assert.EqualError(t, err, test.expectedError, test.description)
+		}
+	}
+}
+
+func TestAccountTargetingRequestTargeting(t *testing.T) {
+	enabled, disabled := true, false
+	lowGranularity := openrtb_ext.PriceGranularityFromString("low")
+
+	assert.Equal(t, &openrtb_ext.ExtRequestTargeting{
+		PriceGranularity:  openrtb_ext.PriceGranularityFromString("med"),
+		IncludeWinners:    true,
+		IncludeBidderKeys: true,
+	}, (&AccountTargeting{}).RequestTargeting(), "defaults")
+	assert.False(t, (&AccountTargeting{}).Enabled(), "defaults")
+
+	targeting := AccountTargeting{
+		PriceGranularity:          &lowGranularity,
+		MediaTypePriceGranularity: &openrtb_ext.MediaTypePriceGranularity{Video: &lowGranularity},
+		IncludeWinners:            &disabled,
+		IncludeBidderKeys:         &enabled,
+		IncludeFormat:             &enabled,
+		PreferDeals:               &enabled,
+	}
+	assert.Equal(t, &openrtb_ext.ExtRequestTargeting{
+		PriceGranularity:          lowGranularity,
+		MediaTypePriceGranularity: &openrtb_ext.MediaTypePriceGranularity{Video: &lowGranularity},
+		IncludeBidderKeys:         true,
+		IncludeFormat:             true,
+		PreferDeals:               true,
+	}, targeting.RequestTargeting(), "account settings")
+	assert.True(t, targeting.Enabled(), "account settings")
+	assert.True(t, (&AccountTargeting{PriceGranularity: &lowGranularity}).Enabled(), "price granularity only")
+	assert.True(t, (&AccountTargeting{IncludeWinners: &disabled}).Enabled(), "winners disabled only")
+}
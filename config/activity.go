@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Component types an activity rule condition may reference. Hook modules aren't subject to the activity
+// controls, so the "module" component type is rejected rather than silently never matching.
+const (
+	ComponentTypeBidder    = "bidder"
+	ComponentTypeAnalytics = "analytics"
+)
+
+// AccountActivities defines the rules controlling which components may perform privacy sensitive activities
+type AccountActivities struct {
+	SyncUser           Activity `mapstructure:"syncUser" json:"syncUser"`
+	FetchBids          Activity `mapstructure:"fetchBids" json:"fetchBids"`
+	TransmitUserFPD    Activity `mapstructure:"transmitUfpd" json:"transmitUfpd"`
+	TransmitPreciseGeo Activity `mapstructure:"transmitPreciseGeo" json:"transmitPreciseGeo"`
+	ReportAnalytics    Activity `mapstructure:"reportAnalytics" json:"reportAnalytics"`
+}
+
+// Activity holds the ordered rules of a single activity. The first rule whose condition matches decides
+// whether the activity is allowed. Default applies when no rule matches and is treated as true if not set.
+type Activity struct {
+	Default *bool          `mapstructure:"default" json:"default"`
+	Rules   []ActivityRule `mapstructure:"rules" json:"rules"`
+}
+
+// ActivityRule allows or denies the activity for the components matching its condition
+type ActivityRule struct {
+	Condition ActivityCondition `mapstructure:"condition" json:"condition"`
+	Allow     bool              `mapstructure:"allow" json:"allow"`
+}
+
+// ActivityCondition restricts a rule to the listed components, GPP sections and geographies. All
+// non-empty fields must match. Geo entries are ISO-3166-1-alpha-3 country codes, optionally followed
+// by a dot and the region code, e.g. "USA" or "USA.CA".
+type ActivityCondition struct {
+	ComponentName []string `mapstructure:"componentName" json:"componentName"`
+	ComponentType []string `mapstructure:"componentType" json:"componentType"`
+	GPPSID        []int8   `mapstructure:"gppSid" json:"gppSid"`
+	Geo           []string `mapstructure:"geo" json:"geo"`
+}
+
+// Validate returns an error if any of the activity rules references an unknown component type or
+// an empty geo.
+func (a *AccountActivities) Validate() error {
+	activities := []struct {
+		name     string
+		activity Activity
+	}{
+		{"syncUser", a.SyncUser},
+		{"fetchBids", a.FetchBids},
+		{"transmitUfpd", a.TransmitUserFPD},
+		{"transmitPreciseGeo", a.TransmitPreciseGeo},
+		{"reportAnalytics", a.ReportAnalytics},
+	}
+
+	for _, entry := range activities {
+		for i, rule := range entry.activity.Rules {
+			for _, componentType := range rule.Condition.ComponentType {
+				if !isComponentType(componentType) {
+					return fmt.Errorf("activities.%s.rules[%d] has an invalid componentType %s", entry.name, i, componentType)
+				}
+			}
+			for _, geo := range rule.Condition.Geo {
+				if strings.Trim(geo, ".") == "" {
+					return fmt.Errorf("activities.%s.rules[%d] has an empty geo", entry.name, i)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func isComponentType(componentType string) bool {
+	switch strings.ToLower(componentType) {
+	case ComponentTypeBidder, ComponentTypeAnalytics:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountActivitiesValidate(t *testing.T) {
+	testCases := []struct {
+		description     string
+		givenActivities AccountActivities
+		expectedError   string
+	}{
+		{
+			description:     "Empty",
+			givenActivities: AccountActivities{},
+		},
+		{
+			description: "Valid",
+			givenActivities: AccountActivities{
+				SyncUser: Activity{Rules: []ActivityRule{
+					{Condition: ActivityCondition{ComponentType: []string{"bidder", "Analytics"}, Geo: []string{"USA.CA"}}},
+				}},
+			},
+		},
+		{
+			description: "Invalid Component Type",
+			givenActivities: AccountActivities{
+				TransmitUserFPD: Activity{Rules: []ActivityRule{
+					{Condition: ActivityCondition{ComponentType: []string{"bidder"}}},
+					{Condition: ActivityCondition{ComponentType: []string{"publisher"}}},
+				}},
+			},
+			expectedError: "activities.transmitUfpd.rules[1] has an invalid componentType publisher",
+		},
+		{
+			description: "Module Component Type",
+			givenActivities: AccountActivities{
+				ReportAnalytics: Activity{Rules: []ActivityRule{
+					{Condition: ActivityCondition{ComponentType: []string{"module"}}},
+				}},
+			},
+			expectedError: "activities.reportAnalytics.rules[0] has an invalid componentType module",
+		},
+		{
+			description: "Empty Geo",
+			givenActivities: AccountActivities{
+				FetchBids: Activity{Rules: []ActivityRule{
+					{Condition: ActivityCondition{Geo: []string{"."}}},
+				}},
+			},
+			expectedError: "activities.fetchBids.rules[0] has an empty geo",
+		},
+	}
+
+	for _, test := range testCases {
+		err := test.givenActivities.Validate()
+
+		if test.expectedError == "" {
+			assert.NoError(t, err, test.description)
+		} else {
+			assert.EqualError(t, err, test.expectedError, test.description)
+		}
+	}
+}
@@ -23,6 +23,9 @@ type BidderInfos map[string]BidderInfo
 
 // BidderInfo specifies all configuration for a bidder except for enabled status, endpoint, and extra information.
 type BidderInfo struct {
+	// AliasOf declares the bidder as an alias of a core bidder. The alias is built with the adapter and
+	// bidder params of the core bidder, and inherits the info it leaves unset.
+	AliasOf          string `yaml:"aliasOf" mapstructure:"aliasOf"`
 	Disabled         bool   `yaml:"disabled" mapstructure:"disabled"`
 	Endpoint         string `yaml:"endpoint" mapstructure:"endpoint"`
 	ExtraAdapterInfo string `yaml:"extra_info" mapstructure:"extra_info"`
@@ -48,6 +51,63 @@ type BidderInfo struct {
 	AppSecret  string `yaml:"app_secret" mapstructure:"app_secret"`
 	// EndpointCompression determines, if set, the type of compression the bid request will undergo before being sent to the corresponding bid server
 	EndpointCompression string `yaml:"endpointCompression" mapstructure:"endpointCompression"`
+
+	// OpenRTB specifies the OpenRTB version of the requests sent to the bidder
+	OpenRTB *OpenRTBInfo `yaml:"openrtb" mapstructure:"openrtb"`
+
+	// GenericORTB configures the generic OpenRTB adapter for the bidders built with it
+	GenericORTB *GenericORTBInfo `yaml:"genericOrtb" mapstructure:"genericOrtb"`
+}
+
+// Locations of the bidder params in the imp.ext sent by the generic OpenRTB adapter
+const (
+	GenericORTBImpParamsBidder = "bidder"
+	GenericORTBImpParamsExt    = "ext"
+)
+
+// Sources of the bid type used by the generic OpenRTB adapter
+const (
+	GenericORTBBidTypeMType = "mtype"
+	GenericORTBBidTypeExt   = "ext"
+	GenericORTBBidTypeImp   = "imp"
+)
+
+// GenericORTBInfo specifies how the generic OpenRTB adapter talks to a bidder, so a bidder following the
+// OpenRTB specification can be added with a bidder info file alone.
+type GenericORTBInfo struct {
+	// ImpParamsLocation is where the bidder params are sent: in imp.ext.bidder, which is the default, or
+	// as imp.ext itself.
+	ImpParamsLocation string `yaml:"impParamsLocation" mapstructure:"impParamsLocation"`
+	// Headers are added to the requests sent to the bidder.
+	Headers map[string]string `yaml:"headers" mapstructure:"headers"`
+	// BidType is where the bid type is read from: bid.mtype, which is the default, a field of bid.ext
+	// or the media type of the imp.
+	BidType string `yaml:"bidType" mapstructure:"bidType"`
+	// BidTypeExtPath is the dot separated path of the bid type within bid.ext when BidType is ext.
+	BidTypeExtPath string `yaml:"bidTypeExtPath" mapstructure:"bidTypeExtPath"`
+	// Currency is the currency of the bids when the bidder response doesn't specify one.
+	Currency string `yaml:"currency" mapstructure:"currency"`
+	// AllowedHosts are the values the host param may take for the Host macro of the endpoint. Imps with
+	// any other host are rejected, so requests can't be sent to arbitrary servers.
+	AllowedHosts []string `yaml:"allowedHosts" mapstructure:"allowedHosts"`
+}
+
+// OpenRTB versions a bidder may declare support for. Bidders which don't declare a version are sent
+// OpenRTB 2.5 requests.
+const (
+	OpenRTBVersion25 = "2.5"
+	OpenRTBVersion26 = "2.6"
+)
+
+// OpenRTBInfo specifies the OpenRTB features supported by a bidder.
+type OpenRTBInfo struct {
+	Version string `yaml:"version" mapstructure:"version"`
+}
+
+// SupportsOpenRTB26 reports whether the bidder is able to receive OpenRTB 2.6 requests, in which
+// case the 2.6 fields are sent in place of their 2.5 extension locations.
+func (bi BidderInfo) SupportsOpenRTB26() bool {
+	return bi.OpenRTB != nil && bi.OpenRTB.Version == OpenRTBVersion26
 }
 
 // BidderInfoExperiment specifies non-production ready feature config for a bidder
@@ -111,6 +171,15 @@ type Syncer struct {
 
 	// SupportCORS identifies if CORS is supported for the user syncing endpoints.
 	SupportCORS *bool `yaml:"supportCors" mapstructure:"support_cors"`
+
+	// UIDTTLDays is the number of days a user id stored in the user sync cookie is considered valid
+	// before the user is synced again. The default of 14 days is used if not specified.
+	UIDTTLDays int `yaml:"uidTtlDays" mapstructure:"uid_ttl_days"`
+
+	// Priority ranks the syncer against the others. Syncers with a higher priority are chosen first by
+	// the /cookie_sync endpoint, and their user ids are the last ones removed when the user sync cookie
+	// is too large. Defaults to 0.
+	Priority int `yaml:"priority" mapstructure:"priority"`
 }
 
 // SyncerEndpoint specifies the configuration of the URL returned by the /cookie_sync endpoint
@@ -208,7 +277,19 @@ func LoadBidderInfoFromDisk(path string) (BidderInfos, error) {
 }
 
 func LoadBidderInfo(reader InfoReader) (BidderInfos, error) {
-	return processBidderInfos(reader, openrtb_ext.NormalizeBidderName)
+	infos, err := processBidderInfos(reader, openrtb_ext.NormalizeBidderName)
+	if err != nil {
+		return nil, err
+	}
+
+	for bidderName, info := range infos {
+		if info.AliasOf != "" {
+			if err := openrtb_ext.SetAliasBidderName(bidderName, openrtb_ext.BidderName(info.AliasOf)); err != nil {
+				return nil, fmt.Errorf("error registering alias %s: %v", bidderName, err)
+			}
+		}
+	}
+	return infos, nil
 }
 
 func processBidderInfos(reader InfoReader, normalizeBidderName func(string) (openrtb_ext.BidderName, bool)) (BidderInfos, error) {
@@ -218,25 +299,80 @@ func processBidderInfos(reader InfoReader, normalizeBidderName func(string) (ope
 	}
 
 	infos := BidderInfos{}
+	aliases := BidderInfos{}
 
 	for fileName, data := range bidderConfigs {
 		bidderName := strings.Split(fileName, ".")
 		if len(bidderName) == 2 && bidderName[1] == "yaml" {
-			normalizedBidderName, bidderNameExists := normalizeBidderName(bidderName[0])
-			if !bidderNameExists {
-				return nil, fmt.Errorf("error parsing config for bidder %s: unknown bidder", fileName)
-			}
 			info := BidderInfo{}
 			if err := yaml.Unmarshal(data, &info); err != nil {
 				return nil, fmt.Errorf("error parsing config for bidder %s: %v", fileName, err)
 			}
 
+			// aliases are not known bidders until they are registered
+			if info.AliasOf != "" {
+				aliases[bidderName[0]] = info
+				continue
+			}
+
+			normalizedBidderName, bidderNameExists := normalizeBidderName(bidderName[0])
+			if !bidderNameExists {
+				return nil, fmt.Errorf("error parsing config for bidder %s: unknown bidder", fileName)
+			}
 			infos[string(normalizedBidderName)] = info
 		}
 	}
+
+	if err := processBidderAliases(aliases, infos, normalizeBidderName); err != nil {
+		return nil, err
+	}
 	return infos, nil
 }
 
+// processBidderAliases adds the aliases to the bidder infos. The info an alias leaves unset is taken from
+// its core bidder, except for the syncer and the GVL vendor ID which belong to the alias.
+func processBidderAliases(aliases BidderInfos, infos BidderInfos, normalizeBidderName func(string) (openrtb_ext.BidderName, bool)) error {
+	for aliasName, aliasInfo := range aliases {
+		if openrtb_ext.IsBidderNameReserved(aliasName) {
+			return fmt.Errorf("error parsing config for alias %s: reserved bidder name", aliasName)
+		}
+		if _, exists := normalizeBidderName(aliasName); exists {
+			return fmt.Errorf("error parsing config for alias %s: the name is used by a core bidder", aliasName)
+		}
+
+		parentName, parentExists := normalizeBidderName(aliasInfo.AliasOf)
+		parentInfo, parentInfoExists := infos[string(parentName)]
+		if !parentExists || !parentInfoExists {
+			return fmt.Errorf("error parsing config for alias %s: core bidder %s not found", aliasName, aliasInfo.AliasOf)
+		}
+
+		aliasInfo.AliasOf = string(parentName)
+		if aliasInfo.Endpoint == "" {
+			aliasInfo.Endpoint = parentInfo.Endpoint
+		}
+		if aliasInfo.Maintainer == nil {
+			aliasInfo.Maintainer = parentInfo.Maintainer
+		}
+		if aliasInfo.Capabilities == nil {
+			aliasInfo.Capabilities = parentInfo.Capabilities
+		}
+		if aliasInfo.Debug == nil {
+			aliasInfo.Debug = parentInfo.Debug
+		}
+		if aliasInfo.EndpointCompression == "" {
+			aliasInfo.EndpointCompression = parentInfo.EndpointCompression
+		}
+		if aliasInfo.OpenRTB == nil {
+			aliasInfo.OpenRTB = parentInfo.OpenRTB
+		}
+		if aliasInfo.GenericORTB == nil {
+			aliasInfo.GenericORTB = parentInfo.GenericORTB
+		}
+		infos[aliasName] = aliasInfo
+	}
+	return nil
+}
+
 // ToGVLVendorIDMap transforms a BidderInfos object to a map of bidder names to GVL id.
 // Disabled bidders are omitted from the result.
 func (infos BidderInfos) ToGVLVendorIDMap() map[openrtb_ext.BidderName]uint16 {
@@ -317,6 +453,12 @@ func validateInfo(info BidderInfo, bidderName string) error {
 	if err := validateCapabilities(info.Capabilities, bidderName); err != nil {
 		return err
 	}
+	if err := validateOpenRTB(info.OpenRTB, bidderName); err != nil {
+		return err
+	}
+	if err := validateGenericORTB(info.GenericORTB, bidderName); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -351,6 +493,40 @@ func validateCapabilities(info *CapabilitiesInfo, bidderName string) error {
 	return nil
 }
 
+func validateOpenRTB(info *OpenRTBInfo, bidderName string) error {
+	if info == nil || info.Version == "" {
+		return nil
+	}
+	if info.Version != OpenRTBVersion25 && info.Version != OpenRTBVersion26 {
+		return fmt.Errorf("openrtb.version %s is not supported for adapter: %s, supported versions are %s and %s", info.Version, bidderName, OpenRTBVersion25, OpenRTBVersion26)
+	}
+	return nil
+}
+
+func validateGenericORTB(info *GenericORTBInfo, bidderName string) error {
+	if info == nil {
+		return nil
+	}
+	switch info.ImpParamsLocation {
+	case "", GenericORTBImpParamsBidder, GenericORTBImpParamsExt:
+	default:
+		return fmt.Errorf("genericOrtb.impParamsLocation %s is not supported for adapter: %s, supported locations are %s and %s", info.ImpParamsLocation, bidderName, GenericORTBImpParamsBidder, GenericORTBImpParamsExt)
+	}
+	switch info.BidType {
+	case "", GenericORTBBidTypeMType, GenericORTBBidTypeImp:
+	case GenericORTBBidTypeExt:
+		if info.BidTypeExtPath == "" {
+			return fmt.Errorf("genericOrtb.bidTypeExtPath is required when genericOrtb.bidType is %s for adapter: %s", GenericORTBBidTypeExt, bidderName)
+		}
+	default:
+		return fmt.Errorf("genericOrtb.bidType %s is not supported for adapter: %s, supported types are %s, %s and %s", info.BidType, bidderName, GenericORTBBidTypeMType, GenericORTBBidTypeExt, GenericORTBBidTypeImp)
+	}
+	if info.Currency != "" && len(info.Currency) != 3 {
+		return fmt.Errorf("genericOrtb.currency %s is not a valid currency code for adapter: %s", info.Currency, bidderName)
+	}
+	return nil
+}
+
 func validatePlatformInfo(info *PlatformInfo) error {
 	if len(info.MediaTypes) == 0 {
 		return errors.New("at least one media type needs to be specified")
@@ -424,6 +600,12 @@ func applyBidderInfoConfigOverrides(configBidderInfos BidderInfos, fsBidderInfos
 			if bidderInfo.EndpointCompression == "" && fsBidderCfg.EndpointCompression != "" {
 				bidderInfo.EndpointCompression = fsBidderCfg.EndpointCompression
 			}
+			if bidderInfo.OpenRTB == nil && fsBidderCfg.OpenRTB != nil {
+				bidderInfo.OpenRTB = fsBidderCfg.OpenRTB
+			}
+			if bidderInfo.GenericORTB == nil && fsBidderCfg.GenericORTB != nil {
+				bidderInfo.GenericORTB = fsBidderCfg.GenericORTB
+			}
 
 			// validate and try to apply the legacy usersync_url configuration in attempt to provide
 			// an easier upgrade path. be warned, this will break if the bidder adds a second syncer
@@ -515,6 +697,14 @@ func (s *Syncer) Override(original *Syncer) *Syncer {
 		copy.SupportCORS = s.SupportCORS
 	}
 
+	if s.UIDTTLDays != 0 {
+		copy.UIDTTLDays = s.UIDTTLDays
+	}
+
+	if s.Priority != 0 {
+		copy.Priority = s.Priority
+	}
+
 	return &copy
 }
 
@@ -38,6 +38,8 @@ experiment:
   adsCert:
     enabled: true
 endpointCompression: "GZIP"
+openrtb:
+  version: 2.6
 `
 
 func TestLoadBidderInfoFromDisk(t *testing.T) {
@@ -127,6 +129,66 @@ func TestProcessBidderInfo(t *testing.T) {
 			expectedBidderInfos: nil,
 			expectError:         "error parsing config for bidder bidderA.yaml",
 		},
+		{
+			description: "Alias inherits unset info from its core bidder",
+			bidderInfos: map[string][]byte{
+				"bidderA.yaml": []byte(testSimpleYAML),
+				"whitelabel.yaml": []byte(`
+aliasOf: biddera
+endpoint: https://whitelabel.com/bid
+gvlVendorID: 7
+`),
+			},
+			expectedBidderInfos: BidderInfos{
+				"bidderA": BidderInfo{
+					Maintainer: &MaintainerInfo{
+						Email: "some-email@domain.com",
+					},
+					GVLVendorID: 42,
+				},
+				"whitelabel": BidderInfo{
+					AliasOf:  "bidderA",
+					Endpoint: "https://whitelabel.com/bid",
+					Maintainer: &MaintainerInfo{
+						Email: "some-email@domain.com",
+					},
+					GVLVendorID: 7,
+				},
+			},
+		},
+		{
+			description: "Alias of unknown core bidder",
+			bidderInfos: map[string][]byte{
+				"bidderA.yaml":    []byte(testSimpleYAML),
+				"whitelabel.yaml": []byte("aliasOf: unknown"),
+			},
+			expectError: "error parsing config for alias whitelabel: core bidder unknown not found",
+		},
+		{
+			description: "Alias of alias",
+			bidderInfos: map[string][]byte{
+				"bidderA.yaml":    []byte(testSimpleYAML),
+				"whitelabel.yaml": []byte("aliasOf: bidderA"),
+				"other.yaml":      []byte("aliasOf: whitelabel"),
+			},
+			expectError: "error parsing config for alias other: core bidder whitelabel not found",
+		},
+		{
+			description: "Alias named as a core bidder",
+			bidderInfos: map[string][]byte{
+				"bidderA.yaml": []byte(testSimpleYAML),
+				"bidderB.yaml": []byte("aliasOf: bidderA"),
+			},
+			expectError: "error parsing config for alias bidderB: the name is used by a core bidder",
+		},
+		{
+			description: "Alias with reserved name",
+			bidderInfos: map[string][]byte{
+				"bidderA.yaml": []byte(testSimpleYAML),
+				"all.yaml":     []byte("aliasOf: bidderA"),
+			},
+			expectError: "error parsing config for alias all: reserved bidder name",
+		},
 	}
 	for _, test := range testCases {
 		reader := StubInfoReader{test.bidderInfos}
@@ -532,6 +594,118 @@ func TestBidderInfoValidationNegative(t *testing.T) {
 				errors.New("The endpoint: incorrect for bidderB is not a valid URL"),
 			},
 		},
+		{
+			"One bidder unsupported openrtb version",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					OpenRTB: &OpenRTBInfo{
+						Version: "3.0",
+					},
+				},
+			},
+			[]error{
+				errors.New("openrtb.version 3.0 is not supported for adapter: bidderA, supported versions are 2.5 and 2.6"),
+			},
+		},
+		{
+			"One bidder unsupported generic ortb imp params location",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					GenericORTB: &GenericORTBInfo{ImpParamsLocation: "prebid"},
+				},
+			},
+			[]error{
+				errors.New("genericOrtb.impParamsLocation prebid is not supported for adapter: bidderA, supported locations are bidder and ext"),
+			},
+		},
+		{
+			"One bidder unsupported generic ortb bid type",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					GenericORTB: &GenericORTBInfo{BidType: "adm"},
+				},
+			},
+			[]error{
+				errors.New("genericOrtb.bidType adm is not supported for adapter: bidderA, supported types are mtype, ext and imp"),
+			},
+		},
+		{
+			"One bidder generic ortb bid type ext without path",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					GenericORTB: &GenericORTBInfo{BidType: "ext"},
+				},
+			},
+			[]error{
+				errors.New("genericOrtb.bidTypeExtPath is required when genericOrtb.bidType is ext for adapter: bidderA"),
+			},
+		},
+		{
+			"One bidder invalid generic ortb currency",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					GenericORTB: &GenericORTBInfo{Currency: "EURO"},
+				},
+			},
+			[]error{
+				errors.New("genericOrtb.currency EURO is not a valid currency code for adapter: bidderA"),
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -612,6 +786,18 @@ func TestSyncerOverride(t *testing.T) {
 			givenOverride: &Syncer{SupportCORS: &falseValue},
 			expected:      &Syncer{SupportCORS: &falseValue},
 		},
+		{
+			description:   "Override UIDTTLDays",
+			givenOriginal: &Syncer{UIDTTLDays: 14},
+			givenOverride: &Syncer{UIDTTLDays: 30},
+			expected:      &Syncer{UIDTTLDays: 30},
+		},
+		{
+			description:   "Override Priority",
+			givenOriginal: &Syncer{Priority: 1},
+			givenOverride: &Syncer{Priority: 5},
+			expected:      &Syncer{Priority: 5},
+		},
 		{
 			description:   "Override Partial - Other Fields Untouched",
 			givenOriginal: &Syncer{Key: "originalKey", ExternalURL: "originalExternalURL"},
@@ -926,6 +1112,30 @@ func TestApplyBidderInfoConfigOverrides(t *testing.T) {
 			givenConfigBidderInfos: BidderInfos{"a": {EndpointCompression: "LZ77", Syncer: &Syncer{Key: "override"}}},
 			expectedBidderInfos:    BidderInfos{"a": {EndpointCompression: "LZ77", Syncer: &Syncer{Key: "override"}}},
 		},
+		{
+			description:            "Don't override OpenRTB",
+			givenFsBidderInfos:     BidderInfos{"a": {OpenRTB: &OpenRTBInfo{Version: "2.6"}}},
+			givenConfigBidderInfos: BidderInfos{"a": {Syncer: &Syncer{Key: "override"}}},
+			expectedBidderInfos:    BidderInfos{"a": {OpenRTB: &OpenRTBInfo{Version: "2.6"}, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override OpenRTB",
+			givenFsBidderInfos:     BidderInfos{"a": {OpenRTB: &OpenRTBInfo{Version: "2.6"}}},
+			givenConfigBidderInfos: BidderInfos{"a": {OpenRTB: &OpenRTBInfo{Version: "2.5"}, Syncer: &Syncer{Key: "override"}}},
+			expectedBidderInfos:    BidderInfos{"a": {OpenRTB: &OpenRTBInfo{Version: "2.5"}, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Don't override GenericORTB",
+			givenFsBidderInfos:     BidderInfos{"a": {GenericORTB: &GenericORTBInfo{BidType: "imp"}}},
+			givenConfigBidderInfos: BidderInfos{"a": {Syncer: &Syncer{Key: "override"}}},
+			expectedBidderInfos:    BidderInfos{"a": {GenericORTB: &GenericORTBInfo{BidType: "imp"}, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override GenericORTB",
+			givenFsBidderInfos:     BidderInfos{"a": {GenericORTB: &GenericORTBInfo{BidType: "imp"}}},
+			givenConfigBidderInfos: BidderInfos{"a": {GenericORTB: &GenericORTBInfo{BidType: "mtype"}, Syncer: &Syncer{Key: "override"}}},
+			expectedBidderInfos:    BidderInfos{"a": {GenericORTB: &GenericORTBInfo{BidType: "mtype"}, Syncer: &Syncer{Key: "override"}}},
+		},
 	}
 	for _, test := range testCases {
 		bidderInfos, resultErr := applyBidderInfoConfigOverrides(test.givenConfigBidderInfos, test.givenFsBidderInfos, mockNormalizeBidderName)
@@ -990,6 +1200,7 @@ func TestReadFullYamlBidderConfig(t *testing.T) {
 			},
 			Experiment:          BidderInfoExperiment{AdsCert: BidderAdsCert{Enabled: true}},
 			EndpointCompression: "GZIP",
+			OpenRTB:             &OpenRTBInfo{Version: "2.6"},
 		},
 	}
 	assert.Equalf(t, expectedBidderInfo, actualBidderInfo, "Bidder info objects aren't matching")
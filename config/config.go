@@ -60,6 +60,7 @@ type Configuration struct {
 	GDPR                 GDPR              `mapstructure:"gdpr"`
 	CCPA                 CCPA              `mapstructure:"ccpa"`
 	LMT                  LMT               `mapstructure:"lmt"`
+	GPP                  GPP               `mapstructure:"gpp"`
 	CurrencyConverter    CurrencyConverter `mapstructure:"currency_converter"`
 	DefReqConfig         DefReqConfig      `mapstructure:"default_request"`
 
@@ -101,6 +102,165 @@ type Configuration struct {
 	BidderInfos BidderInfos `mapstructure:"adapters"`
 	// Hooks provides a way to specify hook execution plan for specific endpoints and stages
 	Hooks Hooks `mapstructure:"hooks"`
+	// PriceFloors holds the host level price floors configuration
+	PriceFloors PriceFloors `mapstructure:"price_floors"`
+	// BidderHealth configures the circuit breaker which stops sending requests to unhealthy bidders
+	BidderHealth BidderHealth `mapstructure:"bidder_health"`
+	// Geolocation configures the enrichment of device.geo from the device ip
+	Geolocation Geolocation `mapstructure:"geolocation"`
+	// DeviceDetection configures the enrichment of the device fields from the user agent and client hints
+	DeviceDetection DeviceDetection `mapstructure:"device_detection"`
+	// UIDStore configures the server side storage of the user syncs, as an alternative to the uids cookie
+	UIDStore UIDStore `mapstructure:"uid_store"`
+}
+
+// PriceFloors holds the host level configuration for the price floors feature
+type PriceFloors struct {
+	Enabled bool              `mapstructure:"enabled"`
+	Fetcher PriceFloorFetcher `mapstructure:"fetcher"`
+}
+
+// PriceFloorFetcher configures the background worker which reloads account floor data
+type PriceFloorFetcher struct {
+	CheckIntervalSeconds int `mapstructure:"check_interval_seconds"`
+	// FileDirectory is the only directory accounts may load floor data from with file:// URLs. File URLs are
+	// rejected when it is empty.
+	FileDirectory string `mapstructure:"file_directory"`
+}
+
+func (cfg *PriceFloors) validate(errs []error) []error {
+	if cfg.Fetcher.CheckIntervalSeconds < 0 {
+		errs = append(errs, fmt.Errorf("price_floors.fetcher.check_interval_seconds must be >= 0. Got %d", cfg.Fetcher.CheckIntervalSeconds))
+	}
+	return errs
+}
+
+// BidderHealth configures the tracking of bidder error and timeout rates. When either rate exceeds its threshold
+// within a window, the bidder only receives sample_percentage of the requests, or none when it is 0, for
+// open_seconds. Then probe_requests are sent to the bidder and it recovers when all of them succeed.
+type BidderHealth struct {
+	Enabled              bool    `mapstructure:"enabled"`
+	WindowSeconds        int     `mapstructure:"window_seconds"`
+	MinRequests          int     `mapstructure:"min_requests"`
+	ErrorRateThreshold   float64 `mapstructure:"error_rate_threshold"`
+	TimeoutRateThreshold float64 `mapstructure:"timeout_rate_threshold"`
+	SamplePercentage     int     `mapstructure:"sample_percentage"`
+	OpenSeconds          int     `mapstructure:"open_seconds"`
+	ProbeRequests        int     `mapstructure:"probe_requests"`
+}
+
+func (cfg *BidderHealth) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.WindowSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("bidder_health.window_seconds must be > 0. Got %d", cfg.WindowSeconds))
+	}
+	if cfg.MinRequests < 0 {
+		errs = append(errs, fmt.Errorf("bidder_health.min_requests must be >= 0. Got %d", cfg.MinRequests))
+	}
+	if cfg.ErrorRateThreshold <= 0 || cfg.ErrorRateThreshold > 1 {
+		errs = append(errs, fmt.Errorf("bidder_health.error_rate_threshold must be in the range (0, 1]. Got %v", cfg.ErrorRateThreshold))
+	}
+	if cfg.TimeoutRateThreshold <= 0 || cfg.TimeoutRateThreshold > 1 {
+		errs = append(errs, fmt.Errorf("bidder_health.timeout_rate_threshold must be in the range (0, 1]. Got %v", cfg.TimeoutRateThreshold))
+	}
+	if cfg.SamplePercentage < 0 || cfg.SamplePercentage > 100 {
+		errs = append(errs, fmt.Errorf("bidder_health.sample_percentage must be in the range [0, 100]. Got %d", cfg.SamplePercentage))
+	}
+	if cfg.OpenSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("bidder_health.open_seconds must be > 0. Got %d", cfg.OpenSeconds))
+	}
+	if cfg.ProbeRequests <= 0 {
+		errs = append(errs, fmt.Errorf("bidder_health.probe_requests must be > 0. Got %d", cfg.ProbeRequests))
+	}
+	return errs
+}
+
+// Geolocation configures the lookup of the device ip in a local MaxMind DB file, such as GeoLite2 City, which is
+// reloaded every refresh_interval_seconds, or only at startup when it is 0.
+type Geolocation struct {
+	Enabled                bool   `mapstructure:"enabled"`
+	DatabasePath           string `mapstructure:"database_path"`
+	RefreshIntervalSeconds int    `mapstructure:"refresh_interval_seconds"`
+}
+
+func (cfg *Geolocation) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.DatabasePath == "" {
+		errs = append(errs, errors.New("geolocation.database_path must be set when geolocation is enabled"))
+	}
+	if cfg.RefreshIntervalSeconds < 0 {
+		errs = append(errs, fmt.Errorf("geolocation.refresh_interval_seconds must be >= 0. Got %d", cfg.RefreshIntervalSeconds))
+	}
+	return errs
+}
+
+// DeviceDetection configures the detection of the device type, os, make and model from the user agent with the
+// rules of rules_file. The results are cached for up to cache_size user agents, or not at all when it is 0.
+type DeviceDetection struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	RulesFile string `mapstructure:"rules_file"`
+	CacheSize int    `mapstructure:"cache_size"`
+}
+
+func (cfg *DeviceDetection) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.RulesFile == "" {
+		errs = append(errs, errors.New("device_detection.rules_file must be set when device detection is enabled"))
+	}
+	if cfg.CacheSize < 0 {
+		errs = append(errs, fmt.Errorf("device_detection.cache_size must be >= 0. Got %d", cfg.CacheSize))
+	}
+	return errs
+}
+
+// UIDStore configures the storage of the user syncs on the server, keyed by the value of the host cookie. The
+// "memory" store is lost on restart, the "file" store is kept in the file at path.
+type UIDStore struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Type    string `mapstructure:"type"`
+	Path    string `mapstructure:"path"`
+	// TTLDays is how long the user syncs of an id are kept after they were last stored
+	TTLDays int `mapstructure:"ttl_days"`
+	// MaxEntries bounds the number of ids stored. The ids stored the longest ago are evicted to make room for new ones.
+	MaxEntries int `mapstructure:"max_entries"`
+	// CleanupIntervalSeconds is how often the expired user syncs are removed. The file store is compacted then too.
+	CleanupIntervalSeconds int `mapstructure:"cleanup_interval_seconds"`
+}
+
+const (
+	UIDStoreTypeMemory = "memory"
+	UIDStoreTypeFile   = "file"
+)
+
+func (cfg *UIDStore) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	switch cfg.Type {
+	case UIDStoreTypeMemory:
+	case UIDStoreTypeFile:
+		if cfg.Path == "" {
+			errs = append(errs, errors.New("uid_store.path must be set for the file store"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("uid_store.type must be one of [%s, %s]. Got %s", UIDStoreTypeMemory, UIDStoreTypeFile, cfg.Type))
+	}
+	if cfg.TTLDays <= 0 {
+		errs = append(errs, fmt.Errorf("uid_store.ttl_days must be > 0. Got %d", cfg.TTLDays))
+	}
+	if cfg.MaxEntries <= 0 {
+		errs = append(errs, fmt.Errorf("uid_store.max_entries must be > 0. Got %d", cfg.MaxEntries))
+	}
+	if cfg.CleanupIntervalSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("uid_store.cleanup_interval_seconds must be > 0. Got %d", cfg.CleanupIntervalSeconds))
+	}
+	return errs
 }
 
 const MIN_COOKIE_SIZE_BYTES = 500
@@ -125,9 +285,38 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 		errs = append(errs, fmt.Errorf("cfg.max_request_size must be >= 0. Got %d", cfg.MaxRequestSize))
 	}
 	errs = cfg.GDPR.validate(v, errs)
+	errs = cfg.HostCookie.validate(errs)
 	errs = cfg.CurrencyConverter.validate(errs)
 	errs = cfg.Debug.validate(errs)
 	errs = cfg.ExtCacheURL.validate(errs)
+	errs = cfg.CacheURL.Embedded.validate(errs)
+	errs = cfg.PriceFloors.validate(errs)
+	errs = cfg.BidderHealth.validate(errs)
+	errs = cfg.Geolocation.validate(errs)
+	errs = cfg.DeviceDetection.validate(errs)
+	errs = cfg.UIDStore.validate(errs)
+	errs = cfg.AccountDefaults.PriceFloors.validate(errs)
+	if err := cfg.AccountDefaults.Activities.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("account_defaults.%v", err))
+	}
+	if err := cfg.AccountDefaults.Auction.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("account_defaults.%v", err))
+	}
+	if err := cfg.AccountDefaults.BidValidations.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("account_defaults.%v", err))
+	}
+	if err := cfg.AccountDefaults.Blocking.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("account_defaults.%v", err))
+	}
+	if err := cfg.AccountDefaults.Targeting.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("account_defaults.%v", err))
+	}
+	if err := cfg.AccountDefaults.Cookie.Validate(cfg.HostCookie.Domain); err != nil {
+		errs = append(errs, fmt.Errorf("account_defaults.%v", err))
+	}
+	if err := cfg.AccountDefaults.CookieSync.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("account_defaults.%v", err))
+	}
 	if cfg.AccountDefaults.Disabled {
 		glog.Warning(`With account_defaults.disabled=true, host-defined accounts must exist and have "disabled":false. All other requests will be rejected.`)
 	}
@@ -211,6 +400,7 @@ type Privacy struct {
 	CCPA CCPA
 	GDPR GDPR
 	LMT  LMT
+	GPP  GPP
 }
 
 type GDPR struct {
@@ -424,6 +614,11 @@ type LMT struct {
 	Enforce bool `mapstructure:"enforce"`
 }
 
+// GPP configures the enforcement of the US National and US state sections of GPP strings.
+type GPP struct {
+	Enforce bool `mapstructure:"enforce"`
+}
+
 type Analytics struct {
 	File     FileLogs `mapstructure:"file"`
 	Pubstack Pubstack `mapstructure:"pubstack"`
@@ -433,12 +628,71 @@ type CurrencyConverter struct {
 	FetchURL             string `mapstructure:"fetch_url"`
 	FetchIntervalSeconds int    `mapstructure:"fetch_interval_seconds"`
 	StaleRatesSeconds    int    `mapstructure:"stale_rates_seconds"`
+	// FetchTimeoutMs bounds the time taken to fetch the rates from a url
+	FetchTimeoutMs int `mapstructure:"fetch_timeout_ms"`
+	// Sources are the rates sources in order of preference. The rates of the first source whose rates are not
+	// stale are used, falling back to the constant rates when all of them are. When empty, the rates are
+	// fetched from FetchURL.
+	Sources []CurrencyRatesSource `mapstructure:"sources"`
+}
+
+// Currency rates source types
+const (
+	CurrencyRatesSourceHTTP   = "http"
+	CurrencyRatesSourceFile   = "file"
+	CurrencyRatesSourceStatic = "static"
+)
+
+// CurrencyRatesSource loads currency rates from a url, a local file in the format of the url response, or the
+// rates set in the host config. The name identifies the source in metrics and defaults to the type.
+type CurrencyRatesSource struct {
+	Name  string                        `mapstructure:"name"`
+	Type  string                        `mapstructure:"type"`
+	URL   string                        `mapstructure:"url"`
+	Path  string                        `mapstructure:"path"`
+	Rates map[string]map[string]float64 `mapstructure:"rates"`
+}
+
+// SourceName returns the name of the source, which defaults to its type
+func (s *CurrencyRatesSource) SourceName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Type
 }
 
 func (cfg *CurrencyConverter) validate(errs []error) []error {
 	if cfg.FetchIntervalSeconds < 0 {
 		errs = append(errs, fmt.Errorf("currency_converter.fetch_interval_seconds must be in the range [0, %d]. Got %d", 0xffff, cfg.FetchIntervalSeconds))
 	}
+	if cfg.FetchTimeoutMs <= 0 {
+		errs = append(errs, fmt.Errorf("currency_converter.fetch_timeout_ms must be > 0. Got %d", cfg.FetchTimeoutMs))
+	}
+
+	names := make(map[string]struct{}, len(cfg.Sources))
+	for i, source := range cfg.Sources {
+		switch source.Type {
+		case CurrencyRatesSourceHTTP:
+			if source.URL == "" {
+				errs = append(errs, fmt.Errorf("currency_converter.sources[%d].url must be set for http sources", i))
+			}
+		case CurrencyRatesSourceFile:
+			if source.Path == "" {
+				errs = append(errs, fmt.Errorf("currency_converter.sources[%d].path must be set for file sources", i))
+			}
+		case CurrencyRatesSourceStatic:
+			if len(source.Rates) == 0 {
+				errs = append(errs, fmt.Errorf("currency_converter.sources[%d].rates must be set for static sources", i))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("currency_converter.sources[%d].type must be one of [%s, %s, %s]. Got %s", i, CurrencyRatesSourceHTTP, CurrencyRatesSourceFile, CurrencyRatesSourceStatic, source.Type))
+		}
+
+		if _, ok := names[source.SourceName()]; ok {
+			errs = append(errs, fmt.Errorf("currency_converter.sources[%d].name must be unique. Got %s", i, source.SourceName()))
+		}
+		names[source.SourceName()] = struct{}{}
+	}
 	return errs
 }
 
@@ -478,9 +732,52 @@ type HostCookie struct {
 	OptOutURL          string `mapstructure:"opt_out_url"`
 	OptInURL           string `mapstructure:"opt_in_url"`
 	MaxCookieSizeBytes int    `mapstructure:"max_cookie_size_bytes"`
-	OptOutCookie       Cookie `mapstructure:"optout_cookie"`
+	// MaxCookies is the number of cookies the uids are split across when they don't fit in MaxCookieSizeBytes:
+	// uids, uids2, uids3 and so on. The UIDs which expire the soonest are dropped when they don't fit in all of them.
+	// 0 behaves like 1.
+	MaxCookies   int    `mapstructure:"max_cookies"`
+	OptOutCookie Cookie `mapstructure:"optout_cookie"`
 	// Cookie timeout in days
 	TTL int64 `mapstructure:"ttl_days"`
+	// SameSite is the SameSite attribute of the cookies set by Prebid Server. "auto" sets SameSite=None and
+	// Secure only for the browsers known to handle them.
+	SameSite string `mapstructure:"samesite"`
+	// Partitioned sets the CHIPS Partitioned attribute, which keeps the cookies in browsers which block
+	// third-party cookies. It implies SameSite=None and Secure.
+	Partitioned bool `mapstructure:"partitioned"`
+	// CompactFormat writes the uids cookie in a compact format which fits more UIDs. Older versions of Prebid
+	// Server can't read it, so it should only be enabled once all the servers sharing the cookie read it.
+	CompactFormat bool `mapstructure:"compact_format"`
+	// RefreshHostCookie sets the host cookie of the request again on the /setuid responses, so that it gets the
+	// attributes and the ttl of the uids cookie. It should only be enabled when Prebid Server owns the host cookie.
+	RefreshHostCookie bool `mapstructure:"refresh_host_cookie"`
+}
+
+// SameSite modes of the cookies set by Prebid Server. An empty mode behaves like auto.
+const (
+	SameSiteAuto   = "auto"
+	SameSiteNone   = "none"
+	SameSiteLax    = "lax"
+	SameSiteStrict = "strict"
+)
+
+// ValidateSameSite returns an error if the SameSite mode is not supported
+func ValidateSameSite(sameSite string) error {
+	switch sameSite {
+	case "", SameSiteAuto, SameSiteNone, SameSiteLax, SameSiteStrict:
+		return nil
+	}
+	return fmt.Errorf("samesite must be one of [%s, %s, %s, %s]. Got %s", SameSiteAuto, SameSiteNone, SameSiteLax, SameSiteStrict, sameSite)
+}
+
+func (cfg *HostCookie) validate(errs []error) []error {
+	if cfg.MaxCookies < 0 {
+		errs = append(errs, fmt.Errorf("host_cookie.max_cookies must be >= 0. Got %d", cfg.MaxCookies))
+	}
+	if err := ValidateSameSite(cfg.SameSite); err != nil {
+		errs = append(errs, fmt.Errorf("host_cookie.%v", err))
+	}
+	return errs
 }
 
 func (cfg *HostCookie) TTLDuration() time.Duration {
@@ -577,6 +874,44 @@ type Cache struct {
 	ExpectedTimeMillis int `mapstructure:"expected_millis"`
 
 	DefaultTTLs DefaultTTLs `mapstructure:"default_ttl_seconds"`
+
+	Embedded EmbeddedCache `mapstructure:"embedded"`
+}
+
+// EmbeddedCache configures the in-process cache which replaces Prebid Cache when enabled. Cached values are
+// served from the /cache endpoint of Prebid Server.
+type EmbeddedCache struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MaxSizeBytes bounds the total size of the cached values. The oldest values are evicted to make room for new ones.
+	MaxSizeBytes int64 `mapstructure:"max_size_bytes"`
+	// DefaultTTLSeconds is used for values stored without a TTL
+	DefaultTTLSeconds int64 `mapstructure:"default_ttl_seconds"`
+	// MaxTTLSeconds caps the TTL requested for a value
+	MaxTTLSeconds int64 `mapstructure:"max_ttl_seconds"`
+	// MaxValueSizeBytes bounds the size of a single value stored through the /cache endpoint
+	MaxValueSizeBytes int64 `mapstructure:"max_value_size_bytes"`
+	// AllowSettingKeys lets /cache requests choose the key of a value instead of getting a random uuid.
+	// Anyone who knows a key could overwrite the value stored under it, so this is off by default.
+	AllowSettingKeys bool `mapstructure:"allow_setting_keys"`
+}
+
+func (cfg *EmbeddedCache) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.MaxSizeBytes <= 0 {
+		errs = append(errs, fmt.Errorf("cache.embedded.max_size_bytes must be > 0. Got %d", cfg.MaxSizeBytes))
+	}
+	if cfg.DefaultTTLSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("cache.embedded.default_ttl_seconds must be > 0. Got %d", cfg.DefaultTTLSeconds))
+	}
+	if cfg.MaxTTLSeconds < cfg.DefaultTTLSeconds {
+		errs = append(errs, fmt.Errorf("cache.embedded.max_ttl_seconds must be >= cache.embedded.default_ttl_seconds. Got %d", cfg.MaxTTLSeconds))
+	}
+	if cfg.MaxValueSizeBytes <= 0 {
+		errs = append(errs, fmt.Errorf("cache.embedded.max_value_size_bytes must be > 0. Got %d", cfg.MaxValueSizeBytes))
+	}
+	return errs
 }
 
 // Default TTLs to use to cache bids for different types of imps.
@@ -808,6 +1143,12 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("cache.default_ttl_seconds.video", 0)
 	v.SetDefault("cache.default_ttl_seconds.native", 0)
 	v.SetDefault("cache.default_ttl_seconds.audio", 0)
+	v.SetDefault("cache.embedded.enabled", false)
+	v.SetDefault("cache.embedded.max_size_bytes", 104857600)
+	v.SetDefault("cache.embedded.default_ttl_seconds", 300)
+	v.SetDefault("cache.embedded.max_ttl_seconds", 3600)
+	v.SetDefault("cache.embedded.max_value_size_bytes", 10240)
+	v.SetDefault("cache.embedded.allow_setting_keys", false)
 	v.SetDefault("external_cache.scheme", "")
 	v.SetDefault("external_cache.host", "")
 	v.SetDefault("external_cache.path", "")
@@ -821,6 +1162,11 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("host_cookie.value", "")
 	v.SetDefault("host_cookie.ttl_days", 90)
 	v.SetDefault("host_cookie.max_cookie_size_bytes", 0)
+	v.SetDefault("host_cookie.max_cookies", 1)
+	v.SetDefault("host_cookie.compact_format", false)
+	v.SetDefault("host_cookie.refresh_host_cookie", false)
+	v.SetDefault("host_cookie.samesite", SameSiteAuto)
+	v.SetDefault("host_cookie.partitioned", false)
 	v.SetDefault("host_schain_node", nil)
 	v.SetDefault("http_client.max_connections_per_host", 0) // unlimited
 	v.SetDefault("http_client.max_idle_connections", 400)
@@ -955,9 +1301,11 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 		"SVK", "SVN", "ESP", "SWE", "GBR"})
 	v.SetDefault("ccpa.enforce", false)
 	v.SetDefault("lmt.enforce", true)
+	v.SetDefault("gpp.enforce", true)
 	v.SetDefault("currency_converter.fetch_url", "https://cdn.jsdelivr.net/gh/prebid/currency-file@1/latest.json")
 	v.SetDefault("currency_converter.fetch_interval_seconds", 1800) // fetch currency rates every 30 minutes
 	v.SetDefault("currency_converter.stale_rates_seconds", 0)
+	v.SetDefault("currency_converter.fetch_timeout_ms", 10000)
 	v.SetDefault("default_request.type", "")
 	v.SetDefault("default_request.file.name", "")
 	v.SetDefault("default_request.alias_info", false)
@@ -966,6 +1314,27 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("account_required", false)
 	v.SetDefault("account_defaults.disabled", false)
 	v.SetDefault("account_defaults.debug_allow", true)
+	v.SetDefault("account_defaults.price_floors.enabled", true)
+	v.SetDefault("account_defaults.price_floors.enforce_floors_rate", 100)
+	v.SetDefault("account_defaults.price_floors.enforce_deal_floors", false)
+	v.SetDefault("account_defaults.price_floors.use_dynamic_data", true)
+	v.SetDefault("account_defaults.price_floors.max_rules", 100)
+	v.SetDefault("account_defaults.price_floors.max_schema_dims", 3)
+	v.SetDefault("account_defaults.price_floors.fetch.enabled", false)
+	v.SetDefault("account_defaults.price_floors.fetch.url", "")
+	v.SetDefault("account_defaults.price_floors.fetch.timeout_ms", 3000)
+	v.SetDefault("account_defaults.price_floors.fetch.max_file_size_kb", 100)
+	v.SetDefault("account_defaults.price_floors.fetch.max_rules", 1000)
+	v.SetDefault("account_defaults.price_floors.fetch.max_age_sec", 86400)
+	v.SetDefault("account_defaults.price_floors.fetch.period_sec", 3600)
+	v.SetDefault("account_defaults.auction.type", openrtb_ext.AuctionTypeFirstPrice)
+	v.SetDefault("account_defaults.auction.increment", 0.01)
+	v.SetDefault("account_defaults.auction.reserve", openrtb_ext.ReserveTypeNone)
+	v.SetDefault("account_defaults.bidvalidations.banner_creative_size", ValidationSkip)
+	v.SetDefault("account_defaults.bidvalidations.secure_markup", ValidationSkip)
+	v.SetDefault("account_defaults.bidvalidations.max_creative_width", 0)
+	v.SetDefault("account_defaults.bidvalidations.max_creative_height", 0)
+	v.SetDefault("account_defaults.blocking.mode", ValidationSkip)
 	v.SetDefault("certificates_file", "")
 	v.SetDefault("auto_gen_source_tid", true)
 	v.SetDefault("generate_bid_id", false)
@@ -1060,6 +1429,29 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("experiment.adscert.remote.signing_timeout_ms", 5)
 
 	v.SetDefault("hooks.enabled", false)
+	v.SetDefault("price_floors.enabled", false)
+	v.SetDefault("price_floors.fetcher.check_interval_seconds", 60)
+	v.SetDefault("price_floors.fetcher.file_directory", "")
+	v.SetDefault("bidder_health.enabled", false)
+	v.SetDefault("bidder_health.window_seconds", 60)
+	v.SetDefault("bidder_health.min_requests", 20)
+	v.SetDefault("bidder_health.error_rate_threshold", 0.5)
+	v.SetDefault("bidder_health.timeout_rate_threshold", 0.5)
+	v.SetDefault("bidder_health.sample_percentage", 0)
+	v.SetDefault("bidder_health.open_seconds", 30)
+	v.SetDefault("bidder_health.probe_requests", 5)
+	v.SetDefault("geolocation.enabled", false)
+	v.SetDefault("geolocation.database_path", "")
+	v.SetDefault("geolocation.refresh_interval_seconds", 86400)
+	v.SetDefault("device_detection.enabled", false)
+	v.SetDefault("device_detection.rules_file", "./static/device-detection/rules.json")
+	v.SetDefault("device_detection.cache_size", 10000)
+	v.SetDefault("uid_store.enabled", false)
+	v.SetDefault("uid_store.type", UIDStoreTypeMemory)
+	v.SetDefault("uid_store.path", "")
+	v.SetDefault("uid_store.ttl_days", 90)
+	v.SetDefault("uid_store.max_entries", 1000000)
+	v.SetDefault("uid_store.cleanup_interval_seconds", 3600)
 
 	for bidderName := range bidderInfos {
 		setBidderDefaults(v, strings.ToLower(bidderName))
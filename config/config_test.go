@@ -151,7 +151,13 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "max_request_size", int(cfg.MaxRequestSize), 1024*256)
 	cmpInts(t, "host_cookie.ttl_days", int(cfg.HostCookie.TTL), 90)
 	cmpInts(t, "host_cookie.max_cookie_size_bytes", cfg.HostCookie.MaxCookieSizeBytes, 0)
+	cmpInts(t, "host_cookie.max_cookies", cfg.HostCookie.MaxCookies, 1)
+	cmpBools(t, "host_cookie.compact_format", cfg.HostCookie.CompactFormat, false)
+	cmpBools(t, "host_cookie.refresh_host_cookie", cfg.HostCookie.RefreshHostCookie, false)
+	cmpStrings(t, "host_cookie.samesite", cfg.HostCookie.SameSite, "auto")
+	cmpBools(t, "host_cookie.partitioned", cfg.HostCookie.Partitioned, false)
 	cmpInts(t, "currency_converter.fetch_interval_seconds", cfg.CurrencyConverter.FetchIntervalSeconds, 1800)
+	cmpInts(t, "currency_converter.fetch_timeout_ms", cfg.CurrencyConverter.FetchTimeoutMs, 10000)
 	cmpStrings(t, "currency_converter.fetch_url", cfg.CurrencyConverter.FetchURL, "https://cdn.jsdelivr.net/gh/prebid/currency-file@1/latest.json")
 	cmpBools(t, "account_required", cfg.AccountRequired, false)
 	cmpInts(t, "metrics.influxdb.collection_rate_seconds", cfg.Metrics.Influxdb.MetricSendInterval, 20)
@@ -176,6 +182,33 @@ func TestDefaults(t *testing.T) {
 	cmpStrings(t, "datacenter", cfg.DataCenter, "")
 	cmpBools(t, "hooks.enabled", cfg.Hooks.Enabled, false)
 	cmpBools(t, "account_modules_metrics", cfg.Metrics.Disabled.AccountModulesMetrics, false)
+	cmpStrings(t, "account_defaults.auction.type", cfg.AccountDefaults.Auction.Type, "first_price")
+	cmpStrings(t, "account_defaults.auction.reserve", cfg.AccountDefaults.Auction.Reserve, "none")
+	cmpStrings(t, "account_defaults.bidvalidations.banner_creative_size", cfg.AccountDefaults.BidValidations.BannerCreativeSize, "skip")
+	cmpStrings(t, "account_defaults.bidvalidations.secure_markup", cfg.AccountDefaults.BidValidations.SecureMarkup, "skip")
+	cmpBools(t, "bidder_health.enabled", cfg.BidderHealth.Enabled, false)
+	cmpInts(t, "bidder_health.window_seconds", cfg.BidderHealth.WindowSeconds, 60)
+	cmpInts(t, "bidder_health.min_requests", cfg.BidderHealth.MinRequests, 20)
+	cmpInts(t, "bidder_health.open_seconds", cfg.BidderHealth.OpenSeconds, 30)
+	cmpInts(t, "bidder_health.probe_requests", cfg.BidderHealth.ProbeRequests, 5)
+	cmpBools(t, "cache.embedded.enabled", cfg.CacheURL.Embedded.Enabled, false)
+	cmpInts(t, "cache.embedded.max_size_bytes", int(cfg.CacheURL.Embedded.MaxSizeBytes), 104857600)
+	cmpInts(t, "cache.embedded.default_ttl_seconds", int(cfg.CacheURL.Embedded.DefaultTTLSeconds), 300)
+	cmpInts(t, "cache.embedded.max_ttl_seconds", int(cfg.CacheURL.Embedded.MaxTTLSeconds), 3600)
+	cmpInts(t, "cache.embedded.max_value_size_bytes", int(cfg.CacheURL.Embedded.MaxValueSizeBytes), 10240)
+	cmpBools(t, "cache.embedded.allow_setting_keys", cfg.CacheURL.Embedded.AllowSettingKeys, false)
+	cmpBools(t, "geolocation.enabled", cfg.Geolocation.Enabled, false)
+	cmpStrings(t, "geolocation.database_path", cfg.Geolocation.DatabasePath, "")
+	cmpInts(t, "geolocation.refresh_interval_seconds", cfg.Geolocation.RefreshIntervalSeconds, 86400)
+	cmpBools(t, "device_detection.enabled", cfg.DeviceDetection.Enabled, false)
+	cmpStrings(t, "device_detection.rules_file", cfg.DeviceDetection.RulesFile, "./static/device-detection/rules.json")
+	cmpInts(t, "device_detection.cache_size", cfg.DeviceDetection.CacheSize, 10000)
+	cmpBools(t, "uid_store.enabled", cfg.UIDStore.Enabled, false)
+	cmpStrings(t, "uid_store.type", cfg.UIDStore.Type, "memory")
+	cmpStrings(t, "uid_store.path", cfg.UIDStore.Path, "")
+	cmpInts(t, "uid_store.ttl_days", cfg.UIDStore.TTLDays, 90)
+	cmpInts(t, "uid_store.max_entries", cfg.UIDStore.MaxEntries, 1000000)
+	cmpInts(t, "uid_store.cleanup_interval_seconds", cfg.UIDStore.CleanupIntervalSeconds, 3600)
 
 	//Assert purpose VendorExceptionMap hash tables were built correctly
 	expectedTCF2 := TCF2{
@@ -342,6 +375,8 @@ ccpa:
   enforce: true
 lmt:
   enforce: true
+gpp:
+  enforce: false
 host_cookie:
   cookie_name: userid
   family: prebid
@@ -349,6 +384,9 @@ host_cookie:
   opt_out_url: http://prebid.org/optout
   opt_in_url: http://prebid.org/optin
   max_cookie_size_bytes: 32768
+  max_cookies: 3
+  samesite: none
+  partitioned: true
 external_url: http://prebid-server.prebid.org/
 host: prebid-server.prebid.org
 port: 1234
@@ -379,6 +417,16 @@ http_client_cache:
 currency_converter:
   fetch_url: https://currency.prebid.org
   fetch_interval_seconds: 1800
+  sources:
+    - type: http
+      url: https://currency.prebid.org
+    - name: backup
+      type: file
+      path: /etc/pbs/rates.json
+    - type: static
+      rates:
+        USD:
+          EUR: 0.9
 recaptcha_secret: asdfasdfasdfasdf
 metrics:
   influxdb:
@@ -469,6 +517,9 @@ func TestFullConfig(t *testing.T) {
 	cmpStrings(t, "cookie family", cfg.HostCookie.Family, "prebid")
 	cmpStrings(t, "opt out", cfg.HostCookie.OptOutURL, "http://prebid.org/optout")
 	cmpStrings(t, "opt in", cfg.HostCookie.OptInURL, "http://prebid.org/optin")
+	cmpInts(t, "host_cookie.max_cookies", cfg.HostCookie.MaxCookies, 3)
+	cmpStrings(t, "host_cookie.samesite", cfg.HostCookie.SameSite, "none")
+	cmpBools(t, "host_cookie.partitioned", cfg.HostCookie.Partitioned, true)
 	cmpStrings(t, "external url", cfg.ExternalURL, "http://prebid-server.prebid.org/")
 	cmpStrings(t, "host", cfg.Host, "prebid-server.prebid.org")
 	cmpInts(t, "port", cfg.Port, 1234)
@@ -508,6 +559,7 @@ func TestFullConfig(t *testing.T) {
 
 	cmpBools(t, "ccpa.enforce", cfg.CCPA.Enforce, true)
 	cmpBools(t, "lmt.enforce", cfg.LMT.Enforce, true)
+	cmpBools(t, "gpp.enforce", cfg.GPP.Enforce, false)
 
 	//Assert the NonStandardPublishers was correctly unmarshalled
 	cmpStrings(t, "blacklisted_apps", cfg.BlacklistedApps[0], "spamAppID")
@@ -637,6 +689,11 @@ func TestFullConfig(t *testing.T) {
 
 	cmpStrings(t, "currency_converter.fetch_url", cfg.CurrencyConverter.FetchURL, "https://currency.prebid.org")
 	cmpInts(t, "currency_converter.fetch_interval_seconds", cfg.CurrencyConverter.FetchIntervalSeconds, 1800)
+	assert.Equal(t, []CurrencyRatesSource{
+		{Type: "http", URL: "https://currency.prebid.org"},
+		{Name: "backup", Type: "file", Path: "/etc/pbs/rates.json"},
+		{Type: "static", Rates: map[string]map[string]float64{"USD": {"EUR": 0.9}}},
+	}, cfg.CurrencyConverter.Sources, "currency_converter.sources")
 	cmpStrings(t, "recaptcha_secret", cfg.RecaptchaSecret, "asdfasdfasdfasdf")
 	cmpStrings(t, "metrics.influxdb.host", cfg.Metrics.Influxdb.Host, "upstream:8232")
 	cmpStrings(t, "metrics.influxdb.database", cfg.Metrics.Influxdb.Database, "metricsdb")
@@ -707,6 +764,7 @@ func TestValidateConfig(t *testing.T) {
 			Files:         FileFetcherConfig{Enabled: true},
 			InMemoryCache: InMemoryCache{Type: "none"},
 		},
+		CurrencyConverter: CurrencyConverter{FetchTimeoutMs: 10000},
 	}
 
 	v := viper.New()
@@ -2688,6 +2746,53 @@ func TestOverflowedCurrencyConverterFetchInterval(t *testing.T) {
 	assert.NotNil(t, err, "cfg.currency_converter.fetch_interval_seconds prevent values over %d, but it doesn't", 0xffff)
 }
 
+func TestValidateCurrencyConverterFetchTimeout(t *testing.T) {
+	cfg := CurrencyConverter{FetchTimeoutMs: 0}
+	errs := cfg.validate(nil)
+	assert.Equal(t, []error{errors.New("currency_converter.fetch_timeout_ms must be > 0. Got 0")}, errs)
+}
+
+func TestValidateCurrencyConverterSources(t *testing.T) {
+	testCases := []struct {
+		description  string
+		sources      []CurrencyRatesSource
+		expectedErrs []error
+	}{
+		{
+			description: "Valid sources",
+			sources: []CurrencyRatesSource{
+				{Type: CurrencyRatesSourceHTTP, URL: "https://currency.prebid.org"},
+				{Name: "backup", Type: CurrencyRatesSourceHTTP, URL: "https://backup.currency.prebid.org"},
+				{Type: CurrencyRatesSourceFile, Path: "rates.json"},
+				{Type: CurrencyRatesSourceStatic, Rates: map[string]map[string]float64{"USD": {"EUR": 0.9}}},
+			},
+		},
+		{
+			description: "Invalid sources",
+			sources: []CurrencyRatesSource{
+				{Type: CurrencyRatesSourceHTTP},
+				{Type: CurrencyRatesSourceFile},
+				{Type: CurrencyRatesSourceStatic},
+				{Type: "ftp"},
+				{Type: CurrencyRatesSourceStatic, Rates: map[string]map[string]float64{"USD": {"EUR": 0.9}}},
+			},
+			expectedErrs: []error{
+				errors.New("currency_converter.sources[0].url must be set for http sources"),
+				errors.New("currency_converter.sources[1].path must be set for file sources"),
+				errors.New("currency_converter.sources[2].rates must be set for static sources"),
+				errors.New("currency_converter.sources[3].type must be one of [http, file, static]. Got ftp"),
+				errors.New("currency_converter.sources[4].name must be unique. Got static"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		cfg := CurrencyConverter{FetchTimeoutMs: 10000, Sources: test.sources}
+		errs := cfg.validate(nil)
+		assert.Equal(t, test.expectedErrs, errs, test.description)
+	}
+}
+
 func TestLimitTimeout(t *testing.T) {
 	doTimeoutTest(t, 10, 15, 10, 0)
 	doTimeoutTest(t, 10, 0, 10, 0)
@@ -2767,6 +2872,196 @@ func TestValidateDebug(t *testing.T) {
 	assert.NotNil(t, err, "cfg.debug.timeout_notification.sampling_rate should not be allowed to be greater than 1.0, but it was allowed")
 }
 
+func TestValidateBidderHealth(t *testing.T) {
+	testCases := []struct {
+		description  string
+		bidderHealth BidderHealth
+		expectedErrs []error
+	}{
+		{
+			description:  "Disabled with invalid values",
+			bidderHealth: BidderHealth{Enabled: false, WindowSeconds: -1},
+		},
+		{
+			description: "Enabled with valid values",
+			bidderHealth: BidderHealth{
+				Enabled:              true,
+				WindowSeconds:        60,
+				MinRequests:          20,
+				ErrorRateThreshold:   0.5,
+				TimeoutRateThreshold: 1,
+				SamplePercentage:     10,
+				OpenSeconds:          30,
+				ProbeRequests:        5,
+			},
+		},
+		{
+			description: "Enabled with invalid values",
+			bidderHealth: BidderHealth{
+				Enabled:              true,
+				WindowSeconds:        0,
+				MinRequests:          -1,
+				ErrorRateThreshold:   0,
+				TimeoutRateThreshold: 1.5,
+				SamplePercentage:     101,
+				OpenSeconds:          0,
+				ProbeRequests:        0,
+			},
+			expectedErrs: []error{
+				errors.New("bidder_health.window_seconds must be > 0. Got 0"),
+				errors.New("bidder_health.min_requests must be >= 0. Got -1"),
+				errors.New("bidder_health.error_rate_threshold must be in the range (0, 1]. Got 0"),
+				errors.New("bidder_health.timeout_rate_threshold must be in the range (0, 1]. Got 1.5"),
+				errors.New("bidder_health.sample_percentage must be in the range [0, 100]. Got 101"),
+				errors.New("bidder_health.open_seconds must be > 0. Got 0"),
+				errors.New("bidder_health.probe_requests must be > 0. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		errs := test.bidderHealth.validate(nil)
+		assert.Equal(t, test.expectedErrs, errs, test.description)
+	}
+}
+
+func TestValidateGeolocation(t *testing.T) {
+	testCases := []struct {
+		description  string
+		geolocation  Geolocation
+		expectedErrs []error
+	}{
+		{
+			description: "Disabled with invalid values",
+			geolocation: Geolocation{Enabled: false, RefreshIntervalSeconds: -1},
+		},
+		{
+			description: "Enabled with valid values",
+			geolocation: Geolocation{Enabled: true, DatabasePath: "/var/lib/GeoLite2-City.mmdb", RefreshIntervalSeconds: 0},
+		},
+		{
+			description: "Enabled with invalid values",
+			geolocation: Geolocation{Enabled: true, RefreshIntervalSeconds: -1},
+			expectedErrs: []error{
+				errors.New("geolocation.database_path must be set when geolocation is enabled"),
+				errors.New("geolocation.refresh_interval_seconds must be >= 0. Got -1"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		errs := test.geolocation.validate(nil)
+		assert.Equal(t, test.expectedErrs, errs, test.description)
+	}
+}
+
+func TestValidateDeviceDetection(t *testing.T) {
+	testCases := []struct {
+		description     string
+		deviceDetection DeviceDetection
+		expectedErrs    []error
+	}{
+		{
+			description:     "Disabled with invalid values",
+			deviceDetection: DeviceDetection{Enabled: false, CacheSize: -1},
+		},
+		{
+			description:     "Enabled with valid values",
+			deviceDetection: DeviceDetection{Enabled: true, RulesFile: "rules.json", CacheSize: 0},
+		},
+		{
+			description:     "Enabled with invalid values",
+			deviceDetection: DeviceDetection{Enabled: true, CacheSize: -1},
+			expectedErrs: []error{
+				errors.New("device_detection.rules_file must be set when device detection is enabled"),
+				errors.New("device_detection.cache_size must be >= 0. Got -1"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		errs := test.deviceDetection.validate(nil)
+		assert.Equal(t, test.expectedErrs, errs, test.description)
+	}
+}
+
+func TestValidateUIDStore(t *testing.T) {
+	testCases := []struct {
+		description  string
+		uidStore     UIDStore
+		expectedErrs []error
+	}{
+		{
+			description: "Disabled with invalid values",
+			uidStore:    UIDStore{Enabled: false, Type: "redis"},
+		},
+		{
+			description: "Memory",
+			uidStore:    UIDStore{Enabled: true, Type: UIDStoreTypeMemory, TTLDays: 90, MaxEntries: 1000, CleanupIntervalSeconds: 60},
+		},
+		{
+			description: "File",
+			uidStore:    UIDStore{Enabled: true, Type: UIDStoreTypeFile, Path: "uids.db", TTLDays: 90, MaxEntries: 1000, CleanupIntervalSeconds: 60},
+		},
+		{
+			description:  "File without path",
+			uidStore:     UIDStore{Enabled: true, Type: UIDStoreTypeFile, TTLDays: 90, MaxEntries: 1000, CleanupIntervalSeconds: 60},
+			expectedErrs: []error{errors.New("uid_store.path must be set for the file store")},
+		},
+		{
+			description:  "Unknown type",
+			uidStore:     UIDStore{Enabled: true, Type: "redis", TTLDays: 90, MaxEntries: 1000, CleanupIntervalSeconds: 60},
+			expectedErrs: []error{errors.New("uid_store.type must be one of [memory, file]. Got redis")},
+		},
+		{
+			description: "Unbounded",
+			uidStore:    UIDStore{Enabled: true, Type: UIDStoreTypeMemory},
+			expectedErrs: []error{
+				errors.New("uid_store.ttl_days must be > 0. Got 0"),
+				errors.New("uid_store.max_entries must be > 0. Got 0"),
+				errors.New("uid_store.cleanup_interval_seconds must be > 0. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		errs := test.uidStore.validate(nil)
+		assert.Equal(t, test.expectedErrs, errs, test.description)
+	}
+}
+
+func TestValidateEmbeddedCache(t *testing.T) {
+	testCases := []struct {
+		description   string
+		embeddedCache EmbeddedCache
+		expectedErrs  []error
+	}{
+		{
+			description:   "Disabled with invalid values",
+			embeddedCache: EmbeddedCache{Enabled: false, MaxSizeBytes: -1},
+		},
+		{
+			description:   "Enabled with valid values",
+			embeddedCache: EmbeddedCache{Enabled: true, MaxSizeBytes: 1024, DefaultTTLSeconds: 300, MaxTTLSeconds: 300, MaxValueSizeBytes: 512},
+		},
+		{
+			description:   "Enabled with invalid values",
+			embeddedCache: EmbeddedCache{Enabled: true, MaxSizeBytes: 0, DefaultTTLSeconds: 0, MaxTTLSeconds: -1, MaxValueSizeBytes: 0},
+			expectedErrs: []error{
+				errors.New("cache.embedded.max_size_bytes must be > 0. Got 0"),
+				errors.New("cache.embedded.default_ttl_seconds must be > 0. Got 0"),
+				errors.New("cache.embedded.max_ttl_seconds must be >= cache.embedded.default_ttl_seconds. Got -1"),
+				errors.New("cache.embedded.max_value_size_bytes must be > 0. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		errs := test.embeddedCache.validate(nil)
+		assert.Equal(t, test.expectedErrs, errs, test.description)
+	}
+}
+
 func TestValidateAccountsConfigRestrictions(t *testing.T) {
 	cfg, v := newDefaultConfig(t)
 	cfg.Accounts.Files.Enabled = true
@@ -3137,3 +3432,35 @@ func TestTCF2FeatureOneVendorException(t *testing.T) {
 		assert.Equal(t, tt.wantIsVendorException, value, tt.description)
 	}
 }
+
+func TestValidateHostCookie(t *testing.T) {
+	testCases := []struct {
+		description  string
+		maxCookies   int
+		sameSite     string
+		expectedErrs []error
+	}{
+		{description: "Unset", maxCookies: 0},
+		{description: "Multiple cookies", maxCookies: 3},
+		{
+			description:  "Negative",
+			maxCookies:   -1,
+			expectedErrs: []error{errors.New("host_cookie.max_cookies must be >= 0. Got -1")},
+		},
+		{description: "SameSite auto", sameSite: "auto"},
+		{description: "SameSite none", sameSite: "none"},
+		{description: "SameSite lax", sameSite: "lax"},
+		{description: "SameSite strict", sameSite: "strict"},
+		{
+			description:  "Invalid SameSite",
+			sameSite:     "None",
+			expectedErrs: []error{errors.New("host_cookie.samesite must be one of [auto, none, lax, strict]. Got None")},
+		},
+	}
+
+	for _, test := range testCases {
+		cfg := HostCookie{MaxCookies: test.maxCookies, SameSite: test.sameSite}
+		errs := cfg.validate(nil)
+		assert.Equal(t, test.expectedErrs, errs, test.description)
+	}
+}
@@ -1,95 +1,139 @@
 package currency
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/golang/glog"
-	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/metrics"
 	"github.com/prebid/prebid-server/util/timeutil"
 )
 
+// constantRatesSource is the source reported by GetInfo when no rates source has valid rates
+const constantRatesSource = "constant"
+
 // RateConverter holds the currencies conversion rates dictionary
 type RateConverter struct {
-	httpClient          httpClient
+	sources             []RatesSource
+	sourcesState        []ratesSourceState
+	sourcesMutex        sync.Mutex
 	staleRatesThreshold time.Duration
-	syncSourceURL       string
 	rates               atomic.Value // Should only hold Rates struct
 	lastUpdated         atomic.Value // Should only hold time.Time
+	activeSource        atomic.Value // Should only hold string
 	constantRates       Conversions
+	metricsEngine       metrics.MetricsEngine
 	time                timeutil.Time
 }
 
-// NewRateConverter returns a new RateConverter
+// ratesSourceState holds the last rates successfully fetched from a source, and whether its latest fetch failed
+type ratesSourceState struct {
+	rates       *Rates
+	lastUpdated time.Time
+	fetchFailed bool
+}
+
+// NewRateConverter returns a new RateConverter fetching the rates from the syncSourceURL
 func NewRateConverter(
 	httpClient httpClient,
 	syncSourceURL string,
 	staleRatesThreshold time.Duration,
 ) *RateConverter {
+	return NewRateConverterWithSources(
+		[]RatesSource{NewHTTPRatesSource(config.CurrencyRatesSourceHTTP, syncSourceURL, httpClient)},
+		staleRatesThreshold,
+	)
+}
+
+// NewRateConverterWithSources returns a new RateConverter which uses the rates of the first source, in order,
+// whose rates aren't stale
+func NewRateConverterWithSources(sources []RatesSource, staleRatesThreshold time.Duration) *RateConverter {
 	return &RateConverter{
-		httpClient:          httpClient,
+		sources:             sources,
+		sourcesState:        make([]ratesSourceState, len(sources)),
 		staleRatesThreshold: staleRatesThreshold,
-		syncSourceURL:       syncSourceURL,
 		rates:               atomic.Value{},
 		lastUpdated:         atomic.Value{},
+		activeSource:        atomic.Value{},
 		constantRates:       NewConstantRates(),
 		time:                &timeutil.RealTime{},
 	}
 }
 
-// fetch allows to retrieve the currencies rates from the syncSourceURL provided
-func (rc *RateConverter) fetch() (*Rates, error) {
-	request, err := http.NewRequest("GET", rc.syncSourceURL, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	response, err := rc.httpClient.Do(request)
-	if err != nil {
-		return nil, err
-	}
+// SetMetricsEngine sets the engine recording the staleness of each source. It must be called before the
+// converter starts running.
+func (rc *RateConverter) SetMetricsEngine(metricsEngine metrics.MetricsEngine) {
+	rc.metricsEngine = metricsEngine
+}
 
-	if response.StatusCode >= 400 {
-		message := fmt.Sprintf("The currency rates request failed with status code %d", response.StatusCode)
-		return nil, &errortypes.BadServerResponse{Message: message}
+// Update updates the internal currencies rates from the sources
+func (rc *RateConverter) update() error {
+	// the sources are fetched without holding the lock, so that a slow source doesn't block GetInfo
+	fetchedRates := make([]*Rates, len(rc.sources))
+	fetchedAt := make([]time.Time, len(rc.sources))
+	var errs []string
+	for i, source := range rc.sources {
+		rates, err := source.Fetch()
+		if err != nil {
+			glog.Errorf("Error updating conversion rates from source %s: %v", source.Name(), err)
+			errs = append(errs, fmt.Sprintf("%s: %v", source.Name(), err))
+			continue
+		}
+		fetchedRates[i] = rates
+		fetchedAt[i] = rc.time.Now()
 	}
 
-	defer response.Body.Close()
+	rc.sourcesMutex.Lock()
+	defer rc.sourcesMutex.Unlock()
 
-	bytesJSON, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, err
+	for i := range rc.sources {
+		if fetchedRates[i] != nil {
+			rc.sourcesState[i] = ratesSourceState{rates: fetchedRates[i], lastUpdated: fetchedAt[i]}
+		} else {
+			rc.sourcesState[i].fetchFailed = true
+		}
 	}
 
-	updatedRates := &Rates{}
-	err = json.Unmarshal(bytesJSON, updatedRates)
-	if err != nil {
-		return nil, err
+	// Sources whose latest fetch failed are only used when no other source has usable rates, so that a
+	// failing source fails over even when the rates never become stale
+	active, fallback := -1, -1
+	for i, source := range rc.sources {
+		stale := !rc.isUsable(rc.sourcesState[i])
+		if !stale {
+			if active < 0 && !rc.sourcesState[i].fetchFailed {
+				active = i
+			}
+			if fallback < 0 {
+				fallback = i
+			}
+		}
+		if rc.metricsEngine != nil {
+			rc.metricsEngine.RecordCurrencyRatesStale(source.Name(), stale)
+		}
+	}
+	if active < 0 {
+		active = fallback
 	}
 
-	return updatedRates, err
-}
-
-// Update updates the internal currencies rates from remote sources
-func (rc *RateConverter) update() error {
-	rates, err := rc.fetch()
-	if err == nil {
-		rc.rates.Store(rates)
-		rc.lastUpdated.Store(rc.time.Now())
-	} else {
-		if rc.checkStaleRates() {
-			rc.clearRates()
-			glog.Errorf("Error updating conversion rates, falling back to constant rates: %v", err)
-		} else {
-			glog.Errorf("Error updating conversion rates: %v", err)
-		}
+	if active >= 0 {
+		rc.rates.Store(rc.sourcesState[active].rates)
+		rc.lastUpdated.Store(rc.sourcesState[active].lastUpdated)
+		rc.activeSource.Store(rc.sources[active].Name())
+	} else if rc.Rates() != rc.constantRates {
+		rc.clearRates()
+		rc.activeSource.Store("")
+		glog.Errorf("No currency rates source has valid rates, falling back to constant rates")
 	}
 
-	return err
+	if len(errs) > 0 {
+		return fmt.Errorf("Error updating conversion rates: %s", strings.Join(errs, "; "))
+	}
+	return nil
 }
 
 func (rc *RateConverter) Run() error {
@@ -104,6 +148,15 @@ func (rc *RateConverter) LastUpdated() time.Time {
 	return time.Time{}
 }
 
+// ActiveSource returns the name of the source whose rates are in use, or an empty string when the converter
+// falls back to the constant rates
+func (rc *RateConverter) ActiveSource() string {
+	if activeSource := rc.activeSource.Load(); activeSource != nil {
+		return activeSource.(string)
+	}
+	return ""
+}
+
 // Rates returns current conversions rates
 func (rc *RateConverter) Rates() Conversions {
 	// atomic.Value field rates is an empty interface and will be of type *Rates the first time rates are stored
@@ -120,30 +173,63 @@ func (rc *RateConverter) clearRates() {
 	rc.rates.Store((*Rates)(nil))
 }
 
-// checkStaleRates checks if loaded third party conversion rates are stale
-func (rc *RateConverter) checkStaleRates() bool {
+// isUsable checks if a source has fetched rates which aren't stale
+func (rc *RateConverter) isUsable(state ratesSourceState) bool {
+	return state.rates != nil && !rc.checkStaleRates(state.lastUpdated)
+}
+
+// checkStaleRates checks if third party conversion rates loaded at lastUpdated are stale
+func (rc *RateConverter) checkStaleRates(lastUpdated time.Time) bool {
 	if rc.staleRatesThreshold <= 0 {
 		return false
 	}
 
 	currentTime := rc.time.Now().UTC()
-	if lastUpdated := rc.lastUpdated.Load(); lastUpdated != nil {
-		delta := currentTime.Sub(lastUpdated.(time.Time).UTC())
-		if delta.Seconds() > rc.staleRatesThreshold.Seconds() {
-			return true
-		}
-	}
-	return false
+	delta := currentTime.Sub(lastUpdated.UTC())
+	return delta.Seconds() > rc.staleRatesThreshold.Seconds()
+}
+
+// sourceInfo describes a rates source in the /currency/rates endpoint
+type sourceInfo struct {
+	Name        string     `json:"name"`
+	Location    string     `json:"location"`
+	Active      bool       `json:"active"`
+	Stale       bool       `json:"stale"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
 }
 
 // GetInfo returns setup information about the converter
 func (rc *RateConverter) GetInfo() ConverterInfo {
-	var rates *map[string]map[string]float64
-	rates = rc.Rates().GetRates()
+	rc.sourcesMutex.Lock()
+	defer rc.sourcesMutex.Unlock()
+
+	activeSource := rc.ActiveSource()
+	source := constantRatesSource
+	sources := make([]sourceInfo, 0, len(rc.sources))
+	for i, s := range rc.sources {
+		info := sourceInfo{
+			Name:     s.Name(),
+			Location: s.Location(),
+			Active:   s.Name() == activeSource,
+			Stale:    !rc.isUsable(rc.sourcesState[i]),
+		}
+		if info.Active {
+			source = s.Location()
+		}
+		if lastUpdated := rc.sourcesState[i].lastUpdated; !lastUpdated.IsZero() {
+			info.LastUpdated = &lastUpdated
+		}
+		sources = append(sources, info)
+	}
+
 	return converterInfo{
-		source:      rc.syncSourceURL,
+		source:      source,
 		lastUpdated: rc.LastUpdated(),
-		rates:       rates,
+		rates:       rc.Rates().GetRates(),
+		additionalInfo: map[string]interface{}{
+			"activeSource": activeSource,
+			"sources":      sources,
+		},
 	}
 }
 
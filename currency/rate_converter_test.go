@@ -1,6 +1,7 @@
 package currency
 
 import (
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -9,8 +10,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prebid/prebid-server/metrics"
 	"github.com/prebid/prebid-server/util/task"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func getMockRates() []byte {
@@ -365,6 +368,106 @@ func TestRace(t *testing.T) {
 	wg.Wait()
 }
 
+func TestRatesSourcesFailover(t *testing.T) {
+	primary := &mockRatesSource{name: "primary", rates: &Rates{Conversions: map[string]map[string]float64{"USD": {"GBP": 0.77}}}}
+	backup := &mockRatesSource{name: "backup", rates: &Rates{Conversions: map[string]map[string]float64{"USD": {"GBP": 0.8}}}}
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordCurrencyRatesStale", mock.Anything, mock.Anything).Return()
+
+	initialFakeTime := time.Date(2018, time.September, 12, 30, 0, 0, 0, time.UTC)
+	fakeTime := &FakeTime{time: initialFakeTime}
+
+	currencyConverter := NewRateConverterWithSources([]RatesSource{primary, backup}, 30*time.Second)
+	currencyConverter.SetMetricsEngine(metricsEngine)
+	currencyConverter.time = fakeTime
+
+	// Both sources succeed, the first one is used
+	assert.Nil(t, currencyConverter.Run())
+	assert.Equal(t, primary.rates, currencyConverter.Rates(), "Rates of the first source should be used")
+	assert.Equal(t, "primary", currencyConverter.ActiveSource())
+	assert.Equal(t, "primary-url", currencyConverter.GetInfo().Source())
+
+	// The latest fetch of the first source fails, the converter fails over to the second one
+	primary.err = errors.New("primary failure")
+	fakeTime.time = initialFakeTime.Add(20 * time.Second)
+	assert.NotNil(t, currencyConverter.Run())
+	assert.Equal(t, backup.rates, currencyConverter.Rates(), "Rates of the second source should be used")
+	assert.Equal(t, "backup", currencyConverter.ActiveSource())
+	assert.Equal(t, initialFakeTime.Add(20*time.Second), currencyConverter.LastUpdated())
+	metricsEngine.AssertCalled(t, "RecordCurrencyRatesStale", "backup", false)
+
+	// Both sources fail, the rates of the first source are used as long as they aren't stale
+	backup.err = errors.New("backup failure")
+	fakeTime.time = initialFakeTime.Add(25 * time.Second)
+	assert.NotNil(t, currencyConverter.Run())
+	assert.Equal(t, primary.rates, currencyConverter.Rates(), "Rates of the first source should be used")
+	assert.Equal(t, "primary", currencyConverter.ActiveSource())
+	assert.Equal(t, initialFakeTime, currencyConverter.LastUpdated())
+
+	// The rates of the first source are stale, the converter uses the second one which isn't stale yet
+	fakeTime.time = initialFakeTime.Add(40 * time.Second)
+	assert.NotNil(t, currencyConverter.Run())
+	assert.Equal(t, backup.rates, currencyConverter.Rates(), "Rates of the second source should be used")
+	assert.Equal(t, "backup", currencyConverter.ActiveSource())
+	metricsEngine.AssertCalled(t, "RecordCurrencyRatesStale", "primary", true)
+
+	// All the rates are stale, the converter falls back to the constant rates
+	fakeTime.time = initialFakeTime.Add(80 * time.Second)
+	assert.NotNil(t, currencyConverter.Run())
+	assert.Equal(t, &ConstantRates{}, currencyConverter.Rates(), "Rates should return constant rates")
+	assert.Equal(t, "", currencyConverter.ActiveSource())
+	assert.Equal(t, "constant", currencyConverter.GetInfo().Source())
+	metricsEngine.AssertCalled(t, "RecordCurrencyRatesStale", "backup", true)
+
+	// The first source recovers while the second one still fails
+	primary.err = nil
+	assert.NotNil(t, currencyConverter.Run())
+	assert.Equal(t, primary.rates, currencyConverter.Rates(), "Rates of the first source should be used")
+	assert.Equal(t, "primary", currencyConverter.ActiveSource())
+}
+
+func TestRatesSourcesFailoverWithoutStaleness(t *testing.T) {
+	primary := &mockRatesSource{name: "primary", rates: &Rates{Conversions: map[string]map[string]float64{"USD": {"GBP": 0.77}}}}
+	backup := &mockRatesSource{name: "backup", rates: &Rates{Conversions: map[string]map[string]float64{"USD": {"GBP": 0.8}}}}
+
+	currencyConverter := NewRateConverterWithSources([]RatesSource{primary, backup}, 0)
+
+	assert.Nil(t, currencyConverter.Run())
+	assert.Equal(t, "primary", currencyConverter.ActiveSource())
+
+	// Rates never become stale, the failed fetch alone makes the converter fail over
+	primary.err = errors.New("primary failure")
+	assert.NotNil(t, currencyConverter.Run())
+	assert.Equal(t, backup.rates, currencyConverter.Rates(), "Rates of the second source should be used")
+	assert.Equal(t, "backup", currencyConverter.ActiveSource())
+
+	primary.err = nil
+	assert.Nil(t, currencyConverter.Run())
+	assert.Equal(t, "primary", currencyConverter.ActiveSource())
+}
+
+// mockRatesSource is a rates source mock returning either its rates or its error
+type mockRatesSource struct {
+	name  string
+	rates *Rates
+	err   error
+}
+
+func (m *mockRatesSource) Name() string {
+	return m.name
+}
+
+func (m *mockRatesSource) Location() string {
+	return m.name + "-url"
+}
+
+func (m *mockRatesSource) Fetch() (*Rates, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.rates, nil
+}
+
 // mockHttpClient is a simple http client mock returning a constant response body
 type mockHttpClient struct {
 	responseBody string
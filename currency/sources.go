@@ -0,0 +1,176 @@
+package currency
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/errortypes"
+)
+
+// RatesSource provides currency conversion rates to the RateConverter
+type RatesSource interface {
+	// Name identifies the source in logs, metrics and the /currency/rates endpoint
+	Name() string
+	// Location describes where the rates come from, like an URL or a file path
+	Location() string
+	Fetch() (*Rates, error)
+}
+
+// NewRatesSources builds the ordered list of rates sources of the host config. The fetch URL is used as the only
+// source when no sources are configured.
+func NewRatesSources(cfg config.CurrencyConverter, httpClient httpClient) []RatesSource {
+	if len(cfg.Sources) == 0 {
+		return []RatesSource{NewHTTPRatesSource(config.CurrencyRatesSourceHTTP, cfg.FetchURL, httpClient)}
+	}
+
+	sources := make([]RatesSource, 0, len(cfg.Sources))
+	for _, source := range cfg.Sources {
+		switch source.Type {
+		case config.CurrencyRatesSourceHTTP:
+			sources = append(sources, NewHTTPRatesSource(source.SourceName(), source.URL, httpClient))
+		case config.CurrencyRatesSourceFile:
+			sources = append(sources, NewFileRatesSource(source.SourceName(), source.Path))
+		case config.CurrencyRatesSourceStatic:
+			sources = append(sources, NewStaticRatesSource(source.SourceName(), source.Rates))
+		}
+	}
+	return sources
+}
+
+type httpRatesSource struct {
+	name       string
+	url        string
+	httpClient httpClient
+}
+
+// NewHTTPRatesSource returns a source which fetches the rates from a remote currency file
+func NewHTTPRatesSource(name string, url string, httpClient httpClient) RatesSource {
+	return &httpRatesSource{
+		name:       name,
+		url:        url,
+		httpClient: httpClient,
+	}
+}
+
+func (s *httpRatesSource) Name() string {
+	return s.name
+}
+
+func (s *httpRatesSource) Location() string {
+	return s.url
+}
+
+func (s *httpRatesSource) Fetch() (*Rates, error) {
+	request, err := http.NewRequest("GET", s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 400 {
+		message := fmt.Sprintf("The currency rates request failed with status code %d", response.StatusCode)
+		return nil, &errortypes.BadServerResponse{Message: message}
+	}
+
+	defer response.Body.Close()
+
+	bytesJSON, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedRates := &Rates{}
+	err = json.Unmarshal(bytesJSON, updatedRates)
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedRates, err
+}
+
+type fileRatesSource struct {
+	name string
+	path string
+}
+
+// NewFileRatesSource returns a source which reads the rates from a local file in the currency file format
+func NewFileRatesSource(name string, path string) RatesSource {
+	return &fileRatesSource{
+		name: name,
+		path: path,
+	}
+}
+
+func (s *fileRatesSource) Name() string {
+	return s.name
+}
+
+func (s *fileRatesSource) Location() string {
+	return s.path
+}
+
+func (s *fileRatesSource) Fetch() (*Rates, error) {
+	bytesJSON, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := &Rates{}
+	if err := json.Unmarshal(bytesJSON, rates); err != nil {
+		return nil, fmt.Errorf("invalid currency rates file %s: %v", s.path, err)
+	}
+	return rates, nil
+}
+
+type staticRatesSource struct {
+	name  string
+	rates *Rates
+}
+
+// NewStaticRatesSource returns a source which always provides the rates set in the host config
+func NewStaticRatesSource(name string, conversions map[string]map[string]float64) RatesSource {
+	return &staticRatesSource{
+		name:  name,
+		rates: &Rates{Conversions: NormalizeConversions(conversions)},
+	}
+}
+
+func (s *staticRatesSource) Name() string {
+	return s.name
+}
+
+func (s *staticRatesSource) Location() string {
+	return config.CurrencyRatesSourceStatic
+}
+
+func (s *staticRatesSource) Fetch() (*Rates, error) {
+	return s.rates, nil
+}
+
+// NormalizeConversions upper cases the currency codes, since the config loader may lower case map keys
+func NormalizeConversions(conversions map[string]map[string]float64) map[string]map[string]float64 {
+	if conversions == nil {
+		return nil
+	}
+
+	normalized := make(map[string]map[string]float64, len(conversions))
+	for from, rates := range conversions {
+		from = strings.ToUpper(from)
+		if normalized[from] == nil {
+			normalized[from] = make(map[string]float64, len(rates))
+		}
+		for to, rate := range rates {
+			normalized[from][strings.ToUpper(to)] = rate
+		}
+	}
+	return normalized
+}
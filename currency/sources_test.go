@@ -0,0 +1,81 @@
+package currency
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRatesSources(t *testing.T) {
+	testCases := []struct {
+		description       string
+		cfg               config.CurrencyConverter
+		expectedNames     []string
+		expectedLocations []string
+	}{
+		{
+			description:       "No sources, fetch url is used",
+			cfg:               config.CurrencyConverter{FetchURL: "https://currency.prebid.org"},
+			expectedNames:     []string{"http"},
+			expectedLocations: []string{"https://currency.prebid.org"},
+		},
+		{
+			description: "Ordered sources",
+			cfg: config.CurrencyConverter{
+				FetchURL: "https://currency.prebid.org",
+				Sources: []config.CurrencyRatesSource{
+					{Name: "primary", Type: config.CurrencyRatesSourceHTTP, URL: "https://primary.currency.org"},
+					{Type: config.CurrencyRatesSourceFile, Path: "rates.json"},
+					{Type: config.CurrencyRatesSourceStatic, Rates: map[string]map[string]float64{"USD": {"EUR": 0.9}}},
+				},
+			},
+			expectedNames:     []string{"primary", "file", "static"},
+			expectedLocations: []string{"https://primary.currency.org", "rates.json", "static"},
+		},
+	}
+
+	for _, test := range testCases {
+		sources := NewRatesSources(test.cfg, &mockHttpClient{})
+
+		var names, locations []string
+		for _, source := range sources {
+			names = append(names, source.Name())
+			locations = append(locations, source.Location())
+		}
+		assert.Equal(t, test.expectedNames, names, test.description)
+		assert.Equal(t, test.expectedLocations, locations, test.description)
+	}
+}
+
+func TestFileRatesSource(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rates.json")
+	if err := os.WriteFile(path, getMockRates(), 0644); err != nil {
+		t.Fatalf("Failed to write the rates file: %v", err)
+	}
+
+	rates, err := NewFileRatesSource("file", path).Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]float64{"USD": {"GBP": 0.77208}, "GBP": {"USD": 1.2952}}, rates.Conversions)
+
+	malformedPath := filepath.Join(dir, "malformed.json")
+	if err := os.WriteFile(malformedPath, []byte(`{"conversions":`), 0644); err != nil {
+		t.Fatalf("Failed to write the rates file: %v", err)
+	}
+	_, err = NewFileRatesSource("file", malformedPath).Fetch()
+	assert.EqualError(t, err, "invalid currency rates file "+malformedPath+": unexpected end of JSON input")
+
+	_, err = NewFileRatesSource("file", filepath.Join(dir, "missing.json")).Fetch()
+	assert.Error(t, err, "Missing file")
+}
+
+func TestStaticRatesSource(t *testing.T) {
+	source := NewStaticRatesSource("static", map[string]map[string]float64{"usd": {"eur": 0.9}})
+
+	rates, err := source.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]float64{"USD": {"EUR": 0.9}}, rates.Conversions, "Currency codes should be upper cased")
+}
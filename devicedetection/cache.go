@@ -0,0 +1,60 @@
+package devicedetection
+
+import (
+	"container/list"
+	"sync"
+)
+
+// cachedDetector remembers the devices detected for the most recently seen user agents
+type cachedDetector struct {
+	detector DeviceDetector
+	maxSize  int
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type cacheEntry struct {
+	ua   string
+	info DeviceInfo
+}
+
+// NewCachedDetector caches the results of the detector for up to size user agents. The least recently used
+// user agent is evicted when the cache is full.
+func NewCachedDetector(detector DeviceDetector, size int) DeviceDetector {
+	if size <= 0 {
+		return detector
+	}
+	return &cachedDetector{
+		detector: detector,
+		maxSize:  size,
+		entries:  make(map[string]*list.Element, size),
+		order:    list.New(),
+	}
+}
+
+func (c *cachedDetector) Detect(ua string) DeviceInfo {
+	c.mutex.Lock()
+	if element, ok := c.entries[ua]; ok {
+		c.order.MoveToFront(element)
+		info := element.Value.(*cacheEntry).info
+		c.mutex.Unlock()
+		return info
+	}
+	c.mutex.Unlock()
+
+	info := c.detector.Detect(ua)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.entries[ua]; ok {
+		return info
+	}
+	if c.order.Len() >= c.maxSize {
+		oldest := c.order.Back()
+		delete(c.entries, c.order.Remove(oldest).(*cacheEntry).ua)
+	}
+	c.entries[ua] = c.order.PushFront(&cacheEntry{ua: ua, info: info})
+	return info
+}
@@ -0,0 +1,39 @@
+package devicedetection
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v17/adcom1"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingDetector struct {
+	calls map[string]int
+}
+
+func (d *countingDetector) Detect(ua string) DeviceInfo {
+	d.calls[ua]++
+	return DeviceInfo{DeviceType: adcom1.DevicePC, Model: ua}
+}
+
+func TestCachedDetector(t *testing.T) {
+	detector := &countingDetector{calls: make(map[string]int)}
+	cached := NewCachedDetector(detector, 2)
+
+	assert.Equal(t, DeviceInfo{DeviceType: adcom1.DevicePC, Model: "a"}, cached.Detect("a"))
+	assert.Equal(t, DeviceInfo{DeviceType: adcom1.DevicePC, Model: "a"}, cached.Detect("a"))
+	cached.Detect("b")
+	cached.Detect("a")
+	// c evicts b, the least recently used user agent
+	cached.Detect("c")
+	cached.Detect("a")
+	cached.Detect("b")
+
+	assert.Equal(t, map[string]int{"a": 1, "b": 2, "c": 1}, detector.calls)
+}
+
+func TestCachedDetectorDisabled(t *testing.T) {
+	detector := &countingDetector{calls: make(map[string]int)}
+
+	assert.Same(t, detector, NewCachedDetector(detector, 0))
+}
@@ -0,0 +1,121 @@
+package devicedetection
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/prebid/openrtb/v17/adcom1"
+	"github.com/prebid/openrtb/v17/openrtb2"
+)
+
+const (
+	headerUA                = "Sec-CH-UA"
+	headerUAFullVersionList = "Sec-CH-UA-Full-Version-List"
+	headerUAPlatform        = "Sec-CH-UA-Platform"
+	headerUAPlatformVersion = "Sec-CH-UA-Platform-Version"
+	headerUAMobile          = "Sec-CH-UA-Mobile"
+	headerUAArch            = "Sec-CH-UA-Arch"
+	headerUABitness         = "Sec-CH-UA-Bitness"
+	headerUAModel           = "Sec-CH-UA-Model"
+)
+
+// ParseClientHints builds the structured user agent from the User-Agent Client Hints headers. It returns nil
+// when the browser didn't send any.
+func ParseClientHints(header http.Header) *openrtb2.UserAgent {
+	sua := &openrtb2.UserAgent{}
+	highEntropy := false
+
+	if browsers := parseBrandList(header.Get(headerUAFullVersionList)); len(browsers) > 0 {
+		sua.Browsers = browsers
+		highEntropy = true
+	} else {
+		sua.Browsers = parseBrandList(header.Get(headerUA))
+	}
+
+	if platform := parseString(header.Get(headerUAPlatform)); platform != "" {
+		sua.Platform = &openrtb2.BrandVersion{Brand: platform}
+		if version := parseString(header.Get(headerUAPlatformVersion)); version != "" {
+			sua.Platform.Version = strings.Split(version, ".")
+			highEntropy = true
+		}
+	}
+
+	switch strings.TrimSpace(header.Get(headerUAMobile)) {
+	case "?1":
+		mobile := int8(1)
+		sua.Mobile = &mobile
+	case "?0":
+		mobile := int8(0)
+		sua.Mobile = &mobile
+	}
+
+	if sua.Architecture = parseString(header.Get(headerUAArch)); sua.Architecture != "" {
+		highEntropy = true
+	}
+	if sua.Bitness = parseString(header.Get(headerUABitness)); sua.Bitness != "" {
+		highEntropy = true
+	}
+	if sua.Model = parseString(header.Get(headerUAModel)); sua.Model != "" {
+		highEntropy = true
+	}
+
+	if len(sua.Browsers) == 0 && sua.Platform == nil && sua.Mobile == nil && !highEntropy {
+		return nil
+	}
+	if highEntropy {
+		sua.Source = adcom1.UASourceHighEntropy
+	} else {
+		sua.Source = adcom1.UASourceLowEntropy
+	}
+	return sua
+}
+
+// parseBrandList parses a structured header list like "Chromium";v="112", "Not:A-Brand";v="99"
+func parseBrandList(value string) []openrtb2.BrandVersion {
+	var brands []openrtb2.BrandVersion
+	for _, item := range splitOutsideQuotes(value, ',') {
+		params := splitOutsideQuotes(item, ';')
+		brand := parseString(params[0])
+		if brand == "" {
+			continue
+		}
+		brandVersion := openrtb2.BrandVersion{Brand: brand}
+		for _, param := range params[1:] {
+			if name, value, ok := strings.Cut(param, "="); ok && strings.TrimSpace(name) == "v" {
+				if version := parseString(value); version != "" {
+					brandVersion.Version = strings.Split(version, ".")
+				}
+			}
+		}
+		brands = append(brands, brandVersion)
+	}
+	return brands
+}
+
+// parseString returns the value of a structured header string, which is quoted
+func parseString(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = value[1 : len(value)-1]
+	}
+	return strings.TrimSpace(strings.ReplaceAll(value, `\"`, `"`))
+}
+
+func splitOutsideQuotes(value string, separator byte) []string {
+	var parts []string
+	start, quoted := 0, false
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case '"':
+			quoted = !quoted
+		case separator:
+			if !quoted {
+				parts = append(parts, value[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, value[start:])
+}
@@ -0,0 +1,86 @@
+package devicedetection
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/prebid/openrtb/v17/adcom1"
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseClientHints(t *testing.T) {
+	mobile := int8(1)
+	desktop := int8(0)
+
+	testCases := []struct {
+		description string
+		headers     map[string]string
+		expectedSUA *openrtb2.UserAgent
+	}{
+		{
+			description: "No client hints",
+			headers:     map[string]string{"User-Agent": "Mozilla/5.0"},
+		},
+		{
+			description: "Low entropy",
+			headers: map[string]string{
+				"Sec-CH-UA":          `"Chromium";v="112", "Google Chrome";v="112", "Not:A-Brand";v="99"`,
+				"Sec-CH-UA-Mobile":   "?0",
+				"Sec-CH-UA-Platform": `"Windows"`,
+			},
+			expectedSUA: &openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{
+					{Brand: "Chromium", Version: []string{"112"}},
+					{Brand: "Google Chrome", Version: []string{"112"}},
+					{Brand: "Not:A-Brand", Version: []string{"99"}},
+				},
+				Platform: &openrtb2.BrandVersion{Brand: "Windows"},
+				Mobile:   &desktop,
+				Source:   adcom1.UASourceLowEntropy,
+			},
+		},
+		{
+			description: "High entropy",
+			headers: map[string]string{
+				"Sec-CH-UA":                   `"Chromium";v="112", "Google Chrome";v="112"`,
+				"Sec-CH-UA-Full-Version-List": `"Chromium";v="112.0.5615.49", "Google Chrome";v="112.0.5615.49", "Not\"A;Brand";v="99.0.0.0"`,
+				"Sec-CH-UA-Mobile":            "?1",
+				"Sec-CH-UA-Platform":          `"Android"`,
+				"Sec-CH-UA-Platform-Version":  `"13.0.0"`,
+				"Sec-CH-UA-Arch":              `""`,
+				"Sec-CH-UA-Bitness":           `"64"`,
+				"Sec-CH-UA-Model":             `"Pixel 7"`,
+			},
+			expectedSUA: &openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{
+					{Brand: "Chromium", Version: []string{"112", "0", "5615", "49"}},
+					{Brand: "Google Chrome", Version: []string{"112", "0", "5615", "49"}},
+					{Brand: `Not"A;Brand`, Version: []string{"99", "0", "0", "0"}},
+				},
+				Platform: &openrtb2.BrandVersion{Brand: "Android", Version: []string{"13", "0", "0"}},
+				Mobile:   &mobile,
+				Bitness:  "64",
+				Model:    "Pixel 7",
+				Source:   adcom1.UASourceHighEntropy,
+			},
+		},
+		{
+			description: "Brand without version",
+			headers:     map[string]string{"Sec-CH-UA": `"Chromium", ""`},
+			expectedSUA: &openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{{Brand: "Chromium"}},
+				Source:   adcom1.UASourceLowEntropy,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		header := http.Header{}
+		for name, value := range test.headers {
+			header.Set(name, value)
+		}
+
+		assert.Equal(t, test.expectedSUA, ParseClientHints(header), test.description)
+	}
+}
@@ -0,0 +1,17 @@
+package devicedetection
+
+import "github.com/prebid/openrtb/v17/adcom1"
+
+// DeviceInfo holds the device fields detected from a user agent. Empty fields are unknown.
+type DeviceInfo struct {
+	DeviceType adcom1.DeviceType
+	OS         string
+	OSV        string
+	Make       string
+	Model      string
+}
+
+// DeviceDetector detects the device which sent a user agent
+type DeviceDetector interface {
+	Detect(ua string) DeviceInfo
+}
@@ -0,0 +1,98 @@
+package devicedetection
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/prebid/openrtb/v17/adcom1"
+)
+
+// rulesFile is the format of the device detection rules file. The rules are matched against the user agent in
+// order and each field is taken from the first matching rule which sets it, so specific rules must come before
+// generic ones. The os, osv, make and model values may reference the groups of the pattern, like $1.
+type rulesFile struct {
+	Rules []ruleConfig `json:"rules"`
+}
+
+type ruleConfig struct {
+	Pattern    string            `json:"pattern"`
+	DeviceType adcom1.DeviceType `json:"devicetype"`
+	OS         string            `json:"os"`
+	OSV        string            `json:"osv"`
+	Make       string            `json:"make"`
+	Model      string            `json:"model"`
+}
+
+type rule struct {
+	pattern *regexp.Regexp
+	ruleConfig
+}
+
+// RuleDetector detects devices with the regular expressions of a rules file
+type RuleDetector struct {
+	rules []rule
+}
+
+// NewRuleDetector loads the rules file at the path
+func NewRuleDetector(path string) (*RuleDetector, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file rulesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid device detection rules file %s: %v", path, err)
+	}
+
+	detector := &RuleDetector{rules: make([]rule, 0, len(file.Rules))}
+	for i, config := range file.Rules {
+		pattern, err := regexp.Compile(config.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern of device detection rule %d: %v", i, err)
+		}
+		detector.rules = append(detector.rules, rule{pattern: pattern, ruleConfig: config})
+	}
+	return detector, nil
+}
+
+func (d *RuleDetector) Detect(ua string) DeviceInfo {
+	var info DeviceInfo
+	if ua == "" {
+		return info
+	}
+
+	for _, rule := range d.rules {
+		match := rule.pattern.FindStringSubmatchIndex(ua)
+		if match == nil {
+			continue
+		}
+		if info.DeviceType == 0 {
+			info.DeviceType = rule.DeviceType
+		}
+		if info.OS == "" {
+			info.OS = rule.expand(rule.OS, ua, match)
+		}
+		if info.OSV == "" {
+			// versions like the iOS one in 16_4_1 are reported with dots
+			info.OSV = strings.ReplaceAll(rule.expand(rule.OSV, ua, match), "_", ".")
+		}
+		if info.Make == "" {
+			info.Make = rule.expand(rule.Make, ua, match)
+		}
+		if info.Model == "" {
+			info.Model = rule.expand(rule.Model, ua, match)
+		}
+	}
+	return info
+}
+
+func (r *rule) expand(template string, ua string, match []int) string {
+	if template == "" {
+		return ""
+	}
+	return strings.TrimSpace(string(r.pattern.ExpandString(nil, template, ua, match)))
+}
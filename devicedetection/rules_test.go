@@ -0,0 +1,127 @@
+package devicedetection
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/openrtb/v17/adcom1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticRules(t *testing.T) {
+	detector, err := NewRuleDetector("../static/device-detection/rules.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	testCases := []struct {
+		description  string
+		ua           string
+		expectedInfo DeviceInfo
+	}{
+		{
+			description:  "iPhone",
+			ua:           "Mozilla/5.0 (iPhone; CPU iPhone OS 16_4_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.4 Mobile/15E148 Safari/604.1",
+			expectedInfo: DeviceInfo{DeviceType: adcom1.DevicePhone, OS: "iOS", OSV: "16.4.1", Make: "Apple", Model: "iPhone"},
+		},
+		{
+			description:  "iPad",
+			ua:           "Mozilla/5.0 (iPad; CPU OS 15_7 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.7 Mobile/15E148 Safari/604.1",
+			expectedInfo: DeviceInfo{DeviceType: adcom1.DeviceTablet, OS: "iOS", OSV: "15.7", Make: "Apple", Model: "iPad"},
+		},
+		{
+			description:  "Mac",
+			ua:           "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Safari/537.36",
+			expectedInfo: DeviceInfo{DeviceType: adcom1.DevicePC, OS: "macOS", OSV: "10.15.7", Make: "Apple", Model: "Macintosh"},
+		},
+		{
+			description:  "Samsung phone",
+			ua:           "Mozilla/5.0 (Linux; Android 13; SM-S908B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Mobile Safari/537.36",
+			expectedInfo: DeviceInfo{DeviceType: adcom1.DevicePhone, OS: "Android", OSV: "13", Make: "Samsung", Model: "SM-S908B"},
+		},
+		{
+			description:  "Pixel phone",
+			ua:           "Mozilla/5.0 (Linux; Android 13; Pixel 7 Pro Build/TQ2A.230405.003) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Mobile Safari/537.36",
+			expectedInfo: DeviceInfo{DeviceType: adcom1.DevicePhone, OS: "Android", OSV: "13", Make: "Google", Model: "Pixel 7 Pro"},
+		},
+		{
+			description:  "Android tablet with an unknown make",
+			ua:           "Mozilla/5.0 (Linux; Android 11; Lenovo TB-X606F) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Safari/537.36",
+			expectedInfo: DeviceInfo{DeviceType: adcom1.DeviceTablet, OS: "Android", OSV: "11", Model: "Lenovo TB-X606F"},
+		},
+		{
+			description:  "Android with a reduced user agent",
+			ua:           "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Mobile Safari/537.36",
+			expectedInfo: DeviceInfo{DeviceType: adcom1.DevicePhone, OS: "Android", OSV: "10"},
+		},
+		{
+			description:  "Windows",
+			ua:           "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Safari/537.36",
+			expectedInfo: DeviceInfo{DeviceType: adcom1.DevicePC, OS: "Windows", OSV: "10.0"},
+		},
+		{
+			description:  "Xbox",
+			ua:           "Mozilla/5.0 (Windows NT 10.0; Win64; x64; Xbox; Xbox One) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/70.0.3538.102 Safari/537.36 Edge/18.19041",
+			expectedInfo: DeviceInfo{DeviceType: adcom1.DeviceConnected, OS: "Windows", OSV: "10.0", Make: "Microsoft", Model: "Xbox One"},
+		},
+		{
+			description:  "Samsung TV",
+			ua:           "Mozilla/5.0 (SMART-TV; LINUX; Tizen 6.0) AppleWebKit/537.36 (KHTML, like Gecko) 76.0.3809.146/6.0 TV Safari/537.36",
+			expectedInfo: DeviceInfo{DeviceType: adcom1.DeviceTV, OS: "Tizen", OSV: "6.0", Make: "Samsung"},
+		},
+		{
+			description:  "Fire TV",
+			ua:           "Mozilla/5.0 (Linux; Android 9; AFTMM Build/PS7233) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Mobile Safari/537.36",
+			expectedInfo: DeviceInfo{DeviceType: adcom1.DeviceTV, OS: "Android", OSV: "9", Make: "Amazon", Model: "Fire TV"},
+		},
+		{
+			description:  "Linux",
+			ua:           "Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/112.0",
+			expectedInfo: DeviceInfo{DeviceType: adcom1.DevicePC, OS: "Linux"},
+		},
+		{
+			description:  "Unknown",
+			ua:           "curl/7.88.1",
+			expectedInfo: DeviceInfo{},
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expectedInfo, detector.Detect(test.ua), test.description)
+	}
+}
+
+func TestNewRuleDetectorErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	testCases := []struct {
+		description   string
+		content       string
+		expectedError string
+	}{
+		{
+			description:   "Malformed file",
+			content:       `{"rules":`,
+			expectedError: "invalid device detection rules file " + filepath.Join(dir, "rules.json") + ": unexpected end of JSON input",
+		},
+		{
+			description:   "Invalid pattern",
+			content:       `{"rules":[{"pattern":"Android"},{"pattern":"("}]}`,
+			expectedError: "invalid pattern of device detection rule 1: error parsing regexp: missing closing ): `(`",
+		},
+	}
+
+	for _, test := range testCases {
+		path := filepath.Join(dir, "rules.json")
+		if err := os.WriteFile(path, []byte(test.content), 0644); err != nil {
+			t.Fatalf("Failed to write the rules file: %v", err)
+		}
+
+		_, err := NewRuleDetector(path)
+		assert.EqualError(t, err, test.expectedError, test.description)
+	}
+
+	_, err := NewRuleDetector(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err, "Missing file")
+}
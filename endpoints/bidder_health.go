@@ -0,0 +1,24 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/bidderhealth"
+)
+
+// NewBidderHealthEndpoint returns the circuit breaker state of the bidders which received requests
+func NewBidderHealthEndpoint(tracker bidderhealth.Tracker) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		jsonOutput, err := json.Marshal(tracker.Status())
+		if err != nil {
+			glog.Errorf("/bidders/health Critical error when trying to marshal bidder health: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
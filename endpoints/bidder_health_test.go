@@ -0,0 +1,64 @@
+package endpoints
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/bidderhealth"
+	"github.com/prebid/prebid-server/metrics"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeBidderHealthTracker struct {
+	bidderhealth.NilTracker
+	status map[openrtb_ext.BidderName]bidderhealth.Status
+}
+
+func (t *fakeBidderHealthTracker) Status() map[openrtb_ext.BidderName]bidderhealth.Status {
+	return t.status
+}
+
+func TestBidderHealthEndpoint(t *testing.T) {
+	var testCases = []struct {
+		description string
+		tracker     bidderhealth.Tracker
+		expected    string
+	}{
+		{
+			description: "Disabled",
+			tracker:     &bidderhealth.NilTracker{},
+			expected:    `{}`,
+		},
+		{
+			description: "Tracked bidders",
+			tracker: &fakeBidderHealthTracker{
+				status: map[openrtb_ext.BidderName]bidderhealth.Status{
+					"appnexus": {
+						State:    metrics.AdapterHealthOpen,
+						Since:    time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+						Requests: 20,
+						Errors:   12,
+						Timeouts: 3,
+					},
+				},
+			},
+			expected: `{"appnexus":{"state":"open","since":"2023-01-01T00:00:00Z","requests":20,"errors":12,"timeouts":3}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		handler := NewBidderHealthEndpoint(test.tracker)
+		w := httptest.NewRecorder()
+
+		handler(w, nil)
+
+		response, err := io.ReadAll(w.Result().Body)
+		if assert.NoError(t, err, test.description+":read") {
+			assert.JSONEq(t, test.expected, string(response), test.description+":response")
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"), test.description+":content-type")
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/prebid/prebid-server/config"
+	pbc "github.com/prebid/prebid-server/prebid_cache_client"
+)
+
+type cachePutRequest struct {
+	Puts []pbc.Cacheable `json:"puts"`
+}
+
+type cachePutResponse struct {
+	Responses []cachePutResponseObject `json:"responses"`
+}
+
+type cachePutResponseObject struct {
+	UUID string `json:"uuid"`
+}
+
+// NewCachePutEndpoint stores values in the embedded cache. It accepts the same requests as Prebid Cache.
+// Requests may only choose the keys of their values if the host allows it.
+func NewCachePutEndpoint(client pbc.Client, cfg *config.Configuration) http.HandlerFunc {
+	embedded := cfg.CacheURL.Embedded
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request cachePutRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, cfg.MaxRequestSize)).Decode(&request); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				w.WriteHeader(http.StatusRequestEntityTooLarge)
+				fmt.Fprintf(w, "Request size exceeded max size of %d bytes.\n", cfg.MaxRequestSize)
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "Invalid request: %v\n", err)
+			return
+		}
+		if len(request.Puts) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid request: puts must not be empty\n"))
+			return
+		}
+		for i, put := range request.Puts {
+			if put.Key != "" && !embedded.AllowSettingKeys {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, "Invalid request: put at index %d sets a key, which is not allowed\n", i)
+				return
+			}
+			if int64(len(put.Data)) > embedded.MaxValueSizeBytes {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, "Invalid request: put at index %d exceeds the max value size of %d bytes\n", i, embedded.MaxValueSizeBytes)
+				return
+			}
+		}
+
+		uuids, errs := client.PutJson(r.Context(), request.Puts)
+		if len(errs) > 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			for _, err := range errs {
+				fmt.Fprintf(w, "Invalid request: %v\n", err)
+			}
+			return
+		}
+
+		response := cachePutResponse{Responses: make([]cachePutResponseObject, len(uuids))}
+		for i, uuid := range uuids {
+			response.Responses[i].UUID = uuid
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// NewCacheGetEndpoint serves the value stored in the embedded cache under the uuid query parameter
+func NewCacheGetEndpoint(store *pbc.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uuid := strings.TrimSpace(r.URL.Query().Get("uuid"))
+		if uuid == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Missing required parameter uuid\n"))
+			return
+		}
+
+		value, ok := store.Get(uuid)
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("No content stored for uuid=" + uuid + "\n"))
+			return
+		}
+
+		if value.Type == pbc.TypeXML {
+			w.Header().Set("Content-Type", "application/xml")
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.Write(value.Data)
+	}
+}
@@ -0,0 +1,151 @@
+package endpoints
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+	metricsConf "github.com/prebid/prebid-server/metrics/config"
+	pbc "github.com/prebid/prebid-server/prebid_cache_client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachePutEndpoint(t *testing.T) {
+	testCases := []struct {
+		description      string
+		body             string
+		allowSettingKeys bool
+		storeFull        bool
+		expectedStatus   int
+		expectedBody     string
+	}{
+		{
+			description:      "Stored",
+			body:             `{"puts":[{"type":"xml","value":"<VAST></VAST>","key":"vast"},{"type":"json","value":{"id":"bid"},"key":"bid"}]}`,
+			allowSettingKeys: true,
+			expectedStatus:   200,
+			expectedBody:     `{"responses":[{"uuid":"vast"},{"uuid":"bid"}]}`,
+		},
+		{
+			description:    "Setting keys not allowed",
+			body:           `{"puts":[{"type":"xml","value":"<VAST></VAST>"},{"type":"json","value":{"id":"bid"},"key":"bid"}]}`,
+			expectedStatus: 400,
+			expectedBody:   "Invalid request: put at index 1 sets a key, which is not allowed\n",
+		},
+		{
+			description:    "Value too large",
+			body:           `{"puts":[{"type":"xml","value":"` + strings.Repeat("a", 64) + `"}]}`,
+			expectedStatus: 400,
+			expectedBody:   "Invalid request: put at index 0 exceeds the max value size of 64 bytes\n",
+		},
+		{
+			description:    "Request too large",
+			body:           `{"puts":[{"type":"xml","value":"` + strings.Repeat("a", 256) + `"}]}`,
+			expectedStatus: 413,
+			expectedBody:   "Request size exceeded max size of 256 bytes.\n",
+		},
+		{
+			description:    "Malformed",
+			body:           `{"puts":`,
+			expectedStatus: 400,
+			expectedBody:   "Invalid request: unexpected EOF\n",
+		},
+		{
+			description:    "Empty",
+			body:           `{"puts":[]}`,
+			expectedStatus: 400,
+			expectedBody:   "Invalid request: puts must not be empty\n",
+		},
+		{
+			description:      "Store full",
+			body:             `{"puts":[{"type":"xml","value":"<VAST></VAST>","key":"vast"}]}`,
+			allowSettingKeys: true,
+			storeFull:        true,
+			expectedStatus:   400,
+			expectedBody:     "Invalid request: Error storing the embedded cache value at index 0 with key vast: the cache is full\n",
+		},
+		{
+			description:    "Invalid value",
+			body:           `{"puts":[{"type":"xml","value":{}}]}`,
+			expectedStatus: 400,
+			expectedBody:   "Invalid request: Embedded cache value at index 0 is not valid: json: cannot unmarshal object into Go value of type string\n",
+		},
+	}
+
+	for _, test := range testCases {
+		cfg := &config.Configuration{MaxRequestSize: 256}
+		cfg.CacheURL.Embedded = config.EmbeddedCache{Enabled: true, MaxSizeBytes: 1024, DefaultTTLSeconds: 300, MaxTTLSeconds: 3600, MaxValueSizeBytes: 64, AllowSettingKeys: test.allowSettingKeys}
+		store := pbc.NewStore(cfg.CacheURL.Embedded.MaxSizeBytes)
+		if test.storeFull {
+			store.Put("auction", pbc.StoredValue{Type: pbc.TypeJSON, Data: []byte(strings.Repeat("1", 1010))}, time.Hour)
+		}
+		client := pbc.NewLocalUploadClient(store, &cfg.CacheURL.Embedded, &metricsConf.NilMetricsEngine{})
+		handler := NewCachePutEndpoint(client, cfg)
+		w := httptest.NewRecorder()
+
+		handler(w, httptest.NewRequest("POST", "/cache", strings.NewReader(test.body)))
+
+		assert.Equal(t, test.expectedStatus, w.Code, test.description)
+		if test.expectedStatus == 200 {
+			assert.JSONEq(t, test.expectedBody, w.Body.String(), test.description)
+		} else {
+			assert.Equal(t, test.expectedBody, w.Body.String(), test.description)
+		}
+	}
+}
+
+func TestCacheGetEndpoint(t *testing.T) {
+	store := pbc.NewStore(1024)
+	store.Put("vast", pbc.StoredValue{Type: pbc.TypeXML, Data: []byte("<VAST></VAST>")}, time.Minute)
+	store.Put("bid", pbc.StoredValue{Type: pbc.TypeJSON, Data: []byte(`{"id":"bid"}`)}, time.Minute)
+
+	testCases := []struct {
+		description         string
+		url                 string
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			description:         "XML",
+			url:                 "/cache?uuid=vast",
+			expectedStatus:      200,
+			expectedContentType: "application/xml",
+			expectedBody:        "<VAST></VAST>",
+		},
+		{
+			description:         "JSON",
+			url:                 "/cache?uuid=bid",
+			expectedStatus:      200,
+			expectedContentType: "application/json",
+			expectedBody:        `{"id":"bid"}`,
+		},
+		{
+			description:    "Not found",
+			url:            "/cache?uuid=unknown",
+			expectedStatus: 404,
+			expectedBody:   "No content stored for uuid=unknown\n",
+		},
+		{
+			description:    "Missing uuid",
+			url:            "/cache",
+			expectedStatus: 400,
+			expectedBody:   "Missing required parameter uuid\n",
+		},
+	}
+
+	for _, test := range testCases {
+		handler := NewCacheGetEndpoint(store)
+		w := httptest.NewRecorder()
+
+		handler(w, httptest.NewRequest("GET", test.url, nil))
+
+		assert.Equal(t, test.expectedStatus, w.Code, test.description)
+		assert.Equal(t, test.expectedBody, w.Body.String(), test.description)
+		if test.expectedContentType != "" {
+			assert.Equal(t, test.expectedContentType, w.Header().Get("Content-Type"), test.description)
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/prebid/prebid-server/privacy"
 	"github.com/prebid/prebid-server/privacy/ccpa"
 	gdprPrivacy "github.com/prebid/prebid-server/privacy/gdpr"
+	gppPrivacy "github.com/prebid/prebid-server/privacy/gpp"
 	"github.com/prebid/prebid-server/stored_requests"
 	"github.com/prebid/prebid-server/usersync"
 )
@@ -62,6 +63,7 @@ func NewCookieSyncEndpoint(
 			gdprPermissionsBuilder: gdprPermsBuilder,
 			tcf2ConfigBuilder:      tcf2CfgBuilder,
 			ccpaEnforce:            config.CCPA.Enforce,
+			gppEnforce:             config.GPP.Enforce,
 			bidderHashSet:          bidderHashSet,
 		},
 		metrics:         metrics,
@@ -96,11 +98,11 @@ func (c *cookieSyncEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ ht
 		c.handleError(w, errCookieSyncOptOut, http.StatusUnauthorized)
 	case usersync.StatusBlockedByGDPR:
 		c.metrics.RecordCookieSync(metrics.CookieSyncGDPRHostCookieBlocked)
-		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyPolicies, nil)
+		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyPolicies, nil, nil, request.Debug)
 	case usersync.StatusOK:
 		c.metrics.RecordCookieSync(metrics.CookieSyncOK)
 		c.writeBidderMetrics(result.BiddersEvaluated)
-		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyPolicies, result.SyncersChosen)
+		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyPolicies, result.SyncersChosen, result.BiddersEvaluated, request.Debug)
 	}
 }
 
@@ -143,6 +145,11 @@ func (c *cookieSyncEndpoint) parseRequest(r *http.Request) (usersync.Request, pr
 		}
 	}
 
+	gppSID, err := gppPrivacy.ParseSID(request.GPPSID)
+	if err != nil {
+		return usersync.Request{}, privacy.Policies{}, err
+	}
+
 	request = c.setLimit(request, account.CookieSync)
 	request = c.setCooperativeSync(request, account.CookieSync)
 
@@ -154,6 +161,10 @@ func (c *cookieSyncEndpoint) parseRequest(r *http.Request) (usersync.Request, pr
 		CCPA: ccpa.Policy{
 			Consent: request.USPrivacy,
 		},
+		GPP: gppPrivacy.Policy{
+			Consent: request.GPP,
+			SID:     gppSID,
+		},
 	}
 
 	ccpaParsedPolicy := ccpa.ParsedPolicy{}
@@ -167,10 +178,22 @@ func (c *cookieSyncEndpoint) parseRequest(r *http.Request) (usersync.Request, pr
 		}
 	}
 
+	gppParsedPolicy := gppPrivacy.ParsedPolicy{}
+	if request.GPP != "" {
+		parsedPolicy, err := privacyPolicies.GPP.Parse()
+		if err != nil {
+			privacyPolicies.GPP = gppPrivacy.Policy{}
+		}
+		if c.privacyConfig.gppEnforce {
+			gppParsedPolicy = parsedPolicy
+		}
+	}
+
 	syncTypeFilter, err := parseTypeFilter(request.FilterSettings)
 	if err != nil {
 		return usersync.Request{}, privacy.Policies{}, err
 	}
+	syncTypeFilter.Preferred = usersync.SyncType(account.CookieSync.PreferredSyncType)
 
 	gdprRequestInfo := gdpr.RequestInfo{
 		Consent:    request.GDPRConsent,
@@ -190,8 +213,14 @@ func (c *cookieSyncEndpoint) parseRequest(r *http.Request) (usersync.Request, pr
 		Privacy: usersyncPrivacy{
 			gdprPermissions:  gdprPerms,
 			ccpaParsedPolicy: ccpaParsedPolicy,
+			gppParsedPolicy:  gppParsedPolicy,
+			activityControl:  privacy.NewActivityControl(account.Activities),
+			activityRequest:  privacy.ActivityRequest{GPPSID: privacyPolicies.GPP.SID},
 		},
-		SyncTypeFilter: syncTypeFilter,
+		SyncTypeFilter:        syncTypeFilter,
+		AccountFilter:         parseAccountBidderFilter(account.CookieSync),
+		AccountPriorityGroups: account.CookieSync.PriorityGroups,
+		Debug:                 request.Debug,
 	}
 	return rx, privacyPolicies, nil
 }
@@ -254,6 +283,26 @@ func parseTypeFilter(request *cookieSyncRequestFilterSettings) (usersync.SyncTyp
 	return syncTypeFilter, nil
 }
 
+// parseAccountBidderFilter returns the filter of the bidders the account allows to sync, or nil if it allows all
+// of them. The excluded bidders take precedence over the included ones.
+func parseAccountBidderFilter(cookieSync config.CookieSync) usersync.BidderFilter {
+	if len(cookieSync.IncludeBidders) > 0 {
+		excluded := usersync.NewSpecificBidderFilter(cookieSync.ExcludeBidders, usersync.BidderFilterModeExclude)
+		included := make([]string, 0, len(cookieSync.IncludeBidders))
+		for _, bidder := range cookieSync.IncludeBidders {
+			if excluded.Allowed(bidder) {
+				included = append(included, bidder)
+			}
+		}
+		return usersync.NewSpecificBidderFilter(included, usersync.BidderFilterModeInclude)
+	}
+
+	if len(cookieSync.ExcludeBidders) > 0 {
+		return usersync.NewSpecificBidderFilter(cookieSync.ExcludeBidders, usersync.BidderFilterModeExclude)
+	}
+	return nil
+}
+
 func parseBidderFilter(filter *cookieSyncRequestFilter) (usersync.BidderFilter, error) {
 	if filter == nil {
 		return cookieSyncBidderFilterAllowAll, nil
@@ -327,15 +376,21 @@ func (c *cookieSyncEndpoint) writeBidderMetrics(biddersEvaluated []usersync.Bidd
 			c.metrics.RecordSyncerRequest(bidder.SyncerKey, metrics.SyncerCookieSyncPrivacyBlocked)
 		case usersync.StatusBlockedByCCPA:
 			c.metrics.RecordSyncerRequest(bidder.SyncerKey, metrics.SyncerCookieSyncPrivacyBlocked)
+		case usersync.StatusBlockedByGPP:
+			c.metrics.RecordSyncerRequest(bidder.SyncerKey, metrics.SyncerCookieSyncPrivacyBlocked)
+		case usersync.StatusBlockedByActivity:
+			c.metrics.RecordSyncerRequest(bidder.SyncerKey, metrics.SyncerCookieSyncPrivacyBlocked)
 		case usersync.StatusAlreadySynced:
 			c.metrics.RecordSyncerRequest(bidder.SyncerKey, metrics.SyncerCookieSyncAlreadySynced)
 		case usersync.StatusTypeNotSupported:
 			c.metrics.RecordSyncerRequest(bidder.SyncerKey, metrics.SyncerCookieSyncTypeNotSupported)
+		case usersync.StatusBlockedByAccount:
+			c.metrics.RecordSyncerRequest(bidder.SyncerKey, metrics.SyncerCookieSyncAccountBlocked)
 		}
 	}
 }
 
-func (c *cookieSyncEndpoint) handleResponse(w http.ResponseWriter, tf usersync.SyncTypeFilter, co *usersync.Cookie, p privacy.Policies, s []usersync.SyncerChoice) {
+func (c *cookieSyncEndpoint) handleResponse(w http.ResponseWriter, tf usersync.SyncTypeFilter, co *usersync.Cookie, p privacy.Policies, s []usersync.SyncerChoice, biddersEvaluated []usersync.BidderEvaluation, debug bool) {
 	status := "no_cookie"
 	if co.HasAnyLiveSyncs() {
 		status = "ok"
@@ -347,7 +402,7 @@ func (c *cookieSyncEndpoint) handleResponse(w http.ResponseWriter, tf usersync.S
 	}
 
 	for _, syncerChoice := range s {
-		syncTypes := tf.ForBidder(syncerChoice.Bidder)
+		syncTypes := tf.ForSyncer(syncerChoice.Bidder, syncerChoice.Syncer)
 		sync, err := syncerChoice.Syncer.GetSync(syncTypes, p)
 		if err != nil {
 			glog.Errorf("Failed to get usersync info for %s: %v", syncerChoice.Bidder, err)
@@ -365,6 +420,14 @@ func (c *cookieSyncEndpoint) handleResponse(w http.ResponseWriter, tf usersync.S
 		})
 	}
 
+	if debug {
+		for _, bidder := range biddersEvaluated {
+			if message := getDebugMessage(bidder.Status); message != "" {
+				response.Debug = append(response.Debug, cookieSyncResponseDebug{Bidder: bidder.Bidder, Error: message})
+			}
+		}
+	}
+
 	c.pbsAnalytics.LogCookieSyncObject(&analytics.CookieSyncObject{
 		Status:       http.StatusOK,
 		BidderStatus: mapBidderStatusToAnalytics(response.BidderStatus),
@@ -376,6 +439,25 @@ func (c *cookieSyncEndpoint) handleResponse(w http.ResponseWriter, tf usersync.S
 	enc.Encode(response)
 }
 
+// getDebugMessage returns why a bidder with the status wasn't synced, or an empty string if it was.
+func getDebugMessage(status usersync.Status) string {
+	switch status {
+	case usersync.StatusBlockedByGDPR, usersync.StatusBlockedByCCPA, usersync.StatusBlockedByGPP, usersync.StatusBlockedByActivity:
+		return "Rejected by privacy"
+	case usersync.StatusAlreadySynced:
+		return "Already in sync"
+	case usersync.StatusUnknownBidder:
+		return "Unsupported bidder"
+	case usersync.StatusTypeNotSupported:
+		return "Type not supported"
+	case usersync.StatusDuplicate:
+		return "Duplicate bidder"
+	case usersync.StatusBlockedByAccount:
+		return "Excluded by the account cookie sync rules"
+	}
+	return ""
+}
+
 func mapBidderStatusToAnalytics(from []cookieSyncResponseBidder) []*analytics.CookieSyncBidder {
 	to := make([]*analytics.CookieSyncBidder, len(from))
 	for i, b := range from {
@@ -397,10 +479,13 @@ type cookieSyncRequest struct {
 	GDPR            *int                             `json:"gdpr"`
 	GDPRConsent     string                           `json:"gdpr_consent"`
 	USPrivacy       string                           `json:"us_privacy"`
+	GPP             string                           `json:"gpp"`
+	GPPSID          string                           `json:"gpp_sid"`
 	Limit           int                              `json:"limit"`
 	CooperativeSync *bool                            `json:"coopSync"`
 	FilterSettings  *cookieSyncRequestFilterSettings `json:"filterSettings"`
 	Account         string                           `json:"account"`
+	Debug           bool                             `json:"debug"`
 }
 
 type cookieSyncRequestFilterSettings struct {
@@ -416,6 +501,7 @@ type cookieSyncRequestFilter struct {
 type cookieSyncResponse struct {
 	Status       string                     `json:"status"`
 	BidderStatus []cookieSyncResponseBidder `json:"bidder_status"`
+	Debug        []cookieSyncResponseDebug  `json:"debug,omitempty"`
 }
 
 type cookieSyncResponseBidder struct {
@@ -424,6 +510,11 @@ type cookieSyncResponseBidder struct {
 	UsersyncInfo cookieSyncResponseSync `json:"usersync,omitempty"`
 }
 
+type cookieSyncResponseDebug struct {
+	Bidder string `json:"bidder"`
+	Error  string `json:"error,omitempty"`
+}
+
 type cookieSyncResponseSync struct {
 	URL         string `json:"url,omitempty"`
 	Type        string `json:"type,omitempty"`
@@ -435,12 +526,16 @@ type usersyncPrivacyConfig struct {
 	gdprPermissionsBuilder gdpr.PermissionsBuilder
 	tcf2ConfigBuilder      gdpr.TCF2ConfigBuilder
 	ccpaEnforce            bool
+	gppEnforce             bool
 	bidderHashSet          map[string]struct{}
 }
 
 type usersyncPrivacy struct {
 	gdprPermissions  gdpr.Permissions
 	ccpaParsedPolicy ccpa.ParsedPolicy
+	gppParsedPolicy  gppPrivacy.ParsedPolicy
+	activityControl  privacy.ActivityControl
+	activityRequest  privacy.ActivityRequest
 }
 
 func (p usersyncPrivacy) GDPRAllowsHostCookie() bool {
@@ -457,3 +552,15 @@ func (p usersyncPrivacy) CCPAAllowsBidderSync(bidder string) bool {
 	enforce := p.ccpaParsedPolicy.CanEnforce() && p.ccpaParsedPolicy.ShouldEnforce(bidder)
 	return !enforce
 }
+
+func (p usersyncPrivacy) GPPAllowsBidderSync(bidder string) bool {
+	saleOrSharing := p.gppParsedPolicy.Enforcer(gppPrivacy.RestrictionSaleOrSharing)
+	knownChild := p.gppParsedPolicy.Enforcer(gppPrivacy.RestrictionKnownChild)
+	enforce := saleOrSharing.CanEnforce() && (saleOrSharing.ShouldEnforce(bidder) || knownChild.ShouldEnforce(bidder))
+	return !enforce
+}
+
+func (p usersyncPrivacy) ActivityAllowsUserSync(bidder string) bool {
+	component := privacy.Component{Type: config.ComponentTypeBidder, Name: bidder}
+	return p.activityControl.Allow(privacy.ActivitySyncUser, component, p.activityRequest)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/prebid/prebid-server/privacy"
 	"github.com/prebid/prebid-server/privacy/ccpa"
 	gdprPrivacy "github.com/prebid/prebid-server/privacy/gdpr"
+	gppPrivacy "github.com/prebid/prebid-server/privacy/gpp"
 	"github.com/prebid/prebid-server/usersync"
 
 	"github.com/stretchr/testify/assert"
@@ -305,6 +306,7 @@ func TestCookieSyncHandle(t *testing.T) {
 
 func TestCookieSyncParseRequest(t *testing.T) {
 	expectedCCPAParsedPolicy, _ := ccpa.Policy{Consent: "1NYN"}.Parse(map[string]struct{}{})
+	expectedGPPParsedPolicy, _ := gppPrivacy.Policy{Consent: "DBABLA~BVVaqqqqCaA", SID: []int8{7}}.Parse()
 
 	testCases := []struct {
 		description          string
@@ -752,6 +754,30 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "Account Rules",
+			givenBody: strings.NewReader(`{` +
+				`"bidders":["a", "b"],` +
+				`"filterSettings":{"image":{"bidders":["b"],"filter":"exclude"}},` +
+				`"account":"RulesAccount"` +
+				`}`),
+			givenGDPRConfig:  config.GDPR{Enabled: true, DefaultValue: "0"},
+			givenCCPAEnabled: true,
+			expectedPrivacy:  privacy.Policies{},
+			expectedRequest: usersync.Request{
+				Bidders: []string{"a", "b"},
+				Privacy: usersyncPrivacy{
+					gdprPermissions: &fakePermissions{},
+				},
+				SyncTypeFilter: usersync.SyncTypeFilter{
+					IFrame:    usersync.NewUniformBidderFilter(usersync.BidderFilterModeInclude),
+					Redirect:  usersync.NewSpecificBidderFilter([]string{"b"}, usersync.BidderFilterModeExclude),
+					Preferred: usersync.SyncTypeRedirect,
+				},
+				AccountFilter:         usersync.NewSpecificBidderFilter([]string{"c"}, usersync.BidderFilterModeExclude),
+				AccountPriorityGroups: [][]string{{"d"}},
+			},
+		},
 		{
 			description: "Account Defaults - Error",
 			givenBody: strings.NewReader(`{` +
@@ -785,6 +811,63 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			expectedError:        errCookieSyncAccountBlocked.Error(),
 			givenAccountRequired: true,
 		},
+		{
+			description: "GPP",
+			givenBody: strings.NewReader(`{` +
+				`"bidders":["a", "b"],` +
+				`"gpp":"DBABLA~BVVaqqqqCaA",` +
+				`"gpp_sid":"7"` +
+				`}`),
+			givenGDPRConfig: config.GDPR{Enabled: true, DefaultValue: "0"},
+			expectedPrivacy: privacy.Policies{
+				GPP: gppPrivacy.Policy{
+					Consent: "DBABLA~BVVaqqqqCaA",
+					SID:     []int8{7},
+				},
+			},
+			expectedRequest: usersync.Request{
+				Bidders: []string{"a", "b"},
+				Privacy: usersyncPrivacy{
+					gdprPermissions: &fakePermissions{},
+					gppParsedPolicy: expectedGPPParsedPolicy,
+					activityRequest: privacy.ActivityRequest{GPPSID: []int8{7}},
+				},
+				SyncTypeFilter: usersync.SyncTypeFilter{
+					IFrame:   usersync.NewUniformBidderFilter(usersync.BidderFilterModeInclude),
+					Redirect: usersync.NewUniformBidderFilter(usersync.BidderFilterModeInclude),
+				},
+			},
+		},
+		{
+			description: "GPP - Malformed",
+			givenBody: strings.NewReader(`{` +
+				`"bidders":["a", "b"],` +
+				`"gpp":"malformed",` +
+				`"gpp_sid":"7"` +
+				`}`),
+			givenGDPRConfig: config.GDPR{Enabled: true, DefaultValue: "0"},
+			expectedPrivacy: privacy.Policies{},
+			expectedRequest: usersync.Request{
+				Bidders: []string{"a", "b"},
+				Privacy: usersyncPrivacy{
+					gdprPermissions: &fakePermissions{},
+				},
+				SyncTypeFilter: usersync.SyncTypeFilter{
+					IFrame:   usersync.NewUniformBidderFilter(usersync.BidderFilterModeInclude),
+					Redirect: usersync.NewUniformBidderFilter(usersync.BidderFilterModeInclude),
+				},
+			},
+		},
+		{
+			description: "GPP SID - Invalid",
+			givenBody: strings.NewReader(`{` +
+				`"bidders":["a", "b"],` +
+				`"gpp":"DBABLA~BVVaqqqqCaA",` +
+				`"gpp_sid":"a"` +
+				`}`),
+			givenGDPRConfig: config.GDPR{Enabled: true, DefaultValue: "0"},
+			expectedError:   "gpp_sid a is invalid: a is not a section id",
+		},
 	}
 
 	for _, test := range testCases {
@@ -807,9 +890,11 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				gdprPermissionsBuilder: gdprPermsBuilder,
 				tcf2ConfigBuilder:      tcf2ConfigBuilder,
 				ccpaEnforce:            test.givenCCPAEnabled,
+				gppEnforce:             true,
 			},
 			accountsFetcher: FakeAccountsFetcher{AccountData: map[string]json.RawMessage{
 				"TestAccount":     json.RawMessage(`{"cookie_sync": {"default_limit": 20, "max_limit": 30, "default_coop_sync": true}}`),
+				"RulesAccount":    json.RawMessage(`{"cookie_sync": {"exclude_bidders": ["c"], "priority_groups": [["d"]], "preferred_sync_type": "redirect"}}`),
 				"DisabledAccount": json.RawMessage(`{"disabled":true}`),
 			}},
 		}
@@ -1203,6 +1288,55 @@ func TestParseTypeFilter(t *testing.T) {
 	}
 }
 
+func TestParseAccountBidderFilter(t *testing.T) {
+	testCases := []struct {
+		description      string
+		givenCookieSync  config.CookieSync
+		expectedAllowed  []string
+		expectedBlocked  []string
+		expectedNoFilter bool
+	}{
+		{
+			description:      "No Rules",
+			givenCookieSync:  config.CookieSync{},
+			expectedNoFilter: true,
+		},
+		{
+			description:     "Include",
+			givenCookieSync: config.CookieSync{IncludeBidders: []string{"a", "b"}},
+			expectedAllowed: []string{"a", "b"},
+			expectedBlocked: []string{"c"},
+		},
+		{
+			description:     "Exclude",
+			givenCookieSync: config.CookieSync{ExcludeBidders: []string{"a"}},
+			expectedAllowed: []string{"b", "c"},
+			expectedBlocked: []string{"a"},
+		},
+		{
+			description:     "Include And Exclude",
+			givenCookieSync: config.CookieSync{IncludeBidders: []string{"a", "b"}, ExcludeBidders: []string{"b"}},
+			expectedAllowed: []string{"a"},
+			expectedBlocked: []string{"b", "c"},
+		},
+	}
+
+	for _, test := range testCases {
+		filter := parseAccountBidderFilter(test.givenCookieSync)
+		if test.expectedNoFilter {
+			assert.Nil(t, filter, test.description)
+			continue
+		}
+
+		for _, bidder := range test.expectedAllowed {
+			assert.True(t, filter.Allowed(bidder), test.description+":"+bidder)
+		}
+		for _, bidder := range test.expectedBlocked {
+			assert.False(t, filter.Allowed(bidder), test.description+":"+bidder)
+		}
+	}
+}
+
 func TestParseBidderFilter(t *testing.T) {
 	testCases := []struct {
 		description    string
@@ -1327,6 +1461,20 @@ func TestCookieSyncWriteBidderMetrics(t *testing.T) {
 				m.On("RecordSyncerRequest", "aSyncer", metrics.SyncerCookieSyncPrivacyBlocked).Once()
 			},
 		},
+		{
+			description: "One - Blocked By GPP",
+			given:       []usersync.BidderEvaluation{{Bidder: "a", SyncerKey: "aSyncer", Status: usersync.StatusBlockedByGPP}},
+			setExpectations: func(m *metrics.MetricsEngineMock) {
+				m.On("RecordSyncerRequest", "aSyncer", metrics.SyncerCookieSyncPrivacyBlocked).Once()
+			},
+		},
+		{
+			description: "One - Blocked By Activity Control",
+			given:       []usersync.BidderEvaluation{{Bidder: "a", SyncerKey: "aSyncer", Status: usersync.StatusBlockedByActivity}},
+			setExpectations: func(m *metrics.MetricsEngineMock) {
+				m.On("RecordSyncerRequest", "aSyncer", metrics.SyncerCookieSyncPrivacyBlocked).Once()
+			},
+		},
 		{
 			description: "One - Already Synced",
 			given:       []usersync.BidderEvaluation{{Bidder: "a", SyncerKey: "aSyncer", Status: usersync.StatusAlreadySynced}},
@@ -1341,6 +1489,13 @@ func TestCookieSyncWriteBidderMetrics(t *testing.T) {
 				m.On("RecordSyncerRequest", "aSyncer", metrics.SyncerCookieSyncTypeNotSupported).Once()
 			},
 		},
+		{
+			description: "One - Blocked By Account",
+			given:       []usersync.BidderEvaluation{{Bidder: "a", SyncerKey: "aSyncer", Status: usersync.StatusBlockedByAccount}},
+			setExpectations: func(m *metrics.MetricsEngineMock) {
+				m.On("RecordSyncerRequest", "aSyncer", metrics.SyncerCookieSyncAccountBlocked).Once()
+			},
+		},
 		{
 			description: "Many",
 			given: []usersync.BidderEvaluation{
@@ -1388,11 +1543,13 @@ func TestCookieSyncHandleResponse(t *testing.T) {
 	syncerWithError.On("GetSync", syncTypeExpected, privacyPolicies).Return(syncWithError, errors.New("anyError")).Maybe()
 
 	testCases := []struct {
-		description         string
-		givenCookieHasSyncs bool
-		givenSyncersChosen  []usersync.SyncerChoice
-		expectedJSON        string
-		expectedAnalytics   analytics.CookieSyncObject
+		description           string
+		givenCookieHasSyncs   bool
+		givenSyncersChosen    []usersync.SyncerChoice
+		givenBiddersEvaluated []usersync.BidderEvaluation
+		givenDebug            bool
+		expectedJSON          string
+		expectedAnalytics     analytics.CookieSyncObject
 	}{
 		{
 			description:         "None",
@@ -1461,6 +1618,40 @@ func TestCookieSyncHandleResponse(t *testing.T) {
 				},
 			},
 		},
+		{
+			description:         "Debug",
+			givenCookieHasSyncs: true,
+			givenSyncersChosen:  []usersync.SyncerChoice{{Bidder: "foo", Syncer: &syncerA}},
+			givenBiddersEvaluated: []usersync.BidderEvaluation{
+				{Bidder: "foo", Status: usersync.StatusOK},
+				{Bidder: "bar", Status: usersync.StatusBlockedByAccount},
+				{Bidder: "baz", Status: usersync.StatusAlreadySynced},
+			},
+			givenDebug: true,
+			expectedJSON: `{"status":"ok","bidder_status":[` +
+				`{"bidder":"foo","no_cookie":true,"usersync":{"url":"https://syncA.com/sync?a=1&b=2","type":"redirect","supportCORS":true}}` +
+				`],"debug":[{"bidder":"bar","error":"Excluded by the account cookie sync rules"},{"bidder":"baz","error":"Already in sync"}]}` + "\n",
+			expectedAnalytics: analytics.CookieSyncObject{
+				Status: 200,
+				BidderStatus: []*analytics.CookieSyncBidder{
+					{
+						BidderCode:   "foo",
+						NoCookie:     true,
+						UsersyncInfo: &analytics.UsersyncInfo{URL: "https://syncA.com/sync?a=1&b=2", Type: "redirect", SupportCORS: true},
+					},
+				},
+			},
+		},
+		{
+			description:         "Debug Not Requested",
+			givenCookieHasSyncs: true,
+			givenSyncersChosen:  []usersync.SyncerChoice{},
+			givenBiddersEvaluated: []usersync.BidderEvaluation{
+				{Bidder: "bar", Status: usersync.StatusBlockedByAccount},
+			},
+			expectedJSON:      `{"status":"ok","bidder_status":[]}` + "\n",
+			expectedAnalytics: analytics.CookieSyncObject{Status: 200, BidderStatus: []*analytics.CookieSyncBidder{}},
+		},
 		{
 			description:         "No Existing Syncs",
 			givenCookieHasSyncs: false,
@@ -1483,7 +1674,7 @@ func TestCookieSyncHandleResponse(t *testing.T) {
 
 		writer := httptest.NewRecorder()
 		endpoint := cookieSyncEndpoint{pbsAnalytics: &mockAnalytics}
-		endpoint.handleResponse(writer, syncTypeFilter, cookie, privacyPolicies, test.givenSyncersChosen)
+		endpoint.handleResponse(writer, syncTypeFilter, cookie, privacyPolicies, test.givenSyncersChosen, test.givenBiddersEvaluated, test.givenDebug)
 
 		if assert.Equal(t, writer.Code, http.StatusOK, test.description+":http_status") {
 			assert.Equal(t, writer.Header().Get("Content-Type"), "application/json; charset=utf-8", test.description+":http_header")
@@ -1683,6 +1874,115 @@ func TestUsersyncPrivacyCCPAAllowsBidderSync(t *testing.T) {
 	}
 }
 
+func TestUsersyncPrivacyGPPAllowsBidderSync(t *testing.T) {
+	testCases := []struct {
+		description string
+		givenGPP    string
+		givenSID    []int8
+		expected    bool
+	}{
+		{
+			description: "Allowed - No Opt-Out",
+			givenGPP:    "DBABLA~BVVqqqqqCaA",
+			givenSID:    []int8{7},
+			expected:    true,
+		},
+		{
+			description: "Not Allowed - Opt-Out",
+			givenGPP:    "DBABLA~BVVaqqqqCaA",
+			givenSID:    []int8{7},
+			expected:    false,
+		},
+		{
+			description: "Not Allowed - Known Child",
+			givenGPP:    "DBABRg~BVqqqlo",
+			givenSID:    []int8{9},
+			expected:    false,
+		},
+		{
+			description: "Allowed - Sensitive Data Limited",
+			givenGPP:    "DBABBg~BVqmqoJo",
+			givenSID:    []int8{8},
+			expected:    true,
+		},
+		{
+			description: "Allowed - Section Not Applicable",
+			givenGPP:    "DBABLA~BVVaqqqqCaA",
+			givenSID:    []int8{8},
+			expected:    true,
+		},
+		{
+			description: "Not Specified",
+			givenGPP:    "",
+			expected:    true,
+		},
+	}
+
+	for _, test := range testCases {
+		parsedPolicy, err := gppPrivacy.Policy{Consent: test.givenGPP, SID: test.givenSID}.Parse()
+
+		if assert.NoError(t, err) {
+			privacy := usersyncPrivacy{gppParsedPolicy: parsedPolicy}
+			result := privacy.GPPAllowsBidderSync("foo")
+			assert.Equal(t, test.expected, result, test.description)
+		}
+	}
+}
+
+func TestUsersyncPrivacyActivityAllowsUserSync(t *testing.T) {
+	denyFooInCalifornia := config.AccountActivities{
+		SyncUser: config.Activity{Rules: []config.ActivityRule{
+			{Condition: config.ActivityCondition{ComponentName: []string{"foo"}, GPPSID: []int8{8}}, Allow: false},
+		}},
+	}
+
+	testCases := []struct {
+		description     string
+		givenActivities config.AccountActivities
+		givenSID        []int8
+		givenBidder     string
+		expected        bool
+	}{
+		{
+			description:     "Allowed - No Activities",
+			givenActivities: config.AccountActivities{},
+			givenSID:        []int8{8},
+			givenBidder:     "foo",
+			expected:        true,
+		},
+		{
+			description:     "Not Allowed - Rule Matches",
+			givenActivities: denyFooInCalifornia,
+			givenSID:        []int8{8},
+			givenBidder:     "foo",
+			expected:        false,
+		},
+		{
+			description:     "Allowed - Other Bidder",
+			givenActivities: denyFooInCalifornia,
+			givenSID:        []int8{8},
+			givenBidder:     "bar",
+			expected:        true,
+		},
+		{
+			description:     "Allowed - Other Section",
+			givenActivities: denyFooInCalifornia,
+			givenSID:        []int8{7},
+			givenBidder:     "foo",
+			expected:        true,
+		},
+	}
+
+	for _, test := range testCases {
+		privacy := usersyncPrivacy{
+			activityControl: privacy.NewActivityControl(test.givenActivities),
+			activityRequest: privacy.ActivityRequest{GPPSID: test.givenSID},
+		}
+		result := privacy.ActivityAllowsUserSync(test.givenBidder)
+		assert.Equal(t, test.expected, result, test.description)
+	}
+}
+
 func TestCombineErrors(t *testing.T) {
 	testCases := []struct {
 		description    string
@@ -1764,6 +2064,16 @@ func (m *MockSyncer) GetSync(syncTypes []usersync.SyncType, privacyPolicies priv
 	return args.Get(0).(usersync.Sync), args.Error(1)
 }
 
+func (m *MockSyncer) UIDTTL() time.Duration {
+	args := m.Called()
+	return args.Get(0).(time.Duration)
+}
+
+func (m *MockSyncer) Priority() int {
+	args := m.Called()
+	return args.Int(0)
+}
+
 type MockAnalytics struct {
 	mock.Mock
 }
@@ -55,11 +55,11 @@ func newCurrencyRatesInfo(rateConverter rateConverter, fetchingInterval time.Dur
 	return currencyRatesInfo
 }
 
-// NewCurrencyRatesEndpoint returns current currency rates applied by the PBS server.
+// NewCurrencyRatesEndpoint returns current currency rates applied by the PBS server. The info is read on each
+// request since the converter may fail over to another rates source.
 func NewCurrencyRatesEndpoint(rateConverter rateConverter, fetchingInterval time.Duration) http.HandlerFunc {
-	currencyRateInfo := newCurrencyRatesInfo(rateConverter, fetchingInterval)
-
 	return func(w http.ResponseWriter, _ *http.Request) {
+		currencyRateInfo := newCurrencyRatesInfo(rateConverter, fetchingInterval)
 		jsonOutput, err := json.Marshal(currencyRateInfo)
 		if err != nil {
 			glog.Errorf("/currency/rates Critical error when trying to marshal currencyRateInfo: %v", err)
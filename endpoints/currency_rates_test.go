@@ -1,6 +1,7 @@
 package endpoints
 
 import (
+	"encoding/json"
 	"math/cmplx"
 	"net/http"
 	"net/http/httptest"
@@ -106,6 +107,46 @@ func TestCurrencyRatesEndpoint(t *testing.T) {
 	}
 }
 
+func TestCurrencyRatesEndpointActiveSource(t *testing.T) {
+	rateConverter := currency.NewRateConverterWithSources([]currency.RatesSource{
+		currency.NewFileRatesSource("primary", "missing.json"),
+		currency.NewStaticRatesSource("backup", map[string]map[string]float64{"USD": {"EUR": 0.9}}),
+	}, 24*time.Hour)
+	handler := NewCurrencyRatesEndpoint(rateConverter, time.Duration(0))
+
+	// The rates are fetched after the endpoint is built, it should still report the active source
+	rateConverter.Run()
+	w := httptest.NewRecorder()
+	handler(w, nil)
+
+	var info struct {
+		Source         string `json:"source"`
+		AdditionalInfo struct {
+			ActiveSource string `json:"activeSource"`
+			Sources      []struct {
+				Name   string `json:"name"`
+				Active bool   `json:"active"`
+				Stale  bool   `json:"stale"`
+			} `json:"sources"`
+		} `json:"additionalInfo"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &info); err != nil {
+		t.Fatalf("Failed to parse the response: %v", err)
+	}
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "static", info.Source)
+	assert.Equal(t, "backup", info.AdditionalInfo.ActiveSource)
+	if assert.Len(t, info.AdditionalInfo.Sources, 2) {
+		assert.Equal(t, "primary", info.AdditionalInfo.Sources[0].Name)
+		assert.False(t, info.AdditionalInfo.Sources[0].Active)
+		assert.True(t, info.AdditionalInfo.Sources[0].Stale)
+		assert.Equal(t, "backup", info.AdditionalInfo.Sources[1].Name)
+		assert.True(t, info.AdditionalInfo.Sources[1].Active)
+		assert.False(t, info.AdditionalInfo.Sources[1].Stale)
+	}
+}
+
 type conversionMock struct {
 	rates *map[string]map[string]float64
 }
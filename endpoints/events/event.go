@@ -33,6 +33,7 @@ const (
 	FormatParameter          = "f"
 	AnalyticsParameter       = "x"
 	IntegrationTypeParameter = "int"
+	PriceParameter           = "price"
 )
 
 const integrationParamMaxLength = 64
@@ -177,6 +178,11 @@ func ParseEventRequest(r *http.Request) (*analytics.EventRequest, []error) {
 		errs = append(errs, err)
 	}
 
+	// validate price (optional)
+	if err := readPrice(event, r); err != nil {
+		errs = append(errs, err)
+	}
+
 	if err := readIntegrationType(event, r); err != nil {
 		errs = append(errs, err)
 	}
@@ -250,6 +256,11 @@ func optionalParameters(request *analytics.EventRequest) string {
 		r.Add(IntegrationTypeParameter, request.Integration)
 	}
 
+	// clearing price
+	if request.Price > 0 {
+		r.Add(PriceParameter, strconv.FormatFloat(request.Price, 'f', -1, 64))
+	}
+
 	opt := r.Encode()
 
 	if opt != "" {
@@ -367,6 +378,23 @@ func readTimestamp(er *analytics.EventRequest, httpRequest *http.Request) error
 	return nil
 }
 
+// readPrice validates the optional clearing price of the winning bid
+func readPrice(er *analytics.EventRequest, httpRequest *http.Request) error {
+	p := httpRequest.URL.Query().Get(PriceParameter)
+
+	if p != "" {
+		price, err := strconv.ParseFloat(p, 64)
+
+		if err != nil || price < 0 {
+			return &errortypes.BadInput{Message: fmt.Sprintf("invalid request: error parsing price '%s'", p)}
+		}
+
+		er.Price = price
+	}
+
+	return nil
+}
+
 // checkRequiredParameter checks if http.Request contains all required parameters
 func checkRequiredParameter(httpRequest *http.Request, parameter string) (string, error) {
 	t := httpRequest.URL.Query().Get(parameter)
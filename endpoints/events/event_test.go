@@ -657,6 +657,15 @@ func TestShouldParseEventCorrectly(t *testing.T) {
 				Analytics: analytics.Enabled,
 			},
 		},
+		"four - price": {
+			req: httptest.NewRequest("GET", "/event?t=win&b=bidId&a=accountId&price=1.25", strings.NewReader("")),
+			expected: &analytics.EventRequest{
+				Type:      analytics.Win,
+				BidID:     "bidId",
+				Analytics: analytics.Enabled,
+				Price:     1.25,
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -715,6 +724,16 @@ func TestEventRequestToUrl(t *testing.T) {
 			},
 			want: "http://localhost:8000/event?t=win&b=bidid&a=accountId&bidder=bidder&f=i&int=integration&ts=1234567&x=0",
 		},
+		"four": {
+			er: &analytics.EventRequest{
+				Type:      analytics.Win,
+				BidID:     "bidid",
+				AccountID: "accountId",
+				Bidder:    "bidder",
+				Price:     1.51,
+			},
+			want: "http://localhost:8000/event?t=win&b=bidid&a=accountId&bidder=bidder&price=1.51",
+		},
 	}
 
 	for name, test := range tests {
@@ -726,6 +745,42 @@ func TestEventRequestToUrl(t *testing.T) {
 	}
 }
 
+func TestReadPrice(t *testing.T) {
+	testCases := []struct {
+		description   string
+		givenURL      string
+		expectedPrice float64
+		expectedError error
+	}{
+		{
+			description: "Not Provided",
+			givenURL:    "/event?t=win&b=bidId&a=accountId",
+		},
+		{
+			description:   "Valid",
+			givenURL:      "/event?t=win&b=bidId&a=accountId&price=0.5",
+			expectedPrice: 0.5,
+		},
+		{
+			description:   "Invalid",
+			givenURL:      "/event?t=win&b=bidId&a=accountId&price=abc",
+			expectedError: &errortypes.BadInput{Message: "invalid request: error parsing price 'abc'"},
+		},
+		{
+			description:   "Negative",
+			givenURL:      "/event?t=win&b=bidId&a=accountId&price=-1",
+			expectedError: &errortypes.BadInput{Message: "invalid request: error parsing price '-1'"},
+		},
+	}
+
+	for _, test := range testCases {
+		er := &analytics.EventRequest{}
+		err := readPrice(er, httptest.NewRequest("GET", test.givenURL, strings.NewReader("")))
+		assert.Equal(t, test.expectedError, err, test.description)
+		assert.Equal(t, test.expectedPrice, er.Price, test.description)
+	}
+}
+
 func TestReadIntegrationType(t *testing.T) {
 	testCases := []struct {
 		description             string
@@ -2,6 +2,7 @@ package endpoints
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/prebid-server/config"
@@ -11,16 +12,39 @@ import (
 )
 
 type userSyncs struct {
-	BuyerUIDs map[string]string `json:"buyeruids,omitempty"`
+	BuyerUIDs map[string]string        `json:"buyeruids,omitempty"`
+	Expiries  map[string]userSyncDebug `json:"debug,omitempty"`
+}
+
+// userSyncDebug describes when a user sync expires, for debugging purposes.
+type userSyncDebug struct {
+	Expires time.Time `json:"expires"`
+	Live    bool      `json:"live"`
 }
 
 // NewGetUIDsEndpoint implements the /getuid endpoint which
-// returns all the existing syncs for the user
+// returns all the existing syncs for the user. The expiration
+// of the syncs is included with the debug=1 query parameter.
 func NewGetUIDsEndpoint(cfg config.HostCookie) httprouter.Handle {
 	return httprouter.Handle(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 		pc := usersync.ParseCookieFromRequest(r, &cfg)
 		userSyncs := new(userSyncs)
 		userSyncs.BuyerUIDs = pc.GetUIDs()
+		if r.URL.Query().Get("debug") == "1" {
+			userSyncs.Expiries = getUIDExpiries(pc)
+		}
 		json.NewEncoder(w).Encode(userSyncs)
 	})
 }
+
+func getUIDExpiries(pc *usersync.Cookie) map[string]userSyncDebug {
+	now := time.Now()
+	expiries := make(map[string]userSyncDebug)
+	for bidder, expires := range pc.GetUIDExpiries() {
+		expiries[bidder] = userSyncDebug{
+			Expires: expires.UTC(),
+			Live:    now.Before(expires),
+		}
+	}
+	return expiries
+}
@@ -1,6 +1,7 @@
 package endpoints
 
 import (
+	"encoding/base64"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -39,3 +40,22 @@ func TestGetUIDWIthNoCookie(t *testing.T) {
 	assert.Equal(t, http.StatusOK, res.Code)
 	assert.JSONEq(t, `{}`, res.Body.String(), "GetUIDs endpoint shouldn't return anything if there doesn't exist a PBS cookie")
 }
+
+func TestGetUIDsWithDebug(t *testing.T) {
+	cookieJSON := `{"tempUIDs":{"adnxs":{"uid":"123","expires":"2030-01-02T03:04:05Z"},"rubicon":{"uid":"456","expires":"2020-01-02T03:04:05Z"}}}`
+	req := httptest.NewRequest("GET", "/getuids?debug=1", nil)
+	req.AddCookie(&http.Cookie{Name: "uids", Value: base64.URLEncoding.EncodeToString([]byte(cookieJSON))})
+
+	endpoint := NewGetUIDsEndpoint(config.HostCookie{})
+	res := httptest.NewRecorder()
+	endpoint(res, req, nil)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{
+		"buyeruids": {"adnxs": "123", "rubicon": "456"},
+		"debug": {
+			"adnxs": {"expires": "2030-01-02T03:04:05Z", "live": true},
+			"rubicon": {"expires": "2020-01-02T03:04:05Z", "live": false}
+		}
+	}`, res.Body.String(), "GetUIDs endpoint should return the expiration of each user ID in debug mode")
+}
@@ -131,6 +131,8 @@ type platform struct {
 func mapDetailFromConfig(c config.BidderInfo) bidderDetail {
 	var bidderDetail bidderDetail
 
+	bidderDetail.AliasOf = c.AliasOf
+
 	if c.Maintainer != nil {
 		bidderDetail.Maintainer = &maintainer{
 			Email: c.Maintainer.Email,
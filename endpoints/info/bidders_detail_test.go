@@ -318,6 +318,19 @@ func TestMapDetailFromConfig(t *testing.T) {
 				UsesHTTPS: &falseValue,
 			},
 		},
+		{
+			description: "Alias Declared In Bidder Info",
+			givenBidderInfo: config.BidderInfo{
+				AliasOf:  "appnexus",
+				Endpoint: "https://anyEndpoint",
+				Disabled: false,
+			},
+			expected: bidderDetail{
+				Status:    "ACTIVE",
+				UsesHTTPS: &trueValue,
+				AliasOf:   "appnexus",
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -24,8 +24,10 @@ import (
 	"github.com/prebid/prebid-server/amp"
 	"github.com/prebid/prebid-server/analytics"
 	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/devicedetection"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/exchange"
+	"github.com/prebid/prebid-server/geolocation"
 	"github.com/prebid/prebid-server/hooks"
 	"github.com/prebid/prebid-server/metrics"
 	"github.com/prebid/prebid-server/openrtb_ext"
@@ -46,6 +48,8 @@ type AmpResponse struct {
 	Debug     *openrtb_ext.ExtResponseDebug                             `json:"debug,omitempty"`
 	Errors    map[openrtb_ext.BidderName][]openrtb_ext.ExtBidderMessage `json:"errors,omitempty"`
 	Warnings  map[openrtb_ext.BidderName][]openrtb_ext.ExtBidderMessage `json:"warnings,omitempty"`
+	// SeatNonBid is only returned when ext.prebid.returnallbidstatus is set on the stored request
+	SeatNonBid []openrtb_ext.SeatNonBid `json:"seatnonbid,omitempty"`
 }
 
 // NewAmpEndpoint modifies the OpenRTB endpoint to handle AMP requests. This will basically modify the parsing
@@ -64,6 +68,9 @@ func NewAmpEndpoint(
 	bidderMap map[string]openrtb_ext.BidderName,
 	storedRespFetcher stored_requests.Fetcher,
 	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
+	geoLocation geolocation.GeoLocation,
+	deviceDetector devicedetection.DeviceDetector,
+	uidStore usersync.UIDStore,
 ) (httprouter.Handle, error) {
 
 	if ex == nil || validator == nil || requestsById == nil || accounts == nil || cfg == nil || metricsEngine == nil {
@@ -97,7 +104,10 @@ func NewAmpEndpoint(
 		nil,
 		ipValidator,
 		storedRespFetcher,
-		hookExecutor}).AmpAuction), nil
+		hookExecutor,
+		geoLocation,
+		deviceDetector,
+		uidStore}).AmpAuction), nil
 
 }
 
@@ -148,11 +158,11 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 
 	// There is no body for AMP requests, so we pass a nil body and ignore the return value.
 	if _, rejectErr := deps.hookExecutor.ExecuteEntrypointStage(r, nilBody); rejectErr != nil {
-		labels, ao = rejectAmpRequest(*rejectErr, w, nil, labels, ao, nil)
+		labels, ao = rejectAmpRequest(*rejectErr, w, deps.hookExecutor, nil, labels, ao, nil)
 		return
 	}
 
-	reqWrapper, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, errL := deps.parseAmpRequest(r)
+	reqWrapper, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, targetingDefaulted, errL := deps.parseAmpRequest(r)
 	ao.Errors = append(ao.Errors, errL...)
 
 	if errortypes.ContainsFatalError(errL) {
@@ -175,7 +185,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	}
 	defer cancel()
 
-	usersyncs := usersync.ParseCookieFromRequest(r, &(deps.cfg.HostCookie))
+	usersyncs := deps.parseUserSyncs(r)
 	if usersyncs.HasAnyLiveSyncs() {
 		labels.CookieFlag = metrics.CookieFlagYes
 	} else {
@@ -213,6 +223,13 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 		ao.Errors = append(ao.Errors, acctIDErrs...)
 		return
 	}
+	ao.Account = account
+
+	if targetingDefaulted {
+		if err := setAccountTargeting(reqWrapper, account.Targeting); err != nil {
+			ao.Errors = append(ao.Errors, err)
+		}
+	}
 
 	secGPC := r.Header.Get("Sec-GPC")
 
@@ -231,7 +248,11 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 		HookExecutor:               deps.hookExecutor,
 	}
 
-	response, err := deps.ex.HoldAuction(ctx, auctionRequest, nil)
+	auctionResponse, err := deps.ex.HoldAuction(ctx, auctionRequest, nil)
+	var response *openrtb2.BidResponse
+	if auctionResponse != nil {
+		response = auctionResponse.BidResponse
+	}
 	ao.AuctionResponse = response
 	rejectErr, isRejectErr := hookexecution.CastRejectErr(err)
 	if err != nil && !isRejectErr {
@@ -255,16 +276,17 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	}
 
 	if isRejectErr {
-		labels, ao = rejectAmpRequest(*rejectErr, w, reqWrapper, labels, ao, errL)
+		labels, ao = rejectAmpRequest(*rejectErr, w, deps.hookExecutor, reqWrapper, labels, ao, errL)
 		return
 	}
 
-	labels, ao = sendAmpResponse(w, response, reqWrapper, labels, ao, errL)
+	labels, ao = sendAmpResponse(w, deps.hookExecutor, response, reqWrapper, labels, ao, errL)
 }
 
 func rejectAmpRequest(
 	rejectErr hookexecution.RejectError,
 	w http.ResponseWriter,
+	hookExecutor hookexecution.HookStageExecutor,
 	reqWrapper *openrtb_ext.RequestWrapper,
 	labels metrics.Labels,
 	ao analytics.AmpObject,
@@ -274,17 +296,20 @@ func rejectAmpRequest(
 	ao.AuctionResponse = response
 	ao.Errors = append(ao.Errors, rejectErr)
 
-	return sendAmpResponse(w, response, reqWrapper, labels, ao, errs)
+	return sendAmpResponse(w, hookExecutor, response, reqWrapper, labels, ao, errs)
 }
 
 func sendAmpResponse(
 	w http.ResponseWriter,
+	hookExecutor hookexecution.HookStageExecutor,
 	response *openrtb2.BidResponse,
 	reqWrapper *openrtb_ext.RequestWrapper,
 	labels metrics.Labels,
 	ao analytics.AmpObject,
 	errs []error,
 ) (metrics.Labels, analytics.AmpObject) {
+	hookExecutor.ExecuteAuctionResponseStage(response)
+
 	// Need to extract the targeting parameters from the response, as those are all that
 	// go in the AMP response
 	targets := map[string]string{}
@@ -339,6 +364,9 @@ func sendAmpResponse(
 		Errors:    extResponse.Errors,
 		Warnings:  warnings,
 	}
+	if extResponse.Prebid != nil {
+		ampResponse.SeatNonBid = extResponse.Prebid.SeatNonBid
+	}
 
 	ao.AmpTargetingValues = targets
 
@@ -373,7 +401,9 @@ func sendAmpResponse(
 // possible, it will return errors with messages that suggest improvements.
 //
 // If the errors list has at least one element, then no guarantees are made about the returned request.
-func (deps *endpointDeps) parseAmpRequest(httpRequest *http.Request) (req *openrtb_ext.RequestWrapper, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImp stored_responses.BidderImpReplaceImpID, errs []error) {
+//
+// targetingDefaulted is true when the stored request omits ext.prebid.targeting, so the account targeting applies.
+func (deps *endpointDeps) parseAmpRequest(httpRequest *http.Request) (req *openrtb_ext.RequestWrapper, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImp stored_responses.BidderImpReplaceImpID, targetingDefaulted bool, errs []error) {
 	// Load the stored request for the AMP ID.
 	reqNormal, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, e := deps.loadRequestJSONForAmp(httpRequest)
 	if errs = append(errs, e...); errortypes.ContainsFatalError(errs) {
@@ -387,6 +417,7 @@ func (deps *endpointDeps) parseAmpRequest(httpRequest *http.Request) (req *openr
 	deps.setFieldsImplicitly(httpRequest, req)
 
 	// Need to ensure cache and targeting are turned on
+	targetingDefaulted = !hasTargeting(req)
 	e = defaultRequestExt(req)
 	if errs = append(errs, e...); errortypes.ContainsFatalError(errs) {
 		return
@@ -676,6 +707,32 @@ func defaultRequestExt(req *openrtb_ext.RequestWrapper) []error {
 	return nil
 }
 
+// hasTargeting returns true when the request sets ext.prebid.targeting
+func hasTargeting(req *openrtb_ext.RequestWrapper) bool {
+	extRequest, err := req.GetRequestExt()
+	if err != nil {
+		return false
+	}
+	prebid := extRequest.GetPrebid()
+	return prebid != nil && prebid.Targeting != nil
+}
+
+// setAccountTargeting replaces the AMP default targeting with the targeting of the account
+func setAccountTargeting(req *openrtb_ext.RequestWrapper, accountTargeting config.AccountTargeting) error {
+	extRequest, err := req.GetRequestExt()
+	if err != nil {
+		return err
+	}
+
+	prebid := extRequest.GetPrebid()
+	if prebid == nil {
+		prebid = &openrtb_ext.ExtRequestPrebid{}
+	}
+	prebid.Targeting = accountTargeting.RequestTargeting()
+	extRequest.SetPrebid(prebid)
+	return nil
+}
+
 func setAmpExtDirect(site *openrtb2.Site, value string) {
 	if len(site.Ext) > 0 {
 		if _, dataType, _, _ := jsonparser.Get(site.Ext, "amp"); dataType == jsonparser.NotExist {
@@ -213,6 +213,9 @@ func TestAMPPageInfo(t *testing.T) {
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+		nil,
 	)
 	request := httptest.NewRequest("GET", fmt.Sprintf("/openrtb2/auction/amp?tag_id=1&curl=%s", url.QueryEscape(page)), nil)
 	recorder := httptest.NewRecorder()
@@ -316,6 +319,9 @@ func TestGDPRConsent(t *testing.T) {
 			openrtb_ext.BuildBidderMap(),
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
+			nil,
+			nil,
+			nil,
 		)
 
 		// Invoke Endpoint
@@ -702,6 +708,9 @@ func TestCCPAConsent(t *testing.T) {
 			openrtb_ext.BuildBidderMap(),
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
+			nil,
+			nil,
+			nil,
 		)
 
 		// Invoke Endpoint
@@ -815,6 +824,9 @@ func TestConsentWarnings(t *testing.T) {
 			openrtb_ext.BuildBidderMap(),
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
+			nil,
+			nil,
+			nil,
 		)
 
 		// Invoke Endpoint
@@ -913,6 +925,9 @@ func TestNewAndLegacyConsentBothProvided(t *testing.T) {
 			openrtb_ext.BuildBidderMap(),
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
+			nil,
+			nil,
+			nil,
 		)
 
 		// Invoke Endpoint
@@ -967,6 +982,9 @@ func TestAMPSiteExt(t *testing.T) {
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+		nil,
 	)
 	request, err := http.NewRequest("GET", "/openrtb2/auction/amp?tag_id=1", nil)
 	if !assert.NoError(t, err) {
@@ -1009,6 +1027,9 @@ func TestAmpBadRequests(t *testing.T) {
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+		nil,
 	)
 	for requestID := range badRequests {
 		request := httptest.NewRequest("GET", fmt.Sprintf("/openrtb2/auction/amp?tag_id=%s", requestID), nil)
@@ -1042,6 +1063,9 @@ func TestAmpDebug(t *testing.T) {
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+		nil,
 	)
 
 	for requestID := range requests {
@@ -1073,6 +1097,49 @@ func TestAmpDebug(t *testing.T) {
 	}
 }
 
+func TestAmpSeatNonBid(t *testing.T) {
+	requests := map[string]json.RawMessage{
+		"2": json.RawMessage(validRequest(t, "site.json")),
+	}
+
+	endpoint, _ := NewAmpEndpoint(
+		fakeUUIDGenerator{},
+		&mockAmpExchangeSeatNonBid{},
+		newParamsValidator(t),
+		&mockAmpStoredReqFetcher{requests},
+		empty_fetcher.EmptyFetcher{},
+		&config.Configuration{MaxRequestSize: maxSize},
+		&metricsConfig.NilMetricsEngine{},
+		analyticsConf.NewPBSAnalytics(&config.Analytics{}),
+		map[string]string{},
+		[]byte{},
+		openrtb_ext.BuildBidderMap(),
+		empty_fetcher.EmptyFetcher{},
+		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+		nil,
+	)
+
+	request := httptest.NewRequest("GET", "/openrtb2/auction/amp?tag_id=2", nil)
+	recorder := httptest.NewRecorder()
+	endpoint(recorder, request, nil)
+
+	assert.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var response AmpResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Error unmarshalling response: %s", err.Error())
+	}
+	expected := []openrtb_ext.SeatNonBid{
+		{
+			Seat:   "appnexus",
+			NonBid: []openrtb_ext.NonBid{{ImpId: "imp1", StatusCode: 300}},
+		},
+	}
+	assert.Equal(t, expected, response.SeatNonBid)
+}
+
 // Prevents #452
 func TestAmpTargetingDefaults(t *testing.T) {
 	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}
@@ -1100,6 +1167,101 @@ func TestAmpTargetingDefaults(t *testing.T) {
 	}
 }
 
+func TestAmpAccountTargeting(t *testing.T) {
+	requestWithoutTargeting, err := getTestBidRequest(true, nil, true, nil)
+	if err != nil {
+		t.Fatalf("Failed to marshal the test bid request: %v", err)
+	}
+	var bidRequest openrtb2.BidRequest
+	if err := json.Unmarshal(requestWithoutTargeting, &bidRequest); err != nil {
+		t.Fatalf("Failed to unmarshal the test bid request: %v", err)
+	}
+	bidRequest.Ext = json.RawMessage(`{"prebid":{"targeting":{"pricegranularity":"low"}}}`)
+	requestWithTargeting, err := json.Marshal(bidRequest)
+	if err != nil {
+		t.Fatalf("Failed to marshal the test bid request: %v", err)
+	}
+
+	videoPriceGranularity := openrtb_ext.PriceGranularityFromString("high")
+	mediaTypePriceGranularity := &openrtb_ext.MediaTypePriceGranularity{Video: &videoPriceGranularity}
+	denseGranularity := openrtb_ext.PriceGranularityFromString("dense")
+	preferDeals := true
+
+	cfg := &config.Configuration{
+		MaxRequestSize: maxSize,
+		AccountDefaults: config.Account{
+			Targeting: config.AccountTargeting{
+				PriceGranularity:          &denseGranularity,
+				MediaTypePriceGranularity: mediaTypePriceGranularity,
+				PreferDeals:               &preferDeals,
+			},
+		},
+	}
+	cfg.MarshalAccountDefaults()
+
+	testCases := []struct {
+		description       string
+		storedRequest     []byte
+		expectedTargeting *openrtb_ext.ExtRequestTargeting
+	}{
+		{
+			description:   "Account targeting replaces the AMP defaults",
+			storedRequest: requestWithoutTargeting,
+			expectedTargeting: &openrtb_ext.ExtRequestTargeting{
+				PriceGranularity:          denseGranularity,
+				MediaTypePriceGranularity: mediaTypePriceGranularity,
+				IncludeWinners:            true,
+				IncludeBidderKeys:         true,
+				PreferDeals:               true,
+			},
+		},
+		{
+			description:   "Stored request targeting is kept",
+			storedRequest: requestWithTargeting,
+			expectedTargeting: &openrtb_ext.ExtRequestTargeting{
+				PriceGranularity:  openrtb_ext.PriceGranularityFromString("low"),
+				IncludeWinners:    true,
+				IncludeBidderKeys: true,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		exchange := &mockAmpExchange{}
+		endpoint, _ := NewAmpEndpoint(
+			fakeUUIDGenerator{},
+			exchange,
+			newParamsValidator(t),
+			&mockAmpStoredReqFetcher{map[string]json.RawMessage{"1": test.storedRequest}},
+			empty_fetcher.EmptyFetcher{},
+			cfg,
+			&metricsConfig.NilMetricsEngine{},
+			analyticsConf.NewPBSAnalytics(&config.Analytics{}),
+			map[string]string{},
+			[]byte{},
+			openrtb_ext.BuildBidderMap(),
+			empty_fetcher.EmptyFetcher{},
+			hooks.EmptyPlanBuilder{},
+			nil,
+			nil,
+			nil,
+		)
+
+		request := httptest.NewRequest("GET", "/openrtb2/auction/amp?tag_id=1", nil)
+		recorder := httptest.NewRecorder()
+		endpoint(recorder, request, nil)
+
+		if !assert.Equal(t, http.StatusOK, recorder.Code, test.description) {
+			continue
+		}
+		var requestExt openrtb_ext.ExtRequest
+		if err := json.Unmarshal(exchange.lastRequest.Ext, &requestExt); err != nil {
+			t.Fatalf("Failed to unmarshal the request ext: %v", err)
+		}
+		assert.Equal(t, test.expectedTargeting, requestExt.Prebid.Targeting, test.description)
+	}
+}
+
 func TestQueryParamOverrides(t *testing.T) {
 	requests := map[string]json.RawMessage{
 		"1": json.RawMessage(validRequest(t, "site.json")),
@@ -1119,6 +1281,9 @@ func TestQueryParamOverrides(t *testing.T) {
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+		nil,
 	)
 
 	requestID := "1"
@@ -1276,6 +1441,9 @@ func (s formatOverrideSpec) execute(t *testing.T) {
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+		nil,
 	)
 
 	url := fmt.Sprintf("/openrtb2/auction/amp?tag_id=1&debug=1&w=%d&h=%d&ow=%d&oh=%d&ms=%s&account=%s", s.width, s.height, s.overrideWidth, s.overrideHeight, s.multisize, s.account)
@@ -1321,7 +1489,7 @@ var expectedErrorsFromHoldAuction map[openrtb_ext.BidderName][]openrtb_ext.ExtBi
 	},
 }
 
-func (m *mockAmpExchange) HoldAuction(ctx context.Context, auctionRequest exchange.AuctionRequest, debugLog *exchange.DebugLog) (*openrtb2.BidResponse, error) {
+func (m *mockAmpExchange) HoldAuction(ctx context.Context, auctionRequest exchange.AuctionRequest, debugLog *exchange.DebugLog) (*exchange.AuctionResponse, error) {
 	r := auctionRequest.BidRequestWrapper
 	m.lastRequest = r.BidRequest
 
@@ -1351,12 +1519,12 @@ func (m *mockAmpExchange) HoldAuction(ctx context.Context, auctionRequest exchan
 		response.Ext = json.RawMessage(fmt.Sprintf(`{"debug": {"httpcalls": {}, "resolvedrequest": %s}}`, resolvedRequest))
 	}
 
-	return response, nil
+	return &exchange.AuctionResponse{BidResponse: response}, nil
 }
 
 type mockAmpExchangeWarnings struct{}
 
-func (m *mockAmpExchangeWarnings) HoldAuction(ctx context.Context, r exchange.AuctionRequest, debugLog *exchange.DebugLog) (*openrtb2.BidResponse, error) {
+func (m *mockAmpExchangeWarnings) HoldAuction(ctx context.Context, r exchange.AuctionRequest, debugLog *exchange.DebugLog) (*exchange.AuctionResponse, error) {
 	response := &openrtb2.BidResponse{
 		SeatBid: []openrtb2.SeatBid{{
 			Bid: []openrtb2.Bid{{
@@ -1366,7 +1534,22 @@ func (m *mockAmpExchangeWarnings) HoldAuction(ctx context.Context, r exchange.Au
 		}},
 		Ext: json.RawMessage(`{ "warnings": {"appnexus": [{"code": 10003, "message": "debug turned off for bidder"}] }}`),
 	}
-	return response, nil
+	return &exchange.AuctionResponse{BidResponse: response}, nil
+}
+
+type mockAmpExchangeSeatNonBid struct{}
+
+func (m *mockAmpExchangeSeatNonBid) HoldAuction(ctx context.Context, r exchange.AuctionRequest, debugLog *exchange.DebugLog) (*exchange.AuctionResponse, error) {
+	response := &openrtb2.BidResponse{
+		SeatBid: []openrtb2.SeatBid{{
+			Bid: []openrtb2.Bid{{
+				AdM: "<script></script>",
+				Ext: json.RawMessage(`{ "prebid": {"targeting": { "hb_pb": "1.20", "hb_appnexus_pb": "1.20", "hb_cache_id": "some_id"}}}`),
+			}},
+		}},
+		Ext: json.RawMessage(`{ "prebid": {"auctiontimestamp": 1, "seatnonbid": [{"seat": "appnexus", "nonbid": [{"impid": "imp1", "statuscode": 300}]}]}}`),
+	}
+	return &exchange.AuctionResponse{BidResponse: response}, nil
 }
 
 func getTestBidRequest(nilUser bool, userExt *openrtb_ext.ExtUser, nilRegs bool, regsExt *openrtb_ext.ExtRegs) ([]byte, error) {
@@ -1739,6 +1922,9 @@ func ampObjectTestSetup(t *testing.T, inTagId string, inStoredRequest json.RawMe
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+		nil,
 	)
 	return &actualAmpObject, endpoint
 }
@@ -1791,6 +1977,9 @@ func TestAmpAuctionResponseHeaders(t *testing.T) {
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+		nil,
 	)
 
 	for _, test := range testCases {
@@ -1826,6 +2015,9 @@ func TestRequestWithTargeting(t *testing.T) {
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+		nil,
 	)
 	url, err := url.Parse("/openrtb2/auction/amp")
 	assert.NoError(t, err, "unexpected error received while parsing url")
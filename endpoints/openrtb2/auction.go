@@ -1,6 +1,8 @@
 package openrtb2
 
 import (
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"encoding/json"
 	"errors"
@@ -31,8 +33,10 @@ import (
 	"github.com/prebid/prebid-server/analytics"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/devicedetection"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/exchange"
+	"github.com/prebid/prebid-server/geolocation"
 	"github.com/prebid/prebid-server/hooks/hookexecution"
 	"github.com/prebid/prebid-server/metrics"
 	"github.com/prebid/prebid-server/openrtb_ext"
@@ -85,6 +89,9 @@ func NewEndpoint(
 	bidderMap map[string]openrtb_ext.BidderName,
 	storedRespFetcher stored_requests.Fetcher,
 	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
+	geoLocation geolocation.GeoLocation,
+	deviceDetector devicedetection.DeviceDetector,
+	uidStore usersync.UIDStore,
 ) (httprouter.Handle, error) {
 	if ex == nil || validator == nil || requestsById == nil || accounts == nil || cfg == nil || metricsEngine == nil {
 		return nil, errors.New("NewEndpoint requires non-nil arguments.")
@@ -117,7 +124,10 @@ func NewEndpoint(
 		nil,
 		ipValidator,
 		storedRespFetcher,
-		hookExecutor}).Auction), nil
+		hookExecutor,
+		geoLocation,
+		deviceDetector,
+		uidStore}).Auction), nil
 }
 
 type endpointDeps struct {
@@ -139,6 +149,9 @@ type endpointDeps struct {
 	privateNetworkIPValidator iputil.IPValidator
 	storedRespFetcher         stored_requests.Fetcher
 	hookExecutor              hookexecution.HookStageExecutor
+	geoLocation               geolocation.GeoLocation
+	deviceDetector            devicedetection.DeviceDetector
+	uidStore                  usersync.UIDStore
 }
 
 func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
@@ -177,7 +190,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	}
 
 	if rejectErr := hookexecution.FindFirstRejectOrNil(errL); rejectErr != nil {
-		labels, ao = rejectAuctionRequest(*rejectErr, w, req.BidRequest, labels, ao)
+		labels, ao = rejectAuctionRequest(*rejectErr, w, deps.hookExecutor, req.BidRequest, labels, ao)
 		return
 	}
 
@@ -190,7 +203,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 		defer cancel()
 	}
 
-	usersyncs := usersync.ParseCookieFromRequest(r, &(deps.cfg.HostCookie))
+	usersyncs := deps.parseUserSyncs(r)
 	if req.Site != nil {
 		if usersyncs.HasAnyLiveSyncs() {
 			labels.CookieFlag = metrics.CookieFlagYes
@@ -226,10 +239,15 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 		PubID:                      labels.PubID,
 		HookExecutor:               deps.hookExecutor,
 	}
-	response, err := deps.ex.HoldAuction(ctx, auctionRequest, nil)
+	auctionResponse, err := deps.ex.HoldAuction(ctx, auctionRequest, nil)
+	var response *openrtb2.BidResponse
+	if auctionResponse != nil {
+		response = auctionResponse.BidResponse
+	}
 	ao.Request = req.BidRequest
 	ao.Response = response
 	ao.Account = account
+	ao.SeatNonBid = auctionResponse.GetSeatNonBid()
 	rejectErr, isRejectErr := hookexecution.CastRejectErr(err)
 	if err != nil && !isRejectErr {
 		if errortypes.ReadCode(err) == errortypes.BadInputErrorCode {
@@ -244,16 +262,17 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 		ao.Errors = append(ao.Errors, err)
 		return
 	} else if isRejectErr {
-		labels, ao = rejectAuctionRequest(*rejectErr, w, req.BidRequest, labels, ao)
+		labels, ao = rejectAuctionRequest(*rejectErr, w, deps.hookExecutor, req.BidRequest, labels, ao)
 		return
 	}
 
-	labels, ao = sendAuctionResponse(w, response, labels, ao)
+	labels, ao = sendAuctionResponse(w, deps.hookExecutor, response, labels, ao)
 }
 
 func rejectAuctionRequest(
 	rejectErr hookexecution.RejectError,
 	w http.ResponseWriter,
+	hookExecutor hookexecution.HookStageExecutor,
 	request *openrtb2.BidRequest,
 	labels metrics.Labels,
 	ao analytics.AuctionObject,
@@ -266,15 +285,18 @@ func rejectAuctionRequest(
 	ao.Response = response
 	ao.Errors = append(ao.Errors, rejectErr)
 
-	return sendAuctionResponse(w, response, labels, ao)
+	return sendAuctionResponse(w, hookExecutor, response, labels, ao)
 }
 
 func sendAuctionResponse(
 	w http.ResponseWriter,
+	hookExecutor hookexecution.HookStageExecutor,
 	response *openrtb2.BidResponse,
 	labels metrics.Labels,
 	ao analytics.AuctionObject,
 ) (metrics.Labels, analytics.AuctionObject) {
+	hookExecutor.ExecuteAuctionResponseStage(response)
+
 	// Fixes #231
 	enc := json.NewEncoder(w)
 	enc.SetEscapeHTML(false)
@@ -292,6 +314,67 @@ func sendAuctionResponse(
 	return labels, ao
 }
 
+// readRequestBody reads the request body, decompressing it first when it was sent with a gzip or deflate
+// Content-Encoding. The max request size applies to the decompressed body so a small compressed body can't
+// expand past it.
+func (deps *endpointDeps) readRequestBody(httpRequest *http.Request) ([]byte, error) {
+	body, compression, err := decompressRequestBody(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	if compression != "" {
+		deps.metricsEngine.RecordCompressedRequest(compression)
+		// the body is handed to the hooks and the rest of the endpoint decompressed
+		httpRequest.Header.Del("Content-Encoding")
+		defer body.Close()
+	}
+
+	lr := &io.LimitedReader{
+		R: body,
+		N: deps.cfg.MaxRequestSize,
+	}
+	requestJson, err := io.ReadAll(lr)
+	if err != nil {
+		return nil, err
+	}
+	// If the request size was too large, read through the rest of the request body so that the connection can be reused.
+	if lr.N <= 0 {
+		if compression != "" {
+			// a single extra decompressed byte is enough to reject the body without expanding all of it
+			if n, _ := io.ReadFull(body, make([]byte, 1)); n > 0 {
+				io.Copy(io.Discard, httpRequest.Body)
+				return nil, fmt.Errorf("Request size exceeded max size of %d bytes.", deps.cfg.MaxRequestSize)
+			}
+		} else if written, err := io.Copy(io.Discard, httpRequest.Body); written > 0 || err != nil {
+			return nil, fmt.Errorf("Request size exceeded max size of %d bytes.", deps.cfg.MaxRequestSize)
+		}
+	}
+	return requestJson, nil
+}
+
+// decompressRequestBody returns a reader of the decompressed request body along with the compression it was
+// sent with, which is empty when the body is not compressed
+func decompressRequestBody(httpRequest *http.Request) (io.ReadCloser, metrics.RequestCompression, error) {
+	encoding := strings.ToLower(strings.TrimSpace(httpRequest.Header.Get("Content-Encoding")))
+	switch encoding {
+	case "", "identity":
+		return httpRequest.Body, "", nil
+	case "gzip", "x-gzip":
+		reader, err := gzip.NewReader(httpRequest.Body)
+		if err != nil {
+			return nil, "", fmt.Errorf("Failed to decompress the gzip request body: %v", err)
+		}
+		return reader, metrics.RequestCompressionGzip, nil
+	case "deflate":
+		reader, err := zlib.NewReader(httpRequest.Body)
+		if err != nil {
+			return nil, "", fmt.Errorf("Failed to decompress the deflate request body: %v", err)
+		}
+		return reader, metrics.RequestCompressionDeflate, nil
+	}
+	return nil, "", fmt.Errorf("Content-Encoding %s is not supported, use gzip or deflate", encoding)
+}
+
 // parseRequest turns the HTTP request into an OpenRTB request. This is guaranteed to return:
 //
 //   - A context which times out appropriately, given the request.
@@ -308,22 +391,11 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	errs = nil
 
 	// Pull the request body into a buffer, so we have it for later usage.
-	lr := &io.LimitedReader{
-		R: httpRequest.Body,
-		N: deps.cfg.MaxRequestSize,
-	}
-	requestJson, err := io.ReadAll(lr)
+	requestJson, err := deps.readRequestBody(httpRequest)
 	if err != nil {
 		errs = []error{err}
 		return
 	}
-	// If the request size was too large, read through the rest of the request body so that the connection can be reused.
-	if lr.N <= 0 {
-		if written, err := io.Copy(io.Discard, httpRequest.Body); written > 0 || err != nil {
-			errs = []error{fmt.Errorf("Request size exceeded max size of %d bytes.", deps.cfg.MaxRequestSize)}
-			return
-		}
-	}
 
 	requestJson, rejectErr := deps.hookExecutor.ExecuteEntrypointStage(httpRequest, requestJson)
 	if rejectErr != nil {
@@ -686,8 +758,11 @@ func (deps *endpointDeps) validateRequest(req *openrtb_ext.RequestWrapper, isAmp
 		return append(errL, errors.New("request.site or request.app must be defined, but not both."))
 	}
 
-	if err := validateRequestExt(req); err != nil {
-		return append(errL, err)
+	if errs := validateRequestExt(req); len(errs) != 0 {
+		if errortypes.ContainsFatalError(errs) {
+			return append(errL, errs...)
+		}
+		errL = append(errL, errs...)
 	}
 
 	if err := deps.validateSite(req); err != nil {
@@ -1451,18 +1526,34 @@ func (deps *endpointDeps) validateAliasesGVLIDs(aliasesGVLIDs map[string]uint16,
 	return nil
 }
 
-func validateRequestExt(req *openrtb_ext.RequestWrapper) error {
+func validateRequestExt(req *openrtb_ext.RequestWrapper) []error {
 	reqExt, err := req.GetRequestExt()
 	if err != nil {
-		return err
+		return []error{err}
 	}
 
 	prebid := reqExt.GetPrebid()
-	if prebid != nil && prebid.Cache != nil && (prebid.Cache.Bids == nil && prebid.Cache.VastXML == nil) {
-		return errors.New(`request.ext is invalid: request.ext.prebid.cache requires one of the "bids" or "vastxml" properties`)
+	if prebid == nil {
+		return nil
 	}
 
-	return nil
+	if prebid.Cache != nil && (prebid.Cache.Bids == nil && prebid.Cache.VastXML == nil) {
+		return []error{errors.New(`request.ext is invalid: request.ext.prebid.cache requires one of the "bids" or "vastxml" properties`)}
+	}
+
+	if err := prebid.Auction.Validate(); err != nil {
+		return []error{fmt.Errorf("request.ext.prebid.auction is invalid: %v", err)}
+	}
+
+	var errs []error
+	if len(prebid.MultiBid) > 0 {
+		var multiBidErrs []error
+		prebid.MultiBid, multiBidErrs = openrtb_ext.ValidateAndBuildExtMultiBid(prebid)
+		errs = append(errs, multiBidErrs...)
+		reqExt.SetPrebid(prebid)
+	}
+
+	return errs
 }
 
 func (deps *endpointDeps) validateSite(req *openrtb_ext.RequestWrapper) error {
@@ -1656,6 +1747,8 @@ func (deps *endpointDeps) setFieldsImplicitly(httpReq *http.Request, r *openrtb_
 	sanitizeRequest(r, deps.privateNetworkIPValidator)
 
 	setDeviceImplicitly(httpReq, r, deps.privateNetworkIPValidator)
+	setGeoImplicitly(r, deps.geoLocation)
+	setDeviceDetailsImplicitly(httpReq, r, deps.deviceDetector)
 
 	// Per the OpenRTB spec: A bid request must not contain both a Site and an App object. If neither are
 	// present, we'll assume it's a site request.
@@ -1983,6 +2076,96 @@ func setIPImplicitly(httpReq *http.Request, r *openrtb_ext.RequestWrapper, ipVal
 	}
 }
 
+// setGeoImplicitly fills device.geo with the location of the device ip, unless the request already has a country.
+// The exchange relies on device.geo.country to decide whether GDPR applies when the request doesn't say.
+func setGeoImplicitly(r *openrtb_ext.RequestWrapper, geoLocation geolocation.GeoLocation) {
+	if geoLocation == nil || r.Device == nil {
+		return
+	}
+	if r.Device.Geo != nil && r.Device.Geo.Country != "" {
+		return
+	}
+
+	ip := r.Device.IP
+	if ip == "" {
+		ip = r.Device.IPv6
+	}
+	if ip == "" {
+		return
+	}
+
+	// A failed lookup leaves the request as it is, just like an unknown ip
+	info, err := geoLocation.Lookup(ip)
+	if err != nil || info == nil {
+		return
+	}
+
+	if r.Device.Geo == nil {
+		r.Device.Geo = &openrtb2.Geo{}
+	}
+	geo := r.Device.Geo
+	geo.Country = info.Country
+	if geo.Region == "" {
+		geo.Region = info.Region
+	}
+	if geo.Metro == "" {
+		geo.Metro = info.Metro
+	}
+	if geo.City == "" {
+		geo.City = info.City
+	}
+	if geo.Type == 0 {
+		geo.Type = adcom1.LocationIP
+	}
+}
+
+// setDeviceDetailsImplicitly fills the device fields which the request doesn't have from the User-Agent Client
+// Hints headers, and then from the device detected for the user agent. The os and make are only completed with
+// the detected version and model when they agree with the detected ones. The Client Hints headers are only used
+// when the device is the one which sent the http request, which isn't the case for server to server requests.
+func setDeviceDetailsImplicitly(httpReq *http.Request, r *openrtb_ext.RequestWrapper, detector devicedetection.DeviceDetector) {
+	if detector == nil || r.Device == nil {
+		return
+	}
+	device := r.Device
+
+	if device.SUA == nil && device.UA != "" && device.UA == httpReq.UserAgent() {
+		device.SUA = devicedetection.ParseClientHints(httpReq.Header)
+	}
+	if sua := device.SUA; sua != nil {
+		if device.OS == "" && sua.Platform != nil {
+			device.OS = sua.Platform.Brand
+			if device.OSV == "" {
+				device.OSV = strings.Join(sua.Platform.Version, ".")
+			}
+		}
+		if device.Model == "" {
+			device.Model = sua.Model
+		}
+	}
+
+	if device.UA == "" {
+		return
+	}
+	info := detector.Detect(device.UA)
+
+	if device.DeviceType == 0 {
+		device.DeviceType = info.DeviceType
+	}
+	if device.OS == "" {
+		device.OS = info.OS
+	}
+	if device.OSV == "" && strings.EqualFold(device.OS, info.OS) {
+		device.OSV = info.OSV
+	}
+	if device.Make == "" {
+		device.Make = info.Make
+	}
+	if device.Model == "" && strings.EqualFold(device.Make, info.Make) {
+		device.Model = info.Model
+	}
+}
+
 // setUAImplicitly sets the User Agent on bidReq, if it's not explicitly defined and it's defined on the request.
 func setUAImplicitly(httpReq *http.Request, r *openrtb_ext.RequestWrapper) {
 	if r.Device == nil || r.Device.UA == "" {
@@ -2181,3 +2364,23 @@ func validateStoredBidRespAndImpExtBidders(bidderExts map[string]json.RawMessage
 func generateStoredBidResponseValidationError(impID string) error {
 	return fmt.Errorf("request validation failed. Stored bid responses are specified for imp %s. Bidders specified in imp.ext should match with bidders specified in imp.ext.prebid.storedbidresponse", impID)
 }
+
+// parseUserSyncs parses the user syncs of the uids cookie, completed by the ones kept in the UID store for the
+// host cookie. The UID store is only written by /setuid, once the privacy checks allowed the sync, so the uids
+// cookie of the request never changes the stored user syncs.
+func (deps *endpointDeps) parseUserSyncs(httpReq *http.Request) *usersync.Cookie {
+	usersyncs := usersync.ParseCookieFromRequest(httpReq, &(deps.cfg.HostCookie))
+	if deps.uidStore == nil {
+		return usersyncs
+	}
+
+	if id := usersync.UIDStoreHostCookieID(httpReq, &(deps.cfg.HostCookie)); id != "" {
+		stored, err := deps.uidStore.Get(id)
+		if err != nil {
+			glog.Errorf("Failed to read the uid store: %v", err)
+			return usersyncs
+		}
+		usersyncs.Merge(stored)
+	}
+	return usersyncs
+}
@@ -98,6 +98,8 @@ func BenchmarkOpenrtbEndpoint(b *testing.B) {
 		currency.NewRateConverter(&http.Client{}, "", time.Duration(0)),
 		empty_fetcher.EmptyFetcher{},
 		&adscert.NilSigner{},
+		nil,
+		nil,
 	)
 
 	endpoint, _ := NewEndpoint(
@@ -114,6 +116,9 @@ func BenchmarkOpenrtbEndpoint(b *testing.B) {
 		nil,
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+		nil,
 	)
 
 	b.ResetTimer()
@@ -2,9 +2,12 @@ package openrtb2
 
 import (
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
@@ -17,6 +20,7 @@ import (
 
 	"github.com/buger/jsonparser"
 	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/openrtb/v17/adcom1"
 	"github.com/prebid/openrtb/v17/native1"
 	nativeRequests "github.com/prebid/openrtb/v17/native1/request"
 	"github.com/prebid/openrtb/v17/openrtb2"
@@ -24,16 +28,20 @@ import (
 	"github.com/prebid/prebid-server/hooks/hookexecution"
 	"github.com/prebid/prebid-server/hooks/hookstage"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	analyticsConf "github.com/prebid/prebid-server/analytics/config"
 	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/devicedetection"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/exchange"
+	"github.com/prebid/prebid-server/geolocation"
 	"github.com/prebid/prebid-server/metrics"
 	metricsConfig "github.com/prebid/prebid-server/metrics/config"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/prebid/prebid-server/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/stored_responses"
+	"github.com/prebid/prebid-server/usersync"
 	"github.com/prebid/prebid-server/util/iputil"
 )
 
@@ -383,7 +391,9 @@ func TestExplicitUserId(t *testing.T) {
 		[]byte{},
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
-		hooks.EmptyPlanBuilder{})
+		hooks.EmptyPlanBuilder{},
+		nil,
+		nil, nil)
 
 	endpoint(httptest.NewRecorder(), request, nil)
 
@@ -439,7 +449,9 @@ func doBadAliasRequest(t *testing.T, filename string, expectMsg string) {
 		aliasJSON,
 		bidderMap,
 		empty_fetcher.EmptyFetcher{},
-		hooks.EmptyPlanBuilder{})
+		hooks.EmptyPlanBuilder{},
+		nil,
+		nil, nil)
 
 	request := httptest.NewRequest("POST", "/openrtb2/auction", bytes.NewReader(testBidRequest))
 	recorder := httptest.NewRecorder()
@@ -491,7 +503,9 @@ func TestNilExchange(t *testing.T) {
 		[]byte{},
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
-		hooks.EmptyPlanBuilder{})
+		hooks.EmptyPlanBuilder{},
+		nil,
+		nil, nil)
 
 	if err == nil {
 		t.Errorf("NewEndpoint should return an error when given a nil Exchange.")
@@ -515,7 +529,9 @@ func TestNilValidator(t *testing.T) {
 		[]byte{},
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
-		hooks.EmptyPlanBuilder{})
+		hooks.EmptyPlanBuilder{},
+		nil,
+		nil, nil)
 
 	if err == nil {
 		t.Errorf("NewEndpoint should return an error when given a nil BidderParamValidator.")
@@ -539,7 +555,9 @@ func TestExchangeError(t *testing.T) {
 		[]byte{},
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
-		hooks.EmptyPlanBuilder{})
+		hooks.EmptyPlanBuilder{},
+		nil,
+		nil, nil)
 
 	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
 	recorder := httptest.NewRecorder()
@@ -664,7 +682,9 @@ func TestImplicitIPsEndToEnd(t *testing.T) {
 			[]byte{},
 			openrtb_ext.BuildBidderMap(),
 			empty_fetcher.EmptyFetcher{},
-			hooks.EmptyPlanBuilder{})
+			hooks.EmptyPlanBuilder{},
+			nil,
+			nil, nil)
 
 		httpReq := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, test.reqJSONFile)))
 		httpReq.Header.Set("X-Forwarded-For", test.xForwardedForHeader)
@@ -804,6 +824,252 @@ func TestImplicitDNT(t *testing.T) {
 	}
 }
 
+type mockGeoLocation struct {
+	info map[string]*geolocation.GeoInfo
+}
+
+func (g mockGeoLocation) Lookup(ip string) (*geolocation.GeoInfo, error) {
+	if ip == "invalid" {
+		return nil, errors.New("invalid ip address")
+	}
+	return g.info[ip], nil
+}
+
+func TestImplicitGeo(t *testing.T) {
+	geoLocation := mockGeoLocation{info: map[string]*geolocation.GeoInfo{
+		"8.8.8.8":     {Country: "USA", Region: "CA", Metro: "807", City: "Mountain View"},
+		"2001:db8::1": {Country: "DEU"},
+	}}
+
+	testCases := []struct {
+		description string
+		geoLocation geolocation.GeoLocation
+		device      *openrtb2.Device
+		expectedGeo *openrtb2.Geo
+	}{
+		{
+			description: "Geolocation disabled",
+			device:      &openrtb2.Device{IP: "8.8.8.8"},
+		},
+		{
+			description: "IPv4",
+			geoLocation: geoLocation,
+			device:      &openrtb2.Device{IP: "8.8.8.8"},
+			expectedGeo: &openrtb2.Geo{Country: "USA", Region: "CA", Metro: "807", City: "Mountain View", Type: adcom1.LocationIP},
+		},
+		{
+			description: "IPv6",
+			geoLocation: geoLocation,
+			device:      &openrtb2.Device{IPv6: "2001:db8::1"},
+			expectedGeo: &openrtb2.Geo{Country: "DEU", Type: adcom1.LocationIP},
+		},
+		{
+			description: "Request geo without country keeps its fields",
+			geoLocation: geoLocation,
+			device:      &openrtb2.Device{IP: "8.8.8.8", Geo: &openrtb2.Geo{City: "Palo Alto", Lat: 37.4, Type: adcom1.LocationGPS}},
+			expectedGeo: &openrtb2.Geo{Country: "USA", Region: "CA", Metro: "807", City: "Palo Alto", Lat: 37.4, Type: adcom1.LocationGPS},
+		},
+		{
+			description: "Request country is not overwritten",
+			geoLocation: geoLocation,
+			device:      &openrtb2.Device{IP: "8.8.8.8", Geo: &openrtb2.Geo{Country: "CAN"}},
+			expectedGeo: &openrtb2.Geo{Country: "CAN"},
+		},
+		{
+			description: "Unknown ip",
+			geoLocation: geoLocation,
+			device:      &openrtb2.Device{IP: "9.9.9.9"},
+		},
+		{
+			description: "Lookup error",
+			geoLocation: geoLocation,
+			device:      &openrtb2.Device{IP: "invalid"},
+		},
+		{
+			description: "No ip",
+			geoLocation: geoLocation,
+			device:      &openrtb2.Device{},
+		},
+	}
+
+	for _, test := range testCases {
+		req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: test.device}}
+
+		setGeoImplicitly(req, test.geoLocation)
+
+		assert.Equal(t, test.expectedGeo, req.Device.Geo, test.description)
+	}
+}
+
+type mockDeviceDetector struct{}
+
+func (d mockDeviceDetector) Detect(ua string) devicedetection.DeviceInfo {
+	if ua == "iPhone" {
+		return devicedetection.DeviceInfo{DeviceType: adcom1.DevicePhone, OS: "iOS", OSV: "16.4", Make: "Apple", Model: "iPhone"}
+	}
+	return devicedetection.DeviceInfo{}
+}
+
+func TestParseUserSyncs(t *testing.T) {
+	newUIDStore := func() usersync.UIDStore {
+		uidStore := usersync.NewMemoryUIDStore(time.Hour, 10)
+		hostCookieSyncs := usersync.NewCookie()
+		hostCookieSyncs.TrySync("adnxs", "host-cookie-uid")
+		uidStore.Set("host:host-id", hostCookieSyncs)
+		return uidStore
+	}
+
+	cookieSyncs := usersync.NewCookie()
+	cookieSyncs.TrySync("rubicon", "cookie-uid")
+
+	testCases := []struct {
+		description  string
+		uidStore     usersync.UIDStore
+		hostCookie   string
+		expectedUIDs map[string]string
+	}{
+		{
+			description:  "No uid store",
+			hostCookie:   "host-id",
+			expectedUIDs: map[string]string{"rubicon": "cookie-uid", "pbs": "host-id"},
+		},
+		{
+			description:  "Host cookie",
+			uidStore:     newUIDStore(),
+			hostCookie:   "host-id",
+			expectedUIDs: map[string]string{"rubicon": "cookie-uid", "pbs": "host-id", "adnxs": "host-cookie-uid"},
+		},
+		{
+			description:  "Unknown host cookie",
+			uidStore:     newUIDStore(),
+			hostCookie:   "unknown",
+			expectedUIDs: map[string]string{"rubicon": "cookie-uid", "pbs": "unknown"},
+		},
+		{
+			description:  "No host cookie",
+			uidStore:     newUIDStore(),
+			expectedUIDs: map[string]string{"rubicon": "cookie-uid"},
+		},
+	}
+
+	for _, test := range testCases {
+		deps := &endpointDeps{
+			cfg:      &config.Configuration{HostCookie: config.HostCookie{Family: "pbs", CookieName: "khaos"}},
+			uidStore: test.uidStore,
+		}
+
+		httpReq := httptest.NewRequest("POST", "/openrtb2/auction", nil)
+		httpReq.AddCookie(cookieSyncs.ToHTTPCookie(time.Hour))
+		if test.hostCookie != "" {
+			httpReq.AddCookie(&http.Cookie{Name: "khaos", Value: test.hostCookie})
+		}
+
+		usersyncs := deps.parseUserSyncs(httpReq)
+		assert.Equal(t, test.expectedUIDs, usersyncs.GetUIDs(), test.description)
+
+		if test.uidStore != nil {
+			stored, err := test.uidStore.Get("host:host-id")
+			if assert.NoError(t, err, test.description) && assert.NotNil(t, stored, test.description) {
+				assert.Equal(t, map[string]string{"adnxs": "host-cookie-uid"}, stored.GetUIDs(), "the uids cookie isn't stored: "+test.description)
+			}
+			stored, err = test.uidStore.Get("host:" + test.hostCookie)
+			if assert.NoError(t, err, test.description) && test.hostCookie != "host-id" {
+				assert.Nil(t, stored, "the uids cookie isn't stored: "+test.description)
+			}
+		}
+	}
+}
+
+func TestImplicitDeviceDetails(t *testing.T) {
+	mobile := int8(1)
+
+	testCases := []struct {
+		description    string
+		deviceDetector devicedetection.DeviceDetector
+		headers        map[string]string
+		device         *openrtb2.Device
+		expectedDevice *openrtb2.Device
+	}{
+		{
+			description:    "Device detection disabled",
+			device:         &openrtb2.Device{UA: "iPhone"},
+			expectedDevice: &openrtb2.Device{UA: "iPhone"},
+		},
+		{
+			description:    "Detected fields",
+			deviceDetector: mockDeviceDetector{},
+			device:         &openrtb2.Device{UA: "iPhone"},
+			expectedDevice: &openrtb2.Device{UA: "iPhone", DeviceType: adcom1.DevicePhone, OS: "iOS", OSV: "16.4", Make: "Apple", Model: "iPhone"},
+		},
+		{
+			description:    "Request fields are kept",
+			deviceDetector: mockDeviceDetector{},
+			device:         &openrtb2.Device{UA: "iPhone", DeviceType: adcom1.DeviceTablet, OSV: "17.0", Model: "iPhone 14"},
+			expectedDevice: &openrtb2.Device{UA: "iPhone", DeviceType: adcom1.DeviceTablet, OS: "iOS", OSV: "17.0", Make: "Apple", Model: "iPhone 14"},
+		},
+		{
+			description:    "Detected version and model of another os and make are not used",
+			deviceDetector: mockDeviceDetector{},
+			device:         &openrtb2.Device{UA: "iPhone", OS: "Android", Make: "Samsung"},
+			expectedDevice: &openrtb2.Device{UA: "iPhone", DeviceType: adcom1.DevicePhone, OS: "Android", Make: "Samsung"},
+		},
+		{
+			description:    "Client hints",
+			deviceDetector: mockDeviceDetector{},
+			headers: map[string]string{
+				"User-Agent":                 "Android",
+				"Sec-CH-UA-Mobile":           "?1",
+				"Sec-CH-UA-Platform":         `"Android"`,
+				"Sec-CH-UA-Platform-Version": `"13.0.0"`,
+				"Sec-CH-UA-Model":            `"Pixel 7"`,
+			},
+			device: &openrtb2.Device{UA: "Android"},
+			expectedDevice: &openrtb2.Device{
+				UA:    "Android",
+				OS:    "Android",
+				OSV:   "13.0.0",
+				Model: "Pixel 7",
+				SUA: &openrtb2.UserAgent{
+					Platform: &openrtb2.BrandVersion{Brand: "Android", Version: []string{"13", "0", "0"}},
+					Mobile:   &mobile,
+					Model:    "Pixel 7",
+					Source:   adcom1.UASourceHighEntropy,
+				},
+			},
+		},
+		{
+			description:    "Client hints of another user agent are not used",
+			deviceDetector: mockDeviceDetector{},
+			headers: map[string]string{
+				"User-Agent":         "server",
+				"Sec-CH-UA-Platform": `"Android"`,
+				"Sec-CH-UA-Model":    `"Pixel 7"`,
+			},
+			device:         &openrtb2.Device{UA: "iPhone"},
+			expectedDevice: &openrtb2.Device{UA: "iPhone", DeviceType: adcom1.DevicePhone, OS: "iOS", OSV: "16.4", Make: "Apple", Model: "iPhone"},
+		},
+		{
+			description:    "Request sua is kept",
+			deviceDetector: mockDeviceDetector{},
+			headers:        map[string]string{"Sec-CH-UA-Platform": `"Android"`},
+			device:         &openrtb2.Device{SUA: &openrtb2.UserAgent{Platform: &openrtb2.BrandVersion{Brand: "iOS"}}},
+			expectedDevice: &openrtb2.Device{OS: "iOS", SUA: &openrtb2.UserAgent{Platform: &openrtb2.BrandVersion{Brand: "iOS"}}},
+		},
+	}
+
+	for _, test := range testCases {
+		httpReq := httptest.NewRequest("POST", "/openrtb2/auction", nil)
+		for name, value := range test.headers {
+			httpReq.Header.Set(name, value)
+		}
+		req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: test.device}}
+
+		setDeviceDetailsImplicitly(httpReq, req, test.deviceDetector)
+
+		assert.Equal(t, test.expectedDevice, req.Device, test.description)
+	}
+}
+
 func TestImplicitDNTEndToEnd(t *testing.T) {
 	var (
 		disabled int8 = 0
@@ -862,7 +1128,9 @@ func TestImplicitDNTEndToEnd(t *testing.T) {
 			[]byte{},
 			openrtb_ext.BuildBidderMap(),
 			empty_fetcher.EmptyFetcher{},
-			hooks.EmptyPlanBuilder{})
+			hooks.EmptyPlanBuilder{},
+			nil,
+			nil, nil)
 
 		httpReq := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, test.reqJSONFile)))
 		httpReq.Header.Set("DNT", test.dntHeader)
@@ -1091,6 +1359,9 @@ func TestStoredRequests(t *testing.T) {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
 	}
 
 	testStoreVideoAttr := []bool{true, true, false, false, false}
@@ -1465,6 +1736,9 @@ func TestValidateRequest(t *testing.T) {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
 	}
 
 	testCases := []struct {
@@ -1699,16 +1973,31 @@ func TestValidateRequestExt(t *testing.T) {
 			description:     "prebid cache - bids + vastxml - provided",
 			givenRequestExt: json.RawMessage(`{"prebid": {"cache": {"bids": {}, "vastxml": {}}}}`),
 		},
+		{
+			description:     "prebid auction - valid",
+			givenRequestExt: json.RawMessage(`{"prebid": {"auction": {"type": "second_price", "increment": 0.05, "reserve": "soft"}}}`),
+		},
+		{
+			description:     "prebid auction - type - invalid",
+			givenRequestExt: json.RawMessage(`{"prebid": {"auction": {"type": "third_price"}}}`),
+			expectedError:   `request.ext.prebid.auction is invalid: type "third_price" is not supported`,
+		},
+		{
+			description:     "prebid auction - increment - negative",
+			givenRequestExt: json.RawMessage(`{"prebid": {"auction": {"increment": -1}}}`),
+			expectedError:   `request.ext.prebid.auction is invalid: increment -1 must be a positive number`,
+		},
 	}
 
 	for _, test := range testCases {
 		w := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Ext: test.givenRequestExt}}
-		err := validateRequestExt(w)
+		errs := validateRequestExt(w)
 
 		if len(test.expectedError) > 0 {
-			assert.EqualError(t, err, test.expectedError, test.description)
+			assert.Len(t, errs, 1, test.description)
+			assert.EqualError(t, errs[0], test.expectedError, test.description)
 		} else {
-			assert.NoError(t, err, test.description)
+			assert.Empty(t, errs, test.description)
 		}
 	}
 }
@@ -1838,6 +2127,9 @@ func TestSetIntegrationType(t *testing.T) {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
 	}
 
 	testCases := []struct {
@@ -1903,6 +2195,9 @@ func TestStoredRequestGenerateUuid(t *testing.T) {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
 	}
 
 	req := &openrtb2.BidRequest{}
@@ -2006,6 +2301,9 @@ func TestOversizedRequest(t *testing.T) {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
 	}
 
 	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
@@ -2044,6 +2342,9 @@ func TestRequestSizeEdgeCase(t *testing.T) {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
 	}
 
 	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
@@ -2060,6 +2361,100 @@ func TestRequestSizeEdgeCase(t *testing.T) {
 	}
 }
 
+func TestReadRequestBody(t *testing.T) {
+	reqBody := validRequest(t, "site.json")
+
+	var gzipBody bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipBody)
+	gzipWriter.Write([]byte(reqBody))
+	gzipWriter.Close()
+
+	var deflateBody bytes.Buffer
+	deflateWriter := zlib.NewWriter(&deflateBody)
+	deflateWriter.Write([]byte(reqBody))
+	deflateWriter.Close()
+
+	testCases := []struct {
+		description         string
+		contentEncoding     string
+		body                []byte
+		maxRequestSize      int64
+		expectedCompression metrics.RequestCompression
+		expectedError       string
+	}{
+		{
+			description:    "Uncompressed",
+			body:           []byte(reqBody),
+			maxRequestSize: int64(len(reqBody)),
+		},
+		{
+			description:         "Gzip",
+			contentEncoding:     "gzip",
+			body:                gzipBody.Bytes(),
+			maxRequestSize:      int64(len(reqBody)),
+			expectedCompression: metrics.RequestCompressionGzip,
+		},
+		{
+			description:         "Deflate",
+			contentEncoding:     "Deflate",
+			body:                deflateBody.Bytes(),
+			maxRequestSize:      int64(len(reqBody)),
+			expectedCompression: metrics.RequestCompressionDeflate,
+		},
+		{
+			description:         "Gzip over the max size once decompressed",
+			contentEncoding:     "gzip",
+			body:                gzipBody.Bytes(),
+			maxRequestSize:      int64(len(reqBody) - 1),
+			expectedCompression: metrics.RequestCompressionGzip,
+			expectedError:       fmt.Sprintf("Request size exceeded max size of %d bytes.", len(reqBody)-1),
+		},
+		{
+			description:     "Malformed gzip",
+			contentEncoding: "gzip",
+			body:            []byte(reqBody),
+			maxRequestSize:  int64(len(reqBody)),
+			expectedError:   "Failed to decompress the gzip request body: gzip: invalid header",
+		},
+		{
+			description:     "Unsupported encoding",
+			contentEncoding: "br",
+			body:            []byte(reqBody),
+			maxRequestSize:  int64(len(reqBody)),
+			expectedError:   "Content-Encoding br is not supported, use gzip or deflate",
+		},
+	}
+
+	for _, test := range testCases {
+		metricsMock := &metrics.MetricsEngineMock{}
+		metricsMock.On("RecordCompressedRequest", test.expectedCompression).Return()
+		deps := &endpointDeps{
+			cfg:           &config.Configuration{MaxRequestSize: test.maxRequestSize},
+			metricsEngine: metricsMock,
+		}
+
+		req := httptest.NewRequest("POST", "/openrtb2/auction", bytes.NewReader(test.body))
+		if test.contentEncoding != "" {
+			req.Header.Set("Content-Encoding", test.contentEncoding)
+		}
+
+		body, err := deps.readRequestBody(req)
+
+		if test.expectedError != "" {
+			assert.EqualError(t, err, test.expectedError, test.description)
+		} else {
+			assert.NoError(t, err, test.description)
+			assert.Equal(t, reqBody, string(body), test.description)
+			assert.Empty(t, req.Header.Get("Content-Encoding"), test.description)
+		}
+		if test.expectedCompression != "" {
+			metricsMock.AssertCalled(t, "RecordCompressedRequest", test.expectedCompression)
+		} else {
+			metricsMock.AssertNotCalled(t, "RecordCompressedRequest", mock.Anything)
+		}
+	}
+}
+
 // TestNoEncoding prevents #231.
 func TestNoEncoding(t *testing.T) {
 	endpoint, _ := NewEndpoint(
@@ -2076,6 +2471,9 @@ func TestNoEncoding(t *testing.T) {
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+		nil,
 	)
 	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
 	recorder := httptest.NewRecorder()
@@ -2160,6 +2558,9 @@ func TestContentType(t *testing.T) {
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+		nil,
 	)
 	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
 	recorder := httptest.NewRecorder()
@@ -2380,6 +2781,9 @@ func TestValidateImpExt(t *testing.T) {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
 	}
 
 	for _, group := range testGroups {
@@ -2432,6 +2836,9 @@ func TestCurrencyTrunc(t *testing.T) {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -2479,6 +2886,9 @@ func TestCCPAInvalid(t *testing.T) {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -2530,6 +2940,9 @@ func TestNoSaleInvalid(t *testing.T) {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -2584,6 +2997,9 @@ func TestValidateSourceTID(t *testing.T) {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -2628,6 +3044,9 @@ func TestSChainInvalid(t *testing.T) {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -2980,6 +3399,9 @@ func TestEidPermissionsInvalid(t *testing.T) {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -3228,7 +3650,9 @@ func TestIOS14EndToEnd(t *testing.T) {
 		[]byte{},
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
-		hooks.EmptyPlanBuilder{})
+		hooks.EmptyPlanBuilder{},
+		nil,
+		nil, nil)
 
 	httpReq := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "app-ios140-no-ifa.json")))
 
@@ -3264,6 +3688,9 @@ func TestAuctionWarnings(t *testing.T) {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
 	}
 
 	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
@@ -3306,6 +3733,9 @@ func TestParseRequestParseImpInfoError(t *testing.T) {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
 	}
 
 	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
@@ -3777,7 +4207,9 @@ func TestAuctionResponseHeaders(t *testing.T) {
 		[]byte{},
 		openrtb_ext.BuildBidderMap(),
 		empty_fetcher.EmptyFetcher{},
-		hooks.EmptyPlanBuilder{})
+		hooks.EmptyPlanBuilder{},
+		nil,
+		nil, nil)
 
 	for _, test := range testCases {
 		httpReq := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.requestBody))
@@ -3881,6 +4313,9 @@ func TestParseRequestMergeBidderParams(t *testing.T) {
 				hardcodedResponseIPValidator{response: true},
 				empty_fetcher.EmptyFetcher{},
 				hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+				nil,
+				nil,
+				nil,
 			}
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
@@ -3984,6 +4419,9 @@ func TestParseRequestStoredResponses(t *testing.T) {
 				hardcodedResponseIPValidator{response: true},
 				&mockStoredResponseFetcher{mockStoredResponses},
 				hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+				nil,
+				nil,
+				nil,
 			}
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
@@ -4072,6 +4510,9 @@ func TestParseRequestStoredBidResponses(t *testing.T) {
 				hardcodedResponseIPValidator{response: true},
 				&mockStoredResponseFetcher{mockStoredBidResponses},
 				hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+				nil,
+				nil,
+				nil,
 			}
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
@@ -4106,6 +4547,9 @@ func TestValidateStoredResp(t *testing.T) {
 		hardcodedResponseIPValidator{response: true},
 		&mockStoredResponseFetcher{},
 		hookexecution.NewHookExecutor(hooks.EmptyPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
 	}
 
 	testCases := []struct {
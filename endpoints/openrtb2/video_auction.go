@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -24,8 +23,10 @@ import (
 	accountService "github.com/prebid/prebid-server/account"
 	"github.com/prebid/prebid-server/analytics"
 	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/devicedetection"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/exchange"
+	"github.com/prebid/prebid-server/geolocation"
 	"github.com/prebid/prebid-server/metrics"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/prebid/prebid-server/prebid_cache_client"
@@ -53,6 +54,9 @@ func NewVideoEndpoint(
 	defReqJSON []byte,
 	bidderMap map[string]openrtb_ext.BidderName,
 	cache prebid_cache_client.Client,
+	geoLocation geolocation.GeoLocation,
+	deviceDetector devicedetection.DeviceDetector,
+	uidStore usersync.UIDStore,
 ) (httprouter.Handle, error) {
 
 	if ex == nil || validator == nil || requestsById == nil || accounts == nil || cfg == nil || met == nil {
@@ -86,7 +90,10 @@ func NewVideoEndpoint(
 		videoEndpointRegexp,
 		ipValidator,
 		empty_fetcher.EmptyFetcher{},
-		&hookexecution.EmptyHookExecutor{}}).VideoAuctionEndpoint), nil
+		&hookexecution.EmptyHookExecutor{},
+		geoLocation,
+		deviceDetector,
+		uidStore}).VideoAuctionEndpoint), nil
 }
 
 /*
@@ -157,11 +164,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 
 	w.Header().Set("X-Prebid", version.BuildXPrebidHeader(version.Ver))
 
-	lr := &io.LimitedReader{
-		R: r.Body,
-		N: deps.cfg.MaxRequestSize,
-	}
-	requestJson, err := io.ReadAll(lr)
+	requestJson, err := deps.readRequestBody(r)
 	if err != nil {
 		handleError(&labels, w, []error{err}, &vo, &debugLog)
 		return
@@ -267,7 +270,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		defer cancel()
 	}
 
-	usersyncs := usersync.ParseCookieFromRequest(r, &(deps.cfg.HostCookie))
+	usersyncs := deps.parseUserSyncs(r)
 	if bidReqWrapper.App != nil {
 		labels.Source = metrics.DemandApp
 		labels.PubID = getAccountID(bidReqWrapper.App.Publisher)
@@ -287,6 +290,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		handleError(&labels, w, acctIDErrs, &vo, &debugLog)
 		return
 	}
+	vo.Account = account
 
 	secGPC := r.Header.Get("Sec-GPC")
 
@@ -302,7 +306,11 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		HookExecutor:               deps.hookExecutor,
 	}
 
-	response, err := deps.ex.HoldAuction(ctx, auctionRequest, &debugLog)
+	auctionResponse, err := deps.ex.HoldAuction(ctx, auctionRequest, &debugLog)
+	var response *openrtb2.BidResponse
+	if auctionResponse != nil {
+		response = auctionResponse.BidResponse
+	}
 	vo.Request = bidReqWrapper.BidRequest
 	vo.Response = response
 	if err != nil {
@@ -320,6 +328,8 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	}
 	if bidReq.Test == 1 {
 		bidResp.Ext = response.Ext
+	} else {
+		bidResp.Ext = seatNonBidResponseExt(response.Ext)
 	}
 
 	if len(bidResp.AdPods) == 0 && debugLog.DebugEnabledOrOverridden {
@@ -492,6 +502,23 @@ func minMax(array []int) (int, int) {
 	return min, max
 }
 
+// seatNonBidResponseExt returns a response ext holding only the seat non-bids of the auction response ext, or nil
+// if there are none because the request didn't set ext.prebid.returnallbidstatus.
+func seatNonBidResponseExt(responseExt json.RawMessage) json.RawMessage {
+	var ext openrtb_ext.ExtBidResponse
+	if err := json.Unmarshal(responseExt, &ext); err != nil || ext.Prebid == nil || len(ext.Prebid.SeatNonBid) == 0 {
+		return nil
+	}
+
+	seatNonBidExt, err := json.Marshal(openrtb_ext.ExtBidResponse{
+		Prebid: &openrtb_ext.ExtResponsePrebid{SeatNonBid: ext.Prebid.SeatNonBid},
+	})
+	if err != nil {
+		return nil
+	}
+	return seatNonBidExt
+}
+
 func buildVideoResponse(bidresponse *openrtb2.BidResponse, podErrors []PodError) (*openrtb_ext.BidResponseVideo, error) {
 
 	adPods := make([]*openrtb_ext.AdPod, 0)
@@ -504,7 +531,12 @@ func buildVideoResponse(bidresponse *openrtb2.BidResponse, podErrors []PodError)
 			if err := json.Unmarshal(bid.Ext, &tempRespBidExt); err != nil {
 				return nil, err
 			}
-			if tempRespBidExt.Prebid.Targeting[formatTargetingKey(openrtb_ext.HbVastCacheKey, seatBid.Seat)] == "" {
+			// extra multibid bids are targeted using their target bidder code rather than the seat
+			targetBidderCode := seatBid.Seat
+			if tempRespBidExt.Prebid.TargetBidderCode != "" {
+				targetBidderCode = tempRespBidExt.Prebid.TargetBidderCode
+			}
+			if tempRespBidExt.Prebid.Targeting[formatTargetingKey(openrtb_ext.HbVastCacheKey, targetBidderCode)] == "" {
 				continue
 			}
 
@@ -513,9 +545,9 @@ func buildVideoResponse(bidresponse *openrtb2.BidResponse, podErrors []PodError)
 			podId, _ := strconv.ParseInt(podNum, 0, 64)
 
 			videoTargeting := openrtb_ext.VideoTargeting{
-				HbPb:       tempRespBidExt.Prebid.Targeting[formatTargetingKey(openrtb_ext.HbpbConstantKey, seatBid.Seat)],
-				HbPbCatDur: tempRespBidExt.Prebid.Targeting[formatTargetingKey(openrtb_ext.HbCategoryDurationKey, seatBid.Seat)],
-				HbCacheID:  tempRespBidExt.Prebid.Targeting[formatTargetingKey(openrtb_ext.HbVastCacheKey, seatBid.Seat)],
+				HbPb:       tempRespBidExt.Prebid.Targeting[formatTargetingKey(openrtb_ext.HbpbConstantKey, targetBidderCode)],
+				HbPbCatDur: tempRespBidExt.Prebid.Targeting[formatTargetingKey(openrtb_ext.HbCategoryDurationKey, targetBidderCode)],
+				HbCacheID:  tempRespBidExt.Prebid.Targeting[formatTargetingKey(openrtb_ext.HbVastCacheKey, targetBidderCode)],
 			}
 
 			adPod := findAdPod(podId, adPods)
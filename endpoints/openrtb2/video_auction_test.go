@@ -673,6 +673,27 @@ func TestVideoBuildVideoResponseMissedCacheForOneBid(t *testing.T) {
 	assert.Equal(t, "17.00_456_30s", bidRespVideo.AdPods[0].Targeting[1].HbPbCatDur, "AdPod Targeting first element hb_pb_cat_dur should be 17.00_456_30s")
 }
 
+func TestVideoBuildVideoResponseMultiBid(t *testing.T) {
+	openRtbBidResp := openrtb2.BidResponse{}
+	podErrors := make([]PodError, 0)
+
+	bid1 := openrtb2.Bid{ImpID: "1_0"}
+	bid2 := openrtb2.Bid{ImpID: "1_1"}
+
+	bid1.Ext = []byte(`{"prebid":{"targetbiddercode":"appnexus","targeting":{"hb_bidder_appnexus":"appnexus","hb_pb_appnexus":"17.00","hb_pb_cat_dur_appnex":"17.00_123_30s","hb_uuid_appnexus":"837ea3b7-5598-4958-8c45-8e9ef2bf7cc1"}}}`)
+	bid2.Ext = []byte(`{"prebid":{"targetbiddercode":"apn2","targeting":{"hb_bidder_apn2":"apn2","hb_pb_apn2":"12.00","hb_pb_cat_dur_apn2":"12.00_456_30s","hb_uuid_apn2":"4e4a1c7c-2a6e-4b4a-9c47-6b5b3e1b8d8e"}}}`)
+
+	openRtbBidResp.SeatBid = []openrtb2.SeatBid{{Seat: "appnexus", Bid: []openrtb2.Bid{bid1, bid2}}}
+
+	bidRespVideo, err := buildVideoResponse(&openRtbBidResp, podErrors)
+	assert.NoError(t, err, "Should be no error")
+	assert.Len(t, bidRespVideo.AdPods, 1, "AdPods length should be 1")
+	assert.Len(t, bidRespVideo.AdPods[0].Targeting, 2, "AdPod Targeting length should be 2")
+	assert.Equal(t, "17.00_123_30s", bidRespVideo.AdPods[0].Targeting[0].HbPbCatDur, "AdPod Targeting first element hb_pb_cat_dur should be 17.00_123_30s")
+	assert.Equal(t, "12.00_456_30s", bidRespVideo.AdPods[0].Targeting[1].HbPbCatDur, "AdPod Targeting second element should use the multibid target bidder code")
+	assert.Equal(t, "12.00", bidRespVideo.AdPods[0].Targeting[1].HbPb, "AdPod Targeting second element should use the multibid target bidder code")
+}
+
 func TestVideoBuildVideoResponseMissedCacheForAllBids(t *testing.T) {
 	openRtbBidResp := openrtb2.BidResponse{}
 	podErrors := make([]PodError, 0)
@@ -759,6 +780,40 @@ func TestVideoBuildVideoResponseNoBids(t *testing.T) {
 	assert.Len(t, bidRespVideo.AdPods, 0, "AdPods length should be 0")
 }
 
+func TestSeatNonBidResponseExt(t *testing.T) {
+	testCases := []struct {
+		description string
+		responseExt json.RawMessage
+		expectedExt json.RawMessage
+	}{
+		{
+			description: "Seat non-bids",
+			responseExt: json.RawMessage(`{"warnings":{"general":[{"code":10002,"message":"debug"}]},"prebid":{"auctiontimestamp":1,"seatnonbid":[{"seat":"appnexus","nonbid":[{"impid":"imp1","statuscode":300}]}]}}`),
+			expectedExt: json.RawMessage(`{"prebid":{"seatnonbid":[{"seat":"appnexus","nonbid":[{"impid":"imp1","statuscode":300}]}]}}`),
+		},
+		{
+			description: "No seat non-bids",
+			responseExt: json.RawMessage(`{"prebid":{"auctiontimestamp":1}}`),
+		},
+		{
+			description: "No prebid ext",
+			responseExt: json.RawMessage(`{}`),
+		},
+		{
+			description: "No ext",
+		},
+	}
+
+	for _, test := range testCases {
+		ext := seatNonBidResponseExt(test.responseExt)
+		if test.expectedExt == nil {
+			assert.Nil(t, ext, test.description)
+		} else {
+			assert.JSONEq(t, string(test.expectedExt), string(ext), test.description)
+		}
+	}
+}
+
 func TestMergeOpenRTBToVideoRequest(t *testing.T) {
 	var bidReq = &openrtb2.BidRequest{}
 	var videoReq = &openrtb_ext.BidRequestVideo{}
@@ -1238,6 +1293,9 @@ func mockDepsWithMetrics(t *testing.T, ex *mockExchangeVideo) (*endpointDeps, *m
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		&hookexecution.EmptyHookExecutor{},
+		nil,
+		nil,
+		nil,
 	}
 	return deps, metrics, mockModule
 }
@@ -1283,6 +1341,9 @@ func mockDeps(t *testing.T, ex *mockExchangeVideo) *endpointDeps {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		&hookexecution.EmptyHookExecutor{},
+		nil,
+		nil,
+		nil,
 	}
 }
 
@@ -1306,6 +1367,9 @@ func mockDepsAppendBidderNames(t *testing.T, ex *mockExchangeAppendBidderNames)
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		&hookexecution.EmptyHookExecutor{},
+		nil,
+		nil,
+		nil,
 	}
 
 	return deps
@@ -1331,6 +1395,9 @@ func mockDepsNoBids(t *testing.T, ex *mockExchangeVideoNoBids) *endpointDeps {
 		hardcodedResponseIPValidator{response: true},
 		empty_fetcher.EmptyFetcher{},
 		&hookexecution.EmptyHookExecutor{},
+		nil,
+		nil,
+		nil,
 	}
 
 	return edep
@@ -1367,13 +1434,13 @@ type mockExchangeVideo struct {
 	cache       *mockCacheClient
 }
 
-func (m *mockExchangeVideo) HoldAuction(ctx context.Context, r exchange.AuctionRequest, debugLog *exchange.DebugLog) (*openrtb2.BidResponse, error) {
+func (m *mockExchangeVideo) HoldAuction(ctx context.Context, r exchange.AuctionRequest, debugLog *exchange.DebugLog) (*exchange.AuctionResponse, error) {
 	m.lastRequest = r.BidRequestWrapper.BidRequest
 	if debugLog != nil && debugLog.Enabled {
 		m.cache.called = true
 	}
 	ext := []byte(`{"prebid":{"targeting":{"hb_bidder_appnexus":"appnexus","hb_pb_appnexus":"20.00","hb_pb_cat_dur_appnex":"20.00_395_30s","hb_size":"1x1", "hb_uuid_appnexus":"837ea3b7-5598-4958-8c45-8e9ef2bf7cc1"},"type":"video","dealpriority":0,"dealtiersatisfied":false},"bidder":{"appnexus":{"brand_id":1,"auction_id":7840037870526938650,"bidder_id":2,"bid_ad_type":1,"creative_info":{"video":{"duration":30,"mimes":["video\/mp4"]}}}}}`)
-	return &openrtb2.BidResponse{
+	return &exchange.AuctionResponse{BidResponse: &openrtb2.BidResponse{
 		SeatBid: []openrtb2.SeatBid{{
 			Seat: "appnexus",
 			Bid: []openrtb2.Bid{
@@ -1395,7 +1462,7 @@ func (m *mockExchangeVideo) HoldAuction(ctx context.Context, r exchange.AuctionR
 				{ID: "16", ImpID: "5_2", Ext: ext},
 			},
 		}},
-	}, nil
+	}}, nil
 }
 
 type mockExchangeAppendBidderNames struct {
@@ -1403,13 +1470,13 @@ type mockExchangeAppendBidderNames struct {
 	cache       *mockCacheClient
 }
 
-func (m *mockExchangeAppendBidderNames) HoldAuction(ctx context.Context, r exchange.AuctionRequest, debugLog *exchange.DebugLog) (*openrtb2.BidResponse, error) {
+func (m *mockExchangeAppendBidderNames) HoldAuction(ctx context.Context, r exchange.AuctionRequest, debugLog *exchange.DebugLog) (*exchange.AuctionResponse, error) {
 	m.lastRequest = r.BidRequestWrapper.BidRequest
 	if debugLog != nil && debugLog.Enabled {
 		m.cache.called = true
 	}
 	ext := []byte(`{"prebid":{"targeting":{"hb_bidder_appnexus":"appnexus","hb_pb_appnexus":"20.00","hb_pb_cat_dur_appnex":"20.00_395_30s_appnexus","hb_size":"1x1", "hb_uuid_appnexus":"837ea3b7-5598-4958-8c45-8e9ef2bf7cc1"},"type":"video"},"bidder":{"appnexus":{"brand_id":1,"auction_id":7840037870526938650,"bidder_id":2,"bid_ad_type":1,"creative_info":{"video":{"duration":30,"mimes":["video\/mp4"]}}}}}`)
-	return &openrtb2.BidResponse{
+	return &exchange.AuctionResponse{BidResponse: &openrtb2.BidResponse{
 		SeatBid: []openrtb2.SeatBid{{
 			Seat: "appnexus",
 			Bid: []openrtb2.Bid{
@@ -1431,7 +1498,7 @@ func (m *mockExchangeAppendBidderNames) HoldAuction(ctx context.Context, r excha
 				{ID: "16", ImpID: "5_2", Ext: ext},
 			},
 		}},
-	}, nil
+	}}, nil
 }
 
 type mockExchangeVideoNoBids struct {
@@ -1439,11 +1506,11 @@ type mockExchangeVideoNoBids struct {
 	cache       *mockCacheClient
 }
 
-func (m *mockExchangeVideoNoBids) HoldAuction(ctx context.Context, r exchange.AuctionRequest, debugLog *exchange.DebugLog) (*openrtb2.BidResponse, error) {
+func (m *mockExchangeVideoNoBids) HoldAuction(ctx context.Context, r exchange.AuctionRequest, debugLog *exchange.DebugLog) (*exchange.AuctionResponse, error) {
 	m.lastRequest = r.BidRequestWrapper.BidRequest
-	return &openrtb2.BidResponse{
+	return &exchange.AuctionResponse{BidResponse: &openrtb2.BidResponse{
 		SeatBid: []openrtb2.SeatBid{{}},
-	}, nil
+	}}, nil
 }
 
 var mockVideoAccountData = map[string]json.RawMessage{
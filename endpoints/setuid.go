@@ -9,26 +9,24 @@ import (
 	"strings"
 	"time"
 
+	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
 	accountService "github.com/prebid/prebid-server/account"
 	"github.com/prebid/prebid-server/analytics"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/gdpr"
 	"github.com/prebid/prebid-server/metrics"
+	"github.com/prebid/prebid-server/privacy"
+	gppPrivacy "github.com/prebid/prebid-server/privacy/gpp"
 	"github.com/prebid/prebid-server/stored_requests"
 	"github.com/prebid/prebid-server/usersync"
 	"github.com/prebid/prebid-server/util/httputil"
 )
 
-const (
-	chromeStr       = "Chrome/"
-	chromeiOSStr    = "CriOS/"
-	chromeMinVer    = 67
-	chromeStrLen    = len(chromeStr)
-	chromeiOSStrLen = len(chromeiOSStr)
-)
+var errSetUIDActivityBlocked = errors.New("user sync is not allowed by the account activity controls")
+var errSetUIDGPPBlocked = errors.New("user sync is not allowed by the GPP string")
 
-func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, pbsanalytics analytics.PBSAnalyticsModule, accountsFetcher stored_requests.AccountFetcher, metricsEngine metrics.MetricsEngine) httprouter.Handle {
+func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, pbsanalytics analytics.PBSAnalyticsModule, accountsFetcher stored_requests.AccountFetcher, metricsEngine metrics.MetricsEngine, uidStore usersync.UIDStore) httprouter.Handle {
 	cookieTTL := time.Duration(cfg.HostCookie.TTL) * 24 * time.Hour
 
 	// convert map of syncers by bidder to map of syncers by key
@@ -37,6 +35,7 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 	for _, v := range syncersByBidder {
 		syncersByKey[v.Key()] = v
 	}
+	syncerPriorities := usersync.NewSyncerPriorities(syncersByBidder)
 
 	return httprouter.Handle(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 		so := analytics.SetUIDObject{
@@ -117,6 +116,36 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 			return
 		}
 
+		gppSID, err := gppPrivacy.ParseSID(query.Get("gpp_sid"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			metricsEngine.RecordSetUid(metrics.SetUidBadRequest)
+			so.Errors = []error{err}
+			so.Status = http.StatusBadRequest
+			return
+		}
+
+		if cfg.GPP.Enforce && !gppAllowsSync(gppPrivacy.Policy{Consent: query.Get("gpp"), SID: gppSID}, syncer.Key()) {
+			w.WriteHeader(http.StatusUnavailableForLegalReasons)
+			w.Write([]byte(errSetUIDGPPBlocked.Error()))
+			metricsEngine.RecordSetUid(metrics.SetUidGPPBlocked)
+			so.Errors = []error{errSetUIDGPPBlocked}
+			so.Status = http.StatusUnavailableForLegalReasons
+			return
+		}
+
+		activityControl := privacy.NewActivityControl(account.Activities)
+		component := privacy.Component{Type: config.ComponentTypeBidder, Name: syncer.Key()}
+		if !activityControl.Allow(privacy.ActivitySyncUser, component, privacy.ActivityRequest{GPPSID: gppSID}) {
+			w.WriteHeader(http.StatusUnavailableForLegalReasons)
+			w.Write([]byte(errSetUIDActivityBlocked.Error()))
+			metricsEngine.RecordSetUid(metrics.SetUidActivityBlocked)
+			so.Errors = []error{errSetUIDActivityBlocked}
+			so.Status = http.StatusUnavailableForLegalReasons
+			return
+		}
+
 		uid := query.Get("uid")
 		so.UID = uid
 
@@ -125,14 +154,19 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 			metricsEngine.RecordSetUid(metrics.SetUidOK)
 			metricsEngine.RecordSyncerSet(syncer.Key(), metrics.SyncerSetUidCleared)
 			so.Success = true
-		} else if err = pc.TrySync(syncer.Key(), uid); err == nil {
+		} else if err = pc.TrySyncWithTTL(syncer.Key(), uid, syncer.UIDTTL()); err == nil {
 			metricsEngine.RecordSetUid(metrics.SetUidOK)
 			metricsEngine.RecordSyncerSet(syncer.Key(), metrics.SyncerSetUidOK)
 			so.Success = true
 		}
 
-		setSiteCookie := siteCookieCheck(r.UserAgent())
-		pc.SetCookieOnResponse(w, setSiteCookie, &cfg.HostCookie, cookieTTL)
+		if uidStore != nil && so.Success {
+			storeUID(uidStore, usersync.UIDStoreHostCookieID(r, &cfg.HostCookie), syncer, uid)
+		}
+
+		cookieAttributes := usersync.NewCookieAttributes(&cfg.HostCookie, account.Cookie, r.UserAgent())
+		pc.SetCookieOnResponse(w, cookieAttributes, &cfg.HostCookie, cookieTTL, syncerPriorities)
+		usersync.SetHostCookieOnResponse(w, r, &cfg.HostCookie, cookieAttributes, cookieTTL)
 
 		switch responseFormat {
 		case "i":
@@ -148,6 +182,39 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 	})
 }
 
+// gppAllowsSync applies the GPP policy of the /setuid call the same way /cookie_sync does. A GPP string which
+// can't be parsed doesn't block the sync.
+func gppAllowsSync(policy gppPrivacy.Policy, bidder string) bool {
+	if policy.Consent == "" {
+		return true
+	}
+	parsedPolicy, err := policy.Parse()
+	if err != nil {
+		return true
+	}
+	return usersyncPrivacy{gppParsedPolicy: parsedPolicy}.GPPAllowsBidderSync(bidder)
+}
+
+// storeUID applies the /setuid call to the user syncs kept in the UID store, so that they survive the loss of
+// the uids cookie. It's only called once the privacy checks allowed the sync and the user didn't opt out, and
+// the stored uids expire after the UIDTTL of their syncer, like the ones of the uids cookie.
+func storeUID(uidStore usersync.UIDStore, id string, syncer usersync.Syncer, uid string) {
+	if id == "" {
+		return
+	}
+
+	err := usersync.UpdateUIDStore(uidStore, id, func(cookie *usersync.Cookie) {
+		if uid == "" {
+			cookie.Unsync(syncer.Key())
+		} else {
+			cookie.TrySyncWithTTL(syncer.Key(), uid, syncer.UIDTTL())
+		}
+	})
+	if err != nil {
+		glog.Errorf("Failed to store the uid of %s in the uid store: %v", syncer.Key(), err)
+	}
+}
+
 func getSyncer(query url.Values, syncersByKey map[string]usersync.Syncer) (usersync.Syncer, error) {
 	key := query.Get("bidder")
 
@@ -187,35 +254,6 @@ func getResponseFormat(query url.Values, syncer usersync.Syncer) (string, error)
 	return strings.ToLower(format[0]), nil
 }
 
-// siteCookieCheck scans the input User Agent string to check if browser is Chrome and browser version is greater than the minimum version for adding the SameSite cookie attribute
-func siteCookieCheck(ua string) bool {
-	result := false
-
-	index := strings.Index(ua, chromeStr)
-	criOSIndex := strings.Index(ua, chromeiOSStr)
-	if index != -1 {
-		result = checkChromeBrowserVersion(ua, index, chromeStrLen)
-	} else if criOSIndex != -1 {
-		result = checkChromeBrowserVersion(ua, criOSIndex, chromeiOSStrLen)
-	}
-
-	return result
-}
-
-func checkChromeBrowserVersion(ua string, index int, chromeStrLength int) bool {
-	result := false
-	vIndex := index + chromeStrLength
-	dotIndex := strings.Index(ua[vIndex:], ".")
-	if dotIndex == -1 {
-		dotIndex = len(ua[vIndex:])
-	}
-	version, _ := strconv.Atoi(ua[vIndex : vIndex+dotIndex])
-	if version >= chromeMinVer {
-		result = true
-	}
-	return result
-}
-
 func preventSyncsGDPR(gdprEnabled string, gdprConsent string, permsBuilder gdpr.PermissionsBuilder, tcf2Cfg gdpr.TCF2ConfigReader) (shouldReturn bool, status int, body string) {
 	if gdprEnabled != "" && gdprEnabled != "0" && gdprEnabled != "1" {
 		return true, http.StatusBadRequest, "the gdpr query param must be either 0 or 1. You gave " + gdprEnabled
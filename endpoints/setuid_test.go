@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -254,6 +255,76 @@ func TestSetUIDEndpoint(t *testing.T) {
 			expectedBody:           "account is disabled, please reach out to the prebid server host",
 			description:            "Set uid for valid bidder with valid disabled account provided",
 		},
+		{
+			uri:                    "/setuid?bidder=pubmatic&uid=123&account=activity_acct&gpp_sid=8",
+			syncersBidderNameToKey: map[string]string{"pubmatic": "pubmatic"},
+			existingSyncs:          nil,
+			gdprAllowsHostCookies:  true,
+			expectedSyncs:          nil,
+			expectedStatusCode:     http.StatusUnavailableForLegalReasons,
+			expectedBody:           "user sync is not allowed by the account activity controls",
+			description:            "Set uid blocked by account activity controls",
+		},
+		{
+			uri:                    "/setuid?bidder=pubmatic&uid=123&account=activity_acct&gpp_sid=7",
+			syncersBidderNameToKey: map[string]string{"pubmatic": "pubmatic"},
+			existingSyncs:          nil,
+			gdprAllowsHostCookies:  true,
+			expectedSyncs:          map[string]string{"pubmatic": "123"},
+			expectedStatusCode:     http.StatusOK,
+			expectedHeaders:        map[string]string{"Content-Type": "text/html", "Content-Length": "0"},
+			description:            "Set uid allowed by account activity controls for other GPP section",
+		},
+		{
+			uri:                    "/setuid?bidder=pubmatic&uid=123&gpp_sid=a",
+			syncersBidderNameToKey: map[string]string{"pubmatic": "pubmatic"},
+			existingSyncs:          nil,
+			gdprAllowsHostCookies:  true,
+			expectedSyncs:          nil,
+			expectedStatusCode:     http.StatusBadRequest,
+			expectedBody:           "gpp_sid a is invalid: a is not a section id",
+			description:            "Set uid with invalid gpp_sid",
+		},
+		{
+			uri:                    "/setuid?bidder=pubmatic&uid=123&gpp=DBABLA~BVVaqqqqCaA&gpp_sid=7",
+			syncersBidderNameToKey: map[string]string{"pubmatic": "pubmatic"},
+			existingSyncs:          nil,
+			gdprAllowsHostCookies:  true,
+			expectedSyncs:          nil,
+			expectedStatusCode:     http.StatusUnavailableForLegalReasons,
+			expectedBody:           "user sync is not allowed by the GPP string",
+			description:            "Set uid blocked by a GPP sale opt-out",
+		},
+		{
+			uri:                    "/setuid?bidder=pubmatic&uid=123&gpp=DBABLA~BVVqqqqqCaA&gpp_sid=7",
+			syncersBidderNameToKey: map[string]string{"pubmatic": "pubmatic"},
+			existingSyncs:          nil,
+			gdprAllowsHostCookies:  true,
+			expectedSyncs:          map[string]string{"pubmatic": "123"},
+			expectedStatusCode:     http.StatusOK,
+			expectedHeaders:        map[string]string{"Content-Type": "text/html", "Content-Length": "0"},
+			description:            "Set uid allowed by a GPP string without opt-out",
+		},
+		{
+			uri:                    "/setuid?bidder=pubmatic&uid=123&gpp=DBABLA~BVVaqqqqCaA&gpp_sid=8",
+			syncersBidderNameToKey: map[string]string{"pubmatic": "pubmatic"},
+			existingSyncs:          nil,
+			gdprAllowsHostCookies:  true,
+			expectedSyncs:          map[string]string{"pubmatic": "123"},
+			expectedStatusCode:     http.StatusOK,
+			expectedHeaders:        map[string]string{"Content-Type": "text/html", "Content-Length": "0"},
+			description:            "Set uid allowed by a GPP sale opt-out of a section not applicable",
+		},
+		{
+			uri:                    "/setuid?bidder=pubmatic&uid=123&gpp=malformed&gpp_sid=7",
+			syncersBidderNameToKey: map[string]string{"pubmatic": "pubmatic"},
+			existingSyncs:          nil,
+			gdprAllowsHostCookies:  true,
+			expectedSyncs:          map[string]string{"pubmatic": "123"},
+			expectedStatusCode:     http.StatusOK,
+			expectedHeaders:        map[string]string{"Content-Type": "text/html", "Content-Length": "0"},
+			description:            "Set uid with a malformed GPP string",
+		},
 	}
 
 	analytics := analyticsConf.NewPBSAnalytics(&config.Analytics{})
@@ -517,6 +588,28 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 				a.On("LogSetUIDObject", &expected).Once()
 			},
 		},
+		{
+			description:            "Account cookie domain outside the host cookie domain",
+			uri:                    "/setuid?bidder=pubmatic&uid=123&account=other_domain_acct",
+			cookies:                []*usersync.Cookie{},
+			syncersBidderNameToKey: map[string]string{"pubmatic": "pubmatic"},
+			gdprAllowsHostCookies:  true,
+			cfgAccountRequired:     true,
+			expectedResponseCode:   400,
+			expectedMetrics: func(m *metrics.MetricsEngineMock) {
+				m.On("RecordSetUid", metrics.SetUidAccountConfigMalformed).Once()
+			},
+			expectedAnalytics: func(a *MockAnalytics) {
+				expected := analytics.SetUIDObject{
+					Status:  400,
+					Bidder:  "pubmatic",
+					UID:     "",
+					Errors:  []error{errCookieSyncAccountConfigMalformed},
+					Success: false,
+				}
+				a.On("LogSetUIDObject", &expected).Once()
+			},
+		},
 		{
 			description:            "Invalid JSON account",
 			uri:                    "/setuid?bidder=pubmatic&uid=123&account=invalid_json_acct",
@@ -539,6 +632,48 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 				a.On("LogSetUIDObject", &expected).Once()
 			},
 		},
+		{
+			description:            "Blocked by activity controls",
+			uri:                    "/setuid?bidder=pubmatic&uid=123&account=activity_acct&gpp_sid=8",
+			cookies:                []*usersync.Cookie{},
+			syncersBidderNameToKey: map[string]string{"pubmatic": "pubmatic"},
+			gdprAllowsHostCookies:  true,
+			expectedResponseCode:   451,
+			expectedMetrics: func(m *metrics.MetricsEngineMock) {
+				m.On("RecordSetUid", metrics.SetUidActivityBlocked).Once()
+			},
+			expectedAnalytics: func(a *MockAnalytics) {
+				expected := analytics.SetUIDObject{
+					Status:  451,
+					Bidder:  "pubmatic",
+					UID:     "",
+					Errors:  []error{errSetUIDActivityBlocked},
+					Success: false,
+				}
+				a.On("LogSetUIDObject", &expected).Once()
+			},
+		},
+		{
+			description:            "Blocked by GPP",
+			uri:                    "/setuid?bidder=pubmatic&uid=123&gpp=DBABLA~BVVaqqqqCaA&gpp_sid=7",
+			cookies:                []*usersync.Cookie{},
+			syncersBidderNameToKey: map[string]string{"pubmatic": "pubmatic"},
+			gdprAllowsHostCookies:  true,
+			expectedResponseCode:   451,
+			expectedMetrics: func(m *metrics.MetricsEngineMock) {
+				m.On("RecordSetUid", metrics.SetUidGPPBlocked).Once()
+			},
+			expectedAnalytics: func(a *MockAnalytics) {
+				expected := analytics.SetUIDObject{
+					Status:  451,
+					Bidder:  "pubmatic",
+					UID:     "",
+					Errors:  []error{errSetUIDGPPBlocked},
+					Success: false,
+				}
+				a.On("LogSetUIDObject", &expected).Once()
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -573,26 +708,146 @@ func TestOptedOut(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, response.Code)
 }
 
-func TestSiteCookieCheck(t *testing.T) {
+func TestSetUIDEndpointUIDStore(t *testing.T) {
+	uidStore := usersync.NewMemoryUIDStore(time.Hour, 10)
+	syncersBidderNameToKey := map[string]string{"pubmatic": "pubmatic"}
+	analytics := analyticsConf.NewPBSAnalytics(&config.Analytics{})
+	metrics := &metricsConf.NilMetricsEngine{}
+
 	testCases := []struct {
-		ua             string
-		expectedResult bool
-		description    string
+		description           string
+		uri                   string
+		hostCookie            string
+		gdprAllowsHostCookies bool
+		expectedStatusCode    int
+		expectedUIDs          map[string]string
+	}{
+		{
+			description:           "Set",
+			uri:                   "/setuid?bidder=pubmatic&uid=123",
+			hostCookie:            "user",
+			gdprAllowsHostCookies: true,
+			expectedStatusCode:    http.StatusOK,
+			expectedUIDs:          map[string]string{"pubmatic": "123"},
+		},
+		{
+			description:           "Set without host cookie",
+			uri:                   "/setuid?bidder=pubmatic&uid=456",
+			gdprAllowsHostCookies: true,
+			expectedStatusCode:    http.StatusOK,
+			expectedUIDs:          map[string]string{"pubmatic": "123"},
+		},
+		{
+			description:           "First-party id query param is ignored",
+			uri:                   "/setuid?bidder=pubmatic&uid=789&fpid=user",
+			gdprAllowsHostCookies: true,
+			expectedStatusCode:    http.StatusOK,
+			expectedUIDs:          map[string]string{"pubmatic": "123"},
+		},
+		{
+			description:           "Prevented by GDPR",
+			uri:                   "/setuid?bidder=pubmatic&uid=789&gdpr=1&gdpr_consent=any",
+			hostCookie:            "user",
+			gdprAllowsHostCookies: false,
+			expectedStatusCode:    http.StatusUnavailableForLegalReasons,
+			expectedUIDs:          map[string]string{"pubmatic": "123"},
+		},
+		{
+			description:           "Prevented by the syncUser activity",
+			uri:                   "/setuid?bidder=pubmatic&uid=789&account=activity_acct&gpp_sid=8",
+			hostCookie:            "user",
+			gdprAllowsHostCookies: true,
+			expectedStatusCode:    http.StatusUnavailableForLegalReasons,
+			expectedUIDs:          map[string]string{"pubmatic": "123"},
+		},
+		{
+			description:           "Clear",
+			uri:                   "/setuid?bidder=pubmatic&uid=",
+			hostCookie:            "user",
+			gdprAllowsHostCookies: true,
+			expectedStatusCode:    http.StatusOK,
+			expectedUIDs:          map[string]string{},
+		},
+	}
+
+	for _, test := range testCases {
+		request := makeRequest(test.uri, nil)
+		if test.hostCookie != "" {
+			request.AddCookie(&http.Cookie{Name: "khaos", Value: test.hostCookie})
+		}
+		response := doRequestWithUIDStore(request, analytics, metrics, syncersBidderNameToKey, test.gdprAllowsHostCookies, false, false, false, uidStore)
+		assert.Equal(t, test.expectedStatusCode, response.Code, test.description)
+
+		stored, err := uidStore.Get("host:user")
+		if assert.NoError(t, err, test.description) && assert.NotNil(t, stored, test.description) {
+			assert.Equal(t, test.expectedUIDs, stored.GetUIDs(), test.description)
+		}
+
+		stored, err = uidStore.Get("user")
+		assert.NoError(t, err, test.description)
+		assert.Nil(t, stored, test.description)
+	}
+}
+
+func TestSetUIDEndpointCookieAttributes(t *testing.T) {
+	syncersBidderNameToKey := map[string]string{"pubmatic": "pubmatic"}
+	analytics := analyticsConf.NewPBSAnalytics(&config.Analytics{})
+	metrics := &metricsConf.NilMetricsEngine{}
+
+	testCases := []struct {
+		description       string
+		uri               string
+		ua                string
+		expectedSameSite  http.SameSite
+		expectedSecure    bool
+		expectedDomain    string
+		expectedPartition bool
 	}{
 		{
-			ua:             "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_14_0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/75.0.3770.142 Safari/537.36",
-			expectedResult: true,
-			description:    "Should return true for a valid chrome version",
+			description:      "Host defaults, modern chrome",
+			uri:              "/setuid?bidder=pubmatic&uid=123",
+			ua:               "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_14_0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/75.0.3770.142 Safari/537.36",
+			expectedSameSite: http.SameSiteNoneMode,
+			expectedSecure:   true,
+			expectedDomain:   "host.com",
 		},
 		{
+			description:    "Host defaults, old chrome",
+			uri:            "/setuid?bidder=pubmatic&uid=123",
 			ua:             "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_14_0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/65.0.3770.142 Safari/537.36",
-			expectedResult: false,
-			description:    "Should return false for chrome version below than the supported min version",
+			expectedDomain: "host.com",
+		},
+		{
+			description:      "Account config",
+			uri:              "/setuid?bidder=pubmatic&uid=123&account=cookie_acct",
+			ua:               "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_14_0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/75.0.3770.142 Safari/537.36",
+			expectedSameSite: http.SameSiteLaxMode,
+			expectedDomain:   "publisher.host.com",
+		},
+		{
+			description:       "Account partitioned config",
+			uri:               "/setuid?bidder=pubmatic&uid=123&account=partitioned_acct",
+			ua:                "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_14_0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/65.0.3770.142 Safari/537.36",
+			expectedSameSite:  http.SameSiteNoneMode,
+			expectedSecure:    true,
+			expectedDomain:    "host.com",
+			expectedPartition: true,
 		},
 	}
 
 	for _, test := range testCases {
-		assert.Equal(t, test.expectedResult, siteCookieCheck(test.ua), test.description)
+		request := makeRequest(test.uri, nil)
+		request.Header.Set("User-Agent", test.ua)
+		response := doRequest(request, analytics, metrics, syncersBidderNameToKey, true, false, false, false)
+		assert.Equal(t, http.StatusOK, response.Code, test.description)
+
+		httpCookies := response.Result().Cookies()
+		if assert.Len(t, httpCookies, 1, test.description) {
+			assert.Equal(t, test.expectedSameSite, httpCookies[0].SameSite, test.description)
+			assert.Equal(t, test.expectedSecure, httpCookies[0].Secure, test.description)
+			assert.Equal(t, test.expectedDomain, httpCookies[0].Domain, test.description)
+		}
+		assert.Equal(t, test.expectedPartition, strings.HasSuffix(response.Header().Get("Set-Cookie"), "; Partitioned"), test.description)
 	}
 }
 
@@ -705,11 +960,17 @@ func makeRequest(uri string, existingSyncs map[string]string) *http.Request {
 }
 
 func doRequest(req *http.Request, analytics analytics.PBSAnalyticsModule, metrics metrics.MetricsEngine, syncersBidderNameToKey map[string]string, gdprAllowsHostCookies, gdprReturnsError, gdprReturnsMalformedError, cfgAccountRequired bool) *httptest.ResponseRecorder {
+	return doRequestWithUIDStore(req, analytics, metrics, syncersBidderNameToKey, gdprAllowsHostCookies, gdprReturnsError, gdprReturnsMalformedError, cfgAccountRequired, nil)
+}
+
+func doRequestWithUIDStore(req *http.Request, analytics analytics.PBSAnalyticsModule, metrics metrics.MetricsEngine, syncersBidderNameToKey map[string]string, gdprAllowsHostCookies, gdprReturnsError, gdprReturnsMalformedError, cfgAccountRequired bool, uidStore usersync.UIDStore) *httptest.ResponseRecorder {
 	cfg := config.Configuration{
 		AccountRequired: cfgAccountRequired,
 		BlacklistedAcctMap: map[string]bool{
 			"blocked_acct": true,
 		},
+		GPP:        config.GPP{Enforce: true},
+		HostCookie: config.HostCookie{CookieName: "khaos", Domain: "host.com"},
 	}
 	cfg.MarshalAccountDefaults()
 
@@ -739,9 +1000,13 @@ func doRequest(req *http.Request, analytics analytics.PBSAnalyticsModule, metric
 		"disabled_acct":     json.RawMessage(`{"disabled":true}`),
 		"malformed_acct":    json.RawMessage(`{"disabled":"malformed"}`),
 		"invalid_json_acct": json.RawMessage(`{"}`),
+		"activity_acct":     json.RawMessage(`{"activities":{"syncUser":{"rules":[{"condition":{"componentName":["pubmatic"],"gppSid":[8]},"allow":false}]}}}`),
+		"cookie_acct":       json.RawMessage(`{"cookie":{"domain":"publisher.host.com","samesite":"lax"}}`),
+		"partitioned_acct":  json.RawMessage(`{"cookie":{"samesite":"lax","partitioned":true}}`),
+		"other_domain_acct": json.RawMessage(`{"cookie":{"domain":"publisher.com"}}`),
 	}}
 
-	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analytics, fakeAccountsFetcher, metrics)
+	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analytics, fakeAccountsFetcher, metrics, uidStore)
 	response := httptest.NewRecorder()
 	endpoint(response, req, nil)
 	return response
@@ -829,3 +1094,11 @@ func (s fakeSyncer) SupportsType(syncTypes []usersync.SyncType) bool {
 func (s fakeSyncer) GetSync(syncTypes []usersync.SyncType, privacyPolicies privacy.Policies) (usersync.Sync, error) {
 	return usersync.Sync{}, nil
 }
+
+func (s fakeSyncer) UIDTTL() time.Duration {
+	return 14 * 24 * time.Hour
+}
+
+func (s fakeSyncer) Priority() int {
+	return 0
+}
@@ -24,6 +24,12 @@ const (
 	BidderLevelDebugDisabledWarningCode
 	DisabledCurrencyConversionWarningCode
 	AlternateBidderCodeWarningCode
+	FloorWarningCode
+	MultiBidWarningCode
+	BidAdjustmentWarningCode
+	BidderUnhealthyWarningCode
+	BidValidationWarningCode
+	ActivityBlockedWarningCode
 )
 
 // Coder provides an error or warning code with severity.
@@ -22,7 +22,7 @@ func BuildAdapters(client *http.Client, cfg *config.Configuration, infos config.
 	for bidderName, bidder := range bidders {
 		info := infos[string(bidderName)]
 		exchangeBidder := AdaptBidder(bidder, client, cfg, me, bidderName, info.Debug, info.EndpointCompression)
-		exchangeBidder = addValidatedBidderMiddleware(exchangeBidder)
+		exchangeBidder = addValidatedBidderMiddleware(exchangeBidder, me)
 		exchangeBidders[bidderName] = exchangeBidder
 	}
 	return exchangeBidders, nil
@@ -39,7 +39,13 @@ func buildBidders(infos config.BidderInfos, builders map[openrtb_ext.BidderName]
 			continue
 		}
 
-		builder, builderFound := builders[bidderName]
+		// aliases declared in the bidder info files are built by the builder of their core bidder
+		builderName := bidderName
+		if info.AliasOf != "" {
+			builderName = openrtb_ext.BidderName(info.AliasOf)
+		}
+
+		builder, builderFound := builders[builderName]
 		if !builderFound {
 			errs = append(errs, fmt.Errorf("%v: builder not registered", bidder))
 			continue
@@ -66,6 +72,7 @@ func buildAdapterInfo(bidderInfo config.BidderInfo) config.Adapter {
 	adapter.PlatformID = bidderInfo.PlatformID
 	adapter.AppSecret = bidderInfo.AppSecret
 	adapter.XAPI = bidderInfo.XAPI
+	adapter.GenericORTB = bidderInfo.GenericORTB
 	return adapter
 }
 
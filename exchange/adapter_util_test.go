@@ -27,12 +27,12 @@ func TestBuildAdapters(t *testing.T) {
 	appnexusBidder, _ := appnexus.Builder(openrtb_ext.BidderAppnexus, config.Adapter{}, config.Server{})
 	appnexusBidderWithInfo := adapters.BuildInfoAwareBidder(appnexusBidder, infoEnabled)
 	appnexusBidderAdapted := AdaptBidder(appnexusBidderWithInfo, client, &config.Configuration{}, metricEngine, openrtb_ext.BidderAppnexus, nil, "")
-	appnexusValidated := addValidatedBidderMiddleware(appnexusBidderAdapted)
+	appnexusValidated := addValidatedBidderMiddleware(appnexusBidderAdapted, metricEngine)
 
 	rubiconBidder, _ := rubicon.Builder(openrtb_ext.BidderRubicon, config.Adapter{}, config.Server{})
 	rubiconBidderWithInfo := adapters.BuildInfoAwareBidder(rubiconBidder, infoEnabled)
 	rubiconBidderAdapted := AdaptBidder(rubiconBidderWithInfo, client, &config.Configuration{}, metricEngine, openrtb_ext.BidderRubicon, nil, "")
-	rubiconBidderValidated := addValidatedBidderMiddleware(rubiconBidderAdapted)
+	rubiconBidderValidated := addValidatedBidderMiddleware(rubiconBidderAdapted, metricEngine)
 
 	testCases := []struct {
 		description     string
@@ -140,6 +140,14 @@ func TestBuildBidders(t *testing.T) {
 				openrtb_ext.BidderRubicon:  adapters.BuildInfoAwareBidder(rubiconBidder, infoEnabled),
 			},
 		},
+		{
+			description: "Success - Alias Uses Core Bidder Builder",
+			bidderInfos: map[string]config.BidderInfo{"rubicon": {AliasOf: "appnexus"}},
+			builders:    map[openrtb_ext.BidderName]adapters.Builder{openrtb_ext.BidderAppnexus: appnexusBuilder},
+			expectedBidders: map[openrtb_ext.BidderName]adapters.Bidder{
+				openrtb_ext.BidderRubicon: adapters.BuildInfoAwareBidder(appnexusBidder, config.BidderInfo{AliasOf: "appnexus"}),
+			},
+		},
 		{
 			description: "Success - Ignores Disabled",
 			bidderInfos: map[string]config.BidderInfo{"appnexus": infoDisabled, "rubicon": infoEnabled},
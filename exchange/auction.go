@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -106,35 +107,119 @@ func (d *DebugLog) PutDebugLogError(cache prebid_cache_client.Client, timeout in
 
 func newAuction(seatBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, numImps int, preferDeals bool) *auction {
 	winningBids := make(map[string]*pbsOrtbBid, numImps)
-	winningBidsByBidder := make(map[string]map[openrtb_ext.BidderName]*pbsOrtbBid, numImps)
+	winningBidsByBidder := make(map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid, numImps)
 
 	for bidderName, seatBid := range seatBids {
 		if seatBid != nil {
 			for _, bid := range seatBid.bids {
-				cpm := bid.bid.Price
 				wbid, ok := winningBids[bid.bid.ImpID]
 				if !ok || isNewWinningBid(bid.bid, wbid.bid, preferDeals) {
 					winningBids[bid.bid.ImpID] = bid
 				}
 				if bidMap, ok := winningBidsByBidder[bid.bid.ImpID]; ok {
-					bestSoFar, ok := bidMap[bidderName]
-					if !ok || cpm > bestSoFar.bid.Price {
-						bidMap[bidderName] = bid
-					}
+					bidMap[bidderName] = append(bidMap[bidderName], bid)
 				} else {
-					winningBidsByBidder[bid.bid.ImpID] = make(map[openrtb_ext.BidderName]*pbsOrtbBid)
-					winningBidsByBidder[bid.bid.ImpID][bidderName] = bid
+					winningBidsByBidder[bid.bid.ImpID] = map[openrtb_ext.BidderName][]*pbsOrtbBid{
+						bidderName: {bid},
+					}
 				}
 			}
 		}
 	}
 
+	// sort the bids of each bidder so that the bidder's best bid comes first
+	for _, topBidsPerImp := range winningBidsByBidder {
+		for _, topBidsPerBidder := range topBidsPerImp {
+			sortBids(topBidsPerBidder, preferDeals)
+		}
+	}
+
 	return &auction{
 		winningBids:         winningBids,
 		winningBidsByBidder: winningBidsByBidder,
 	}
 }
 
+// applyMultiBidLimits drops the bids of each imp beyond maxbids for the bidders with a multibid entry. The bids are
+// ranked like the auction ranks them, so each bidder keeps its best bids. Dropped bids are recorded in seatNonBids.
+func applyMultiBidLimits(seatBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, multiBidMap map[string]openrtb_ext.ExtMultiBid, preferDeals bool, seatNonBids *nonBids) {
+	for bidderName, seatBid := range seatBids {
+		if seatBid == nil {
+			continue
+		}
+		multiBid, ok := multiBidMap[bidderName.String()]
+		if !ok || multiBid.MaxBids == nil {
+			continue
+		}
+
+		bidsByImp := make(map[string][]*pbsOrtbBid)
+		for _, bid := range seatBid.bids {
+			bidsByImp[bid.bid.ImpID] = append(bidsByImp[bid.bid.ImpID], bid)
+		}
+
+		droppedBids := make(map[*pbsOrtbBid]struct{})
+		for _, bids := range bidsByImp {
+			sortBids(bids, preferDeals)
+			for _, bid := range safeSlice(bids, *multiBid.MaxBids) {
+				droppedBids[bid] = struct{}{}
+				seatNonBids.addBid(bid, openrtb_ext.ResponseRejectedGeneral, bidderName.String())
+			}
+		}
+		if len(droppedBids) == 0 {
+			continue
+		}
+
+		bidsToKeep := make([]*pbsOrtbBid, 0, len(seatBid.bids)-len(droppedBids))
+		for _, bid := range seatBid.bids {
+			if _, dropped := droppedBids[bid]; !dropped {
+				bidsToKeep = append(bidsToKeep, bid)
+			}
+		}
+		seatBid.bids = bidsToKeep
+	}
+}
+
+// applyMultiBid keeps in winningBidsByBidder only the bids which receive targeting: every bid of the bidders with a
+// targetbiddercodeprefix, up to their maxbids, and the top bid of every other bidder.
+func (a *auction) applyMultiBid(multiBidMap map[string]openrtb_ext.ExtMultiBid) {
+	for _, topBidsPerImp := range a.winningBidsByBidder {
+		for bidderName, topBidsPerBidder := range topBidsPerImp {
+			bidderCodePrefix, maxBids := getMultiBidMeta(multiBidMap, bidderName.String())
+
+			targetedBids := openrtb_ext.DefaultBidLimit
+			if bidderCodePrefix != "" {
+				targetedBids = maxBids
+			}
+			if len(topBidsPerBidder) > targetedBids {
+				topBidsPerImp[bidderName] = topBidsPerBidder[:targetedBids]
+			}
+		}
+	}
+}
+
+// sortBids orders the bids from the best to the worst, as ranked by the auction
+func sortBids(bids []*pbsOrtbBid, preferDeals bool) {
+	sort.SliceStable(bids, func(i, j int) bool {
+		return isNewWinningBid(bids[i].bid, bids[j].bid, preferDeals)
+	})
+}
+
+// safeSlice returns the bids beyond the first n bids
+func safeSlice(bids []*pbsOrtbBid, n int) []*pbsOrtbBid {
+	if len(bids) <= n {
+		return nil
+	}
+	return bids[n:]
+}
+
+// getMultiBidMeta returns the targeting bidder code prefix and the maximum number of bids allowed for the bidder
+func getMultiBidMeta(multiBidMap map[string]openrtb_ext.ExtMultiBid, bidder string) (string, int) {
+	if multiBid, ok := multiBidMap[bidder]; ok && multiBid.MaxBids != nil {
+		return multiBid.TargetBidderCodePrefix, *multiBid.MaxBids
+	}
+	return "", openrtb_ext.DefaultBidLimit
+}
+
 // isNewWinningBid calculates if the new bid (nbid) will win against the current winning bid (wbid) given preferDeals.
 func isNewWinningBid(bid, wbid *openrtb2.Bid, preferDeals bool) bool {
 	if preferDeals {
@@ -148,11 +233,19 @@ func isNewWinningBid(bid, wbid *openrtb2.Bid, preferDeals bool) bool {
 	return bid.Price > wbid.Price
 }
 
-func (a *auction) setRoundedPrices(priceGranularity openrtb_ext.PriceGranularity) {
+// setRoundedPrices buckets the price of the top bids for targeting, with the price granularity of their media
+// type. Winning bids of second price auctions use their clearing price.
+func (a *auction) setRoundedPrices(targData *targetData) {
 	roundedPrices := make(map[*pbsOrtbBid]string, 5*len(a.winningBids))
 	for _, topBidsPerImp := range a.winningBidsByBidder {
-		for _, topBidPerBidder := range topBidsPerImp {
-			roundedPrices[topBidPerBidder] = GetPriceBucket(topBidPerBidder.bid.Price, priceGranularity)
+		for _, topBidsPerBidder := range topBidsPerImp {
+			for _, topBid := range topBidsPerBidder {
+				price := topBid.bid.Price
+				if topBid.clearingPrice > 0 {
+					price = topBid.clearingPrice
+				}
+				roundedPrices[topBid] = GetPriceBucket(price, targData.priceGranularityFor(topBid.bidType))
+			}
 		}
 	}
 	a.roundedPrices = roundedPrices
@@ -187,63 +280,65 @@ func (a *auction) doCache(ctx context.Context, cache prebid_cache_client.Client,
 		expByImp[imp.ID] = imp.Exp
 	}
 	for _, topBidsPerImp := range a.winningBidsByBidder {
-		for bidderName, topBidPerBidder := range topBidsPerImp {
-			impID := topBidPerBidder.bid.ImpID
-			isOverallWinner := a.winningBids[impID] == topBidPerBidder
-			if !includeBidderKeys && !isOverallWinner {
-				continue
-			}
-			var customCacheKey string
-			var catDur string
-			useCustomCacheKey := false
-			if competitiveExclusion && isOverallWinner || includeBidderKeys {
-				// set custom cache key for winning bid when competitive exclusion applies
-				catDur = bidCategory[topBidPerBidder.bid.ID]
-				if len(catDur) > 0 {
-					customCacheKey = fmt.Sprintf("%s_%s", catDur, hbCacheID)
-					useCustomCacheKey = true
+		for bidderName, topBidsPerBidder := range topBidsPerImp {
+			for _, topBidPerBidder := range topBidsPerBidder {
+				impID := topBidPerBidder.bid.ImpID
+				isOverallWinner := a.winningBids[impID] == topBidPerBidder
+				if !includeBidderKeys && !isOverallWinner {
+					continue
 				}
-			}
-			if bids {
-				if jsonBytes, err := json.Marshal(topBidPerBidder.bid); err == nil {
-					jsonBytes, err = evTracking.modifyBidJSON(topBidPerBidder, bidderName, jsonBytes)
-					if err != nil {
-						errs = append(errs, err)
+				var customCacheKey string
+				var catDur string
+				useCustomCacheKey := false
+				if competitiveExclusion && isOverallWinner || includeBidderKeys {
+					// set custom cache key for winning bid when competitive exclusion applies
+					catDur = bidCategory[topBidPerBidder.bid.ID]
+					if len(catDur) > 0 {
+						customCacheKey = fmt.Sprintf("%s_%s", catDur, hbCacheID)
+						useCustomCacheKey = true
 					}
-					if useCustomCacheKey {
-						// not allowed if bids is true; log error and cache normally
-						errs = append(errs, errors.New("cannot use custom cache key for non-vast bids"))
-					}
-					toCache = append(toCache, prebid_cache_client.Cacheable{
-						Type:       prebid_cache_client.TypeJSON,
-						Data:       jsonBytes,
-						TTLSeconds: cacheTTL(expByImp[impID], topBidPerBidder.bid.Exp, defTTL(topBidPerBidder.bidType, defaultTTLs), ttlBuffer),
-					})
-					bidIndices[len(toCache)-1] = topBidPerBidder.bid
-				} else {
-					errs = append(errs, err)
 				}
-			}
-			if vast && topBidPerBidder.bidType == openrtb_ext.BidTypeVideo {
-				vastXML := makeVAST(topBidPerBidder.bid)
-				if jsonBytes, err := json.Marshal(vastXML); err == nil {
-					if useCustomCacheKey {
+				if bids {
+					if jsonBytes, err := json.Marshal(topBidPerBidder.bid); err == nil {
+						jsonBytes, err = evTracking.modifyBidJSON(topBidPerBidder, bidderName, jsonBytes)
+						if err != nil {
+							errs = append(errs, err)
+						}
+						if useCustomCacheKey {
+							// not allowed if bids is true; log error and cache normally
+							errs = append(errs, errors.New("cannot use custom cache key for non-vast bids"))
+						}
 						toCache = append(toCache, prebid_cache_client.Cacheable{
-							Type:       prebid_cache_client.TypeXML,
+							Type:       prebid_cache_client.TypeJSON,
 							Data:       jsonBytes,
 							TTLSeconds: cacheTTL(expByImp[impID], topBidPerBidder.bid.Exp, defTTL(topBidPerBidder.bidType, defaultTTLs), ttlBuffer),
-							Key:        customCacheKey,
 						})
+						bidIndices[len(toCache)-1] = topBidPerBidder.bid
 					} else {
-						toCache = append(toCache, prebid_cache_client.Cacheable{
-							Type:       prebid_cache_client.TypeXML,
-							Data:       jsonBytes,
-							TTLSeconds: cacheTTL(expByImp[impID], topBidPerBidder.bid.Exp, defTTL(topBidPerBidder.bidType, defaultTTLs), ttlBuffer),
-						})
+						errs = append(errs, err)
+					}
+				}
+				if vast && topBidPerBidder.bidType == openrtb_ext.BidTypeVideo {
+					vastXML := makeVAST(topBidPerBidder.bid)
+					if jsonBytes, err := json.Marshal(vastXML); err == nil {
+						if useCustomCacheKey {
+							toCache = append(toCache, prebid_cache_client.Cacheable{
+								Type:       prebid_cache_client.TypeXML,
+								Data:       jsonBytes,
+								TTLSeconds: cacheTTL(expByImp[impID], topBidPerBidder.bid.Exp, defTTL(topBidPerBidder.bidType, defaultTTLs), ttlBuffer),
+								Key:        customCacheKey,
+							})
+						} else {
+							toCache = append(toCache, prebid_cache_client.Cacheable{
+								Type:       prebid_cache_client.TypeXML,
+								Data:       jsonBytes,
+								TTLSeconds: cacheTTL(expByImp[impID], topBidPerBidder.bid.Exp, defTTL(topBidPerBidder.bidType, defaultTTLs), ttlBuffer),
+							})
+						}
+						vastIndices[len(toCache)-1] = topBidPerBidder.bid
+					} else {
+						errs = append(errs, err)
 					}
-					vastIndices[len(toCache)-1] = topBidPerBidder.bid
-				} else {
-					errs = append(errs, err)
 				}
 			}
 		}
@@ -354,8 +449,8 @@ func defTTL(bidType openrtb_ext.BidType, defaultTTLs *config.DefaultTTLs) (ttl i
 type auction struct {
 	// winningBids is a map from imp.id to the highest overall CPM bid in that imp.
 	winningBids map[string]*pbsOrtbBid
-	// winningBidsByBidder stores the highest bid on each imp by each bidder.
-	winningBidsByBidder map[string]map[openrtb_ext.BidderName]*pbsOrtbBid
+	// winningBidsByBidder stores the bids on each imp by each bidder, highest bid first.
+	winningBidsByBidder map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid
 	// roundedPrices stores the price strings rounded for each bid according to the price granularity.
 	roundedPrices map[*pbsOrtbBid]string
 	// cacheIds stores the UUIDs from Prebid Cache for fetching the full bid JSON.
@@ -0,0 +1,208 @@
+package exchange
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// auctionPriceMacro is replaced with the clearing price in the markup and notification urls of the winning bids
+const auctionPriceMacro = "${AUCTION_PRICE}"
+
+// openrtbSecondPricePlus is the OpenRTB "at" value requesting a second price plus auction
+const openrtbSecondPricePlus = 2
+
+// auctionPricing defines how the price paid by the winning bid of each imp is computed
+type auctionPricing struct {
+	auctionType string
+	increment   float64
+	reserve     string
+}
+
+// getAuctionPricing resolves the auction pricing from the request ext.prebid.auction, the request "at" attribute
+// and the account, in that order of precedence. Since at=1 is the default set on every request, only at=2
+// overrides the account auction type.
+func getAuctionPricing(requestExt *openrtb_ext.ExtRequest, bidRequest *openrtb2.BidRequest, account config.Account) auctionPricing {
+	pricing := auctionPricing{
+		auctionType: account.Auction.Type,
+		increment:   account.Auction.Increment,
+		reserve:     account.Auction.Reserve,
+	}
+
+	if bidRequest != nil && bidRequest.AT == openrtbSecondPricePlus {
+		pricing.auctionType = openrtb_ext.AuctionTypeSecondPrice
+	}
+
+	if requestExt != nil && requestExt.Prebid.Auction != nil {
+		auction := requestExt.Prebid.Auction
+		if auction.Type != "" {
+			pricing.auctionType = auction.Type
+		}
+		if auction.Increment != nil {
+			pricing.increment = *auction.Increment
+		}
+		if auction.Reserve != "" {
+			pricing.reserve = auction.Reserve
+		}
+	}
+
+	if pricing.auctionType == "" {
+		pricing.auctionType = openrtb_ext.AuctionTypeFirstPrice
+	}
+	if pricing.reserve == "" {
+		pricing.reserve = openrtb_ext.ReserveTypeNone
+	}
+	return pricing
+}
+
+// applyAuctionPricing removes the non-deal bids priced below a hard reserve and, for second price auctions, sets the
+// clearing price of the winning bid of each imp. The imp floor is used as the reserve and the winning bids are the ones
+// the auction picks. The ${AUCTION_PRICE} macro in the markup and notification urls of the bids is replaced with their
+// clearing price, or with their own price in first price auctions and for the bids which didn't win. It returns the
+// remaining bids along with a rejection message for every bid removed. Removed bids are also recorded in seatNonBids.
+func applyAuctionPricing(bidRequest *openrtb2.BidRequest, pricing auctionPricing, seatBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, preferDeals bool, conversions currency.Conversions, seatNonBids *nonBids) (map[openrtb_ext.BidderName]*pbsOrtbSeatBid, []string) {
+	isSecondPrice := pricing.auctionType == openrtb_ext.AuctionTypeSecondPrice
+	isHardReserve := pricing.reserve == openrtb_ext.ReserveTypeHard
+
+	impReserves := make(map[string]openrtb2.Imp, len(bidRequest.Imp))
+	if pricing.reserve != openrtb_ext.ReserveTypeNone {
+		for _, imp := range bidRequest.Imp {
+			if imp.BidFloor > 0 {
+				impReserves[imp.ID] = imp
+			}
+		}
+	}
+
+	var rejections []string
+	bidReserves := make(map[*pbsOrtbBid]float64)
+	for bidderName, seatBid := range seatBids {
+		if seatBid == nil {
+			continue
+		}
+		validBids := make([]*pbsOrtbBid, 0, len(seatBid.bids))
+		for _, pbsBid := range seatBid.bids {
+			var reserve float64
+			if imp, hasReserve := impReserves[pbsBid.bid.ImpID]; hasReserve {
+				var err error
+				if reserve, err = convertReserve(imp, seatBid.currency, conversions); err != nil {
+					rejections = updateRejections(rejections, pbsBid.bid.ID, fmt.Sprintf("Unable to convert reserve currency %s to bid currency %s: %v", imp.BidFloorCur, seatBid.currency, err))
+					if isHardReserve {
+						seatNonBids.addBid(pbsBid, openrtb_ext.ResponseRejectedGeneral, bidderName.String())
+						continue
+					}
+				}
+			}
+
+			if isHardReserve && pbsBid.bid.DealID == "" && pbsBid.bid.Price < reserve {
+				reason := fmt.Sprintf("bid price value %.4f is less than the hard reserve value %.4f for impression id %s bidder %s", pbsBid.bid.Price, reserve, pbsBid.bid.ImpID, bidderName)
+				rejections = updateRejections(rejections, pbsBid.bid.ID, reason)
+				seatNonBids.addBid(pbsBid, openrtb_ext.ResponseRejectedBelowFloor, bidderName.String())
+				continue
+			}
+
+			validBids = append(validBids, pbsBid)
+			bidReserves[pbsBid] = reserve
+		}
+		seatBid.bids = validBids
+	}
+
+	if isSecondPrice {
+		auc := newAuction(seatBids, len(bidRequest.Imp), preferDeals)
+		for impID, winningBid := range auc.winningBids {
+			runnerUpPrice := getRunnerUpPrice(auc.winningBidsByBidder[impID], winningBid)
+			winningBid.clearingPrice = getClearingPrice(winningBid.bid, runnerUpPrice, bidReserves[winningBid], pricing)
+		}
+	}
+
+	for _, seatBid := range seatBids {
+		if seatBid == nil {
+			continue
+		}
+		for _, pbsBid := range seatBid.bids {
+			price := pbsBid.bid.Price
+			if pbsBid.clearingPrice > 0 {
+				price = pbsBid.clearingPrice
+			}
+			replaceAuctionPriceMacro(pbsBid.bid, price)
+		}
+	}
+
+	return seatBids, rejections
+}
+
+// getRunnerUpPrice returns the highest price bid on the imp by the seats other than the seat of the winning bid, so
+// that a seat bidding several times doesn't set its own clearing price.
+func getRunnerUpPrice(bidsBySeat map[openrtb_ext.BidderName][]*pbsOrtbBid, winningBid *pbsOrtbBid) float64 {
+	var runnerUpPrice float64
+	for _, bids := range bidsBySeat {
+		if containsBid(bids, winningBid) {
+			continue
+		}
+		for _, pbsBid := range bids {
+			if pbsBid.bid.Price > runnerUpPrice {
+				runnerUpPrice = pbsBid.bid.Price
+			}
+		}
+	}
+	return runnerUpPrice
+}
+
+func containsBid(bids []*pbsOrtbBid, bid *pbsOrtbBid) bool {
+	for _, b := range bids {
+		if b == bid {
+			return true
+		}
+	}
+	return false
+}
+
+// getClearingPrice returns the price paid by the winning bid of a second price auction. Deal bids, bids below a soft
+// reserve and bids without competition nor reserve pay their own price. Other bids pay the highest of the runner-up
+// price and the reserve plus one increment, up to their own price.
+func getClearingPrice(bid *openrtb2.Bid, runnerUpPrice, reserve float64, pricing auctionPricing) float64 {
+	if bid.DealID != "" || bid.Price < reserve {
+		return bid.Price
+	}
+
+	minPrice := math.Max(runnerUpPrice, reserve)
+	if minPrice == 0 {
+		return bid.Price
+	}
+	return math.Min(bid.Price, roundClearingPrice(minPrice+pricing.increment))
+}
+
+// roundClearingPrice removes the floating point noise added by the increment
+func roundClearingPrice(price float64) float64 {
+	return math.Round(price*10000) / 10000
+}
+
+// convertReserve returns the imp floor expressed in the bid currency
+func convertReserve(imp openrtb2.Imp, bidCur string, conversions currency.Conversions) (float64, error) {
+	floorCur := imp.BidFloorCur
+	if floorCur == "" {
+		floorCur = "USD"
+	}
+	if bidCur == "" {
+		bidCur = "USD"
+	}
+
+	rate, err := conversions.GetRate(floorCur, bidCur)
+	if err != nil {
+		return 0, err
+	}
+	return imp.BidFloor * rate, nil
+}
+
+// replaceAuctionPriceMacro substitutes the ${AUCTION_PRICE} macro in the bid markup and notification urls
+func replaceAuctionPriceMacro(bid *openrtb2.Bid, price float64) {
+	formattedPrice := strconv.FormatFloat(price, 'f', -1, 64)
+	bid.AdM = strings.ReplaceAll(bid.AdM, auctionPriceMacro, formattedPrice)
+	bid.NURL = strings.ReplaceAll(bid.NURL, auctionPriceMacro, formattedPrice)
+	bid.BURL = strings.ReplaceAll(bid.BURL, auctionPriceMacro, formattedPrice)
+}
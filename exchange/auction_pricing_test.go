@@ -0,0 +1,309 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAuctionPricing(t *testing.T) {
+	increment := 0.05
+	zero := 0.0
+	account := config.Account{Auction: config.AccountAuction{Type: openrtb_ext.AuctionTypeFirstPrice, Increment: 0.01, Reserve: openrtb_ext.ReserveTypeSoft}}
+
+	testCases := []struct {
+		description     string
+		givenRequestExt *openrtb_ext.ExtRequest
+		givenRequest    *openrtb2.BidRequest
+		givenAccount    config.Account
+		expectedPricing auctionPricing
+	}{
+		{
+			description:     "Defaults",
+			givenRequest:    &openrtb2.BidRequest{},
+			expectedPricing: auctionPricing{auctionType: openrtb_ext.AuctionTypeFirstPrice, reserve: openrtb_ext.ReserveTypeNone},
+		},
+		{
+			description:     "Account",
+			givenRequest:    &openrtb2.BidRequest{AT: 1},
+			givenAccount:    account,
+			expectedPricing: auctionPricing{auctionType: openrtb_ext.AuctionTypeFirstPrice, increment: 0.01, reserve: openrtb_ext.ReserveTypeSoft},
+		},
+		{
+			description:     "Request AT Second Price",
+			givenRequest:    &openrtb2.BidRequest{AT: 2},
+			givenAccount:    account,
+			expectedPricing: auctionPricing{auctionType: openrtb_ext.AuctionTypeSecondPrice, increment: 0.01, reserve: openrtb_ext.ReserveTypeSoft},
+		},
+		{
+			description: "Request Ext",
+			givenRequestExt: &openrtb_ext.ExtRequest{Prebid: openrtb_ext.ExtRequestPrebid{
+				Auction: &openrtb_ext.ExtRequestPrebidAuction{Type: openrtb_ext.AuctionTypeSecondPrice, Increment: &increment, Reserve: openrtb_ext.ReserveTypeHard},
+			}},
+			givenRequest:    &openrtb2.BidRequest{AT: 1},
+			givenAccount:    account,
+			expectedPricing: auctionPricing{auctionType: openrtb_ext.AuctionTypeSecondPrice, increment: 0.05, reserve: openrtb_ext.ReserveTypeHard},
+		},
+		{
+			description: "Request Ext Takes Precedence Over AT",
+			givenRequestExt: &openrtb_ext.ExtRequest{Prebid: openrtb_ext.ExtRequestPrebid{
+				Auction: &openrtb_ext.ExtRequestPrebidAuction{Type: openrtb_ext.AuctionTypeFirstPrice, Increment: &zero},
+			}},
+			givenRequest:    &openrtb2.BidRequest{AT: 2},
+			givenAccount:    account,
+			expectedPricing: auctionPricing{auctionType: openrtb_ext.AuctionTypeFirstPrice, increment: 0, reserve: openrtb_ext.ReserveTypeSoft},
+		},
+	}
+
+	for _, test := range testCases {
+		pricing := getAuctionPricing(test.givenRequestExt, test.givenRequest, test.givenAccount)
+		assert.Equal(t, test.expectedPricing, pricing, test.description)
+	}
+}
+
+func TestApplyAuctionPricing(t *testing.T) {
+	bidRequest := &openrtb2.BidRequest{
+		Imp: []openrtb2.Imp{
+			{ID: "imp1", BidFloor: 1, BidFloorCur: "EUR"},
+			{ID: "imp2"},
+			{ID: "imp3", BidFloor: 2, BidFloorCur: "USD"},
+		},
+	}
+	conversions := currency.NewRates(map[string]map[string]float64{"EUR": {"USD": 1.2}})
+
+	makeSeatBids := func() map[openrtb_ext.BidderName]*pbsOrtbSeatBid {
+		return map[openrtb_ext.BidderName]*pbsOrtbSeatBid{
+			"appnexus": {
+				currency: "USD",
+				bids: []*pbsOrtbBid{
+					{bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", Price: 3, NURL: "http://win?p=${AUCTION_PRICE}"}},
+					{bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp2", Price: 0.5}},
+					{bid: &openrtb2.Bid{ID: "bid3", ImpID: "imp3", Price: 1.5, AdM: "<img src=\"http://imp?p=${AUCTION_PRICE}\">"}},
+				},
+			},
+			"rubicon": {
+				currency: "USD",
+				bids: []*pbsOrtbBid{
+					{bid: &openrtb2.Bid{ID: "bid4", ImpID: "imp1", Price: 1}},
+					{bid: &openrtb2.Bid{ID: "bid5", ImpID: "imp2", Price: 0.4, DealID: "deal1", BURL: "http://bill?p=${AUCTION_PRICE}"}},
+				},
+			},
+		}
+	}
+
+	type expectedBid struct {
+		clearingPrice float64
+		nurl          string
+		adm           string
+		burl          string
+	}
+
+	testCases := []struct {
+		description        string
+		givenPricing       auctionPricing
+		givenPreferDeals   bool
+		expectedBids       map[string]expectedBid
+		expectedRejections []string
+	}{
+		{
+			description:  "First Price",
+			givenPricing: auctionPricing{auctionType: openrtb_ext.AuctionTypeFirstPrice, increment: 0.01, reserve: openrtb_ext.ReserveTypeSoft},
+			expectedBids: map[string]expectedBid{
+				"bid1": {nurl: "http://win?p=3"},
+				"bid2": {},
+				"bid3": {adm: "<img src=\"http://imp?p=1.5\">"},
+				"bid4": {},
+				"bid5": {burl: "http://bill?p=0.4"},
+			},
+		},
+		{
+			description:  "First Price - Hard Reserve",
+			givenPricing: auctionPricing{auctionType: openrtb_ext.AuctionTypeFirstPrice, increment: 0.01, reserve: openrtb_ext.ReserveTypeHard},
+			expectedBids: map[string]expectedBid{
+				"bid1": {nurl: "http://win?p=3"},
+				"bid2": {},
+				"bid5": {burl: "http://bill?p=0.4"},
+			},
+			expectedRejections: []string{
+				"bid rejected [bid ID: bid3] reason: bid price value 1.5000 is less than the hard reserve value 2.0000 for impression id imp3 bidder appnexus",
+				"bid rejected [bid ID: bid4] reason: bid price value 1.0000 is less than the hard reserve value 1.2000 for impression id imp1 bidder rubicon",
+			},
+		},
+		{
+			description:  "Second Price - No Reserve",
+			givenPricing: auctionPricing{auctionType: openrtb_ext.AuctionTypeSecondPrice, increment: 0.01, reserve: openrtb_ext.ReserveTypeNone},
+			expectedBids: map[string]expectedBid{
+				"bid1": {clearingPrice: 1.01, nurl: "http://win?p=1.01"},
+				"bid2": {clearingPrice: 0.41},
+				"bid3": {clearingPrice: 1.5, adm: "<img src=\"http://imp?p=1.5\">"},
+				"bid4": {},
+				"bid5": {burl: "http://bill?p=0.4"},
+			},
+		},
+		{
+			description:      "Second Price - Prefer Deals",
+			givenPricing:     auctionPricing{auctionType: openrtb_ext.AuctionTypeSecondPrice, increment: 0.01, reserve: openrtb_ext.ReserveTypeNone},
+			givenPreferDeals: true,
+			expectedBids: map[string]expectedBid{
+				"bid1": {clearingPrice: 1.01, nurl: "http://win?p=1.01"},
+				"bid2": {},
+				"bid3": {clearingPrice: 1.5, adm: "<img src=\"http://imp?p=1.5\">"},
+				"bid4": {},
+				"bid5": {clearingPrice: 0.4, burl: "http://bill?p=0.4"},
+			},
+		},
+		{
+			description:  "Second Price - Soft Reserve",
+			givenPricing: auctionPricing{auctionType: openrtb_ext.AuctionTypeSecondPrice, increment: 0.01, reserve: openrtb_ext.ReserveTypeSoft},
+			expectedBids: map[string]expectedBid{
+				"bid1": {clearingPrice: 1.21, nurl: "http://win?p=1.21"},
+				"bid2": {clearingPrice: 0.41},
+				"bid3": {clearingPrice: 1.5, adm: "<img src=\"http://imp?p=1.5\">"},
+				"bid4": {},
+				"bid5": {burl: "http://bill?p=0.4"},
+			},
+		},
+		{
+			description:  "Second Price - Hard Reserve",
+			givenPricing: auctionPricing{auctionType: openrtb_ext.AuctionTypeSecondPrice, increment: 0.01, reserve: openrtb_ext.ReserveTypeHard},
+			expectedBids: map[string]expectedBid{
+				"bid1": {clearingPrice: 1.21, nurl: "http://win?p=1.21"},
+				"bid2": {clearingPrice: 0.41},
+				"bid5": {burl: "http://bill?p=0.4"},
+			},
+			expectedRejections: []string{
+				"bid rejected [bid ID: bid3] reason: bid price value 1.5000 is less than the hard reserve value 2.0000 for impression id imp3 bidder appnexus",
+				"bid rejected [bid ID: bid4] reason: bid price value 1.0000 is less than the hard reserve value 1.2000 for impression id imp1 bidder rubicon",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		seatNonBids := nonBids{}
+		seatBids, rejections := applyAuctionPricing(bidRequest, test.givenPricing, makeSeatBids(), test.givenPreferDeals, conversions, &seatNonBids)
+
+		actualBids := make(map[string]expectedBid)
+		for _, seatBid := range seatBids {
+			for _, pbsBid := range seatBid.bids {
+				actualBids[pbsBid.bid.ID] = expectedBid{
+					clearingPrice: pbsBid.clearingPrice,
+					nurl:          pbsBid.bid.NURL,
+					adm:           pbsBid.bid.AdM,
+					burl:          pbsBid.bid.BURL,
+				}
+			}
+		}
+		assert.Equal(t, test.expectedBids, actualBids, test.description+":bids")
+		assert.ElementsMatch(t, test.expectedRejections, rejections, test.description+":rejections")
+		assert.Len(t, seatNonBids.get(), len(test.expectedRejections), test.description+":nonbids")
+	}
+}
+
+func TestApplyAuctionPricingRunnerUpFromOtherSeat(t *testing.T) {
+	bidRequest := &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}}}
+	pricing := auctionPricing{auctionType: openrtb_ext.AuctionTypeSecondPrice, increment: 0.01, reserve: openrtb_ext.ReserveTypeNone}
+
+	seatBids := map[openrtb_ext.BidderName]*pbsOrtbSeatBid{
+		"appnexus": {
+			currency: "USD",
+			bids: []*pbsOrtbBid{
+				{bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", Price: 2.9, NURL: "http://win?p=${AUCTION_PRICE}"}},
+				{bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp1", Price: 3, NURL: "http://win?p=${AUCTION_PRICE}"}},
+			},
+		},
+		"rubicon": {
+			currency: "USD",
+			bids: []*pbsOrtbBid{
+				{bid: &openrtb2.Bid{ID: "bid3", ImpID: "imp1", Price: 1, NURL: "http://win?p=${AUCTION_PRICE}"}},
+			},
+		},
+	}
+
+	seatBidsResult, rejections := applyAuctionPricing(bidRequest, pricing, seatBids, false, currency.NewRates(nil), &nonBids{})
+
+	assert.Empty(t, rejections)
+	appnexusBids := seatBidsResult["appnexus"].bids
+	assert.Equal(t, 0.0, appnexusBids[0].clearingPrice, "losing bid of the winning seat")
+	assert.Equal(t, "http://win?p=2.9", appnexusBids[0].bid.NURL, "losing bid of the winning seat")
+	assert.Equal(t, 1.01, appnexusBids[1].clearingPrice, "winning bid priced from the other seat")
+	assert.Equal(t, "http://win?p=1.01", appnexusBids[1].bid.NURL, "winning bid priced from the other seat")
+	assert.Equal(t, "http://win?p=1", seatBidsResult["rubicon"].bids[0].bid.NURL, "losing bid")
+}
+
+func TestGetClearingPrice(t *testing.T) {
+	pricing := auctionPricing{auctionType: openrtb_ext.AuctionTypeSecondPrice, increment: 0.1}
+
+	testCases := []struct {
+		description   string
+		givenBid      *openrtb2.Bid
+		givenRunnerUp float64
+		givenReserve  float64
+		expectedPrice float64
+	}{
+		{
+			description:   "Runner-Up Plus Increment",
+			givenBid:      &openrtb2.Bid{Price: 2},
+			givenRunnerUp: 1,
+			expectedPrice: 1.1,
+		},
+		{
+			description:   "Capped At Bid Price",
+			givenBid:      &openrtb2.Bid{Price: 2},
+			givenRunnerUp: 1.95,
+			expectedPrice: 2,
+		},
+		{
+			description:   "Reserve Above Runner-Up",
+			givenBid:      &openrtb2.Bid{Price: 2},
+			givenRunnerUp: 1,
+			givenReserve:  1.5,
+			expectedPrice: 1.6,
+		},
+		{
+			description:   "Below Soft Reserve",
+			givenBid:      &openrtb2.Bid{Price: 1},
+			givenRunnerUp: 0.5,
+			givenReserve:  1.5,
+			expectedPrice: 1,
+		},
+		{
+			description:   "No Competition",
+			givenBid:      &openrtb2.Bid{Price: 2},
+			expectedPrice: 2,
+		},
+		{
+			description:   "Deal",
+			givenBid:      &openrtb2.Bid{Price: 2, DealID: "deal1"},
+			givenRunnerUp: 1,
+			expectedPrice: 2,
+		},
+	}
+
+	for _, test := range testCases {
+		price := getClearingPrice(test.givenBid, test.givenRunnerUp, test.givenReserve, pricing)
+		assert.Equal(t, test.expectedPrice, price, test.description)
+	}
+}
+
+func TestSetRoundedPricesUsesClearingPrice(t *testing.T) {
+	winningBid := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", Price: 3}, clearingPrice: 1.25}
+	losingBid := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp1", Price: 1.35}}
+	auc := &auction{
+		winningBids: map[string]*pbsOrtbBid{"imp1": winningBid},
+		winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
+			"imp1": {
+				openrtb_ext.BidderAppnexus: {winningBid},
+				openrtb_ext.BidderRubicon:  {losingBid},
+			},
+		},
+	}
+
+	auc.setRoundedPrices(&targetData{priceGranularity: openrtb_ext.PriceGranularityFromString("med")})
+
+	assert.Equal(t, "1.20", auc.roundedPrices[winningBid])
+	assert.Equal(t, "1.30", auc.roundedPrices[losingBid])
+}
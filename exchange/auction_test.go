@@ -188,7 +188,7 @@ func loadCacheSpec(filename string) (*cacheSpec, error) {
 func runCacheSpec(t *testing.T, fileDisplayName string, specData *cacheSpec) {
 	var bid *pbsOrtbBid
 	winningBidsByImp := make(map[string]*pbsOrtbBid)
-	winningBidsByBidder := make(map[string]map[openrtb_ext.BidderName]*pbsOrtbBid)
+	winningBidsByBidder := make(map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid)
 	roundedPrices := make(map[*pbsOrtbBid]string)
 	bidCategory := make(map[string]string)
 
@@ -209,12 +209,12 @@ func runCacheSpec(t *testing.T, fileDisplayName string, specData *cacheSpec) {
 		// Map this bid if it's the highest we've seen from this bidder so far
 		if _, ok := winningBidsByBidder[bid.bid.ImpID]; ok {
 			bestSoFar, ok := winningBidsByBidder[bid.bid.ImpID][pbsBid.Bidder]
-			if !ok || cpm > bestSoFar.bid.Price {
-				winningBidsByBidder[bid.bid.ImpID][pbsBid.Bidder] = bid
+			if !ok || cpm > bestSoFar[0].bid.Price {
+				winningBidsByBidder[bid.bid.ImpID][pbsBid.Bidder] = []*pbsOrtbBid{bid}
 			}
 		} else {
-			winningBidsByBidder[bid.bid.ImpID] = make(map[openrtb_ext.BidderName]*pbsOrtbBid)
-			winningBidsByBidder[bid.bid.ImpID][pbsBid.Bidder] = bid
+			winningBidsByBidder[bid.bid.ImpID] = make(map[openrtb_ext.BidderName][]*pbsOrtbBid)
+			winningBidsByBidder[bid.bid.ImpID][pbsBid.Bidder] = []*pbsOrtbBid{bid}
 		}
 
 		if len(pbsBid.Bid.Cat) == 1 {
@@ -384,10 +384,10 @@ func TestNewAuction(t *testing.T) {
 				winningBids: map[string]*pbsOrtbBid{
 					"imp1": &bid1p230,
 				},
-				winningBidsByBidder: map[string]map[openrtb_ext.BidderName]*pbsOrtbBid{
+				winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
 					"imp1": {
-						"appnexus": &bid1p123,
-						"rubicon":  &bid1p230,
+						"appnexus": {&bid1p123},
+						"rubicon":  {&bid1p230},
 					},
 				},
 			},
@@ -412,15 +412,15 @@ func TestNewAuction(t *testing.T) {
 					"imp1": &bid1p230,
 					"imp2": &bid2p144,
 				},
-				winningBidsByBidder: map[string]map[openrtb_ext.BidderName]*pbsOrtbBid{
+				winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
 					"imp1": {
-						"appnexus": &bid1p230,
-						"rubicon":  &bid1p077,
-						"openx":    &bid1p123,
+						"appnexus": {&bid1p230},
+						"rubicon":  {&bid1p077},
+						"openx":    {&bid1p123},
 					},
 					"imp2": {
-						"appnexus": &bid2p123,
-						"rubicon":  &bid2p144,
+						"appnexus": {&bid2p123},
+						"rubicon":  {&bid2p144},
 					},
 				},
 			},
@@ -441,10 +441,10 @@ func TestNewAuction(t *testing.T) {
 				winningBids: map[string]*pbsOrtbBid{
 					"imp1": &bid1p123,
 				},
-				winningBidsByBidder: map[string]map[openrtb_ext.BidderName]*pbsOrtbBid{
+				winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
 					"imp1": {
-						"appnexus": &bid1p123,
-						"rubicon":  &bid1p088d,
+						"appnexus": {&bid1p123},
+						"rubicon":  {&bid1p088d},
 					},
 				},
 			},
@@ -465,10 +465,10 @@ func TestNewAuction(t *testing.T) {
 				winningBids: map[string]*pbsOrtbBid{
 					"imp1": &bid1p088d,
 				},
-				winningBidsByBidder: map[string]map[openrtb_ext.BidderName]*pbsOrtbBid{
+				winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
 					"imp1": {
-						"appnexus": &bid1p123,
-						"rubicon":  &bid1p088d,
+						"appnexus": {&bid1p123},
+						"rubicon":  {&bid1p088d},
 					},
 				},
 			},
@@ -489,10 +489,10 @@ func TestNewAuction(t *testing.T) {
 				winningBids: map[string]*pbsOrtbBid{
 					"imp1": &bid1p166d,
 				},
-				winningBidsByBidder: map[string]map[openrtb_ext.BidderName]*pbsOrtbBid{
+				winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
 					"imp1": {
-						"appnexus": &bid1p166d,
-						"rubicon":  &bid1p088d,
+						"appnexus": {&bid1p166d},
+						"rubicon":  {&bid1p088d},
 					},
 				},
 			},
@@ -516,11 +516,35 @@ func TestNewAuction(t *testing.T) {
 				winningBids: map[string]*pbsOrtbBid{
 					"imp1": &bid1p166d,
 				},
-				winningBidsByBidder: map[string]map[openrtb_ext.BidderName]*pbsOrtbBid{
+				winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
 					"imp1": {
-						"appnexus": &bid1p166d,
-						"rubicon":  &bid1p088d,
-						"openx":    &bid1p230,
+						"appnexus": {&bid1p166d},
+						"rubicon":  {&bid1p088d},
+						"openx":    {&bid1p230},
+					},
+				},
+			},
+		},
+		{
+			description: "Auction with multiple bids from the same bidder",
+			seatBids: map[openrtb_ext.BidderName]*pbsOrtbSeatBid{
+				"appnexus": {
+					bids: []*pbsOrtbBid{&bid1p077, &bid1p230, &bid1p123},
+				},
+				"rubicon": {
+					bids: []*pbsOrtbBid{&bid1p088d},
+				},
+			},
+			numImps:     1,
+			preferDeals: false,
+			expectedAuction: auction{
+				winningBids: map[string]*pbsOrtbBid{
+					"imp1": &bid1p230,
+				},
+				winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
+					"imp1": {
+						"appnexus": {&bid1p230, &bid1p123, &bid1p077},
+						"rubicon":  {&bid1p088d},
 					},
 				},
 			},
@@ -535,6 +559,131 @@ func TestNewAuction(t *testing.T) {
 
 }
 
+func TestApplyMultiBidLimits(t *testing.T) {
+	maxBids2 := 2
+	bid1p077 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "1", ImpID: "imp1", Price: 0.77}}
+	bid1p123 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "2", ImpID: "imp1", Price: 1.23}}
+	bid1p230 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "3", ImpID: "imp1", Price: 2.30}}
+	bid1p050Deal := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "4", ImpID: "imp1", Price: 0.50, DealID: "deal"}}
+	bid2p100 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "5", ImpID: "imp2", Price: 1.00}}
+	bid3p100 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "6", ImpID: "imp1", Price: 1.00}}
+	bid3p050 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "7", ImpID: "imp1", Price: 0.50}}
+
+	tests := []struct {
+		description         string
+		multiBidMap         map[string]openrtb_ext.ExtMultiBid
+		preferDeals         bool
+		expectedSeatBidsIDs map[openrtb_ext.BidderName][]string
+		expectedNonBidIDs   []string
+	}{
+		{
+			description: "No multibid, all bids kept",
+			expectedSeatBidsIDs: map[openrtb_ext.BidderName][]string{
+				"appnexus": {"1", "2", "3", "4", "5"},
+				"rubicon":  {"6", "7"},
+			},
+		},
+		{
+			description: "Multibid, lowest bids of each imp dropped",
+			multiBidMap: map[string]openrtb_ext.ExtMultiBid{
+				"appnexus": {Bidder: "appnexus", MaxBids: &maxBids2},
+			},
+			expectedSeatBidsIDs: map[openrtb_ext.BidderName][]string{
+				"appnexus": {"2", "3", "5"},
+				"rubicon":  {"6", "7"},
+			},
+			expectedNonBidIDs: []string{"1", "4"},
+		},
+		{
+			description: "Multibid with preferdeals, deal bid kept",
+			multiBidMap: map[string]openrtb_ext.ExtMultiBid{
+				"appnexus": {Bidder: "appnexus", MaxBids: &maxBids2},
+			},
+			preferDeals: true,
+			expectedSeatBidsIDs: map[openrtb_ext.BidderName][]string{
+				"appnexus": {"3", "4", "5"},
+				"rubicon":  {"6", "7"},
+			},
+			expectedNonBidIDs: []string{"2", "1"},
+		},
+	}
+
+	for _, test := range tests {
+		seatBids := map[openrtb_ext.BidderName]*pbsOrtbSeatBid{
+			"appnexus": {bids: []*pbsOrtbBid{bid1p077, bid1p123, bid1p230, bid1p050Deal, bid2p100}},
+			"rubicon":  {bids: []*pbsOrtbBid{bid3p100, bid3p050}},
+		}
+		seatNonBids := nonBids{}
+		applyMultiBidLimits(seatBids, test.multiBidMap, test.preferDeals, &seatNonBids)
+
+		for bidderName, expectedIDs := range test.expectedSeatBidsIDs {
+			assert.Equal(t, expectedIDs, bidIDs(seatBids[bidderName]), test.description)
+		}
+
+		var nonBidIDs []string
+		for _, nonBid := range seatNonBids.seatNonBidsMap["appnexus"] {
+			assert.Equal(t, openrtb_ext.ResponseRejectedGeneral, nonBid.StatusCode, test.description)
+			nonBidIDs = append(nonBidIDs, nonBid.Ext.Prebid.Bid.ID)
+		}
+		assert.Equal(t, test.expectedNonBidIDs, nonBidIDs, test.description)
+		assert.Empty(t, seatNonBids.seatNonBidsMap["rubicon"], test.description)
+	}
+}
+
+func TestApplyMultiBid(t *testing.T) {
+	maxBids2 := 2
+	bid1p077 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "1", ImpID: "imp1", Price: 0.77}}
+	bid1p123 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "2", ImpID: "imp1", Price: 1.23}}
+	bid1p230 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "3", ImpID: "imp1", Price: 2.30}}
+	bid1p100 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "4", ImpID: "imp1", Price: 1.00}}
+	bid1p050 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "5", ImpID: "imp1", Price: 0.50}}
+
+	tests := []struct {
+		description      string
+		multiBidMap      map[string]openrtb_ext.ExtMultiBid
+		expectedByBidder map[openrtb_ext.BidderName][]*pbsOrtbBid
+	}{
+		{
+			description: "No multibid, top bid targeted",
+			expectedByBidder: map[openrtb_ext.BidderName][]*pbsOrtbBid{
+				"appnexus": {bid1p230},
+				"rubicon":  {bid1p100},
+			},
+		},
+		{
+			description: "Multibid with prefix, kept bids targeted",
+			multiBidMap: map[string]openrtb_ext.ExtMultiBid{
+				"appnexus": {Bidder: "appnexus", MaxBids: &maxBids2, TargetBidderCodePrefix: "apn"},
+			},
+			expectedByBidder: map[openrtb_ext.BidderName][]*pbsOrtbBid{
+				"appnexus": {bid1p230, bid1p123},
+				"rubicon":  {bid1p100},
+			},
+		},
+		{
+			description: "Multibid without prefix, top bid targeted",
+			multiBidMap: map[string]openrtb_ext.ExtMultiBid{
+				"appnexus": {Bidder: "appnexus", MaxBids: &maxBids2},
+			},
+			expectedByBidder: map[openrtb_ext.BidderName][]*pbsOrtbBid{
+				"appnexus": {bid1p230},
+				"rubicon":  {bid1p100},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		seatBids := map[openrtb_ext.BidderName]*pbsOrtbSeatBid{
+			"appnexus": {bids: []*pbsOrtbBid{bid1p077, bid1p123, bid1p230}},
+			"rubicon":  {bids: []*pbsOrtbBid{bid1p100, bid1p050}},
+		}
+		auc := newAuction(seatBids, 1, false)
+		auc.applyMultiBid(test.multiBidMap)
+
+		assert.Equal(t, test.expectedByBidder, auc.winningBidsByBidder["imp1"], test.description)
+	}
+}
+
 type cacheSpec struct {
 	BidRequest                  openrtb2.BidRequest             `json:"bidRequest"`
 	PbsBids                     []pbsBid                        `json:"pbsBids"`
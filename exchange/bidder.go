@@ -19,12 +19,14 @@ import (
 	"github.com/prebid/prebid-server/config/util"
 	"github.com/prebid/prebid-server/currency"
 	"github.com/prebid/prebid-server/experiment/adscert"
+	"github.com/prebid/prebid-server/hooks/hookexecution"
 	"github.com/prebid/prebid-server/version"
 
 	nativeRequests "github.com/prebid/openrtb/v17/native1/request"
 	nativeResponse "github.com/prebid/openrtb/v17/native1/response"
 	"github.com/prebid/openrtb/v17/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/bidadjustment"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/metrics"
@@ -54,7 +56,7 @@ type AdaptedBidder interface {
 	//
 	// Any errors will be user-facing in the API.
 	// Error messages should help publishers understand what might account for "bad" bids.
-	requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestOptions bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes) ([]*pbsOrtbSeatBid, []error)
+	requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestOptions bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes, hookExecutor hookexecution.StageExecutor) ([]*pbsOrtbSeatBid, []error)
 }
 
 // bidRequestOptions holds additional options for bid request execution to maintain clean code and reasonable number of parameters
@@ -63,6 +65,8 @@ type bidRequestOptions struct {
 	headerDebugAllowed  bool
 	addCallSignHeader   bool
 	bidAdjustments      map[string]float64
+	bidAdjustmentRules  bidadjustment.Rules
+	bidValidations      config.AccountBidValidations
 }
 
 const ImpIdReqBody = "Stored bid response for impression id: "
@@ -90,6 +94,9 @@ type pbsOrtbBid struct {
 	generatedBidID    string
 	originalBidCPM    float64
 	originalBidCur    string
+	targetBidderCode  string
+	bidAdjustment     *openrtb_ext.ExtBidPrebidAdjustment
+	clearingPrice     float64
 }
 
 // pbsOrtbSeatBid is a SeatBid returned by an AdaptedBidder.
@@ -106,6 +113,9 @@ type pbsOrtbSeatBid struct {
 	httpCalls []*openrtb_ext.ExtHttpCall
 	// seat defines whom these extra bids belong to.
 	seat string
+	// nonBids lists the bids of this seat which were dropped before reaching the auction.
+	// These are reported in bidresponse.ext.prebid.seatnonbid when all bid statuses are requested.
+	nonBids []openrtb_ext.NonBid
 }
 
 // Possible values of compression types Prebid Server can support for bidder compression
@@ -154,7 +164,12 @@ type bidderAdapterConfig struct {
 	EndpointCompression string
 }
 
-func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestOptions bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes) ([]*pbsOrtbSeatBid, []error) {
+func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestOptions bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes, hookExecutor hookexecution.StageExecutor) ([]*pbsOrtbSeatBid, []error) {
+
+	reject := hookExecutor.ExecuteBidderRequestStage(bidderRequest.BidRequest, string(bidderRequest.BidderName))
+	if reject != nil {
+		return nil, []error{reject}
+	}
 
 	var reqData []*adapters.RequestData
 	var errs []error
@@ -266,6 +281,12 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 			errs = append(errs, moreErrs...)
 
 			if bidResponse != nil {
+				reject := hookExecutor.ExecuteRawBidderResponseStage(bidResponse, string(bidderRequest.BidderName))
+				if reject != nil {
+					errs = append(errs, reject)
+					continue
+				}
+
 				// Setup default currency as `USD` is not set in bid request nor bid response
 				if bidResponse.Currency == "" {
 					bidResponse.Currency = defaultCurrency
@@ -353,9 +374,33 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 						}
 
 						originalBidCpm := 0.0
+						var bidAdjustment *openrtb_ext.ExtBidPrebidAdjustment
 						if bidResponse.Bids[i].Bid != nil {
 							originalBidCpm = bidResponse.Bids[i].Bid.Price
+							adjusted := false
+							if len(bidRequestOptions.bidAdjustmentRules) > 0 {
+								bidInfo := bidadjustment.BidInfo{
+									MediaType: bidadjustment.MediaType(bidResponse.Bids[i].BidType, findImp(bidderRequest.BidRequest.Imp, bidResponse.Bids[i].Bid.ImpID)),
+									Bidders:   []string{bidderName.String(), bidderRequest.BidderName.String()},
+									DealID:    bidResponse.Bids[i].Bid.DealID,
+									Price:     bidResponse.Bids[i].Bid.Price,
+									Currency:  bidResponse.Currency,
+								}
+								bidResponse.Bids[i].Bid.Price, adjusted = bidRequestOptions.bidAdjustmentRules.Apply(bidInfo, conversions)
+							}
+							if adjusted {
+								// a matching bid adjustment rule replaces the legacy bidadjustmentfactors
+								adjustmentFactor = 1.0
+							}
 							bidResponse.Bids[i].Bid.Price = bidResponse.Bids[i].Bid.Price * adjustmentFactor * conversionRate
+							if adjusted {
+								bidAdjustment = &openrtb_ext.ExtBidPrebidAdjustment{
+									OriginalBidCPM: originalBidCpm,
+									OriginalBidCur: bidResponse.Currency,
+									AdjustedBidCPM: bidResponse.Bids[i].Bid.Price,
+									AdjustedBidCur: seatBidMap[bidderRequest.BidderName].currency,
+								}
+							}
 						}
 
 						if _, ok := seatBidMap[bidderName]; !ok {
@@ -377,11 +422,19 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 							dealPriority:   bidResponse.Bids[i].DealPriority,
 							originalBidCPM: originalBidCpm,
 							originalBidCur: bidResponse.Currency,
+							bidAdjustment:  bidAdjustment,
 						})
 					}
 				} else {
 					// If no conversions found, do not handle the bid
 					errs = append(errs, err)
+					for _, typedBid := range bidResponse.Bids {
+						if typedBid == nil || typedBid.Bid == nil {
+							continue
+						}
+						droppedBid := &pbsOrtbBid{bid: typedBid.Bid, originalBidCPM: typedBid.Bid.Price, originalBidCur: bidResponse.Currency}
+						seatBidMap[bidderRequest.BidderName].nonBids = append(seatBidMap[bidderRequest.BidderName].nonBids, newNonBid(droppedBid, openrtb_ext.ResponseRejectedGeneral))
+					}
 				}
 			}
 		} else {
@@ -471,6 +524,15 @@ func getNativeImpByImpID(impID string, request *openrtb2.BidRequest) (*openrtb2.
 	return nil, errors.New("Could not find native imp")
 }
 
+func findImp(imps []openrtb2.Imp, impID string) *openrtb2.Imp {
+	for i := range imps {
+		if imps[i].ID == impID {
+			return &imps[i]
+		}
+	}
+	return nil
+}
+
 func getAssetByID(id int64, assets []nativeRequests.Asset) (nativeRequests.Asset, error) {
 	for _, asset := range assets {
 		if id == asset.ID {
@@ -17,14 +17,18 @@ import (
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/prebid/openrtb/v17/adcom1"
 	nativeRequests "github.com/prebid/openrtb/v17/native1/request"
 	nativeResponse "github.com/prebid/openrtb/v17/native1/response"
 	"github.com/prebid/openrtb/v17/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/bidadjustment"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/currency"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/experiment/adscert"
+	"github.com/prebid/prebid-server/hooks"
+	"github.com/prebid/prebid-server/hooks/hookexecution"
 	"github.com/prebid/prebid-server/metrics"
 	metricsConfig "github.com/prebid/prebid-server/metrics/config"
 	"github.com/prebid/prebid-server/openrtb_ext"
@@ -106,7 +110,7 @@ func TestSingleBidder(t *testing.T) {
 			addCallSignHeader:   false,
 			bidAdjustments:      bidAdjustments,
 		}
-		seatBids, errs := bidder.requestBid(ctx, bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{})
+		seatBids, errs := bidder.requestBid(ctx, bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
 		assert.Len(t, seatBids, 1)
 		seatBid := seatBids[0]
 
@@ -228,7 +232,7 @@ func TestSingleBidderGzip(t *testing.T) {
 			addCallSignHeader:   false,
 			bidAdjustments:      bidAdjustments,
 		}
-		seatBids, errs := bidder.requestBid(ctx, bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{})
+		seatBids, errs := bidder.requestBid(ctx, bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
 		assert.Len(t, seatBids, 1)
 		seatBid := seatBids[0]
 
@@ -328,7 +332,7 @@ func TestRequestBidRemovesSensitiveHeaders(t *testing.T) {
 		addCallSignHeader:   false,
 		bidAdjustments:      bidAdjustments,
 	}
-	seatBids, errs := bidder.requestBid(ctx, bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{})
+	seatBids, errs := bidder.requestBid(ctx, bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
 
 	expectedHttpCalls := []*openrtb_ext.ExtHttpCall{
 		{
@@ -345,6 +349,57 @@ func TestRequestBidRemovesSensitiveHeaders(t *testing.T) {
 	assert.ElementsMatch(t, seatBids[0].httpCalls, expectedHttpCalls)
 }
 
+func TestRequestBidAppliesBidAdjustmentRules(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "responseJson"))
+	defer server.Close()
+
+	bidderImpl := &goodSingleBidder{
+		httpRequest: &adapters.RequestData{
+			Method: "POST",
+			Uri:    server.URL,
+			Body:   []byte("requestJson"),
+		},
+		bidResponse: &adapters.BidderResponse{
+			Bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "impId", Price: 2}, BidType: openrtb_ext.BidTypeVideo},
+				{Bid: &openrtb2.Bid{ID: "bid2", ImpID: "impId", Price: 2}, BidType: openrtb_ext.BidTypeBanner},
+			},
+		},
+	}
+
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+
+	bidderReq := BidderRequest{
+		BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId", Video: &openrtb2.Video{Placement: adcom1.VideoInStream}}}},
+		BidderName: "appnexus",
+	}
+	bidReqOptions := bidRequestOptions{
+		bidAdjustments: map[string]float64{"appnexus": 0.5},
+		bidAdjustmentRules: bidadjustment.Rules{
+			"video-instream|appnexus|*": {{Type: openrtb_ext.AdjustmentTypeMultiplier, Value: 0.9}},
+		},
+	}
+	seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
+
+	assert.Empty(t, errs)
+	if assert.Len(t, seatBids, 1) && assert.Len(t, seatBids[0].bids, 2) {
+		videoBid := seatBids[0].bids[0]
+		assert.InDelta(t, 1.8, videoBid.bid.Price, 0.0001, "only the rule should apply, not the bid adjustment factor")
+		assert.Equal(t, float64(2), videoBid.originalBidCPM)
+		if assert.NotNil(t, videoBid.bidAdjustment) {
+			assert.Equal(t, float64(2), videoBid.bidAdjustment.OriginalBidCPM)
+			assert.Equal(t, "USD", videoBid.bidAdjustment.OriginalBidCur)
+			assert.InDelta(t, 1.8, videoBid.bidAdjustment.AdjustedBidCPM, 0.0001)
+			assert.Equal(t, "USD", videoBid.bidAdjustment.AdjustedBidCur)
+		}
+
+		bannerBid := seatBids[0].bids[1]
+		assert.Equal(t, float64(1), bannerBid.bid.Price, "only the bid adjustment factor should apply")
+		assert.Nil(t, bannerBid.bidAdjustment)
+	}
+}
+
 func TestSetGPCHeader(t *testing.T) {
 	server := httptest.NewServer(mockHandler(200, "getBody", "responseJson"))
 	defer server.Close()
@@ -380,7 +435,7 @@ func TestSetGPCHeader(t *testing.T) {
 		addCallSignHeader:   false,
 		bidAdjustments:      bidAdjustments,
 	}
-	seatBids, errs := bidder.requestBid(ctx, bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{GlobalPrivacyControlHeader: "1"}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{})
+	seatBids, errs := bidder.requestBid(ctx, bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{GlobalPrivacyControlHeader: "1"}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
 
 	expectedHttpCall := []*openrtb_ext.ExtHttpCall{
 		{
@@ -430,7 +485,7 @@ func TestSetGPCHeaderNil(t *testing.T) {
 		addCallSignHeader:   false,
 		bidAdjustments:      bidAdjustments,
 	}
-	seatBids, errs := bidder.requestBid(ctx, bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{GlobalPrivacyControlHeader: "1"}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{})
+	seatBids, errs := bidder.requestBid(ctx, bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{GlobalPrivacyControlHeader: "1"}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
 
 	expectedHttpCall := []*openrtb_ext.ExtHttpCall{
 		{
@@ -500,7 +555,7 @@ func TestMultiBidder(t *testing.T) {
 		addCallSignHeader:   false,
 		bidAdjustments:      bidAdjustments,
 	}
-	seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{})
+	seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
 
 	if len(seatBids) != 1 {
 		t.Fatalf("SeatBid should exist, because bids exist.")
@@ -880,6 +935,7 @@ func TestMultiCurrencies(t *testing.T) {
 				bidAdjustments:      bidAdjustments,
 			},
 			openrtb_ext.ExtAlternateBidderCodes{},
+			&hookexecution.EmptyHookExecutor{},
 		)
 		assert.Len(t, seatBids, 1)
 		seatBid := seatBids[0]
@@ -1038,6 +1094,7 @@ func TestMultiCurrencies_RateConverterNotSet(t *testing.T) {
 				bidAdjustments:      bidAdjustments,
 			},
 			openrtb_ext.ExtAlternateBidderCodes{},
+			&hookexecution.EmptyHookExecutor{},
 		)
 		assert.Len(t, seatBids, 1)
 		seatBid := seatBids[0]
@@ -1215,6 +1272,7 @@ func TestMultiCurrencies_RequestCurrencyPick(t *testing.T) {
 				bidAdjustments:      bidAdjustments,
 			},
 			openrtb_ext.ExtAlternateBidderCodes{},
+			&hookexecution.EmptyHookExecutor{},
 		)
 		assert.Len(t, seatBids, 1)
 		seatBid := seatBids[0]
@@ -1531,6 +1589,7 @@ func TestMobileNativeTypes(t *testing.T) {
 				bidAdjustments:      bidAdjustments,
 			},
 			openrtb_ext.ExtAlternateBidderCodes{},
+			&hookexecution.EmptyHookExecutor{},
 		)
 		assert.Len(t, seatBids, 1)
 
@@ -1649,6 +1708,7 @@ func TestRequestBidsStoredBidResponses(t *testing.T) {
 				bidAdjustments:      bidAdjustments,
 			},
 			openrtb_ext.ExtAlternateBidderCodes{},
+			&hookexecution.EmptyHookExecutor{},
 		)
 		assert.Len(t, seatBids, 1)
 
@@ -1675,7 +1735,7 @@ func TestErrorReporting(t *testing.T) {
 		addCallSignHeader:   false,
 		bidAdjustments:      bidAdjustments,
 	}
-	bids, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{})
+	bids, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
 	if bids != nil {
 		t.Errorf("There should be no seatbid if no http requests are returned.")
 	}
@@ -1907,7 +1967,7 @@ func TestCallRecordAdapterConnections(t *testing.T) {
 		addCallSignHeader:   false,
 		bidAdjustments:      bidAdjustments,
 	}
-	_, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{})
+	_, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
 
 	// Assert no errors
 	assert.Equal(t, 0, len(errs), "bidder.requestBid returned errors %v \n", errs)
@@ -2150,7 +2210,7 @@ func TestRequestBidsWithAdsCertsSigner(t *testing.T) {
 		addCallSignHeader:   true,
 		bidAdjustments:      bidAdjustments,
 	}
-	_, errs := bidder.requestBid(ctx, bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &MockSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{})
+	_, errs := bidder.requestBid(ctx, bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &MockSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
 
 	assert.Empty(t, errs, "no errors should be returned")
 }
@@ -2373,7 +2433,7 @@ func TestExtraBid(t *testing.T) {
 					AllowedBidderCodes: []string{"groupm"},
 				},
 			},
-		})
+		}, &hookexecution.EmptyHookExecutor{})
 	assert.Nil(t, errs)
 	assert.Len(t, seatBids, 2)
 	sort.Slice(seatBids, func(i, j int) bool {
@@ -2485,7 +2545,7 @@ func TestExtraBidWithAlternateBidderCodeDisabled(t *testing.T) {
 					AllowedBidderCodes: []string{"groupm-allowed"},
 				},
 			},
-		})
+		}, &hookexecution.EmptyHookExecutor{})
 	assert.Equal(t, wantErrs, errs)
 	assert.Len(t, seatBids, 2)
 	assert.ElementsMatch(t, wantSeatBids, seatBids)
@@ -2594,7 +2654,7 @@ func TestExtraBidWithBidAdjustments(t *testing.T) {
 					AllowedBidderCodes: []string{"groupm"},
 				},
 			},
-		})
+		}, &hookexecution.EmptyHookExecutor{})
 	assert.Nil(t, errs)
 	assert.Len(t, seatBids, 2)
 	sort.Slice(seatBids, func(i, j int) bool {
@@ -2705,7 +2765,7 @@ func TestExtraBidWithBidAdjustmentsUsingAdapterCode(t *testing.T) {
 					AllowedBidderCodes: []string{"groupm"},
 				},
 			},
-		})
+		}, &hookexecution.EmptyHookExecutor{})
 	assert.Nil(t, errs)
 	assert.Len(t, seatBids, 2)
 	sort.Slice(seatBids, func(i, j int) bool {
@@ -2828,7 +2888,7 @@ func TestExtraBidWithMultiCurrencies(t *testing.T) {
 					AllowedBidderCodes: []string{"groupm"},
 				},
 			},
-		})
+		}, &hookexecution.EmptyHookExecutor{})
 	assert.Nil(t, errs)
 	assert.Len(t, seatBids, 2)
 	sort.Slice(seatBids, func(i, j int) bool {
@@ -2836,3 +2896,105 @@ func TestExtraBidWithMultiCurrencies(t *testing.T) {
 	})
 	assert.Equal(t, wantSeatBids, seatBids)
 }
+
+type mockBidderStagesHookExecutor struct {
+	hookexecution.EmptyHookExecutor
+	rejectBidderRequest     bool
+	rejectBidderResponse    bool
+	bidderResponseBidsLimit int
+}
+
+func (e *mockBidderStagesHookExecutor) ExecuteBidderRequestStage(_ *openrtb2.BidRequest, bidder string) *hookexecution.RejectError {
+	if e.rejectBidderRequest {
+		return &hookexecution.RejectError{Stage: hooks.StageBidderRequest.String()}
+	}
+	return nil
+}
+
+func (e *mockBidderStagesHookExecutor) ExecuteRawBidderResponseStage(response *adapters.BidderResponse, bidder string) *hookexecution.RejectError {
+	if e.rejectBidderResponse {
+		return &hookexecution.RejectError{Stage: hooks.StageRawBidderResponse.String()}
+	}
+	if e.bidderResponseBidsLimit > 0 && len(response.Bids) > e.bidderResponseBidsLimit {
+		response.Bids = response.Bids[:e.bidderResponseBidsLimit]
+	}
+	return nil
+}
+
+func TestRequestBidExecutesBidderStagesHooks(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "responseJson"))
+	defer server.Close()
+
+	testCases := []struct {
+		description         string
+		hookExecutor        *mockBidderStagesHookExecutor
+		expectedBidIDs      []string
+		expectedRejectStage string
+		expectedRequestMade bool
+	}{
+		{
+			description:         "bids not changed by hooks",
+			hookExecutor:        &mockBidderStagesHookExecutor{},
+			expectedBidIDs:      []string{"bid-1", "bid-2"},
+			expectedRequestMade: true,
+		},
+		{
+			description:         "bidder request rejected",
+			hookExecutor:        &mockBidderStagesHookExecutor{rejectBidderRequest: true},
+			expectedRejectStage: hooks.StageBidderRequest.String(),
+		},
+		{
+			description:         "bidder response rejected",
+			hookExecutor:        &mockBidderStagesHookExecutor{rejectBidderResponse: true},
+			expectedBidIDs:      []string{},
+			expectedRejectStage: hooks.StageRawBidderResponse.String(),
+			expectedRequestMade: true,
+		},
+		{
+			description:         "bidder response bids discarded",
+			hookExecutor:        &mockBidderStagesHookExecutor{bidderResponseBidsLimit: 1},
+			expectedBidIDs:      []string{"bid-1"},
+			expectedRequestMade: true,
+		},
+	}
+
+	for _, test := range testCases {
+		bidderImpl := &goodSingleBidder{
+			httpRequest: &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("requestJson"), Headers: http.Header{}},
+			bidResponse: &adapters.BidderResponse{
+				Bids: []*adapters.TypedBid{
+					{Bid: &openrtb2.Bid{ID: "bid-1", Price: 1}, BidType: openrtb_ext.BidTypeBanner},
+					{Bid: &openrtb2.Bid{ID: "bid-2", Price: 2}, BidType: openrtb_ext.BidTypeBanner},
+				},
+			},
+		}
+		bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+		bidderReq := BidderRequest{
+			BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
+			BidderName: "appnexus",
+		}
+
+		seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidRequestOptions{}, openrtb_ext.ExtAlternateBidderCodes{}, test.hookExecutor)
+
+		assert.Equal(t, test.expectedRequestMade, bidderImpl.bidRequest != nil, test.description)
+		if test.expectedRejectStage != "" {
+			assert.Len(t, errs, 1, test.description)
+			rejectErr, ok := hookexecution.CastRejectErr(errs[0])
+			assert.True(t, ok, test.description)
+			assert.Equal(t, test.expectedRejectStage, rejectErr.Stage, test.description)
+		} else {
+			assert.Empty(t, errs, test.description)
+		}
+
+		if test.expectedBidIDs == nil {
+			assert.Empty(t, seatBids, test.description)
+			continue
+		}
+		assert.Len(t, seatBids, 1, test.description)
+		bidIDs := make([]string, 0, len(seatBids[0].bids))
+		for _, pbsBid := range seatBids[0].bids {
+			bidIDs = append(bidIDs, pbsBid.bid.ID)
+		}
+		assert.Equal(t, test.expectedBidIDs, bidIDs, test.description)
+	}
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/prebid/openrtb/v17/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/experiment/adscert"
+	"github.com/prebid/prebid-server/hooks/hookexecution"
+	"github.com/prebid/prebid-server/metrics"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	goCurrency "golang.org/x/text/currency"
 )
@@ -19,26 +24,136 @@ import (
 //
 // The goal here is to make sure that the response contains Bids which are valid given the initial Request,
 // so that Publishers can trust the Bids they get from Prebid Server.
-func addValidatedBidderMiddleware(bidder AdaptedBidder) AdaptedBidder {
+func addValidatedBidderMiddleware(bidder AdaptedBidder, me metrics.MetricsEngine) AdaptedBidder {
 	return &validatedBidder{
 		bidder: bidder,
+		me:     me,
 	}
 }
 
 type validatedBidder struct {
 	bidder AdaptedBidder
+	me     metrics.MetricsEngine
 }
 
-func (v *validatedBidder) requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestOptions bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes) ([]*pbsOrtbSeatBid, []error) {
-	seatBids, errs := v.bidder.requestBid(ctx, bidderRequest, conversions, reqInfo, adsCertSigner, bidRequestOptions, alternateBidderCodes)
+func (v *validatedBidder) requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestOptions bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes, hookExecutor hookexecution.StageExecutor) ([]*pbsOrtbSeatBid, []error) {
+	seatBids, errs := v.bidder.requestBid(ctx, bidderRequest, conversions, reqInfo, adsCertSigner, bidRequestOptions, alternateBidderCodes, hookExecutor)
 	for _, seatBid := range seatBids {
 		if validationErrors := removeInvalidBids(bidderRequest.BidRequest, seatBid); len(validationErrors) > 0 {
 			errs = append(errs, validationErrors...)
 		}
+		if validationWarnings := v.validateCreatives(bidderRequest, seatBid, bidRequestOptions.bidValidations); len(validationWarnings) > 0 {
+			errs = append(errs, validationWarnings...)
+		}
 	}
 	return seatBids, errs
 }
 
+// validateCreatives runs the banner creative size and secure markup validations enabled on the account. Bids
+// failing an enforced validation are removed from the seat bid. Every failure is reported as a warning.
+func (v *validatedBidder) validateCreatives(bidderRequest BidderRequest, seatBid *pbsOrtbSeatBid, validations config.AccountBidValidations) []error {
+	checkSize := validations.BannerCreativeSize == config.ValidationWarn || validations.BannerCreativeSize == config.ValidationEnforce
+	checkSecure := validations.SecureMarkup == config.ValidationWarn || validations.SecureMarkup == config.ValidationEnforce
+	if seatBid == nil || (!checkSize && !checkSecure) {
+		return nil
+	}
+
+	var warnings []error
+	validBids := make([]*pbsOrtbBid, 0, len(seatBid.bids))
+	for _, bid := range seatBid.bids {
+		imp := findImp(bidderRequest.BidRequest.Imp, bid.bid.ImpID)
+		if imp == nil {
+			validBids = append(validBids, bid)
+			continue
+		}
+
+		if checkSize && bid.bidType == openrtb_ext.BidTypeBanner {
+			if err := validateBannerCreativeSize(bid.bid, imp, validations); err != nil {
+				if validations.BannerCreativeSize == config.ValidationEnforce {
+					v.me.RecordBidValidationCreativeSizeError(bidderRequest.BidderCoreName)
+					warnings = append(warnings, newBidValidationWarning("bid rejected: %v", err))
+					seatBid.nonBids = append(seatBid.nonBids, newNonBid(bid, openrtb_ext.ResponseRejectedCreativeSizeNotAllowed))
+					continue
+				}
+				v.me.RecordBidValidationCreativeSizeWarn(bidderRequest.BidderCoreName)
+				warnings = append(warnings, newBidValidationWarning("%v", err))
+			}
+		}
+
+		if checkSecure && isSecureImp(imp) && hasInsecureMarkup(bid.bid.AdM) {
+			if validations.SecureMarkup == config.ValidationEnforce {
+				v.me.RecordBidValidationSecureMarkupError(bidderRequest.BidderCoreName)
+				warnings = append(warnings, newBidValidationWarning("bid rejected: Bid \"%s\" has insecure creative markup on secure imp \"%s\"", bid.bid.ID, imp.ID))
+				seatBid.nonBids = append(seatBid.nonBids, newNonBid(bid, openrtb_ext.ResponseRejectedCreativeNotSecure))
+				continue
+			}
+			v.me.RecordBidValidationSecureMarkupWarn(bidderRequest.BidderCoreName)
+			warnings = append(warnings, newBidValidationWarning("Bid \"%s\" has insecure creative markup on secure imp \"%s\"", bid.bid.ID, imp.ID))
+		}
+
+		validBids = append(validBids, bid)
+	}
+	seatBid.bids = validBids
+	return warnings
+}
+
+// validateBannerCreativeSize checks the banner bid size against the imp banner sizes, or against the max creative
+// size when one is configured
+func validateBannerCreativeSize(bid *openrtb2.Bid, imp *openrtb2.Imp, validations config.AccountBidValidations) error {
+	if validations.MaxCreativeWidth > 0 || validations.MaxCreativeHeight > 0 {
+		if (validations.MaxCreativeWidth > 0 && bid.W > validations.MaxCreativeWidth) || (validations.MaxCreativeHeight > 0 && bid.H > validations.MaxCreativeHeight) {
+			return fmt.Errorf("Bid \"%s\" banner creative size %dx%d exceeds the max creative size %dx%d", bid.ID, bid.W, bid.H, validations.MaxCreativeWidth, validations.MaxCreativeHeight)
+		}
+		return nil
+	}
+
+	if imp.Banner == nil {
+		return nil
+	}
+	if imp.Banner.W != nil && imp.Banner.H != nil && bid.W == *imp.Banner.W && bid.H == *imp.Banner.H {
+		return nil
+	}
+	for _, format := range imp.Banner.Format {
+		if bid.W == format.W && bid.H == format.H {
+			return nil
+		}
+	}
+	return fmt.Errorf("Bid \"%s\" banner creative size %dx%d does not match any size of imp \"%s\"", bid.ID, bid.W, bid.H, imp.ID)
+}
+
+func isSecureImp(imp *openrtb2.Imp) bool {
+	return imp.Secure != nil && *imp.Secure == 1
+}
+
+// insecureResourcePatterns match the plain http urls of the resources a creative loads: src and href attributes, css
+// urls and the media files, trackers and resources of VAST. Other http urls, like xml namespaces or urls passed as
+// query parameters, don't load anything and are ignored.
+var insecureResourcePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\b(?:src|href)\s*=\s*["']?\s*http://`),
+	regexp.MustCompile(`url\(\s*["']?\s*http://`),
+	regexp.MustCompile(`<(?:mediafile|impression|tracking|clicktracking|customclick|companionclicktracking|nonlinearclicktracking|error|staticresource|iframeresource|javascriptresource|vastadtaguri|interactivecreativefile)\b[^>]*>\s*(?:<!\[cdata\[)?\s*http://`),
+}
+
+// hasInsecureMarkup returns true when the markup loads plain http resources. The markup of native bids and of
+// creatives written by scripts may be JSON escaped, so escaped quotes and slashes are taken into account.
+func hasInsecureMarkup(adm string) bool {
+	adm = strings.ToLower(adm)
+	adm = strings.NewReplacer(`\/`, `/`, `\"`, `"`).Replace(adm)
+	for _, pattern := range insecureResourcePatterns {
+		if pattern.MatchString(adm) {
+			return true
+		}
+	}
+	return false
+}
+
+func newBidValidationWarning(format string, args ...interface{}) error {
+	return &errortypes.Warning{
+		Message:     fmt.Sprintf(format, args...),
+		WarningCode: errortypes.BidValidationWarningCode,
+	}
+}
+
 // validateBids will run some validation checks on the returned bids and excise any invalid bids
 func removeInvalidBids(request *openrtb2.BidRequest, seatBid *pbsOrtbSeatBid) []error {
 	// Exit early if there is nothing to do.
@@ -48,6 +163,11 @@ func removeInvalidBids(request *openrtb2.BidRequest, seatBid *pbsOrtbSeatBid) []
 
 	// By design, default currency is USD.
 	if cerr := validateCurrency(request.Cur, seatBid.currency); cerr != nil {
+		for _, bid := range seatBid.bids {
+			if bid != nil && bid.bid != nil {
+				seatBid.nonBids = append(seatBid.nonBids, newNonBid(bid, openrtb_ext.ErrorInvalidBidResponse))
+			}
+		}
 		seatBid.bids = nil
 		return []error{cerr}
 	}
@@ -59,6 +179,9 @@ func removeInvalidBids(request *openrtb2.BidRequest, seatBid *pbsOrtbSeatBid) []
 			validBids = append(validBids, bid)
 		} else {
 			errs = append(errs, berr)
+			if bid.bid != nil && bid.bid.ImpID != "" {
+				seatBid.nonBids = append(seatBid.nonBids, newNonBid(bid, openrtb_ext.ErrorInvalidBidResponse))
+			}
 		}
 	}
 	seatBid.bids = validBids
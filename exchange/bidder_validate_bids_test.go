@@ -6,8 +6,13 @@ import (
 
 	"github.com/prebid/openrtb/v17/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/experiment/adscert"
+	"github.com/prebid/prebid-server/hooks/hookexecution"
+	"github.com/prebid/prebid-server/metrics"
+	metricsConf "github.com/prebid/prebid-server/metrics/config"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/stretchr/testify/assert"
 )
@@ -51,7 +56,7 @@ func TestAllValidBids(t *testing.T) {
 				},
 			},
 		},
-		}})
+		}}, &metricsConf.NilMetricsEngine{})
 	bidderReq := BidderRequest{
 		BidRequest: &openrtb2.BidRequest{},
 		BidderName: openrtb_ext.BidderAppnexus,
@@ -63,7 +68,7 @@ func TestAllValidBids(t *testing.T) {
 		addCallSignHeader:   false,
 		bidAdjustments:      bidAdjustments,
 	}
-	seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{})
+	seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
 	assert.Len(t, seatBids, 1)
 	assert.Len(t, seatBids[0].bids, 4)
 	assert.Len(t, errs, 0)
@@ -121,7 +126,7 @@ func TestAllBadBids(t *testing.T) {
 				{},
 			},
 		},
-		}})
+		}}, &metricsConf.NilMetricsEngine{})
 	bidderReq := BidderRequest{
 		BidRequest: &openrtb2.BidRequest{},
 		BidderName: openrtb_ext.BidderAppnexus,
@@ -133,7 +138,7 @@ func TestAllBadBids(t *testing.T) {
 		addCallSignHeader:   false,
 		bidAdjustments:      bidAdjustments,
 	}
-	seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{})
+	seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
 	assert.Len(t, seatBids, 1)
 	assert.Len(t, seatBids[0].bids, 0)
 	assert.Len(t, errs, 7)
@@ -202,7 +207,7 @@ func TestMixedBids(t *testing.T) {
 				{},
 			},
 		},
-		}})
+		}}, &metricsConf.NilMetricsEngine{})
 	bidderReq := BidderRequest{
 		BidRequest: &openrtb2.BidRequest{},
 		BidderName: openrtb_ext.BidderAppnexus,
@@ -214,9 +219,13 @@ func TestMixedBids(t *testing.T) {
 		addCallSignHeader:   false,
 		bidAdjustments:      bidAdjustments,
 	}
-	seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{})
+	seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
 	assert.Len(t, seatBids, 1)
 	assert.Len(t, seatBids[0].bids, 3)
+	assert.Len(t, seatBids[0].nonBids, 4)
+	for _, nonBid := range seatBids[0].nonBids {
+		assert.Equal(t, openrtb_ext.ErrorInvalidBidResponse, nonBid.StatusCode)
+	}
 	assert.Len(t, errs, 5)
 }
 
@@ -320,7 +329,7 @@ func TestCurrencyBids(t *testing.T) {
 				currency: tc.brpCur,
 				bids:     bids,
 			},
-			}})
+			}}, &metricsConf.NilMetricsEngine{})
 
 		expectedValidBids := len(bids)
 		expectedErrs := 0
@@ -343,7 +352,7 @@ func TestCurrencyBids(t *testing.T) {
 			addCallSignHeader:   false,
 			bidAdjustments:      bidAdjustments,
 		}
-		seatBids, errs := bidder.requestBid(context.Background(), bidderRequest, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{})
+		seatBids, errs := bidder.requestBid(context.Background(), bidderRequest, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
 		assert.Len(t, seatBids, 1)
 		assert.Len(t, seatBids[0].bids, expectedValidBids)
 		assert.Len(t, errs, expectedErrs)
@@ -355,6 +364,228 @@ type mockAdaptedBidder struct {
 	errorResponse []error
 }
 
-func (b *mockAdaptedBidder) requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestMetadata bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes) ([]*pbsOrtbSeatBid, []error) {
+func (b *mockAdaptedBidder) requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestMetadata bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes, hookExecutor hookexecution.StageExecutor) ([]*pbsOrtbSeatBid, []error) {
 	return b.bidResponse, b.errorResponse
 }
+
+func TestCreativeValidations(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		Imp: []openrtb2.Imp{
+			{
+				ID: "bannerImp",
+				Banner: &openrtb2.Banner{
+					W:      openrtb2.Int64Ptr(728),
+					H:      openrtb2.Int64Ptr(90),
+					Format: []openrtb2.Format{{W: 300, H: 250}},
+				},
+			},
+			{
+				ID:     "secureImp",
+				Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}},
+				Secure: openrtb2.Int8Ptr(1),
+			},
+		},
+	}
+
+	testCases := []struct {
+		description     string
+		validations     config.AccountBidValidations
+		bid             openrtb2.Bid
+		expectedBids    int
+		expectedNonBids []openrtb_ext.NonBidStatusCode
+		expectedErrs    []error
+		expectedMetric  string
+	}{
+		{
+			description:  "Skip",
+			validations:  config.AccountBidValidations{BannerCreativeSize: "skip", SecureMarkup: "skip"},
+			bid:          openrtb2.Bid{ID: "bid", ImpID: "secureImp", W: 1, H: 1, AdM: "<img src='http://insecure.com'>"},
+			expectedBids: 1,
+		},
+		{
+			description:  "Size matches the banner size",
+			validations:  config.AccountBidValidations{BannerCreativeSize: "enforce"},
+			bid:          openrtb2.Bid{ID: "bid", ImpID: "bannerImp", W: 728, H: 90},
+			expectedBids: 1,
+		},
+		{
+			description:  "Size matches a banner format",
+			validations:  config.AccountBidValidations{BannerCreativeSize: "enforce"},
+			bid:          openrtb2.Bid{ID: "bid", ImpID: "bannerImp", W: 300, H: 250},
+			expectedBids: 1,
+		},
+		{
+			description:     "Size enforced",
+			validations:     config.AccountBidValidations{BannerCreativeSize: "enforce"},
+			bid:             openrtb2.Bid{ID: "bid", ImpID: "bannerImp", W: 300, H: 600},
+			expectedBids:    0,
+			expectedNonBids: []openrtb_ext.NonBidStatusCode{openrtb_ext.ResponseRejectedCreativeSizeNotAllowed},
+			expectedErrs:    []error{&errortypes.Warning{Message: `bid rejected: Bid "bid" banner creative size 300x600 does not match any size of imp "bannerImp"`, WarningCode: errortypes.BidValidationWarningCode}},
+			expectedMetric:  "RecordBidValidationCreativeSizeError",
+		},
+		{
+			description:    "Size warned",
+			validations:    config.AccountBidValidations{BannerCreativeSize: "warn"},
+			bid:            openrtb2.Bid{ID: "bid", ImpID: "bannerImp", W: 300, H: 600},
+			expectedBids:   1,
+			expectedErrs:   []error{&errortypes.Warning{Message: `Bid "bid" banner creative size 300x600 does not match any size of imp "bannerImp"`, WarningCode: errortypes.BidValidationWarningCode}},
+			expectedMetric: "RecordBidValidationCreativeSizeWarn",
+		},
+		{
+			description:  "Size within the max creative size",
+			validations:  config.AccountBidValidations{BannerCreativeSize: "enforce", MaxCreativeWidth: 300, MaxCreativeHeight: 600},
+			bid:          openrtb2.Bid{ID: "bid", ImpID: "bannerImp", W: 300, H: 600},
+			expectedBids: 1,
+		},
+		{
+			description:     "Size exceeds the max creative size",
+			validations:     config.AccountBidValidations{BannerCreativeSize: "enforce", MaxCreativeWidth: 300},
+			bid:             openrtb2.Bid{ID: "bid", ImpID: "bannerImp", W: 728, H: 90},
+			expectedBids:    0,
+			expectedNonBids: []openrtb_ext.NonBidStatusCode{openrtb_ext.ResponseRejectedCreativeSizeNotAllowed},
+			expectedErrs:    []error{&errortypes.Warning{Message: `bid rejected: Bid "bid" banner creative size 728x90 exceeds the max creative size 300x0`, WarningCode: errortypes.BidValidationWarningCode}},
+			expectedMetric:  "RecordBidValidationCreativeSizeError",
+		},
+		{
+			description:  "Secure markup on secure imp",
+			validations:  config.AccountBidValidations{SecureMarkup: "enforce"},
+			bid:          openrtb2.Bid{ID: "bid", ImpID: "secureImp", AdM: "<img src='https://secure.com'>"},
+			expectedBids: 1,
+		},
+		{
+			description:  "Insecure markup on insecure imp",
+			validations:  config.AccountBidValidations{SecureMarkup: "enforce"},
+			bid:          openrtb2.Bid{ID: "bid", ImpID: "bannerImp", AdM: "<img src='http://insecure.com'>"},
+			expectedBids: 1,
+		},
+		{
+			description:     "Insecure markup enforced",
+			validations:     config.AccountBidValidations{SecureMarkup: "enforce"},
+			bid:             openrtb2.Bid{ID: "bid", ImpID: "secureImp", AdM: "<img src='HTTP://insecure.com'>"},
+			expectedBids:    0,
+			expectedNonBids: []openrtb_ext.NonBidStatusCode{openrtb_ext.ResponseRejectedCreativeNotSecure},
+			expectedErrs:    []error{&errortypes.Warning{Message: `bid rejected: Bid "bid" has insecure creative markup on secure imp "secureImp"`, WarningCode: errortypes.BidValidationWarningCode}},
+			expectedMetric:  "RecordBidValidationSecureMarkupError",
+		},
+		{
+			description:    "Insecure VAST markup warned",
+			validations:    config.AccountBidValidations{SecureMarkup: "warn"},
+			bid:            openrtb2.Bid{ID: "bid", ImpID: "secureImp", AdM: "<VAST version=\"3.0\"><Ad><InLine><Creatives><Creative><Linear><MediaFiles><MediaFile type=\"video/mp4\"><![CDATA[http://insecure.com/video.mp4]]></MediaFile></MediaFiles></Linear></Creative></Creatives></InLine></Ad></VAST>"},
+			expectedBids:   1,
+			expectedErrs:   []error{&errortypes.Warning{Message: `Bid "bid" has insecure creative markup on secure imp "secureImp"`, WarningCode: errortypes.BidValidationWarningCode}},
+			expectedMetric: "RecordBidValidationSecureMarkupWarn",
+		},
+		{
+			description:  "Secure VAST markup with an xml namespace",
+			validations:  config.AccountBidValidations{SecureMarkup: "enforce"},
+			bid:          openrtb2.Bid{ID: "bid", ImpID: "secureImp", AdM: "<VAST version=\"3.0\" xmlns:xsi=\"http://www.w3.org/2001/XMLSchema-instance\"><Ad><InLine><Impression><![CDATA[https://secure.com/imp]]></Impression></InLine></Ad></VAST>"},
+			expectedBids: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		me := &metrics.MetricsEngineMock{}
+		if test.expectedMetric != "" {
+			me.On(test.expectedMetric, openrtb_ext.BidderAppnexus).Return()
+		}
+
+		bid := test.bid
+		bid.Price = 1
+		bid.CrID = "creative"
+		bidder := addValidatedBidderMiddleware(&mockAdaptedBidder{
+			bidResponse: []*pbsOrtbSeatBid{{
+				bids: []*pbsOrtbBid{{bid: &bid, bidType: openrtb_ext.BidTypeBanner}},
+			}},
+		}, me)
+		bidderRequest := BidderRequest{
+			BidRequest:     request,
+			BidderName:     openrtb_ext.BidderAppnexus,
+			BidderCoreName: openrtb_ext.BidderAppnexus,
+		}
+		bidReqOptions := bidRequestOptions{bidValidations: test.validations}
+
+		seatBids, errs := bidder.requestBid(context.Background(), bidderRequest, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
+
+		assert.Len(t, seatBids[0].bids, test.expectedBids, test.description)
+		assert.Equal(t, test.expectedErrs, errs, test.description)
+		var nonBids []openrtb_ext.NonBidStatusCode
+		for _, nonBid := range seatBids[0].nonBids {
+			nonBids = append(nonBids, nonBid.StatusCode)
+		}
+		assert.Equal(t, test.expectedNonBids, nonBids, test.description)
+		me.AssertExpectations(t)
+	}
+}
+
+func TestHasInsecureMarkup(t *testing.T) {
+	testCases := []struct {
+		description string
+		adm         string
+		expected    bool
+	}{
+		{
+			description: "Secure image",
+			adm:         `<img src="https://secure.com/img.png">`,
+			expected:    false,
+		},
+		{
+			description: "Insecure image",
+			adm:         `<img src="http://insecure.com/img.png">`,
+			expected:    true,
+		},
+		{
+			description: "Insecure unquoted script",
+			adm:         `<script SRC = http://insecure.com/ad.js></script>`,
+			expected:    true,
+		},
+		{
+			description: "Insecure link",
+			adm:         `<link rel="stylesheet" href='http://insecure.com/ad.css'>`,
+			expected:    true,
+		},
+		{
+			description: "Insecure css url",
+			adm:         `<div style="background: url('http://insecure.com/bg.png')"></div>`,
+			expected:    true,
+		},
+		{
+			description: "JSON escaped insecure image",
+			adm:         `document.write("<img src=\"http:\/\/insecure.com\/img.png\">");`,
+			expected:    true,
+		},
+		{
+			description: "Insecure url passed as a query parameter",
+			adm:         `<img src="https://secure.com/?r=http%3A%2F%2Finsecure.com&u=http://insecure.com">`,
+			expected:    false,
+		},
+		{
+			description: "Insecure url in a script string",
+			adm:         `<script>var landing = "http://insecure.com";</script>`,
+			expected:    false,
+		},
+		{
+			description: "XML namespace",
+			adm:         `<VAST xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"></VAST>`,
+			expected:    false,
+		},
+		{
+			description: "Insecure VAST tracking event",
+			adm:         `<VAST><TrackingEvents><Tracking event="start"> <![CDATA[ http://insecure.com/start ]]></Tracking></TrackingEvents></VAST>`,
+			expected:    true,
+		},
+		{
+			description: "Insecure VAST wrapper",
+			adm:         `<VAST><Ad><Wrapper><VASTAdTagURI>http://insecure.com/vast.xml</VASTAdTagURI></Wrapper></Ad></VAST>`,
+			expected:    true,
+		},
+		{
+			description: "Insecure VAST click through",
+			adm:         `<VAST><VideoClicks><ClickThrough>http://insecure.com/landing</ClickThrough></VideoClicks></VAST>`,
+			expected:    false,
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, hasInsecureMarkup(test.adm), test.description)
+	}
+}
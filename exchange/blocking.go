@@ -0,0 +1,186 @@
+package exchange
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prebid/openrtb/v17/adcom1"
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// mergeBlockingLists adds the account blocking lists to the request badv, bcat and bapp and to the battr of every
+// imp media type, so the bidders are told about them. It returns true when the imps were updated.
+func mergeBlockingLists(req *openrtb_ext.RequestWrapper, blocking config.AccountBlocking) bool {
+	req.BAdv = mergeBlockedValues(req.BAdv, blocking.BAdv)
+	req.BCat = mergeBlockedValues(req.BCat, blocking.BCat)
+	req.BApp = mergeBlockedValues(req.BApp, blocking.BApp)
+
+	if len(blocking.BAttr) == 0 {
+		return false
+	}
+
+	// the media objects are copied since they may be shared with the incoming request
+	for _, imp := range req.GetImp() {
+		if imp.Banner != nil {
+			banner := *imp.Banner
+			banner.BAttr = mergeBlockedValues(banner.BAttr, blocking.BAttr)
+			imp.Banner = &banner
+		}
+		if imp.Video != nil {
+			video := *imp.Video
+			video.BAttr = mergeBlockedValues(video.BAttr, blocking.BAttr)
+			imp.Video = &video
+		}
+		if imp.Audio != nil {
+			audio := *imp.Audio
+			audio.BAttr = mergeBlockedValues(audio.BAttr, blocking.BAttr)
+			imp.Audio = &audio
+		}
+		if imp.Native != nil {
+			native := *imp.Native
+			native.BAttr = mergeBlockedValues(native.BAttr, blocking.BAttr)
+			imp.Native = &native
+		}
+	}
+	return true
+}
+
+// mergeBlockedValues appends the account values missing from the request values
+func mergeBlockedValues[T comparable](requestValues, accountValues []T) []T {
+	if len(accountValues) == 0 {
+		return requestValues
+	}
+
+	merged := make([]T, 0, len(requestValues)+len(accountValues))
+	seen := make(map[T]struct{}, len(requestValues)+len(accountValues))
+	for _, values := range [][]T{requestValues, accountValues} {
+		for _, value := range values {
+			if _, ok := seen[value]; !ok {
+				seen[value] = struct{}{}
+				merged = append(merged, value)
+			}
+		}
+	}
+	return merged
+}
+
+// enforceBlocking checks the bids against the badv, bcat and bapp of the request and the battr of the imp media
+// type they were made for. Violating bids are removed when the blocking mode of their seat is enforce and are
+// only reported when it is warn. It returns the remaining bids along with a message for every violation. Removed
+// bids are also recorded in seatNonBids.
+func enforceBlocking(bidRequest *openrtb2.BidRequest, blocking config.AccountBlocking, seatBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, seatNonBids *nonBids) (map[openrtb_ext.BidderName]*pbsOrtbSeatBid, []string) {
+	var messages []string
+	for bidderName, seatBid := range seatBids {
+		if seatBid == nil {
+			continue
+		}
+		mode := blocking.ModeForBidder(bidderName.String())
+		if mode != config.ValidationWarn && mode != config.ValidationEnforce {
+			continue
+		}
+
+		validBids := make([]*pbsOrtbBid, 0, len(seatBid.bids))
+		for _, pbsBid := range seatBid.bids {
+			reason, statusCode := findBlockingViolation(bidRequest, pbsBid)
+			if reason == "" {
+				validBids = append(validBids, pbsBid)
+				continue
+			}
+
+			reason = fmt.Sprintf("%s for impression id %s bidder %s", reason, pbsBid.bid.ImpID, bidderName)
+			if mode == config.ValidationWarn {
+				messages = append(messages, fmt.Sprintf("bid not rejected in warn mode [bid ID: %s] reason: %s", pbsBid.bid.ID, reason))
+				validBids = append(validBids, pbsBid)
+				continue
+			}
+			messages = updateRejections(messages, pbsBid.bid.ID, reason)
+			seatNonBids.addBid(pbsBid, statusCode, bidderName.String())
+		}
+		seatBid.bids = validBids
+	}
+	return seatBids, messages
+}
+
+// findBlockingViolation returns the reason and non bid status code of the first blocking list the bid violates,
+// or an empty reason when it violates none
+func findBlockingViolation(bidRequest *openrtb2.BidRequest, pbsBid *pbsOrtbBid) (string, openrtb_ext.NonBidStatusCode) {
+	bid := pbsBid.bid
+	for _, domain := range bid.ADomain {
+		for _, blockedDomain := range bidRequest.BAdv {
+			if isBlockedDomain(domain, blockedDomain) {
+				return fmt.Sprintf("advertiser domain %s is blocked by badv %s", domain, blockedDomain), openrtb_ext.ResponseRejectedAdvertiserExclusions
+			}
+		}
+	}
+	for _, category := range bid.Cat {
+		for _, blockedCategory := range bidRequest.BCat {
+			if isBlockedCategory(category, blockedCategory) {
+				return fmt.Sprintf("category %s is blocked by bcat %s", category, blockedCategory), openrtb_ext.ResponseRejectedAdvertiserExclusions
+			}
+		}
+	}
+	if bid.Bundle != "" {
+		for _, blockedApp := range bidRequest.BApp {
+			if strings.EqualFold(bid.Bundle, blockedApp) {
+				return fmt.Sprintf("app bundle %s is blocked by bapp", bid.Bundle), openrtb_ext.ResponseRejectedAdvertiserExclusions
+			}
+		}
+	}
+	if len(bid.Attr) > 0 {
+		blockedAttributes := getBlockedAttributes(findImp(bidRequest.Imp, bid.ImpID), pbsBid.bidType)
+		for _, attribute := range bid.Attr {
+			for _, blockedAttribute := range blockedAttributes {
+				if attribute == blockedAttribute {
+					return fmt.Sprintf("creative attribute %d is blocked by battr", attribute), openrtb_ext.ResponseRejectedAdvertiserBlockedByAttr
+				}
+			}
+		}
+	}
+	return "", 0
+}
+
+// isBlockedDomain returns true when the domain is the blocked domain or one of its subdomains
+func isBlockedDomain(domain, blockedDomain string) bool {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	blockedDomain = strings.ToLower(strings.TrimSpace(blockedDomain))
+	if domain == "" || blockedDomain == "" {
+		return false
+	}
+	return domain == blockedDomain || strings.HasSuffix(domain, "."+blockedDomain)
+}
+
+// isBlockedCategory returns true when the category is the blocked category or one of its subcategories, such as
+// IAB1-2 for IAB1
+func isBlockedCategory(category, blockedCategory string) bool {
+	if category == "" || blockedCategory == "" {
+		return false
+	}
+	return strings.EqualFold(category, blockedCategory) || strings.HasPrefix(strings.ToUpper(category), strings.ToUpper(blockedCategory)+"-")
+}
+
+func getBlockedAttributes(imp *openrtb2.Imp, bidType openrtb_ext.BidType) []adcom1.CreativeAttribute {
+	if imp == nil {
+		return nil
+	}
+	switch bidType {
+	case openrtb_ext.BidTypeBanner:
+		if imp.Banner != nil {
+			return imp.Banner.BAttr
+		}
+	case openrtb_ext.BidTypeVideo:
+		if imp.Video != nil {
+			return imp.Video.BAttr
+		}
+	case openrtb_ext.BidTypeAudio:
+		if imp.Audio != nil {
+			return imp.Audio.BAttr
+		}
+	case openrtb_ext.BidTypeNative:
+		if imp.Native != nil {
+			return imp.Native.BAttr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v17/adcom1"
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeBlockingLists(t *testing.T) {
+	banner := &openrtb2.Banner{BAttr: []adcom1.CreativeAttribute{1}}
+	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		BAdv: []string{"a.com"},
+		BCat: []string{"IAB1"},
+		Imp: []openrtb2.Imp{
+			{ID: "banner", Banner: banner},
+			{ID: "video", Video: &openrtb2.Video{}},
+		},
+	}}
+	blocking := config.AccountBlocking{
+		BAdv:  []string{"b.com", "a.com"},
+		BApp:  []string{"com.app"},
+		BAttr: []adcom1.CreativeAttribute{1, 3},
+	}
+
+	updated := mergeBlockingLists(req, blocking)
+	assert.NoError(t, req.RebuildRequest())
+
+	assert.True(t, updated)
+	assert.Equal(t, []string{"a.com", "b.com"}, req.BAdv)
+	assert.Equal(t, []string{"IAB1"}, req.BCat)
+	assert.Equal(t, []string{"com.app"}, req.BApp)
+	assert.Equal(t, []adcom1.CreativeAttribute{1, 3}, req.Imp[0].Banner.BAttr)
+	assert.Equal(t, []adcom1.CreativeAttribute{1, 3}, req.Imp[1].Video.BAttr)
+	assert.Equal(t, []adcom1.CreativeAttribute{1}, banner.BAttr, "incoming banner must not be modified")
+}
+
+func TestMergeBlockingListsEmpty(t *testing.T) {
+	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{BCat: []string{"IAB1"}}}
+
+	updated := mergeBlockingLists(req, config.AccountBlocking{})
+
+	assert.False(t, updated)
+	assert.Equal(t, []string{"IAB1"}, req.BCat)
+	assert.Nil(t, req.BAdv)
+}
+
+func TestEnforceBlocking(t *testing.T) {
+	bidRequest := &openrtb2.BidRequest{
+		BAdv: []string{"blocked.com"},
+		BCat: []string{"IAB7"},
+		BApp: []string{"com.blocked.app"},
+		Imp: []openrtb2.Imp{{
+			ID:     "imp",
+			Banner: &openrtb2.Banner{BAttr: []adcom1.CreativeAttribute{adcom1.AttrAudioAuto}},
+			Video:  &openrtb2.Video{},
+		}},
+	}
+
+	testCases := []struct {
+		description      string
+		blocking         config.AccountBlocking
+		bid              openrtb2.Bid
+		bidType          openrtb_ext.BidType
+		expectedBids     int
+		expectedMessages []string
+		expectedNonBid   openrtb_ext.NonBidStatusCode
+	}{
+		{
+			description:  "Skip",
+			blocking:     config.AccountBlocking{Mode: "skip"},
+			bid:          openrtb2.Bid{ID: "bid", ImpID: "imp", ADomain: []string{"blocked.com"}},
+			expectedBids: 1,
+		},
+		{
+			description:  "No violation",
+			blocking:     config.AccountBlocking{Mode: "enforce"},
+			bid:          openrtb2.Bid{ID: "bid", ImpID: "imp", ADomain: []string{"notblocked.com"}, Cat: []string{"IAB70"}, Bundle: "com.app"},
+			expectedBids: 1,
+		},
+		{
+			description:      "Blocked subdomain",
+			blocking:         config.AccountBlocking{Mode: "enforce"},
+			bid:              openrtb2.Bid{ID: "bid", ImpID: "imp", ADomain: []string{"ads.Blocked.com"}},
+			expectedMessages: []string{"bid rejected [bid ID: bid] reason: advertiser domain ads.Blocked.com is blocked by badv blocked.com for impression id imp bidder appnexus"},
+			expectedNonBid:   openrtb_ext.ResponseRejectedAdvertiserExclusions,
+		},
+		{
+			description:      "Blocked subcategory",
+			blocking:         config.AccountBlocking{Mode: "enforce"},
+			bid:              openrtb2.Bid{ID: "bid", ImpID: "imp", Cat: []string{"IAB7-3"}},
+			expectedMessages: []string{"bid rejected [bid ID: bid] reason: category IAB7-3 is blocked by bcat IAB7 for impression id imp bidder appnexus"},
+			expectedNonBid:   openrtb_ext.ResponseRejectedAdvertiserExclusions,
+		},
+		{
+			description:      "Blocked app",
+			blocking:         config.AccountBlocking{Mode: "enforce"},
+			bid:              openrtb2.Bid{ID: "bid", ImpID: "imp", Bundle: "com.blocked.app"},
+			expectedMessages: []string{"bid rejected [bid ID: bid] reason: app bundle com.blocked.app is blocked by bapp for impression id imp bidder appnexus"},
+			expectedNonBid:   openrtb_ext.ResponseRejectedAdvertiserExclusions,
+		},
+		{
+			description:      "Blocked attribute",
+			blocking:         config.AccountBlocking{Mode: "enforce"},
+			bid:              openrtb2.Bid{ID: "bid", ImpID: "imp", Attr: []adcom1.CreativeAttribute{adcom1.AttrAudioAuto}},
+			expectedMessages: []string{"bid rejected [bid ID: bid] reason: creative attribute 1 is blocked by battr for impression id imp bidder appnexus"},
+			expectedNonBid:   openrtb_ext.ResponseRejectedAdvertiserBlockedByAttr,
+		},
+		{
+			description:  "Attribute blocked on another media type",
+			blocking:     config.AccountBlocking{Mode: "enforce"},
+			bid:          openrtb2.Bid{ID: "bid", ImpID: "imp", Attr: []adcom1.CreativeAttribute{adcom1.AttrAudioAuto}},
+			bidType:      openrtb_ext.BidTypeVideo,
+			expectedBids: 1,
+		},
+		{
+			description:      "Warn",
+			blocking:         config.AccountBlocking{Mode: "warn"},
+			bid:              openrtb2.Bid{ID: "bid", ImpID: "imp", ADomain: []string{"blocked.com"}},
+			expectedBids:     1,
+			expectedMessages: []string{"bid not rejected in warn mode [bid ID: bid] reason: advertiser domain blocked.com is blocked by badv blocked.com for impression id imp bidder appnexus"},
+		},
+		{
+			description:      "Bidder mode override",
+			blocking:         config.AccountBlocking{Mode: "skip", BidderModes: map[string]string{"appnexus": "enforce"}},
+			bid:              openrtb2.Bid{ID: "bid", ImpID: "imp", ADomain: []string{"blocked.com"}},
+			expectedMessages: []string{"bid rejected [bid ID: bid] reason: advertiser domain blocked.com is blocked by badv blocked.com for impression id imp bidder appnexus"},
+			expectedNonBid:   openrtb_ext.ResponseRejectedAdvertiserExclusions,
+		},
+	}
+
+	for _, test := range testCases {
+		bid := test.bid
+		bidType := test.bidType
+		if bidType == "" {
+			bidType = openrtb_ext.BidTypeBanner
+		}
+		seatBids := map[openrtb_ext.BidderName]*pbsOrtbSeatBid{
+			openrtb_ext.BidderAppnexus: {bids: []*pbsOrtbBid{{bid: &bid, bidType: bidType}}},
+		}
+		seatNonBids := nonBids{}
+
+		seatBids, messages := enforceBlocking(bidRequest, test.blocking, seatBids, &seatNonBids)
+
+		assert.Len(t, seatBids[openrtb_ext.BidderAppnexus].bids, test.expectedBids, test.description)
+		assert.Equal(t, test.expectedMessages, messages, test.description)
+		if test.expectedNonBid != 0 {
+			if assert.Len(t, seatNonBids.seatNonBidsMap["appnexus"], 1, test.description) {
+				assert.Equal(t, test.expectedNonBid, seatNonBids.seatNonBidsMap["appnexus"][0].StatusCode, test.description)
+			}
+		} else {
+			assert.Empty(t, seatNonBids.seatNonBidsMap, test.description)
+		}
+	}
+}
+
+func TestIsBlockedDomain(t *testing.T) {
+	assert.True(t, isBlockedDomain("blocked.com", "blocked.com"))
+	assert.True(t, isBlockedDomain("ads.blocked.com", "BLOCKED.com"))
+	assert.False(t, isBlockedDomain("notblocked.com", "blocked.com"))
+	assert.False(t, isBlockedDomain("", "blocked.com"))
+	assert.False(t, isBlockedDomain("blocked.com", ""))
+}
+
+func TestIsBlockedCategory(t *testing.T) {
+	assert.True(t, isBlockedCategory("IAB1", "IAB1"))
+	assert.True(t, isBlockedCategory("iab1-2", "IAB1"))
+	assert.False(t, isBlockedCategory("IAB10", "IAB1"))
+	assert.False(t, isBlockedCategory("IAB1", "IAB1-2"))
+	assert.False(t, isBlockedCategory("", "IAB1"))
+}
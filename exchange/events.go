@@ -119,5 +119,6 @@ func (ev *eventTracking) makeEventURL(evType analytics.EventType, pbsBid *pbsOrt
 			AccountID:   ev.accountID,
 			Timestamp:   ev.auctionTimestampMs,
 			Integration: ev.integrationType,
+			Price:       pbsBid.clearingPrice,
 		})
 }
@@ -15,11 +15,14 @@ import (
 	"time"
 
 	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/bidadjustment"
+	"github.com/prebid/prebid-server/bidderhealth"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/currency"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/experiment/adscert"
 	"github.com/prebid/prebid-server/firstpartydata"
+	"github.com/prebid/prebid-server/floors"
 	"github.com/prebid/prebid-server/gdpr"
 	"github.com/prebid/prebid-server/hooks/hookexecution"
 	"github.com/prebid/prebid-server/metrics"
@@ -44,7 +47,24 @@ type extCacheInstructions struct {
 // Exchange runs Auctions. Implementations must be threadsafe, and will be shared across many goroutines.
 type Exchange interface {
 	// HoldAuction executes an OpenRTB v2.5 Auction.
-	HoldAuction(ctx context.Context, r AuctionRequest, debugLog *DebugLog) (*openrtb2.BidResponse, error)
+	HoldAuction(ctx context.Context, r AuctionRequest, debugLog *DebugLog) (*AuctionResponse, error)
+}
+
+// AuctionResponse contains the OpenRTB bid response of an auction along with the auction details
+// which are not always part of that response.
+type AuctionResponse struct {
+	*openrtb2.BidResponse
+	// SeatNonBid lists the imps each seat did not bid on and the bids rejected during the auction.
+	// It is populated even when the request did not ask for it in bidresponse.ext.prebid.seatnonbid.
+	SeatNonBid []openrtb_ext.SeatNonBid
+}
+
+// GetSeatNonBid returns the seat non-bids of the auction, if any
+func (ar *AuctionResponse) GetSeatNonBid() []openrtb_ext.SeatNonBid {
+	if ar == nil {
+		return nil
+	}
+	return ar.SeatNonBid
 }
 
 // IdFetcher can find the user's ID for a specific Bidder.
@@ -71,6 +91,9 @@ type exchange struct {
 	hostSChainNode    *openrtb2.SupplyChainNode
 	adsCertSigner     adscert.Signer
 	server            config.Server
+	priceFloorEnabled bool
+	priceFloorFetcher floors.FloorFetcher
+	bidderHealth      bidderhealth.Tracker
 }
 
 // Container to pass out response ext data from the GetAllBids goroutines back into the main thread
@@ -87,6 +110,7 @@ type bidResponseWrapper struct {
 	adapterSeatBids []*pbsOrtbSeatBid
 	adapterExtra    *seatResponseExtra
 	bidder          openrtb_ext.BidderName
+	nonBids         nonBids
 }
 
 type BidIDGenerator interface {
@@ -117,7 +141,7 @@ func (randomDeduplicateBidBooleanGenerator) Generate() bool {
 	return rand.Intn(100) < 50
 }
 
-func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid_cache_client.Client, cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, metricsEngine metrics.MetricsEngine, infos config.BidderInfos, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, currencyConverter *currency.RateConverter, categoriesFetcher stored_requests.CategoryFetcher, adsCertSigner adscert.Signer) Exchange {
+func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid_cache_client.Client, cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, metricsEngine metrics.MetricsEngine, infos config.BidderInfos, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, currencyConverter *currency.RateConverter, categoriesFetcher stored_requests.CategoryFetcher, adsCertSigner adscert.Signer, priceFloorFetcher floors.FloorFetcher, bidderHealth bidderhealth.Tracker) Exchange {
 	bidderToSyncerKey := map[string]string{}
 	for bidder, syncer := range syncersByBidder {
 		bidderToSyncerKey[bidder] = syncer.Key()
@@ -145,11 +169,16 @@ func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid
 			CCPA: cfg.CCPA,
 			GDPR: cfg.GDPR,
 			LMT:  cfg.LMT,
+			GPP:  cfg.GPP,
 		},
 		bidIDGenerator: &bidIDGenerator{cfg.GenerateBidID},
 		hostSChainNode: cfg.HostSChainNode,
 		adsCertSigner:  adsCertSigner,
 		server:         config.Server{ExternalUrl: cfg.ExternalURL, GvlID: cfg.GDPR.HostVendorID, DataCenter: cfg.DataCenter},
+
+		priceFloorEnabled: cfg.PriceFloors.Enabled,
+		priceFloorFetcher: priceFloorFetcher,
+		bidderHealth:      bidderHealth,
 	}
 }
 
@@ -196,13 +225,17 @@ type BidderRequest struct {
 	ImpReplaceImpId       map[string]bool
 }
 
-func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *DebugLog) (*openrtb2.BidResponse, error) {
+func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *DebugLog) (*AuctionResponse, error) {
 	reject := r.HookExecutor.ExecuteProcessedAuctionStage(r.BidRequestWrapper.BidRequest)
 	if reject != nil {
 		return nil, reject
 	}
 
 	var errs []error
+	if err := applyAccountTargeting(r.BidRequestWrapper, r.Account.Targeting); err != nil {
+		return nil, err
+	}
+
 	// rebuild/resync the request in the request wrapper.
 	if err := r.BidRequestWrapper.RebuildRequest(); err != nil {
 		return nil, err
@@ -211,6 +244,30 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 	if err != nil {
 		return nil, err
 	}
+
+	// Get currency rates conversions for the auction
+	conversions := e.getAuctionCurrencyRates(requestExt.Prebid.CurrencyConversions, r.Account.Currency.Rates)
+
+	if e.priceFloorEnabled {
+		floorErrs := floors.EnrichWithPriceFloors(r.BidRequestWrapper, r.Account, conversions, e.priceFloorFetcher)
+		r.Warnings = append(r.Warnings, floorErrs...)
+		// rebuild/resync the request in the request wrapper as the imp floors and ext.prebid.floors may have been updated
+		if err := r.BidRequestWrapper.RebuildRequest(); err != nil {
+			return nil, err
+		}
+		requestExt, err = extractBidRequestExt(r.BidRequestWrapper.BidRequest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if mergeBlockingLists(r.BidRequestWrapper, r.Account.Blocking) {
+		// rebuild/resync the request in the request wrapper as the imp battr have been updated
+		if err := r.BidRequestWrapper.RebuildRequest(); err != nil {
+			return nil, err
+		}
+	}
+
 	if !e.server.Empty() {
 		requestExt.Prebid.Server = &openrtb_ext.ExtRequestPrebidServer{ExternalUrl: e.server.ExternalUrl, GvlID: e.server.GvlID, DataCenter: e.server.DataCenter}
 	}
@@ -258,6 +315,10 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 	}
 
 	bidAdjustmentFactors := getExtBidAdjustmentFactors(requestExt)
+	mergedBidAdjustments, bidAdjustmentWarnings := bidadjustment.Merge(requestExt.Prebid.BidAdjustments, r.Account.BidAdjustments)
+	r.Warnings = append(r.Warnings, bidAdjustmentWarnings...)
+	bidAdjustmentRules := bidadjustment.BuildRules(mergedBidAdjustments)
+	multiBidMap := getExtMultiBid(requestExt)
 
 	recordImpMetrics(r.BidRequestWrapper.BidRequest, e.me)
 
@@ -265,7 +326,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 	gdprDefaultValue := e.parseGDPRDefaultValue(r.BidRequestWrapper.BidRequest)
 
 	// Slice of BidRequests, each a copy of the original cleaned to only contain bidder data for the named bidder
-	bidderRequests, privacyLabels, errs := cleanOpenRTBRequests(ctx, r, requestExt, e.bidderToSyncerKey, e.me, gdprDefaultValue, e.privacyConfig, e.gdprPermsBuilder, e.tcf2ConfigBuilder, e.hostSChainNode)
+	bidderRequests, privacyLabels, errs := cleanOpenRTBRequests(ctx, r, requestExt, e.bidderToSyncerKey, e.me, gdprDefaultValue, e.privacyConfig, e.gdprPermsBuilder, e.tcf2ConfigBuilder, e.hostSChainNode, e.bidderInfo)
 
 	e.me.RecordRequestPrivacy(privacyLabels)
 
@@ -278,12 +339,10 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 	auctionCtx, cancel := e.makeAuctionContext(ctx, cacheInstructions.cacheBids)
 	defer cancel()
 
-	// Get currency rates conversions for the auction
-	conversions := e.getAuctionCurrencyRates(requestExt.Prebid.CurrencyConversions)
-
 	var adapterBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid
 	var adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra
 	var anyBidsReturned bool
+	var seatNonBids nonBids
 
 	// List of bidders we have requests for.
 	var liveAdapters []openrtb_ext.BidderName
@@ -307,7 +366,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 			alternateBidderCodes = *r.Account.AlternateBidderCodes
 		}
 
-		adapterBids, adapterExtra, anyBidsReturned = e.getAllBids(auctionCtx, bidderRequests, bidAdjustmentFactors, conversions, accountDebugAllow, r.GlobalPrivacyControlHeader, debugLog.DebugOverride, alternateBidderCodes, requestExt.Prebid.Experiment)
+		adapterBids, adapterExtra, anyBidsReturned, seatNonBids = e.getAllBids(auctionCtx, bidderRequests, bidAdjustmentFactors, bidAdjustmentRules, r.Account.BidValidations, conversions, accountDebugAllow, r.GlobalPrivacyControlHeader, debugLog.DebugOverride, alternateBidderCodes, requestExt.Prebid.Experiment, r.HookExecutor)
 	}
 
 	var auc *auction
@@ -315,11 +374,27 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 	var bidResponseExt *openrtb_ext.ExtBidResponse
 	if anyBidsReturned {
 
+		if e.priceFloorEnabled && r.Account.PriceFloors.Enabled {
+			var rejections []string
+			adapterBids, rejections = enforceFloors(r.BidRequestWrapper.BidRequest, requestExt.Prebid.Floors, r.Account, adapterBids, conversions, &seatNonBids)
+			for _, message := range rejections {
+				errs = append(errs, errors.New(message))
+			}
+		}
+
+		var blockingMessages []string
+		adapterBids, blockingMessages = enforceBlocking(r.BidRequestWrapper.BidRequest, r.Account.Blocking, adapterBids, &seatNonBids)
+		for _, message := range blockingMessages {
+			errs = append(errs, errors.New(message))
+		}
+
+		adapterBids = executeAllProcessedBidResponsesStage(r.HookExecutor, adapterBids)
+
 		var bidCategory map[string]string
 		//If includebrandcategory is present in ext then CE feature is on.
 		if requestExt.Prebid.Targeting != nil && requestExt.Prebid.Targeting.IncludeBrandCategory != nil {
 			var rejections []string
-			bidCategory, adapterBids, rejections, err = applyCategoryMapping(ctx, requestExt, adapterBids, e.categoriesFetcher, targData, &randomDeduplicateBidBooleanGenerator{})
+			bidCategory, adapterBids, rejections, err = applyCategoryMapping(ctx, requestExt, adapterBids, e.categoriesFetcher, targData, &randomDeduplicateBidBooleanGenerator{}, &seatNonBids)
 			if err != nil {
 				return nil, fmt.Errorf("Error in category mapping : %s", err.Error())
 			}
@@ -339,13 +414,23 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 			}
 		}
 
+		applyMultiBidLimits(adapterBids, multiBidMap, targData != nil && targData.preferDeals, &seatNonBids)
+
+		pricing := getAuctionPricing(requestExt, r.BidRequestWrapper.BidRequest, r.Account)
+		var pricingRejections []string
+		adapterBids, pricingRejections = applyAuctionPricing(r.BidRequestWrapper.BidRequest, pricing, adapterBids, targData != nil && targData.preferDeals, conversions, &seatNonBids)
+		for _, message := range pricingRejections {
+			errs = append(errs, errors.New(message))
+		}
+
 		evTracking := getEventTracking(&requestExt.Prebid, r.StartTime, &r.Account, e.bidderInfo, e.externalURL)
 		adapterBids = evTracking.modifyBidsForEvents(adapterBids)
 
 		if targData != nil {
 			// A non-nil auction is only needed if targeting is active. (It is used below this block to extract cache keys)
 			auc = newAuction(adapterBids, len(r.BidRequestWrapper.Imp), targData.preferDeals)
-			auc.setRoundedPrices(targData.priceGranularity)
+			auc.applyMultiBid(multiBidMap)
+			auc.setRoundedPrices(targData)
 
 			if requestExt.Prebid.SupportDeals {
 				dealErrs := applyDealSupport(r.BidRequestWrapper.BidRequest, auc, bidCategory)
@@ -367,7 +452,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 				errs = append(errs, cacheErrs...)
 			}
 
-			targData.setTargeting(auc, r.BidRequestWrapper.BidRequest.App != nil, bidCategory, r.Account.TruncateTargetAttribute)
+			targData.setTargeting(auc, r.BidRequestWrapper.BidRequest.App != nil, bidCategory, r.Account.TruncateTargetAttribute, multiBidMap)
 
 		}
 		bidResponseExt = e.makeExtBidResponse(adapterBids, adapterExtra, r, responseDebugAllow, requestExt.Prebid.Passthrough, errs)
@@ -393,7 +478,10 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 		bidResponseExt.Warnings[openrtb_ext.BidderReservedGeneral] = append(bidResponseExt.Warnings[openrtb_ext.BidderReservedGeneral], accountDebugDisabledWarning)
 	}
 
-	for _, warning := range r.Warnings {
+	generalWarnings := make([]error, 0, len(r.Warnings))
+	generalWarnings = append(generalWarnings, r.Warnings...)
+	generalWarnings = append(generalWarnings, errortypes.WarningOnly(errs)...)
+	for _, warning := range generalWarnings {
 		generalWarning := openrtb_ext.ExtBidderMessage{
 			Code:    errortypes.ReadCode(warning),
 			Message: warning.Error(),
@@ -401,8 +489,17 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 		bidResponseExt.Warnings[openrtb_ext.BidderReservedGeneral] = append(bidResponseExt.Warnings[openrtb_ext.BidderReservedGeneral], generalWarning)
 	}
 
+	seatNonBid := seatNonBids.get()
+	if requestExt.Prebid.ReturnAllBidStatus && len(seatNonBid) > 0 {
+		if bidResponseExt.Prebid == nil {
+			bidResponseExt.Prebid = &openrtb_ext.ExtResponsePrebid{}
+		}
+		bidResponseExt.Prebid.SeatNonBid = seatNonBid
+	}
+
 	// Build the response
-	return e.buildBidResponse(ctx, liveAdapters, adapterBids, r.BidRequestWrapper.BidRequest, adapterExtra, auc, bidResponseExt, cacheInstructions.returnCreative, r.ImpExtInfoMap, errs)
+	bidResponse, err := e.buildBidResponse(ctx, liveAdapters, adapterBids, r.BidRequestWrapper.BidRequest, adapterExtra, auc, bidResponseExt, cacheInstructions.returnCreative, r.ImpExtInfoMap, errs)
+	return &AuctionResponse{BidResponse: bidResponse, SeatNonBid: seatNonBid}, err
 }
 
 func (e *exchange) parseGDPRDefaultValue(bidRequest *openrtb2.BidRequest) gdpr.Signal {
@@ -447,12 +544,14 @@ func applyDealSupport(bidRequest *openrtb2.BidRequest, auc *auction, bidCategory
 
 	for impID, topBidsPerImp := range auc.winningBidsByBidder {
 		impDeal := impDealMap[impID]
-		for bidder, topBidPerBidder := range topBidsPerImp {
-			if topBidPerBidder.dealPriority > 0 {
-				if validateDealTier(impDeal[bidder]) {
-					updateHbPbCatDur(topBidPerBidder, impDeal[bidder], bidCategory)
-				} else {
-					errs = append(errs, fmt.Errorf("dealTier configuration invalid for bidder '%s', imp ID '%s'", string(bidder), impID))
+		for bidder, topBidsPerBidder := range topBidsPerImp {
+			for _, topBid := range topBidsPerBidder {
+				if topBid.dealPriority > 0 {
+					if validateDealTier(impDeal[bidder]) {
+						updateHbPbCatDur(topBid, impDeal[bidder], bidCategory)
+					} else {
+						errs = append(errs, fmt.Errorf("dealTier configuration invalid for bidder '%s', imp ID '%s'", string(bidder), impID))
+					}
 				}
 			}
 		}
@@ -511,19 +610,23 @@ func (e *exchange) getAllBids(
 	ctx context.Context,
 	bidderRequests []BidderRequest,
 	bidAdjustments map[string]float64,
+	bidAdjustmentRules bidadjustment.Rules,
+	bidValidations config.AccountBidValidations,
 	conversions currency.Conversions,
 	accountDebugAllowed bool,
 	globalPrivacyControlHeader string,
 	headerDebugAllowed bool,
 	alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes,
-	experiment *openrtb_ext.Experiment) (
+	experiment *openrtb_ext.Experiment,
+	hookExecutor hookexecution.StageExecutor) (
 	map[openrtb_ext.BidderName]*pbsOrtbSeatBid,
-	map[openrtb_ext.BidderName]*seatResponseExtra, bool) {
+	map[openrtb_ext.BidderName]*seatResponseExtra, bool, nonBids) {
 	// Set up pointers to the bid results
 	adapterBids := make(map[openrtb_ext.BidderName]*pbsOrtbSeatBid, len(bidderRequests))
 	adapterExtra := make(map[openrtb_ext.BidderName]*seatResponseExtra, len(bidderRequests))
 	chBids := make(chan *bidResponseWrapper, len(bidderRequests))
 	bidsFound := false
+	var seatNonBids nonBids
 
 	for _, bidder := range bidderRequests {
 		// Here we actually call the adapters and collect the bids.
@@ -535,6 +638,24 @@ func (e *exchange) getAllBids(
 			}
 			brw := new(bidResponseWrapper)
 			brw.bidder = bidderRequest.BidderName
+			if e.bidderHealth != nil && !e.bidderHealth.Allow(bidderRequest.BidderCoreName) {
+				brw.adapterExtra = &seatResponseExtra{
+					Warnings: errsToBidderWarnings([]error{&errortypes.Warning{
+						Message:     fmt.Sprintf("%s request skipped: the bidder is unhealthy", bidderRequest.BidderName),
+						WarningCode: errortypes.BidderUnhealthyWarningCode,
+					}}),
+				}
+				brw.nonBids.addBlockedRequest(bidderRequest, openrtb_ext.RequestBlockedGeneral)
+				chBids <- brw
+				return
+			}
+			// Record the outcome even when the request panics, so that a half-open bidder gets its probe back
+			healthOutcome := bidderhealth.Error
+			if e.bidderHealth != nil {
+				defer func() {
+					e.bidderHealth.Record(bidderRequest.BidderCoreName, healthOutcome)
+				}()
+			}
 			// Defer basic metrics to insure we capture them after all the values have been set
 			defer func() {
 				e.me.RecordAdapterRequest(bidderRequest.BidderLabels)
@@ -550,8 +671,10 @@ func (e *exchange) getAllBids(
 				headerDebugAllowed:  headerDebugAllowed,
 				addCallSignHeader:   isAdsCertEnabled(experiment, e.bidderInfo[string(bidderRequest.BidderName)]),
 				bidAdjustments:      bidAdjustments,
+				bidAdjustmentRules:  bidAdjustmentRules,
+				bidValidations:      bidValidations,
 			}
-			seatBids, err := e.adapterMap[bidderRequest.BidderCoreName].requestBid(ctx, bidderRequest, conversions, &reqInfo, e.adsCertSigner, bidReqOptions, alternateBidderCodes)
+			seatBids, err := e.adapterMap[bidderRequest.BidderCoreName].requestBid(ctx, bidderRequest, conversions, &reqInfo, e.adsCertSigner, bidReqOptions, alternateBidderCodes, hookExecutor)
 
 			// Add in time reporting
 			elapsed := time.Since(start)
@@ -565,12 +688,14 @@ func (e *exchange) getAllBids(
 
 			// Timing statistics
 			e.me.RecordAdapterTime(bidderRequest.BidderLabels, time.Since(start))
+			healthOutcome = bidderhealth.OutcomeFromErrors(err)
 			bidderRequest.BidderLabels.AdapterBids = bidsToMetric(brw.adapterSeatBids)
 			bidderRequest.BidderLabels.AdapterErrors = errorsToMetric(err)
 			// Append any bid validation errors to the error list
 			ae.Errors = errsToBidderErrors(err)
 			ae.Warnings = errsToBidderWarnings(err)
 			brw.adapterExtra = ae
+			brw.nonBids.addSeatBids(bidderRequest, seatBids, err)
 			for _, seatBid := range seatBids {
 				if seatBid != nil {
 					for _, bid := range seatBid.bids {
@@ -600,13 +725,84 @@ func (e *exchange) getAllBids(
 		}
 		//but we need to add all bidders data to adapterExtra to have metrics and other metadata
 		adapterExtra[brw.bidder] = brw.adapterExtra
+		seatNonBids.append(brw.nonBids)
 
 		if !bidsFound && adapterBids[brw.bidder] != nil && len(adapterBids[brw.bidder].bids) > 0 {
 			bidsFound = true
 		}
 	}
 
-	return adapterBids, adapterExtra, bidsFound
+	return adapterBids, adapterExtra, bidsFound, seatNonBids
+}
+
+// executeAllProcessedBidResponsesStage exposes the bids of every seat to the all_processed_bid_responses hooks
+// and returns the seat bids resulting from the changes made by the hooks.
+func executeAllProcessedBidResponsesStage(hookExecutor hookexecution.StageExecutor, adapterBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid) map[openrtb_ext.BidderName]*pbsOrtbSeatBid {
+	responses := make(map[openrtb_ext.BidderName]*adapters.BidderResponse, len(adapterBids))
+	processedBids := make(map[*openrtb2.Bid]*pbsOrtbBid)
+	for seat, seatBid := range adapterBids {
+		if seatBid == nil {
+			continue
+		}
+		response := &adapters.BidderResponse{
+			Currency: seatBid.currency,
+			Bids:     make([]*adapters.TypedBid, 0, len(seatBid.bids)),
+		}
+		for _, pbsBid := range seatBid.bids {
+			processedBids[pbsBid.bid] = pbsBid
+			response.Bids = append(response.Bids, &adapters.TypedBid{
+				Bid:          pbsBid.bid,
+				BidMeta:      pbsBid.bidMeta,
+				BidType:      pbsBid.bidType,
+				BidVideo:     pbsBid.bidVideo,
+				DealPriority: pbsBid.dealPriority,
+				Seat:         seat,
+			})
+		}
+		responses[seat] = response
+	}
+
+	hookExecutor.ExecuteAllProcessedBidResponsesStage(responses)
+
+	for seat := range adapterBids {
+		if _, ok := responses[seat]; !ok {
+			delete(adapterBids, seat)
+		}
+	}
+	for seat, response := range responses {
+		if response == nil {
+			delete(adapterBids, seat)
+			continue
+		}
+		seatBid, ok := adapterBids[seat]
+		if !ok {
+			seatBid = &pbsOrtbSeatBid{currency: response.Currency, seat: seat.String()}
+			adapterBids[seat] = seatBid
+		}
+
+		bids := make([]*pbsOrtbBid, 0, len(response.Bids))
+		for _, typedBid := range response.Bids {
+			if typedBid == nil || typedBid.Bid == nil {
+				continue
+			}
+			pbsBid, ok := processedBids[typedBid.Bid]
+			if !ok {
+				pbsBid = &pbsOrtbBid{
+					bid:            typedBid.Bid,
+					originalBidCPM: typedBid.Bid.Price,
+					originalBidCur: seatBid.currency,
+				}
+			}
+			pbsBid.bidMeta = typedBid.BidMeta
+			pbsBid.bidType = typedBid.BidType
+			pbsBid.bidVideo = typedBid.BidVideo
+			pbsBid.dealPriority = typedBid.DealPriority
+			bids = append(bids, pbsBid)
+		}
+		seatBid.bids = bids
+	}
+
+	return adapterBids
 }
 
 func (e *exchange) recoverSafely(bidderRequests []BidderRequest,
@@ -633,6 +829,11 @@ func (e *exchange) recoverSafely(bidderRequests []BidderRequest,
 				// Let the master request know that there is no data here
 				brw := new(bidResponseWrapper)
 				brw.adapterExtra = new(seatResponseExtra)
+				if bidderRequest.BidRequest != nil {
+					for _, imp := range bidderRequest.BidRequest.Imp {
+						brw.nonBids.addImp(imp.ID, openrtb_ext.ErrorGeneral, bidderRequest.BidderName.String())
+					}
+				}
 				chBids <- brw
 			}
 		}()
@@ -739,7 +940,7 @@ func encodeBidResponseExt(bidResponseExt *openrtb_ext.ExtBidResponse) ([]byte, e
 	return buffer.Bytes(), err
 }
 
-func applyCategoryMapping(ctx context.Context, requestExt *openrtb_ext.ExtRequest, seatBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, categoriesFetcher stored_requests.CategoryFetcher, targData *targetData, booleanGenerator deduplicateChanceGenerator) (map[string]string, map[openrtb_ext.BidderName]*pbsOrtbSeatBid, []string, error) {
+func applyCategoryMapping(ctx context.Context, requestExt *openrtb_ext.ExtRequest, seatBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, categoriesFetcher stored_requests.CategoryFetcher, targData *targetData, booleanGenerator deduplicateChanceGenerator, seatNonBids *nonBids) (map[string]string, map[openrtb_ext.BidderName]*pbsOrtbSeatBid, []string, error) {
 	res := make(map[string]string)
 
 	type bidDedupe struct {
@@ -747,6 +948,7 @@ func applyCategoryMapping(ctx context.Context, requestExt *openrtb_ext.ExtReques
 		bidIndex   int
 		bidID      string
 		bidPrice   string
+		bid        *pbsOrtbBid
 	}
 
 	dedupe := make(map[string]bidDedupe)
@@ -802,6 +1004,7 @@ func applyCategoryMapping(ctx context.Context, requestExt *openrtb_ext.ExtReques
 					//on receiving bids from adapters if no unique IAB category is returned  or if no ad server category is returned discard the bid
 					bidsToRemove = append(bidsToRemove, bidInd)
 					rejections = updateRejections(rejections, bidID, "Bid did not contain a category")
+					seatNonBids.addBid(bid, openrtb_ext.ResponseRejectedCategoryMappingInvalid, bidderName.String())
 					continue
 				}
 				if translateCategories {
@@ -813,6 +1016,7 @@ func applyCategoryMapping(ctx context.Context, requestExt *openrtb_ext.ExtReques
 						bidsToRemove = append(bidsToRemove, bidInd)
 						reason := fmt.Sprintf("Category mapping file for primary ad server: '%s', publisher: '%s' not found", primaryAdServer, publisher)
 						rejections = updateRejections(rejections, bidID, reason)
+						seatNonBids.addBid(bid, openrtb_ext.ResponseRejectedCategoryMappingInvalid, bidderName.String())
 						continue
 					}
 				} else {
@@ -823,7 +1027,7 @@ func applyCategoryMapping(ctx context.Context, requestExt *openrtb_ext.ExtReques
 
 			// TODO: consider should we remove bids with zero duration here?
 
-			pb = GetPriceBucket(bid.bid.Price, targData.priceGranularity)
+			pb = GetPriceBucket(bid.bid.Price, targData.priceGranularityFor(bid.bidType))
 
 			newDur := duration
 			if len(requestExt.Prebid.Targeting.DurationRangeSec) > 0 {
@@ -833,6 +1037,7 @@ func applyCategoryMapping(ctx context.Context, requestExt *openrtb_ext.ExtReques
 				if duration > durationRange[len(durationRange)-1] {
 					bidsToRemove = append(bidsToRemove, bidInd)
 					rejections = updateRejections(rejections, bidID, "Bid duration exceeds maximum allowed")
+					seatNonBids.addBid(bid, openrtb_ext.ResponseRejectedGeneral, bidderName.String())
 					continue
 				}
 				for _, dur := range durationRange {
@@ -880,10 +1085,12 @@ func applyCategoryMapping(ctx context.Context, requestExt *openrtb_ext.ExtReques
 						// An older bid from the current bidder
 						bidsToRemove = append(bidsToRemove, dupe.bidIndex)
 						rejections = updateRejections(rejections, dupe.bidID, "Bid was deduplicated")
+						seatNonBids.addBid(dupe.bid, openrtb_ext.ResponseRejectedDuplicate, dupe.bidderName.String())
 					} else {
 						// An older bid from a different seatBid we've already finished with
 						oldSeatBid := (seatBids)[dupe.bidderName]
 						rejections = updateRejections(rejections, dupe.bidID, "Bid was deduplicated")
+						seatNonBids.addBid(dupe.bid, openrtb_ext.ResponseRejectedDuplicate, dupe.bidderName.String())
 						if len(oldSeatBid.bids) == 1 {
 							seatBidsToRemove = append(seatBidsToRemove, dupe.bidderName)
 						} else {
@@ -900,11 +1107,12 @@ func applyCategoryMapping(ctx context.Context, requestExt *openrtb_ext.ExtReques
 					// Remove this bid
 					bidsToRemove = append(bidsToRemove, bidInd)
 					rejections = updateRejections(rejections, bidID, "Bid was deduplicated")
+					seatNonBids.addBid(bid, openrtb_ext.ResponseRejectedDuplicate, bidderName.String())
 					continue
 				}
 			}
 			res[bidID] = categoryDuration
-			dedupe[dupeKey] = bidDedupe{bidderName: bidderName, bidIndex: bidInd, bidID: bidID, bidPrice: pb}
+			dedupe[dupeKey] = bidDedupe{bidderName: bidderName, bidIndex: bidInd, bidID: bidID, bidPrice: pb, bid: bid}
 		}
 
 		if len(bidsToRemove) > 0 {
@@ -1004,8 +1212,8 @@ func (e *exchange) makeExtBidResponse(adapterBids map[openrtb_ext.BidderName]*pb
 		if len(responseExtra.Errors) > 0 {
 			bidResponseExt.Errors[bidderName] = responseExtra.Errors
 		}
-		if len(errList) > 0 {
-			bidResponseExt.Errors[openrtb_ext.PrebidExtKey] = errsToBidderErrors(errList)
+		if prebidErrs := errsToBidderErrors(errList); len(prebidErrs) > 0 {
+			bidResponseExt.Errors[openrtb_ext.PrebidExtKey] = prebidErrs
 		}
 		bidResponseExt.ResponseTimeMillis[bidderName] = responseExtra.ResponseTimeMillis
 		// Defering the filling of bidResponseExt.Usersync[bidderName] until later
@@ -1045,6 +1253,8 @@ func (e *exchange) makeBid(bids []*pbsOrtbBid, auc *auction, returnCreative bool
 			Meta:              bid.bidMeta,
 			Video:             bid.bidVideo,
 			BidId:             bid.generatedBidID,
+			TargetBidderCode:  bid.targetBidderCode,
+			BidAdjustment:     bid.bidAdjustment,
 		}
 
 		if cacheInfo, found := e.getBidCacheInfo(bid, auc); found {
@@ -1132,10 +1342,16 @@ func (e *exchange) getBidCacheInfo(bid *pbsOrtbBid, auction *auction) (cacheInfo
 	return
 }
 
-func (e *exchange) getAuctionCurrencyRates(requestRates *openrtb_ext.ExtRequestCurrency) currency.Conversions {
+func (e *exchange) getAuctionCurrencyRates(requestRates *openrtb_ext.ExtRequestCurrency, accountRates map[string]map[string]float64) currency.Conversions {
+	pbsRates := e.currencyConverter.Rates()
+	if len(accountRates) > 0 {
+		// The account rates override the rates of the currency converter
+		pbsRates = currency.NewAggregateConversions(currency.NewRates(currency.NormalizeConversions(accountRates)), pbsRates)
+	}
+
 	if requestRates == nil {
 		// No bidRequest.ext.currency field was found, use PBS rates as usual
-		return e.currencyConverter.Rates()
+		return pbsRates
 	}
 
 	// If bidRequest.ext.currency.usepbsrates is nil, we understand its value as true. It will be false
@@ -1152,12 +1368,12 @@ func (e *exchange) getAuctionCurrencyRates(requestRates *openrtb_ext.ExtRequestC
 	// Both PBS and custom rates can be used, check if ConversionRates is not empty
 	if len(requestRates.ConversionRates) == 0 {
 		// Custom rates map is empty, use PBS rates only
-		return e.currencyConverter.Rates()
+		return pbsRates
 	}
 
 	// Return an AggregateConversions object that includes both custom and PBS currency rates but will
 	// prioritize custom rates over PBS rates whenever a currency rate is found in both
-	return currency.NewAggregateConversions(currency.NewRates(requestRates.ConversionRates), e.currencyConverter.Rates())
+	return currency.NewAggregateConversions(currency.NewRates(requestRates.ConversionRates), pbsRates)
 }
 
 func findCacheID(bid *pbsOrtbBid, auction *auction) (string, bool) {
@@ -1234,7 +1450,7 @@ func buildStoredAuctionResponse(storedAuctionResponses map[string]json.RawMessag
 			} else {
 				//create new seat bid and add it to live adapters
 				liveAdapters = append(liveAdapters, bidderName)
-				newSeatBid := pbsOrtbSeatBid{bidsToAdd, "", nil, ""}
+				newSeatBid := pbsOrtbSeatBid{bids: bidsToAdd}
 				adapterBids[bidderName] = &newSeatBid
 
 			}
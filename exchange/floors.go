@@ -0,0 +1,96 @@
+package exchange
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// floorsEnforceRandom is used to decide whether floors are enforced for an auction according to the enforce rates
+var floorsEnforceRandom = rand.Intn
+
+// enforceFloors removes the bids priced below the floor of the imp they were made for. It returns the remaining
+// bids along with a rejection message for every bid removed. Removed bids are also recorded in seatNonBids.
+func enforceFloors(bidRequest *openrtb2.BidRequest, floors *openrtb_ext.PriceFloorRules, account config.Account, seatBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, conversions currency.Conversions, seatNonBids *nonBids) (map[openrtb_ext.BidderName]*pbsOrtbSeatBid, []string) {
+	if !shouldEnforceFloors(floors, account.PriceFloors) {
+		return seatBids, nil
+	}
+
+	enforceDealFloors := account.PriceFloors.EnforceDealFloors
+	if floorDeals, exists := floors.GetFloorDeals(); exists {
+		enforceDealFloors = floorDeals
+	}
+
+	impFloors := make(map[string]openrtb2.Imp, len(bidRequest.Imp))
+	for _, imp := range bidRequest.Imp {
+		if imp.BidFloor > 0 {
+			impFloors[imp.ID] = imp
+		}
+	}
+
+	var rejections []string
+	for bidderName, seatBid := range seatBids {
+		if seatBid == nil {
+			continue
+		}
+		validBids := make([]*pbsOrtbBid, 0, len(seatBid.bids))
+		for _, pbsBid := range seatBid.bids {
+			imp, hasFloor := impFloors[pbsBid.bid.ImpID]
+			if !hasFloor || (pbsBid.bid.DealID != "" && !enforceDealFloors) {
+				validBids = append(validBids, pbsBid)
+				continue
+			}
+
+			floorCur := imp.BidFloorCur
+			if floorCur == "" {
+				floorCur = "USD"
+			}
+			bidCur := seatBid.currency
+			if bidCur == "" {
+				bidCur = "USD"
+			}
+
+			rate, err := conversions.GetRate(bidCur, floorCur)
+			if err != nil {
+				rejections = updateRejections(rejections, pbsBid.bid.ID, fmt.Sprintf("Unable to convert bid currency %s to floor currency %s: %v", bidCur, floorCur, err))
+				seatNonBids.addBid(pbsBid, openrtb_ext.ResponseRejectedGeneral, bidderName.String())
+				continue
+			}
+
+			if bidPrice := pbsBid.bid.Price * rate; bidPrice < imp.BidFloor {
+				reason := fmt.Sprintf("bid price value %.4f %s is less than bidFloor value %.4f %s for impression id %s bidder %s", bidPrice, floorCur, imp.BidFloor, floorCur, imp.ID, bidderName)
+				rejections = updateRejections(rejections, pbsBid.bid.ID, reason)
+				if pbsBid.bid.DealID != "" {
+					seatNonBids.addBid(pbsBid, openrtb_ext.ResponseRejectedBelowDealFloor, bidderName.String())
+				} else {
+					seatNonBids.addBid(pbsBid, openrtb_ext.ResponseRejectedBelowFloor, bidderName.String())
+				}
+				continue
+			}
+			validBids = append(validBids, pbsBid)
+		}
+		seatBid.bids = validBids
+	}
+
+	return seatBids, rejections
+}
+
+// shouldEnforceFloors reports whether the floors resolved for the auction must be enforced by Prebid Server
+func shouldEnforceFloors(floors *openrtb_ext.PriceFloorRules, accountFloors config.AccountPriceFloors) bool {
+	if floors == nil || floors.IsSkipped() || !floors.GetEnforcePBS() {
+		return false
+	}
+
+	enforceRate := floorsEnforceRandom(100)
+	if enforceRate >= accountFloors.EnforceFloorsRate {
+		return false
+	}
+	if requestRate := floors.GetEnforceRate(); requestRate > 0 && enforceRate >= requestRate {
+		return false
+	}
+	return true
+}
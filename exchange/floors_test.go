@@ -0,0 +1,121 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func floorsTestSeatBids() map[openrtb_ext.BidderName]*pbsOrtbSeatBid {
+	return map[openrtb_ext.BidderName]*pbsOrtbSeatBid{
+		"appnexus": {
+			currency: "USD",
+			bids: []*pbsOrtbBid{
+				{bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", Price: 1.2}},
+				{bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp1", Price: 0.8}},
+				{bid: &openrtb2.Bid{ID: "bid3", ImpID: "imp1", Price: 0.5, DealID: "deal1"}},
+				{bid: &openrtb2.Bid{ID: "bid4", ImpID: "imp2", Price: 0.1}},
+			},
+		},
+		"rubicon": {
+			currency: "EUR",
+			bids: []*pbsOrtbBid{
+				{bid: &openrtb2.Bid{ID: "bid5", ImpID: "imp1", Price: 0.9}},
+			},
+		},
+	}
+}
+
+func bidIDs(seatBid *pbsOrtbSeatBid) []string {
+	ids := make([]string, 0, len(seatBid.bids))
+	for _, pbsBid := range seatBid.bids {
+		ids = append(ids, pbsBid.bid.ID)
+	}
+	return ids
+}
+
+func TestEnforceFloors(t *testing.T) {
+	original := floorsEnforceRandom
+	defer func() { floorsEnforceRandom = original }()
+	floorsEnforceRandom = func(n int) int { return 50 }
+
+	bidRequest := &openrtb2.BidRequest{
+		Imp: []openrtb2.Imp{
+			{ID: "imp1", BidFloor: 1, BidFloorCur: "USD"},
+			{ID: "imp2"},
+		},
+	}
+	conversions := currency.NewRates(map[string]map[string]float64{"EUR": {"USD": 1.2}})
+	enforcePBS, floorDeals := false, true
+
+	testCases := []struct {
+		name               string
+		floors             *openrtb_ext.PriceFloorRules
+		accountFloors      config.AccountPriceFloors
+		expectedAppnexus   []string
+		expectedRubicon    []string
+		expectedRejections []string
+	}{
+		{
+			name:             "enforced",
+			floors:           &openrtb_ext.PriceFloorRules{},
+			accountFloors:    config.AccountPriceFloors{EnforceFloorsRate: 100},
+			expectedAppnexus: []string{"bid1", "bid3", "bid4"},
+			expectedRubicon:  []string{"bid5"},
+			expectedRejections: []string{
+				"bid rejected [bid ID: bid2] reason: bid price value 0.8000 USD is less than bidFloor value 1.0000 USD for impression id imp1 bidder appnexus",
+			},
+		},
+		{
+			name:             "deal-floors-enforced",
+			floors:           &openrtb_ext.PriceFloorRules{Enforcement: &openrtb_ext.PriceFloorEnforcement{FloorDeals: &floorDeals}},
+			accountFloors:    config.AccountPriceFloors{EnforceFloorsRate: 100},
+			expectedAppnexus: []string{"bid1", "bid4"},
+			expectedRubicon:  []string{"bid5"},
+			expectedRejections: []string{
+				"bid rejected [bid ID: bid2] reason: bid price value 0.8000 USD is less than bidFloor value 1.0000 USD for impression id imp1 bidder appnexus",
+				"bid rejected [bid ID: bid3] reason: bid price value 0.5000 USD is less than bidFloor value 1.0000 USD for impression id imp1 bidder appnexus",
+			},
+		},
+		{
+			name:             "enforcement-disabled-on-request",
+			floors:           &openrtb_ext.PriceFloorRules{Enforcement: &openrtb_ext.PriceFloorEnforcement{EnforcePBS: &enforcePBS}},
+			accountFloors:    config.AccountPriceFloors{EnforceFloorsRate: 100},
+			expectedAppnexus: []string{"bid1", "bid2", "bid3", "bid4"},
+			expectedRubicon:  []string{"bid5"},
+		},
+		{
+			name:             "account-enforce-rate-not-met",
+			floors:           &openrtb_ext.PriceFloorRules{},
+			accountFloors:    config.AccountPriceFloors{EnforceFloorsRate: 50},
+			expectedAppnexus: []string{"bid1", "bid2", "bid3", "bid4"},
+			expectedRubicon:  []string{"bid5"},
+		},
+		{
+			name:             "request-enforce-rate-not-met",
+			floors:           &openrtb_ext.PriceFloorRules{Enforcement: &openrtb_ext.PriceFloorEnforcement{EnforceRate: 10}},
+			accountFloors:    config.AccountPriceFloors{EnforceFloorsRate: 100},
+			expectedAppnexus: []string{"bid1", "bid2", "bid3", "bid4"},
+			expectedRubicon:  []string{"bid5"},
+		},
+		{
+			name:             "no-floors",
+			accountFloors:    config.AccountPriceFloors{EnforceFloorsRate: 100},
+			expectedAppnexus: []string{"bid1", "bid2", "bid3", "bid4"},
+			expectedRubicon:  []string{"bid5"},
+		},
+	}
+
+	for _, test := range testCases {
+		account := config.Account{PriceFloors: test.accountFloors}
+		seatBids, rejections := enforceFloors(bidRequest, test.floors, account, floorsTestSeatBids(), conversions, &nonBids{})
+
+		assert.Equal(t, test.expectedAppnexus, bidIDs(seatBids["appnexus"]), test.name)
+		assert.Equal(t, test.expectedRubicon, bidIDs(seatBids["rubicon"]), test.name)
+		assert.Equal(t, test.expectedRejections, rejections, test.name)
+	}
+}
@@ -0,0 +1,132 @@
+package exchange
+
+import (
+	"sort"
+
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// nonBids collects, per seat, the imps a seat did not bid on and the bids removed from the auction
+type nonBids struct {
+	seatNonBidsMap map[string][]openrtb_ext.NonBid
+}
+
+// newNonBid builds the non-bid entry reported for a bid rejected with the given reason
+func newNonBid(bid *pbsOrtbBid, reason openrtb_ext.NonBidStatusCode) openrtb_ext.NonBid {
+	return openrtb_ext.NonBid{
+		ImpId:      bid.bid.ImpID,
+		StatusCode: reason,
+		Ext: &openrtb_ext.NonBidExt{
+			Prebid: openrtb_ext.ExtResponseNonBidPrebid{
+				Bid: openrtb_ext.NonBidObject{
+					ID:             bid.bid.ID,
+					Price:          bid.bid.Price,
+					ADomain:        bid.bid.ADomain,
+					Cat:            bid.bid.Cat,
+					DealID:         bid.bid.DealID,
+					W:              bid.bid.W,
+					H:              bid.bid.H,
+					OriginalBidCPM: bid.originalBidCPM,
+					OriginalBidCur: bid.originalBidCur,
+				},
+			},
+		},
+	}
+}
+
+// addBid records a bid of the seat which was rejected for the given reason
+func (snb *nonBids) addBid(bid *pbsOrtbBid, reason openrtb_ext.NonBidStatusCode, seat string) {
+	if bid == nil || bid.bid == nil {
+		return
+	}
+	snb.add(seat, newNonBid(bid, reason))
+}
+
+// addImp records an imp the seat did not bid on for the given reason
+func (snb *nonBids) addImp(impID string, reason openrtb_ext.NonBidStatusCode, seat string) {
+	snb.add(seat, openrtb_ext.NonBid{ImpId: impID, StatusCode: reason})
+}
+
+// addBlockedRequest records every imp of a bidder request which was not sent to the bidder for the given reason
+func (snb *nonBids) addBlockedRequest(bidderRequest BidderRequest, reason openrtb_ext.NonBidStatusCode) {
+	if bidderRequest.BidRequest == nil {
+		return
+	}
+	for _, imp := range bidderRequest.BidRequest.Imp {
+		snb.addImp(imp.ID, reason, bidderRequest.BidderName.String())
+	}
+}
+
+func (snb *nonBids) add(seat string, nonBids ...openrtb_ext.NonBid) {
+	if len(nonBids) == 0 {
+		return
+	}
+	if snb.seatNonBidsMap == nil {
+		snb.seatNonBidsMap = make(map[string][]openrtb_ext.NonBid)
+	}
+	snb.seatNonBidsMap[seat] = append(snb.seatNonBidsMap[seat], nonBids...)
+}
+
+// addSeatBids records the bids the bidder dropped from its seats along with the imps of the bidder request
+// which got no bid at all. The reason reported for those imps is derived from the errors the bidder returned.
+func (snb *nonBids) addSeatBids(bidderRequest BidderRequest, seatBids []*pbsOrtbSeatBid, errs []error) {
+	impsWithBids := make(map[string]struct{})
+	for _, seatBid := range seatBids {
+		if seatBid == nil {
+			continue
+		}
+		snb.add(seatBid.seat, seatBid.nonBids...)
+		for _, nonBid := range seatBid.nonBids {
+			impsWithBids[nonBid.ImpId] = struct{}{}
+		}
+		for _, pbsBid := range seatBid.bids {
+			impsWithBids[pbsBid.bid.ImpID] = struct{}{}
+		}
+	}
+
+	if bidderRequest.BidRequest == nil {
+		return
+	}
+	reason := noBidReason(errs)
+	for _, imp := range bidderRequest.BidRequest.Imp {
+		if _, ok := impsWithBids[imp.ID]; !ok {
+			snb.addImp(imp.ID, reason, bidderRequest.BidderName.String())
+		}
+	}
+}
+
+// append adds the non-bids collected by other to snb
+func (snb *nonBids) append(other nonBids) {
+	for seat, nonBids := range other.seatNonBidsMap {
+		snb.add(seat, nonBids...)
+	}
+}
+
+func noBidReason(errs []error) openrtb_ext.NonBidStatusCode {
+	fatalErrs := errortypes.FatalOnly(errs)
+	for _, err := range fatalErrs {
+		if errortypes.ReadCode(err) == errortypes.TimeoutErrorCode {
+			return openrtb_ext.ErrorTimeout
+		}
+	}
+	if len(fatalErrs) > 0 {
+		return openrtb_ext.ErrorGeneral
+	}
+	return openrtb_ext.NoBid
+}
+
+// get returns the non-bids collected so far, grouped by seat and sorted by seat name
+func (snb *nonBids) get() []openrtb_ext.SeatNonBid {
+	if snb == nil || len(snb.seatNonBidsMap) == 0 {
+		return nil
+	}
+	seatNonBids := make([]openrtb_ext.SeatNonBid, 0, len(snb.seatNonBidsMap))
+	for seat, nonBids := range snb.seatNonBidsMap {
+		seatNonBids = append(seatNonBids, openrtb_ext.SeatNonBid{Seat: seat, NonBid: nonBids})
+	}
+	sort.Slice(seatNonBids, func(i, j int) bool {
+		return seatNonBids[i].Seat < seatNonBids[j].Seat
+	})
+	return seatNonBids
+}
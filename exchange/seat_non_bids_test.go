@@ -0,0 +1,102 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeatNonBidsAddSeatBids(t *testing.T) {
+	bidderRequest := BidderRequest{
+		BidderName: "appnexus",
+		BidRequest: &openrtb2.BidRequest{
+			Imp: []openrtb2.Imp{{ID: "imp1"}, {ID: "imp2"}, {ID: "imp3"}},
+		},
+	}
+	invalidBid := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp2", Price: 1}}
+
+	testCases := []struct {
+		name     string
+		seatBids []*pbsOrtbSeatBid
+		errs     []error
+		expected []openrtb_ext.SeatNonBid
+	}{
+		{
+			name: "no-bid",
+			expected: []openrtb_ext.SeatNonBid{
+				{Seat: "appnexus", NonBid: []openrtb_ext.NonBid{
+					{ImpId: "imp1", StatusCode: openrtb_ext.NoBid},
+					{ImpId: "imp2", StatusCode: openrtb_ext.NoBid},
+					{ImpId: "imp3", StatusCode: openrtb_ext.NoBid},
+				}},
+			},
+		},
+		{
+			name: "timeout",
+			seatBids: []*pbsOrtbSeatBid{
+				{seat: "appnexus", bids: []*pbsOrtbBid{{bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1"}}}},
+			},
+			errs: []error{&errortypes.Warning{Message: "warning"}, &errortypes.Timeout{Message: "timeout"}},
+			expected: []openrtb_ext.SeatNonBid{
+				{Seat: "appnexus", NonBid: []openrtb_ext.NonBid{
+					{ImpId: "imp2", StatusCode: openrtb_ext.ErrorTimeout},
+					{ImpId: "imp3", StatusCode: openrtb_ext.ErrorTimeout},
+				}},
+			},
+		},
+		{
+			name: "rejected-bids-and-error",
+			seatBids: []*pbsOrtbSeatBid{
+				{seat: "appnexus", bids: []*pbsOrtbBid{{bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1"}}}},
+				{seat: "groupm", nonBids: []openrtb_ext.NonBid{newNonBid(invalidBid, openrtb_ext.ErrorInvalidBidResponse)}},
+			},
+			errs: []error{errors.New("invalid bid")},
+			expected: []openrtb_ext.SeatNonBid{
+				{Seat: "appnexus", NonBid: []openrtb_ext.NonBid{
+					{ImpId: "imp3", StatusCode: openrtb_ext.ErrorGeneral},
+				}},
+				{Seat: "groupm", NonBid: []openrtb_ext.NonBid{
+					newNonBid(invalidBid, openrtb_ext.ErrorInvalidBidResponse),
+				}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		var seatNonBids nonBids
+		seatNonBids.addSeatBids(bidderRequest, test.seatBids, test.errs)
+		assert.Equal(t, test.expected, seatNonBids.get(), test.name)
+	}
+}
+
+func TestSeatNonBidsAddBid(t *testing.T) {
+	var seatNonBids nonBids
+	seatNonBids.addBid(&pbsOrtbBid{bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", Price: 0.5, DealID: "deal1", ADomain: []string{"a.com"}}, originalBidCPM: 0.6, originalBidCur: "EUR"}, openrtb_ext.ResponseRejectedBelowDealFloor, "rubicon")
+	seatNonBids.addBid(&pbsOrtbBid{bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp1", Price: 2}}, openrtb_ext.ResponseRejectedDuplicate, "appnexus")
+	seatNonBids.addBid(nil, openrtb_ext.ResponseRejectedGeneral, "appnexus")
+	seatNonBids.addBid(&pbsOrtbBid{}, openrtb_ext.ResponseRejectedGeneral, "appnexus")
+
+	var other nonBids
+	other.addImp("imp2", openrtb_ext.NoBid, "appnexus")
+	seatNonBids.append(other)
+
+	expected := []openrtb_ext.SeatNonBid{
+		{Seat: "appnexus", NonBid: []openrtb_ext.NonBid{
+			{ImpId: "imp1", StatusCode: openrtb_ext.ResponseRejectedDuplicate, Ext: &openrtb_ext.NonBidExt{
+				Prebid: openrtb_ext.ExtResponseNonBidPrebid{Bid: openrtb_ext.NonBidObject{ID: "bid2", Price: 2}},
+			}},
+			{ImpId: "imp2", StatusCode: openrtb_ext.NoBid},
+		}},
+		{Seat: "rubicon", NonBid: []openrtb_ext.NonBid{
+			{ImpId: "imp1", StatusCode: openrtb_ext.ResponseRejectedBelowDealFloor, Ext: &openrtb_ext.NonBidExt{
+				Prebid: openrtb_ext.ExtResponseNonBidPrebid{Bid: openrtb_ext.NonBidObject{ID: "bid1", Price: 0.5, DealID: "deal1", ADomain: []string{"a.com"}, OriginalBidCPM: 0.6, OriginalBidCur: "EUR"}},
+			}},
+		}},
+	}
+	assert.Equal(t, expected, seatNonBids.get())
+	assert.Nil(t, (&nonBids{}).get())
+}
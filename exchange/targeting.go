@@ -1,9 +1,11 @@
 package exchange
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/openrtb_ext"
 )
 
@@ -17,67 +19,135 @@ const MaxKeyLength = 20
 // All functions on this struct are all nil-safe.
 // If the value is nil, then no targeting data will be tracked.
 type targetData struct {
-	priceGranularity  openrtb_ext.PriceGranularity
-	includeWinners    bool
-	includeBidderKeys bool
-	includeCacheBids  bool
-	includeCacheVast  bool
-	includeFormat     bool
-	preferDeals       bool
+	priceGranularity          openrtb_ext.PriceGranularity
+	mediaTypePriceGranularity *openrtb_ext.MediaTypePriceGranularity
+	includeWinners            bool
+	includeBidderKeys         bool
+	includeCacheBids          bool
+	includeCacheVast          bool
+	includeFormat             bool
+	preferDeals               bool
 	// cacheHost and cachePath exist to supply cache host and path as targeting parameters
 	cacheHost string
 	cachePath string
 }
 
+// applyAccountTargeting sets the account targeting on the requests which omit ext.prebid.targeting when the account
+// turns targeting on, and the account media type price granularity on the requests whose targeting doesn't set one.
+func applyAccountTargeting(req *openrtb_ext.RequestWrapper, accountTargeting config.AccountTargeting) error {
+	requestExt, err := req.GetRequestExt()
+	if err != nil {
+		return err
+	}
+
+	prebid := requestExt.GetPrebid()
+	switch {
+	case prebid == nil || prebid.Targeting == nil:
+		if !accountTargeting.Enabled() {
+			return nil
+		}
+		if prebid == nil {
+			prebid = &openrtb_ext.ExtRequestPrebid{}
+		}
+		prebid.Targeting = accountTargeting.RequestTargeting()
+	case prebid.Targeting.MediaTypePriceGranularity == nil && accountTargeting.MediaTypePriceGranularity != nil:
+		// the targeting is copied since it may be shared with the incoming request
+		targeting := *prebid.Targeting
+		targeting.MediaTypePriceGranularity = accountTargeting.MediaTypePriceGranularity
+		prebid.Targeting = &targeting
+	default:
+		return nil
+	}
+
+	requestExt.SetPrebid(prebid)
+	return nil
+}
+
+// priceGranularityFor returns the price granularity of the media type, which defaults to the price granularity
+// of all bids
+func (targData *targetData) priceGranularityFor(bidType openrtb_ext.BidType) openrtb_ext.PriceGranularity {
+	if mediaTypes := targData.mediaTypePriceGranularity; mediaTypes != nil {
+		var priceGranularity *openrtb_ext.PriceGranularity
+		switch bidType {
+		case openrtb_ext.BidTypeBanner:
+			priceGranularity = mediaTypes.Banner
+		case openrtb_ext.BidTypeVideo:
+			priceGranularity = mediaTypes.Video
+		case openrtb_ext.BidTypeNative:
+			priceGranularity = mediaTypes.Native
+		}
+		if priceGranularity != nil {
+			return *priceGranularity
+		}
+	}
+	return targData.priceGranularity
+}
+
 // setTargeting writes all the targeting params into the bids.
 // If any errors occur when setting the targeting params for a particular bid, then that bid will be ejected from the auction.
 //
 // The one exception is the `hb_cache_id` key. Since our APIs explicitly document cache keys to be on a "best effort" basis,
 // it's ok if those stay in the auction. For now, this method implements a very naive cache strategy.
 // In the future, we should implement a more clever retry & backoff strategy to balance the success rate & performance.
-func (targData *targetData) setTargeting(auc *auction, isApp bool, categoryMapping map[string]string, truncateTargetAttr *int) {
+func (targData *targetData) setTargeting(auc *auction, isApp bool, categoryMapping map[string]string, truncateTargetAttr *int, multiBidMap map[string]openrtb_ext.ExtMultiBid) {
 	for impId, topBidsPerImp := range auc.winningBidsByBidder {
 		overallWinner := auc.winningBids[impId]
-		for bidderName, topBidPerBidder := range topBidsPerImp {
-			isOverallWinner := overallWinner == topBidPerBidder
+		for originalBidderName, topBidsPerBidder := range topBidsPerImp {
+			bidderCodePrefix, maxBids := getMultiBidMeta(multiBidMap, originalBidderName.String())
 
-			targets := make(map[string]string, 10)
-			if cpm, ok := auc.roundedPrices[topBidPerBidder]; ok {
-				targData.addKeys(targets, openrtb_ext.HbpbConstantKey, cpm, bidderName, isOverallWinner, truncateTargetAttr)
-			}
-			targData.addKeys(targets, openrtb_ext.HbBidderConstantKey, string(bidderName), bidderName, isOverallWinner, truncateTargetAttr)
-			if hbSize := makeHbSize(topBidPerBidder.bid); hbSize != "" {
-				targData.addKeys(targets, openrtb_ext.HbSizeConstantKey, hbSize, bidderName, isOverallWinner, truncateTargetAttr)
-			}
-			if cacheID, ok := auc.cacheIds[topBidPerBidder.bid]; ok {
-				targData.addKeys(targets, openrtb_ext.HbCacheKey, cacheID, bidderName, isOverallWinner, truncateTargetAttr)
-			}
-			if vastID, ok := auc.vastCacheIds[topBidPerBidder.bid]; ok {
-				targData.addKeys(targets, openrtb_ext.HbVastCacheKey, vastID, bidderName, isOverallWinner, truncateTargetAttr)
-			}
-			if targData.includeFormat {
-				targData.addKeys(targets, openrtb_ext.HbFormatKey, string(topBidPerBidder.bidType), bidderName, isOverallWinner, truncateTargetAttr)
-			}
+			for i, topBidPerBidder := range topBidsPerBidder {
+				// the bidder's best bid is targeted with the bidder name, the following ones with the multibid prefix
+				bidderName := originalBidderName
+				if i > 0 {
+					if bidderCodePrefix == "" || i >= maxBids {
+						break
+					}
+					bidderName = openrtb_ext.BidderName(fmt.Sprintf("%s%d", bidderCodePrefix, i+1))
+				}
+				if maxBids > openrtb_ext.DefaultBidLimit {
+					topBidPerBidder.targetBidderCode = bidderName.String()
+				}
 
-			if targData.cacheHost != "" {
-				targData.addKeys(targets, openrtb_ext.HbConstantCacheHostKey, targData.cacheHost, bidderName, isOverallWinner, truncateTargetAttr)
-			}
-			if targData.cachePath != "" {
-				targData.addKeys(targets, openrtb_ext.HbConstantCachePathKey, targData.cachePath, bidderName, isOverallWinner, truncateTargetAttr)
-			}
+				isOverallWinner := overallWinner == topBidPerBidder
 
-			if deal := topBidPerBidder.bid.DealID; len(deal) > 0 {
-				targData.addKeys(targets, openrtb_ext.HbDealIDConstantKey, deal, bidderName, isOverallWinner, truncateTargetAttr)
-			}
+				targets := make(map[string]string, 10)
+				if cpm, ok := auc.roundedPrices[topBidPerBidder]; ok {
+					targData.addKeys(targets, openrtb_ext.HbpbConstantKey, cpm, bidderName, isOverallWinner, truncateTargetAttr)
+				}
+				targData.addKeys(targets, openrtb_ext.HbBidderConstantKey, string(bidderName), bidderName, isOverallWinner, truncateTargetAttr)
+				if hbSize := makeHbSize(topBidPerBidder.bid); hbSize != "" {
+					targData.addKeys(targets, openrtb_ext.HbSizeConstantKey, hbSize, bidderName, isOverallWinner, truncateTargetAttr)
+				}
+				if cacheID, ok := auc.cacheIds[topBidPerBidder.bid]; ok {
+					targData.addKeys(targets, openrtb_ext.HbCacheKey, cacheID, bidderName, isOverallWinner, truncateTargetAttr)
+				}
+				if vastID, ok := auc.vastCacheIds[topBidPerBidder.bid]; ok {
+					targData.addKeys(targets, openrtb_ext.HbVastCacheKey, vastID, bidderName, isOverallWinner, truncateTargetAttr)
+				}
+				if targData.includeFormat {
+					targData.addKeys(targets, openrtb_ext.HbFormatKey, string(topBidPerBidder.bidType), bidderName, isOverallWinner, truncateTargetAttr)
+				}
 
-			if isApp {
-				targData.addKeys(targets, openrtb_ext.HbEnvKey, openrtb_ext.HbEnvKeyApp, bidderName, isOverallWinner, truncateTargetAttr)
-			}
-			if len(categoryMapping) > 0 {
-				targData.addKeys(targets, openrtb_ext.HbCategoryDurationKey, categoryMapping[topBidPerBidder.bid.ID], bidderName, isOverallWinner, truncateTargetAttr)
-			}
+				if targData.cacheHost != "" {
+					targData.addKeys(targets, openrtb_ext.HbConstantCacheHostKey, targData.cacheHost, bidderName, isOverallWinner, truncateTargetAttr)
+				}
+				if targData.cachePath != "" {
+					targData.addKeys(targets, openrtb_ext.HbConstantCachePathKey, targData.cachePath, bidderName, isOverallWinner, truncateTargetAttr)
+				}
 
-			topBidPerBidder.bidTargets = targets
+				if deal := topBidPerBidder.bid.DealID; len(deal) > 0 {
+					targData.addKeys(targets, openrtb_ext.HbDealIDConstantKey, deal, bidderName, isOverallWinner, truncateTargetAttr)
+				}
+
+				if isApp {
+					targData.addKeys(targets, openrtb_ext.HbEnvKey, openrtb_ext.HbEnvKeyApp, bidderName, isOverallWinner, truncateTargetAttr)
+				}
+				if len(categoryMapping) > 0 {
+					targData.addKeys(targets, openrtb_ext.HbCategoryDurationKey, categoryMapping[topBidPerBidder.bid.ID], bidderName, isOverallWinner, truncateTargetAttr)
+				}
+
+				topBidPerBidder.bidTargets = targets
+			}
 		}
 	}
 }
@@ -292,16 +292,16 @@ var TargetingTests []TargetingTestData = []TargetingTestData{
 			includeWinners:   true,
 		},
 		Auction: auction{
-			winningBidsByBidder: map[string]map[openrtb_ext.BidderName]*pbsOrtbBid{
+			winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
 				"ImpId-1": {
-					openrtb_ext.BidderAppnexus: {
+					openrtb_ext.BidderAppnexus: {{
 						bid:     bid123,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
-					openrtb_ext.BidderRubicon: {
+					}},
+					openrtb_ext.BidderRubicon: {{
 						bid:     bid084,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
+					}},
 				},
 			},
 		},
@@ -323,16 +323,16 @@ var TargetingTests []TargetingTestData = []TargetingTestData{
 			includeBidderKeys: true,
 		},
 		Auction: auction{
-			winningBidsByBidder: map[string]map[openrtb_ext.BidderName]*pbsOrtbBid{
+			winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
 				"ImpId-1": {
-					openrtb_ext.BidderAppnexus: {
+					openrtb_ext.BidderAppnexus: {{
 						bid:     bid123,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
-					openrtb_ext.BidderRubicon: {
+					}},
+					openrtb_ext.BidderRubicon: {{
 						bid:     bid084,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
+					}},
 				},
 			},
 		},
@@ -359,16 +359,16 @@ var TargetingTests []TargetingTestData = []TargetingTestData{
 			includeFormat:     true,
 		},
 		Auction: auction{
-			winningBidsByBidder: map[string]map[openrtb_ext.BidderName]*pbsOrtbBid{
+			winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
 				"ImpId-1": {
-					openrtb_ext.BidderAppnexus: {
+					openrtb_ext.BidderAppnexus: {{
 						bid:     bid123,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
-					openrtb_ext.BidderRubicon: {
+					}},
+					openrtb_ext.BidderRubicon: {{
 						bid:     bid084,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
+					}},
 				},
 			},
 		},
@@ -400,16 +400,16 @@ var TargetingTests []TargetingTestData = []TargetingTestData{
 			cachePath:         "cache",
 		},
 		Auction: auction{
-			winningBidsByBidder: map[string]map[openrtb_ext.BidderName]*pbsOrtbBid{
+			winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
 				"ImpId-1": {
-					openrtb_ext.BidderAppnexus: {
+					openrtb_ext.BidderAppnexus: {{
 						bid:     bid123,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
-					openrtb_ext.BidderRubicon: {
+					}},
+					openrtb_ext.BidderRubicon: {{
 						bid:     bid111,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
+					}},
 				},
 			},
 			cacheIds: map[*openrtb2.Bid]string{
@@ -445,16 +445,16 @@ var TargetingTests []TargetingTestData = []TargetingTestData{
 			includeBidderKeys: true,
 		},
 		Auction: auction{
-			winningBidsByBidder: map[string]map[openrtb_ext.BidderName]*pbsOrtbBid{
+			winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
 				"ImpId-1": {
-					openrtb_ext.BidderAppnexus: {
+					openrtb_ext.BidderAppnexus: {{
 						bid:     bid123,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
-					openrtb_ext.BidderRubicon: {
+					}},
+					openrtb_ext.BidderRubicon: {{
 						bid:     bid084,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
+					}},
 				},
 			},
 		},
@@ -479,16 +479,16 @@ var TargetingTests []TargetingTestData = []TargetingTestData{
 			includeBidderKeys: true,
 		},
 		Auction: auction{
-			winningBidsByBidder: map[string]map[openrtb_ext.BidderName]*pbsOrtbBid{
+			winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
 				"ImpId-1": {
-					openrtb_ext.BidderAppnexus: {
+					openrtb_ext.BidderAppnexus: {{
 						bid:     bid123,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
-					openrtb_ext.BidderRubicon: {
+					}},
+					openrtb_ext.BidderRubicon: {{
 						bid:     bid084,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
+					}},
 				},
 			},
 		},
@@ -513,16 +513,16 @@ var TargetingTests []TargetingTestData = []TargetingTestData{
 			includeBidderKeys: true,
 		},
 		Auction: auction{
-			winningBidsByBidder: map[string]map[openrtb_ext.BidderName]*pbsOrtbBid{
+			winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
 				"ImpId-1": {
-					openrtb_ext.BidderAppnexus: {
+					openrtb_ext.BidderAppnexus: {{
 						bid:     bid123,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
-					openrtb_ext.BidderRubicon: {
+					}},
+					openrtb_ext.BidderRubicon: {{
 						bid:     bid084,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
+					}},
 				},
 			},
 		},
@@ -547,16 +547,16 @@ var TargetingTests []TargetingTestData = []TargetingTestData{
 			includeWinners:   true,
 		},
 		Auction: auction{
-			winningBidsByBidder: map[string]map[openrtb_ext.BidderName]*pbsOrtbBid{
+			winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
 				"ImpId-1": {
-					openrtb_ext.BidderAppnexus: {
+					openrtb_ext.BidderAppnexus: {{
 						bid:     bid123,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
-					openrtb_ext.BidderRubicon: {
+					}},
+					openrtb_ext.BidderRubicon: {{
 						bid:     bid084,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
+					}},
 				},
 			},
 		},
@@ -578,16 +578,16 @@ var TargetingTests []TargetingTestData = []TargetingTestData{
 			includeWinners:   true,
 		},
 		Auction: auction{
-			winningBidsByBidder: map[string]map[openrtb_ext.BidderName]*pbsOrtbBid{
+			winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
 				"ImpId-1": {
-					openrtb_ext.BidderAppnexus: {
+					openrtb_ext.BidderAppnexus: {{
 						bid:     bid123,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
-					openrtb_ext.BidderRubicon: {
+					}},
+					openrtb_ext.BidderRubicon: {{
 						bid:     bid084,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
+					}},
 				},
 			},
 		},
@@ -609,16 +609,16 @@ var TargetingTests []TargetingTestData = []TargetingTestData{
 			includeWinners:   true,
 		},
 		Auction: auction{
-			winningBidsByBidder: map[string]map[openrtb_ext.BidderName]*pbsOrtbBid{
+			winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
 				"ImpId-1": {
-					openrtb_ext.BidderAppnexus: {
+					openrtb_ext.BidderAppnexus: {{
 						bid:     bid123,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
-					openrtb_ext.BidderRubicon: {
+					}},
+					openrtb_ext.BidderRubicon: {{
 						bid:     bid084,
 						bidType: openrtb_ext.BidTypeBanner,
-					},
+					}},
 				},
 			},
 		},
@@ -639,28 +639,30 @@ func TestSetTargeting(t *testing.T) {
 	for _, test := range TargetingTests {
 		auc := &test.Auction
 		// Set rounded prices from the auction data
-		auc.setRoundedPrices(test.TargetData.priceGranularity)
+		auc.setRoundedPrices(&test.TargetData)
 		winningBids := make(map[string]*pbsOrtbBid)
 		// Set winning bids from the auction data
 		for imp, bidsByBidder := range auc.winningBidsByBidder {
-			for _, bid := range bidsByBidder {
-				if winningBid, ok := winningBids[imp]; ok {
-					if winningBid.bid.Price < bid.bid.Price {
+			for _, bids := range bidsByBidder {
+				for _, bid := range bids {
+					if winningBid, ok := winningBids[imp]; ok {
+						if winningBid.bid.Price < bid.bid.Price {
+							winningBids[imp] = bid
+						}
+					} else {
 						winningBids[imp] = bid
 					}
-				} else {
-					winningBids[imp] = bid
 				}
 			}
 		}
 		auc.winningBids = winningBids
 		targData := test.TargetData
-		targData.setTargeting(auc, test.IsApp, test.CategoryMapping, test.TruncateTargetAttr)
+		targData.setTargeting(auc, test.IsApp, test.CategoryMapping, test.TruncateTargetAttr, nil)
 		for imp, targetsByBidder := range test.ExpectedBidTargetsByBidder {
 			for bidder, expected := range targetsByBidder {
 				assert.Equal(t,
 					expected,
-					auc.winningBidsByBidder[imp][bidder].bidTargets,
+					auc.winningBidsByBidder[imp][bidder][0].bidTargets,
 					"Test: %s\nTargeting failed for bidder %s on imp %s.",
 					test.Description,
 					string(bidder),
@@ -670,3 +672,165 @@ func TestSetTargeting(t *testing.T) {
 	}
 
 }
+
+func TestSetTargetingMultiBid(t *testing.T) {
+	maxBids2, maxBids3 := 2, 3
+	appnexusTop := &pbsOrtbBid{bid: bid123, bidType: openrtb_ext.BidTypeBanner}
+	appnexusSecond := &pbsOrtbBid{bid: bid111, bidType: openrtb_ext.BidTypeBanner}
+	rubiconTop := &pbsOrtbBid{bid: bid084, bidType: openrtb_ext.BidTypeBanner}
+
+	auc := &auction{
+		winningBids: map[string]*pbsOrtbBid{
+			"ImpId-1": appnexusTop,
+		},
+		winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
+			"ImpId-1": {
+				openrtb_ext.BidderAppnexus: {appnexusTop, appnexusSecond},
+				openrtb_ext.BidderRubicon:  {rubiconTop},
+			},
+		},
+	}
+	multiBidMap := map[string]openrtb_ext.ExtMultiBid{
+		"appnexus": {Bidder: "appnexus", MaxBids: &maxBids3, TargetBidderCodePrefix: "apn"},
+		"rubicon":  {Bidder: "rubicon", MaxBids: &maxBids2},
+	}
+
+	targData := &targetData{
+		priceGranularity:  openrtb_ext.PriceGranularityFromString("med"),
+		includeWinners:    true,
+		includeBidderKeys: true,
+	}
+	auc.setRoundedPrices(targData)
+	targData.setTargeting(auc, false, nil, nil, multiBidMap)
+
+	assert.Equal(t, map[string]string{
+		"hb_bidder":          "appnexus",
+		"hb_bidder_appnexus": "appnexus",
+		"hb_pb":              "1.20",
+		"hb_pb_appnexus":     "1.20",
+	}, appnexusTop.bidTargets)
+	assert.Equal(t, "appnexus", appnexusTop.targetBidderCode)
+
+	assert.Equal(t, map[string]string{
+		"hb_bidder_apn2": "apn2",
+		"hb_deal_apn2":   "mydeal",
+		"hb_pb_apn2":     "1.10",
+	}, appnexusSecond.bidTargets)
+	assert.Equal(t, "apn2", appnexusSecond.targetBidderCode)
+
+	assert.Equal(t, map[string]string{
+		"hb_bidder_rubicon": "rubicon",
+		"hb_pb_rubicon":     "0.80",
+	}, rubiconTop.bidTargets)
+	assert.Equal(t, "rubicon", rubiconTop.targetBidderCode)
+}
+
+func TestPriceGranularityFor(t *testing.T) {
+	videoGranularity := openrtb_ext.PriceGranularityFromString("high")
+	targData := &targetData{
+		priceGranularity:          openrtb_ext.PriceGranularityFromString("med"),
+		mediaTypePriceGranularity: &openrtb_ext.MediaTypePriceGranularity{Video: &videoGranularity},
+	}
+
+	assert.Equal(t, videoGranularity, targData.priceGranularityFor(openrtb_ext.BidTypeVideo), "video")
+	assert.Equal(t, openrtb_ext.PriceGranularityFromString("med"), targData.priceGranularityFor(openrtb_ext.BidTypeBanner), "banner")
+	assert.Equal(t, openrtb_ext.PriceGranularityFromString("med"), (&targetData{priceGranularity: openrtb_ext.PriceGranularityFromString("med")}).priceGranularityFor(openrtb_ext.BidTypeVideo), "no media type price granularity")
+
+	auc := &auction{
+		winningBidsByBidder: map[string]map[openrtb_ext.BidderName][]*pbsOrtbBid{
+			"imp": {
+				openrtb_ext.BidderAppnexus: {{bid: &openrtb2.Bid{Price: 1.234}, bidType: openrtb_ext.BidTypeVideo}},
+				openrtb_ext.BidderRubicon:  {{bid: &openrtb2.Bid{Price: 1.234}, bidType: openrtb_ext.BidTypeBanner}},
+			},
+		},
+	}
+	auc.setRoundedPrices(targData)
+
+	assert.Equal(t, "1.23", auc.roundedPrices[auc.winningBidsByBidder["imp"][openrtb_ext.BidderAppnexus][0]], "video bid")
+	assert.Equal(t, "1.20", auc.roundedPrices[auc.winningBidsByBidder["imp"][openrtb_ext.BidderRubicon][0]], "banner bid")
+}
+
+func TestApplyAccountTargeting(t *testing.T) {
+	includeWinners := true
+	videoGranularity := openrtb_ext.PriceGranularityFromString("high")
+	bannerGranularity := openrtb_ext.PriceGranularityFromString("low")
+	mediaTypePriceGranularity := &openrtb_ext.MediaTypePriceGranularity{Video: &videoGranularity}
+
+	testCases := []struct {
+		description       string
+		requestExt        json.RawMessage
+		accountTargeting  config.AccountTargeting
+		expectedTargeting *openrtb_ext.ExtRequestTargeting
+	}{
+		{
+			description: "Request without targeting, account doesn't set targeting",
+			requestExt:  json.RawMessage(`{"prebid":{}}`),
+		},
+		{
+			description:      "Request without targeting, account sets only the price granularity",
+			requestExt:       json.RawMessage(`{"prebid":{}}`),
+			accountTargeting: config.AccountTargeting{PriceGranularity: &bannerGranularity},
+			expectedTargeting: &openrtb_ext.ExtRequestTargeting{
+				PriceGranularity:  bannerGranularity,
+				IncludeWinners:    true,
+				IncludeBidderKeys: true,
+			},
+		},
+		{
+			description:      "Request without targeting, account sets only the media type price granularity",
+			requestExt:       json.RawMessage(`{"prebid":{}}`),
+			accountTargeting: config.AccountTargeting{MediaTypePriceGranularity: mediaTypePriceGranularity},
+			expectedTargeting: &openrtb_ext.ExtRequestTargeting{
+				PriceGranularity:          openrtb_ext.PriceGranularityFromString("med"),
+				MediaTypePriceGranularity: mediaTypePriceGranularity,
+				IncludeWinners:            true,
+				IncludeBidderKeys:         true,
+			},
+		},
+		{
+			description:      "Request without targeting, account enables targeting",
+			accountTargeting: config.AccountTargeting{IncludeWinners: &includeWinners, MediaTypePriceGranularity: mediaTypePriceGranularity},
+			expectedTargeting: &openrtb_ext.ExtRequestTargeting{
+				PriceGranularity:          openrtb_ext.PriceGranularityFromString("med"),
+				MediaTypePriceGranularity: mediaTypePriceGranularity,
+				IncludeWinners:            true,
+				IncludeBidderKeys:         true,
+			},
+		},
+		{
+			description:      "Request targeting without media type price granularity",
+			requestExt:       json.RawMessage(`{"prebid":{"targeting":{"pricegranularity":"low","includeformat":true}}}`),
+			accountTargeting: config.AccountTargeting{MediaTypePriceGranularity: mediaTypePriceGranularity},
+			expectedTargeting: &openrtb_ext.ExtRequestTargeting{
+				PriceGranularity:          openrtb_ext.PriceGranularityFromString("low"),
+				MediaTypePriceGranularity: mediaTypePriceGranularity,
+				IncludeWinners:            true,
+				IncludeBidderKeys:         true,
+				IncludeFormat:             true,
+			},
+		},
+		{
+			description:      "Request media type price granularity takes precedence",
+			requestExt:       json.RawMessage(`{"prebid":{"targeting":{"mediatypepricegranularity":{"banner":"low"}}}}`),
+			accountTargeting: config.AccountTargeting{IncludeWinners: &includeWinners, MediaTypePriceGranularity: mediaTypePriceGranularity},
+			expectedTargeting: &openrtb_ext.ExtRequestTargeting{
+				PriceGranularity:          openrtb_ext.PriceGranularityFromString("med"),
+				MediaTypePriceGranularity: &openrtb_ext.MediaTypePriceGranularity{Banner: &bannerGranularity},
+				IncludeWinners:            true,
+				IncludeBidderKeys:         true,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Ext: test.requestExt}}
+
+		err := applyAccountTargeting(req, test.accountTargeting)
+		assert.NoError(t, err, test.description)
+		assert.NoError(t, req.RebuildRequest(), test.description)
+
+		requestExt, err := extractBidRequestExt(req.BidRequest)
+		assert.NoError(t, err, test.description)
+		assert.Equal(t, test.expectedTargeting, requestExt.Prebid.Targeting, test.description)
+	}
+}
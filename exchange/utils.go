@@ -14,12 +14,14 @@ import (
 
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/firstpartydata"
 	"github.com/prebid/prebid-server/gdpr"
 	"github.com/prebid/prebid-server/metrics"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/prebid/prebid-server/privacy"
 	"github.com/prebid/prebid-server/privacy/ccpa"
+	gppPrivacy "github.com/prebid/prebid-server/privacy/gpp"
 	"github.com/prebid/prebid-server/privacy/lmt"
 	"github.com/prebid/prebid-server/schain"
 	"github.com/prebid/prebid-server/stored_responses"
@@ -49,6 +51,7 @@ func cleanOpenRTBRequests(ctx context.Context,
 	gdprPermsBuilder gdpr.PermissionsBuilder,
 	tcf2ConfigBuilder gdpr.TCF2ConfigBuilder,
 	hostSChainNode *openrtb2.SupplyChainNode,
+	bidderInfo config.BidderInfos,
 ) (allowedBidderRequests []BidderRequest, privacyLabels metrics.PrivacyLabels, errs []error) {
 
 	req := auctionReq.BidRequestWrapper
@@ -73,7 +76,7 @@ func cleanOpenRTBRequests(ctx context.Context,
 	}
 
 	var allBidderRequests []BidderRequest
-	allBidderRequests, errs = getAuctionBidderRequests(auctionReq, requestExt, bidderToSyncerKey, impsByBidder, aliases, hostSChainNode)
+	allBidderRequests, errs = getAuctionBidderRequests(auctionReq, requestExt, bidderToSyncerKey, impsByBidder, aliases, hostSChainNode, bidderInfo)
 
 	bidderNameToBidderReq := buildBidResponseRequest(req.BidRequest, bidderImpWithBidResp, aliases, auctionReq.BidderImpReplaceImpID)
 	//this function should be executed after getAuctionBidderRequests
@@ -105,10 +108,18 @@ func cleanOpenRTBRequests(ctx context.Context,
 
 	lmtEnforcer := extractLMT(req.BidRequest, privacyConfig)
 
+	gppPolicy, err := extractGPP(req.BidRequest, gpp)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
 	// request level privacy policies
 	privacyEnforcement := privacy.Enforcement{
-		COPPA: req.BidRequest.Regs != nil && req.BidRequest.Regs.COPPA == 1,
-		LMT:   lmtEnforcer.ShouldEnforce(unknownBidder),
+		COPPA:    req.BidRequest.Regs != nil && req.BidRequest.Regs.COPPA == 1,
+		LMT:      lmtEnforcer.ShouldEnforce(unknownBidder),
+		GPPID:    gppEnforcer(gppPolicy, gppPrivacy.RestrictionSaleOrSharing, privacyConfig).ShouldEnforce(unknownBidder),
+		GPPGeo:   gppEnforcer(gppPolicy, gppPrivacy.RestrictionSensitiveData, privacyConfig).ShouldEnforce(unknownBidder),
+		GPPChild: gppEnforcer(gppPolicy, gppPrivacy.RestrictionKnownChild, privacyConfig).ShouldEnforce(unknownBidder),
 	}
 
 	privacyLabels.CCPAProvided = ccpaEnforcer.CanEnforce()
@@ -116,6 +127,9 @@ func cleanOpenRTBRequests(ctx context.Context,
 	privacyLabels.COPPAEnforced = privacyEnforcement.COPPA
 	privacyLabels.LMTEnforced = lmtEnforcer.ShouldEnforce(unknownBidder)
 
+	activityControl := privacy.NewActivityControl(auctionReq.Account.Activities)
+	activityRequest := privacy.NewActivityRequest(req.BidRequest)
+
 	tcf2Cfg := tcf2ConfigBuilder(privacyConfig.GDPR.TCF2, auctionReq.Account.GDPR)
 
 	var gdprEnforced bool
@@ -146,6 +160,18 @@ func cleanOpenRTBRequests(ctx context.Context,
 	for _, bidderRequest := range allBidderRequests {
 		bidRequestAllowed := true
 
+		// account activity controls
+		component := privacy.Component{Type: config.ComponentTypeBidder, Name: bidderRequest.BidderName.String()}
+		if !activityControl.Allow(privacy.ActivityFetchBids, component, activityRequest) {
+			errs = append(errs, &errortypes.Warning{
+				Message:     fmt.Sprintf("%s request skipped: fetching bids is not allowed by the account activity controls", bidderRequest.BidderName),
+				WarningCode: errortypes.ActivityBlockedWarningCode,
+			})
+			continue
+		}
+		privacyEnforcement.UFPD = !activityControl.Allow(privacy.ActivityTransmitUserFPD, component, activityRequest)
+		privacyEnforcement.PreciseGeo = !activityControl.Allow(privacy.ActivityTransmitPreciseGeo, component, activityRequest)
+
 		// CCPA
 		privacyEnforcement.CCPA = ccpaEnforcer.ShouldEnforce(bidderRequest.BidderName.String())
 
@@ -167,6 +193,7 @@ func cleanOpenRTBRequests(ctx context.Context,
 			}
 		}
 
+		// the privacy enforcement applies to the first party data as well, so it has to be merged first
 		if auctionReq.FirstPartyData != nil && auctionReq.FirstPartyData[bidderRequest.BidderName] != nil {
 			applyFPD(auctionReq.FirstPartyData[bidderRequest.BidderName], bidderRequest.BidRequest)
 		}
@@ -214,12 +241,28 @@ func extractLMT(orig *openrtb2.BidRequest, privacyConfig config.Privacy) privacy
 	}
 }
 
+// extractGPP reads the US National and US state sections of the GPP string which the request lists as applicable
+func extractGPP(orig *openrtb2.BidRequest, gpp gpplib.GppContainer) (gppPrivacy.ParsedPolicy, error) {
+	if orig.Regs == nil {
+		return gppPrivacy.ParsedPolicy{}, nil
+	}
+	return gppPrivacy.ParseContainer(gpp, orig.Regs.GPPSID)
+}
+
+func gppEnforcer(policy gppPrivacy.ParsedPolicy, restriction gppPrivacy.Restriction, privacyConfig config.Privacy) privacy.PolicyEnforcer {
+	return privacy.EnabledPolicyEnforcer{
+		Enabled:        privacyConfig.GPP.Enforce,
+		PolicyEnforcer: policy.Enforcer(restriction),
+	}
+}
+
 func getAuctionBidderRequests(auctionRequest AuctionRequest,
 	requestExt *openrtb_ext.ExtRequest,
 	bidderToSyncerKey map[string]string,
 	impsByBidder map[string][]openrtb2.Imp,
 	aliases map[string]string,
-	hostSChainNode *openrtb2.SupplyChainNode) ([]BidderRequest, []error) {
+	hostSChainNode *openrtb2.SupplyChainNode,
+	bidderInfo config.BidderInfos) ([]BidderRequest, []error) {
 
 	bidderRequests := make([]BidderRequest, 0, len(impsByBidder))
 	req := auctionRequest.BidRequestWrapper
@@ -257,6 +300,11 @@ func getAuctionBidderRequests(auctionRequest AuctionRequest,
 			continue
 		}
 
+		if err := convertBidderRequestVersion(&reqCopy, bidderInfo[string(coreBidder)]); err != nil {
+			errs = append(errs, fmt.Errorf("unable to convert request for bidder %s to its OpenRTB version because %v", bidder, err))
+			continue
+		}
+
 		bidderRequest := BidderRequest{
 			BidderName:     openrtb_ext.BidderName(bidder),
 			BidderCoreName: coreBidder,
@@ -283,6 +331,37 @@ func getAuctionBidderRequests(auctionRequest AuctionRequest,
 	return bidderRequests, errs
 }
 
+// convertBidderRequestVersion moves the fields which have a native location in OpenRTB 2.6 to the location
+// understood by the bidder. Bidders declaring OpenRTB 2.6 support receive regs.gdpr, user.consent, source.schain,
+// imp.rwdd and user.eids, all other bidders receive their OpenRTB 2.5 extension counterparts.
+func convertBidderRequestVersion(req *openrtb2.BidRequest, bidderInfo config.BidderInfo) error {
+	// the conversion modifies objects which are shared with the incoming request and the other bidder requests
+	if req.Regs != nil {
+		regsCopy := *req.Regs
+		req.Regs = &regsCopy
+	}
+	if req.User != nil {
+		userCopy := *req.User
+		req.User = &userCopy
+	}
+	if req.Source != nil {
+		sourceCopy := *req.Source
+		req.Source = &sourceCopy
+	}
+
+	reqWrapper := &openrtb_ext.RequestWrapper{BidRequest: req}
+	var err error
+	if bidderInfo.SupportsOpenRTB26() {
+		err = openrtb_ext.ConvertUpTo26(reqWrapper)
+	} else {
+		err = openrtb_ext.ConvertDownTo25(reqWrapper)
+	}
+	if err != nil {
+		return err
+	}
+	return reqWrapper.RebuildRequest()
+}
+
 func buildRequestExtForBidder(bidder string, requestExt json.RawMessage, requestExtParsed *openrtb_ext.ExtRequest, bidderParamsInReqExt map[string]json.RawMessage, cfgABC *openrtb_ext.ExtAlternateBidderCodes) (json.RawMessage, error) {
 	// Resolve alternatebiddercode for current bidder
 	var reqABC *openrtb_ext.ExtAlternateBidderCodes
@@ -538,10 +617,11 @@ func copyWithBuyerUID(user *openrtb2.User, buyerUID string) *openrtb2.User {
 	return user
 }
 
-// removeUnpermissionedEids modifies the request to remove any request.user.ext.eids not permissions for the specific bidder
+// removeUnpermissionedEids modifies the request to remove any request.user.eids and request.user.ext.eids not
+// permissions for the specific bidder. Both are filtered since the OpenRTB version conversion moves one to the other.
 func removeUnpermissionedEids(request *openrtb2.BidRequest, bidder string, requestExt *openrtb_ext.ExtRequest) error {
 	// ensure request might have eids (as much as we can check before unmarshalling)
-	if request.User == nil || len(request.User.Ext) == 0 {
+	if request.User == nil || (len(request.User.EIDs) == 0 && len(request.User.Ext) == 0) {
 		return nil
 	}
 
@@ -550,6 +630,28 @@ func removeUnpermissionedEids(request *openrtb2.BidRequest, bidder string, reque
 		return nil
 	}
 
+	// translate eid permissions to a map for quick lookup
+	eidRules := make(map[string][]string)
+	for _, p := range requestExt.Prebid.Data.EidPermissions {
+		eidRules[p.Source] = p.Bidders
+	}
+
+	if len(request.User.EIDs) > 0 {
+		eidsAllowed := filterEids(request.User.EIDs, eidRules, bidder)
+		if len(eidsAllowed) != len(request.User.EIDs) {
+			userCopy := *request.User
+			userCopy.EIDs = nil
+			if len(eidsAllowed) > 0 {
+				userCopy.EIDs = eidsAllowed
+			}
+			request.User = &userCopy
+		}
+	}
+
+	if len(request.User.Ext) == 0 {
+		return nil
+	}
+
 	// low level unmarshal to preserve other request.user.ext values. prebid server is non-destructive.
 	var userExt map[string]json.RawMessage
 	if err := json.Unmarshal(request.User.Ext, &userExt); err != nil {
@@ -571,30 +673,7 @@ func removeUnpermissionedEids(request *openrtb2.BidRequest, bidder string, reque
 		return nil
 	}
 
-	// translate eid permissions to a map for quick lookup
-	eidRules := make(map[string][]string)
-	for _, p := range requestExt.Prebid.Data.EidPermissions {
-		eidRules[p.Source] = p.Bidders
-	}
-
-	eidsAllowed := make([]openrtb2.EID, 0, len(eids))
-	for _, eid := range eids {
-		allowed := false
-		if rule, hasRule := eidRules[eid.Source]; hasRule {
-			for _, ruleBidder := range rule {
-				if ruleBidder == "*" || ruleBidder == bidder {
-					allowed = true
-					break
-				}
-			}
-		} else {
-			allowed = true
-		}
-
-		if allowed {
-			eidsAllowed = append(eidsAllowed, eid)
-		}
-	}
+	eidsAllowed := filterEids(eids, eidRules, bidder)
 
 	// exit early if all eids are allowed and nothing needs to be removed
 	if len(eids) == len(eidsAllowed) {
@@ -626,6 +705,30 @@ func removeUnpermissionedEids(request *openrtb2.BidRequest, bidder string, reque
 	return nil
 }
 
+// filterEids returns the eids the bidder is permitted to receive. Eids from a source without a rule are permitted
+// to all bidders.
+func filterEids(eids []openrtb2.EID, eidRules map[string][]string, bidder string) []openrtb2.EID {
+	eidsAllowed := make([]openrtb2.EID, 0, len(eids))
+	for _, eid := range eids {
+		allowed := false
+		if rule, hasRule := eidRules[eid.Source]; hasRule {
+			for _, ruleBidder := range rule {
+				if ruleBidder == "*" || ruleBidder == bidder {
+					allowed = true
+					break
+				}
+			}
+		} else {
+			allowed = true
+		}
+
+		if allowed {
+			eidsAllowed = append(eidsAllowed, eid)
+		}
+	}
+	return eidsAllowed
+}
+
 func setUserExtWithCopy(request *openrtb2.BidRequest, userExtJSON json.RawMessage) {
 	userCopy := *request.User
 	userCopy.Ext = userExtJSON
@@ -738,13 +841,14 @@ func getExtTargetData(requestExt *openrtb_ext.ExtRequest, cacheInstructions *ext
 
 	if requestExt != nil && requestExt.Prebid.Targeting != nil {
 		targData = &targetData{
-			priceGranularity:  requestExt.Prebid.Targeting.PriceGranularity,
-			includeWinners:    requestExt.Prebid.Targeting.IncludeWinners,
-			includeBidderKeys: requestExt.Prebid.Targeting.IncludeBidderKeys,
-			includeCacheBids:  cacheInstructions.cacheBids,
-			includeCacheVast:  cacheInstructions.cacheVAST,
-			includeFormat:     requestExt.Prebid.Targeting.IncludeFormat,
-			preferDeals:       requestExt.Prebid.Targeting.PreferDeals,
+			priceGranularity:          requestExt.Prebid.Targeting.PriceGranularity,
+			mediaTypePriceGranularity: requestExt.Prebid.Targeting.MediaTypePriceGranularity,
+			includeWinners:            requestExt.Prebid.Targeting.IncludeWinners,
+			includeBidderKeys:         requestExt.Prebid.Targeting.IncludeBidderKeys,
+			includeCacheBids:          cacheInstructions.cacheBids,
+			includeCacheVast:          cacheInstructions.cacheVAST,
+			includeFormat:             requestExt.Prebid.Targeting.IncludeFormat,
+			preferDeals:               requestExt.Prebid.Targeting.PreferDeals,
 		}
 	}
 	return targData
@@ -785,6 +889,28 @@ func getExtBidAdjustmentFactors(requestExt *openrtb_ext.ExtRequest) map[string]f
 	return bidAdjustmentFactors
 }
 
+// getExtMultiBid indexes the multibid entries of the request by bidder
+func getExtMultiBid(requestExt *openrtb_ext.ExtRequest) map[string]openrtb_ext.ExtMultiBid {
+	if requestExt == nil || len(requestExt.Prebid.MultiBid) == 0 {
+		return nil
+	}
+
+	multiBidMap := make(map[string]openrtb_ext.ExtMultiBid)
+	for _, multiBid := range requestExt.Prebid.MultiBid {
+		if multiBid == nil || multiBid.MaxBids == nil {
+			continue
+		}
+		if multiBid.Bidder != "" {
+			multiBidMap[multiBid.Bidder] = *multiBid
+		} else {
+			for _, bidder := range multiBid.Bidders {
+				multiBidMap[bidder] = openrtb_ext.ExtMultiBid{Bidder: bidder, MaxBids: multiBid.MaxBids}
+			}
+		}
+	}
+	return multiBidMap
+}
+
 func applyFPD(fpd *firstpartydata.ResolvedFirstPartyData, bidReq *openrtb2.BidRequest) {
 	if fpd.Site != nil {
 		bidReq.Site = fpd.Site
@@ -488,7 +488,7 @@ func TestCleanOpenRTBRequests(t *testing.T) {
 			cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
 		}.Builder
 
-		bidderRequests, _, err := cleanOpenRTBRequests(context.Background(), test.req, nil, bidderToSyncerKey, &metricsMock, gdpr.SignalNo, privacyConfig, gdprPermsBuilder, tcf2ConfigBuilder, nil)
+		bidderRequests, _, err := cleanOpenRTBRequests(context.Background(), test.req, nil, bidderToSyncerKey, &metricsMock, gdpr.SignalNo, privacyConfig, gdprPermsBuilder, tcf2ConfigBuilder, nil, config.BidderInfos{})
 		if test.hasError {
 			assert.NotNil(t, err, "Error shouldn't be nil")
 		} else {
@@ -555,7 +555,7 @@ func TestCleanOpenRTBRequestsWithFPD(t *testing.T) {
 			cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
 		}.Builder
 
-		bidderRequests, _, err := cleanOpenRTBRequests(context.Background(), test.req, nil, bidderToSyncerKey, &metricsMock, gdpr.SignalNo, config.Privacy{}, gdprPermissionsBuilder, tcf2ConfigBuilder, nil)
+		bidderRequests, _, err := cleanOpenRTBRequests(context.Background(), test.req, nil, bidderToSyncerKey, &metricsMock, gdpr.SignalNo, config.Privacy{}, gdprPermissionsBuilder, tcf2ConfigBuilder, nil, config.BidderInfos{})
 		assert.Empty(t, err, "No errors should be returned")
 		for _, bidderRequest := range bidderRequests {
 			bidderName := bidderRequest.BidderName
@@ -834,7 +834,8 @@ func TestCleanOpenRTBRequestsWithBidResponses(t *testing.T) {
 			config.Privacy{},
 			gdprPermissionsBuilder,
 			tcf2ConfigBuilder,
-			nil)
+			nil,
+			config.BidderInfos{})
 		assert.Empty(t, err, "No errors should be returned")
 		assert.Len(t, actualBidderRequests, len(test.expectedBidderRequests), "result len doesn't match for testCase %s", test.description)
 		for _, actualBidderRequest := range actualBidderRequests {
@@ -1006,7 +1007,8 @@ func TestCleanOpenRTBRequestsCCPA(t *testing.T) {
 			privacyConfig,
 			gdprPermissionsBuilder,
 			tcf2ConfigBuilder,
-			nil)
+			nil,
+			config.BidderInfos{})
 		result := bidderRequests[0]
 
 		assert.Nil(t, errs)
@@ -1076,7 +1078,7 @@ func TestCleanOpenRTBRequestsCCPAErrors(t *testing.T) {
 		bidderToSyncerKey := map[string]string{}
 		metrics := metrics.MetricsEngineMock{}
 
-		_, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, &reqExtStruct, bidderToSyncerKey, &metrics, gdpr.SignalNo, privacyConfig, gdprPermissionsBuilder, tcf2ConfigBuilder, nil)
+		_, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, &reqExtStruct, bidderToSyncerKey, &metrics, gdpr.SignalNo, privacyConfig, gdprPermissionsBuilder, tcf2ConfigBuilder, nil, config.BidderInfos{})
 
 		assert.ElementsMatch(t, []error{test.expectError}, errs, test.description)
 	}
@@ -1128,7 +1130,7 @@ func TestCleanOpenRTBRequestsCOPPA(t *testing.T) {
 		bidderToSyncerKey := map[string]string{}
 		metrics := metrics.MetricsEngineMock{}
 
-		bidderRequests, privacyLabels, errs := cleanOpenRTBRequests(context.Background(), auctionReq, nil, bidderToSyncerKey, &metrics, gdpr.SignalNo, config.Privacy{}, gdprPermissionsBuilder, tcf2ConfigBuilder, nil)
+		bidderRequests, privacyLabels, errs := cleanOpenRTBRequests(context.Background(), auctionReq, nil, bidderToSyncerKey, &metrics, gdpr.SignalNo, config.Privacy{}, gdprPermissionsBuilder, tcf2ConfigBuilder, nil, config.BidderInfos{})
 		result := bidderRequests[0]
 
 		assert.Nil(t, errs)
@@ -1223,7 +1225,7 @@ func TestCleanOpenRTBRequestsSChain(t *testing.T) {
 
 		bidderToSyncerKey := map[string]string{}
 		metrics := metrics.MetricsEngineMock{}
-		bidderRequests, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, bidderToSyncerKey, &metrics, gdpr.SignalNo, config.Privacy{}, gdprPermissionsBuilder, tcf2ConfigBuilder, nil)
+		bidderRequests, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, bidderToSyncerKey, &metrics, gdpr.SignalNo, config.Privacy{}, gdprPermissionsBuilder, tcf2ConfigBuilder, nil, config.BidderInfos{})
 		if test.hasError == true {
 			assert.NotNil(t, errs)
 			assert.Len(t, bidderRequests, 0)
@@ -1290,7 +1292,7 @@ func TestCleanOpenRTBRequestsBidderParams(t *testing.T) {
 		bidderToSyncerKey := map[string]string{}
 		metrics := metrics.MetricsEngineMock{}
 
-		bidderRequests, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, bidderToSyncerKey, &metrics, gdpr.SignalNo, config.Privacy{}, gdprPermissionsBuilder, tcf2ConfigBuilder, nil)
+		bidderRequests, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, bidderToSyncerKey, &metrics, gdpr.SignalNo, config.Privacy{}, gdprPermissionsBuilder, tcf2ConfigBuilder, nil, config.BidderInfos{})
 		if test.hasError == true {
 			assert.NotNil(t, errs)
 			assert.Len(t, bidderRequests, 0)
@@ -1904,6 +1906,39 @@ func TestGetExtBidAdjustmentFactors(t *testing.T) {
 	}
 }
 
+func TestGetExtMultiBid(t *testing.T) {
+	maxBids2, maxBids3 := 2, 3
+	testCases := []struct {
+		desc             string
+		inRequestExt     *openrtb_ext.ExtRequest
+		expectedMultiBid map[string]openrtb_ext.ExtMultiBid
+	}{
+		{
+			desc:         "Nil request ext",
+			inRequestExt: nil,
+		},
+		{
+			desc:         "No multibid",
+			inRequestExt: &openrtb_ext.ExtRequest{},
+		},
+		{
+			desc: "Bidder and bidders entries",
+			inRequestExt: &openrtb_ext.ExtRequest{Prebid: openrtb_ext.ExtRequestPrebid{MultiBid: []*openrtb_ext.ExtMultiBid{
+				{Bidder: "pubmatic", MaxBids: &maxBids3, TargetBidderCodePrefix: "pm"},
+				{Bidders: []string{"appnexus", "rubicon"}, MaxBids: &maxBids2},
+			}}},
+			expectedMultiBid: map[string]openrtb_ext.ExtMultiBid{
+				"pubmatic": {Bidder: "pubmatic", MaxBids: &maxBids3, TargetBidderCodePrefix: "pm"},
+				"appnexus": {Bidder: "appnexus", MaxBids: &maxBids2},
+				"rubicon":  {Bidder: "rubicon", MaxBids: &maxBids2},
+			},
+		},
+	}
+	for _, test := range testCases {
+		assert.Equal(t, test.expectedMultiBid, getExtMultiBid(test.inRequestExt), test.desc)
+	}
+}
+
 func TestCleanOpenRTBRequestsLMT(t *testing.T) {
 	var (
 		enabled  int8 = 1
@@ -1980,7 +2015,7 @@ func TestCleanOpenRTBRequestsLMT(t *testing.T) {
 
 		bidderToSyncerKey := map[string]string{}
 		metrics := metrics.MetricsEngineMock{}
-		results, privacyLabels, errs := cleanOpenRTBRequests(context.Background(), auctionReq, nil, bidderToSyncerKey, &metrics, gdpr.SignalNo, privacyConfig, gdprPermissionsBuilder, tcf2ConfigBuilder, nil)
+		results, privacyLabels, errs := cleanOpenRTBRequests(context.Background(), auctionReq, nil, bidderToSyncerKey, &metrics, gdpr.SignalNo, privacyConfig, gdprPermissionsBuilder, tcf2ConfigBuilder, nil, config.BidderInfos{})
 		result := results[0]
 
 		assert.Nil(t, errs)
@@ -1995,6 +2030,242 @@ func TestCleanOpenRTBRequestsLMT(t *testing.T) {
 	}
 }
 
+func TestCleanOpenRTBRequestsGPP(t *testing.T) {
+	testCases := []struct {
+		description        string
+		gpp                string
+		gppSID             []int8
+		enforceGPP         bool
+		expectIDScrub      bool
+		expectGeoScrub     bool
+		expectDemoScrub    bool
+		expectErrorMessage string
+	}{
+		{
+			description:    "Sale Opt Out",
+			gpp:            "DBABLA~BVVaqqqqCaA",
+			gppSID:         []int8{7},
+			enforceGPP:     true,
+			expectIDScrub:  true,
+			expectGeoScrub: true,
+		},
+		{
+			description: "Sale Opt Out - Feature Flag Disabled",
+			gpp:         "DBABLA~BVVaqqqqCaA",
+			gppSID:      []int8{7},
+			enforceGPP:  false,
+		},
+		{
+			description: "Sale Opt Out - Section Not Applicable",
+			gpp:         "DBABLA~BVVaqqqqCaA",
+			gppSID:      []int8{8},
+			enforceGPP:  true,
+		},
+		{
+			description:    "Sensitive Data Limited",
+			gpp:            "DBABBg~BVqmqoJo",
+			gppSID:         []int8{8},
+			enforceGPP:     true,
+			expectGeoScrub: true,
+		},
+		{
+			description:     "Known Child",
+			gpp:             "DBABRg~BVqqqlo",
+			gppSID:          []int8{9},
+			enforceGPP:      true,
+			expectIDScrub:   true,
+			expectGeoScrub:  true,
+			expectDemoScrub: true,
+		},
+		{
+			description: "No Restriction",
+			gpp:         "DBABLA~BVVqqqqqCaA",
+			gppSID:      []int8{7},
+			enforceGPP:  true,
+		},
+		{
+			description:        "Invalid Section",
+			gpp:                "DBABLA~BVo",
+			gppSID:             []int8{7},
+			enforceGPP:         true,
+			expectErrorMessage: "error parsing GPP usnat section: core segment is too short",
+		},
+	}
+
+	for _, test := range testCases {
+		req := newBidRequest(t)
+		req.Regs = &openrtb2.Regs{GPP: test.gpp, GPPSID: test.gppSID}
+		req.Device.Geo = &openrtb2.Geo{Lat: 123.456}
+
+		auctionReq := AuctionRequest{
+			BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: req},
+			UserSyncs:         &emptyUsersync{},
+		}
+
+		gdprPermissionsBuilder := fakePermissionsBuilder{
+			permissions: &permissionsMock{
+				allowAllBidders: true,
+			},
+		}.Builder
+		tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+			cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+		}.Builder
+
+		privacyConfig := config.Privacy{
+			GPP: config.GPP{
+				Enforce: test.enforceGPP,
+			},
+		}
+
+		bidderToSyncerKey := map[string]string{}
+		metrics := metrics.MetricsEngineMock{}
+		results, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, nil, bidderToSyncerKey, &metrics, gdpr.SignalNo, privacyConfig, gdprPermissionsBuilder, tcf2ConfigBuilder, nil, config.BidderInfos{})
+		result := results[0]
+
+		if test.expectErrorMessage == "" {
+			assert.Nil(t, errs, test.description+":errors")
+		} else if assert.Len(t, errs, 1, test.description+":errors") {
+			assert.ErrorContains(t, errs[0], test.expectErrorMessage, test.description+":errors")
+		}
+		if test.expectIDScrub {
+			assert.Equal(t, "", result.BidRequest.User.BuyerUID, test.description+":User.BuyerUID")
+			assert.Equal(t, "", result.BidRequest.Device.DIDMD5, test.description+":Device.DIDMD5")
+		} else {
+			assert.NotEqual(t, "", result.BidRequest.User.BuyerUID, test.description+":User.BuyerUID")
+			assert.NotEqual(t, "", result.BidRequest.Device.DIDMD5, test.description+":Device.DIDMD5")
+		}
+		if test.expectGeoScrub {
+			assert.NotEqual(t, 123.456, result.BidRequest.Device.Geo.Lat, test.description+":Device.Geo.Lat")
+		} else {
+			assert.Equal(t, 123.456, result.BidRequest.Device.Geo.Lat, test.description+":Device.Geo.Lat")
+		}
+		if test.expectDemoScrub {
+			assert.Equal(t, int64(0), result.BidRequest.User.Yob, test.description+":User.Yob")
+		} else {
+			assert.Equal(t, int64(1982), result.BidRequest.User.Yob, test.description+":User.Yob")
+		}
+	}
+}
+
+func TestCleanOpenRTBRequestsActivities(t *testing.T) {
+	denyAppnexus := []config.ActivityRule{
+		{Condition: config.ActivityCondition{ComponentType: []string{"bidder"}, ComponentName: []string{"appnexus"}}, Allow: false},
+	}
+
+	testCases := []struct {
+		description     string
+		activities      config.AccountActivities
+		expectBidders   int
+		expectErrs      []error
+		expectIDScrub   bool
+		expectGeoScrub  bool
+		expectDemoScrub bool
+		expectFPDScrub  bool
+	}{
+		{
+			description:   "No Activities",
+			activities:    config.AccountActivities{},
+			expectBidders: 1,
+		},
+		{
+			description:   "Fetch Bids Denied",
+			activities:    config.AccountActivities{FetchBids: config.Activity{Rules: denyAppnexus}},
+			expectBidders: 0,
+			expectErrs: []error{&errortypes.Warning{
+				Message:     "appnexus request skipped: fetching bids is not allowed by the account activity controls",
+				WarningCode: errortypes.ActivityBlockedWarningCode,
+			}},
+		},
+		{
+			description:     "Transmit UFPD Denied",
+			activities:      config.AccountActivities{TransmitUserFPD: config.Activity{Rules: denyAppnexus}},
+			expectBidders:   1,
+			expectIDScrub:   true,
+			expectDemoScrub: true,
+			expectFPDScrub:  true,
+		},
+		{
+			description:    "Transmit Precise Geo Denied",
+			activities:     config.AccountActivities{TransmitPreciseGeo: config.Activity{Rules: denyAppnexus}},
+			expectBidders:  1,
+			expectGeoScrub: true,
+		},
+		{
+			description: "Other Geo Denied",
+			activities: config.AccountActivities{FetchBids: config.Activity{Rules: []config.ActivityRule{
+				{Condition: config.ActivityCondition{Geo: []string{"USA.VA"}}, Allow: false},
+			}}},
+			expectBidders: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		req := newBidRequest(t)
+		req.Device.Geo = &openrtb2.Geo{Lat: 123.456, Country: "USA", Region: "CA"}
+
+		auctionReq := AuctionRequest{
+			BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: req},
+			UserSyncs:         &emptyUsersync{},
+			Account:           config.Account{Activities: test.activities},
+			FirstPartyData: map[openrtb_ext.BidderName]*firstpartydata.ResolvedFirstPartyData{
+				"appnexus": {
+					Site: &openrtb2.Site{Page: "www.some.domain.com", Content: &openrtb2.Content{Data: []openrtb2.Data{{ID: "siteData"}}}},
+					User: &openrtb2.User{Yob: 1982, Keywords: "userKeywords", Data: []openrtb2.Data{{ID: "userData"}}, Ext: json.RawMessage(`{"data":{"key":"value"}}`)},
+				},
+			},
+		}
+
+		gdprPermissionsBuilder := fakePermissionsBuilder{
+			permissions: &permissionsMock{
+				allowAllBidders: true,
+			},
+		}.Builder
+		tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+			cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+		}.Builder
+
+		bidderToSyncerKey := map[string]string{}
+		metrics := metrics.MetricsEngineMock{}
+		results, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, nil, bidderToSyncerKey, &metrics, gdpr.SignalNo, config.Privacy{}, gdprPermissionsBuilder, tcf2ConfigBuilder, nil, config.BidderInfos{})
+
+		assert.Equal(t, test.expectErrs, errs, test.description+":errors")
+		if !assert.Len(t, results, test.expectBidders, test.description+":bidders") || test.expectBidders == 0 {
+			continue
+		}
+		result := results[0]
+
+		if test.expectFPDScrub {
+			assert.Empty(t, result.BidRequest.User.Data, test.description+":User.Data")
+			assert.Empty(t, result.BidRequest.User.Keywords, test.description+":User.Keywords")
+			assert.JSONEq(t, `{}`, string(result.BidRequest.User.Ext), test.description+":User.Ext")
+			assert.Empty(t, result.BidRequest.Site.Content.Data, test.description+":Site.Content.Data")
+		} else {
+			assert.NotEmpty(t, result.BidRequest.User.Data, test.description+":User.Data")
+			assert.NotEmpty(t, result.BidRequest.User.Keywords, test.description+":User.Keywords")
+			assert.JSONEq(t, `{"data":{"key":"value"}}`, string(result.BidRequest.User.Ext), test.description+":User.Ext")
+			assert.NotEmpty(t, result.BidRequest.Site.Content.Data, test.description+":Site.Content.Data")
+		}
+
+		if test.expectIDScrub {
+			assert.Equal(t, "", result.BidRequest.User.BuyerUID, test.description+":User.BuyerUID")
+			assert.Equal(t, "", result.BidRequest.Device.DIDMD5, test.description+":Device.DIDMD5")
+		} else {
+			assert.NotEqual(t, "", result.BidRequest.User.BuyerUID, test.description+":User.BuyerUID")
+			assert.NotEqual(t, "", result.BidRequest.Device.DIDMD5, test.description+":Device.DIDMD5")
+		}
+		if test.expectGeoScrub {
+			assert.NotEqual(t, 123.456, result.BidRequest.Device.Geo.Lat, test.description+":Device.Geo.Lat")
+		} else {
+			assert.Equal(t, 123.456, result.BidRequest.Device.Geo.Lat, test.description+":Device.Geo.Lat")
+		}
+		if test.expectDemoScrub {
+			assert.Equal(t, int64(0), result.BidRequest.User.Yob, test.description+":User.Yob")
+		} else {
+			assert.Equal(t, int64(1982), result.BidRequest.User.Yob, test.description+":User.Yob")
+		}
+	}
+}
+
 func TestCleanOpenRTBRequestsGDPR(t *testing.T) {
 	tcf2Consent := "COzTVhaOzTVhaGvAAAENAiCIAP_AAH_AAAAAAEEUACCKAAA"
 	trueValue, falseValue := true, false
@@ -2218,7 +2489,8 @@ func TestCleanOpenRTBRequestsGDPR(t *testing.T) {
 			privacyConfig,
 			gdprPermissionsBuilder,
 			tcf2ConfigBuilder,
-			nil)
+			nil,
+			config.BidderInfos{})
 		result := results[0]
 
 		if test.expectError {
@@ -2323,7 +2595,8 @@ func TestCleanOpenRTBRequestsGDPRBlockBidRequest(t *testing.T) {
 			privacyConfig,
 			gdprPermissionsBuilder,
 			tcf2ConfigBuilder,
-			nil)
+			nil,
+			config.BidderInfos{})
 
 		// extract bidder name from each request in the results
 		bidders := []openrtb_ext.BidderName{}
@@ -3047,7 +3320,7 @@ func TestCleanOpenRTBRequestsSChainMultipleBidders(t *testing.T) {
 
 	bidderToSyncerKey := map[string]string{}
 	metrics := metrics.MetricsEngineMock{}
-	bidderRequests, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, bidderToSyncerKey, &metrics, gdpr.SignalNo, config.Privacy{}, gdprPermissionsBuilder, tcf2ConfigBuilder, nil)
+	bidderRequests, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, bidderToSyncerKey, &metrics, gdpr.SignalNo, config.Privacy{}, gdprPermissionsBuilder, tcf2ConfigBuilder, nil, config.BidderInfos{})
 
 	assert.Nil(t, errs)
 	assert.Len(t, bidderRequests, 2, "Bid request count is not 2")
@@ -3063,6 +3336,157 @@ func TestCleanOpenRTBRequestsSChainMultipleBidders(t *testing.T) {
 	assert.Equal(t, axonixPrebidSchainsSchain, bidRequestSourceExts["axonix"], "Incorrect axonix bid request schain in source.ext")
 }
 
+func TestCleanOpenRTBRequestsOpenRTBVersion(t *testing.T) {
+	req := &openrtb2.BidRequest{
+		Site: &openrtb2.Site{},
+		Regs: &openrtb2.Regs{
+			Ext: json.RawMessage(`{"gdpr":0}`),
+		},
+		User: &openrtb2.User{
+			Ext: json.RawMessage(`{"consent":"anyConsent","eids":[{"source":"anySource","uids":[{"id":"anyID"}]}]}`),
+		},
+		Source: &openrtb2.Source{
+			TID:    "61018dc9-fa61-4c41-b7dc-f90b9ae80e87",
+			SChain: &openrtb2.SupplyChain{Complete: 1, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{{ASI: "directseller.com", SID: "00001", HP: openrtb2.Int8Ptr(1)}}},
+		},
+		Imp: []openrtb2.Imp{{
+			ID:  "some-imp-id",
+			Ext: json.RawMessage(`{"prebid":{"bidder":{"appnexus": {"placementId": 1}, "axonix": {"supplyId": "123"}}}}`),
+		}},
+	}
+
+	auctionReq := AuctionRequest{
+		BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: req},
+		UserSyncs:         &emptyUsersync{},
+	}
+
+	gdprPermissionsBuilder := fakePermissionsBuilder{
+		permissions: &permissionsMock{
+			allowAllBidders: true,
+			passGeo:         true,
+			passID:          true,
+		},
+	}.Builder
+	tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+		cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+	}.Builder
+
+	bidderInfo := config.BidderInfos{
+		"appnexus": config.BidderInfo{OpenRTB: &config.OpenRTBInfo{Version: config.OpenRTBVersion26}},
+		"axonix":   config.BidderInfo{},
+	}
+
+	bidderToSyncerKey := map[string]string{}
+	metrics := metrics.MetricsEngineMock{}
+	bidderRequests, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, nil, bidderToSyncerKey, &metrics, gdpr.SignalNo, config.Privacy{}, gdprPermissionsBuilder, tcf2ConfigBuilder, nil, bidderInfo)
+
+	assert.Empty(t, errs)
+	assert.Len(t, bidderRequests, 2)
+
+	bidRequests := map[openrtb_ext.BidderName]*openrtb2.BidRequest{}
+	for _, bidderRequest := range bidderRequests {
+		bidRequests[bidderRequest.BidderName] = bidderRequest.BidRequest
+	}
+
+	ortb26Request := bidRequests["appnexus"]
+	if assert.NotNil(t, ortb26Request, "Missing appnexus bid request") {
+		assert.Equal(t, openrtb2.Int8Ptr(0), ortb26Request.Regs.GDPR, "regs.gdpr")
+		assert.Empty(t, ortb26Request.Regs.Ext, "regs.ext")
+		assert.Equal(t, "anyConsent", ortb26Request.User.Consent, "user.consent")
+		assert.Equal(t, []openrtb2.EID{{Source: "anySource", UIDs: []openrtb2.UID{{ID: "anyID"}}}}, ortb26Request.User.EIDs, "user.eids")
+		assert.Empty(t, ortb26Request.User.Ext, "user.ext")
+		assert.Equal(t, req.Source.SChain, ortb26Request.Source.SChain, "source.schain")
+	}
+
+	ortb25Request := bidRequests["axonix"]
+	if assert.NotNil(t, ortb25Request, "Missing axonix bid request") {
+		assert.Nil(t, ortb25Request.Regs.GDPR, "regs.gdpr")
+		assert.JSONEq(t, `{"gdpr":0}`, string(ortb25Request.Regs.Ext), "regs.ext")
+		assert.Empty(t, ortb25Request.User.Consent, "user.consent")
+		assert.Nil(t, ortb25Request.User.EIDs, "user.eids")
+		assert.JSONEq(t, `{"consent":"anyConsent","eids":[{"source":"anySource","uids":[{"id":"anyID"}]}]}`, string(ortb25Request.User.Ext), "user.ext")
+		assert.Nil(t, ortb25Request.Source.SChain, "source.schain")
+		assert.JSONEq(t, `{"schain":{"complete":1,"nodes":[{"asi":"directseller.com","sid":"00001","hp":1}],"ver":"1.0"}}`, string(ortb25Request.Source.Ext), "source.ext")
+	}
+
+	assert.Nil(t, req.Regs.GDPR, "Incoming regs.gdpr modified")
+	assert.Empty(t, req.User.Consent, "Incoming user.consent modified")
+	assert.NotNil(t, req.Source.SChain, "Incoming source.schain modified")
+}
+
+func TestCleanOpenRTBRequestsOpenRTBVersionEidPermissions(t *testing.T) {
+	req := &openrtb2.BidRequest{
+		Site: &openrtb2.Site{},
+		User: &openrtb2.User{
+			EIDs: []openrtb2.EID{
+				{Source: "allowedSource", UIDs: []openrtb2.UID{{ID: "allowedID"}}},
+				{Source: "appnexusSource", UIDs: []openrtb2.UID{{ID: "appnexusID"}}},
+			},
+			Ext: json.RawMessage(`{"other":"otherUser"}`),
+		},
+		Imp: []openrtb2.Imp{{
+			ID:  "some-imp-id",
+			Ext: json.RawMessage(`{"prebid":{"bidder":{"appnexus": {"placementId": 1}, "axonix": {"supplyId": "123"}}}}`),
+		}},
+	}
+	requestExt := &openrtb_ext.ExtRequest{
+		Prebid: openrtb_ext.ExtRequestPrebid{
+			Data: &openrtb_ext.ExtRequestPrebidData{
+				EidPermissions: []openrtb_ext.ExtRequestPrebidDataEidPermission{
+					{Source: "appnexusSource", Bidders: []string{"appnexus"}},
+				},
+			},
+		},
+	}
+
+	auctionReq := AuctionRequest{
+		BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: req},
+		UserSyncs:         &emptyUsersync{},
+	}
+
+	gdprPermissionsBuilder := fakePermissionsBuilder{
+		permissions: &permissionsMock{
+			allowAllBidders: true,
+			passGeo:         true,
+			passID:          true,
+		},
+	}.Builder
+	tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+		cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+	}.Builder
+
+	bidderInfo := config.BidderInfos{
+		"appnexus": config.BidderInfo{OpenRTB: &config.OpenRTBInfo{Version: config.OpenRTBVersion26}},
+		"axonix":   config.BidderInfo{},
+	}
+
+	bidderToSyncerKey := map[string]string{}
+	metrics := metrics.MetricsEngineMock{}
+	bidderRequests, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, requestExt, bidderToSyncerKey, &metrics, gdpr.SignalNo, config.Privacy{}, gdprPermissionsBuilder, tcf2ConfigBuilder, nil, bidderInfo)
+
+	assert.Empty(t, errs)
+	assert.Len(t, bidderRequests, 2)
+
+	bidRequests := map[openrtb_ext.BidderName]*openrtb2.BidRequest{}
+	for _, bidderRequest := range bidderRequests {
+		bidRequests[bidderRequest.BidderName] = bidderRequest.BidRequest
+	}
+
+	ortb26Request := bidRequests["appnexus"]
+	if assert.NotNil(t, ortb26Request, "Missing appnexus bid request") {
+		assert.Equal(t, req.User.EIDs, ortb26Request.User.EIDs, "user.eids")
+		assert.JSONEq(t, `{"other":"otherUser"}`, string(ortb26Request.User.Ext), "user.ext")
+	}
+
+	ortb25Request := bidRequests["axonix"]
+	if assert.NotNil(t, ortb25Request, "Missing axonix bid request") {
+		assert.Nil(t, ortb25Request.User.EIDs, "user.eids")
+		assert.JSONEq(t, `{"other":"otherUser","eids":[{"source":"allowedSource","uids":[{"id":"allowedID"}]}]}`, string(ortb25Request.User.Ext), "user.ext")
+	}
+
+	assert.Len(t, req.User.EIDs, 2, "Incoming user.eids modified")
+}
+
 func TestApplyFPD(t *testing.T) {
 
 	testCases := []struct {
@@ -3398,7 +3822,7 @@ func TestCleanOpenRTBRequestsFilterBidderRequestExt(t *testing.T) {
 		bidderToSyncerKey := map[string]string{}
 		metrics := metrics.MetricsEngineMock{}
 
-		bidderRequests, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, bidderToSyncerKey, &metrics, gdpr.SignalNo, config.Privacy{}, gdprPermissionsBuilder, tcf2ConfigBuilder, nil)
+		bidderRequests, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, bidderToSyncerKey, &metrics, gdpr.SignalNo, config.Privacy{}, gdprPermissionsBuilder, tcf2ConfigBuilder, nil, config.BidderInfos{})
 		assert.Equal(t, test.wantError, len(errs) != 0, test.desc)
 		sort.Slice(bidderRequests, func(i, j int) bool {
 			return bidderRequests[i].BidderCoreName < bidderRequests[j].BidderCoreName
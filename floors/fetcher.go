@@ -0,0 +1,266 @@
+package floors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/prebid/prebid-server/util/timeutil"
+)
+
+const fileScheme = "file"
+
+// unusedSourceTTL is how long a source is kept, and reloaded, after the last account asked for it. Sources
+// whose account config changed stop being asked for and are dropped.
+const unusedSourceTTL = 24 * time.Hour
+
+var errFileSourcesDisabled = errors.New("file floor sources are disabled")
+
+// FloorFetcher provides the floor data loaded from an account's configured floors source.
+type FloorFetcher interface {
+	// Fetch returns the most recently loaded floor data for the account along with the fetch status.
+	// A nil result is returned until the first load of the source has completed.
+	Fetch(configs config.AccountPriceFloors) (*openrtb_ext.PriceFloorData, string)
+}
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// PriceFloorFetcher loads floor data from http(s) URLs or local files and keeps it up to date.
+// Sources are registered the first time an account asks for them, loaded in the background and
+// reloaded by Run once their refresh period has elapsed. A source is identified by its whole fetch
+// config, so accounts sharing a URL with different limits don't share the data.
+type PriceFloorFetcher struct {
+	httpClient    httpClient
+	fileDirectory string
+	time          timeutil.Time
+	mutex         sync.RWMutex
+	sources       map[config.AccountFloorFetch]*floorSource
+}
+
+type floorSource struct {
+	data       *openrtb_ext.PriceFloorData
+	fetchedAt  time.Time
+	nextFetch  time.Time
+	inProgress bool
+	// lastRequested is the unix time in nanoseconds an account last asked for the source
+	lastRequested int64
+}
+
+// NewPriceFloorFetcher returns a new PriceFloorFetcher. Accounts can only load floor data from the files in
+// fileDirectory, and from no file at all when it is empty.
+func NewPriceFloorFetcher(httpClient httpClient, fileDirectory string) *PriceFloorFetcher {
+	return &PriceFloorFetcher{
+		httpClient:    httpClient,
+		fileDirectory: fileDirectory,
+		time:          &timeutil.RealTime{},
+		sources:       make(map[config.AccountFloorFetch]*floorSource),
+	}
+}
+
+// Fetch implements the FloorFetcher interface
+func (f *PriceFloorFetcher) Fetch(configs config.AccountPriceFloors) (*openrtb_ext.PriceFloorData, string) {
+	if !configs.Fetcher.Enabled || configs.Fetcher.URL == "" {
+		return nil, openrtb_ext.FetchStatusNone
+	}
+
+	now := f.time.Now().UnixNano()
+
+	f.mutex.RLock()
+	source, found := f.sources[configs.Fetcher]
+	var data *openrtb_ext.PriceFloorData
+	if found {
+		data = source.data
+		atomic.StoreInt64(&source.lastRequested, now)
+	}
+	f.mutex.RUnlock()
+
+	if found {
+		if data != nil {
+			return data, openrtb_ext.FetchStatusSuccess
+		}
+		return nil, openrtb_ext.FetchStatusInprogress
+	}
+
+	f.mutex.Lock()
+	if _, found := f.sources[configs.Fetcher]; !found {
+		f.sources[configs.Fetcher] = &floorSource{inProgress: true, lastRequested: now}
+		go f.load(configs.Fetcher)
+	}
+	f.mutex.Unlock()
+
+	return nil, openrtb_ext.FetchStatusInprogress
+}
+
+// Run reloads every registered source whose refresh period has elapsed and evicts the data of
+// sources which could not be refreshed within their max age. Sources no account asked for within
+// unusedSourceTTL are dropped. It is meant to be scheduled periodically using a task.TickerTask.
+func (f *PriceFloorFetcher) Run() error {
+	now := f.time.Now()
+	var due []config.AccountFloorFetch
+
+	f.mutex.Lock()
+	for fetchConfig, source := range f.sources {
+		if !source.inProgress && now.Sub(time.Unix(0, atomic.LoadInt64(&source.lastRequested))) > unusedSourceTTL {
+			delete(f.sources, fetchConfig)
+			continue
+		}
+		if source.data != nil && fetchConfig.MaxAge > 0 && now.Sub(source.fetchedAt) > time.Duration(fetchConfig.MaxAge)*time.Second {
+			glog.Warningf("Price floors data loaded from %s is older than %d seconds and has been discarded", fetchConfig.URL, fetchConfig.MaxAge)
+			source.data = nil
+		}
+		if !source.inProgress && !now.Before(source.nextFetch) {
+			source.inProgress = true
+			due = append(due, fetchConfig)
+		}
+	}
+	f.mutex.Unlock()
+
+	for _, fetchConfig := range due {
+		f.load(fetchConfig)
+	}
+	return nil
+}
+
+// load fetches the source and stores the result. It must only be called for a registered source
+// which has been flagged as in progress.
+func (f *PriceFloorFetcher) load(fetchConfig config.AccountFloorFetch) {
+	data, err := f.fetchAndValidate(fetchConfig)
+	now := f.time.Now()
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	source := f.sources[fetchConfig]
+	source.inProgress = false
+	source.nextFetch = now.Add(time.Duration(fetchConfig.Period) * time.Second)
+	if err != nil {
+		glog.Errorf("Error loading price floors from %s: %v", fetchConfig.URL, err)
+		return
+	}
+	source.data = data
+	source.fetchedAt = now
+}
+
+func (f *PriceFloorFetcher) fetchAndValidate(fetchConfig config.AccountFloorFetch) (*openrtb_ext.PriceFloorData, error) {
+	body, err := f.fetch(fetchConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var data openrtb_ext.PriceFloorData
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("unable to parse floor data: %v", err)
+	}
+
+	if errs := validateFloorData(&data, fetchConfig.MaxRules, 0); len(data.ModelGroups) == 0 {
+		return nil, fmt.Errorf("invalid floor data: %v", errs)
+	}
+	return &data, nil
+}
+
+func (f *PriceFloorFetcher) fetch(fetchConfig config.AccountFloorFetch) ([]byte, error) {
+	sourceURL, err := url.Parse(fetchConfig.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSize := int64(fetchConfig.MaxFileSize) * 1024
+
+	if sourceURL.Scheme == fileScheme {
+		path, err := f.filePath(sourceURL.Path)
+		if err != nil {
+			return nil, err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return readLimited(file, maxSize)
+	}
+
+	ctx := context.Background()
+	if fetchConfig.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(fetchConfig.Timeout)*time.Millisecond)
+		defer cancel()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fetchConfig.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := f.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", response.StatusCode)
+	}
+	return readLimited(response.Body, maxSize)
+}
+
+// filePath returns the path of a file source, which must be in the file directory of the host once the
+// symbolic links are resolved.
+func (f *PriceFloorFetcher) filePath(path string) (string, error) {
+	if f.fileDirectory == "" {
+		return "", errFileSourcesDisabled
+	}
+
+	directory, err := filepath.EvalSymlinks(f.fileDirectory)
+	if err != nil {
+		return "", err
+	}
+	directory, err = filepath.Abs(directory)
+	if err != nil {
+		return "", err
+	}
+
+	resolved, err := filepath.EvalSymlinks(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	resolved, err = filepath.Abs(resolved)
+	if err != nil {
+		return "", err
+	}
+
+	relative, err := filepath.Rel(directory, resolved)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("floor file %s is outside of the floor file directory", path)
+	}
+	return resolved, nil
+}
+
+// readLimited reads the whole reader, failing if more than maxSize bytes are available. A
+// non-positive maxSize disables the check.
+func readLimited(r io.Reader, maxSize int64) ([]byte, error) {
+	if maxSize <= 0 {
+		return io.ReadAll(r)
+	}
+	body, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > maxSize {
+		return nil, errors.New("floor data exceeds the maximum file size")
+	}
+	return body, nil
+}
@@ -0,0 +1,219 @@
+package floors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+const testFloorData = `{"currency":"USD","modelgroups":[{"schema":{"fields":["mediaType"]},"values":{"banner":1.5}}]}`
+
+type fakeTime struct {
+	time time.Time
+}
+
+func (f *fakeTime) Now() time.Time {
+	return f.time
+}
+
+func floorFetchConfig(url string) config.AccountPriceFloors {
+	return config.AccountPriceFloors{
+		Fetcher: config.AccountFloorFetch{
+			Enabled:     true,
+			URL:         url,
+			Timeout:     1000,
+			MaxFileSize: 10,
+			MaxRules:    10,
+			MaxAge:      600,
+			Period:      300,
+		},
+	}
+}
+
+func TestFetchLoadsSourceInBackground(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testFloorData))
+	}))
+	defer server.Close()
+
+	fetcher := NewPriceFloorFetcher(server.Client(), "")
+	configs := floorFetchConfig(server.URL)
+
+	data, status := fetcher.Fetch(configs)
+	assert.Nil(t, data)
+	assert.Equal(t, openrtb_ext.FetchStatusInprogress, status)
+
+	assert.Eventually(t, func() bool {
+		data, status = fetcher.Fetch(configs)
+		return status == openrtb_ext.FetchStatusSuccess
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, map[string]float64{"banner": 1.5}, data.ModelGroups[0].Values)
+}
+
+func TestFetchDisabled(t *testing.T) {
+	fetcher := NewPriceFloorFetcher(http.DefaultClient, "")
+	configs := floorFetchConfig("http://test.com/floors")
+	configs.Fetcher.Enabled = false
+
+	data, status := fetcher.Fetch(configs)
+	assert.Nil(t, data)
+	assert.Equal(t, openrtb_ext.FetchStatusNone, status)
+	assert.Empty(t, fetcher.sources)
+}
+
+func TestFetchAndValidate(t *testing.T) {
+	dir := t.TempDir()
+	outsideFile := filepath.Join(t.TempDir(), "floors.json")
+	assert.NoError(t, os.WriteFile(outsideFile, []byte(testFloorData), 0644))
+	validFile := filepath.Join(dir, "floors.json")
+	assert.NoError(t, os.WriteFile(validFile, []byte(testFloorData), 0644))
+	invalidFile := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(invalidFile, []byte(`{"modelgroups":[{"schema":{"fields":["unknown"]}}]}`), 0644))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/floors":
+			w.Write([]byte(testFloorData))
+		case "/large":
+			w.Write(make([]byte, 11*1024))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name        string
+		url         string
+		expectError bool
+	}{
+		{name: "http", url: server.URL + "/floors"},
+		{name: "file", url: "file://" + validFile},
+		{name: "http-not-found", url: server.URL + "/missing", expectError: true},
+		{name: "http-too-large", url: server.URL + "/large", expectError: true},
+		{name: "file-missing", url: "file://" + filepath.Join(dir, "missing.json"), expectError: true},
+		{name: "file-invalid-data", url: "file://" + invalidFile, expectError: true},
+		{name: "file-outside-directory", url: "file://" + outsideFile, expectError: true},
+	}
+
+	fetcher := NewPriceFloorFetcher(server.Client(), dir)
+	for _, test := range testCases {
+		data, err := fetcher.fetchAndValidate(floorFetchConfig(test.url).Fetcher)
+		if test.expectError {
+			assert.Error(t, err, test.name)
+			assert.Nil(t, data, test.name)
+		} else {
+			assert.NoError(t, err, test.name)
+			assert.Len(t, data.ModelGroups, 1, test.name)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	requests := 0
+	available := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if !available {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(testFloorData))
+	}))
+	defer server.Close()
+
+	clock := &fakeTime{time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	fetcher := NewPriceFloorFetcher(server.Client(), "")
+	fetcher.time = clock
+	configs := floorFetchConfig(server.URL)
+	fetcher.sources[configs.Fetcher] = &floorSource{lastRequested: clock.time.UnixNano()}
+
+	// source due for its first load
+	assert.NoError(t, fetcher.Run())
+	assert.Equal(t, 1, requests)
+	data, status := fetcher.Fetch(configs)
+	assert.NotNil(t, data)
+	assert.Equal(t, openrtb_ext.FetchStatusSuccess, status)
+
+	// period not elapsed yet
+	clock.time = clock.time.Add(100 * time.Second)
+	assert.NoError(t, fetcher.Run())
+	assert.Equal(t, 1, requests)
+
+	// period elapsed but the source fails, the previous data is kept
+	available = false
+	clock.time = clock.time.Add(200 * time.Second)
+	assert.NoError(t, fetcher.Run())
+	assert.Equal(t, 2, requests)
+	data, _ = fetcher.Fetch(configs)
+	assert.NotNil(t, data)
+
+	// max age elapsed, the stale data is discarded
+	clock.time = clock.time.Add(400 * time.Second)
+	assert.NoError(t, fetcher.Run())
+	assert.Equal(t, 3, requests)
+	data, status = fetcher.Fetch(configs)
+	assert.Nil(t, data)
+	assert.Equal(t, openrtb_ext.FetchStatusInprogress, status)
+}
+
+func TestFetchFileSourcesDisabled(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "floors.json")
+	assert.NoError(t, os.WriteFile(file, []byte(testFloorData), 0644))
+
+	fetcher := NewPriceFloorFetcher(http.DefaultClient, "")
+	data, err := fetcher.fetchAndValidate(floorFetchConfig("file://" + file).Fetcher)
+	assert.Equal(t, errFileSourcesDisabled, err)
+	assert.Nil(t, data)
+}
+
+func TestFetchSourcesByConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testFloorData))
+	}))
+	defer server.Close()
+
+	fetcher := NewPriceFloorFetcher(server.Client(), "")
+	small := floorFetchConfig(server.URL)
+	small.Fetcher.MaxFileSize = 0
+	large := floorFetchConfig(server.URL)
+
+	fetcher.Fetch(small)
+	fetcher.Fetch(large)
+
+	assert.Eventually(t, func() bool {
+		_, smallStatus := fetcher.Fetch(small)
+		_, largeStatus := fetcher.Fetch(large)
+		return smallStatus == openrtb_ext.FetchStatusSuccess && largeStatus == openrtb_ext.FetchStatusSuccess
+	}, time.Second, 10*time.Millisecond)
+	assert.Len(t, fetcher.sources, 2)
+}
+
+func TestRunDropsUnusedSources(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testFloorData))
+	}))
+	defer server.Close()
+
+	clock := &fakeTime{time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	fetcher := NewPriceFloorFetcher(server.Client(), "")
+	fetcher.time = clock
+	configs := floorFetchConfig(server.URL)
+	fetcher.sources[configs.Fetcher] = &floorSource{lastRequested: clock.time.UnixNano()}
+
+	clock.time = clock.time.Add(time.Hour)
+	assert.NoError(t, fetcher.Run())
+	assert.Len(t, fetcher.sources, 1, "recently requested")
+
+	clock.time = clock.time.Add(unusedSourceTTL)
+	assert.NoError(t, fetcher.Run())
+	assert.Empty(t, fetcher.sources, "unused")
+}
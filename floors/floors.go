@@ -0,0 +1,285 @@
+package floors
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// floorPrecisionFactor rounds floor values up to the nearest cent
+const floorPrecisionFactor float64 = 100
+
+// randomIntn is swapped in tests to make skip and model group selection deterministic
+var randomIntn = rand.Intn
+
+// EnrichWithPriceFloors resolves the floor data to use for the auction, selects a model group,
+// and sets imp.bidfloor, imp.bidfloorcur and imp.ext.prebid.floors on every imp. The resolved
+// floor rules are written back to request.ext.prebid.floors so that they are available to floor
+// enforcement. Any issue found while resolving floors is returned as a warning.
+func EnrichWithPriceFloors(bidRequestWrapper *openrtb_ext.RequestWrapper, account config.Account, conversions currency.Conversions, priceFloorFetcher FloorFetcher) []error {
+	if bidRequestWrapper == nil || bidRequestWrapper.BidRequest == nil {
+		return []error{errors.New("Empty bidrequest")}
+	}
+
+	requestExt, err := bidRequestWrapper.GetRequestExt()
+	if err != nil {
+		return []error{err}
+	}
+	prebidExt := requestExt.GetPrebid()
+
+	var requestFloors *openrtb_ext.PriceFloorRules
+	if prebidExt != nil {
+		requestFloors = prebidExt.Floors
+	}
+
+	if !account.PriceFloors.Enabled || !requestFloors.GetEnabled() {
+		return nil
+	}
+
+	floors, errs := resolveFloors(account, requestFloors, priceFloorFetcher)
+
+	if shouldSkipFloors(floors) {
+		skipped := true
+		floors.Skipped = &skipped
+	} else {
+		skipped := false
+		floors.Skipped = &skipped
+		errs = append(errs, updateBidRequestWithFloors(floors, bidRequestWrapper, conversions)...)
+	}
+
+	if prebidExt == nil {
+		prebidExt = &openrtb_ext.ExtRequestPrebid{}
+	}
+	prebidExt.Floors = floors
+	requestExt.SetPrebid(prebidExt)
+
+	return toWarnings(errs)
+}
+
+// resolveFloors picks the source of the floor data. Dynamically fetched data takes precedence
+// when allowed by the account, followed by the data sent on the request and then the data
+// configured on the account itself.
+func resolveFloors(account config.Account, requestFloors *openrtb_ext.PriceFloorRules, priceFloorFetcher FloorFetcher) (*openrtb_ext.PriceFloorRules, []error) {
+	var errs []error
+	floors := &openrtb_ext.PriceFloorRules{}
+	if requestFloors != nil {
+		*floors = *requestFloors
+		floors.Data = nil
+		if err := validateFloorRules(floors); err != nil {
+			errs = append(errs, err)
+			floors = &openrtb_ext.PriceFloorRules{Enabled: requestFloors.Enabled, Enforcement: requestFloors.Enforcement}
+		}
+	}
+	floors.PriceFloorLocation = openrtb_ext.FloorLocationNoData
+
+	if priceFloorFetcher != nil && account.PriceFloors.UseDynamicData {
+		fetchedData, fetchStatus := priceFloorFetcher.Fetch(account.PriceFloors)
+		floors.FetchStatus = fetchStatus
+		if fetchedData != nil {
+			data, dataErrs := validatedCopy(fetchedData, account.PriceFloors.MaxRule, account.PriceFloors.MaxSchemaDims)
+			errs = append(errs, dataErrs...)
+			if data != nil {
+				floors.Data = data
+				floors.PriceFloorLocation = openrtb_ext.FloorLocationFetch
+				return floors, errs
+			}
+		}
+	}
+
+	if requestFloors != nil && requestFloors.Data != nil {
+		data, dataErrs := validatedCopy(requestFloors.Data, account.PriceFloors.MaxRule, account.PriceFloors.MaxSchemaDims)
+		errs = append(errs, dataErrs...)
+		if data != nil {
+			floors.Data = data
+			floors.PriceFloorLocation = openrtb_ext.FloorLocationRequest
+			return floors, errs
+		}
+	}
+
+	if account.PriceFloors.Data != nil {
+		data, dataErrs := validatedCopy(account.PriceFloors.Data, account.PriceFloors.MaxRule, account.PriceFloors.MaxSchemaDims)
+		errs = append(errs, dataErrs...)
+		if data != nil {
+			floors.Data = data
+			floors.PriceFloorLocation = openrtb_ext.FloorLocationAccount
+		}
+	}
+
+	return floors, errs
+}
+
+// validatedCopy returns a validated copy of the floor data so that shared data, such as account
+// or fetched data, is never modified. It returns nil if the data has no usable model group.
+func validatedCopy(data *openrtb_ext.PriceFloorData, maxRules, maxDims int) (*openrtb_ext.PriceFloorData, []error) {
+	dataCopy := *data
+	dataCopy.ModelGroups = append([]openrtb_ext.PriceFloorModelGroup(nil), data.ModelGroups...)
+
+	errs := validateFloorData(&dataCopy, maxRules, maxDims)
+	if len(dataCopy.ModelGroups) == 0 {
+		return nil, errs
+	}
+
+	if modelGroup, ok := selectModelGroup(dataCopy.ModelGroups); ok {
+		dataCopy.ModelGroups = []openrtb_ext.PriceFloorModelGroup{modelGroup}
+	}
+	return &dataCopy, errs
+}
+
+// selectModelGroup picks one of the model groups at random according to their weights. Model
+// groups without a weight are given a weight of 1.
+func selectModelGroup(modelGroups []openrtb_ext.PriceFloorModelGroup) (openrtb_ext.PriceFloorModelGroup, bool) {
+	if len(modelGroups) == 0 {
+		return openrtb_ext.PriceFloorModelGroup{}, false
+	}
+
+	totalWeight := 0
+	for i := range modelGroups {
+		if modelGroups[i].ModelWeight == nil {
+			weight := 1
+			modelGroups[i].ModelWeight = &weight
+		}
+		totalWeight += *modelGroups[i].ModelWeight
+	}
+
+	winningWeight := randomIntn(totalWeight) + 1
+	for _, modelGroup := range modelGroups {
+		winningWeight -= *modelGroup.ModelWeight
+		if winningWeight <= 0 {
+			return modelGroup, true
+		}
+	}
+	return modelGroups[len(modelGroups)-1], true
+}
+
+// shouldSkipFloors decides whether floors are skipped for this auction. The model group skip
+// rate has precedence over the data skip rate which has precedence over the request skip rate.
+func shouldSkipFloors(floors *openrtb_ext.PriceFloorRules) bool {
+	skipRate := floors.SkipRate
+	if floors.Data != nil {
+		if floors.Data.SkipRate > 0 {
+			skipRate = floors.Data.SkipRate
+		}
+		if len(floors.Data.ModelGroups) > 0 && floors.Data.ModelGroups[0].SkipRate > 0 {
+			skipRate = floors.Data.ModelGroups[0].SkipRate
+		}
+	}
+
+	if skipRate <= 0 {
+		return false
+	}
+	return randomIntn(100) < skipRate
+}
+
+// updateBidRequestWithFloors sets the resolved floor on every imp of the request
+func updateBidRequestWithFloors(floors *openrtb_ext.PriceFloorRules, bidRequestWrapper *openrtb_ext.RequestWrapper, conversions currency.Conversions) []error {
+	var errs []error
+
+	var modelGroup openrtb_ext.PriceFloorModelGroup
+	hasModelGroup := floors.Data != nil && len(floors.Data.ModelGroups) > 0
+	if hasModelGroup {
+		modelGroup = floors.Data.ModelGroups[0]
+	}
+
+	floorCur := getFloorCurrency(floors.Data, modelGroup)
+	floorMin, err := getMinFloorValue(floors, floorCur, conversions)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, imp := range bidRequestWrapper.GetImp() {
+		impFloors := openrtb_ext.ExtImpPrebidFloors{
+			FloorMin:    floors.FloorMin,
+			FloorMinCur: floors.FloorMinCur,
+		}
+
+		floorValue := 0.0
+		matched := false
+		if hasModelGroup {
+			desiredValues := createRuleKey(modelGroup.Schema.Fields, bidRequestWrapper.BidRequest, imp)
+			if rule, ok := findRule(modelGroup.Values, getDelimiter(modelGroup.Schema), desiredValues); ok {
+				floorValue = modelGroup.Values[rule]
+				impFloors.FloorRule = rule
+				impFloors.FloorRuleValue = floorValue
+				matched = true
+			} else if modelGroup.Default > 0 {
+				floorValue = modelGroup.Default
+				matched = true
+			}
+		}
+
+		if !matched {
+			// keep any floor the publisher sent on the imp, converting it when needed
+			floorValue, err = impFloorInCurrency(imp.Imp, floorCur, conversions)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+
+		if floorMin > floorValue {
+			floorValue = floorMin
+		}
+
+		if floorValue <= 0 {
+			continue
+		}
+
+		floorValue = roundToPrecision(floorValue)
+		imp.BidFloor = floorValue
+		imp.BidFloorCur = floorCur
+		impFloors.FloorValue = floorValue
+
+		if err := setImpFloors(imp, impFloors); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+func impFloorInCurrency(imp *openrtb2.Imp, floorCur string, conversions currency.Conversions) (float64, error) {
+	if imp.BidFloor <= 0 {
+		return 0, nil
+	}
+	impCur := imp.BidFloorCur
+	if impCur == "" {
+		impCur = defaultCurrency
+	}
+	rate, err := conversions.GetRate(impCur, floorCur)
+	if err != nil {
+		return 0, err
+	}
+	return imp.BidFloor * rate, nil
+}
+
+func setImpFloors(imp *openrtb_ext.ImpWrapper, impFloors openrtb_ext.ExtImpPrebidFloors) error {
+	impExt, err := imp.GetImpExt()
+	if err != nil {
+		return err
+	}
+	prebid := impExt.GetOrCreatePrebid()
+	prebid.Floors = &impFloors
+	impExt.SetPrebid(prebid)
+	return nil
+}
+
+func roundToPrecision(value float64) float64 {
+	return math.Ceil(value*floorPrecisionFactor-1e-9) / floorPrecisionFactor
+}
+
+func toWarnings(errs []error) []error {
+	warnings := make([]error, 0, len(errs))
+	for _, err := range errs {
+		warnings = append(warnings, &errortypes.Warning{
+			Message:     err.Error(),
+			WarningCode: errortypes.FloorWarningCode,
+		})
+	}
+	return warnings
+}
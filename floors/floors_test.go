@@ -0,0 +1,224 @@
+package floors
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockFloorFetcher struct {
+	data   *openrtb_ext.PriceFloorData
+	status string
+}
+
+func (f *mockFloorFetcher) Fetch(configs config.AccountPriceFloors) (*openrtb_ext.PriceFloorData, string) {
+	return f.data, f.status
+}
+
+func withRandom(value int) func() {
+	original := randomIntn
+	randomIntn = func(n int) int {
+		if value >= n {
+			return n - 1
+		}
+		return value
+	}
+	return func() { randomIntn = original }
+}
+
+func floorsAccount() config.Account {
+	return config.Account{
+		PriceFloors: config.AccountPriceFloors{
+			Enabled:        true,
+			UseDynamicData: true,
+			MaxRule:        100,
+			MaxSchemaDims:  3,
+		},
+	}
+}
+
+func bannerRequest(ext string) *openrtb_ext.RequestWrapper {
+	return &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		ID:   "request",
+		Site: &openrtb2.Site{Domain: "www.test.com"},
+		Imp: []openrtb2.Imp{
+			{ID: "imp1", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}}},
+			{ID: "imp2", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 728, H: 90}}}, BidFloor: 0.5, BidFloorCur: "USD"},
+		},
+		Ext: json.RawMessage(ext),
+	}}
+}
+
+func getRequestFloors(t *testing.T, request *openrtb_ext.RequestWrapper) *openrtb_ext.PriceFloorRules {
+	assert.NoError(t, request.RebuildRequest())
+	var ext openrtb_ext.ExtRequest
+	assert.NoError(t, json.Unmarshal(request.Ext, &ext))
+	return ext.Prebid.Floors
+}
+
+func TestEnrichWithPriceFloorsFromRequest(t *testing.T) {
+	defer withRandom(50)()
+
+	request := bannerRequest(`{"prebid":{"floors":{"floormin":0.1,"data":{"currency":"USD","modelgroups":[{"schema":{"fields":["mediaType","size"]},"values":{"banner|300x250":1.5,"*|*":0.25}}]}}}}`)
+
+	errs := EnrichWithPriceFloors(request, floorsAccount(), currency.NewRates(nil), nil)
+	assert.Empty(t, errs)
+
+	floors := getRequestFloors(t, request)
+	assert.Equal(t, openrtb_ext.FloorLocationRequest, floors.PriceFloorLocation)
+	assert.False(t, floors.IsSkipped())
+
+	assert.Equal(t, 1.5, request.Imp[0].BidFloor)
+	assert.Equal(t, "USD", request.Imp[0].BidFloorCur)
+	assert.JSONEq(t, `{"prebid":{"floors":{"floorrule":"banner|300x250","floorrulevalue":1.5,"floorvalue":1.5,"floormin":0.1}}}`, string(request.Imp[0].Ext))
+
+	assert.Equal(t, 0.25, request.Imp[1].BidFloor)
+	assert.JSONEq(t, `{"prebid":{"floors":{"floorrule":"*|*","floorrulevalue":0.25,"floorvalue":0.25,"floormin":0.1}}}`, string(request.Imp[1].Ext))
+}
+
+func TestEnrichWithPriceFloorsSourcePrecedence(t *testing.T) {
+	defer withRandom(50)()
+
+	fetchedData := &openrtb_ext.PriceFloorData{
+		ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+			Schema: openrtb_ext.PriceFloorSchema{Fields: []string{MediaType}},
+			Values: map[string]float64{"banner": 3},
+		}},
+	}
+	accountData := &openrtb_ext.PriceFloorData{
+		ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+			Schema: openrtb_ext.PriceFloorSchema{Fields: []string{MediaType}},
+			Values: map[string]float64{"banner": 2},
+		}},
+	}
+	requestExt := `{"prebid":{"floors":{"data":{"modelgroups":[{"schema":{"fields":["mediaType"]},"values":{"banner":1}}]}}}}`
+
+	testCases := []struct {
+		name             string
+		requestExt       string
+		fetcher          FloorFetcher
+		accountData      *openrtb_ext.PriceFloorData
+		expectedLocation string
+		expectedFloor    float64
+	}{
+		{
+			name:             "fetched-data",
+			requestExt:       requestExt,
+			fetcher:          &mockFloorFetcher{data: fetchedData, status: openrtb_ext.FetchStatusSuccess},
+			accountData:      accountData,
+			expectedLocation: openrtb_ext.FloorLocationFetch,
+			expectedFloor:    3,
+		},
+		{
+			name:             "fetch-in-progress-uses-request",
+			requestExt:       requestExt,
+			fetcher:          &mockFloorFetcher{status: openrtb_ext.FetchStatusInprogress},
+			accountData:      accountData,
+			expectedLocation: openrtb_ext.FloorLocationRequest,
+			expectedFloor:    1,
+		},
+		{
+			name:             "account-data",
+			requestExt:       `{}`,
+			accountData:      accountData,
+			expectedLocation: openrtb_ext.FloorLocationAccount,
+			expectedFloor:    2,
+		},
+		{
+			name:             "no-data-keeps-imp-floor",
+			requestExt:       `{}`,
+			expectedLocation: openrtb_ext.FloorLocationNoData,
+			expectedFloor:    0.5,
+		},
+	}
+
+	for _, test := range testCases {
+		account := floorsAccount()
+		account.PriceFloors.Data = test.accountData
+		request := bannerRequest(test.requestExt)
+		request.Imp = request.Imp[1:]
+
+		errs := EnrichWithPriceFloors(request, account, currency.NewRates(nil), test.fetcher)
+		assert.Empty(t, errs, test.name)
+
+		floors := getRequestFloors(t, request)
+		assert.Equal(t, test.expectedLocation, floors.PriceFloorLocation, test.name)
+		assert.Equal(t, test.expectedFloor, request.Imp[0].BidFloor, test.name)
+	}
+}
+
+func TestEnrichWithPriceFloorsDisabled(t *testing.T) {
+	account := floorsAccount()
+	account.PriceFloors.Enabled = false
+	request := bannerRequest(`{"prebid":{"floors":{"data":{"modelgroups":[{"schema":{"fields":["mediaType"]},"values":{"banner":1}}]}}}}`)
+
+	assert.Empty(t, EnrichWithPriceFloors(request, account, currency.NewRates(nil), nil), "account-disabled")
+	assert.Equal(t, 0.0, request.Imp[0].BidFloor, "account-disabled")
+
+	request = bannerRequest(`{"prebid":{"floors":{"enabled":false,"data":{"modelgroups":[{"schema":{"fields":["mediaType"]},"values":{"banner":1}}]}}}}`)
+
+	assert.Empty(t, EnrichWithPriceFloors(request, floorsAccount(), currency.NewRates(nil), nil), "request-disabled")
+	assert.Equal(t, 0.0, request.Imp[0].BidFloor, "request-disabled")
+}
+
+func TestEnrichWithPriceFloorsSkipped(t *testing.T) {
+	defer withRandom(10)()
+
+	request := bannerRequest(`{"prebid":{"floors":{"skiprate":50,"data":{"modelgroups":[{"schema":{"fields":["mediaType"]},"values":{"banner":1}}]}}}}`)
+
+	errs := EnrichWithPriceFloors(request, floorsAccount(), currency.NewRates(nil), nil)
+	assert.Empty(t, errs)
+
+	floors := getRequestFloors(t, request)
+	assert.True(t, floors.IsSkipped())
+	assert.Equal(t, 0.0, request.Imp[0].BidFloor)
+}
+
+func TestEnrichWithPriceFloorsWarnings(t *testing.T) {
+	defer withRandom(50)()
+
+	request := bannerRequest(`{"prebid":{"floors":{"floormin":1,"floormincur":"JPY","data":{"modelgroups":[{"schema":{"fields":["unknownField"]},"values":{"banner":1}}]}}}}`)
+
+	errs := EnrichWithPriceFloors(request, floorsAccount(), currency.NewRates(nil), nil)
+	assert.Len(t, errs, 3)
+
+	floors := getRequestFloors(t, request)
+	assert.Equal(t, openrtb_ext.FloorLocationNoData, floors.PriceFloorLocation)
+}
+
+func TestSelectModelGroup(t *testing.T) {
+	weight10, weight90 := 10, 90
+	modelGroups := []openrtb_ext.PriceFloorModelGroup{
+		{ModelVersion: "first", ModelWeight: &weight10},
+		{ModelVersion: "second", ModelWeight: &weight90},
+	}
+
+	testCases := []struct {
+		name            string
+		random          int
+		expectedVersion string
+	}{
+		{name: "first", random: 5, expectedVersion: "first"},
+		{name: "boundary", random: 9, expectedVersion: "first"},
+		{name: "second", random: 10, expectedVersion: "second"},
+	}
+
+	for _, test := range testCases {
+		restore := withRandom(test.random)
+		modelGroup, ok := selectModelGroup(modelGroups)
+		restore()
+		assert.True(t, ok, test.name)
+		assert.Equal(t, test.expectedVersion, modelGroup.ModelVersion, test.name)
+	}
+}
+
+func TestRoundToPrecision(t *testing.T) {
+	assert.Equal(t, 1.01, roundToPrecision(1.001))
+	assert.Equal(t, 1.5, roundToPrecision(1.5))
+	assert.Equal(t, 0.24, roundToPrecision(0.235))
+}
@@ -0,0 +1,314 @@
+package floors
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// Schema fields supported in floor rules
+const (
+	SiteDomain string = "siteDomain"
+	PubDomain  string = "pubDomain"
+	Domain     string = "domain"
+	Bundle     string = "bundle"
+	Channel    string = "channel"
+	MediaType  string = "mediaType"
+	Size       string = "size"
+	GptSlot    string = "gptSlot"
+	AdUnitCode string = "adUnitCode"
+	Country    string = "country"
+	DeviceType string = "deviceType"
+)
+
+const (
+	catchAll          string = "*"
+	defaultDelimiter  string = "|"
+	defaultCurrency   string = "USD"
+	deviceTypePhone   string = "phone"
+	deviceTypeTablet  string = "tablet"
+	deviceTypeDesktop string = "desktop"
+)
+
+var supportedSchemaFields = map[string]struct{}{
+	SiteDomain: {},
+	PubDomain:  {},
+	Domain:     {},
+	Bundle:     {},
+	Channel:    {},
+	MediaType:  {},
+	Size:       {},
+	GptSlot:    {},
+	AdUnitCode: {},
+	Country:    {},
+	DeviceType: {},
+}
+
+// getFloorCurrency returns the currency of the model group, falling back to the data level
+// currency and then to USD
+func getFloorCurrency(data *openrtb_ext.PriceFloorData, modelGroup openrtb_ext.PriceFloorModelGroup) string {
+	if modelGroup.Currency != "" {
+		return modelGroup.Currency
+	}
+	if data != nil && data.Currency != "" {
+		return data.Currency
+	}
+	return defaultCurrency
+}
+
+// getMinFloorValue returns the floor minimum converted to the floor currency
+func getMinFloorValue(floors *openrtb_ext.PriceFloorRules, floorCur string, conversions currency.Conversions) (float64, error) {
+	floorMin := floors.FloorMin
+	floorMinCur := floors.FloorMinCur
+	if floorMin <= 0 || floorMinCur == "" || strings.EqualFold(floorMinCur, floorCur) {
+		return floorMin, nil
+	}
+
+	rate, err := conversions.GetRate(floorMinCur, floorCur)
+	if err != nil {
+		return 0, fmt.Errorf("Error in getting FloorMin value for currency %s to %s: %v", floorMinCur, floorCur, err)
+	}
+	return rate * floorMin, nil
+}
+
+// findRule looks up the most specific rule matching the desired values, preferring rules
+// which wildcard the rightmost schema fields first. Each rule is matched against the desired
+// values field by field, so the lookup grows with the number of rules rather than with every
+// combination of wildcarded fields.
+func findRule(values map[string]float64, delimiter string, desiredValues []string) (string, bool) {
+	bestRule, bestMask, found := "", uint(0), false
+	for rule := range values {
+		mask, ok := matchRule(rule, delimiter, desiredValues)
+		if !ok {
+			continue
+		}
+		if !found || moreSpecific(mask, bestMask) {
+			bestRule, bestMask, found = rule, mask, true
+		}
+	}
+	return bestRule, found
+}
+
+// matchRule returns whether the rule matches the desired values and a mask of the fields it
+// wildcards: bit (n-1-i) marks field i, so that wildcards in leftmost fields make a rule less
+// specific than wildcards in rightmost fields
+func matchRule(rule string, delimiter string, desiredValues []string) (uint, bool) {
+	parts := strings.Split(rule, delimiter)
+	if len(parts) != len(desiredValues) {
+		return 0, false
+	}
+
+	n := len(desiredValues)
+	var mask uint
+	for i, part := range parts {
+		switch part {
+		case desiredValues[i]:
+		case catchAll:
+			mask |= 1 << uint(n-1-i)
+		default:
+			return 0, false
+		}
+	}
+	return mask, true
+}
+
+// moreSpecific returns whether a rule wildcarding the fields of mask is more specific than one
+// wildcarding the fields of other
+func moreSpecific(mask uint, other uint) bool {
+	if count, otherCount := bits.OnesCount(mask), bits.OnesCount(other); count != otherCount {
+		return count < otherCount
+	}
+	return mask < other
+}
+
+// createRuleKey builds the values desired for the given imp in the order of the schema fields
+func createRuleKey(fields []string, request *openrtb2.BidRequest, imp *openrtb_ext.ImpWrapper) []string {
+	values := make([]string, 0, len(fields))
+	for _, field := range fields {
+		value := catchAll
+		switch field {
+		case MediaType:
+			value = getMediaType(imp.Imp)
+		case Size:
+			value = getSizeValue(imp.Imp)
+		case Domain:
+			value = getDomain(request)
+		case SiteDomain:
+			if request.Site != nil && request.Site.Domain != "" {
+				value = request.Site.Domain
+			}
+		case PubDomain:
+			value = getPublisherDomain(request)
+		case Bundle:
+			if request.App != nil && request.App.Bundle != "" {
+				value = request.App.Bundle
+			}
+		case Channel:
+			value = getChannelName(request)
+		case GptSlot:
+			value = getGptSlot(imp)
+		case AdUnitCode:
+			value = getAdUnitCode(imp)
+		case Country:
+			if request.Device != nil && request.Device.Geo != nil && request.Device.Geo.Country != "" {
+				value = request.Device.Geo.Country
+			}
+		case DeviceType:
+			value = getDeviceType(request)
+		}
+		values = append(values, strings.ToLower(value))
+	}
+	return values
+}
+
+func getMediaType(imp *openrtb2.Imp) string {
+	mediaType := catchAll
+	formatCount := 0
+
+	if imp.Banner != nil {
+		formatCount++
+		mediaType = string(openrtb_ext.BidTypeBanner)
+	}
+	if imp.Video != nil {
+		formatCount++
+		mediaType = string(openrtb_ext.BidTypeVideo)
+	}
+	if imp.Audio != nil {
+		formatCount++
+		mediaType = string(openrtb_ext.BidTypeAudio)
+	}
+	if imp.Native != nil {
+		formatCount++
+		mediaType = string(openrtb_ext.BidTypeNative)
+	}
+
+	if formatCount > 1 {
+		return catchAll
+	}
+	return mediaType
+}
+
+func getSizeValue(imp *openrtb2.Imp) string {
+	var width, height int64
+
+	if imp.Banner != nil {
+		if len(imp.Banner.Format) == 1 {
+			width, height = imp.Banner.Format[0].W, imp.Banner.Format[0].H
+		} else if len(imp.Banner.Format) == 0 && imp.Banner.W != nil && imp.Banner.H != nil {
+			width, height = *imp.Banner.W, *imp.Banner.H
+		}
+	} else if imp.Video != nil {
+		width, height = imp.Video.W, imp.Video.H
+	}
+
+	if width == 0 || height == 0 {
+		return catchAll
+	}
+	return fmt.Sprintf("%dx%d", width, height)
+}
+
+func getDomain(request *openrtb2.BidRequest) string {
+	if request.Site != nil && request.Site.Domain != "" {
+		return request.Site.Domain
+	}
+	if request.App != nil && request.App.Domain != "" {
+		return request.App.Domain
+	}
+	return catchAll
+}
+
+func getPublisherDomain(request *openrtb2.BidRequest) string {
+	if request.Site != nil && request.Site.Publisher != nil && request.Site.Publisher.Domain != "" {
+		return request.Site.Publisher.Domain
+	}
+	if request.App != nil && request.App.Publisher != nil && request.App.Publisher.Domain != "" {
+		return request.App.Publisher.Domain
+	}
+	return catchAll
+}
+
+func getChannelName(request *openrtb2.BidRequest) string {
+	var reqExt struct {
+		Prebid struct {
+			Channel *openrtb_ext.ExtRequestPrebidChannel `json:"channel"`
+		} `json:"prebid"`
+	}
+	if len(request.Ext) > 0 && json.Unmarshal(request.Ext, &reqExt) == nil && reqExt.Prebid.Channel != nil && reqExt.Prebid.Channel.Name != "" {
+		return reqExt.Prebid.Channel.Name
+	}
+	return catchAll
+}
+
+// impExtData holds the first party data fields of imp.ext.data used to resolve slot based schema fields
+type impExtData struct {
+	PbAdSlot string `json:"pbadslot"`
+	AdServer *struct {
+		Name   string `json:"name"`
+		AdSlot string `json:"adslot"`
+	} `json:"adserver"`
+}
+
+func getImpExtData(imp *openrtb_ext.ImpWrapper) (impExtData, map[string]json.RawMessage) {
+	var data impExtData
+	impExt, err := imp.GetImpExt()
+	if err != nil {
+		return data, nil
+	}
+	ext := impExt.GetExt()
+	if dataJSON, ok := ext[openrtb_ext.FirstPartyDataExtKey]; ok {
+		json.Unmarshal(dataJSON, &data)
+	}
+	return data, ext
+}
+
+func getGptSlot(imp *openrtb_ext.ImpWrapper) string {
+	data, _ := getImpExtData(imp)
+	if data.AdServer != nil && strings.EqualFold(data.AdServer.Name, "gam") && data.AdServer.AdSlot != "" {
+		return data.AdServer.AdSlot
+	}
+	if data.PbAdSlot != "" {
+		return data.PbAdSlot
+	}
+	return catchAll
+}
+
+func getAdUnitCode(imp *openrtb_ext.ImpWrapper) string {
+	data, ext := getImpExtData(imp)
+
+	var gpid string
+	if gpidJSON, ok := ext[openrtb_ext.GPIDKey]; ok && json.Unmarshal(gpidJSON, &gpid) == nil && gpid != "" {
+		return gpid
+	}
+	if imp.TagID != "" {
+		return imp.TagID
+	}
+	if data.PbAdSlot != "" {
+		return data.PbAdSlot
+	}
+	if impExt, err := imp.GetImpExt(); err == nil {
+		if prebid := impExt.GetPrebid(); prebid != nil && prebid.StoredRequest != nil && prebid.StoredRequest.ID != "" {
+			return prebid.StoredRequest.ID
+		}
+	}
+	return catchAll
+}
+
+func getDeviceType(request *openrtb2.BidRequest) string {
+	if request.Device == nil {
+		return catchAll
+	}
+	switch request.Device.DeviceType {
+	case 1, 4:
+		return deviceTypePhone
+	case 5:
+		return deviceTypeTablet
+	case 2:
+		return deviceTypeDesktop
+	}
+	return catchAll
+}
@@ -0,0 +1,165 @@
+package floors
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindRulePrecedence(t *testing.T) {
+	desiredValues := []string{"banner", "300x250", "www.test.com"}
+	// from the least to the most specific rule matching the desired values
+	rules := []string{
+		"*|*|*",
+		"*|*|www.test.com",
+		"*|300x250|*",
+		"banner|*|*",
+		"*|300x250|www.test.com",
+		"banner|*|www.test.com",
+		"banner|300x250|*",
+		"banner|300x250|www.test.com",
+	}
+
+	values := map[string]float64{"video|*|*": 1, "banner|728x90|*": 1}
+	for _, rule := range rules {
+		values[rule] = 1
+		found, ok := findRule(values, defaultDelimiter, desiredValues)
+		assert.True(t, ok, rule)
+		assert.Equal(t, rule, found, rule)
+	}
+
+	rule, ok := findRule(map[string]float64{"*|300x250": 1, "*|*": 1}, defaultDelimiter, []string{"*", "300x250"})
+	assert.True(t, ok, "catch-all-value")
+	assert.Equal(t, "*|300x250", rule, "catch-all-value")
+}
+
+func TestFindRule(t *testing.T) {
+	values := map[string]float64{
+		"banner|300x250|www.test.com": 1.01,
+		"banner|*|www.test.com":       2.01,
+		"*|*|*":                       3.01,
+	}
+
+	testCases := []struct {
+		name          string
+		desiredValues []string
+		expectedRule  string
+		expectedFound bool
+	}{
+		{
+			name:          "exact-match",
+			desiredValues: []string{"banner", "300x250", "www.test.com"},
+			expectedRule:  "banner|300x250|www.test.com",
+			expectedFound: true,
+		},
+		{
+			name:          "wildcard-match",
+			desiredValues: []string{"banner", "728x90", "www.test.com"},
+			expectedRule:  "banner|*|www.test.com",
+			expectedFound: true,
+		},
+		{
+			name:          "catch-all-match",
+			desiredValues: []string{"video", "640x480", "www.other.com"},
+			expectedRule:  "*|*|*",
+			expectedFound: true,
+		},
+	}
+
+	for _, test := range testCases {
+		rule, found := findRule(values, defaultDelimiter, test.desiredValues)
+		assert.Equal(t, test.expectedRule, rule, test.name)
+		assert.Equal(t, test.expectedFound, found, test.name)
+	}
+
+	_, found := findRule(map[string]float64{"banner|300x250": 1}, defaultDelimiter, []string{"video", "640x480"})
+	assert.False(t, found, "no-match")
+}
+
+func TestCreateRuleKey(t *testing.T) {
+	width, height := int64(300), int64(250)
+	request := &openrtb2.BidRequest{
+		Site: &openrtb2.Site{
+			Domain:    "www.Test.com",
+			Publisher: &openrtb2.Publisher{Domain: "test.com"},
+		},
+		Device: &openrtb2.Device{DeviceType: 4, Geo: &openrtb2.Geo{Country: "USA"}},
+		Ext:    json.RawMessage(`{"prebid":{"channel":{"name":"web","version":"1.0"}}}`),
+	}
+	imp := &openrtb_ext.ImpWrapper{Imp: &openrtb2.Imp{
+		ID:     "imp1",
+		TagID:  "tag-1",
+		Banner: &openrtb2.Banner{W: &width, H: &height},
+		Ext:    json.RawMessage(`{"data":{"adserver":{"name":"gam","adslot":"/1111/home"},"pbadslot":"pb-slot"}}`),
+	}}
+
+	fields := []string{MediaType, Size, SiteDomain, PubDomain, Domain, Bundle, Channel, GptSlot, AdUnitCode, Country, DeviceType}
+	expected := []string{"banner", "300x250", "www.test.com", "test.com", "www.test.com", "*", "web", "/1111/home", "tag-1", "usa", "phone"}
+
+	assert.Equal(t, expected, createRuleKey(fields, request, imp))
+}
+
+func TestGetMediaType(t *testing.T) {
+	testCases := []struct {
+		name     string
+		imp      *openrtb2.Imp
+		expected string
+	}{
+		{name: "banner", imp: &openrtb2.Imp{Banner: &openrtb2.Banner{}}, expected: "banner"},
+		{name: "video", imp: &openrtb2.Imp{Video: &openrtb2.Video{}}, expected: "video"},
+		{name: "native", imp: &openrtb2.Imp{Native: &openrtb2.Native{}}, expected: "native"},
+		{name: "multi-format", imp: &openrtb2.Imp{Banner: &openrtb2.Banner{}, Video: &openrtb2.Video{}}, expected: "*"},
+		{name: "none", imp: &openrtb2.Imp{}, expected: "*"},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, getMediaType(test.imp), test.name)
+	}
+}
+
+func TestGetMinFloorValue(t *testing.T) {
+	conversions := currency.NewRates(map[string]map[string]float64{"EUR": {"USD": 1.2}})
+
+	testCases := []struct {
+		name          string
+		floors        *openrtb_ext.PriceFloorRules
+		floorCur      string
+		expectedValue float64
+		expectError   bool
+	}{
+		{
+			name:          "same-currency",
+			floors:        &openrtb_ext.PriceFloorRules{FloorMin: 1, FloorMinCur: "USD"},
+			floorCur:      "USD",
+			expectedValue: 1,
+		},
+		{
+			name:          "no-currency",
+			floors:        &openrtb_ext.PriceFloorRules{FloorMin: 1},
+			floorCur:      "EUR",
+			expectedValue: 1,
+		},
+		{
+			name:          "converted",
+			floors:        &openrtb_ext.PriceFloorRules{FloorMin: 1, FloorMinCur: "EUR"},
+			floorCur:      "USD",
+			expectedValue: 1.2,
+		},
+		{
+			name:        "unknown-rate",
+			floors:      &openrtb_ext.PriceFloorRules{FloorMin: 1, FloorMinCur: "JPY"},
+			floorCur:    "USD",
+			expectError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		value, err := getMinFloorValue(test.floors, test.floorCur, conversions)
+		assert.Equal(t, test.expectedValue, value, test.name)
+		assert.Equal(t, test.expectError, err != nil, test.name)
+	}
+}
@@ -0,0 +1,128 @@
+package floors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+const (
+	minSkipRate    = 0
+	maxSkipRate    = 100
+	minModelWeight = 1
+	maxModelWeight = 100
+	minEnforceRate = 0
+	maxEnforceRate = 100
+	// defaultMaxSchemaDims is the limit of schema fields when the account doesn't set one, and
+	// maxSchemaDims the limit no account can exceed
+	defaultMaxSchemaDims = 3
+	maxSchemaDims        = 6
+)
+
+// validateFloorRules validates the request level floor settings
+func validateFloorRules(floors *openrtb_ext.PriceFloorRules) error {
+	if floors.SkipRate < minSkipRate || floors.SkipRate > maxSkipRate {
+		return fmt.Errorf("Invalid SkipRate = '%v' at ext.prebid.floors.skiprate", floors.SkipRate)
+	}
+	if floors.FloorMin < 0 {
+		return fmt.Errorf("Invalid FloorMin = '%v', value should be >= 0", floors.FloorMin)
+	}
+	if enforceRate := floors.GetEnforceRate(); enforceRate < minEnforceRate || enforceRate > maxEnforceRate {
+		return fmt.Errorf("Invalid EnforceRate = '%v' at ext.prebid.floors.enforcement.enforcerate", enforceRate)
+	}
+	return nil
+}
+
+// validateFloorData validates the floor data and the model groups it contains. Invalid model
+// groups are discarded; an error is returned for each of them. maxRules is only enforced when
+// positive; a maxDims of 0 uses the default limit and no limit can exceed maxSchemaDims.
+func validateFloorData(data *openrtb_ext.PriceFloorData, maxRules, maxDims int) []error {
+	var errs []error
+
+	if data.SkipRate < minSkipRate || data.SkipRate > maxSkipRate {
+		data.ModelGroups = nil
+		return []error{fmt.Errorf("Invalid SkipRate = '%v' at ext.prebid.floors.data.skiprate", data.SkipRate)}
+	}
+
+	validModelGroups := make([]openrtb_ext.PriceFloorModelGroup, 0, len(data.ModelGroups))
+	for i, modelGroup := range data.ModelGroups {
+		if err := validateModelGroup(modelGroup, maxRules, maxDims); err != nil {
+			errs = append(errs, fmt.Errorf("%v at ext.prebid.floors.data.modelgroups[%d]", err, i))
+			continue
+		}
+		validModelGroups = append(validModelGroups, normalizeModelGroup(modelGroup))
+	}
+	data.ModelGroups = validModelGroups
+
+	if len(data.ModelGroups) == 0 {
+		errs = append(errs, fmt.Errorf("No valid model group found in floor data"))
+	}
+	return errs
+}
+
+func validateModelGroup(modelGroup openrtb_ext.PriceFloorModelGroup, maxRules, maxDims int) error {
+	if modelGroup.SkipRate < minSkipRate || modelGroup.SkipRate > maxSkipRate {
+		return fmt.Errorf("Invalid SkipRate = '%v'", modelGroup.SkipRate)
+	}
+	if modelGroup.ModelWeight != nil && (*modelGroup.ModelWeight < minModelWeight || *modelGroup.ModelWeight > maxModelWeight) {
+		return fmt.Errorf("Invalid ModelWeight = '%v'", *modelGroup.ModelWeight)
+	}
+	if modelGroup.Default < 0 {
+		return fmt.Errorf("Invalid Default = '%v'", modelGroup.Default)
+	}
+	if len(modelGroup.Schema.Fields) == 0 {
+		return fmt.Errorf("Missing schema fields")
+	}
+	if maxDims <= 0 {
+		maxDims = defaultMaxSchemaDims
+	}
+	if maxDims > maxSchemaDims {
+		maxDims = maxSchemaDims
+	}
+	if len(modelGroup.Schema.Fields) > maxDims {
+		return fmt.Errorf("Number of schema fields %d exceeds the limit of %d", len(modelGroup.Schema.Fields), maxDims)
+	}
+	seenFields := make(map[string]struct{}, len(modelGroup.Schema.Fields))
+	for _, field := range modelGroup.Schema.Fields {
+		if _, ok := supportedSchemaFields[field]; !ok {
+			return fmt.Errorf("Unsupported schema field '%s'", field)
+		}
+		if _, ok := seenFields[field]; ok {
+			return fmt.Errorf("Duplicate schema field '%s'", field)
+		}
+		seenFields[field] = struct{}{}
+	}
+	if maxRules > 0 && len(modelGroup.Values) > maxRules {
+		return fmt.Errorf("Number of rules %d exceeds the limit of %d", len(modelGroup.Values), maxRules)
+	}
+
+	delimiter := getDelimiter(modelGroup.Schema)
+	for rule, value := range modelGroup.Values {
+		if value < 0 {
+			return fmt.Errorf("Invalid floor value = '%v' for rule '%s'", value, rule)
+		}
+		if parts := strings.Split(rule, delimiter); len(parts) != len(modelGroup.Schema.Fields) {
+			return fmt.Errorf("Rule '%s' does not match the %d schema fields", rule, len(modelGroup.Schema.Fields))
+		}
+	}
+	return nil
+}
+
+// normalizeModelGroup lower cases the rule keys so that they can be matched against the lower
+// cased values built from the request
+func normalizeModelGroup(modelGroup openrtb_ext.PriceFloorModelGroup) openrtb_ext.PriceFloorModelGroup {
+	values := make(map[string]float64, len(modelGroup.Values))
+	for rule, value := range modelGroup.Values {
+		values[strings.ToLower(rule)] = value
+	}
+	modelGroup.Values = values
+	return modelGroup
+}
+
+func getDelimiter(schema openrtb_ext.PriceFloorSchema) string {
+	if schema.Delimiter != "" {
+		return schema.Delimiter
+	}
+	return defaultDelimiter
+}
@@ -0,0 +1,137 @@
+package floors
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFloorRules(t *testing.T) {
+	testCases := []struct {
+		name        string
+		floors      *openrtb_ext.PriceFloorRules
+		expectError bool
+	}{
+		{
+			name:   "valid",
+			floors: &openrtb_ext.PriceFloorRules{SkipRate: 10, FloorMin: 1, Enforcement: &openrtb_ext.PriceFloorEnforcement{EnforceRate: 50}},
+		},
+		{
+			name:        "invalid-skip-rate",
+			floors:      &openrtb_ext.PriceFloorRules{SkipRate: 101},
+			expectError: true,
+		},
+		{
+			name:        "invalid-floor-min",
+			floors:      &openrtb_ext.PriceFloorRules{FloorMin: -1},
+			expectError: true,
+		},
+		{
+			name:        "invalid-enforce-rate",
+			floors:      &openrtb_ext.PriceFloorRules{Enforcement: &openrtb_ext.PriceFloorEnforcement{EnforceRate: 101}},
+			expectError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		err := validateFloorRules(test.floors)
+		assert.Equal(t, test.expectError, err != nil, test.name)
+	}
+}
+
+func TestValidateFloorData(t *testing.T) {
+	invalidWeight := 0
+
+	testCases := []struct {
+		name                string
+		data                *openrtb_ext.PriceFloorData
+		maxRules            int
+		maxDims             int
+		expectedModelGroups []openrtb_ext.PriceFloorModelGroup
+		expectedErrCount    int
+	}{
+		{
+			name: "valid-values-lower-cased",
+			data: &openrtb_ext.PriceFloorData{ModelGroups: []openrtb_ext.PriceFloorModelGroup{
+				{Schema: openrtb_ext.PriceFloorSchema{Fields: []string{MediaType, SiteDomain}}, Values: map[string]float64{"Banner|WWW.Test.com": 1}},
+			}},
+			expectedModelGroups: []openrtb_ext.PriceFloorModelGroup{
+				{Schema: openrtb_ext.PriceFloorSchema{Fields: []string{MediaType, SiteDomain}}, Values: map[string]float64{"banner|www.test.com": 1}},
+			},
+		},
+		{
+			name: "invalid-model-group-dropped",
+			data: &openrtb_ext.PriceFloorData{ModelGroups: []openrtb_ext.PriceFloorModelGroup{
+				{ModelWeight: &invalidWeight, Schema: openrtb_ext.PriceFloorSchema{Fields: []string{MediaType}}, Values: map[string]float64{"banner": 1}},
+				{Schema: openrtb_ext.PriceFloorSchema{Fields: []string{MediaType}}, Values: map[string]float64{"video": 2}},
+			}},
+			expectedModelGroups: []openrtb_ext.PriceFloorModelGroup{
+				{Schema: openrtb_ext.PriceFloorSchema{Fields: []string{MediaType}}, Values: map[string]float64{"video": 2}},
+			},
+			expectedErrCount: 1,
+		},
+		{
+			name: "rule-does-not-match-schema",
+			data: &openrtb_ext.PriceFloorData{ModelGroups: []openrtb_ext.PriceFloorModelGroup{
+				{Schema: openrtb_ext.PriceFloorSchema{Fields: []string{MediaType, Size}}, Values: map[string]float64{"banner": 1}},
+			}},
+			expectedModelGroups: []openrtb_ext.PriceFloorModelGroup{},
+			expectedErrCount:    2,
+		},
+		{
+			name: "too-many-rules",
+			data: &openrtb_ext.PriceFloorData{ModelGroups: []openrtb_ext.PriceFloorModelGroup{
+				{Schema: openrtb_ext.PriceFloorSchema{Fields: []string{MediaType}}, Values: map[string]float64{"banner": 1, "video": 2}},
+			}},
+			maxRules:            1,
+			expectedModelGroups: []openrtb_ext.PriceFloorModelGroup{},
+			expectedErrCount:    2,
+		},
+		{
+			name: "too-many-schema-fields",
+			data: &openrtb_ext.PriceFloorData{ModelGroups: []openrtb_ext.PriceFloorModelGroup{
+				{Schema: openrtb_ext.PriceFloorSchema{Fields: []string{MediaType, Size}}, Values: map[string]float64{"banner|300x250": 1}},
+			}},
+			maxDims:             1,
+			expectedModelGroups: []openrtb_ext.PriceFloorModelGroup{},
+			expectedErrCount:    2,
+		},
+		{
+			name: "default-schema-fields-limit",
+			data: &openrtb_ext.PriceFloorData{ModelGroups: []openrtb_ext.PriceFloorModelGroup{
+				{Schema: openrtb_ext.PriceFloorSchema{Fields: []string{MediaType, Size, Domain, Country}}, Values: map[string]float64{"banner|300x250|www.test.com|usa": 1}},
+			}},
+			expectedModelGroups: []openrtb_ext.PriceFloorModelGroup{},
+			expectedErrCount:    2,
+		},
+		{
+			name: "schema-fields-limit-capped",
+			data: &openrtb_ext.PriceFloorData{ModelGroups: []openrtb_ext.PriceFloorModelGroup{
+				{Schema: openrtb_ext.PriceFloorSchema{Fields: []string{MediaType, Size, Domain, Country, Bundle, Channel, GptSlot}}, Values: map[string]float64{"*|*|*|*|*|*|*": 1}},
+			}},
+			maxDims:             20,
+			expectedModelGroups: []openrtb_ext.PriceFloorModelGroup{},
+			expectedErrCount:    2,
+		},
+		{
+			name: "duplicate-schema-fields",
+			data: &openrtb_ext.PriceFloorData{ModelGroups: []openrtb_ext.PriceFloorModelGroup{
+				{Schema: openrtb_ext.PriceFloorSchema{Fields: []string{MediaType, MediaType}}, Values: map[string]float64{"banner|banner": 1}},
+			}},
+			expectedModelGroups: []openrtb_ext.PriceFloorModelGroup{},
+			expectedErrCount:    2,
+		},
+		{
+			name:             "invalid-data-skip-rate",
+			data:             &openrtb_ext.PriceFloorData{SkipRate: -1},
+			expectedErrCount: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		errs := validateFloorData(test.data, test.maxRules, test.maxDims)
+		assert.Len(t, errs, test.expectedErrCount, test.name)
+		assert.Equal(t, test.expectedModelGroups, test.data.ModelGroups, test.name)
+	}
+}
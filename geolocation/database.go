@@ -0,0 +1,99 @@
+package geolocation
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/golang/glog"
+)
+
+var errDatabaseNotLoaded = errors.New("the geolocation database is not loaded")
+
+// Database resolves IP addresses with a local MaxMind DB file, such as GeoLite2 City. It implements
+// task.Runner so the file can be reloaded periodically; a failed reload keeps the previous database.
+type Database struct {
+	path   string
+	reader atomic.Value // Should only hold *mmdbReader
+}
+
+func NewDatabase(path string) *Database {
+	return &Database{path: path}
+}
+
+// Run loads the database file
+func (db *Database) Run() error {
+	buffer, err := os.ReadFile(db.path)
+	if err != nil {
+		glog.Errorf("Error reading the geolocation database %s: %v", db.path, err)
+		return err
+	}
+	reader, err := newMMDBReader(buffer)
+	if err != nil {
+		glog.Errorf("Error loading the geolocation database %s: %v", db.path, err)
+		return err
+	}
+	db.reader.Store(reader)
+	return nil
+}
+
+func (db *Database) Lookup(ip string) (*GeoInfo, error) {
+	reader, ok := db.reader.Load().(*mmdbReader)
+	if !ok {
+		return nil, errDatabaseNotLoaded
+	}
+
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil {
+		return nil, fmt.Errorf("invalid ip address %s", ip)
+	}
+
+	record, err := reader.lookup(parsedIP)
+	if err != nil {
+		return nil, err
+	}
+	return toGeoInfo(record), nil
+}
+
+// toGeoInfo reads the fields of a GeoIP2 or GeoLite2 record
+func toGeoInfo(record interface{}) *GeoInfo {
+	countryCode, _ := lookupPath(record, "country", "iso_code").(string)
+	if countryCode == "" {
+		countryCode, _ = lookupPath(record, "registered_country", "iso_code").(string)
+	}
+	country, ok := countryAlpha3[countryCode]
+	if !ok {
+		return nil
+	}
+
+	info := &GeoInfo{Country: country}
+	info.Region, _ = lookupPath(record, "subdivisions", 0, "iso_code").(string)
+	info.City, _ = lookupPath(record, "city", "names", "en").(string)
+	if metro, ok := lookupPath(record, "location", "metro_code").(uint64); ok && metro > 0 {
+		info.Metro = strconv.FormatUint(metro, 10)
+	}
+	return info
+}
+
+func lookupPath(value interface{}, path ...interface{}) interface{} {
+	for _, key := range path {
+		switch k := key.(type) {
+		case string:
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			value = m[k]
+		case int:
+			a, ok := value.([]interface{})
+			if !ok || k >= len(a) {
+				return nil
+			}
+			value = a[k]
+		}
+	}
+	return value
+}
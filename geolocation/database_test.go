@@ -0,0 +1,106 @@
+package geolocation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabaseLookup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "city.mmdb")
+	err := os.WriteFile(path, buildTestMMDB(t, 6, 28, []testNetwork{
+		{cidr: "8.8.8.0/24", record: map[string]interface{}{
+			"country":      map[string]interface{}{"iso_code": "US"},
+			"subdivisions": []interface{}{map[string]interface{}{"iso_code": "CA"}},
+			"city":         map[string]interface{}{"names": map[string]interface{}{"en": "Mountain View", "de": "Mountain View"}},
+			"location":     map[string]interface{}{"metro_code": uint64(807), "latitude": 37.4},
+		}},
+		{cidr: "2a00:1450::/32", record: map[string]interface{}{
+			"registered_country": map[string]interface{}{"iso_code": "IE"},
+		}},
+		{cidr: "10.0.0.0/8", record: map[string]interface{}{
+			"country": map[string]interface{}{"iso_code": "ZZ"},
+		}},
+	}), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write the test database: %v", err)
+	}
+
+	db := NewDatabase(path)
+	_, err = db.Lookup("8.8.8.8")
+	assert.Equal(t, errDatabaseNotLoaded, err, "Lookup before the database is loaded")
+
+	if !assert.NoError(t, db.Run()) {
+		return
+	}
+
+	testCases := []struct {
+		description   string
+		ip            string
+		expectedInfo  *GeoInfo
+		expectedError string
+	}{
+		{
+			description:  "All fields",
+			ip:           "8.8.8.8",
+			expectedInfo: &GeoInfo{Country: "USA", Region: "CA", Metro: "807", City: "Mountain View"},
+		},
+		{
+			description:  "Registered country",
+			ip:           "2a00:1450:4001::1",
+			expectedInfo: &GeoInfo{Country: "IRL"},
+		},
+		{
+			description: "Unknown country code",
+			ip:          "10.1.1.1",
+		},
+		{
+			description: "Unknown ip",
+			ip:          "9.9.9.9",
+		},
+		{
+			description:   "Invalid ip",
+			ip:            "not an ip",
+			expectedError: "invalid ip address not an ip",
+		},
+	}
+
+	for _, test := range testCases {
+		info, err := db.Lookup(test.ip)
+
+		if test.expectedError != "" {
+			assert.EqualError(t, err, test.expectedError, test.description)
+		} else {
+			assert.NoError(t, err, test.description)
+		}
+		assert.Equal(t, test.expectedInfo, info, test.description)
+	}
+}
+
+func TestDatabaseReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "city.mmdb")
+	writeDatabase := func(countryCode string) {
+		err := os.WriteFile(path, buildTestMMDB(t, 4, 24, []testNetwork{
+			{cidr: "8.8.8.0/24", record: map[string]interface{}{"country": map[string]interface{}{"iso_code": countryCode}}},
+		}), 0644)
+		if err != nil {
+			t.Fatalf("Failed to write the test database: %v", err)
+		}
+	}
+
+	db := NewDatabase(path)
+	writeDatabase("US")
+	assert.NoError(t, db.Run())
+
+	writeDatabase("CA")
+	assert.NoError(t, db.Run())
+	info, _ := db.Lookup("8.8.8.8")
+	assert.Equal(t, &GeoInfo{Country: "CAN"}, info, "Reloaded database")
+
+	os.WriteFile(path, []byte("corrupted"), 0644)
+	assert.Error(t, db.Run())
+	info, _ = db.Lookup("8.8.8.8")
+	assert.Equal(t, &GeoInfo{Country: "CAN"}, info, "Failed reloads keep the previous database")
+}
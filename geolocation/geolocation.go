@@ -0,0 +1,18 @@
+package geolocation
+
+// GeoInfo is the location of an IP address, in the formats used by the OpenRTB geo object
+type GeoInfo struct {
+	// Country is the ISO-3166-1 alpha-3 country code
+	Country string
+	// Region is the ISO-3166-2 subdivision code, without the country prefix
+	Region string
+	// Metro is the Google metro code
+	Metro string
+	City  string
+}
+
+// GeoLocation resolves IP addresses to their location
+type GeoLocation interface {
+	// Lookup returns the location of the ip, or nil if it is unknown
+	Lookup(ip string) (*GeoInfo, error)
+}
@@ -0,0 +1,319 @@
+package geolocation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+)
+
+// The MaxMind DB format is described at https://maxmind.github.io/MaxMind-DB/. Only the parts needed to look
+// up an IP address and decode its record are implemented.
+
+var metadataStartMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+const dataSectionSeparatorSize = 16
+
+const (
+	mmdbExtended  = 0
+	mmdbPointer   = 1
+	mmdbString    = 2
+	mmdbDouble    = 3
+	mmdbBytes     = 4
+	mmdbUint16    = 5
+	mmdbUint32    = 6
+	mmdbMap       = 7
+	mmdbInt32     = 8
+	mmdbUint64    = 9
+	mmdbUint128   = 10
+	mmdbArray     = 11
+	mmdbContainer = 12
+	mmdbEndMarker = 13
+	mmdbBool      = 14
+	mmdbFloat     = 15
+)
+
+// mmdbReader looks up IP addresses in a database held in memory
+type mmdbReader struct {
+	nodeCount   uint
+	recordSize  uint
+	ipVersion   uint
+	tree        []byte
+	data        []byte
+	ipv4Start   uint
+	ipv4Bits    uint
+	nodeByteLen uint
+}
+
+func newMMDBReader(buffer []byte) (*mmdbReader, error) {
+	metadataStart := bytes.LastIndex(buffer, metadataStartMarker)
+	if metadataStart == -1 {
+		return nil, errors.New("invalid MaxMind DB file: metadata not found")
+	}
+	metadataStart += len(metadataStartMarker)
+
+	metadataDecoder := mmdbDecoder{buffer: buffer[metadataStart:]}
+	value, _, err := metadataDecoder.decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MaxMind DB metadata: %v", err)
+	}
+	metadata, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid MaxMind DB metadata: not a map")
+	}
+
+	reader := &mmdbReader{
+		nodeCount:  uintValue(metadata["node_count"]),
+		recordSize: uintValue(metadata["record_size"]),
+		ipVersion:  uintValue(metadata["ip_version"]),
+	}
+	switch reader.recordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("unsupported MaxMind DB record size %d", reader.recordSize)
+	}
+	if reader.ipVersion != 4 && reader.ipVersion != 6 {
+		return nil, fmt.Errorf("unsupported MaxMind DB ip version %d", reader.ipVersion)
+	}
+
+	reader.nodeByteLen = reader.recordSize / 4
+	treeSize := reader.nodeCount * reader.nodeByteLen
+	dataStart := treeSize + dataSectionSeparatorSize
+	if dataStart > uint(metadataStart-len(metadataStartMarker)) {
+		return nil, errors.New("invalid MaxMind DB file: search tree is larger than the file")
+	}
+	reader.tree = buffer[:treeSize]
+	reader.data = buffer[dataStart : metadataStart-len(metadataStartMarker)]
+
+	// IPv4 addresses are stored under ::/96 in IPv6 databases
+	if reader.ipVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < reader.nodeCount; i++ {
+			node = reader.readRecord(node, 0)
+		}
+		reader.ipv4Start = node
+		reader.ipv4Bits = 96
+	}
+	return reader, nil
+}
+
+// lookup returns the record stored for the ip, or nil if the database doesn't have one
+func (r *mmdbReader) lookup(ip net.IP) (interface{}, error) {
+	node, bitCount := uint(0), uint(128)
+	if ipv4 := ip.To4(); ipv4 != nil {
+		ip = ipv4
+		node, bitCount = r.ipv4Start, 32
+	} else if r.ipVersion == 4 {
+		return nil, errors.New("IPv6 address lookup in an IPv4 only database")
+	}
+
+	for i := uint(0); i < bitCount && node < r.nodeCount; i++ {
+		bit := uint(ip[i>>3]>>(7-(i%8))) & 1
+		node = r.readRecord(node, bit)
+	}
+
+	if node == r.nodeCount {
+		return nil, nil
+	}
+	if node < r.nodeCount {
+		return nil, errors.New("invalid MaxMind DB file: search tree is too deep")
+	}
+
+	offset := node - r.nodeCount - dataSectionSeparatorSize
+	decoder := mmdbDecoder{buffer: r.data}
+	value, _, err := decoder.decode(offset, 0)
+	return value, err
+}
+
+func (r *mmdbReader) readRecord(node uint, bit uint) uint {
+	offset := node * r.nodeByteLen
+	b := r.tree[offset : offset+r.nodeByteLen]
+	switch r.recordSize {
+	case 24:
+		b = b[bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		return uint(binary.BigEndian.Uint32(b[bit*4:]))
+	}
+}
+
+// maxDecodeDepth protects against maliciously nested data
+const maxDecodeDepth = 32
+
+type mmdbDecoder struct {
+	buffer []byte
+}
+
+// decode decodes the value at the offset and returns it along with the offset of the next value. Maps are
+// decoded as map[string]interface{}, arrays as []interface{} and all unsigned integers as uint64.
+func (d *mmdbDecoder) decode(offset uint, depth int) (interface{}, uint, error) {
+	if depth > maxDecodeDepth {
+		return nil, 0, errors.New("data is nested too deep")
+	}
+
+	dataType, size, offset, err := d.decodeControl(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if dataType == mmdbPointer {
+		pointer, next, err := d.decodePointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(pointer, depth+1)
+		return value, next, err
+	}
+
+	switch dataType {
+	case mmdbMap:
+		values := make(map[string]interface{}, size)
+		for i := uint(0); i < size; i++ {
+			var key, value interface{}
+			if key, offset, err = d.decode(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+			keyString, ok := key.(string)
+			if !ok {
+				return nil, 0, errors.New("map key is not a string")
+			}
+			if value, offset, err = d.decode(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+			values[keyString] = value
+		}
+		return values, offset, nil
+	case mmdbArray:
+		values := make([]interface{}, 0, size)
+		for i := uint(0); i < size; i++ {
+			var value interface{}
+			if value, offset, err = d.decode(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+			values = append(values, value)
+		}
+		return values, offset, nil
+	case mmdbBool:
+		return size != 0, offset, nil
+	}
+
+	if offset+size > uint(len(d.buffer)) {
+		return nil, 0, errors.New("unexpected end of data")
+	}
+	b := d.buffer[offset : offset+size]
+	next := offset + size
+
+	switch dataType {
+	case mmdbString:
+		return string(b), next, nil
+	case mmdbBytes, mmdbUint128:
+		return append([]byte(nil), b...), next, nil
+	case mmdbDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("invalid double size %d", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), next, nil
+	case mmdbFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("invalid float size %d", size)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), next, nil
+	case mmdbUint16, mmdbUint32, mmdbUint64:
+		if size > 8 {
+			return nil, 0, fmt.Errorf("invalid unsigned integer size %d", size)
+		}
+		var value uint64
+		for _, c := range b {
+			value = value<<8 | uint64(c)
+		}
+		return value, next, nil
+	case mmdbInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("invalid int32 size %d", size)
+		}
+		var value uint32
+		for _, c := range b {
+			value = value<<8 | uint32(c)
+		}
+		return int64(int32(value)), next, nil
+	}
+	return nil, 0, fmt.Errorf("unsupported data type %d", dataType)
+}
+
+func (d *mmdbDecoder) decodeControl(offset uint) (dataType uint, size uint, next uint, err error) {
+	if offset >= uint(len(d.buffer)) {
+		return 0, 0, 0, errors.New("unexpected end of data")
+	}
+	control := d.buffer[offset]
+	offset++
+
+	dataType = uint(control >> 5)
+	if dataType == mmdbExtended {
+		if offset >= uint(len(d.buffer)) {
+			return 0, 0, 0, errors.New("unexpected end of data")
+		}
+		dataType = 7 + uint(d.buffer[offset])
+		offset++
+	}
+
+	size = uint(control & 0x1F)
+	if dataType == mmdbPointer || size < 29 {
+		return dataType, size, offset, nil
+	}
+
+	extraBytes := size - 28
+	if offset+extraBytes > uint(len(d.buffer)) {
+		return 0, 0, 0, errors.New("unexpected end of data")
+	}
+	var extra uint
+	for _, c := range d.buffer[offset : offset+extraBytes] {
+		extra = extra<<8 | uint(c)
+	}
+	switch size {
+	case 29:
+		size = 29 + extra
+	case 30:
+		size = 285 + extra
+	default:
+		size = 65821 + extra
+	}
+	return dataType, size, offset + extraBytes, nil
+}
+
+// decodePointer decodes the pointer whose control byte size bits are given. The returned pointer is an offset
+// in the data section.
+func (d *mmdbDecoder) decodePointer(size uint, offset uint) (uint, uint, error) {
+	pointerSize := ((size >> 3) & 0x3) + 1
+	if offset+pointerSize > uint(len(d.buffer)) {
+		return 0, 0, errors.New("unexpected end of data")
+	}
+	var prefix uint
+	if pointerSize != 4 {
+		prefix = size & 0x7
+	}
+	pointer := prefix
+	for _, c := range d.buffer[offset : offset+pointerSize] {
+		pointer = pointer<<8 | uint(c)
+	}
+	switch pointerSize {
+	case 2:
+		pointer += 2048
+	case 3:
+		pointer += 526336
+	}
+	return pointer, offset + pointerSize, nil
+}
+
+func uintValue(value interface{}) uint {
+	if v, ok := value.(uint64); ok {
+		return uint(v)
+	}
+	return 0
+}
@@ -0,0 +1,283 @@
+package geolocation
+
+import (
+	"encoding/binary"
+	"math"
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testNetwork struct {
+	cidr   string
+	record map[string]interface{}
+}
+
+// buildTestMMDB writes a MaxMind DB file holding the networks
+func buildTestMMDB(t *testing.T, ipVersion int, recordSize int, networks []testNetwork) []byte {
+	t.Helper()
+
+	const emptyRecord = -1
+	// records hold a node index, emptyRecord, or -(index of the data + 2)
+	records := [][2]int{{emptyRecord, emptyRecord}}
+	var data []byte
+	var dataOffsets []int
+
+	for i, network := range networks {
+		_, ipNet, err := net.ParseCIDR(network.cidr)
+		if err != nil {
+			t.Fatalf("invalid test network %s: %v", network.cidr, err)
+		}
+		ip := ipNet.IP
+		prefix, _ := ipNet.Mask.Size()
+		if ipVersion == 6 {
+			if ipv4 := ip.To4(); ipv4 != nil {
+				ip = make(net.IP, 16)
+				copy(ip[12:], ipv4)
+				prefix += 96
+			}
+		}
+
+		node := 0
+		for bit := 0; bit < prefix; bit++ {
+			direction := int(ip[bit/8]>>(7-bit%8)) & 1
+			if bit == prefix-1 {
+				records[node][direction] = -(i + 2)
+				break
+			}
+			if records[node][direction] < 0 {
+				records = append(records, [2]int{emptyRecord, emptyRecord})
+				records[node][direction] = len(records) - 1
+			}
+			node = records[node][direction]
+		}
+
+		dataOffsets = append(dataOffsets, len(data))
+		data = append(data, encodeTestValue(network.record)...)
+	}
+
+	nodeCount := len(records)
+	var buffer []byte
+	for _, record := range records {
+		var values [2]uint32
+		for i, value := range record {
+			switch {
+			case value == emptyRecord:
+				values[i] = uint32(nodeCount)
+			case value < 0:
+				values[i] = uint32(nodeCount + dataSectionSeparatorSize + dataOffsets[-value-2])
+			default:
+				values[i] = uint32(value)
+			}
+		}
+		switch recordSize {
+		case 24:
+			buffer = append(buffer, byte(values[0]>>16), byte(values[0]>>8), byte(values[0]), byte(values[1]>>16), byte(values[1]>>8), byte(values[1]))
+		case 28:
+			buffer = append(buffer, byte(values[0]>>16), byte(values[0]>>8), byte(values[0]), byte(values[0]>>20&0xF0|values[1]>>24&0x0F), byte(values[1]>>16), byte(values[1]>>8), byte(values[1]))
+		case 32:
+			buffer = binary.BigEndian.AppendUint32(buffer, values[0])
+			buffer = binary.BigEndian.AppendUint32(buffer, values[1])
+		}
+	}
+
+	buffer = append(buffer, make([]byte, dataSectionSeparatorSize)...)
+	buffer = append(buffer, data...)
+	buffer = append(buffer, metadataStartMarker...)
+	buffer = append(buffer, encodeTestValue(map[string]interface{}{
+		"node_count":    uint64(nodeCount),
+		"record_size":   uint64(recordSize),
+		"ip_version":    uint64(ipVersion),
+		"database_type": "Test-City",
+	})...)
+	return buffer
+}
+
+func encodeTestValue(value interface{}) []byte {
+	switch v := value.(type) {
+	case string:
+		return append(encodeTestControl(mmdbString, len(v)), v...)
+	case uint64:
+		var b []byte
+		for ; v > 0; v >>= 8 {
+			b = append([]byte{byte(v)}, b...)
+		}
+		return append(encodeTestControl(mmdbUint64, len(b)), b...)
+	case float64:
+		return binary.BigEndian.AppendUint64(encodeTestControl(mmdbDouble, 8), math.Float64bits(v))
+	case bool:
+		if v {
+			return encodeTestControl(mmdbBool, 1)
+		}
+		return encodeTestControl(mmdbBool, 0)
+	case []interface{}:
+		b := encodeTestControl(mmdbArray, len(v))
+		for _, element := range v {
+			b = append(b, encodeTestValue(element)...)
+		}
+		return b
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		b := encodeTestControl(mmdbMap, len(v))
+		for _, key := range keys {
+			b = append(b, encodeTestValue(key)...)
+			b = append(b, encodeTestValue(v[key])...)
+		}
+		return b
+	}
+	panic("unsupported test value")
+}
+
+func encodeTestControl(dataType int, size int) []byte {
+	var b []byte
+	if size < 29 {
+		b = []byte{byte(size)}
+	} else {
+		b = []byte{29, byte(size - 29)}
+	}
+	if dataType > 7 {
+		return append([]byte{b[0], byte(dataType - 7)}, b[1:]...)
+	}
+	b[0] |= byte(dataType << 5)
+	return b
+}
+
+func TestMMDBLookup(t *testing.T) {
+	networks := []testNetwork{
+		{cidr: "1.2.3.0/24", record: map[string]interface{}{"country": map[string]interface{}{"iso_code": "DE"}}},
+		{cidr: "1.2.4.0/23", record: map[string]interface{}{"country": map[string]interface{}{"iso_code": "FR"}}},
+		{cidr: "2001:db8::/32", record: map[string]interface{}{"country": map[string]interface{}{"iso_code": "US"}}},
+	}
+
+	testCases := []struct {
+		description     string
+		ipVersion       int
+		recordSize      int
+		ip              string
+		expectedCountry interface{}
+		expectedError   string
+	}{
+		{description: "IPv4 in an IPv6 database", ipVersion: 6, recordSize: 24, ip: "1.2.3.4", expectedCountry: "DE"},
+		{description: "IPv4 in a larger network", ipVersion: 6, recordSize: 24, ip: "1.2.5.255", expectedCountry: "FR"},
+		{description: "IPv6", ipVersion: 6, recordSize: 24, ip: "2001:db8::1", expectedCountry: "US"},
+		{description: "Unknown IPv4", ipVersion: 6, recordSize: 24, ip: "1.2.6.1"},
+		{description: "Unknown IPv6", ipVersion: 6, recordSize: 24, ip: "2001:db9::1"},
+		{description: "28 bit records", ipVersion: 6, recordSize: 28, ip: "1.2.3.4", expectedCountry: "DE"},
+		{description: "32 bit records", ipVersion: 6, recordSize: 32, ip: "2001:db8::1", expectedCountry: "US"},
+		{description: "IPv6 in an IPv4 database", ipVersion: 4, recordSize: 24, ip: "2001:db8::1", expectedError: "IPv6 address lookup in an IPv4 only database"},
+	}
+
+	for _, test := range testCases {
+		dbNetworks := networks
+		if test.ipVersion == 4 {
+			dbNetworks = networks[:2]
+		}
+		reader, err := newMMDBReader(buildTestMMDB(t, test.ipVersion, test.recordSize, dbNetworks))
+		if !assert.NoError(t, err, test.description) {
+			continue
+		}
+
+		record, err := reader.lookup(net.ParseIP(test.ip))
+
+		if test.expectedError != "" {
+			assert.EqualError(t, err, test.expectedError, test.description)
+			continue
+		}
+		assert.NoError(t, err, test.description)
+		assert.Equal(t, test.expectedCountry, lookupPath(record, "country", "iso_code"), test.description)
+	}
+}
+
+func TestNewMMDBReaderErrors(t *testing.T) {
+	testCases := []struct {
+		description   string
+		buffer        []byte
+		expectedError string
+	}{
+		{
+			description:   "No metadata",
+			buffer:        []byte("not a database"),
+			expectedError: "invalid MaxMind DB file: metadata not found",
+		},
+		{
+			description:   "Unsupported record size",
+			buffer:        append(append([]byte{}, metadataStartMarker...), encodeTestValue(map[string]interface{}{"node_count": uint64(1), "record_size": uint64(20), "ip_version": uint64(6)})...),
+			expectedError: "unsupported MaxMind DB record size 20",
+		},
+		{
+			description:   "Tree larger than the file",
+			buffer:        append(append([]byte{}, metadataStartMarker...), encodeTestValue(map[string]interface{}{"node_count": uint64(100), "record_size": uint64(24), "ip_version": uint64(6)})...),
+			expectedError: "invalid MaxMind DB file: search tree is larger than the file",
+		},
+	}
+
+	for _, test := range testCases {
+		_, err := newMMDBReader(test.buffer)
+		assert.EqualError(t, err, test.expectedError, test.description)
+	}
+}
+
+func TestMMDBDecode(t *testing.T) {
+	testCases := []struct {
+		description   string
+		buffer        []byte
+		expectedValue interface{}
+		expectedNext  uint
+		expectedError string
+	}{
+		{
+			description:   "Pointer",
+			buffer:        append([]byte{0x20, 0x03, 0x00}, encodeTestValue("value")...),
+			expectedValue: "value",
+			expectedNext:  2,
+		},
+		{
+			description:   "Long string",
+			buffer:        encodeTestValue(string(make([]byte, 100))),
+			expectedValue: string(make([]byte, 100)),
+			expectedNext:  102,
+		},
+		{
+			description:   "Array",
+			buffer:        encodeTestValue([]interface{}{true, 1.5}),
+			expectedValue: []interface{}{true, 1.5},
+			expectedNext:  13,
+		},
+		{
+			description:   "Int32",
+			buffer:        []byte{0x04, 0x01, 0xFF, 0xFF, 0xFF, 0xFE},
+			expectedValue: int64(-2),
+			expectedNext:  6,
+		},
+		{
+			description:   "Truncated",
+			buffer:        encodeTestValue("value")[:3],
+			expectedError: "unexpected end of data",
+		},
+		{
+			description:   "Non string map key",
+			buffer:        append([]byte{0xE1}, encodeTestValue(uint64(1))...),
+			expectedError: "map key is not a string",
+		},
+	}
+
+	for _, test := range testCases {
+		decoder := mmdbDecoder{buffer: test.buffer}
+
+		value, next, err := decoder.decode(0, 0)
+
+		if test.expectedError != "" {
+			assert.EqualError(t, err, test.expectedError, test.description)
+			continue
+		}
+		assert.NoError(t, err, test.description)
+		assert.Equal(t, test.expectedValue, value, test.description)
+		assert.Equal(t, test.expectedNext, next, test.description)
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"sync"
 
 	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/hooks"
 	"github.com/prebid/prebid-server/hooks/hookstage"
 	"github.com/prebid/prebid-server/metrics"
+	"github.com/prebid/prebid-server/openrtb_ext"
 )
 
 const (
@@ -31,6 +33,10 @@ type StageExecutor interface {
 	ExecuteEntrypointStage(req *http.Request, body []byte) ([]byte, *RejectError)
 	ExecuteRawAuctionStage(body []byte) ([]byte, *RejectError)
 	ExecuteProcessedAuctionStage(req *openrtb2.BidRequest) *RejectError
+	ExecuteBidderRequestStage(req *openrtb2.BidRequest, bidder string) *RejectError
+	ExecuteRawBidderResponseStage(response *adapters.BidderResponse, bidder string) *RejectError
+	ExecuteAllProcessedBidResponsesStage(responses map[openrtb_ext.BidderName]*adapters.BidderResponse)
+	ExecuteAuctionResponseStage(response *openrtb2.BidResponse)
 }
 
 type HookStageExecutor interface {
@@ -161,6 +167,140 @@ func (e *hookExecutor) ExecuteProcessedAuctionStage(request *openrtb2.BidRequest
 	return reject
 }
 
+func (e *hookExecutor) ExecuteBidderRequestStage(request *openrtb2.BidRequest, bidder string) *RejectError {
+	plan := e.planBuilder.PlanForBidderRequestStage(e.endpoint, e.account)
+	if len(plan) == 0 {
+		return nil
+	}
+
+	handler := func(
+		ctx context.Context,
+		moduleCtx hookstage.ModuleInvocationContext,
+		hook hookstage.BidderRequest,
+		payload hookstage.BidderRequestPayload,
+	) (hookstage.HookResult[hookstage.BidderRequestPayload], error) {
+		return hook.HandleBidderRequestHook(ctx, moduleCtx, payload)
+	}
+
+	stageName := hooks.StageBidderRequest.String()
+	executionCtx := e.newContext(stageName)
+	payload := hookstage.BidderRequestPayload{BidRequest: request}
+
+	outcome, payload, contexts, reject := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome.Entity = entity(bidder)
+	outcome.Stage = stageName
+
+	e.saveModuleContexts(contexts)
+	e.pushStageOutcome(outcome)
+
+	if reject == nil && payload.BidRequest != nil && payload.BidRequest != request {
+		*request = *payload.BidRequest
+	}
+
+	return reject
+}
+
+func (e *hookExecutor) ExecuteRawBidderResponseStage(response *adapters.BidderResponse, bidder string) *RejectError {
+	plan := e.planBuilder.PlanForRawBidderResponseStage(e.endpoint, e.account)
+	if len(plan) == 0 {
+		return nil
+	}
+
+	handler := func(
+		ctx context.Context,
+		moduleCtx hookstage.ModuleInvocationContext,
+		hook hookstage.RawBidderResponse,
+		payload hookstage.RawBidderResponsePayload,
+	) (hookstage.HookResult[hookstage.RawBidderResponsePayload], error) {
+		return hook.HandleRawBidderResponseHook(ctx, moduleCtx, payload)
+	}
+
+	stageName := hooks.StageRawBidderResponse.String()
+	executionCtx := e.newContext(stageName)
+	payload := hookstage.RawBidderResponsePayload{Bids: response.Bids}
+
+	outcome, payload, contexts, reject := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome.Entity = entity(bidder)
+	outcome.Stage = stageName
+
+	e.saveModuleContexts(contexts)
+	e.pushStageOutcome(outcome)
+
+	if reject == nil {
+		response.Bids = payload.Bids
+	}
+
+	return reject
+}
+
+func (e *hookExecutor) ExecuteAllProcessedBidResponsesStage(responses map[openrtb_ext.BidderName]*adapters.BidderResponse) {
+	plan := e.planBuilder.PlanForAllProcessedBidResponsesStage(e.endpoint, e.account)
+	if len(plan) == 0 {
+		return
+	}
+
+	handler := func(
+		ctx context.Context,
+		moduleCtx hookstage.ModuleInvocationContext,
+		hook hookstage.AllProcessedBidResponses,
+		payload hookstage.AllProcessedBidResponsesPayload,
+	) (hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload], error) {
+		return hook.HandleAllProcessedBidResponsesHook(ctx, moduleCtx, payload)
+	}
+
+	stageName := hooks.StageAllProcessedBidResponses.String()
+	executionCtx := e.newContext(stageName)
+	payload := hookstage.AllProcessedBidResponsesPayload{Responses: responses}
+
+	outcome, payload, contexts, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome.Entity = entityAllProcessedBidResponses
+	outcome.Stage = stageName
+
+	e.saveModuleContexts(contexts)
+	e.pushStageOutcome(outcome)
+
+	// mutations may replace the map, so the resulting responses are copied back into the caller's map
+	for bidder := range responses {
+		if _, ok := payload.Responses[bidder]; !ok {
+			delete(responses, bidder)
+		}
+	}
+	for bidder, response := range payload.Responses {
+		responses[bidder] = response
+	}
+}
+
+func (e *hookExecutor) ExecuteAuctionResponseStage(response *openrtb2.BidResponse) {
+	plan := e.planBuilder.PlanForAuctionResponseStage(e.endpoint, e.account)
+	if len(plan) == 0 {
+		return
+	}
+
+	handler := func(
+		ctx context.Context,
+		moduleCtx hookstage.ModuleInvocationContext,
+		hook hookstage.AuctionResponse,
+		payload hookstage.AuctionResponsePayload,
+	) (hookstage.HookResult[hookstage.AuctionResponsePayload], error) {
+		return hook.HandleAuctionResponseHook(ctx, moduleCtx, payload)
+	}
+
+	stageName := hooks.StageAuctionResponse.String()
+	executionCtx := e.newContext(stageName)
+	payload := hookstage.AuctionResponsePayload{BidResponse: response}
+
+	outcome, payload, contexts, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome.Entity = entityAuctionResponse
+	outcome.Stage = stageName
+
+	e.saveModuleContexts(contexts)
+	e.pushStageOutcome(outcome)
+
+	if payload.BidResponse != nil && payload.BidResponse != response {
+		*response = *payload.BidResponse
+	}
+}
+
 func (e *hookExecutor) newContext(stage string) executionContext {
 	return executionContext{
 		account:        e.account,
@@ -204,3 +344,16 @@ func (executor *EmptyHookExecutor) ExecuteRawAuctionStage(body []byte) ([]byte,
 func (executor *EmptyHookExecutor) ExecuteProcessedAuctionStage(_ *openrtb2.BidRequest) *RejectError {
 	return nil
 }
+
+func (executor *EmptyHookExecutor) ExecuteBidderRequestStage(_ *openrtb2.BidRequest, _ string) *RejectError {
+	return nil
+}
+
+func (executor *EmptyHookExecutor) ExecuteRawBidderResponseStage(_ *adapters.BidderResponse, _ string) *RejectError {
+	return nil
+}
+
+func (executor *EmptyHookExecutor) ExecuteAllProcessedBidResponsesStage(_ map[openrtb_ext.BidderName]*adapters.BidderResponse) {
+}
+
+func (executor *EmptyHookExecutor) ExecuteAuctionResponseStage(_ *openrtb2.BidResponse) {}
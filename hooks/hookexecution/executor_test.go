@@ -9,12 +9,14 @@ import (
 	"time"
 
 	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/hooks"
 	"github.com/prebid/prebid-server/hooks/hookanalytics"
 	"github.com/prebid/prebid-server/hooks/hookstage"
 	"github.com/prebid/prebid-server/metrics"
 	metricsConfig "github.com/prebid/prebid-server/metrics/config"
+	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -31,6 +33,10 @@ func TestEmptyHookExecutor(t *testing.T) {
 	entrypointBody, entrypointRejectErr := executor.ExecuteEntrypointStage(req, body)
 	rawAuctionBody, rawAuctionRejectErr := executor.ExecuteRawAuctionStage(body)
 	processedAuctionRejectErr := executor.ExecuteProcessedAuctionStage(&openrtb2.BidRequest{})
+	bidderRequestRejectErr := executor.ExecuteBidderRequestStage(&openrtb2.BidRequest{}, "appnexus")
+	rawBidderResponseRejectErr := executor.ExecuteRawBidderResponseStage(&adapters.BidderResponse{}, "appnexus")
+	executor.ExecuteAllProcessedBidResponsesStage(map[openrtb_ext.BidderName]*adapters.BidderResponse{})
+	executor.ExecuteAuctionResponseStage(&openrtb2.BidResponse{})
 
 	outcomes := executor.GetOutcomes()
 	assert.Equal(t, EmptyHookExecutor{}, executor, "EmptyHookExecutor shouldn't be changed.")
@@ -43,6 +49,8 @@ func TestEmptyHookExecutor(t *testing.T) {
 	assert.Equal(t, body, rawAuctionBody, "EmptyHookExecutor shouldn't change body at raw-auction stage.")
 
 	assert.Nil(t, processedAuctionRejectErr, "EmptyHookExecutor shouldn't return reject error at processed-auction stage.")
+	assert.Nil(t, bidderRequestRejectErr, "EmptyHookExecutor shouldn't return reject error at bidder-request stage.")
+	assert.Nil(t, rawBidderResponseRejectErr, "EmptyHookExecutor shouldn't return reject error at raw-bidder-response stage.")
 }
 
 func TestExecuteEntrypointStage(t *testing.T) {
@@ -920,6 +928,270 @@ func TestExecuteProcessedAuctionStage(t *testing.T) {
 	}
 }
 
+func TestExecuteBidderRequestStage(t *testing.T) {
+	req := openrtb2.BidRequest{ID: "some-id", User: &openrtb2.User{ID: "user-id"}}
+	reqUpdated := openrtb2.BidRequest{ID: "some-id", User: &openrtb2.User{ID: "user-id", Yob: 2000}}
+
+	testCases := []struct {
+		description           string
+		givenPlanBuilder      hooks.ExecutionPlanBuilder
+		expectedRequest       openrtb2.BidRequest
+		expectedReject        *RejectError
+		expectedStageOutcomes []StageOutcome
+	}{
+		{
+			description:           "Request not changed if hook execution plan empty",
+			givenPlanBuilder:      hooks.EmptyPlanBuilder{},
+			expectedRequest:       req,
+			expectedStageOutcomes: []StageOutcome{},
+		},
+		{
+			description:      "Request changed if hooks return mutations",
+			givenPlanBuilder: TestApplyHookMutationsBuilder{},
+			expectedRequest:  reqUpdated,
+			expectedStageOutcomes: []StageOutcome{
+				{
+					Entity: entity("appnexus"),
+					Stage:  hooks.StageBidderRequest.String(),
+					Groups: []GroupOutcome{
+						{
+							InvocationResults: []HookOutcome{
+								{
+									AnalyticsTags: hookanalytics.Analytics{},
+									HookID:        HookID{ModuleCode: "foobar", HookImplCode: "foo"},
+									Status:        StatusSuccess,
+									Action:        ActionUpdate,
+									DebugMessages: []string{
+										fmt.Sprintf("Hook mutation successfully applied, affected key: bidRequest.user.yob, mutation type: %s", hookstage.MutationUpdate),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description:      "Bidder request can be rejected",
+			givenPlanBuilder: TestRejectPlanBuilder{},
+			expectedRequest:  req,
+			expectedReject:   &RejectError{0, HookID{ModuleCode: "foobar", HookImplCode: "foo"}, hooks.StageBidderRequest.String()},
+			expectedStageOutcomes: []StageOutcome{
+				{
+					Entity: entity("appnexus"),
+					Stage:  hooks.StageBidderRequest.String(),
+					Groups: []GroupOutcome{
+						{
+							InvocationResults: []HookOutcome{
+								{
+									AnalyticsTags: hookanalytics.Analytics{},
+									HookID:        HookID{ModuleCode: "foobar", HookImplCode: "foo"},
+									Status:        StatusSuccess,
+									Action:        ActionReject,
+									Errors: []string{
+										`Module foobar (hook: foo) rejected request with code 0 at bidder_request stage`,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(ti *testing.T) {
+			givenRequest := req
+			givenRequest.User = &openrtb2.User{ID: "user-id"}
+
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+			exec.SetAccount(&config.Account{})
+
+			reject := exec.ExecuteBidderRequestStage(&givenRequest, "appnexus")
+
+			assert.Equal(ti, test.expectedReject, reject, "Unexpected stage reject.")
+			assert.Equal(ti, test.expectedRequest, givenRequest, "Incorrect request update.")
+
+			stageOutcomes := exec.GetOutcomes()
+			if len(test.expectedStageOutcomes) == 0 {
+				assert.Empty(ti, stageOutcomes, "Incorrect stage outcomes.")
+			} else {
+				assertEqualStageOutcomes(ti, test.expectedStageOutcomes[0], stageOutcomes[0])
+			}
+		})
+	}
+}
+
+func TestExecuteRawBidderResponseStage(t *testing.T) {
+	bids := []*adapters.TypedBid{
+		{Bid: &openrtb2.Bid{ID: "bid-1"}, BidType: openrtb_ext.BidTypeBanner},
+		{Bid: &openrtb2.Bid{ID: "bid-2"}, BidType: openrtb_ext.BidTypeBanner},
+	}
+
+	testCases := []struct {
+		description      string
+		givenPlanBuilder hooks.ExecutionPlanBuilder
+		expectedBids     []*adapters.TypedBid
+		expectedReject   *RejectError
+		expectedAction   Action
+	}{
+		{
+			description:      "Response not changed if hook execution plan empty",
+			givenPlanBuilder: hooks.EmptyPlanBuilder{},
+			expectedBids:     bids,
+		},
+		{
+			description:      "Response changed if hooks return mutations",
+			givenPlanBuilder: TestApplyHookMutationsBuilder{},
+			expectedBids:     bids[:1],
+			expectedAction:   ActionUpdate,
+		},
+		{
+			description:      "Response can be rejected",
+			givenPlanBuilder: TestRejectPlanBuilder{},
+			expectedBids:     bids,
+			expectedReject:   &RejectError{0, HookID{ModuleCode: "foobar", HookImplCode: "foo"}, hooks.StageRawBidderResponse.String()},
+			expectedAction:   ActionReject,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(ti *testing.T) {
+			response := &adapters.BidderResponse{Currency: "USD", Bids: bids}
+
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+			reject := exec.ExecuteRawBidderResponseStage(response, "appnexus")
+
+			assert.Equal(ti, test.expectedReject, reject, "Unexpected stage reject.")
+			assert.Equal(ti, test.expectedBids, response.Bids, "Incorrect response update.")
+
+			stageOutcomes := exec.GetOutcomes()
+			if test.expectedAction == "" {
+				assert.Empty(ti, stageOutcomes, "Incorrect stage outcomes.")
+			} else {
+				assert.Len(ti, stageOutcomes, 1, "Incorrect stage outcomes.")
+				assert.Equal(ti, entity("appnexus"), stageOutcomes[0].Entity, "Incorrect stage entity.")
+				assert.Equal(ti, hooks.StageRawBidderResponse.String(), stageOutcomes[0].Stage, "Incorrect stage name.")
+				assert.Equal(ti, test.expectedAction, stageOutcomes[0].Groups[0].InvocationResults[0].Action, "Incorrect hook action.")
+			}
+		})
+	}
+}
+
+func TestExecuteAllProcessedBidResponsesStage(t *testing.T) {
+	appnexusResponse := &adapters.BidderResponse{Bids: []*adapters.TypedBid{{Bid: &openrtb2.Bid{ID: "bid-1"}}}}
+	rubiconResponse := &adapters.BidderResponse{Bids: []*adapters.TypedBid{{Bid: &openrtb2.Bid{ID: "bid-2"}}}}
+
+	testCases := []struct {
+		description       string
+		givenPlanBuilder  hooks.ExecutionPlanBuilder
+		expectedResponses map[openrtb_ext.BidderName]*adapters.BidderResponse
+		expectedStatus    Status
+		expectedAction    Action
+	}{
+		{
+			description:      "Responses not changed if hook execution plan empty",
+			givenPlanBuilder: hooks.EmptyPlanBuilder{},
+			expectedResponses: map[openrtb_ext.BidderName]*adapters.BidderResponse{
+				"appnexus": appnexusResponse,
+				"rubicon":  rubiconResponse,
+			},
+		},
+		{
+			description:      "Responses changed if hooks return mutations",
+			givenPlanBuilder: TestApplyHookMutationsBuilder{},
+			expectedResponses: map[openrtb_ext.BidderName]*adapters.BidderResponse{
+				"appnexus": appnexusResponse,
+			},
+			expectedStatus: StatusSuccess,
+			expectedAction: ActionUpdate,
+		},
+		{
+			description:      "Rejection is ignored at this stage",
+			givenPlanBuilder: TestRejectPlanBuilder{},
+			expectedResponses: map[openrtb_ext.BidderName]*adapters.BidderResponse{
+				"appnexus": appnexusResponse,
+				"rubicon":  rubiconResponse,
+			},
+			expectedStatus: StatusExecutionFailure,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(ti *testing.T) {
+			responses := map[openrtb_ext.BidderName]*adapters.BidderResponse{
+				"appnexus": appnexusResponse,
+				"rubicon":  rubiconResponse,
+			}
+
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+			exec.ExecuteAllProcessedBidResponsesStage(responses)
+
+			assert.Equal(ti, test.expectedResponses, responses, "Incorrect responses update.")
+
+			stageOutcomes := exec.GetOutcomes()
+			if test.expectedStatus == "" {
+				assert.Empty(ti, stageOutcomes, "Incorrect stage outcomes.")
+			} else {
+				assert.Len(ti, stageOutcomes, 1, "Incorrect stage outcomes.")
+				assert.Equal(ti, entityAllProcessedBidResponses, stageOutcomes[0].Entity, "Incorrect stage entity.")
+				assert.Equal(ti, hooks.StageAllProcessedBidResponses.String(), stageOutcomes[0].Stage, "Incorrect stage name.")
+				assert.Equal(ti, test.expectedStatus, stageOutcomes[0].Groups[0].InvocationResults[0].Status, "Incorrect hook status.")
+				assert.Equal(ti, test.expectedAction, stageOutcomes[0].Groups[0].InvocationResults[0].Action, "Incorrect hook action.")
+			}
+		})
+	}
+}
+
+func TestExecuteAuctionResponseStage(t *testing.T) {
+	testCases := []struct {
+		description      string
+		givenPlanBuilder hooks.ExecutionPlanBuilder
+		expectedResponse openrtb2.BidResponse
+		expectedStatus   Status
+	}{
+		{
+			description:      "Response not changed if hook execution plan empty",
+			givenPlanBuilder: hooks.EmptyPlanBuilder{},
+			expectedResponse: openrtb2.BidResponse{ID: "some-id", Cur: "USD"},
+		},
+		{
+			description:      "Response changed if hooks return mutations",
+			givenPlanBuilder: TestApplyHookMutationsBuilder{},
+			expectedResponse: openrtb2.BidResponse{ID: "some-id", Cur: "EUR"},
+			expectedStatus:   StatusSuccess,
+		},
+		{
+			description:      "Rejection is ignored at this stage",
+			givenPlanBuilder: TestRejectPlanBuilder{},
+			expectedResponse: openrtb2.BidResponse{ID: "some-id", Cur: "USD"},
+			expectedStatus:   StatusExecutionFailure,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(ti *testing.T) {
+			response := &openrtb2.BidResponse{ID: "some-id", Cur: "USD"}
+
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+			exec.ExecuteAuctionResponseStage(response)
+
+			assert.Equal(ti, test.expectedResponse, *response, "Incorrect response update.")
+
+			stageOutcomes := exec.GetOutcomes()
+			if test.expectedStatus == "" {
+				assert.Empty(ti, stageOutcomes, "Incorrect stage outcomes.")
+			} else {
+				assert.Len(ti, stageOutcomes, 1, "Incorrect stage outcomes.")
+				assert.Equal(ti, entityAuctionResponse, stageOutcomes[0].Entity, "Incorrect stage entity.")
+				assert.Equal(ti, hooks.StageAuctionResponse.String(), stageOutcomes[0].Stage, "Incorrect stage name.")
+				assert.Equal(ti, test.expectedStatus, stageOutcomes[0].Groups[0].InvocationResults[0].Status, "Incorrect hook status.")
+			}
+		})
+	}
+}
+
 func TestInterStageContextCommunication(t *testing.T) {
 	body := []byte(`{"foo": "bar"}`)
 	reader := bytes.NewReader(body)
@@ -1032,6 +1304,50 @@ func (e TestApplyHookMutationsBuilder) PlanForProcessedAuctionStage(_ string, _
 	}
 }
 
+func (e TestApplyHookMutationsBuilder) PlanForBidderRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.BidderRequest] {
+	return hooks.Plan[hookstage.BidderRequest]{
+		hooks.Group[hookstage.BidderRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.BidderRequest]{
+				{Module: "foobar", Code: "foo", Hook: mockUpdateBidRequestHook{}},
+			},
+		},
+	}
+}
+
+func (e TestApplyHookMutationsBuilder) PlanForRawBidderResponseStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawBidderResponse] {
+	return hooks.Plan[hookstage.RawBidderResponse]{
+		hooks.Group[hookstage.RawBidderResponse]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawBidderResponse]{
+				{Module: "foobar", Code: "foo", Hook: mockUpdateBidderResponseHook{}},
+			},
+		},
+	}
+}
+
+func (e TestApplyHookMutationsBuilder) PlanForAllProcessedBidResponsesStage(_ string, _ *config.Account) hooks.Plan[hookstage.AllProcessedBidResponses] {
+	return hooks.Plan[hookstage.AllProcessedBidResponses]{
+		hooks.Group[hookstage.AllProcessedBidResponses]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.AllProcessedBidResponses]{
+				{Module: "foobar", Code: "foo", Hook: mockUpdateBidderResponseHook{}},
+			},
+		},
+	}
+}
+
+func (e TestApplyHookMutationsBuilder) PlanForAuctionResponseStage(_ string, _ *config.Account) hooks.Plan[hookstage.AuctionResponse] {
+	return hooks.Plan[hookstage.AuctionResponse]{
+		hooks.Group[hookstage.AuctionResponse]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.AuctionResponse]{
+				{Module: "foobar", Code: "foo", Hook: mockUpdateBidResponseHook{}},
+			},
+		},
+	}
+}
+
 type TestRejectPlanBuilder struct {
 	hooks.EmptyPlanBuilder
 }
@@ -1112,6 +1428,62 @@ func (e TestRejectPlanBuilder) PlanForProcessedAuctionStage(_ string, _ *config.
 	}
 }
 
+func (e TestRejectPlanBuilder) PlanForBidderRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.BidderRequest] {
+	return hooks.Plan[hookstage.BidderRequest]{
+		hooks.Group[hookstage.BidderRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.BidderRequest]{
+				{Module: "foobar", Code: "foo", Hook: mockRejectHook{}},
+			},
+		},
+		hooks.Group[hookstage.BidderRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.BidderRequest]{
+				{Module: "foobar", Code: "bar", Hook: mockUpdateBidRequestHook{}},
+			},
+		},
+	}
+}
+
+func (e TestRejectPlanBuilder) PlanForRawBidderResponseStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawBidderResponse] {
+	return hooks.Plan[hookstage.RawBidderResponse]{
+		hooks.Group[hookstage.RawBidderResponse]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawBidderResponse]{
+				{Module: "foobar", Code: "foo", Hook: mockRejectHook{}},
+			},
+		},
+		hooks.Group[hookstage.RawBidderResponse]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawBidderResponse]{
+				{Module: "foobar", Code: "bar", Hook: mockUpdateBidderResponseHook{}},
+			},
+		},
+	}
+}
+
+func (e TestRejectPlanBuilder) PlanForAllProcessedBidResponsesStage(_ string, _ *config.Account) hooks.Plan[hookstage.AllProcessedBidResponses] {
+	return hooks.Plan[hookstage.AllProcessedBidResponses]{
+		hooks.Group[hookstage.AllProcessedBidResponses]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.AllProcessedBidResponses]{
+				{Module: "foobar", Code: "foo", Hook: mockRejectHook{}},
+			},
+		},
+	}
+}
+
+func (e TestRejectPlanBuilder) PlanForAuctionResponseStage(_ string, _ *config.Account) hooks.Plan[hookstage.AuctionResponse] {
+	return hooks.Plan[hookstage.AuctionResponse]{
+		hooks.Group[hookstage.AuctionResponse]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.AuctionResponse]{
+				{Module: "foobar", Code: "foo", Hook: mockRejectHook{}},
+			},
+		},
+	}
+}
+
 type TestWithTimeoutPlanBuilder struct {
 	hooks.EmptyPlanBuilder
 }
@@ -5,7 +5,10 @@ import (
 	"errors"
 	"time"
 
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/hooks/hookstage"
+	"github.com/prebid/prebid-server/openrtb_ext"
 )
 
 type mockUpdateHeaderEntrypointHook struct{}
@@ -84,6 +87,22 @@ func (e mockRejectHook) HandleProcessedAuctionHook(_ context.Context, _ hookstag
 	return hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{Reject: true}, nil
 }
 
+func (e mockRejectHook) HandleBidderRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.BidderRequestPayload) (hookstage.HookResult[hookstage.BidderRequestPayload], error) {
+	return hookstage.HookResult[hookstage.BidderRequestPayload]{Reject: true}, nil
+}
+
+func (e mockRejectHook) HandleRawBidderResponseHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.RawBidderResponsePayload) (hookstage.HookResult[hookstage.RawBidderResponsePayload], error) {
+	return hookstage.HookResult[hookstage.RawBidderResponsePayload]{Reject: true}, nil
+}
+
+func (e mockRejectHook) HandleAllProcessedBidResponsesHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.AllProcessedBidResponsesPayload) (hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload], error) {
+	return hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload]{Reject: true}, nil
+}
+
+func (e mockRejectHook) HandleAuctionResponseHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.AuctionResponsePayload) (hookstage.HookResult[hookstage.AuctionResponsePayload], error) {
+	return hookstage.HookResult[hookstage.AuctionResponsePayload]{Reject: true}, nil
+}
+
 type mockTimeoutHook struct{}
 
 func (e mockTimeoutHook) HandleEntrypointHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.EntrypointPayload) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
@@ -198,3 +217,57 @@ func (e mockUpdateBidRequestHook) HandleProcessedAuctionHook(_ context.Context,
 
 	return hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{ChangeSet: c}, nil
 }
+
+func (e mockUpdateBidRequestHook) HandleBidderRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.BidderRequestPayload) (hookstage.HookResult[hookstage.BidderRequestPayload], error) {
+	c := &hookstage.ChangeSet[hookstage.BidderRequestPayload]{}
+	c.AddMutation(
+		func(payload hookstage.BidderRequestPayload) (hookstage.BidderRequestPayload, error) {
+			payload.BidRequest.User.Yob = 2000
+			return payload, nil
+		}, hookstage.MutationUpdate, "bidRequest", "user.yob",
+	)
+
+	return hookstage.HookResult[hookstage.BidderRequestPayload]{ChangeSet: c}, nil
+}
+
+type mockUpdateBidderResponseHook struct{}
+
+func (e mockUpdateBidderResponseHook) HandleRawBidderResponseHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.RawBidderResponsePayload) (hookstage.HookResult[hookstage.RawBidderResponsePayload], error) {
+	c := &hookstage.ChangeSet[hookstage.RawBidderResponsePayload]{}
+	c.AddMutation(
+		func(payload hookstage.RawBidderResponsePayload) (hookstage.RawBidderResponsePayload, error) {
+			payload.Bids = payload.Bids[:1]
+			return payload, nil
+		}, hookstage.MutationDelete, "bids",
+	)
+
+	return hookstage.HookResult[hookstage.RawBidderResponsePayload]{ChangeSet: c}, nil
+}
+
+func (e mockUpdateBidderResponseHook) HandleAllProcessedBidResponsesHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.AllProcessedBidResponsesPayload) (hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload], error) {
+	c := &hookstage.ChangeSet[hookstage.AllProcessedBidResponsesPayload]{}
+	c.AddMutation(
+		func(payload hookstage.AllProcessedBidResponsesPayload) (hookstage.AllProcessedBidResponsesPayload, error) {
+			payload.Responses = map[openrtb_ext.BidderName]*adapters.BidderResponse{
+				"appnexus": payload.Responses["appnexus"],
+			}
+			return payload, nil
+		}, hookstage.MutationDelete, "responses", "rubicon",
+	)
+
+	return hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload]{ChangeSet: c}, nil
+}
+
+type mockUpdateBidResponseHook struct{}
+
+func (e mockUpdateBidResponseHook) HandleAuctionResponseHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.AuctionResponsePayload) (hookstage.HookResult[hookstage.AuctionResponsePayload], error) {
+	c := &hookstage.ChangeSet[hookstage.AuctionResponsePayload]{}
+	c.AddMutation(
+		func(payload hookstage.AuctionResponsePayload) (hookstage.AuctionResponsePayload, error) {
+			payload.BidResponse = &openrtb2.BidResponse{ID: payload.BidResponse.ID, Cur: "EUR"}
+			return payload, nil
+		}, hookstage.MutationUpdate, "bidResponse", "cur",
+	)
+
+	return hookstage.HookResult[hookstage.AuctionResponsePayload]{ChangeSet: c}, nil
+}
@@ -2,6 +2,9 @@ package hookstage
 
 import (
 	"context"
+
+	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/openrtb_ext"
 )
 
 // AllProcessedBidResponses hooks are invoked over a list of all
@@ -21,8 +24,8 @@ type AllProcessedBidResponses interface {
 }
 
 // AllProcessedBidResponsesPayload consists of a list of all
-// processed responses received from bidders.
+// processed responses received from bidders, keyed by seat.
 // Hooks are allowed to modify payload object and discard bids using mutations.
 type AllProcessedBidResponsesPayload struct {
-	// todo: decide what payload to use within the hook invocation task
+	Responses map[openrtb_ext.BidderName]*adapters.BidderResponse
 }
@@ -66,18 +66,22 @@ func loadConfig(bidderInfos config.BidderInfos) (*config.Configuration, error) {
 func serve(cfg *config.Configuration) error {
 	fetchingInterval := time.Duration(cfg.CurrencyConverter.FetchIntervalSeconds) * time.Second
 	staleRatesThreshold := time.Duration(cfg.CurrencyConverter.StaleRatesSeconds) * time.Second
-	currencyConverter := currency.NewRateConverter(&http.Client{}, cfg.CurrencyConverter.FetchURL, staleRatesThreshold)
-
-	currencyConverterTickerTask := task.NewTickerTask(fetchingInterval, currencyConverter)
-	currencyConverterTickerTask.Start()
+	currencyHTTPClient := &http.Client{Timeout: time.Duration(cfg.CurrencyConverter.FetchTimeoutMs) * time.Millisecond}
+	currencySources := currency.NewRatesSources(cfg.CurrencyConverter, currencyHTTPClient)
+	currencyConverter := currency.NewRateConverterWithSources(currencySources, staleRatesThreshold)
 
 	r, err := router.New(cfg, currencyConverter)
 	if err != nil {
 		return err
 	}
 
+	// The staleness metrics of the rates sources need the metrics engine, which is built with the router
+	currencyConverter.SetMetricsEngine(r.MetricsEngine)
+	currencyConverterTickerTask := task.NewTickerTask(fetchingInterval, currencyConverter)
+	currencyConverterTickerTask.Start()
+
 	corsRouter := router.SupportCORS(r)
-	server.Listen(cfg, router.NoCache{Handler: corsRouter}, router.Admin(currencyConverter, fetchingInterval), r.MetricsEngine)
+	server.Listen(cfg, router.NoCache{Handler: corsRouter}, router.Admin(currencyConverter, fetchingInterval, r.BidderHealth), r.MetricsEngine)
 
 	r.Shutdown()
 	return nil
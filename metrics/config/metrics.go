@@ -254,6 +254,48 @@ func (me *MultiMetricsEngine) RecordAdapterGDPRRequestBlocked(adapter openrtb_ex
 	}
 }
 
+// RecordAdapterHealthState across all engines
+func (me *MultiMetricsEngine) RecordAdapterHealthState(adapter openrtb_ext.BidderName, state metrics.AdapterHealthState) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterHealthState(adapter, state)
+	}
+}
+
+// RecordAdapterHealthBlocked across all engines
+func (me *MultiMetricsEngine) RecordAdapterHealthBlocked(adapter openrtb_ext.BidderName) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterHealthBlocked(adapter)
+	}
+}
+
+// RecordBidValidationCreativeSizeError across all engines
+func (me *MultiMetricsEngine) RecordBidValidationCreativeSizeError(adapter openrtb_ext.BidderName) {
+	for _, thisME := range *me {
+		thisME.RecordBidValidationCreativeSizeError(adapter)
+	}
+}
+
+// RecordBidValidationCreativeSizeWarn across all engines
+func (me *MultiMetricsEngine) RecordBidValidationCreativeSizeWarn(adapter openrtb_ext.BidderName) {
+	for _, thisME := range *me {
+		thisME.RecordBidValidationCreativeSizeWarn(adapter)
+	}
+}
+
+// RecordBidValidationSecureMarkupError across all engines
+func (me *MultiMetricsEngine) RecordBidValidationSecureMarkupError(adapter openrtb_ext.BidderName) {
+	for _, thisME := range *me {
+		thisME.RecordBidValidationSecureMarkupError(adapter)
+	}
+}
+
+// RecordBidValidationSecureMarkupWarn across all engines
+func (me *MultiMetricsEngine) RecordBidValidationSecureMarkupWarn(adapter openrtb_ext.BidderName) {
+	for _, thisME := range *me {
+		thisME.RecordBidValidationSecureMarkupWarn(adapter)
+	}
+}
+
 // RecordDebugRequest across all engines
 func (me *MultiMetricsEngine) RecordDebugRequest(debugEnabled bool, pubId string) {
 	for _, thisME := range *me {
@@ -261,6 +303,20 @@ func (me *MultiMetricsEngine) RecordDebugRequest(debugEnabled bool, pubId string
 	}
 }
 
+// RecordCompressedRequest across all engines
+func (me *MultiMetricsEngine) RecordCompressedRequest(compression metrics.RequestCompression) {
+	for _, thisME := range *me {
+		thisME.RecordCompressedRequest(compression)
+	}
+}
+
+// RecordCurrencyRatesStale across all engines
+func (me *MultiMetricsEngine) RecordCurrencyRatesStale(source string, stale bool) {
+	for _, thisME := range *me {
+		thisME.RecordCurrencyRatesStale(source, stale)
+	}
+}
+
 func (me *MultiMetricsEngine) RecordStoredResponse(pubId string) {
 	for _, thisME := range *me {
 		thisME.RecordStoredResponse(pubId)
@@ -433,10 +489,42 @@ func (me *NilMetricsEngine) RecordRequestPrivacy(privacy metrics.PrivacyLabels)
 func (me *NilMetricsEngine) RecordAdapterGDPRRequestBlocked(adapter openrtb_ext.BidderName) {
 }
 
+// RecordAdapterHealthState as a noop
+func (me *NilMetricsEngine) RecordAdapterHealthState(adapter openrtb_ext.BidderName, state metrics.AdapterHealthState) {
+}
+
+// RecordAdapterHealthBlocked as a noop
+func (me *NilMetricsEngine) RecordAdapterHealthBlocked(adapter openrtb_ext.BidderName) {
+}
+
+// RecordBidValidationCreativeSizeError as a noop
+func (me *NilMetricsEngine) RecordBidValidationCreativeSizeError(adapter openrtb_ext.BidderName) {
+}
+
+// RecordBidValidationCreativeSizeWarn as a noop
+func (me *NilMetricsEngine) RecordBidValidationCreativeSizeWarn(adapter openrtb_ext.BidderName) {
+}
+
+// RecordBidValidationSecureMarkupError as a noop
+func (me *NilMetricsEngine) RecordBidValidationSecureMarkupError(adapter openrtb_ext.BidderName) {
+}
+
+// RecordBidValidationSecureMarkupWarn as a noop
+func (me *NilMetricsEngine) RecordBidValidationSecureMarkupWarn(adapter openrtb_ext.BidderName) {
+}
+
 // RecordDebugRequest as a noop
 func (me *NilMetricsEngine) RecordDebugRequest(debugEnabled bool, pubId string) {
 }
 
+// RecordCurrencyRatesStale as a noop
+func (me *NilMetricsEngine) RecordCurrencyRatesStale(source string, stale bool) {
+}
+
+// RecordCompressedRequest as a noop
+func (me *NilMetricsEngine) RecordCompressedRequest(compression metrics.RequestCompression) {
+}
+
 func (me *NilMetricsEngine) RecordStoredResponse(pubId string) {
 }
 
@@ -21,6 +21,7 @@ type Metrics struct {
 	AppRequestMeter                metrics.Meter
 	NoCookieMeter                  metrics.Meter
 	DebugRequestMeter              metrics.Meter
+	CompressedRequestMeter         map[RequestCompression]metrics.Meter
 	RequestTimer                   metrics.Timer
 	RequestsQueueTimer             map[RequestType]map[bool]metrics.Timer
 	PrebidCacheRequestTimerSuccess metrics.Timer
@@ -67,6 +68,10 @@ type Metrics struct {
 	accountMetrics        map[string]*accountMetrics
 	accountMetricsRWMutex sync.RWMutex
 
+	// Don't export currencyRatesStale because the gauges are registered when a rates source is first seen
+	currencyRatesStale      map[string]metrics.Gauge
+	currencyRatesStaleMutex sync.Mutex
+
 	exchanges []openrtb_ext.BidderName
 	modules   []string
 	// Will hold boolean values to help us disable metric collection if needed
@@ -96,6 +101,13 @@ type AdapterMetrics struct {
 	ConnReused         metrics.Counter
 	ConnWaitTime       metrics.Timer
 	GDPRRequestBlocked metrics.Meter
+	HealthState        metrics.Gauge
+	HealthBlockedMeter metrics.Meter
+
+	BidValidationCreativeSizeErrorMeter metrics.Meter
+	BidValidationCreativeSizeWarnMeter  metrics.Meter
+	BidValidationSecureMarkupErrorMeter metrics.Meter
+	BidValidationSecureMarkupWarnMeter  metrics.Meter
 }
 
 type MarkupDeliveryMetrics struct {
@@ -180,6 +192,8 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderNa
 		PrivacyLMTRequest:        blankMeter,
 		PrivacyTCFRequestVersion: make(map[TCFVersionValue]metrics.Meter, len(TCFVersions())),
 
+		CompressedRequestMeter: make(map[RequestCompression]metrics.Meter, len(RequestCompressions())),
+
 		AdapterMetrics:  make(map[openrtb_ext.BidderName]*AdapterMetrics, len(exchanges)),
 		accountMetrics:  make(map[string]*accountMetrics),
 		MetricsDisabled: disabledMetrics,
@@ -219,6 +233,10 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderNa
 		newMetrics.PrivacyTCFRequestVersion[v] = blankMeter
 	}
 
+	for _, c := range RequestCompressions() {
+		newMetrics.CompressedRequestMeter[c] = blankMeter
+	}
+
 	for _, dt := range StoredDataTypes() {
 		newMetrics.StoredDataFetchTimer[dt] = make(map[StoredDataFetchType]metrics.Timer)
 		newMetrics.StoredDataErrorMeter[dt] = make(map[StoredDataError]metrics.Meter)
@@ -270,6 +288,9 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 	newMetrics.NoCookieMeter = metrics.GetOrRegisterMeter("no_cookie_requests", registry)
 	newMetrics.AppRequestMeter = metrics.GetOrRegisterMeter("app_requests", registry)
 	newMetrics.DebugRequestMeter = metrics.GetOrRegisterMeter("debug_requests", registry)
+	for _, compression := range RequestCompressions() {
+		newMetrics.CompressedRequestMeter[compression] = metrics.GetOrRegisterMeter(fmt.Sprintf("requests.compressed.%s", string(compression)), registry)
+	}
 	newMetrics.RequestTimer = metrics.GetOrRegisterTimer("request_time", registry)
 	newMetrics.DNSLookupTimer = metrics.GetOrRegisterTimer("dns_lookup_time", registry)
 	newMetrics.TLSHandshakeTimer = metrics.GetOrRegisterTimer("tls_handshake_time", registry)
@@ -357,15 +378,22 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMetrics {
 	blankMeter := &metrics.NilMeter{}
 	newAdapter := &AdapterMetrics{
-		NoCookieMeter:     blankMeter,
-		ErrorMeters:       make(map[AdapterError]metrics.Meter),
-		NoBidMeter:        blankMeter,
-		GotBidsMeter:      blankMeter,
-		RequestTimer:      &metrics.NilTimer{},
-		PriceHistogram:    &metrics.NilHistogram{},
-		BidsReceivedMeter: blankMeter,
-		PanicMeter:        blankMeter,
-		MarkupMetrics:     makeBlankBidMarkupMetrics(),
+		NoCookieMeter:      blankMeter,
+		ErrorMeters:        make(map[AdapterError]metrics.Meter),
+		NoBidMeter:         blankMeter,
+		GotBidsMeter:       blankMeter,
+		RequestTimer:       &metrics.NilTimer{},
+		PriceHistogram:     &metrics.NilHistogram{},
+		BidsReceivedMeter:  blankMeter,
+		PanicMeter:         blankMeter,
+		MarkupMetrics:      makeBlankBidMarkupMetrics(),
+		HealthState:        metrics.NilGauge{},
+		HealthBlockedMeter: blankMeter,
+
+		BidValidationCreativeSizeErrorMeter: blankMeter,
+		BidValidationCreativeSizeWarnMeter:  blankMeter,
+		BidValidationSecureMarkupErrorMeter: blankMeter,
+		BidValidationSecureMarkupWarnMeter:  blankMeter,
 	}
 	if !disabledMetrics.AdapterConnectionMetrics {
 		newAdapter.ConnCreated = metrics.NilCounter{}
@@ -442,6 +470,14 @@ func registerAdapterMetrics(registry metrics.Registry, adapterOrAccount string,
 	}
 	am.PanicMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.requests.panic", adapterOrAccount, exchange), registry)
 	am.GDPRRequestBlocked = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.gdpr_request_blocked", adapterOrAccount, exchange), registry)
+	if adapterOrAccount == "adapter" {
+		am.HealthState = metrics.GetOrRegisterGauge(fmt.Sprintf("%[1]s.%[2]s.health_state", adapterOrAccount, exchange), registry)
+		am.HealthBlockedMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.requests.health_blocked", adapterOrAccount, exchange), registry)
+		am.BidValidationCreativeSizeErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.size.err", adapterOrAccount, exchange), registry)
+		am.BidValidationCreativeSizeWarnMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.size.warn", adapterOrAccount, exchange), registry)
+		am.BidValidationSecureMarkupErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.secure.err", adapterOrAccount, exchange), registry)
+		am.BidValidationSecureMarkupWarnMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.secure.warn", adapterOrAccount, exchange), registry)
+	}
 }
 
 func registerModuleMetrics(registry metrics.Registry, module string, stages []string, mm map[string]*ModuleMetrics) {
@@ -557,6 +593,33 @@ func (me *Metrics) RecordDebugRequest(debugEnabled bool, pubID string) {
 	}
 }
 
+func (me *Metrics) RecordCompressedRequest(compression RequestCompression) {
+	if meter, ok := me.CompressedRequestMeter[compression]; ok {
+		meter.Mark(1)
+	}
+}
+
+// RecordCurrencyRatesStale sets the stale gauge of the currency rates source to 1 when its rates are stale
+func (me *Metrics) RecordCurrencyRatesStale(source string, stale bool) {
+	me.currencyRatesStaleMutex.Lock()
+	defer me.currencyRatesStaleMutex.Unlock()
+
+	if me.currencyRatesStale == nil {
+		me.currencyRatesStale = make(map[string]metrics.Gauge)
+	}
+	gauge, ok := me.currencyRatesStale[source]
+	if !ok {
+		gauge = metrics.GetOrRegisterGauge(fmt.Sprintf("currency_rates.%s.stale", source), me.MetricsRegistry)
+		me.currencyRatesStale[source] = gauge
+	}
+
+	if stale {
+		gauge.Update(1)
+	} else {
+		gauge.Update(0)
+	}
+}
+
 func (me *Metrics) RecordStoredResponse(pubId string) {
 	me.StoredResponsesMeter.Mark(1)
 	if pubId != PublisherUnknown && !me.MetricsDisabled.AccountStoredResponses {
@@ -862,6 +925,73 @@ func (me *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.Bidde
 	am.GDPRRequestBlocked.Mark(1)
 }
 
+// RecordAdapterHealthState sets the health state gauge of the adapter to the position of the state in
+// AdapterHealthStates, from 0 when closed to 3 when open
+func (me *Metrics) RecordAdapterHealthState(adapterName openrtb_ext.BidderName, state AdapterHealthState) {
+	am, ok := me.AdapterMetrics[adapterName]
+	if !ok {
+		glog.Errorf("Trying to log adapter health state metric for %s: adapter not found", string(adapterName))
+		return
+	}
+
+	for i, s := range AdapterHealthStates() {
+		if s == state {
+			am.HealthState.Update(int64(i))
+			return
+		}
+	}
+}
+
+func (me *Metrics) RecordAdapterHealthBlocked(adapterName openrtb_ext.BidderName) {
+	am, ok := me.AdapterMetrics[adapterName]
+	if !ok {
+		glog.Errorf("Trying to log adapter health blocked metric for %s: adapter not found", string(adapterName))
+		return
+	}
+
+	am.HealthBlockedMeter.Mark(1)
+}
+
+func (me *Metrics) RecordBidValidationCreativeSizeError(adapterName openrtb_ext.BidderName) {
+	am, ok := me.AdapterMetrics[adapterName]
+	if !ok {
+		glog.Errorf("Trying to log bid validation creative size error metric for %s: adapter not found", string(adapterName))
+		return
+	}
+
+	am.BidValidationCreativeSizeErrorMeter.Mark(1)
+}
+
+func (me *Metrics) RecordBidValidationCreativeSizeWarn(adapterName openrtb_ext.BidderName) {
+	am, ok := me.AdapterMetrics[adapterName]
+	if !ok {
+		glog.Errorf("Trying to log bid validation creative size warning metric for %s: adapter not found", string(adapterName))
+		return
+	}
+
+	am.BidValidationCreativeSizeWarnMeter.Mark(1)
+}
+
+func (me *Metrics) RecordBidValidationSecureMarkupError(adapterName openrtb_ext.BidderName) {
+	am, ok := me.AdapterMetrics[adapterName]
+	if !ok {
+		glog.Errorf("Trying to log bid validation secure markup error metric for %s: adapter not found", string(adapterName))
+		return
+	}
+
+	am.BidValidationSecureMarkupErrorMeter.Mark(1)
+}
+
+func (me *Metrics) RecordBidValidationSecureMarkupWarn(adapterName openrtb_ext.BidderName) {
+	am, ok := me.AdapterMetrics[adapterName]
+	if !ok {
+		glog.Errorf("Trying to log bid validation secure markup warning metric for %s: adapter not found", string(adapterName))
+		return
+	}
+
+	am.BidValidationSecureMarkupWarnMeter.Mark(1)
+}
+
 func (me *Metrics) RecordAdsCertReq(success bool) {
 	if success {
 		me.AdsCertRequestsSuccess.Mark(1)
@@ -35,7 +35,11 @@ func TestNewMetrics(t *testing.T) {
 	ensureContains(t, registry, "setuid_requests.opt_out", m.SetUidStatusMeter[SetUidOptOut])
 	ensureContains(t, registry, "setuid_requests.gdpr_blocked_host_cookie", m.SetUidStatusMeter[SetUidGDPRHostCookieBlocked])
 	ensureContains(t, registry, "setuid_requests.syncer_unknown", m.SetUidStatusMeter[SetUidSyncerUnknown])
+	ensureContains(t, registry, "setuid_requests.activity_blocked", m.SetUidStatusMeter[SetUidActivityBlocked])
+	ensureContains(t, registry, "setuid_requests.gpp_blocked", m.SetUidStatusMeter[SetUidGPPBlocked])
 	ensureContains(t, registry, "stored_responses", m.StoredResponsesMeter)
+	ensureContains(t, registry, "requests.compressed.gzip", m.CompressedRequestMeter[RequestCompressionGzip])
+	ensureContains(t, registry, "requests.compressed.deflate", m.CompressedRequestMeter[RequestCompressionDeflate])
 
 	ensureContains(t, registry, "prebid_cache_request_time.ok", m.PrebidCacheRequestTimerSuccess)
 	ensureContains(t, registry, "prebid_cache_request_time.err", m.PrebidCacheRequestTimerError)
@@ -74,6 +78,7 @@ func TestNewMetrics(t *testing.T) {
 	ensureContains(t, registry, "syncer.foo.request.privacy_blocked", m.SyncerRequestsMeter["foo"][SyncerCookieSyncPrivacyBlocked])
 	ensureContains(t, registry, "syncer.foo.request.already_synced", m.SyncerRequestsMeter["foo"][SyncerCookieSyncAlreadySynced])
 	ensureContains(t, registry, "syncer.foo.request.type_not_supported", m.SyncerRequestsMeter["foo"][SyncerCookieSyncTypeNotSupported])
+	ensureContains(t, registry, "syncer.foo.request.acct_blocked", m.SyncerRequestsMeter["foo"][SyncerCookieSyncAccountBlocked])
 	ensureContains(t, registry, "syncer.foo.set.ok", m.SyncerSetsMeter["foo"][SyncerSetUidOK])
 	ensureContains(t, registry, "syncer.foo.set.cleared", m.SyncerSetsMeter["foo"][SyncerSetUidCleared])
 
@@ -272,6 +277,30 @@ func TestRecordDebugRequest(t *testing.T) {
 	}
 }
 
+func TestRecordCompressedRequest(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordCompressedRequest(RequestCompressionGzip)
+	m.RecordCompressedRequest(RequestCompressionGzip)
+	m.RecordCompressedRequest(RequestCompressionDeflate)
+
+	assert.Equal(t, int64(2), m.CompressedRequestMeter[RequestCompressionGzip].Count())
+	assert.Equal(t, int64(1), m.CompressedRequestMeter[RequestCompressionDeflate].Count())
+}
+
+func TestRecordCurrencyRatesStale(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordCurrencyRatesStale("primary", true)
+	m.RecordCurrencyRatesStale("backup", true)
+	m.RecordCurrencyRatesStale("backup", false)
+
+	assert.Equal(t, int64(1), registry.Get("currency_rates.primary.stale").(metrics.Gauge).Value())
+	assert.Equal(t, int64(0), registry.Get("currency_rates.backup.stale").(metrics.Gauge).Value())
+}
+
 func TestRecordDNSTime(t *testing.T) {
 	testCases := []struct {
 		description         string
@@ -691,6 +720,43 @@ func TestRecordAdapterGDPRRequestBlocked(t *testing.T) {
 	}
 }
 
+func TestRecordAdapterHealth(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordAdapterHealthState(openrtb_ext.BidderAppnexus, AdapterHealthSampled)
+	m.RecordAdapterHealthBlocked(openrtb_ext.BidderAppnexus)
+	m.RecordAdapterHealthBlocked(openrtb_ext.BidderAppnexus)
+
+	// Unknown adapters are ignored
+	m.RecordAdapterHealthState("unknown", AdapterHealthOpen)
+	m.RecordAdapterHealthBlocked("unknown")
+
+	assert.Equal(t, int64(2), m.AdapterMetrics[openrtb_ext.BidderAppnexus].HealthState.Value())
+	assert.Equal(t, int64(2), m.AdapterMetrics[openrtb_ext.BidderAppnexus].HealthBlockedMeter.Count())
+	ensureContains(t, registry, "adapter.appnexus.health_state", m.AdapterMetrics[openrtb_ext.BidderAppnexus].HealthState)
+	ensureContains(t, registry, "adapter.appnexus.requests.health_blocked", m.AdapterMetrics[openrtb_ext.BidderAppnexus].HealthBlockedMeter)
+}
+
+func TestRecordBidValidation(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordBidValidationCreativeSizeError(openrtb_ext.BidderAppnexus)
+	m.RecordBidValidationCreativeSizeWarn(openrtb_ext.BidderAppnexus)
+	m.RecordBidValidationCreativeSizeWarn(openrtb_ext.BidderAppnexus)
+	m.RecordBidValidationSecureMarkupError(openrtb_ext.BidderAppnexus)
+	m.RecordBidValidationSecureMarkupWarn("unknown")
+
+	am := m.AdapterMetrics[openrtb_ext.BidderAppnexus]
+	assert.Equal(t, int64(1), am.BidValidationCreativeSizeErrorMeter.Count())
+	assert.Equal(t, int64(2), am.BidValidationCreativeSizeWarnMeter.Count())
+	assert.Equal(t, int64(1), am.BidValidationSecureMarkupErrorMeter.Count())
+	assert.Equal(t, int64(0), am.BidValidationSecureMarkupWarnMeter.Count())
+	ensureContains(t, registry, "adapter.appnexus.response.validation.size.err", am.BidValidationCreativeSizeErrorMeter)
+	ensureContains(t, registry, "adapter.appnexus.response.validation.secure.warn", am.BidValidationSecureMarkupWarnMeter)
+}
+
 func TestRecordCookieSync(t *testing.T) {
 	registry := metrics.NewRegistry()
 	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus, openrtb_ext.BidderRubicon}, config.DisabledMetrics{}, nil, nil)
@@ -726,6 +792,7 @@ func TestRecordSyncerRequest(t *testing.T) {
 	assert.Equal(t, m.SyncerRequestsMeter["foo"][SyncerCookieSyncPrivacyBlocked].Count(), int64(0))
 	assert.Equal(t, m.SyncerRequestsMeter["foo"][SyncerCookieSyncAlreadySynced].Count(), int64(0))
 	assert.Equal(t, m.SyncerRequestsMeter["foo"][SyncerCookieSyncTypeNotSupported].Count(), int64(0))
+	assert.Equal(t, m.SyncerRequestsMeter["foo"][SyncerCookieSyncAccountBlocked].Count(), int64(0))
 }
 
 func TestRecordSetUid(t *testing.T) {
@@ -263,6 +263,26 @@ func AdapterErrors() []AdapterError {
 	}
 }
 
+// AdapterHealthState is the state of the circuit breaker tracking the health of an adapter
+type AdapterHealthState string
+
+// Adapter circuit breaker states
+const (
+	AdapterHealthClosed   AdapterHealthState = "closed"
+	AdapterHealthHalfOpen AdapterHealthState = "half_open"
+	AdapterHealthSampled  AdapterHealthState = "sampled"
+	AdapterHealthOpen     AdapterHealthState = "open"
+)
+
+func AdapterHealthStates() []AdapterHealthState {
+	return []AdapterHealthState{
+		AdapterHealthClosed,
+		AdapterHealthHalfOpen,
+		AdapterHealthSampled,
+		AdapterHealthOpen,
+	}
+}
+
 const (
 	// CacheHit represents a cache hit i.e the key was found in cache
 	CacheHit CacheResult = "hit"
@@ -279,6 +299,22 @@ func CacheResults() []CacheResult {
 	}
 }
 
+// RequestCompression : The compression of the incoming request bodies
+type RequestCompression string
+
+const (
+	RequestCompressionGzip    RequestCompression = "gzip"
+	RequestCompressionDeflate RequestCompression = "deflate"
+)
+
+// RequestCompressions returns the possible values for the request compression
+func RequestCompressions() []RequestCompression {
+	return []RequestCompression{
+		RequestCompressionGzip,
+		RequestCompressionDeflate,
+	}
+}
+
 // TCFVersionValue : The possible values for TCF versions
 type TCFVersionValue string
 
@@ -338,6 +374,7 @@ const (
 	SyncerCookieSyncPrivacyBlocked   SyncerCookieSyncStatus = "privacy_blocked"
 	SyncerCookieSyncAlreadySynced    SyncerCookieSyncStatus = "already_synced"
 	SyncerCookieSyncTypeNotSupported SyncerCookieSyncStatus = "type_not_supported"
+	SyncerCookieSyncAccountBlocked   SyncerCookieSyncStatus = "acct_blocked"
 )
 
 // SyncerRequestStatuses returns possible syncer statuses.
@@ -347,6 +384,7 @@ func SyncerRequestStatuses() []SyncerCookieSyncStatus {
 		SyncerCookieSyncPrivacyBlocked,
 		SyncerCookieSyncAlreadySynced,
 		SyncerCookieSyncTypeNotSupported,
+		SyncerCookieSyncAccountBlocked,
 	}
 }
 
@@ -363,6 +401,8 @@ const (
 	SetUidAccountConfigMalformed SetUidStatus = "acct_config_malformed"
 	SetUidAccountInvalid         SetUidStatus = "acct_invalid"
 	SetUidSyncerUnknown          SetUidStatus = "syncer_unknown"
+	SetUidActivityBlocked        SetUidStatus = "activity_blocked"
+	SetUidGPPBlocked             SetUidStatus = "gpp_blocked"
 )
 
 // SetUidStatuses returns possible setuid statuses.
@@ -376,6 +416,8 @@ func SetUidStatuses() []SetUidStatus {
 		SetUidAccountConfigMalformed,
 		SetUidAccountInvalid,
 		SetUidSyncerUnknown,
+		SetUidActivityBlocked,
+		SetUidGPPBlocked,
 	}
 }
 
@@ -431,7 +473,15 @@ type MetricsEngine interface {
 	RecordTimeoutNotice(success bool)
 	RecordRequestPrivacy(privacy PrivacyLabels)
 	RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName)
+	RecordAdapterHealthState(adapterName openrtb_ext.BidderName, state AdapterHealthState)
+	RecordAdapterHealthBlocked(adapterName openrtb_ext.BidderName)
+	RecordBidValidationCreativeSizeError(adapterName openrtb_ext.BidderName)
+	RecordBidValidationCreativeSizeWarn(adapterName openrtb_ext.BidderName)
+	RecordBidValidationSecureMarkupError(adapterName openrtb_ext.BidderName)
+	RecordBidValidationSecureMarkupWarn(adapterName openrtb_ext.BidderName)
 	RecordDebugRequest(debugEnabled bool, pubId string)
+	RecordCompressedRequest(compression RequestCompression)
+	RecordCurrencyRatesStale(source string, stale bool)
 	RecordStoredResponse(pubId string)
 	RecordAdsCertReq(success bool)
 	RecordAdsCertSignTime(adsCertSignTime time.Duration)
@@ -146,11 +146,51 @@ func (me *MetricsEngineMock) RecordAdapterGDPRRequestBlocked(adapterName openrtb
 	me.Called(adapterName)
 }
 
+// RecordAdapterHealthState mock
+func (me *MetricsEngineMock) RecordAdapterHealthState(adapterName openrtb_ext.BidderName, state AdapterHealthState) {
+	me.Called(adapterName, state)
+}
+
+// RecordAdapterHealthBlocked mock
+func (me *MetricsEngineMock) RecordAdapterHealthBlocked(adapterName openrtb_ext.BidderName) {
+	me.Called(adapterName)
+}
+
+// RecordBidValidationCreativeSizeError mock
+func (me *MetricsEngineMock) RecordBidValidationCreativeSizeError(adapterName openrtb_ext.BidderName) {
+	me.Called(adapterName)
+}
+
+// RecordBidValidationCreativeSizeWarn mock
+func (me *MetricsEngineMock) RecordBidValidationCreativeSizeWarn(adapterName openrtb_ext.BidderName) {
+	me.Called(adapterName)
+}
+
+// RecordBidValidationSecureMarkupError mock
+func (me *MetricsEngineMock) RecordBidValidationSecureMarkupError(adapterName openrtb_ext.BidderName) {
+	me.Called(adapterName)
+}
+
+// RecordBidValidationSecureMarkupWarn mock
+func (me *MetricsEngineMock) RecordBidValidationSecureMarkupWarn(adapterName openrtb_ext.BidderName) {
+	me.Called(adapterName)
+}
+
 // RecordDebugRequest mock
 func (me *MetricsEngineMock) RecordDebugRequest(debugEnabled bool, pubId string) {
 	me.Called(debugEnabled, pubId)
 }
 
+// RecordCompressedRequest mock
+func (me *MetricsEngineMock) RecordCompressedRequest(compression RequestCompression) {
+	me.Called(compression)
+}
+
+// RecordCurrencyRatesStale mock
+func (me *MetricsEngineMock) RecordCurrencyRatesStale(source string, stale bool) {
+	me.Called(source, stale)
+}
+
 func (me *MetricsEngineMock) RecordStoredResponse(pubId string) {
 	me.Called(pubId)
 }
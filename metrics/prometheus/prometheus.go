@@ -28,6 +28,8 @@ type Metrics struct {
 	prebidCacheWriteTimer        *prometheus.HistogramVec
 	requests                     *prometheus.CounterVec
 	debugRequests                prometheus.Counter
+	compressedRequests           *prometheus.CounterVec
+	currencyRatesStale           *prometheus.GaugeVec
 	requestsTimer                *prometheus.HistogramVec
 	requestsQueueTimer           *prometheus.HistogramVec
 	requestsWithoutCookie        *prometheus.CounterVec
@@ -68,6 +70,13 @@ type Metrics struct {
 	adapterCreatedConnections  *prometheus.CounterVec
 	adapterConnectionWaitTime  *prometheus.HistogramVec
 	adapterGDPRBlockedRequests *prometheus.CounterVec
+	adapterHealthState         *prometheus.GaugeVec
+	adapterHealthBlocked       *prometheus.CounterVec
+
+	adapterBidValidationCreativeSizeError *prometheus.CounterVec
+	adapterBidValidationCreativeSizeWarn  *prometheus.CounterVec
+	adapterBidValidationSecureMarkupError *prometheus.CounterVec
+	adapterBidValidationSecureMarkupWarn  *prometheus.CounterVec
 
 	// Syncer Metrics
 	syncerRequests *prometheus.CounterVec
@@ -98,6 +107,7 @@ const (
 	adapterLabel         = "adapter"
 	bidTypeLabel         = "bid_type"
 	cacheResultLabel     = "cache_result"
+	compressionLabel     = "compression"
 	connectionErrorLabel = "connection_error"
 	cookieLabel          = "cookie"
 	hasBidsLabel         = "has_bids"
@@ -111,6 +121,7 @@ const (
 	requestStatusLabel   = "request_status"
 	requestTypeLabel     = "request_type"
 	stageLabel           = "stage"
+	stateLabel           = "state"
 	statusLabel          = "status"
 	successLabel         = "success"
 	syncerLabel          = "syncer"
@@ -205,6 +216,16 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"debug_requests",
 		"Count of total requests to Prebid Server that have debug enabled")
 
+	metrics.compressedRequests = newCounter(cfg, reg,
+		"requests_compressed",
+		"Count of total requests to Prebid Server with a compressed body labeled by compression.",
+		[]string{compressionLabel})
+
+	metrics.currencyRatesStale = newGaugeVec(cfg, reg,
+		"currency_rates_stale",
+		"Set to 1 when the currency rates of the source couldn't be refreshed within the stale rates threshold, labeled by source.",
+		[]string{sourceLabel})
+
 	metrics.requestsTimer = newHistogramVec(cfg, reg,
 		"request_time_seconds",
 		"Seconds to resolve successful Prebid Server requests labeled by type.",
@@ -328,6 +349,36 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 			[]string{adapterLabel})
 	}
 
+	metrics.adapterHealthState = newGaugeVec(cfg, reg,
+		"adapter_health_state",
+		"Circuit breaker state of each adapter. The gauge of the current state is set to 1.",
+		[]string{adapterLabel, stateLabel})
+
+	metrics.adapterHealthBlocked = newCounter(cfg, reg,
+		"adapter_health_requests_blocked",
+		"Count of total bidder requests not sent because the adapter is considered unhealthy",
+		[]string{adapterLabel})
+
+	metrics.adapterBidValidationCreativeSizeError = newCounter(cfg, reg,
+		"adapter_response_validation_size_err",
+		"Count of total bids rejected because their banner creative size is not allowed",
+		[]string{adapterLabel})
+
+	metrics.adapterBidValidationCreativeSizeWarn = newCounter(cfg, reg,
+		"adapter_response_validation_size_warn",
+		"Count of total bids with a banner creative size not allowed, kept because the validation only warns",
+		[]string{adapterLabel})
+
+	metrics.adapterBidValidationSecureMarkupError = newCounter(cfg, reg,
+		"adapter_response_validation_secure_err",
+		"Count of total bids rejected because their markup loads insecure resources on a secure imp",
+		[]string{adapterLabel})
+
+	metrics.adapterBidValidationSecureMarkupWarn = newCounter(cfg, reg,
+		"adapter_response_validation_secure_warn",
+		"Count of total bids with markup loading insecure resources on a secure imp, kept because the validation only warns",
+		[]string{adapterLabel})
+
 	metrics.storedResponsesFetchTimer = newHistogramVec(cfg, reg,
 		"stored_response_fetch_time_seconds",
 		"Seconds to fetch stored responses labeled by fetch type",
@@ -534,6 +585,18 @@ func newCounterWithoutLabels(cfg config.PrometheusMetrics, registry *prometheus.
 	return counter
 }
 
+func newGaugeVec(cfg config.PrometheusMetrics, registry *prometheus.Registry, name, help string, labels []string) *prometheus.GaugeVec {
+	opts := prometheus.GaugeOpts{
+		Namespace: cfg.Namespace,
+		Subsystem: cfg.Subsystem,
+		Name:      name,
+		Help:      help,
+	}
+	gauge := prometheus.NewGaugeVec(opts, labels)
+	registry.MustRegister(gauge)
+	return gauge
+}
+
 func newHistogramVec(cfg config.PrometheusMetrics, registry *prometheus.Registry, name, help string, labels []string, buckets []float64) *prometheus.HistogramVec {
 	opts := prometheus.HistogramOpts{
 		Namespace: cfg.Namespace,
@@ -610,6 +673,22 @@ func (m *Metrics) RecordDebugRequest(debugEnabled bool, pubID string) {
 	}
 }
 
+func (m *Metrics) RecordCompressedRequest(compression metrics.RequestCompression) {
+	m.compressedRequests.With(prometheus.Labels{
+		compressionLabel: string(compression),
+	}).Inc()
+}
+
+func (m *Metrics) RecordCurrencyRatesStale(source string, stale bool) {
+	value := 0.0
+	if stale {
+		value = 1
+	}
+	m.currencyRatesStale.With(prometheus.Labels{
+		sourceLabel: source,
+	}).Set(value)
+}
+
 func (m *Metrics) RecordStoredResponse(pubId string) {
 	m.storedResponses.Inc()
 	if !m.metricsDisabled.AccountStoredResponses && pubId != metrics.PublisherUnknown {
@@ -882,6 +961,49 @@ func (m *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.Bidder
 	}).Inc()
 }
 
+func (m *Metrics) RecordAdapterHealthState(adapterName openrtb_ext.BidderName, state metrics.AdapterHealthState) {
+	for _, s := range metrics.AdapterHealthStates() {
+		value := 0.0
+		if s == state {
+			value = 1
+		}
+		m.adapterHealthState.With(prometheus.Labels{
+			adapterLabel: string(adapterName),
+			stateLabel:   string(s),
+		}).Set(value)
+	}
+}
+
+func (m *Metrics) RecordAdapterHealthBlocked(adapterName openrtb_ext.BidderName) {
+	m.adapterHealthBlocked.With(prometheus.Labels{
+		adapterLabel: string(adapterName),
+	}).Inc()
+}
+
+func (m *Metrics) RecordBidValidationCreativeSizeError(adapterName openrtb_ext.BidderName) {
+	m.adapterBidValidationCreativeSizeError.With(prometheus.Labels{
+		adapterLabel: string(adapterName),
+	}).Inc()
+}
+
+func (m *Metrics) RecordBidValidationCreativeSizeWarn(adapterName openrtb_ext.BidderName) {
+	m.adapterBidValidationCreativeSizeWarn.With(prometheus.Labels{
+		adapterLabel: string(adapterName),
+	}).Inc()
+}
+
+func (m *Metrics) RecordBidValidationSecureMarkupError(adapterName openrtb_ext.BidderName) {
+	m.adapterBidValidationSecureMarkupError.With(prometheus.Labels{
+		adapterLabel: string(adapterName),
+	}).Inc()
+}
+
+func (m *Metrics) RecordBidValidationSecureMarkupWarn(adapterName openrtb_ext.BidderName) {
+	m.adapterBidValidationSecureMarkupWarn.With(prometheus.Labels{
+		adapterLabel: string(adapterName),
+	}).Inc()
+}
+
 func (m *Metrics) RecordAdsCertReq(success bool) {
 	if success {
 		m.adsCertRequests.With(prometheus.Labels{
@@ -206,6 +206,32 @@ func TestDebugRequestMetric(t *testing.T) {
 	}
 }
 
+func TestRecordCompressedRequest(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordCompressedRequest(metrics.RequestCompressionGzip)
+	m.RecordCompressedRequest(metrics.RequestCompressionGzip)
+	m.RecordCompressedRequest(metrics.RequestCompressionDeflate)
+
+	assertCounterVecValue(t, "", "gzip requests", m.compressedRequests, 2, prometheus.Labels{compressionLabel: string(metrics.RequestCompressionGzip)})
+	assertCounterVecValue(t, "", "deflate requests", m.compressedRequests, 1, prometheus.Labels{compressionLabel: string(metrics.RequestCompressionDeflate)})
+}
+
+func TestRecordCurrencyRatesStale(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordCurrencyRatesStale("primary", true)
+	m.RecordCurrencyRatesStale("backup", true)
+	m.RecordCurrencyRatesStale("backup", false)
+
+	expected := map[string]float64{"primary": 1, "backup": 0}
+	for source, value := range expected {
+		gauge := dto.Metric{}
+		m.currencyRatesStale.With(prometheus.Labels{sourceLabel: source}).Write(&gauge)
+		assert.Equal(t, value, gauge.GetGauge().GetValue(), source)
+	}
+}
+
 func TestRequestMetricWithoutCookie(t *testing.T) {
 	requestType := metrics.ReqTypeORTB2Web
 	performTest := func(m *Metrics, cookieFlag metrics.CookieFlag) {
@@ -1113,6 +1139,10 @@ func TestRecordSyncerRequestMetric(t *testing.T) {
 			status: metrics.SyncerCookieSyncTypeNotSupported,
 			label:  "type_not_supported",
 		},
+		{
+			status: metrics.SyncerCookieSyncAccountBlocked,
+			label:  "acct_blocked",
+		},
 	}
 
 	for _, test := range tests {
@@ -1644,6 +1674,87 @@ func TestRecordAdapterGDPRRequestBlocked(t *testing.T) {
 		})
 }
 
+func TestRecordAdapterHealthState(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordAdapterHealthState(openrtb_ext.BidderAppnexus, metrics.AdapterHealthOpen)
+	m.RecordAdapterHealthState(openrtb_ext.BidderAppnexus, metrics.AdapterHealthHalfOpen)
+
+	for _, state := range metrics.AdapterHealthStates() {
+		expected := 0.0
+		if state == metrics.AdapterHealthHalfOpen {
+			expected = 1
+		}
+		gauge := dto.Metric{}
+		m.adapterHealthState.With(prometheus.Labels{
+			adapterLabel: string(openrtb_ext.BidderAppnexus),
+			stateLabel:   string(state),
+		}).Write(&gauge)
+		assert.Equal(t, expected, gauge.GetGauge().GetValue(), string(state))
+	}
+}
+
+func TestRecordAdapterHealthBlocked(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordAdapterHealthBlocked(openrtb_ext.BidderAppnexus)
+
+	assertCounterVecValue(t,
+		"Increment adapter health blocked counter",
+		"adapter_health_requests_blocked",
+		m.adapterHealthBlocked,
+		1,
+		prometheus.Labels{
+			adapterLabel: string(openrtb_ext.BidderAppnexus),
+		})
+}
+
+func TestRecordBidValidation(t *testing.T) {
+	testCases := []struct {
+		description string
+		record      func(m *Metrics)
+		expected    map[string]float64
+	}{
+		{
+			description: "Creative size error",
+			record:      func(m *Metrics) { m.RecordBidValidationCreativeSizeError(openrtb_ext.BidderAppnexus) },
+			expected:    map[string]float64{"size_err": 1},
+		},
+		{
+			description: "Creative size warning",
+			record:      func(m *Metrics) { m.RecordBidValidationCreativeSizeWarn(openrtb_ext.BidderAppnexus) },
+			expected:    map[string]float64{"size_warn": 1},
+		},
+		{
+			description: "Secure markup error",
+			record:      func(m *Metrics) { m.RecordBidValidationSecureMarkupError(openrtb_ext.BidderAppnexus) },
+			expected:    map[string]float64{"secure_err": 1},
+		},
+		{
+			description: "Secure markup warning",
+			record:      func(m *Metrics) { m.RecordBidValidationSecureMarkupWarn(openrtb_ext.BidderAppnexus) },
+			expected:    map[string]float64{"secure_warn": 1},
+		},
+	}
+
+	for _, test := range testCases {
+		m := createMetricsForTesting()
+		test.record(m)
+
+		counters := map[string]*prometheus.CounterVec{
+			"size_err":    m.adapterBidValidationCreativeSizeError,
+			"size_warn":   m.adapterBidValidationCreativeSizeWarn,
+			"secure_err":  m.adapterBidValidationSecureMarkupError,
+			"secure_warn": m.adapterBidValidationSecureMarkupWarn,
+		}
+		for name, counter := range counters {
+			assertCounterVecValue(t, test.description, name, counter, test.expected[name], prometheus.Labels{
+				adapterLabel: string(openrtb_ext.BidderAppnexus),
+			})
+		}
+	}
+}
+
 func TestStoredResponsesMetric(t *testing.T) {
 	testCases := []struct {
 		description                           string
@@ -0,0 +1,61 @@
+package openrtb_ext
+
+import (
+	"fmt"
+	"math"
+)
+
+// Auction types selecting the price paid by the winning bid of an imp
+const (
+	// AuctionTypeFirstPrice makes the winning bid pay its own price
+	AuctionTypeFirstPrice = "first_price"
+	// AuctionTypeSecondPrice makes the winning bid pay the second highest price, or the reserve, plus one increment
+	AuctionTypeSecondPrice = "second_price"
+)
+
+// Reserve types defining how the imp floor takes part in the auction
+const (
+	// ReserveTypeNone ignores the imp floor when computing the clearing price
+	ReserveTypeNone = "none"
+	// ReserveTypeSoft uses the imp floor as the minimum clearing price. Bids below the floor may still win
+	// and pay their own price.
+	ReserveTypeSoft = "soft"
+	// ReserveTypeHard uses the imp floor as the minimum clearing price and removes the bids below it
+	ReserveTypeHard = "hard"
+)
+
+// ExtRequestPrebidAuction defines the contract for bidrequest.ext.prebid.auction
+type ExtRequestPrebidAuction struct {
+	Type      string   `json:"type,omitempty"`
+	Increment *float64 `json:"increment,omitempty"`
+	Reserve   string   `json:"reserve,omitempty"`
+}
+
+// Validate returns an error if the auction type, reserve type or increment is not supported
+func (a *ExtRequestPrebidAuction) Validate() error {
+	if a == nil {
+		return nil
+	}
+	return ValidateAuction(a.Type, a.Reserve, a.Increment)
+}
+
+// ValidateAuction returns an error if the auction type, reserve type or increment is not supported. Empty
+// values are valid and fall back to the next configuration source.
+func ValidateAuction(auctionType, reserveType string, increment *float64) error {
+	switch auctionType {
+	case "", AuctionTypeFirstPrice, AuctionTypeSecondPrice:
+	default:
+		return fmt.Errorf("type %q is not supported", auctionType)
+	}
+
+	switch reserveType {
+	case "", ReserveTypeNone, ReserveTypeSoft, ReserveTypeHard:
+	default:
+		return fmt.Errorf("reserve %q is not supported", reserveType)
+	}
+
+	if increment != nil && (*increment < 0 || math.IsInf(*increment, 0) || math.IsNaN(*increment)) {
+		return fmt.Errorf("increment %v must be a positive number", *increment)
+	}
+	return nil
+}
@@ -14,16 +14,27 @@ type ExtBid struct {
 // DealPriority represents priority of deal bid. If its non deal bid then value will be 0
 // DealTierSatisfied true represents corresponding bid has satisfied the deal tier
 type ExtBidPrebid struct {
-	Cache             *ExtBidPrebidCache  `json:"cache,omitempty"`
-	DealPriority      int                 `json:"dealpriority,omitempty"`
-	DealTierSatisfied bool                `json:"dealtiersatisfied,omitempty"`
-	Meta              *ExtBidPrebidMeta   `json:"meta,omitempty"`
-	Targeting         map[string]string   `json:"targeting,omitempty"`
-	Type              BidType             `json:"type"`
-	Video             *ExtBidPrebidVideo  `json:"video,omitempty"`
-	Events            *ExtBidPrebidEvents `json:"events,omitempty"`
-	BidId             string              `json:"bidid,omitempty"`
-	Passthrough       json.RawMessage     `json:"passthrough,omitempty"`
+	Cache             *ExtBidPrebidCache      `json:"cache,omitempty"`
+	DealPriority      int                     `json:"dealpriority,omitempty"`
+	DealTierSatisfied bool                    `json:"dealtiersatisfied,omitempty"`
+	Meta              *ExtBidPrebidMeta       `json:"meta,omitempty"`
+	Targeting         map[string]string       `json:"targeting,omitempty"`
+	Type              BidType                 `json:"type"`
+	Video             *ExtBidPrebidVideo      `json:"video,omitempty"`
+	Events            *ExtBidPrebidEvents     `json:"events,omitempty"`
+	BidId             string                  `json:"bidid,omitempty"`
+	Passthrough       json.RawMessage         `json:"passthrough,omitempty"`
+	TargetBidderCode  string                  `json:"targetbiddercode,omitempty"`
+	BidAdjustment     *ExtBidPrebidAdjustment `json:"bidadjustment,omitempty"`
+}
+
+// ExtBidPrebidAdjustment defines the contract for bidresponse.seatbid.bid[i].ext.prebid.bidadjustment. It reports
+// the price returned by the bidder and the price after the bid adjustment rules and currency conversion.
+type ExtBidPrebidAdjustment struct {
+	OriginalBidCPM float64 `json:"origbidcpm"`
+	OriginalBidCur string  `json:"origbidcur"`
+	AdjustedBidCPM float64 `json:"adjbidcpm"`
+	AdjustedBidCur string  `json:"adjbidcur"`
 }
 
 // ExtBidPrebidCache defines the contract for  bidresponse.seatbid.bid[i].ext.prebid.cache
@@ -0,0 +1,80 @@
+package openrtb_ext
+
+// Bid adjustment types
+const (
+	// AdjustmentTypeMultiplier multiplies the bid price by the adjustment value
+	AdjustmentTypeMultiplier = "multiplier"
+	// AdjustmentTypeCPM subtracts the adjustment value, expressed in the adjustment currency, from the bid price
+	AdjustmentTypeCPM = "cpm"
+	// AdjustmentTypeStatic replaces the bid price with the adjustment value, expressed in the adjustment currency
+	AdjustmentTypeStatic = "static"
+)
+
+// Bid adjustment media types. Video bids are split between in-stream and out-stream placements.
+const (
+	AdjustmentMediaTypeBanner         = "banner"
+	AdjustmentMediaTypeVideoInstream  = "video-instream"
+	AdjustmentMediaTypeVideoOutstream = "video-outstream"
+	AdjustmentMediaTypeAudio          = "audio"
+	AdjustmentMediaTypeNative         = "native"
+)
+
+// AdjustmentWildCard matches any media type, bidder or deal id
+const AdjustmentWildCard = "*"
+
+// ExtRequestPrebidBidAdjustments defines the contract for bidrequest.ext.prebid.bidadjustments
+type ExtRequestPrebidBidAdjustments struct {
+	MediaType BidAdjustmentsByMediaType `mapstructure:"mediatype" json:"mediatype,omitempty"`
+}
+
+// BidAdjustmentsByMediaType holds the adjustments of each media type, keyed by bidder or alternate bidder code.
+// The WildCard media type and the "*" bidder apply when no more specific entry exists.
+type BidAdjustmentsByMediaType struct {
+	Banner         map[string]AdjustmentsByDealID `mapstructure:"banner" json:"banner,omitempty"`
+	VideoInstream  map[string]AdjustmentsByDealID `mapstructure:"video-instream" json:"video-instream,omitempty"`
+	VideoOutstream map[string]AdjustmentsByDealID `mapstructure:"video-outstream" json:"video-outstream,omitempty"`
+	Audio          map[string]AdjustmentsByDealID `mapstructure:"audio" json:"audio,omitempty"`
+	Native         map[string]AdjustmentsByDealID `mapstructure:"native" json:"native,omitempty"`
+	WildCard       map[string]AdjustmentsByDealID `mapstructure:"*" json:"*,omitempty"`
+}
+
+// AdjustmentsByDealID maps a deal id, or "*" for any bid, to the adjustments applied in order
+type AdjustmentsByDealID map[string][]Adjustment
+
+// Adjustment defines a single bid price adjustment
+type Adjustment struct {
+	Type     string  `mapstructure:"adjtype" json:"adjtype,omitempty"`
+	Value    float64 `mapstructure:"value" json:"value,omitempty"`
+	Currency string  `mapstructure:"currency" json:"currency,omitempty"`
+}
+
+// ByMediaType returns the adjustments by bidder of a media type
+func (m BidAdjustmentsByMediaType) ByMediaType(mediaType string) map[string]AdjustmentsByDealID {
+	switch mediaType {
+	case AdjustmentMediaTypeBanner:
+		return m.Banner
+	case AdjustmentMediaTypeVideoInstream:
+		return m.VideoInstream
+	case AdjustmentMediaTypeVideoOutstream:
+		return m.VideoOutstream
+	case AdjustmentMediaTypeAudio:
+		return m.Audio
+	case AdjustmentMediaTypeNative:
+		return m.Native
+	case AdjustmentWildCard:
+		return m.WildCard
+	}
+	return nil
+}
+
+// AdjustmentMediaTypes lists the media type keys of the bid adjustments, wildcard last
+func AdjustmentMediaTypes() []string {
+	return []string{
+		AdjustmentMediaTypeBanner,
+		AdjustmentMediaTypeVideoInstream,
+		AdjustmentMediaTypeVideoOutstream,
+		AdjustmentMediaTypeAudio,
+		AdjustmentMediaTypeNative,
+		AdjustmentWildCard,
+	}
+}
@@ -155,6 +155,7 @@ const (
 	BidderFreewheelSSPOld   BidderName = "freewheel-ssp"
 	BidderGamma             BidderName = "gamma"
 	BidderGamoshi           BidderName = "gamoshi"
+	BidderGenericORTB       BidderName = "genericortb"
 	BidderGrid              BidderName = "grid"
 	BidderGroupm            BidderName = "groupm"
 	BidderGumGum            BidderName = "gumgum"
@@ -248,175 +249,211 @@ const (
 	BidderZeroClickFraud    BidderName = "zeroclickfraud"
 )
 
-// CoreBidderNames returns a slice of all core bidders.
+// coreBidderNames lists the core bidders followed by the aliases registered with SetAliasBidderName.
+var coreBidderNames = []BidderName{
+	Bidder33Across,
+	BidderAax,
+	BidderAceex,
+	BidderAcuityAds,
+	BidderAdf,
+	BidderAdform,
+	BidderAdgeneration,
+	BidderAdhese,
+	BidderAdkernel,
+	BidderAdkernelAdn,
+	BidderAdman,
+	BidderAdmixer,
+	BidderAdnuntius,
+	BidderAdOcean,
+	BidderAdoppler,
+	BidderAdot,
+	BidderAdpone,
+	BidderAdprime,
+	BidderAdrino,
+	BidderAdtarget,
+	BidderAdtrgtme,
+	BidderAdtelligent,
+	BidderAdvangelists,
+	BidderAdView,
+	BidderAdxcg,
+	BidderAdyoulike,
+	BidderAJA,
+	BidderAlgorix,
+	BidderAMX,
+	BidderApacdex,
+	BidderApplogy,
+	BidderAppnexus,
+	BidderAppush,
+	BidderAudienceNetwork,
+	BidderAutomatad,
+	BidderAvocet,
+	BidderAxonix,
+	BidderBeachfront,
+	BidderBeintoo,
+	BidderBetween,
+	BidderBeyondMedia,
+	BidderBidmachine,
+	BidderBidmyadz,
+	BidderBidsCube,
+	BidderBidstack,
+	BidderBizzclick,
+	BidderBliink,
+	BidderBlue,
+	BidderBmtm,
+	BidderBoldwin,
+	BidderBrightroll,
+	BidderCcx,
+	BidderCoinzilla,
+	BidderColossus,
+	BidderCompass,
+	BidderConnectAd,
+	BidderConsumable,
+	BidderConversant,
+	BidderCpmstar,
+	BidderCriteo,
+	BidderDatablocks,
+	BidderDecenterAds,
+	BidderDeepintent,
+	BidderDianomi,
+	BidderDmx,
+	BidderEmxDigital,
+	BidderEngageBDR,
+	BidderEPlanning,
+	BidderEpom,
+	BidderEVolution,
+	BidderFreewheelSSP,
+	BidderFreewheelSSPOld,
+	BidderGamma,
+	BidderGamoshi,
+	BidderGenericORTB,
+	BidderGrid,
+	BidderGroupm,
+	BidderGumGum,
+	BidderHuaweiAds,
+	BidderImpactify,
+	BidderImprovedigital,
+	BidderInfyTV,
+	BidderInMobi,
+	BidderInteractiveoffers,
+	BidderInvibes,
+	BidderIQZone,
+	BidderIx,
+	BidderJANet,
+	BidderJixie,
+	BidderKargo,
+	BidderKayzen,
+	BidderKidoz,
+	BidderKrushmedia,
+	BidderKubient,
+	BidderLockerDome,
+	BidderLogicad,
+	BidderLunaMedia,
+	BidderMadvertise,
+	BidderMarsmedia,
+	BidderMediafuse,
+	BidderMedianet,
+	BidderMgid,
+	BidderMobfoxpb,
+	BidderMobileFuse,
+	BidderNanoInteractive,
+	BidderNextMillennium,
+	BidderNinthDecimal,
+	BidderNoBid,
+	BidderOFTMedia,
+	BidderOneTag,
+	BidderOpenWeb,
+	BidderOpenx,
+	BidderOperaads,
+	BidderOrbidder,
+	BidderOutbrain,
+	BidderPangle,
+	BidderPGAM,
+	BidderPubmatic,
+	BidderPubnative,
+	BidderPulsepoint,
+	BidderQuantumdex,
+	BidderRevcontent,
+	BidderRhythmone,
+	BidderRichaudience,
+	BidderRTBHouse,
+	BidderRubicon,
+	BidderSeedingAlliance,
+	BidderSaLunaMedia,
+	BidderSharethrough,
+	BidderSilverMob,
+	BidderSmaato,
+	BidderSmartAdserver,
+	BidderSmartHub,
+	BidderSmartRTB,
+	BidderSmartyAds,
+	BidderSmileWanted,
+	BidderSonobi,
+	BidderSovrn,
+	BidderSspBC,
+	BidderStreamkey,
+	BidderStroeerCore,
+	BidderSuntContent,
+	BidderSynacormedia,
+	BidderTaboola,
+	BidderTappx,
+	BidderTelaria,
+	BidderTrafficGate,
+	BidderTriplelift,
+	BidderTripleliftNative,
+	BidderTrustX,
+	BidderUcfunnel,
+	BidderUnicorn,
+	BidderUnruly,
+	BidderValueImpression,
+	BidderVerizonMedia,
+	BidderVideoByte,
+	BidderVidoomy,
+	BidderViewdeos,
+	BidderVisx,
+	BidderVrtcal,
+	BidderYahooSSP,
+	BidderYeahmobi,
+	BidderYieldlab,
+	BidderYieldmo,
+	BidderYieldone,
+	BidderZeroClickFraud,
+}
+
+// CoreBidderNames returns a slice of all core bidders, including the aliases declared in the bidder info files.
 func CoreBidderNames() []BidderName {
-	return []BidderName{
-		Bidder33Across,
-		BidderAax,
-		BidderAceex,
-		BidderAcuityAds,
-		BidderAdf,
-		BidderAdform,
-		BidderAdgeneration,
-		BidderAdhese,
-		BidderAdkernel,
-		BidderAdkernelAdn,
-		BidderAdman,
-		BidderAdmixer,
-		BidderAdnuntius,
-		BidderAdOcean,
-		BidderAdoppler,
-		BidderAdot,
-		BidderAdpone,
-		BidderAdprime,
-		BidderAdrino,
-		BidderAdtarget,
-		BidderAdtrgtme,
-		BidderAdtelligent,
-		BidderAdvangelists,
-		BidderAdView,
-		BidderAdxcg,
-		BidderAdyoulike,
-		BidderAJA,
-		BidderAlgorix,
-		BidderAMX,
-		BidderApacdex,
-		BidderApplogy,
-		BidderAppnexus,
-		BidderAppush,
-		BidderAudienceNetwork,
-		BidderAutomatad,
-		BidderAvocet,
-		BidderAxonix,
-		BidderBeachfront,
-		BidderBeintoo,
-		BidderBetween,
-		BidderBeyondMedia,
-		BidderBidmachine,
-		BidderBidmyadz,
-		BidderBidsCube,
-		BidderBidstack,
-		BidderBizzclick,
-		BidderBliink,
-		BidderBlue,
-		BidderBmtm,
-		BidderBoldwin,
-		BidderBrightroll,
-		BidderCcx,
-		BidderCoinzilla,
-		BidderColossus,
-		BidderCompass,
-		BidderConnectAd,
-		BidderConsumable,
-		BidderConversant,
-		BidderCpmstar,
-		BidderCriteo,
-		BidderDatablocks,
-		BidderDecenterAds,
-		BidderDeepintent,
-		BidderDianomi,
-		BidderDmx,
-		BidderEmxDigital,
-		BidderEngageBDR,
-		BidderEPlanning,
-		BidderEpom,
-		BidderEVolution,
-		BidderFreewheelSSP,
-		BidderFreewheelSSPOld,
-		BidderGamma,
-		BidderGamoshi,
-		BidderGrid,
-		BidderGroupm,
-		BidderGumGum,
-		BidderHuaweiAds,
-		BidderImpactify,
-		BidderImprovedigital,
-		BidderInfyTV,
-		BidderInMobi,
-		BidderInteractiveoffers,
-		BidderInvibes,
-		BidderIQZone,
-		BidderIx,
-		BidderJANet,
-		BidderJixie,
-		BidderKargo,
-		BidderKayzen,
-		BidderKidoz,
-		BidderKrushmedia,
-		BidderKubient,
-		BidderLockerDome,
-		BidderLogicad,
-		BidderLunaMedia,
-		BidderMadvertise,
-		BidderMarsmedia,
-		BidderMediafuse,
-		BidderMedianet,
-		BidderMgid,
-		BidderMobfoxpb,
-		BidderMobileFuse,
-		BidderNanoInteractive,
-		BidderNextMillennium,
-		BidderNinthDecimal,
-		BidderNoBid,
-		BidderOFTMedia,
-		BidderOneTag,
-		BidderOpenWeb,
-		BidderOpenx,
-		BidderOperaads,
-		BidderOrbidder,
-		BidderOutbrain,
-		BidderPangle,
-		BidderPGAM,
-		BidderPubmatic,
-		BidderPubnative,
-		BidderPulsepoint,
-		BidderQuantumdex,
-		BidderRevcontent,
-		BidderRhythmone,
-		BidderRichaudience,
-		BidderRTBHouse,
-		BidderRubicon,
-		BidderSeedingAlliance,
-		BidderSaLunaMedia,
-		BidderSharethrough,
-		BidderSilverMob,
-		BidderSmaato,
-		BidderSmartAdserver,
-		BidderSmartHub,
-		BidderSmartRTB,
-		BidderSmartyAds,
-		BidderSmileWanted,
-		BidderSonobi,
-		BidderSovrn,
-		BidderSspBC,
-		BidderStreamkey,
-		BidderStroeerCore,
-		BidderSuntContent,
-		BidderSynacormedia,
-		BidderTaboola,
-		BidderTappx,
-		BidderTelaria,
-		BidderTrafficGate,
-		BidderTriplelift,
-		BidderTripleliftNative,
-		BidderTrustX,
-		BidderUcfunnel,
-		BidderUnicorn,
-		BidderUnruly,
-		BidderValueImpression,
-		BidderVerizonMedia,
-		BidderVideoByte,
-		BidderVidoomy,
-		BidderViewdeos,
-		BidderVisx,
-		BidderVrtcal,
-		BidderYahooSSP,
-		BidderYeahmobi,
-		BidderYieldlab,
-		BidderYieldmo,
-		BidderYieldone,
-		BidderZeroClickFraud,
+	return append([]BidderName(nil), coreBidderNames...)
+}
+
+// aliasBidderToParent maps the aliases declared in the bidder info files to the core bidder they reuse.
+var aliasBidderToParent = map[BidderName]BidderName{}
+
+// SetAliasBidderName registers an alias declared in the bidder info files as a bidder, so it is accepted
+// everywhere a core bidder is. It must be called during startup, before the bidder names are used.
+func SetAliasBidderName(aliasBidderName string, parentBidderName BidderName) error {
+	if IsBidderNameReserved(aliasBidderName) {
+		return fmt.Errorf("alias %s is a reserved bidder name and cannot be used", aliasBidderName)
+	}
+
+	aliasBidder := BidderName(aliasBidderName)
+	if parent, ok := aliasBidderToParent[aliasBidder]; ok {
+		if parent != parentBidderName {
+			return fmt.Errorf("alias %s is already registered for bidder %s", aliasBidderName, parent)
+		}
+		return nil
 	}
+	if _, ok := NormalizeBidderName(aliasBidderName); ok {
+		return fmt.Errorf("alias %s is already a bidder name and cannot be used", aliasBidderName)
+	}
+
+	coreBidderNames = append(coreBidderNames, aliasBidder)
+	aliasBidderToParent[aliasBidder] = parentBidderName
+	bidderNameLookup[strings.ToLower(aliasBidderName)] = aliasBidder
+	return nil
+}
+
+// GetAliasBidderToParent returns the aliases declared in the bidder info files mapped to their core bidder.
+func GetAliasBidderToParent() map[BidderName]BidderName {
+	return aliasBidderToParent
 }
 
 // BuildBidderMap builds a map of string to BidderName, to remain compatbile with the
@@ -508,6 +545,14 @@ func NewBidderParamsValidator(schemaDirectory string) (BidderParamValidator, err
 		schemaContents[BidderName(bidderName)] = string(fileBytes)
 	}
 
+	// aliases declared in the bidder info files accept the same params as their core bidder
+	for aliasBidderName, parentBidderName := range aliasBidderToParent {
+		if parentSchema, ok := schemas[parentBidderName]; ok {
+			schemas[aliasBidderName] = parentSchema
+			schemaContents[aliasBidderName] = schemaContents[parentBidderName]
+		}
+	}
+
 	return &bidderParamValidator{
 		schemaContents: schemaContents,
 		parsedSchemas:  schemas,
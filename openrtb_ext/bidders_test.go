@@ -125,3 +125,25 @@ func TestIsBidderNameReserved(t *testing.T) {
 		assert.Equal(t, test.expected, result, test.bidder)
 	}
 }
+
+func TestSetAliasBidderName(t *testing.T) {
+	originalCoreBidderNames := coreBidderNames
+	defer func() {
+		coreBidderNames = originalCoreBidderNames
+		aliasBidderToParent = map[BidderName]BidderName{}
+		delete(bidderNameLookup, "whitelabel")
+	}()
+
+	assert.NoError(t, SetAliasBidderName("whiteLabel", BidderAppnexus))
+	assert.NoError(t, SetAliasBidderName("whiteLabel", BidderAppnexus), "registering the same alias again")
+
+	bidderName, exists := NormalizeBidderName("WHITELABEL")
+	assert.True(t, exists)
+	assert.Equal(t, BidderName("whiteLabel"), bidderName)
+	assert.Contains(t, CoreBidderNames(), BidderName("whiteLabel"))
+	assert.Equal(t, map[BidderName]BidderName{"whiteLabel": BidderAppnexus}, GetAliasBidderToParent())
+
+	assert.EqualError(t, SetAliasBidderName("whiteLabel", BidderRubicon), "alias whiteLabel is already registered for bidder appnexus")
+	assert.EqualError(t, SetAliasBidderName("Rubicon", BidderAppnexus), "alias Rubicon is already a bidder name and cannot be used")
+	assert.EqualError(t, SetAliasBidderName("all", BidderAppnexus), "alias all is a reserved bidder name and cannot be used")
+}
@@ -0,0 +1,118 @@
+package openrtb_ext
+
+// Defines the locations a floor rule set can be sourced from
+const (
+	FloorLocationNoData  = "noData"
+	FloorLocationRequest = "request"
+	FloorLocationAccount = "account"
+	FloorLocationFetch   = "fetch"
+)
+
+// Defines the status values reported for a floors fetch
+const (
+	FetchStatusNone       = "none"
+	FetchStatusSuccess    = "success"
+	FetchStatusError      = "error"
+	FetchStatusInprogress = "inprogress"
+	FetchStatusTimeout    = "timeout"
+)
+
+// PriceFloorRules defines the contract for bidrequest.ext.prebid.floors
+type PriceFloorRules struct {
+	FloorMin           float64                `json:"floormin,omitempty"`
+	FloorMinCur        string                 `json:"floormincur,omitempty"`
+	SkipRate           int                    `json:"skiprate,omitempty"`
+	Location           *PriceFloorEndpoint    `json:"floorendpoint,omitempty"`
+	Data               *PriceFloorData        `json:"data,omitempty"`
+	Enforcement        *PriceFloorEnforcement `json:"enforcement,omitempty"`
+	Enabled            *bool                  `json:"enabled,omitempty"`
+	Skipped            *bool                  `json:"skipped,omitempty"`
+	FloorProvider      string                 `json:"floorprovider,omitempty"`
+	FetchStatus        string                 `json:"fetchstatus,omitempty"`
+	PriceFloorLocation string                 `json:"location,omitempty"`
+}
+
+// PriceFloorEndpoint defines the contract for bidrequest.ext.prebid.floors.floorendpoint
+type PriceFloorEndpoint struct {
+	URL string `json:"url,omitempty"`
+}
+
+// PriceFloorData defines the contract for bidrequest.ext.prebid.floors.data
+type PriceFloorData struct {
+	Currency            string                 `json:"currency,omitempty"`
+	SkipRate            int                    `json:"skiprate,omitempty"`
+	FloorsSchemaVersion string                 `json:"floorsschemaversion,omitempty"`
+	ModelTimestamp      int                    `json:"modeltimestamp,omitempty"`
+	ModelGroups         []PriceFloorModelGroup `json:"modelgroups,omitempty"`
+	FloorProvider       string                 `json:"floorprovider,omitempty"`
+}
+
+// PriceFloorModelGroup defines the contract for bidrequest.ext.prebid.floors.data.modelgroups[i]
+type PriceFloorModelGroup struct {
+	Currency     string             `json:"currency,omitempty"`
+	ModelWeight  *int               `json:"modelweight,omitempty"`
+	ModelVersion string             `json:"modelversion,omitempty"`
+	SkipRate     int                `json:"skiprate,omitempty"`
+	Schema       PriceFloorSchema   `json:"schema,omitempty"`
+	Values       map[string]float64 `json:"values,omitempty"`
+	Default      float64            `json:"default,omitempty"`
+}
+
+// PriceFloorSchema defines the contract for bidrequest.ext.prebid.floors.data.modelgroups[i].schema
+type PriceFloorSchema struct {
+	Fields    []string `json:"fields,omitempty"`
+	Delimiter string   `json:"delimiter,omitempty"`
+}
+
+// PriceFloorEnforcement defines the contract for bidrequest.ext.prebid.floors.enforcement
+type PriceFloorEnforcement struct {
+	EnforcePBS  *bool `json:"enforcepbs,omitempty"`
+	FloorDeals  *bool `json:"floordeals,omitempty"`
+	EnforceRate int   `json:"enforcerate,omitempty"`
+}
+
+// ExtImpPrebidFloors defines the contract for bidrequest.imp[i].ext.prebid.floors
+type ExtImpPrebidFloors struct {
+	FloorRule      string  `json:"floorrule,omitempty"`
+	FloorRuleValue float64 `json:"floorrulevalue,omitempty"`
+	FloorValue     float64 `json:"floorvalue,omitempty"`
+	FloorMin       float64 `json:"floormin,omitempty"`
+	FloorMinCur    string  `json:"floormincur,omitempty"`
+}
+
+// GetEnabled returns false only when floors are explicitly disabled on the request
+func (floors *PriceFloorRules) GetEnabled() bool {
+	if floors != nil && floors.Enabled != nil {
+		return *floors.Enabled
+	}
+	return true
+}
+
+// GetEnforcePBS returns false only when floor enforcement is explicitly disabled on the request
+func (floors *PriceFloorRules) GetEnforcePBS() bool {
+	if floors != nil && floors.Enforcement != nil && floors.Enforcement.EnforcePBS != nil {
+		return *floors.Enforcement.EnforcePBS
+	}
+	return true
+}
+
+// GetFloorDeals returns the request level setting for enforcing floors on deal bids and whether it was set
+func (floors *PriceFloorRules) GetFloorDeals() (value, exists bool) {
+	if floors != nil && floors.Enforcement != nil && floors.Enforcement.FloorDeals != nil {
+		return *floors.Enforcement.FloorDeals, true
+	}
+	return false, false
+}
+
+// GetEnforceRate returns the request level enforcement rate, or zero if not set
+func (floors *PriceFloorRules) GetEnforceRate() int {
+	if floors != nil && floors.Enforcement != nil {
+		return floors.Enforcement.EnforceRate
+	}
+	return 0
+}
+
+// IsSkipped reports whether floors were skipped for the auction
+func (floors *PriceFloorRules) IsSkipped() bool {
+	return floors != nil && floors.Skipped != nil && *floors.Skipped
+}
@@ -24,6 +24,9 @@ type ExtImpPrebid struct {
 	Options *Options `json:"options,omitempty"`
 
 	Passthrough json.RawMessage `json:"passthrough,omitempty"`
+
+	// Floors holds the floor resolved for this imp by the price floors module
+	Floors *ExtImpPrebidFloors `json:"floors,omitempty"`
 }
 
 // ExtStoredRequest defines the contract for bidrequest.imp[i].ext.prebid.storedrequest
@@ -0,0 +1,12 @@
+package openrtb_ext
+
+// ExtImpGenericORTB defines the params of the generic OpenRTB adapter which are available to its endpoint
+// template. Any other param is passed to the bidder as is.
+type ExtImpGenericORTB struct {
+	Host        string `json:"host"`
+	PublisherID string `json:"publisherId"`
+	ZoneID      string `json:"zoneId"`
+	SourceID    string `json:"sourceId"`
+	AccountID   string `json:"accountId"`
+	AdUnit      string `json:"adUnit"`
+}
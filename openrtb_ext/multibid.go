@@ -0,0 +1,119 @@
+package openrtb_ext
+
+import (
+	"fmt"
+
+	"github.com/prebid/prebid-server/errortypes"
+)
+
+const (
+	// MaxNumberOfBids is the upper limit of ext.prebid.multibid.maxbids
+	MaxNumberOfBids = 9
+	// DefaultBidLimit is the number of bids kept per bidder per imp when no multibid entry applies
+	DefaultBidLimit = 1
+)
+
+// ExtMultiBid defines the contract for bidrequest.ext.prebid.multibid
+type ExtMultiBid struct {
+	Bidder                 string   `json:"bidder,omitempty"`
+	Bidders                []string `json:"bidders,omitempty"`
+	MaxBids                *int     `json:"maxbids,omitempty"`
+	TargetBidderCodePrefix string   `json:"targetbiddercodeprefix,omitempty"`
+}
+
+func (mb *ExtMultiBid) String() string {
+	if mb == nil {
+		return "<nil>"
+	}
+	maxBids := "<nil>"
+	if mb.MaxBids != nil {
+		maxBids = fmt.Sprintf("%d", *mb.MaxBids)
+	}
+	return fmt.Sprintf("{Bidder:%s, Bidders:%v, MaxBids:%s, TargetBidderCodePrefix:%s}", mb.Bidder, mb.Bidders, maxBids, mb.TargetBidderCodePrefix)
+}
+
+// ValidateAndBuildExtMultiBid validates the multibid entries of the request and returns the ones which can be
+// applied along with a warning for every entry, or part of an entry, which has been ignored or adjusted.
+func ValidateAndBuildExtMultiBid(prebid *ExtRequestPrebid) ([]*ExtMultiBid, []error) {
+	if prebid == nil || len(prebid.MultiBid) == 0 {
+		return nil, nil
+	}
+
+	var validated []*ExtMultiBid
+	var errs []error
+	bidderSeen := make(map[string]struct{})
+
+	for _, multiBid := range prebid.MultiBid {
+		if multiBid == nil {
+			continue
+		}
+
+		entry, entryErrs := validateMultiBidEntry(multiBid, bidderSeen)
+		errs = append(errs, entryErrs...)
+		if entry != nil {
+			validated = append(validated, entry)
+		}
+	}
+
+	return validated, errs
+}
+
+func validateMultiBidEntry(multiBid *ExtMultiBid, bidderSeen map[string]struct{}) (*ExtMultiBid, []error) {
+	var errs []error
+
+	if multiBid.MaxBids == nil {
+		return nil, []error{newMultiBidWarning("maxBids not defined for %v", multiBid)}
+	}
+
+	maxBids := *multiBid.MaxBids
+	if maxBids < DefaultBidLimit {
+		errs = append(errs, newMultiBidWarning("invalid maxBids value, using minimum %d limit", DefaultBidLimit))
+		maxBids = DefaultBidLimit
+	} else if maxBids > MaxNumberOfBids {
+		errs = append(errs, newMultiBidWarning("invalid maxBids value, using maximum %d limit", MaxNumberOfBids))
+		maxBids = MaxNumberOfBids
+	}
+
+	entry := &ExtMultiBid{MaxBids: &maxBids}
+
+	if multiBid.Bidder != "" {
+		if len(multiBid.Bidders) > 0 {
+			errs = append(errs, newMultiBidWarning("ignoring bidders from %v", multiBid))
+		}
+		if _, seen := bidderSeen[multiBid.Bidder]; seen {
+			return nil, append(errs, newMultiBidWarning("multiBid already defined for %s, ignoring this instance %v", multiBid.Bidder, multiBid))
+		}
+		bidderSeen[multiBid.Bidder] = struct{}{}
+		entry.Bidder = multiBid.Bidder
+		entry.TargetBidderCodePrefix = multiBid.TargetBidderCodePrefix
+		return entry, errs
+	}
+
+	if len(multiBid.Bidders) == 0 {
+		return nil, append(errs, newMultiBidWarning("bidder(s) not specified for %v", multiBid))
+	}
+
+	if multiBid.TargetBidderCodePrefix != "" {
+		errs = append(errs, newMultiBidWarning("ignoring targetbiddercodeprefix for %v", multiBid))
+	}
+	for _, bidder := range multiBid.Bidders {
+		if _, seen := bidderSeen[bidder]; seen {
+			errs = append(errs, newMultiBidWarning("multiBid already defined for %s, ignoring this instance %v", bidder, multiBid))
+			continue
+		}
+		bidderSeen[bidder] = struct{}{}
+		entry.Bidders = append(entry.Bidders, bidder)
+	}
+
+	if len(entry.Bidders) == 0 {
+		return nil, errs
+	}
+	return entry, errs
+}
+
+func newMultiBidWarning(format string, args ...interface{}) error {
+	return &errortypes.Warning{
+		Message:     fmt.Sprintf(format, args...),
+		WarningCode: errortypes.MultiBidWarningCode,
+	}
+}
@@ -0,0 +1,119 @@
+package openrtb_ext
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAndBuildExtMultiBid(t *testing.T) {
+	maxBids0, maxBids2, maxBids3, maxBids10 := 0, 2, 3, 10
+	maxBids1, maxBids9 := 1, 9
+
+	testCases := []struct {
+		name             string
+		multiBid         []*ExtMultiBid
+		expected         []*ExtMultiBid
+		expectedWarnings []string
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "single-bidder-with-prefix",
+			multiBid: []*ExtMultiBid{
+				{Bidder: "pubmatic", MaxBids: &maxBids3, TargetBidderCodePrefix: "pm"},
+			},
+			expected: []*ExtMultiBid{
+				{Bidder: "pubmatic", MaxBids: &maxBids3, TargetBidderCodePrefix: "pm"},
+			},
+		},
+		{
+			name: "bidders-list-prefix-ignored",
+			multiBid: []*ExtMultiBid{
+				{Bidders: []string{"appnexus", "rubicon"}, MaxBids: &maxBids2, TargetBidderCodePrefix: "pre"},
+			},
+			expected: []*ExtMultiBid{
+				{Bidders: []string{"appnexus", "rubicon"}, MaxBids: &maxBids2},
+			},
+			expectedWarnings: []string{
+				"ignoring targetbiddercodeprefix for {Bidder:, Bidders:[appnexus rubicon], MaxBids:2, TargetBidderCodePrefix:pre}",
+			},
+		},
+		{
+			name: "bidder-and-bidders-ignores-bidders",
+			multiBid: []*ExtMultiBid{
+				{Bidder: "pubmatic", Bidders: []string{"appnexus"}, MaxBids: &maxBids2},
+			},
+			expected: []*ExtMultiBid{
+				{Bidder: "pubmatic", MaxBids: &maxBids2},
+			},
+			expectedWarnings: []string{
+				"ignoring bidders from {Bidder:pubmatic, Bidders:[appnexus], MaxBids:2, TargetBidderCodePrefix:}",
+			},
+		},
+		{
+			name: "maxbids-missing",
+			multiBid: []*ExtMultiBid{
+				{Bidder: "pubmatic"},
+			},
+			expectedWarnings: []string{
+				"maxBids not defined for {Bidder:pubmatic, Bidders:[], MaxBids:<nil>, TargetBidderCodePrefix:}",
+			},
+		},
+		{
+			name: "maxbids-out-of-range",
+			multiBid: []*ExtMultiBid{
+				{Bidder: "pubmatic", MaxBids: &maxBids0},
+				{Bidder: "appnexus", MaxBids: &maxBids10},
+			},
+			expected: []*ExtMultiBid{
+				{Bidder: "pubmatic", MaxBids: &maxBids1},
+				{Bidder: "appnexus", MaxBids: &maxBids9},
+			},
+			expectedWarnings: []string{
+				"invalid maxBids value, using minimum 1 limit",
+				"invalid maxBids value, using maximum 9 limit",
+			},
+		},
+		{
+			name: "duplicate-bidders",
+			multiBid: []*ExtMultiBid{
+				{Bidder: "pubmatic", MaxBids: &maxBids2},
+				{Bidder: "pubmatic", MaxBids: &maxBids3},
+				{Bidders: []string{"pubmatic", "appnexus"}, MaxBids: &maxBids3},
+			},
+			expected: []*ExtMultiBid{
+				{Bidder: "pubmatic", MaxBids: &maxBids2},
+				{Bidders: []string{"appnexus"}, MaxBids: &maxBids3},
+			},
+			expectedWarnings: []string{
+				"multiBid already defined for pubmatic, ignoring this instance {Bidder:pubmatic, Bidders:[], MaxBids:3, TargetBidderCodePrefix:}",
+				"multiBid already defined for pubmatic, ignoring this instance {Bidder:, Bidders:[pubmatic appnexus], MaxBids:3, TargetBidderCodePrefix:}",
+			},
+		},
+		{
+			name: "no-bidder",
+			multiBid: []*ExtMultiBid{
+				{MaxBids: &maxBids2},
+			},
+			expectedWarnings: []string{
+				"bidder(s) not specified for {Bidder:, Bidders:[], MaxBids:2, TargetBidderCodePrefix:}",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		multiBid, errs := ValidateAndBuildExtMultiBid(&ExtRequestPrebid{MultiBid: test.multiBid})
+
+		assert.Equal(t, test.expected, multiBid, test.name)
+
+		var warnings []string
+		for _, err := range errs {
+			assert.Equal(t, errortypes.MultiBidWarningCode, errortypes.ReadCode(err), test.name)
+			warnings = append(warnings, err.Error())
+		}
+		assert.Equal(t, test.expectedWarnings, warnings, test.name)
+	}
+}
@@ -69,6 +69,28 @@ const ext3 = `{
 	}
 }`
 
+func TestExtRequestTargetingMediaTypePriceGranularity(t *testing.T) {
+	var targeting ExtRequestTargeting
+	err := json.Unmarshal([]byte(`{
+		"pricegranularity": "low",
+		"mediatypepricegranularity": {
+			"video": "high",
+			"native": {"precision": 1, "ranges": [{"max": 10, "increment": 0.5}]}
+		}
+	}`), &targeting)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	videoGranularity := PriceGranularityFromString("high")
+	nativeGranularity := PriceGranularity{Precision: 1, Ranges: []GranularityRange{{Min: 0, Max: 10, Increment: 0.5}}}
+	assert.Equal(t, PriceGranularityFromString("low"), targeting.PriceGranularity)
+	assert.Equal(t, &MediaTypePriceGranularity{Video: &videoGranularity, Native: &nativeGranularity}, targeting.MediaTypePriceGranularity)
+
+	err = json.Unmarshal([]byte(`{"mediatypepricegranularity": {"banner": {"ranges": [{"max": 10, "increment": 0}]}}}`), &targeting)
+	assert.EqualError(t, err, "Price granularity error: increment must be a nonzero positive number")
+}
+
 type granularityTestData struct {
 	json   []byte
 	target PriceGranularity
@@ -41,6 +41,9 @@ type ExtResponseSyncData struct {
 type ExtResponsePrebid struct {
 	AuctionTimestamp int64           `json:"auctiontimestamp,omitempty"`
 	Passthrough      json.RawMessage `json:"passthrough,omitempty"`
+	// SeatNonBid defines the contract for bidresponse.ext.prebid.seatnonbid, only returned when
+	// ext.prebid.returnallbidstatus is set on the request
+	SeatNonBid []SeatNonBid `json:"seatnonbid,omitempty"`
 }
 
 // ExtUserSync defines the contract for bidresponse.ext.usersync.{bidder}.syncs[i]
@@ -0,0 +1,60 @@
+package openrtb_ext
+
+// NonBidStatusCode is the reason a seat did not bid, or had its bid rejected, on an imp. The values follow
+// the status codes of the OpenRTB seat non-bid community extension.
+type NonBidStatusCode int
+
+const (
+	NoBid                                   NonBidStatusCode = 0
+	ErrorGeneral                            NonBidStatusCode = 100
+	ErrorTimeout                            NonBidStatusCode = 101
+	ErrorInvalidBidResponse                 NonBidStatusCode = 102
+	ErrorBidderUnreachable                  NonBidStatusCode = 103
+	RequestBlockedGeneral                   NonBidStatusCode = 200
+	ResponseRejectedGeneral                 NonBidStatusCode = 300
+	ResponseRejectedBelowFloor              NonBidStatusCode = 301
+	ResponseRejectedDuplicate               NonBidStatusCode = 302
+	ResponseRejectedCategoryMappingInvalid  NonBidStatusCode = 303
+	ResponseRejectedBelowDealFloor          NonBidStatusCode = 304
+	ResponseRejectedInvalidCreative         NonBidStatusCode = 350
+	ResponseRejectedCreativeSizeNotAllowed  NonBidStatusCode = 351
+	ResponseRejectedCreativeNotSecure       NonBidStatusCode = 352
+	ResponseRejectedAdvertiserExclusions    NonBidStatusCode = 353
+	ResponseRejectedAdvertiserBlockedByAttr NonBidStatusCode = 354
+)
+
+// SeatNonBid defines the contract for bidresponse.ext.prebid.seatnonbid[i]
+type SeatNonBid struct {
+	Seat   string   `json:"seat"`
+	NonBid []NonBid `json:"nonbid"`
+}
+
+// NonBid defines the contract for bidresponse.ext.prebid.seatnonbid[i].nonbid[j]
+type NonBid struct {
+	ImpId      string           `json:"impid"`
+	StatusCode NonBidStatusCode `json:"statuscode"`
+	Ext        *NonBidExt       `json:"ext,omitempty"`
+}
+
+// NonBidExt defines the contract for bidresponse.ext.prebid.seatnonbid[i].nonbid[j].ext
+type NonBidExt struct {
+	Prebid ExtResponseNonBidPrebid `json:"prebid"`
+}
+
+// ExtResponseNonBidPrebid defines the contract for bidresponse.ext.prebid.seatnonbid[i].nonbid[j].ext.prebid
+type ExtResponseNonBidPrebid struct {
+	Bid NonBidObject `json:"bid"`
+}
+
+// NonBidObject holds the details of a rejected bid, as it was when it was rejected
+type NonBidObject struct {
+	ID             string   `json:"id"`
+	Price          float64  `json:"price"`
+	ADomain        []string `json:"adomain,omitempty"`
+	Cat            []string `json:"cat,omitempty"`
+	DealID         string   `json:"dealid,omitempty"`
+	W              int64    `json:"w,omitempty"`
+	H              int64    `json:"h,omitempty"`
+	OriginalBidCPM float64  `json:"origbidcpm,omitempty"`
+	OriginalBidCur string   `json:"origbidcur,omitempty"`
+}
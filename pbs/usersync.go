@@ -22,6 +22,8 @@ type UserSyncDeps struct {
 	ExternalUrl      string
 	RecaptchaSecret  string
 	HostCookieConfig *config.HostCookie
+	// UIDStore is cleared on opt-out, if the host keeps the user syncs on the server
+	UIDStore usersync.UIDStore
 }
 
 // Struct for parsing json in google's response
@@ -76,7 +78,18 @@ func (deps *UserSyncDeps) OptOut(w http.ResponseWriter, r *http.Request, _ httpr
 	pc := usersync.ParseCookieFromRequest(r, deps.HostCookieConfig)
 	pc.SetOptOut(optout != "")
 
-	pc.SetCookieOnResponse(w, false, deps.HostCookieConfig, deps.HostCookieConfig.TTLDuration())
+	// the host cookie is the only id the UID store keeps user syncs under, so this clears all of them
+	if optout != "" && deps.UIDStore != nil {
+		if id := usersync.UIDStoreHostCookieID(r, deps.HostCookieConfig); id != "" {
+			if err := deps.UIDStore.Delete(id); err != nil {
+				glog.Errorf("Failed to clear the uid store on opt out: %v", err)
+			}
+		}
+	}
+
+	cookieAttributes := usersync.NewCookieAttributes(deps.HostCookieConfig, config.AccountCookie{}, r.UserAgent())
+	pc.SetCookieOnResponse(w, cookieAttributes, deps.HostCookieConfig, deps.HostCookieConfig.TTLDuration(), nil)
+	usersync.SetOptOutCookieOnResponse(w, deps.HostCookieConfig, cookieAttributes, deps.HostCookieConfig.TTLDuration(), optout != "")
 
 	if optout == "" {
 		http.Redirect(w, r, deps.HostCookieConfig.OptInURL, http.StatusMovedPermanently)
@@ -72,7 +72,10 @@ type clientImpl struct {
 }
 
 func (c *clientImpl) GetExtCacheData() (string, string, string) {
-	path := c.externalCachePath
+	return c.externalCacheScheme, c.externalCacheHost, normalizeExtCachePath(c.externalCachePath)
+}
+
+func normalizeExtCachePath(path string) string {
 	if path == "/" {
 		// Only the slash for the path, remove it to empty
 		path = ""
@@ -80,8 +83,7 @@ func (c *clientImpl) GetExtCacheData() (string, string, string) {
 		// Path defined but does not start with "/", prepend it
 		path = "/" + path
 	}
-
-	return c.externalCacheScheme, c.externalCacheHost, path
+	return path
 }
 
 func (c *clientImpl) PutJson(ctx context.Context, values []Cacheable) (uuids []string, errs []error) {
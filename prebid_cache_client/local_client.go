@@ -0,0 +1,130 @@
+package prebid_cache_client
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/metrics"
+	"github.com/prebid/prebid-server/util/uuidutil"
+)
+
+// NewLocalClient returns a Client which stores the values in the embedded cache instead of calling Prebid Cache.
+// The values are served by the /cache endpoint of this server, so the external cache url defaults to the
+// external url of the server when external_cache is not configured.
+func NewLocalClient(store *Store, conf *config.EmbeddedCache, extCache *config.ExternalCache, externalURL string, metrics metrics.MetricsEngine) Client {
+	client := newLocalClient(store, conf, metrics)
+	client.put = store.Put
+
+	if extCache.Host != "" {
+		client.externalCacheScheme = extCache.Scheme
+		client.externalCacheHost = extCache.Host
+		client.externalCachePath = normalizeExtCachePath(extCache.Path)
+	} else if serverURL, err := url.Parse(externalURL); err == nil {
+		client.externalCacheScheme = serverURL.Scheme
+		client.externalCacheHost = serverURL.Host
+		client.externalCachePath = strings.TrimSuffix(serverURL.Path, "/") + "/cache"
+	}
+	return client
+}
+
+// NewLocalUploadClient returns a Client which stores the values uploaded to the /cache endpoint in the embedded
+// cache. Unlike the values of the auctions, they are refused when the cache is full of values which have not
+// expired yet.
+func NewLocalUploadClient(store *Store, conf *config.EmbeddedCache, metrics metrics.MetricsEngine) Client {
+	client := newLocalClient(store, conf, metrics)
+	client.put = store.PutIfRoom
+	return client
+}
+
+func newLocalClient(store *Store, conf *config.EmbeddedCache, metrics metrics.MetricsEngine) *localClient {
+	return &localClient{
+		store:         store,
+		defaultTTL:    time.Duration(conf.DefaultTTLSeconds) * time.Second,
+		maxTTL:        time.Duration(conf.MaxTTLSeconds) * time.Second,
+		uuidGenerator: uuidutil.UUIDRandomGenerator{},
+		metrics:       metrics,
+	}
+}
+
+type localClient struct {
+	store               *Store
+	put                 func(key string, value StoredValue, ttl time.Duration) error
+	defaultTTL          time.Duration
+	maxTTL              time.Duration
+	uuidGenerator       uuidutil.UUIDGenerator
+	externalCacheScheme string
+	externalCacheHost   string
+	externalCachePath   string
+	metrics             metrics.MetricsEngine
+}
+
+func (c *localClient) GetExtCacheData() (string, string, string) {
+	return c.externalCacheScheme, c.externalCacheHost, c.externalCachePath
+}
+
+func (c *localClient) PutJson(ctx context.Context, values []Cacheable) (uuids []string, errs []error) {
+	errs = make([]error, 0, 1)
+	if len(values) < 1 {
+		return nil, errs
+	}
+
+	startTime := time.Now()
+	uuidsToReturn := make([]string, len(values))
+	for i, value := range values {
+		storedValue, err := toStoredValue(value)
+		if err != nil {
+			logError(&errs, "Embedded cache value at index %d is not valid: %v", i, err)
+			continue
+		}
+
+		key := value.Key
+		if key == "" {
+			if key, err = c.uuidGenerator.Generate(); err != nil {
+				logError(&errs, "Error generating a uuid for the embedded cache value at index %d: %v", i, err)
+				continue
+			}
+		}
+
+		if err := c.put(key, storedValue, c.ttl(value.TTLSeconds)); err != nil {
+			logError(&errs, "Error storing the embedded cache value at index %d with key %s: %v", i, key, err)
+			continue
+		}
+		uuidsToReturn[i] = key
+	}
+	c.metrics.RecordPrebidCacheRequestTime(len(errs) == 0, time.Since(startTime))
+
+	return uuidsToReturn, errs
+}
+
+func (c *localClient) ttl(ttlSeconds int64) time.Duration {
+	if ttlSeconds <= 0 {
+		return c.defaultTTL
+	}
+	if ttl := time.Duration(ttlSeconds) * time.Second; ttl < c.maxTTL {
+		return ttl
+	}
+	return c.maxTTL
+}
+
+// toStoredValue validates the value like Prebid Cache does. XML values are sent as JSON strings and are
+// stored unquoted so they can be served as is.
+func toStoredValue(value Cacheable) (StoredValue, error) {
+	switch value.Type {
+	case TypeXML:
+		var xml string
+		if err := json.Unmarshal(value.Data, &xml); err != nil {
+			return StoredValue{}, err
+		}
+		return StoredValue{Type: TypeXML, Data: []byte(xml)}, nil
+	case TypeJSON:
+		if !json.Valid(value.Data) {
+			return StoredValue{}, errInvalidJSON
+		}
+		return StoredValue{Type: TypeJSON, Data: value.Data}, nil
+	}
+	return StoredValue{}, errUnknownType
+}
@@ -0,0 +1,136 @@
+package prebid_cache_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/metrics"
+	metricsConf "github.com/prebid/prebid-server/metrics/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type fakeUUIDGenerator struct {
+	id  string
+	err error
+}
+
+func (f fakeUUIDGenerator) Generate() (string, error) {
+	return f.id, f.err
+}
+
+func newTestLocalClient(uuidGenerator fakeUUIDGenerator) (*localClient, *metrics.MetricsEngineMock) {
+	metricsMock := &metrics.MetricsEngineMock{}
+	metricsMock.On("RecordPrebidCacheRequestTime", mock.Anything, mock.Anything).Return()
+
+	conf := &config.EmbeddedCache{Enabled: true, MaxSizeBytes: 1024, DefaultTTLSeconds: 300, MaxTTLSeconds: 3600}
+	client := NewLocalClient(NewStore(conf.MaxSizeBytes), conf, &config.ExternalCache{}, "", metricsMock).(*localClient)
+	client.uuidGenerator = uuidGenerator
+	return client, metricsMock
+}
+
+func TestLocalClientPutJson(t *testing.T) {
+	client, metricsMock := newTestLocalClient(fakeUUIDGenerator{id: "generated"})
+
+	ids, errs := client.PutJson(context.Background(), []Cacheable{
+		{Type: TypeXML, Data: json.RawMessage(`"<VAST></VAST>"`), Key: "custom"},
+		{Type: TypeJSON, Data: json.RawMessage(`{"id":"bid"}`)},
+	})
+
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"custom", "generated"}, ids)
+	xml, _ := client.store.Get("custom")
+	assert.Equal(t, StoredValue{Type: TypeXML, Data: []byte("<VAST></VAST>")}, xml)
+	jsonValue, _ := client.store.Get("generated")
+	assert.Equal(t, StoredValue{Type: TypeJSON, Data: []byte(`{"id":"bid"}`)}, jsonValue)
+	metricsMock.AssertCalled(t, "RecordPrebidCacheRequestTime", true, mock.Anything)
+}
+
+func TestLocalClientPutJsonErrors(t *testing.T) {
+	testCases := []struct {
+		description   string
+		value         Cacheable
+		uuidGenerator fakeUUIDGenerator
+	}{
+		{
+			description:   "XML not a string",
+			value:         Cacheable{Type: TypeXML, Data: json.RawMessage(`{}`)},
+			uuidGenerator: fakeUUIDGenerator{id: "generated"},
+		},
+		{
+			description:   "Invalid JSON",
+			value:         Cacheable{Type: TypeJSON, Data: json.RawMessage(`{`)},
+			uuidGenerator: fakeUUIDGenerator{id: "generated"},
+		},
+		{
+			description:   "Unknown type",
+			value:         Cacheable{Type: "html", Data: json.RawMessage(`"<div></div>"`)},
+			uuidGenerator: fakeUUIDGenerator{id: "generated"},
+		},
+		{
+			description:   "UUID generation error",
+			value:         Cacheable{Type: TypeJSON, Data: json.RawMessage(`{}`)},
+			uuidGenerator: fakeUUIDGenerator{err: errors.New("no entropy")},
+		},
+	}
+
+	for _, test := range testCases {
+		client, metricsMock := newTestLocalClient(test.uuidGenerator)
+
+		ids, errs := client.PutJson(context.Background(), []Cacheable{test.value})
+
+		assert.Equal(t, []string{""}, ids, test.description)
+		assert.Len(t, errs, 1, test.description)
+		metricsMock.AssertCalled(t, "RecordPrebidCacheRequestTime", false, mock.Anything)
+	}
+}
+
+func TestLocalClientTTL(t *testing.T) {
+	client, _ := newTestLocalClient(fakeUUIDGenerator{})
+
+	assert.Equal(t, 300*time.Second, client.ttl(0), "default")
+	assert.Equal(t, 60*time.Second, client.ttl(60), "requested")
+	assert.Equal(t, 3600*time.Second, client.ttl(7200), "capped")
+}
+
+func TestLocalClientGetExtCacheData(t *testing.T) {
+	testCases := []struct {
+		description    string
+		extCache       config.ExternalCache
+		externalURL    string
+		expectedScheme string
+		expectedHost   string
+		expectedPath   string
+	}{
+		{
+			description:    "External cache configured",
+			extCache:       config.ExternalCache{Scheme: "https", Host: "cache.prebid.com", Path: "pbcache/endpoint"},
+			externalURL:    "https://prebid.com",
+			expectedScheme: "https",
+			expectedHost:   "cache.prebid.com",
+			expectedPath:   "/pbcache/endpoint",
+		},
+		{
+			description:    "Derived from external url",
+			externalURL:    "https://prebid.com/pbs/",
+			expectedScheme: "https",
+			expectedHost:   "prebid.com",
+			expectedPath:   "/pbs/cache",
+		},
+	}
+
+	for _, test := range testCases {
+		conf := &config.EmbeddedCache{Enabled: true, MaxSizeBytes: 1024, DefaultTTLSeconds: 300, MaxTTLSeconds: 3600}
+		client := NewLocalClient(NewStore(conf.MaxSizeBytes), conf, &test.extCache, test.externalURL, &metricsConf.NilMetricsEngine{})
+
+		scheme, host, path := client.GetExtCacheData()
+
+		assert.Equal(t, test.expectedScheme, scheme, test.description)
+		assert.Equal(t, test.expectedHost, host, test.description)
+		assert.Equal(t, test.expectedPath, path, test.description)
+	}
+}
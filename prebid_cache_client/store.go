@@ -0,0 +1,133 @@
+package prebid_cache_client
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/util/timeutil"
+)
+
+var (
+	errKeyExists     = errors.New("a value is already stored under this key")
+	errValueTooLarge = errors.New("the value is larger than the cache")
+	errInvalidJSON   = errors.New("json values must be valid JSON")
+	errUnknownType   = errors.New("type must be json or xml")
+	errStoreFull     = errors.New("the cache is full")
+)
+
+// StoredValue is a value held by the embedded cache. XML values hold the raw markup rather than a JSON string.
+type StoredValue struct {
+	Type PayloadType
+	Data []byte
+}
+
+// Store is the size bounded in-memory store of the embedded cache. Values expire after their TTL. When a new
+// value would not fit, the expired values are evicted first, then the oldest values unless the value comes from
+// an untrusted source.
+type Store struct {
+	maxSizeBytes int64
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	size    int64
+
+	time timeutil.Time
+}
+
+type storeEntry struct {
+	key        string
+	value      StoredValue
+	expiration time.Time
+}
+
+func NewStore(maxSizeBytes int64) *Store {
+	return &Store{
+		maxSizeBytes: maxSizeBytes,
+		entries:      make(map[string]*list.Element),
+		order:        list.New(),
+		time:         &timeutil.RealTime{},
+	}
+}
+
+// Put stores the value under the key for the ttl, evicting the oldest values when it would not fit otherwise.
+// Like Prebid Cache, it refuses to overwrite a value which has not expired yet.
+func (s *Store) Put(key string, value StoredValue, ttl time.Duration) error {
+	return s.put(key, value, ttl, true)
+}
+
+// PutIfRoom stores the value like Put, but returns an error rather than evicting values which have not expired
+// when it would not fit. It is meant for the values uploaded to the /cache endpoint, so that they can't flush
+// the values written by the auctions.
+func (s *Store) PutIfRoom(key string, value StoredValue, ttl time.Duration) error {
+	return s.put(key, value, ttl, false)
+}
+
+func (s *Store) put(key string, value StoredValue, ttl time.Duration, evictLive bool) error {
+	valueSize := int64(len(key) + len(value.Data))
+	if valueSize > s.maxSizeBytes {
+		return errValueTooLarge
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.time.Now()
+	if element, ok := s.entries[key]; ok {
+		if now.Before(element.Value.(*storeEntry).expiration) {
+			return errKeyExists
+		}
+		s.remove(element)
+	}
+
+	if s.size+valueSize > s.maxSizeBytes {
+		s.removeExpired(now)
+	}
+	if s.size+valueSize > s.maxSizeBytes && !evictLive {
+		return errStoreFull
+	}
+	for front := s.order.Front(); front != nil && s.size+valueSize > s.maxSizeBytes; front = s.order.Front() {
+		s.remove(front)
+	}
+
+	s.entries[key] = s.order.PushBack(&storeEntry{key: key, value: value, expiration: now.Add(ttl)})
+	s.size += valueSize
+	return nil
+}
+
+// Get returns the value stored under the key, or false if there is none or it has expired
+func (s *Store) Get(key string) (StoredValue, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return StoredValue{}, false
+	}
+	entry := element.Value.(*storeEntry)
+	if !s.time.Now().Before(entry.expiration) {
+		s.remove(element)
+		return StoredValue{}, false
+	}
+	return entry.value, true
+}
+
+func (s *Store) remove(element *list.Element) {
+	entry := s.order.Remove(element).(*storeEntry)
+	delete(s.entries, entry.key)
+	s.size -= int64(len(entry.key) + len(entry.value.Data))
+}
+
+// removeExpired removes the values which have expired. Values have different ttls, so they can expire in
+// any order.
+func (s *Store) removeExpired(now time.Time) {
+	for element := s.order.Front(); element != nil; {
+		next := element.Next()
+		if !now.Before(element.Value.(*storeEntry).expiration) {
+			s.remove(element)
+		}
+		element = next
+	}
+}
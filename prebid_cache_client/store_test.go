@@ -0,0 +1,106 @@
+package prebid_cache_client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTime struct {
+	time time.Time
+}
+
+func (f *fakeTime) Now() time.Time {
+	return f.time
+}
+
+func newTestStore(maxSizeBytes int64) (*Store, *fakeTime) {
+	clock := &fakeTime{time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := NewStore(maxSizeBytes)
+	store.time = clock
+	return store, clock
+}
+
+func TestStorePutGet(t *testing.T) {
+	store, _ := newTestStore(100)
+	value := StoredValue{Type: TypeXML, Data: []byte("<VAST></VAST>")}
+
+	assert.NoError(t, store.Put("key", value, time.Minute))
+
+	stored, ok := store.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, value, stored)
+
+	_, ok = store.Get("other")
+	assert.False(t, ok)
+}
+
+func TestStoreExpiration(t *testing.T) {
+	store, clock := newTestStore(100)
+	value := StoredValue{Type: TypeJSON, Data: []byte("{}")}
+
+	assert.NoError(t, store.Put("key", value, time.Minute))
+	assert.Equal(t, errKeyExists, store.Put("key", value, time.Minute), "key not expired yet")
+
+	clock.time = clock.time.Add(time.Minute)
+
+	_, ok := store.Get("key")
+	assert.False(t, ok)
+	assert.Zero(t, store.size)
+	assert.NoError(t, store.Put("key", value, time.Minute), "expired key can be reused")
+}
+
+func TestStoreEviction(t *testing.T) {
+	store, _ := newTestStore(10)
+	value := StoredValue{Type: TypeJSON, Data: []byte("1234")}
+
+	assert.NoError(t, store.Put("a", value, time.Minute))
+	assert.NoError(t, store.Put("b", value, time.Minute))
+	assert.NoError(t, store.Put("c", value, time.Minute))
+
+	_, ok := store.Get("a")
+	assert.False(t, ok, "oldest value evicted")
+	_, ok = store.Get("b")
+	assert.True(t, ok)
+	_, ok = store.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, int64(10), store.size)
+
+	assert.Equal(t, errValueTooLarge, store.Put("d", StoredValue{Type: TypeJSON, Data: []byte("1234567890")}, time.Minute))
+}
+
+func TestStoreEvictsExpiredValuesFirst(t *testing.T) {
+	store, clock := newTestStore(10)
+	value := StoredValue{Type: TypeJSON, Data: []byte("1234")}
+
+	assert.NoError(t, store.Put("a", value, time.Hour))
+	assert.NoError(t, store.Put("b", value, time.Minute))
+	clock.time = clock.time.Add(time.Minute)
+	assert.NoError(t, store.Put("c", value, time.Minute))
+
+	_, ok := store.Get("a")
+	assert.True(t, ok, "oldest value kept")
+	_, ok = store.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, int64(10), store.size)
+}
+
+func TestStorePutIfRoom(t *testing.T) {
+	store, clock := newTestStore(10)
+	value := StoredValue{Type: TypeJSON, Data: []byte("1234")}
+
+	assert.NoError(t, store.PutIfRoom("a", value, time.Minute))
+	assert.NoError(t, store.Put("b", value, time.Hour))
+	assert.Equal(t, errStoreFull, store.PutIfRoom("c", value, time.Minute), "live values are not evicted")
+
+	_, ok := store.Get("a")
+	assert.True(t, ok)
+	_, ok = store.Get("b")
+	assert.True(t, ok)
+
+	clock.time = clock.time.Add(time.Minute)
+	assert.NoError(t, store.PutIfRoom("c", value, time.Minute), "expired values are evicted")
+	_, ok = store.Get("c")
+	assert.True(t, ok)
+}
@@ -0,0 +1,149 @@
+package privacy
+
+import (
+	"strings"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/config"
+)
+
+// Activity identifies a privacy sensitive activity a component may perform.
+type Activity int
+
+const (
+	ActivitySyncUser Activity = iota
+	ActivityFetchBids
+	ActivityTransmitUserFPD
+	ActivityTransmitPreciseGeo
+	ActivityReportAnalytics
+)
+
+// Component identifies the bidder, analytics adapter or module performing an activity.
+type Component struct {
+	Type string
+	Name string
+}
+
+// ActivityRequest holds the request attributes the activity rule conditions are matched against.
+type ActivityRequest struct {
+	GPPSID  []int8
+	Country string
+	Region  string
+}
+
+// NewActivityRequest reads the GPP applicable sections and the device geo location of an OpenRTB bid request.
+func NewActivityRequest(req *openrtb2.BidRequest) ActivityRequest {
+	var activityRequest ActivityRequest
+	if req == nil {
+		return activityRequest
+	}
+	if req.Regs != nil {
+		activityRequest.GPPSID = req.Regs.GPPSID
+	}
+	if req.Device != nil && req.Device.Geo != nil {
+		activityRequest.Country = req.Device.Geo.Country
+		activityRequest.Region = req.Device.Geo.Region
+	}
+	return activityRequest
+}
+
+// ActivityControl decides whether a component may perform an activity based on the account activity rules.
+// The zero value allows all activities.
+type ActivityControl struct {
+	activities map[Activity]config.Activity
+}
+
+// NewActivityControl builds the ActivityControl of an account. Activities without rules or default are
+// left out, so an account without activity controls gets the zero value.
+func NewActivityControl(cfg config.AccountActivities) ActivityControl {
+	activities := map[Activity]config.Activity{
+		ActivitySyncUser:           cfg.SyncUser,
+		ActivityFetchBids:          cfg.FetchBids,
+		ActivityTransmitUserFPD:    cfg.TransmitUserFPD,
+		ActivityTransmitPreciseGeo: cfg.TransmitPreciseGeo,
+		ActivityReportAnalytics:    cfg.ReportAnalytics,
+	}
+	for activity, activityCfg := range activities {
+		if activityCfg.Default == nil && len(activityCfg.Rules) == 0 {
+			delete(activities, activity)
+		}
+	}
+
+	if len(activities) == 0 {
+		return ActivityControl{}
+	}
+	return ActivityControl{activities: activities}
+}
+
+// Allow returns the decision of the first rule of the activity matching the component and request,
+// falling back to the activity default.
+func (c ActivityControl) Allow(activity Activity, component Component, request ActivityRequest) bool {
+	cfg, ok := c.activities[activity]
+	if !ok {
+		return true
+	}
+
+	for _, rule := range cfg.Rules {
+		if conditionMatches(rule.Condition, component, request) {
+			return rule.Allow
+		}
+	}
+
+	if cfg.Default != nil {
+		return *cfg.Default
+	}
+	return true
+}
+
+func conditionMatches(condition config.ActivityCondition, component Component, request ActivityRequest) bool {
+	if len(condition.ComponentType) > 0 && !containsFold(condition.ComponentType, component.Type) {
+		return false
+	}
+	if len(condition.ComponentName) > 0 && !containsFold(condition.ComponentName, component.Name) {
+		return false
+	}
+	if len(condition.GPPSID) > 0 && !intersects(condition.GPPSID, request.GPPSID) {
+		return false
+	}
+	if len(condition.Geo) > 0 && !geoMatches(condition.Geo, request.Country, request.Region) {
+		return false
+	}
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func intersects(a, b []int8) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func geoMatches(geos []string, country, region string) bool {
+	if country == "" {
+		return false
+	}
+
+	for _, geo := range geos {
+		geoCountry, geoRegion, hasRegion := strings.Cut(geo, ".")
+		if !strings.EqualFold(geoCountry, country) {
+			continue
+		}
+		if !hasRegion || strings.EqualFold(geoRegion, region) {
+			return true
+		}
+	}
+	return false
+}
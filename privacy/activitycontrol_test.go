@@ -0,0 +1,179 @@
+package privacy
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewActivityRequest(t *testing.T) {
+	testCases := []struct {
+		description     string
+		givenRequest    *openrtb2.BidRequest
+		expectedRequest ActivityRequest
+	}{
+		{
+			description:     "Nil",
+			givenRequest:    nil,
+			expectedRequest: ActivityRequest{},
+		},
+		{
+			description:     "Empty",
+			givenRequest:    &openrtb2.BidRequest{},
+			expectedRequest: ActivityRequest{},
+		},
+		{
+			description: "GPP SID And Geo",
+			givenRequest: &openrtb2.BidRequest{
+				Regs:   &openrtb2.Regs{GPPSID: []int8{7, 8}},
+				Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA", Region: "CA"}},
+			},
+			expectedRequest: ActivityRequest{GPPSID: []int8{7, 8}, Country: "USA", Region: "CA"},
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expectedRequest, NewActivityRequest(test.givenRequest), test.description)
+	}
+}
+
+func TestActivityControlAllow(t *testing.T) {
+	falseValue := false
+	bidderA := Component{Type: config.ComponentTypeBidder, Name: "bidderA"}
+	bidderB := Component{Type: config.ComponentTypeBidder, Name: "bidderB"}
+	analyticsA := Component{Type: config.ComponentTypeAnalytics, Name: "bidderA"}
+	california := ActivityRequest{GPPSID: []int8{8}, Country: "USA", Region: "CA"}
+	virginia := ActivityRequest{GPPSID: []int8{9}, Country: "usa", Region: "VA"}
+
+	testCases := []struct {
+		description      string
+		givenActivity    config.Activity
+		givenComponent   Component
+		givenRequest     ActivityRequest
+		expectedDecision bool
+	}{
+		{
+			description:      "No Rules - Allowed",
+			givenActivity:    config.Activity{},
+			givenComponent:   bidderA,
+			expectedDecision: true,
+		},
+		{
+			description:      "No Rules - Default Denied",
+			givenActivity:    config.Activity{Default: &falseValue},
+			givenComponent:   bidderA,
+			expectedDecision: false,
+		},
+		{
+			description: "Component Name Match",
+			givenActivity: config.Activity{Rules: []config.ActivityRule{
+				{Condition: config.ActivityCondition{ComponentName: []string{"BIDDERA"}}, Allow: false},
+			}},
+			givenComponent:   bidderA,
+			expectedDecision: false,
+		},
+		{
+			description: "Component Name No Match",
+			givenActivity: config.Activity{Rules: []config.ActivityRule{
+				{Condition: config.ActivityCondition{ComponentName: []string{"bidderA"}}, Allow: false},
+			}},
+			givenComponent:   bidderB,
+			expectedDecision: true,
+		},
+		{
+			description: "Component Type No Match",
+			givenActivity: config.Activity{Rules: []config.ActivityRule{
+				{Condition: config.ActivityCondition{ComponentType: []string{"bidder"}, ComponentName: []string{"bidderA"}}, Allow: false},
+			}},
+			givenComponent:   analyticsA,
+			expectedDecision: true,
+		},
+		{
+			description: "First Matching Rule Wins",
+			givenActivity: config.Activity{Rules: []config.ActivityRule{
+				{Condition: config.ActivityCondition{ComponentName: []string{"bidderA"}}, Allow: true},
+				{Condition: config.ActivityCondition{ComponentType: []string{"bidder"}}, Allow: false},
+			}},
+			givenComponent:   bidderA,
+			expectedDecision: true,
+		},
+		{
+			description: "Later Rule Matches",
+			givenActivity: config.Activity{Rules: []config.ActivityRule{
+				{Condition: config.ActivityCondition{ComponentName: []string{"bidderA"}}, Allow: true},
+				{Condition: config.ActivityCondition{ComponentType: []string{"bidder"}}, Allow: false},
+			}},
+			givenComponent:   bidderB,
+			expectedDecision: false,
+		},
+		{
+			description: "GPP SID Match",
+			givenActivity: config.Activity{Rules: []config.ActivityRule{
+				{Condition: config.ActivityCondition{GPPSID: []int8{7, 8}}, Allow: false},
+			}},
+			givenComponent:   bidderA,
+			givenRequest:     california,
+			expectedDecision: false,
+		},
+		{
+			description: "GPP SID No Match",
+			givenActivity: config.Activity{Rules: []config.ActivityRule{
+				{Condition: config.ActivityCondition{GPPSID: []int8{7, 8}}, Allow: false},
+			}},
+			givenComponent:   bidderA,
+			givenRequest:     virginia,
+			expectedDecision: true,
+		},
+		{
+			description: "Geo Country Match",
+			givenActivity: config.Activity{Rules: []config.ActivityRule{
+				{Condition: config.ActivityCondition{Geo: []string{"USA"}}, Allow: false},
+			}},
+			givenComponent:   bidderA,
+			givenRequest:     virginia,
+			expectedDecision: false,
+		},
+		{
+			description: "Geo Region Match",
+			givenActivity: config.Activity{Rules: []config.ActivityRule{
+				{Condition: config.ActivityCondition{Geo: []string{"USA.CA"}}, Allow: false},
+			}},
+			givenComponent:   bidderA,
+			givenRequest:     california,
+			expectedDecision: false,
+		},
+		{
+			description: "Geo Region No Match",
+			givenActivity: config.Activity{Rules: []config.ActivityRule{
+				{Condition: config.ActivityCondition{Geo: []string{"USA.CA"}}, Allow: false},
+			}},
+			givenComponent:   bidderA,
+			givenRequest:     virginia,
+			expectedDecision: true,
+		},
+		{
+			description: "Geo Unknown",
+			givenActivity: config.Activity{Rules: []config.ActivityRule{
+				{Condition: config.ActivityCondition{Geo: []string{"USA"}}, Allow: false},
+			}},
+			givenComponent:   bidderA,
+			givenRequest:     ActivityRequest{},
+			expectedDecision: true,
+		},
+	}
+
+	for _, test := range testCases {
+		control := NewActivityControl(config.AccountActivities{FetchBids: test.givenActivity})
+		assert.Equal(t, test.expectedDecision, control.Allow(ActivityFetchBids, test.givenComponent, test.givenRequest), test.description)
+		assert.True(t, control.Allow(ActivitySyncUser, test.givenComponent, test.givenRequest), test.description+":other_activity")
+	}
+}
+
+func TestNewActivityControlNotConfigured(t *testing.T) {
+	control := NewActivityControl(config.AccountActivities{})
+
+	assert.Equal(t, ActivityControl{}, control)
+	assert.True(t, control.Allow(ActivityFetchBids, Component{Type: config.ComponentTypeBidder, Name: "bidderA"}, ActivityRequest{}))
+}
@@ -9,11 +9,20 @@ type Enforcement struct {
 	GDPRGeo bool
 	GDPRID  bool
 	LMT     bool
+
+	// GPP US National and US state sections
+	GPPID    bool
+	GPPGeo   bool
+	GPPChild bool
+
+	// Account activity controls
+	UFPD       bool
+	PreciseGeo bool
 }
 
 // Any returns true if at least one privacy policy requires enforcement.
 func (e Enforcement) Any() bool {
-	return e.CCPA || e.COPPA || e.GDPRGeo || e.GDPRID || e.LMT
+	return e.CCPA || e.COPPA || e.GDPRGeo || e.GDPRID || e.LMT || e.GPPID || e.GPPGeo || e.GPPChild || e.UFPD || e.PreciseGeo
 }
 
 // Apply cleans personally identifiable information from an OpenRTB bid request.
@@ -25,11 +34,13 @@ func (e Enforcement) apply(bidRequest *openrtb2.BidRequest, scrubber Scrubber) {
 	if bidRequest != nil && e.Any() {
 		bidRequest.Device = scrubber.ScrubDevice(bidRequest.Device, e.getDeviceIDScrubStrategy(), e.getIPv4ScrubStrategy(), e.getIPv6ScrubStrategy(), e.getGeoScrubStrategy())
 		bidRequest.User = scrubber.ScrubUser(bidRequest.User, e.getUserScrubStrategy(), e.getGeoScrubStrategy())
+		bidRequest.Site = scrubber.ScrubSite(bidRequest.Site, e.getContentDataScrubStrategy())
+		bidRequest.App = scrubber.ScrubApp(bidRequest.App, e.getContentDataScrubStrategy())
 	}
 }
 
 func (e Enforcement) getDeviceIDScrubStrategy() ScrubStrategyDeviceID {
-	if e.COPPA || e.GDPRID || e.CCPA || e.LMT {
+	if e.COPPA || e.GDPRID || e.CCPA || e.LMT || e.GPPID || e.GPPChild || e.UFPD {
 		return ScrubStrategyDeviceIDAll
 	}
 
@@ -37,7 +48,7 @@ func (e Enforcement) getDeviceIDScrubStrategy() ScrubStrategyDeviceID {
 }
 
 func (e Enforcement) getIPv4ScrubStrategy() ScrubStrategyIPV4 {
-	if e.COPPA || e.GDPRGeo || e.CCPA || e.LMT {
+	if e.COPPA || e.GDPRGeo || e.CCPA || e.LMT || e.GPPID || e.GPPGeo || e.GPPChild || e.PreciseGeo {
 		return ScrubStrategyIPV4Lowest8
 	}
 
@@ -45,11 +56,11 @@ func (e Enforcement) getIPv4ScrubStrategy() ScrubStrategyIPV4 {
 }
 
 func (e Enforcement) getIPv6ScrubStrategy() ScrubStrategyIPV6 {
-	if e.COPPA {
+	if e.COPPA || e.GPPChild {
 		return ScrubStrategyIPV6Lowest32
 	}
 
-	if e.GDPRGeo || e.CCPA || e.LMT {
+	if e.GDPRGeo || e.CCPA || e.LMT || e.GPPID || e.GPPGeo || e.PreciseGeo {
 		return ScrubStrategyIPV6Lowest16
 	}
 
@@ -57,11 +68,11 @@ func (e Enforcement) getIPv6ScrubStrategy() ScrubStrategyIPV6 {
 }
 
 func (e Enforcement) getGeoScrubStrategy() ScrubStrategyGeo {
-	if e.COPPA {
+	if e.COPPA || e.GPPChild {
 		return ScrubStrategyGeoFull
 	}
 
-	if e.GDPRGeo || e.CCPA || e.LMT {
+	if e.GDPRGeo || e.CCPA || e.LMT || e.GPPID || e.GPPGeo || e.PreciseGeo {
 		return ScrubStrategyGeoReducedPrecision
 	}
 
@@ -69,11 +80,15 @@ func (e Enforcement) getGeoScrubStrategy() ScrubStrategyGeo {
 }
 
 func (e Enforcement) getUserScrubStrategy() ScrubStrategyUser {
-	if e.COPPA {
+	if e.UFPD {
+		return ScrubStrategyUserIDDemographicAndFPD
+	}
+
+	if e.COPPA || e.GPPChild {
 		return ScrubStrategyUserIDAndDemographic
 	}
 
-	if e.CCPA || e.LMT {
+	if e.CCPA || e.LMT || e.GPPID {
 		return ScrubStrategyUserID
 	}
 
@@ -83,3 +98,11 @@ func (e Enforcement) getUserScrubStrategy() ScrubStrategyUser {
 
 	return ScrubStrategyUserNone
 }
+
+func (e Enforcement) getContentDataScrubStrategy() ScrubStrategyContentData {
+	if e.UFPD {
+		return ScrubStrategyContentDataAll
+	}
+
+	return ScrubStrategyContentDataNone
+}
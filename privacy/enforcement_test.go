@@ -47,6 +47,20 @@ func TestAny(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			description: "GPP Only",
+			enforcement: Enforcement{
+				GPPGeo: true,
+			},
+			expected: true,
+		},
+		{
+			description: "Activity Only",
+			enforcement: Enforcement{
+				PreciseGeo: true,
+			},
+			expected: true,
+		},
 	}
 
 	for _, test := range testCases {
@@ -65,6 +79,7 @@ func TestApply(t *testing.T) {
 		expectedDeviceGeo  ScrubStrategyGeo
 		expectedUser       ScrubStrategyUser
 		expectedUserGeo    ScrubStrategyGeo
+		expectedContent    ScrubStrategyContentData
 	}{
 		{
 			description: "All Enforced",
@@ -194,25 +209,108 @@ func TestApply(t *testing.T) {
 			expectedUser:       ScrubStrategyUserIDAndDemographic,
 			expectedUserGeo:    ScrubStrategyGeoFull,
 		},
+		{
+			description: "GPP ID Only",
+			enforcement: Enforcement{
+				GPPID: true,
+			},
+			expectedDeviceID:   ScrubStrategyDeviceIDAll,
+			expectedDeviceIPv4: ScrubStrategyIPV4Lowest8,
+			expectedDeviceIPv6: ScrubStrategyIPV6Lowest16,
+			expectedDeviceGeo:  ScrubStrategyGeoReducedPrecision,
+			expectedUser:       ScrubStrategyUserID,
+			expectedUserGeo:    ScrubStrategyGeoReducedPrecision,
+		},
+		{
+			description: "GPP Geo Only",
+			enforcement: Enforcement{
+				GPPGeo: true,
+			},
+			expectedDeviceID:   ScrubStrategyDeviceIDNone,
+			expectedDeviceIPv4: ScrubStrategyIPV4Lowest8,
+			expectedDeviceIPv6: ScrubStrategyIPV6Lowest16,
+			expectedDeviceGeo:  ScrubStrategyGeoReducedPrecision,
+			expectedUser:       ScrubStrategyUserNone,
+			expectedUserGeo:    ScrubStrategyGeoReducedPrecision,
+		},
+		{
+			description: "GPP Child Only",
+			enforcement: Enforcement{
+				GPPChild: true,
+			},
+			expectedDeviceID:   ScrubStrategyDeviceIDAll,
+			expectedDeviceIPv4: ScrubStrategyIPV4Lowest8,
+			expectedDeviceIPv6: ScrubStrategyIPV6Lowest32,
+			expectedDeviceGeo:  ScrubStrategyGeoFull,
+			expectedUser:       ScrubStrategyUserIDAndDemographic,
+			expectedUserGeo:    ScrubStrategyGeoFull,
+		},
+		{
+			description: "Activity UFPD Only",
+			enforcement: Enforcement{
+				UFPD: true,
+			},
+			expectedDeviceID:   ScrubStrategyDeviceIDAll,
+			expectedDeviceIPv4: ScrubStrategyIPV4None,
+			expectedDeviceIPv6: ScrubStrategyIPV6None,
+			expectedDeviceGeo:  ScrubStrategyGeoNone,
+			expectedUser:       ScrubStrategyUserIDDemographicAndFPD,
+			expectedUserGeo:    ScrubStrategyGeoNone,
+			expectedContent:    ScrubStrategyContentDataAll,
+		},
+		{
+			description: "Activity UFPD And COPPA",
+			enforcement: Enforcement{
+				UFPD:  true,
+				COPPA: true,
+			},
+			expectedDeviceID:   ScrubStrategyDeviceIDAll,
+			expectedDeviceIPv4: ScrubStrategyIPV4Lowest8,
+			expectedDeviceIPv6: ScrubStrategyIPV6Lowest32,
+			expectedDeviceGeo:  ScrubStrategyGeoFull,
+			expectedUser:       ScrubStrategyUserIDDemographicAndFPD,
+			expectedUserGeo:    ScrubStrategyGeoFull,
+			expectedContent:    ScrubStrategyContentDataAll,
+		},
+		{
+			description: "Activity Precise Geo Only",
+			enforcement: Enforcement{
+				PreciseGeo: true,
+			},
+			expectedDeviceID:   ScrubStrategyDeviceIDNone,
+			expectedDeviceIPv4: ScrubStrategyIPV4Lowest8,
+			expectedDeviceIPv6: ScrubStrategyIPV6Lowest16,
+			expectedDeviceGeo:  ScrubStrategyGeoReducedPrecision,
+			expectedUser:       ScrubStrategyUserNone,
+			expectedUserGeo:    ScrubStrategyGeoReducedPrecision,
+		},
 	}
 
 	for _, test := range testCases {
 		req := &openrtb2.BidRequest{
 			Device: &openrtb2.Device{},
 			User:   &openrtb2.User{},
+			Site:   &openrtb2.Site{},
+			App:    &openrtb2.App{},
 		}
 		replacedDevice := &openrtb2.Device{}
 		replacedUser := &openrtb2.User{}
+		replacedSite := &openrtb2.Site{}
+		replacedApp := &openrtb2.App{}
 
 		m := &mockScrubber{}
 		m.On("ScrubDevice", req.Device, test.expectedDeviceID, test.expectedDeviceIPv4, test.expectedDeviceIPv6, test.expectedDeviceGeo).Return(replacedDevice).Once()
 		m.On("ScrubUser", req.User, test.expectedUser, test.expectedUserGeo).Return(replacedUser).Once()
+		m.On("ScrubSite", req.Site, test.expectedContent).Return(replacedSite).Once()
+		m.On("ScrubApp", req.App, test.expectedContent).Return(replacedApp).Once()
 
 		test.enforcement.apply(req, m)
 
 		m.AssertExpectations(t)
 		assert.Same(t, replacedDevice, req.Device, "Device")
 		assert.Same(t, replacedUser, req.User, "User")
+		assert.Same(t, replacedSite, req.Site, "Site")
+		assert.Same(t, replacedApp, req.App, "App")
 	}
 }
 
@@ -257,3 +355,13 @@ func (m *mockScrubber) ScrubUser(user *openrtb2.User, strategy ScrubStrategyUser
 	args := m.Called(user, strategy, geo)
 	return args.Get(0).(*openrtb2.User)
 }
+
+func (m *mockScrubber) ScrubSite(site *openrtb2.Site, data ScrubStrategyContentData) *openrtb2.Site {
+	args := m.Called(site, data)
+	return args.Get(0).(*openrtb2.Site)
+}
+
+func (m *mockScrubber) ScrubApp(app *openrtb2.App, data ScrubStrategyContentData) *openrtb2.App {
+	args := m.Called(app, data)
+	return args.Get(0).(*openrtb2.App)
+}
@@ -0,0 +1,71 @@
+package gpp
+
+// Restriction identifies a processing activity the US National and US state sections may restrict.
+type Restriction int
+
+const (
+	// RestrictionSaleOrSharing applies when the consumer opted out of the sale or sharing of personal data,
+	// or of targeted advertising, either explicitly or through the global privacy control.
+	RestrictionSaleOrSharing Restriction = iota
+
+	// RestrictionSensitiveData applies when the consumer limited the processing of sensitive data.
+	RestrictionSensitiveData
+
+	// RestrictionKnownChild applies when the consumer is a known child whose data may not be processed.
+	RestrictionKnownChild
+)
+
+// ParsedPolicy represents the parsed US National and US state sections of a GPP string. Use this struct
+// to make enforcement decisions.
+type ParsedPolicy struct {
+	Sections []USSection
+}
+
+// CanEnforce returns true when at least one applicable US section was provided.
+func (p ParsedPolicy) CanEnforce() bool {
+	return len(p.Sections) > 0
+}
+
+// Restricts returns true when any of the applicable US sections restricts the activity.
+func (p ParsedPolicy) Restricts(restriction Restriction) bool {
+	for _, section := range p.Sections {
+		if section.restricts(restriction) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s USSection) restricts(restriction Restriction) bool {
+	switch restriction {
+	case RestrictionSaleOrSharing:
+		return s.SaleOptOut || s.SharingOptOut || s.TargetedAdvertisingOptOut || s.ServiceProviderMode || s.GPC
+	case RestrictionSensitiveData:
+		return s.SensitiveDataLimited
+	case RestrictionKnownChild:
+		return s.KnownChild
+	}
+	return false
+}
+
+// Enforcer returns the policy enforcer of a single restriction.
+func (p ParsedPolicy) Enforcer(restriction Restriction) RestrictionEnforcer {
+	return RestrictionEnforcer{policy: p, restriction: restriction}
+}
+
+// RestrictionEnforcer implements the privacy.PolicyEnforcer interface for one of the restrictions
+// signaled by the US sections. The US sections apply to all bidders alike.
+type RestrictionEnforcer struct {
+	policy      ParsedPolicy
+	restriction Restriction
+}
+
+// CanEnforce returns true when at least one applicable US section was provided.
+func (e RestrictionEnforcer) CanEnforce() bool {
+	return e.policy.CanEnforce()
+}
+
+// ShouldEnforce returns true when the US sections restrict the activity.
+func (e RestrictionEnforcer) ShouldEnforce(bidder string) bool {
+	return e.policy.Restricts(e.restriction)
+}
@@ -0,0 +1,87 @@
+package gpp
+
+import (
+	"testing"
+
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanEnforce(t *testing.T) {
+	assert.False(t, ParsedPolicy{}.CanEnforce())
+	assert.True(t, ParsedPolicy{Sections: []USSection{{SectionID: gppConstants.SectionUSPNAT}}}.CanEnforce())
+}
+
+func TestRestricts(t *testing.T) {
+	testCases := []struct {
+		description           string
+		givenSection          USSection
+		expectedSaleOrSharing bool
+		expectedSensitiveData bool
+		expectedKnownChild    bool
+	}{
+		{
+			description:  "No Restriction",
+			givenSection: USSection{},
+		},
+		{
+			description:           "Sale Opt Out",
+			givenSection:          USSection{SaleOptOut: true},
+			expectedSaleOrSharing: true,
+		},
+		{
+			description:           "Sharing Opt Out",
+			givenSection:          USSection{SharingOptOut: true},
+			expectedSaleOrSharing: true,
+		},
+		{
+			description:           "Targeted Advertising Opt Out",
+			givenSection:          USSection{TargetedAdvertisingOptOut: true},
+			expectedSaleOrSharing: true,
+		},
+		{
+			description:           "Service Provider Mode",
+			givenSection:          USSection{ServiceProviderMode: true},
+			expectedSaleOrSharing: true,
+		},
+		{
+			description:           "GPC",
+			givenSection:          USSection{GPC: true},
+			expectedSaleOrSharing: true,
+		},
+		{
+			description:           "Sensitive Data Limited",
+			givenSection:          USSection{SensitiveDataLimited: true},
+			expectedSensitiveData: true,
+		},
+		{
+			description:        "Known Child",
+			givenSection:       USSection{KnownChild: true},
+			expectedKnownChild: true,
+		},
+	}
+
+	for _, test := range testCases {
+		policy := ParsedPolicy{Sections: []USSection{{SectionID: gppConstants.SectionUSPNAT}, test.givenSection}}
+
+		assert.Equal(t, test.expectedSaleOrSharing, policy.Restricts(RestrictionSaleOrSharing), test.description+":sale_or_sharing")
+		assert.Equal(t, test.expectedSensitiveData, policy.Restricts(RestrictionSensitiveData), test.description+":sensitive_data")
+		assert.Equal(t, test.expectedKnownChild, policy.Restricts(RestrictionKnownChild), test.description+":known_child")
+	}
+}
+
+func TestEnforcer(t *testing.T) {
+	policy := ParsedPolicy{Sections: []USSection{{SectionID: gppConstants.SectionUSPCA, SaleOptOut: true}}}
+
+	saleOrSharing := policy.Enforcer(RestrictionSaleOrSharing)
+	assert.True(t, saleOrSharing.CanEnforce())
+	assert.True(t, saleOrSharing.ShouldEnforce("anyBidder"))
+
+	knownChild := policy.Enforcer(RestrictionKnownChild)
+	assert.True(t, knownChild.CanEnforce())
+	assert.False(t, knownChild.ShouldEnforce("anyBidder"))
+
+	noPolicy := ParsedPolicy{}.Enforcer(RestrictionSaleOrSharing)
+	assert.False(t, noPolicy.CanEnforce())
+	assert.False(t, noPolicy.ShouldEnforce("anyBidder"))
+}
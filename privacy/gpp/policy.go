@@ -0,0 +1,99 @@
+package gpp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/openrtb/v17/openrtb2"
+)
+
+// Policy represents the GPP regulatory information from an OpenRTB bid request or a user sync request.
+type Policy struct {
+	Consent string
+	SID     []int8
+}
+
+// ReadFromRequest extracts the GPP regulatory information from an OpenRTB bid request.
+func ReadFromRequest(req *openrtb2.BidRequest) Policy {
+	if req == nil || req.Regs == nil {
+		return Policy{}
+	}
+	return Policy{Consent: req.Regs.GPP, SID: req.Regs.GPPSID}
+}
+
+// ParseSID parses a comma separated list of GPP section ids, as sent on user sync requests.
+func ParseSID(sid string) ([]int8, error) {
+	if sid == "" {
+		return nil, nil
+	}
+
+	values := strings.Split(sid, ",")
+	ids := make([]int8, 0, len(values))
+	for _, value := range values {
+		id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("gpp_sid %s is invalid: %s is not a section id", sid, value)
+		}
+		ids = append(ids, int8(id))
+	}
+	return ids, nil
+}
+
+// SIDString formats the section ids as the comma separated list expected by the user sync macros.
+func (p Policy) SIDString() string {
+	values := make([]string, len(p.SID))
+	for i, id := range p.SID {
+		values[i] = strconv.Itoa(int(id))
+	}
+	return strings.Join(values, ",")
+}
+
+// Parse returns a parsed and validated ParsedPolicy intended for use in enforcement decisions.
+func (p Policy) Parse() (ParsedPolicy, error) {
+	if p.Consent == "" {
+		return ParsedPolicy{}, nil
+	}
+
+	gpp, err := gpplib.Parse(p.Consent)
+	if err != nil {
+		return ParsedPolicy{}, err
+	}
+	return ParseContainer(gpp, p.SID)
+}
+
+// ParseContainer builds the ParsedPolicy from the US National and US state sections of an already parsed
+// GPP string. Only the sections listed as applicable by sid are considered. The sections which cannot be
+// decoded are left out of the policy and reported in the returned error.
+func ParseContainer(gpp gpplib.GppContainer, sid []int8) (ParsedPolicy, error) {
+	var parsedPolicy ParsedPolicy
+	var errs []string
+	for i, id := range gpp.SectionTypes {
+		if !IsUSSection(id) || !sectionApplies(id, sid) {
+			continue
+		}
+
+		section, err := ParseUSSection(id, gpp.Sections[i].GetValue())
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		parsedPolicy.Sections = append(parsedPolicy.Sections, section)
+	}
+
+	if len(errs) > 0 {
+		return parsedPolicy, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return parsedPolicy, nil
+}
+
+func sectionApplies(id gppConstants.SectionID, sid []int8) bool {
+	for _, s := range sid {
+		if s == int8(id) {
+			return true
+		}
+	}
+	return false
+}
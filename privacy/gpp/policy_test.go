@@ -0,0 +1,160 @@
+package gpp
+
+import (
+	"testing"
+
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFromRequest(t *testing.T) {
+	testCases := []struct {
+		description    string
+		request        *openrtb2.BidRequest
+		expectedPolicy Policy
+	}{
+		{
+			description:    "Nil Request",
+			request:        nil,
+			expectedPolicy: Policy{},
+		},
+		{
+			description:    "Nil Regs",
+			request:        &openrtb2.BidRequest{},
+			expectedPolicy: Policy{},
+		},
+		{
+			description:    "Success",
+			request:        &openrtb2.BidRequest{Regs: &openrtb2.Regs{GPP: "DBABLA~BVVqqqqqCaA", GPPSID: []int8{7}}},
+			expectedPolicy: Policy{Consent: "DBABLA~BVVqqqqqCaA", SID: []int8{7}},
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expectedPolicy, ReadFromRequest(test.request), test.description)
+	}
+}
+
+func TestParseSID(t *testing.T) {
+	testCases := []struct {
+		description   string
+		givenSID      string
+		expectedSID   []int8
+		expectedError string
+	}{
+		{
+			description: "Empty",
+			givenSID:    "",
+			expectedSID: nil,
+		},
+		{
+			description: "One",
+			givenSID:    "7",
+			expectedSID: []int8{7},
+		},
+		{
+			description: "Many",
+			givenSID:    "2, 7,8",
+			expectedSID: []int8{2, 7, 8},
+		},
+		{
+			description:   "Not A Number",
+			givenSID:      "7,a",
+			expectedError: "gpp_sid 7,a is invalid: a is not a section id",
+		},
+		{
+			description:   "Out Of Range",
+			givenSID:      "300",
+			expectedError: "gpp_sid 300 is invalid: 300 is not a section id",
+		},
+	}
+
+	for _, test := range testCases {
+		sid, err := ParseSID(test.givenSID)
+
+		if test.expectedError == "" {
+			assert.NoError(t, err, test.description)
+			assert.Equal(t, test.expectedSID, sid, test.description)
+		} else {
+			assert.EqualError(t, err, test.expectedError, test.description)
+		}
+	}
+}
+
+func TestSIDString(t *testing.T) {
+	assert.Equal(t, "", Policy{}.SIDString())
+	assert.Equal(t, "7", Policy{SID: []int8{7}}.SIDString())
+	assert.Equal(t, "2,7,8", Policy{SID: []int8{2, 7, 8}}.SIDString())
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		description    string
+		givenPolicy    Policy
+		expectedPolicy ParsedPolicy
+		expectedError  string
+	}{
+		{
+			description:    "Empty",
+			givenPolicy:    Policy{},
+			expectedPolicy: ParsedPolicy{},
+		},
+		{
+			description:   "Invalid GPP String",
+			givenPolicy:   Policy{Consent: "malformed", SID: []int8{7}},
+			expectedError: "error parsing GPP header",
+		},
+		{
+			description:    "Section Not Applicable",
+			givenPolicy:    Policy{Consent: "DBABLA~BVVaqqqqCaA", SID: []int8{8}},
+			expectedPolicy: ParsedPolicy{},
+		},
+		{
+			description: "Section Applicable",
+			givenPolicy: Policy{Consent: "DBABLA~BVVaqqqqCaA", SID: []int8{7}},
+			expectedPolicy: ParsedPolicy{Sections: []USSection{
+				{SectionID: gppConstants.SectionUSPNAT, SaleOptOut: true},
+			}},
+		},
+		{
+			description: "Non US Sections Ignored",
+			givenPolicy: Policy{Consent: "DBACMMA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~BVVaqqqqCaA", SID: []int8{2, 7}},
+			expectedPolicy: ParsedPolicy{Sections: []USSection{
+				{SectionID: gppConstants.SectionUSPNAT, SaleOptOut: true},
+			}},
+		},
+		{
+			description: "All US Sections",
+			givenPolicy: Policy{Consent: "DBAGLbbY~BVVqqqqqCaA~BVqmqoJo~BVqqqlo~BVqqqGg~BVZqqoaA~BVo", SID: []int8{7, 8, 9, 10, 11}},
+			expectedPolicy: ParsedPolicy{Sections: []USSection{
+				{SectionID: gppConstants.SectionUSPNAT},
+				{SectionID: gppConstants.SectionUSPCA, SensitiveDataLimited: true},
+				{SectionID: gppConstants.SectionUSPVA, KnownChild: true},
+				{SectionID: gppConstants.SectionUSPCO},
+				{SectionID: gppConstants.SectionUSPUT, TargetedAdvertisingOptOut: true},
+			}},
+		},
+		{
+			description: "Invalid US Section Left Out",
+			givenPolicy: Policy{Consent: "DBACLYA~BVVaqqqqCaA~BVo", SID: []int8{7, 8}},
+			expectedPolicy: ParsedPolicy{Sections: []USSection{
+				{SectionID: gppConstants.SectionUSPNAT, SaleOptOut: true},
+			}},
+			expectedError: "error parsing GPP usca section: core segment is too short",
+		},
+	}
+
+	for _, test := range testCases {
+		parsedPolicy, err := test.givenPolicy.Parse()
+
+		if test.expectedError == "" {
+			assert.NoError(t, err, test.description)
+		} else {
+			assert.ErrorContains(t, err, test.expectedError, test.description)
+		}
+		if test.expectedError == "" || len(test.expectedPolicy.Sections) > 0 {
+			assert.Equal(t, test.expectedPolicy, parsedPolicy, test.description)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package gpp
+
+import (
+	"fmt"
+	"strings"
+
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/util"
+)
+
+// optedOut is the value of a two bit field of the US National and US state sections signaling the consumer
+// opted out. For consent fields the same value signals the consumer did not consent.
+const optedOut byte = 1
+
+const gpcSubsectionType byte = 1
+
+type usField int
+
+const (
+	fieldNotice usField = iota
+	fieldSaleOptOut
+	fieldSharingOptOut
+	fieldTargetedAdvertisingOptOut
+	fieldSensitiveDataProcessing
+	fieldKnownChildSensitiveDataConsents
+	fieldPersonalDataConsents
+	fieldMspaCoveredTransaction
+	fieldMspaOptOutOptionMode
+	fieldMspaServiceProviderMode
+)
+
+// usFieldSpec describes a field of the core segment of a US section, count being the number of
+// two bit values the field holds.
+type usFieldSpec struct {
+	field usField
+	count int
+}
+
+type usSectionSpec struct {
+	name        string
+	fields      []usFieldSpec
+	supportsGPC bool
+}
+
+var mspaFields = []usFieldSpec{
+	{fieldMspaCoveredTransaction, 1},
+	{fieldMspaOptOutOptionMode, 1},
+	{fieldMspaServiceProviderMode, 1},
+}
+
+// usSectionSpecs holds the layout of the core segment of each supported section, as defined by the
+// IAB GPP US National and US state specifications. Every field follows the 6 bit version.
+var usSectionSpecs = map[gppConstants.SectionID]usSectionSpec{
+	gppConstants.SectionUSPNAT: {
+		name: "usnat",
+		fields: append([]usFieldSpec{
+			{fieldNotice, 6},
+			{fieldSaleOptOut, 1},
+			{fieldSharingOptOut, 1},
+			{fieldTargetedAdvertisingOptOut, 1},
+			{fieldSensitiveDataProcessing, 12},
+			{fieldKnownChildSensitiveDataConsents, 2},
+			{fieldPersonalDataConsents, 1},
+		}, mspaFields...),
+		supportsGPC: true,
+	},
+	gppConstants.SectionUSPCA: {
+		name: "usca",
+		fields: append([]usFieldSpec{
+			{fieldNotice, 3},
+			{fieldSaleOptOut, 1},
+			{fieldSharingOptOut, 1},
+			{fieldSensitiveDataProcessing, 9},
+			{fieldKnownChildSensitiveDataConsents, 2},
+			{fieldPersonalDataConsents, 1},
+		}, mspaFields...),
+		supportsGPC: true,
+	},
+	gppConstants.SectionUSPVA: {
+		name: "usva",
+		fields: append([]usFieldSpec{
+			{fieldNotice, 3},
+			{fieldSaleOptOut, 1},
+			{fieldTargetedAdvertisingOptOut, 1},
+			{fieldSensitiveDataProcessing, 8},
+			{fieldKnownChildSensitiveDataConsents, 1},
+		}, mspaFields...),
+	},
+	gppConstants.SectionUSPCO: {
+		name: "usco",
+		fields: append([]usFieldSpec{
+			{fieldNotice, 3},
+			{fieldSaleOptOut, 1},
+			{fieldTargetedAdvertisingOptOut, 1},
+			{fieldSensitiveDataProcessing, 7},
+			{fieldKnownChildSensitiveDataConsents, 1},
+		}, mspaFields...),
+		supportsGPC: true,
+	},
+	gppConstants.SectionUSPUT: {
+		name: "usut",
+		fields: append([]usFieldSpec{
+			{fieldNotice, 4},
+			{fieldSaleOptOut, 1},
+			{fieldTargetedAdvertisingOptOut, 1},
+			{fieldSensitiveDataProcessing, 8},
+			{fieldKnownChildSensitiveDataConsents, 1},
+		}, mspaFields...),
+	},
+	gppConstants.SectionUSPCT: {
+		name: "usct",
+		fields: append([]usFieldSpec{
+			{fieldNotice, 3},
+			{fieldSaleOptOut, 1},
+			{fieldTargetedAdvertisingOptOut, 1},
+			{fieldSensitiveDataProcessing, 8},
+			{fieldKnownChildSensitiveDataConsents, 3},
+		}, mspaFields...),
+		supportsGPC: true,
+	},
+}
+
+// IsUSSection returns true if the section is the US National section or one of the supported US state sections.
+func IsUSSection(id gppConstants.SectionID) bool {
+	_, ok := usSectionSpecs[id]
+	return ok
+}
+
+// USSection represents the consumer choices of a US National or US state GPP section which are
+// relevant to enforcement.
+type USSection struct {
+	SectionID                 gppConstants.SectionID
+	SaleOptOut                bool
+	SharingOptOut             bool
+	TargetedAdvertisingOptOut bool
+	// SensitiveDataLimited is set when the consumer opted out of, or did not consent to, the processing
+	// of at least one category of sensitive data.
+	SensitiveDataLimited bool
+	// KnownChild is set when the consumer is a known child whose sensitive data may not be processed
+	// for lack of consent.
+	KnownChild bool
+	// ServiceProviderMode is set when the transaction is covered by the MSPA in service provider mode,
+	// in which case personal data may not be sold or shared.
+	ServiceProviderMode bool
+	GPC                 bool
+}
+
+// ParseUSSection decodes the value of a US National or US state GPP section.
+func ParseUSSection(id gppConstants.SectionID, value string) (USSection, error) {
+	spec, ok := usSectionSpecs[id]
+	if !ok {
+		return USSection{}, fmt.Errorf("GPP section %d is not a supported US section", id)
+	}
+
+	segments := strings.Split(value, ".")
+	section, err := parseUSCoreSegment(id, spec, segments[0])
+	if err != nil {
+		return USSection{}, fmt.Errorf("error parsing GPP %s section: %v", spec.name, err)
+	}
+
+	if spec.supportsGPC {
+		for _, segment := range segments[1:] {
+			gpc, err := parseGPCSegment(segment)
+			if err != nil {
+				return USSection{}, fmt.Errorf("error parsing GPP %s section: %v", spec.name, err)
+			}
+			section.GPC = section.GPC || gpc
+		}
+	}
+
+	return section, nil
+}
+
+func parseUSCoreSegment(id gppConstants.SectionID, spec usSectionSpec, segment string) (USSection, error) {
+	bs, err := util.NewBitStreamFromBase64(segment)
+	if err != nil {
+		return USSection{}, fmt.Errorf("base64 decoding: %v", err)
+	}
+
+	if _, err := bs.ReadByte6(); err != nil {
+		return USSection{}, fmt.Errorf("unable to read version: %v", err)
+	}
+
+	section := USSection{SectionID: id}
+	for _, fieldSpec := range spec.fields {
+		for i := 0; i < fieldSpec.count; i++ {
+			v, err := readByte2(bs)
+			if err != nil {
+				return USSection{}, fmt.Errorf("core segment is too short: %v", err)
+			}
+			section.set(fieldSpec.field, v)
+		}
+	}
+	return section, nil
+}
+
+func (s *USSection) set(field usField, v byte) {
+	switch field {
+	case fieldSaleOptOut:
+		s.SaleOptOut = s.SaleOptOut || v == optedOut
+	case fieldSharingOptOut:
+		s.SharingOptOut = s.SharingOptOut || v == optedOut
+	case fieldTargetedAdvertisingOptOut:
+		s.TargetedAdvertisingOptOut = s.TargetedAdvertisingOptOut || v == optedOut
+	case fieldSensitiveDataProcessing:
+		s.SensitiveDataLimited = s.SensitiveDataLimited || v == optedOut
+	case fieldKnownChildSensitiveDataConsents:
+		s.KnownChild = s.KnownChild || v == optedOut
+	case fieldMspaServiceProviderMode:
+		s.ServiceProviderMode = s.ServiceProviderMode || v == optedOut
+	}
+}
+
+func parseGPCSegment(segment string) (bool, error) {
+	bs, err := util.NewBitStreamFromBase64(segment)
+	if err != nil {
+		return false, fmt.Errorf("base64 decoding of subsection: %v", err)
+	}
+
+	subsectionType, err := readByte2(bs)
+	if err != nil {
+		return false, fmt.Errorf("unable to read subsection type: %v", err)
+	}
+	if subsectionType != gpcSubsectionType {
+		return false, nil
+	}
+
+	gpc, err := bs.ReadByte1()
+	if err != nil {
+		return false, fmt.Errorf("unable to read gpc: %v", err)
+	}
+	return gpc == 1, nil
+}
+
+func readByte2(bs *util.BitStream) (byte, error) {
+	high, err := bs.ReadByte1()
+	if err != nil {
+		return 0, err
+	}
+	low, err := bs.ReadByte1()
+	if err != nil {
+		return 0, err
+	}
+	return high<<1 | low, nil
+}
@@ -0,0 +1,121 @@
+package gpp
+
+import (
+	"testing"
+
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUSSection(t *testing.T) {
+	testCases := []struct {
+		description     string
+		givenSectionID  gppConstants.SectionID
+		givenValue      string
+		expectedSection USSection
+		expectedError   string
+	}{
+		{
+			description:     "US National - No Opt Out",
+			givenSectionID:  gppConstants.SectionUSPNAT,
+			givenValue:      "BVVqqqqqCaA",
+			expectedSection: USSection{SectionID: gppConstants.SectionUSPNAT},
+		},
+		{
+			description:     "US National - Sale Opt Out",
+			givenSectionID:  gppConstants.SectionUSPNAT,
+			givenValue:      "BVVaqqqqCaA",
+			expectedSection: USSection{SectionID: gppConstants.SectionUSPNAT, SaleOptOut: true},
+		},
+		{
+			description:     "US National - Service Provider Mode",
+			givenSectionID:  gppConstants.SectionUSPNAT,
+			givenValue:      "BVVqqqqqCZA",
+			expectedSection: USSection{SectionID: gppConstants.SectionUSPNAT, ServiceProviderMode: true},
+		},
+		{
+			description:     "US National - GPC",
+			givenSectionID:  gppConstants.SectionUSPNAT,
+			givenValue:      "BVVqqqqqCaA.YA",
+			expectedSection: USSection{SectionID: gppConstants.SectionUSPNAT, GPC: true},
+		},
+		{
+			description:     "US National - GPC Not Set",
+			givenSectionID:  gppConstants.SectionUSPNAT,
+			givenValue:      "BVVqqqqqCaA.QA",
+			expectedSection: USSection{SectionID: gppConstants.SectionUSPNAT},
+		},
+		{
+			description:     "US California - Sensitive Data Opt Out",
+			givenSectionID:  gppConstants.SectionUSPCA,
+			givenValue:      "BVqmqoJo",
+			expectedSection: USSection{SectionID: gppConstants.SectionUSPCA, SensitiveDataLimited: true},
+		},
+		{
+			description:     "US Virginia - Known Child",
+			givenSectionID:  gppConstants.SectionUSPVA,
+			givenValue:      "BVqqqlo",
+			expectedSection: USSection{SectionID: gppConstants.SectionUSPVA, KnownChild: true},
+		},
+		{
+			description:     "US Virginia - GPC Segment Ignored",
+			givenSectionID:  gppConstants.SectionUSPVA,
+			givenValue:      "BVqqqlo.YA",
+			expectedSection: USSection{SectionID: gppConstants.SectionUSPVA, KnownChild: true},
+		},
+		{
+			description:     "US Colorado - No Opt Out",
+			givenSectionID:  gppConstants.SectionUSPCO,
+			givenValue:      "BVqqqGg",
+			expectedSection: USSection{SectionID: gppConstants.SectionUSPCO},
+		},
+		{
+			description:     "US Utah - Targeted Advertising Opt Out",
+			givenSectionID:  gppConstants.SectionUSPUT,
+			givenValue:      "BVZqqoaA",
+			expectedSection: USSection{SectionID: gppConstants.SectionUSPUT, TargetedAdvertisingOptOut: true},
+		},
+		{
+			description:    "US Connecticut - Too Short",
+			givenSectionID: gppConstants.SectionUSPCT,
+			givenValue:     "BVo",
+			expectedError:  "error parsing GPP usct section: core segment is too short",
+		},
+		{
+			description:    "Invalid Base64",
+			givenSectionID: gppConstants.SectionUSPNAT,
+			givenValue:     "B*V",
+			expectedError:  "error parsing GPP usnat section: base64 decoding",
+		},
+		{
+			description:    "Invalid GPC Segment",
+			givenSectionID: gppConstants.SectionUSPNAT,
+			givenValue:     "BVVqqqqqCaA.*",
+			expectedError:  "error parsing GPP usnat section: base64 decoding of subsection",
+		},
+		{
+			description:    "Not A US Section",
+			givenSectionID: gppConstants.SectionTCFEU2,
+			givenValue:     "BVVqqqqqCaA",
+			expectedError:  "GPP section 2 is not a supported US section",
+		},
+	}
+
+	for _, test := range testCases {
+		section, err := ParseUSSection(test.givenSectionID, test.givenValue)
+
+		if test.expectedError == "" {
+			assert.NoError(t, err, test.description)
+			assert.Equal(t, test.expectedSection, section, test.description)
+		} else {
+			assert.ErrorContains(t, err, test.expectedError, test.description)
+		}
+	}
+}
+
+func TestIsUSSection(t *testing.T) {
+	assert.True(t, IsUSSection(gppConstants.SectionUSPNAT))
+	assert.True(t, IsUSSection(gppConstants.SectionUSPCT))
+	assert.False(t, IsUSSection(gppConstants.SectionTCFEU2))
+	assert.False(t, IsUSSection(gppConstants.SectionUSPV1))
+}
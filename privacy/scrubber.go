@@ -58,6 +58,21 @@ const (
 
 	// ScrubStrategyUserID removes the user's buyer id.
 	ScrubStrategyUserID
+
+	// ScrubStrategyUserIDDemographicAndFPD removes the user's buyer id, exchange id, year of birth, gender, keywords
+	// and first party data (user.data and user.ext.data).
+	ScrubStrategyUserIDDemographicAndFPD
+)
+
+// ScrubStrategyContentData defines the approach to scrub first party data from the site or app content.
+type ScrubStrategyContentData int
+
+const (
+	// ScrubStrategyContentDataNone does not remove the content data.
+	ScrubStrategyContentDataNone ScrubStrategyContentData = iota
+
+	// ScrubStrategyContentDataAll removes the content data segments.
+	ScrubStrategyContentDataAll
 )
 
 // ScrubStrategyDeviceID defines the approach to remove hardware id and device id data.
@@ -75,6 +90,8 @@ const (
 type Scrubber interface {
 	ScrubDevice(device *openrtb2.Device, id ScrubStrategyDeviceID, ipv4 ScrubStrategyIPV4, ipv6 ScrubStrategyIPV6, geo ScrubStrategyGeo) *openrtb2.Device
 	ScrubUser(user *openrtb2.User, strategy ScrubStrategyUser, geo ScrubStrategyGeo) *openrtb2.User
+	ScrubSite(site *openrtb2.Site, data ScrubStrategyContentData) *openrtb2.Site
+	ScrubApp(app *openrtb2.App, data ScrubStrategyContentData) *openrtb2.App
 }
 
 type scrubber struct{}
@@ -135,13 +152,25 @@ func (scrubber) ScrubUser(user *openrtb2.User, strategy ScrubStrategyUser, geo S
 	case ScrubStrategyUserIDAndDemographic:
 		userCopy.BuyerUID = ""
 		userCopy.ID = ""
+		userCopy.EIDs = nil
 		userCopy.Ext = scrubUserExtIDs(userCopy.Ext)
 		userCopy.Yob = 0
 		userCopy.Gender = ""
 	case ScrubStrategyUserID:
 		userCopy.BuyerUID = ""
 		userCopy.ID = ""
+		userCopy.EIDs = nil
 		userCopy.Ext = scrubUserExtIDs(userCopy.Ext)
+	case ScrubStrategyUserIDDemographicAndFPD:
+		userCopy.BuyerUID = ""
+		userCopy.ID = ""
+		userCopy.EIDs = nil
+		userCopy.Ext = scrubExtFields(userCopy.Ext, "eids", "data")
+		userCopy.Yob = 0
+		userCopy.Gender = ""
+		userCopy.Keywords = ""
+		userCopy.KwArray = nil
+		userCopy.Data = nil
 	}
 
 	switch geo {
@@ -154,6 +183,36 @@ func (scrubber) ScrubUser(user *openrtb2.User, strategy ScrubStrategyUser, geo S
 	return &userCopy
 }
 
+func (scrubber) ScrubSite(site *openrtb2.Site, data ScrubStrategyContentData) *openrtb2.Site {
+	if site == nil || data == ScrubStrategyContentDataNone {
+		return site
+	}
+
+	siteCopy := *site
+	siteCopy.Content = scrubContentData(site.Content)
+	return &siteCopy
+}
+
+func (scrubber) ScrubApp(app *openrtb2.App, data ScrubStrategyContentData) *openrtb2.App {
+	if app == nil || data == ScrubStrategyContentDataNone {
+		return app
+	}
+
+	appCopy := *app
+	appCopy.Content = scrubContentData(app.Content)
+	return &appCopy
+}
+
+func scrubContentData(content *openrtb2.Content) *openrtb2.Content {
+	if content == nil || content.Data == nil {
+		return content
+	}
+
+	contentCopy := *content
+	contentCopy.Data = nil
+	return &contentCopy
+}
+
 func scrubIPV4Lowest8(ip string) string {
 	i := strings.LastIndex(ip, ".")
 	if i == -1 {
@@ -214,24 +273,36 @@ func scrubGeoPrecision(geo *openrtb2.Geo) *openrtb2.Geo {
 }
 
 func scrubUserExtIDs(userExt json.RawMessage) json.RawMessage {
-	if len(userExt) == 0 {
-		return userExt
+	return scrubExtFields(userExt, "eids")
+}
+
+// scrubExtFields removes the fields from the ext. The ext is returned as is if it can't be parsed or has none
+// of the fields.
+func scrubExtFields(ext json.RawMessage, fields ...string) json.RawMessage {
+	if len(ext) == 0 {
+		return ext
 	}
 
-	var userExtParsed map[string]json.RawMessage
-	err := json.Unmarshal(userExt, &userExtParsed)
+	var extParsed map[string]json.RawMessage
+	err := json.Unmarshal(ext, &extParsed)
 	if err != nil {
-		return userExt
+		return ext
+	}
+
+	scrubbed := false
+	for _, field := range fields {
+		if _, hasField := extParsed[field]; hasField {
+			delete(extParsed, field)
+			scrubbed = true
+		}
 	}
 
-	_, hasEids := userExtParsed["eids"]
-	if hasEids {
-		delete(userExtParsed, "eids")
-		result, err := json.Marshal(userExtParsed)
+	if scrubbed {
+		result, err := json.Marshal(extParsed)
 		if err == nil {
 			return result
 		}
 	}
 
-	return userExt
+	return ext
 }
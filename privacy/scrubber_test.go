@@ -202,6 +202,7 @@ func TestScrubUser(t *testing.T) {
 		BuyerUID: "anyBuyerUID",
 		Yob:      42,
 		Gender:   "anyGender",
+		EIDs:     []openrtb2.EID{{Source: "anySource"}},
 		Ext:      json.RawMessage(`{}`),
 		Geo: &openrtb2.Geo{
 			Lat:   123.456,
@@ -327,6 +328,7 @@ func TestScrubUser(t *testing.T) {
 				BuyerUID: "anyBuyerUID",
 				Yob:      42,
 				Gender:   "anyGender",
+				EIDs:     []openrtb2.EID{{Source: "anySource"}},
 				Ext:      json.RawMessage(`{}`),
 				Geo:      &openrtb2.Geo{},
 			},
@@ -340,6 +342,7 @@ func TestScrubUser(t *testing.T) {
 				BuyerUID: "anyBuyerUID",
 				Yob:      42,
 				Gender:   "anyGender",
+				EIDs:     []openrtb2.EID{{Source: "anySource"}},
 				Ext:      json.RawMessage(`{}`),
 				Geo: &openrtb2.Geo{
 					Lat:   123.46,
@@ -359,6 +362,7 @@ func TestScrubUser(t *testing.T) {
 				BuyerUID: "anyBuyerUID",
 				Yob:      42,
 				Gender:   "anyGender",
+				EIDs:     []openrtb2.EID{{Source: "anySource"}},
 				Ext:      json.RawMessage(`{}`),
 				Geo: &openrtb2.Geo{
 					Lat:   123.456,
@@ -379,11 +383,70 @@ func TestScrubUser(t *testing.T) {
 	}
 }
 
+func TestScrubUserFPD(t *testing.T) {
+	user := &openrtb2.User{
+		ID:       "anyID",
+		BuyerUID: "anyBuyerUID",
+		Yob:      42,
+		Gender:   "anyGender",
+		Keywords: "anyKeywords",
+		KwArray:  []string{"anyKeyword"},
+		Data:     []openrtb2.Data{{ID: "anyData"}},
+		EIDs:     []openrtb2.EID{{Source: "anySource"}},
+		Ext:      json.RawMessage(`{"eids":[{"source":"anySource"}],"data":{"any":"data"},"other":42}`),
+		Geo:      &openrtb2.Geo{City: "some city"},
+	}
+
+	expected := &openrtb2.User{
+		Ext: json.RawMessage(`{"other":42}`),
+		Geo: &openrtb2.Geo{City: "some city"},
+	}
+
+	result := NewScrubber().ScrubUser(user, ScrubStrategyUserIDDemographicAndFPD, ScrubStrategyGeoNone)
+	assert.Equal(t, expected, result)
+	assert.Len(t, user.Data, 1, "Incoming user.data modified")
+}
+
 func TestScrubUserNil(t *testing.T) {
 	result := NewScrubber().ScrubUser(nil, ScrubStrategyUserNone, ScrubStrategyGeoNone)
 	assert.Nil(t, result)
 }
 
+func TestScrubSite(t *testing.T) {
+	site := &openrtb2.Site{
+		ID:      "anyID",
+		Content: &openrtb2.Content{ID: "anyContent", Data: []openrtb2.Data{{ID: "anyData"}}},
+	}
+
+	result := NewScrubber().ScrubSite(site, ScrubStrategyContentDataAll)
+	assert.Equal(t, &openrtb2.Site{ID: "anyID", Content: &openrtb2.Content{ID: "anyContent"}}, result, "all")
+	assert.Len(t, site.Content.Data, 1, "Incoming site.content.data modified")
+
+	result = NewScrubber().ScrubSite(site, ScrubStrategyContentDataNone)
+	assert.Same(t, site, result, "none")
+
+	result = NewScrubber().ScrubSite(&openrtb2.Site{ID: "anyID"}, ScrubStrategyContentDataAll)
+	assert.Equal(t, &openrtb2.Site{ID: "anyID"}, result, "no content")
+
+	assert.Nil(t, NewScrubber().ScrubSite(nil, ScrubStrategyContentDataAll), "nil")
+}
+
+func TestScrubApp(t *testing.T) {
+	app := &openrtb2.App{
+		ID:      "anyID",
+		Content: &openrtb2.Content{ID: "anyContent", Data: []openrtb2.Data{{ID: "anyData"}}},
+	}
+
+	result := NewScrubber().ScrubApp(app, ScrubStrategyContentDataAll)
+	assert.Equal(t, &openrtb2.App{ID: "anyID", Content: &openrtb2.Content{ID: "anyContent"}}, result, "all")
+	assert.Len(t, app.Content.Data, 1, "Incoming app.content.data modified")
+
+	result = NewScrubber().ScrubApp(app, ScrubStrategyContentDataNone)
+	assert.Same(t, app, result, "none")
+
+	assert.Nil(t, NewScrubber().ScrubApp(nil, ScrubStrategyContentDataAll), "nil")
+}
+
 func TestScrubIPV4(t *testing.T) {
 	testCases := []struct {
 		IP          string
@@ -5,12 +5,13 @@ import (
 	"net/http/pprof"
 	"time"
 
+	"github.com/prebid/prebid-server/bidderhealth"
 	"github.com/prebid/prebid-server/currency"
 	"github.com/prebid/prebid-server/endpoints"
 	"github.com/prebid/prebid-server/version"
 )
 
-func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration) *http.ServeMux {
+func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration, bidderHealth bidderhealth.Tracker) *http.ServeMux {
 	// Add endpoints to the admin server
 	// Making sure to add pprof routes
 	mux := http.NewServeMux()
@@ -22,6 +23,7 @@ func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 	// Register prebid-server defined admin handlers
 	mux.HandleFunc("/currency/rates", endpoints.NewCurrencyRatesEndpoint(rateConverter, rateConverterFetchingInterval))
+	mux.HandleFunc("/bidders/health", endpoints.NewBidderHealthEndpoint(bidderHealth))
 	mux.HandleFunc("/version", endpoints.NewVersionEndpoint(version.Ver, version.Rev))
 	return mux
 }
@@ -11,8 +11,10 @@ import (
 	"time"
 
 	analyticsConf "github.com/prebid/prebid-server/analytics/config"
+	"github.com/prebid/prebid-server/bidderhealth"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/devicedetection"
 	"github.com/prebid/prebid-server/endpoints"
 	"github.com/prebid/prebid-server/endpoints/events"
 	infoEndpoints "github.com/prebid/prebid-server/endpoints/info"
@@ -20,7 +22,9 @@ import (
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/exchange"
 	"github.com/prebid/prebid-server/experiment/adscert"
+	"github.com/prebid/prebid-server/floors"
 	"github.com/prebid/prebid-server/gdpr"
+	"github.com/prebid/prebid-server/geolocation"
 	"github.com/prebid/prebid-server/hooks"
 	"github.com/prebid/prebid-server/metrics"
 	metricsConf "github.com/prebid/prebid-server/metrics/config"
@@ -32,6 +36,7 @@ import (
 	"github.com/prebid/prebid-server/server/ssl"
 	storedRequestsConf "github.com/prebid/prebid-server/stored_requests/config"
 	"github.com/prebid/prebid-server/usersync"
+	"github.com/prebid/prebid-server/util/task"
 	"github.com/prebid/prebid-server/util/uuidutil"
 	"github.com/prebid/prebid-server/version"
 
@@ -71,6 +76,13 @@ func NewJsonDirectoryServer(schemaDirectory string, validator openrtb_ext.Bidder
 		data[bidder] = json.RawMessage(validator.Schema(bidderName))
 	}
 
+	// Add in the aliases declared in the bidder info files
+	for aliasName, bidderName := range openrtb_ext.GetAliasBidderToParent() {
+		if bidderData, ok := data[string(bidderName)]; ok {
+			data[string(aliasName)] = bidderData
+		}
+	}
+
 	// Add in any default aliases
 	for aliasName, bidderName := range aliases {
 		bidderData, ok := data[bidderName]
@@ -110,6 +122,7 @@ type Router struct {
 	*httprouter.Router
 	MetricsEngine   *metricsConf.DetailedMetricsEngine
 	ParamsValidator openrtb_ext.BidderParamValidator
+	BidderHealth    bidderhealth.Tracker
 	Shutdown        func()
 }
 
@@ -199,7 +212,14 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 	gdprPermsBuilder := gdpr.NewPermissionsBuilder(cfg.GDPR, gvlVendorIDs, vendorListFetcher)
 	tcf2CfgBuilder := gdpr.NewTCF2Config
 
-	cacheClient := pbc.NewClient(cacheHttpClient, &cfg.CacheURL, &cfg.ExtCacheURL, r.MetricsEngine)
+	var cacheClient pbc.Client
+	var cacheStore *pbc.Store
+	if cfg.CacheURL.Embedded.Enabled {
+		cacheStore = pbc.NewStore(cfg.CacheURL.Embedded.MaxSizeBytes)
+		cacheClient = pbc.NewLocalClient(cacheStore, &cfg.CacheURL.Embedded, &cfg.ExtCacheURL, cfg.ExternalURL, r.MetricsEngine)
+	} else {
+		cacheClient = pbc.NewClient(cacheHttpClient, &cfg.CacheURL, &cfg.ExtCacheURL, r.MetricsEngine)
+	}
 
 	adapters, adaptersErrs := exchange.BuildAdapters(generalHttpClient, cfg, cfg.BidderInfos, r.MetricsEngine)
 	if len(adaptersErrs) > 0 {
@@ -211,20 +231,62 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 		glog.Fatalf("Failed to create ads cert signer: %v", err)
 	}
 
+	var priceFloorFetcher floors.FloorFetcher
+	if cfg.PriceFloors.Enabled {
+		floorFetcher := floors.NewPriceFloorFetcher(generalHttpClient, cfg.PriceFloors.Fetcher.FileDirectory)
+		floorFetcherTask := task.NewTickerTask(time.Duration(cfg.PriceFloors.Fetcher.CheckIntervalSeconds)*time.Second, floorFetcher)
+		floorFetcherTask.Start()
+		storedRequestsShutdown := r.Shutdown
+		r.Shutdown = func() {
+			floorFetcherTask.Stop()
+			storedRequestsShutdown()
+		}
+		priceFloorFetcher = floorFetcher
+	}
+
+	r.BidderHealth = bidderhealth.NewTracker(cfg.BidderHealth, r.MetricsEngine)
+
+	var geoLocation geolocation.GeoLocation
+	if cfg.Geolocation.Enabled {
+		geoDatabase := geolocation.NewDatabase(cfg.Geolocation.DatabasePath)
+		geoDatabaseTask := task.NewTickerTask(time.Duration(cfg.Geolocation.RefreshIntervalSeconds)*time.Second, geoDatabase)
+		geoDatabaseTask.Start()
+		previousShutdown := r.Shutdown
+		r.Shutdown = func() {
+			geoDatabaseTask.Stop()
+			previousShutdown()
+		}
+		geoLocation = geoDatabase
+	}
+
+	var deviceDetector devicedetection.DeviceDetector
+	if cfg.DeviceDetection.Enabled {
+		ruleDetector, err := devicedetection.NewRuleDetector(cfg.DeviceDetection.RulesFile)
+		if err != nil {
+			glog.Fatalf("Failed to load the device detection rules. %v", err)
+		}
+		deviceDetector = devicedetection.NewCachedDetector(ruleDetector, cfg.DeviceDetection.CacheSize)
+	}
+
+	uidStore, err := usersync.NewUIDStore(cfg.UIDStore)
+	if err != nil {
+		glog.Fatalf("Failed to open the uid store. %v", err)
+	}
+
 	planBuilder := hooks.NewExecutionPlanBuilder(cfg.Hooks, repo)
-	theExchange := exchange.NewExchange(adapters, cacheClient, cfg, syncersByBidder, r.MetricsEngine, cfg.BidderInfos, gdprPermsBuilder, tcf2CfgBuilder, rateConvertor, categoriesFetcher, adsCertSigner)
+	theExchange := exchange.NewExchange(adapters, cacheClient, cfg, syncersByBidder, r.MetricsEngine, cfg.BidderInfos, gdprPermsBuilder, tcf2CfgBuilder, rateConvertor, categoriesFetcher, adsCertSigner, priceFloorFetcher, r.BidderHealth)
 	var uuidGenerator uuidutil.UUIDRandomGenerator
-	openrtbEndpoint, err := openrtb2.NewEndpoint(uuidGenerator, theExchange, paramsValidator, fetcher, accounts, cfg, r.MetricsEngine, pbsAnalytics, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder)
+	openrtbEndpoint, err := openrtb2.NewEndpoint(uuidGenerator, theExchange, paramsValidator, fetcher, accounts, cfg, r.MetricsEngine, pbsAnalytics, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, geoLocation, deviceDetector, uidStore)
 	if err != nil {
 		glog.Fatalf("Failed to create the openrtb2 endpoint handler. %v", err)
 	}
 
-	ampEndpoint, err := openrtb2.NewAmpEndpoint(uuidGenerator, theExchange, paramsValidator, ampFetcher, accounts, cfg, r.MetricsEngine, pbsAnalytics, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder)
+	ampEndpoint, err := openrtb2.NewAmpEndpoint(uuidGenerator, theExchange, paramsValidator, ampFetcher, accounts, cfg, r.MetricsEngine, pbsAnalytics, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, geoLocation, deviceDetector, uidStore)
 	if err != nil {
 		glog.Fatalf("Failed to create the amp endpoint handler. %v", err)
 	}
 
-	videoEndpoint, err := openrtb2.NewVideoEndpoint(uuidGenerator, theExchange, paramsValidator, fetcher, videoFetcher, accounts, cfg, r.MetricsEngine, pbsAnalytics, disabledBidders, defReqJSON, activeBidders, cacheClient)
+	videoEndpoint, err := openrtb2.NewVideoEndpoint(uuidGenerator, theExchange, paramsValidator, fetcher, videoFetcher, accounts, cfg, r.MetricsEngine, pbsAnalytics, disabledBidders, defReqJSON, activeBidders, cacheClient, geoLocation, deviceDetector, uidStore)
 	if err != nil {
 		glog.Fatalf("Failed to create the video endpoint handler. %v", err)
 	}
@@ -246,6 +308,15 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 	r.Handler("GET", "/version", endpoints.NewVersionEndpoint(version.Ver, version.Rev))
 	r.ServeFiles("/static/*filepath", http.Dir("static"))
 
+	// embedded cache endpoints
+	if cacheStore != nil {
+		cacheUploadClient := pbc.NewLocalUploadClient(cacheStore, &cfg.CacheURL.Embedded, r.MetricsEngine)
+		cachePutEndpoint := endpoints.NewCachePutEndpoint(cacheUploadClient, cfg)
+		r.Handler("POST", "/cache", cachePutEndpoint)
+		r.Handler("PUT", "/cache", cachePutEndpoint)
+		r.Handler("GET", "/cache", endpoints.NewCacheGetEndpoint(cacheStore))
+	}
+
 	// vtrack endpoint
 	if cfg.VTrack.Enabled {
 		vtrackEndpoint := events.NewVTrackEndpoint(cfg, accounts, cacheClient, cfg.BidderInfos)
@@ -260,9 +331,10 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 		HostCookieConfig: &(cfg.HostCookie),
 		ExternalUrl:      cfg.ExternalURL,
 		RecaptchaSecret:  cfg.RecaptchaSecret,
+		UIDStore:         uidStore,
 	}
 
-	r.GET("/setuid", endpoints.NewSetUIDEndpoint(cfg, syncersByBidder, gdprPermsBuilder, tcf2CfgBuilder, pbsAnalytics, accounts, r.MetricsEngine))
+	r.GET("/setuid", endpoints.NewSetUIDEndpoint(cfg, syncersByBidder, gdprPermsBuilder, tcf2CfgBuilder, pbsAnalytics, accounts, r.MetricsEngine, uidStore))
 	r.GET("/getuids", endpoints.NewGetUIDsEndpoint(cfg.HostCookie))
 	r.POST("/optout", userSyncDeps.OptOut)
 	r.GET("/optout", userSyncDeps.OptOut)
@@ -1,5 +1,7 @@
 package usersync
 
+import "sort"
+
 // bidderChooser determines which bidders to consider for user syncing.
 type bidderChooser interface {
 	// choose returns an ordered collection of potentially non-unique bidders.
@@ -9,6 +11,10 @@ type bidderChooser interface {
 // standardBidderChooser implements the bidder choosing algorithm per official Prebid specification.
 type standardBidderChooser struct {
 	shuffler shuffler
+	// bidderSyncerLookup holds the syncers whose priority orders the bidders of each segment (the requested
+	// bidders, each priority group and the available bidders) after they are shuffled. The ordering never
+	// moves a bidder to another segment.
+	bidderSyncerLookup map[string]Syncer
 }
 
 func (c standardBidderChooser) choose(requested, available []string, cooperative Cooperative) []string {
@@ -48,13 +54,29 @@ func (c standardBidderChooser) chooseCooperative(requested, available []string,
 func (c standardBidderChooser) shuffledCopy(a []string) []string {
 	aCopy := make([]string, len(a))
 	copy(aCopy, a)
-	c.shuffler.shuffle(aCopy)
+	c.shuffle(aCopy)
 	return aCopy
 }
 
 func (c standardBidderChooser) shuffledAppend(a, b []string) []string {
 	startIndex := len(a)
 	a = append(a, b...)
-	c.shuffler.shuffle(a[startIndex:])
+	c.shuffle(a[startIndex:])
 	return a
 }
+
+// shuffle shuffles the segment of bidders and then moves the bidders whose syncer has a higher priority first,
+// keeping the shuffled order of the bidders with the same priority.
+func (c standardBidderChooser) shuffle(bidders []string) {
+	c.shuffler.shuffle(bidders)
+	sort.SliceStable(bidders, func(i, j int) bool {
+		return c.priority(bidders[i]) > c.priority(bidders[j])
+	})
+}
+
+func (c standardBidderChooser) priority(bidder string) int {
+	if syncer, exists := c.bidderSyncerLookup[bidder]; exists {
+		return syncer.Priority()
+	}
+	return 0
+}
@@ -128,6 +128,21 @@ func TestBidderChooserCooperative(t *testing.T) {
 	}
 }
 
+func TestBidderChooserCooperativePriority(t *testing.T) {
+	chooser := standardBidderChooser{
+		shuffler: reverseShuffler{},
+		bidderSyncerLookup: map[string]Syncer{
+			"r2":   fakeSyncer{priority: 1},
+			"pr1A": fakeSyncer{priority: 5},
+			"a1":   fakeSyncer{priority: 10},
+		},
+	}
+
+	result := chooser.chooseCooperative([]string{"r1", "r2"}, []string{"a1", "a2"}, [][]string{{"pr1A", "pr1B"}, {"pr2A", "pr2B"}})
+
+	assert.Equal(t, []string{"r2", "r1", "pr1A", "pr1B", "pr2B", "pr2A", "a1", "a2"}, result)
+}
+
 func TestBidderChooserShuffledCopy(t *testing.T) {
 	shuffler := reverseShuffler{}
 
@@ -17,7 +17,8 @@ func NewChooser(bidderSyncerLookup map[string]Syncer) Chooser {
 	return standardChooser{
 		bidderSyncerLookup: bidderSyncerLookup,
 		biddersAvailable:   bidders,
-		bidderChooser:      standardBidderChooser{shuffler: randomShuffler{}},
+		bidderChooser:      standardBidderChooser{shuffler: randomShuffler{}, bidderSyncerLookup: bidderSyncerLookup},
+		shuffler:           randomShuffler{},
 	}
 }
 
@@ -28,6 +29,12 @@ type Request struct {
 	Limit          int
 	Privacy        Privacy
 	SyncTypeFilter SyncTypeFilter
+	// AccountFilter holds the bidders the account allows to sync. A nil filter allows all bidders.
+	AccountFilter BidderFilter
+	// AccountPriorityGroups are considered before all the other bidders, a group at a time.
+	AccountPriorityGroups [][]string
+	// Debug reports why the bidders which aren't synced were skipped.
+	Debug bool
 }
 
 // Cooperative specifies the settings for cooperative syncing for a given request, where bidders
@@ -74,6 +81,13 @@ const (
 	// StatusBlockedByCCPA specifies a user's CCPA consent explicitly forbids bidder syncing.
 	StatusBlockedByCCPA
 
+	// StatusBlockedByGPP specifies the US National or US state sections of a user's GPP consent
+	// forbid bidder syncing.
+	StatusBlockedByGPP
+
+	// StatusBlockedByActivity specifies the account activity controls forbid bidder syncing.
+	StatusBlockedByActivity
+
 	// StatusAlreadySynced specifies a user's cookie has an existing non-expired sync for a specific bidder.
 	StatusAlreadySynced
 
@@ -85,6 +99,9 @@ const (
 
 	// StatusDuplicate specifies the bidder is a duplicate or shared a syncer key with another bidder choice.
 	StatusDuplicate
+
+	// StatusBlockedByAccount specifies the account's cookie sync rules exclude the bidder.
+	StatusBlockedByAccount
 )
 
 // Privacy determines which privacy policies will be enforced for a user sync request.
@@ -92,6 +109,8 @@ type Privacy interface {
 	GDPRAllowsHostCookie() bool
 	GDPRAllowsBidderSync(bidder string) bool
 	CCPAAllowsBidderSync(bidder string) bool
+	GPPAllowsBidderSync(bidder string) bool
+	ActivityAllowsUserSync(bidder string) bool
 }
 
 // standardChooser implements the user syncer algorithm per official Prebid specification.
@@ -99,6 +118,7 @@ type standardChooser struct {
 	bidderSyncerLookup map[string]Syncer
 	biddersAvailable   []string
 	bidderChooser      bidderChooser
+	shuffler           shuffler
 }
 
 // Choose randomly selects user syncers which are permitted by the user's privacy settings and
@@ -119,8 +139,9 @@ func (c standardChooser) Choose(request Request, cookie *Cookie) Result {
 	syncersChosen := make([]SyncerChoice, 0)
 
 	bidders := c.bidderChooser.choose(request.Bidders, c.biddersAvailable, request.Cooperative)
+	bidders = c.prependAccountPriorityGroups(bidders, request.AccountPriorityGroups)
 	for i := 0; i < len(bidders) && (limitDisabled || len(syncersChosen) < request.Limit); i++ {
-		syncer, evaluation := c.evaluate(bidders[i], syncersSeen, request.SyncTypeFilter, request.Privacy, cookie)
+		syncer, evaluation := c.evaluate(bidders[i], syncersSeen, request.AccountFilter, request.SyncTypeFilter, request.Privacy, cookie)
 
 		biddersEvaluated = append(biddersEvaluated, evaluation)
 		if evaluation.Status == StatusOK {
@@ -131,12 +152,33 @@ func (c standardChooser) Choose(request Request, cookie *Cookie) Result {
 	return Result{Status: StatusOK, BiddersEvaluated: biddersEvaluated, SyncersChosen: syncersChosen}
 }
 
-func (c standardChooser) evaluate(bidder string, syncersSeen map[string]struct{}, syncTypeFilter SyncTypeFilter, privacy Privacy, cookie *Cookie) (Syncer, BidderEvaluation) {
+// prependAccountPriorityGroups puts the bidders of the account priority groups before the other bidders. The
+// bidders of each group are shuffled, like the cooperative priority groups. A bidder which is also among the
+// other bidders is evaluated once, as a duplicate is skipped.
+func (c standardChooser) prependAccountPriorityGroups(bidders []string, priorityGroups [][]string) []string {
+	if len(priorityGroups) == 0 {
+		return bidders
+	}
+
+	prioritized := make([]string, 0, len(bidders))
+	for _, group := range priorityGroups {
+		start := len(prioritized)
+		prioritized = append(prioritized, group...)
+		c.shuffler.shuffle(prioritized[start:])
+	}
+	return append(prioritized, bidders...)
+}
+
+func (c standardChooser) evaluate(bidder string, syncersSeen map[string]struct{}, accountFilter BidderFilter, syncTypeFilter SyncTypeFilter, privacy Privacy, cookie *Cookie) (Syncer, BidderEvaluation) {
 	syncer, exists := c.bidderSyncerLookup[bidder]
 	if !exists {
 		return nil, BidderEvaluation{Bidder: bidder, Status: StatusUnknownBidder}
 	}
 
+	if accountFilter != nil && !accountFilter.Allowed(bidder) {
+		return nil, BidderEvaluation{Bidder: bidder, Status: StatusBlockedByAccount}
+	}
+
 	_, seen := syncersSeen[syncer.Key()]
 	if seen {
 		return nil, BidderEvaluation{Bidder: bidder, Status: StatusDuplicate}
@@ -159,5 +201,13 @@ func (c standardChooser) evaluate(bidder string, syncersSeen map[string]struct{}
 		return nil, BidderEvaluation{Bidder: bidder, Status: StatusBlockedByCCPA}
 	}
 
+	if !privacy.GPPAllowsBidderSync(bidder) {
+		return nil, BidderEvaluation{Bidder: bidder, Status: StatusBlockedByGPP}
+	}
+
+	if !privacy.ActivityAllowsUserSync(bidder) {
+		return nil, BidderEvaluation{Bidder: bidder, Status: StatusBlockedByActivity}
+	}
+
 	return syncer, BidderEvaluation{Bidder: bidder, Status: StatusOK}
 }
@@ -66,7 +66,7 @@ func TestChooserChoose(t *testing.T) {
 		{
 			description: "Cookie Opt Out",
 			givenRequest: Request{
-				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true},
+				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 				Limit:   0,
 			},
 			givenChosenBidders: []string{"a"},
@@ -80,7 +80,7 @@ func TestChooserChoose(t *testing.T) {
 		{
 			description: "GDPR Host Cookie Not Allowed",
 			givenRequest: Request{
-				Privacy: fakePrivacy{gdprAllowsHostCookie: false, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true},
+				Privacy: fakePrivacy{gdprAllowsHostCookie: false, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 				Limit:   0,
 			},
 			givenChosenBidders: []string{"a"},
@@ -94,7 +94,7 @@ func TestChooserChoose(t *testing.T) {
 		{
 			description: "No Bidders",
 			givenRequest: Request{
-				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true},
+				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 				Limit:   0,
 			},
 			givenChosenBidders: []string{},
@@ -108,7 +108,7 @@ func TestChooserChoose(t *testing.T) {
 		{
 			description: "One Bidder - Sync",
 			givenRequest: Request{
-				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true},
+				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 				Limit:   0,
 			},
 			givenChosenBidders: []string{"a"},
@@ -122,7 +122,7 @@ func TestChooserChoose(t *testing.T) {
 		{
 			description: "One Bidder - No Sync",
 			givenRequest: Request{
-				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true},
+				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 				Limit:   0,
 			},
 			givenChosenBidders: []string{"c"},
@@ -136,7 +136,7 @@ func TestChooserChoose(t *testing.T) {
 		{
 			description: "Many Bidders - All Sync - Limit Disabled With 0",
 			givenRequest: Request{
-				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true},
+				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 				Limit:   0,
 			},
 			givenChosenBidders: []string{"a", "b"},
@@ -150,7 +150,7 @@ func TestChooserChoose(t *testing.T) {
 		{
 			description: "Many Bidders - All Sync - Limit Disabled With Negative Value",
 			givenRequest: Request{
-				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true},
+				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 				Limit:   -1,
 			},
 			givenChosenBidders: []string{"a", "b"},
@@ -164,7 +164,7 @@ func TestChooserChoose(t *testing.T) {
 		{
 			description: "Many Bidders - Limited Sync",
 			givenRequest: Request{
-				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true},
+				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 				Limit:   1,
 			},
 			givenChosenBidders: []string{"a", "b"},
@@ -178,7 +178,7 @@ func TestChooserChoose(t *testing.T) {
 		{
 			description: "Many Bidders - Limited Sync - Disqualified Syncers Don't Count Towards Limit",
 			givenRequest: Request{
-				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true},
+				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 				Limit:   1,
 			},
 			givenChosenBidders: []string{"c", "a", "b"},
@@ -192,7 +192,7 @@ func TestChooserChoose(t *testing.T) {
 		{
 			description: "Many Bidders - Some Sync, Some Don't",
 			givenRequest: Request{
-				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true},
+				Privacy: fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 				Limit:   0,
 			},
 			givenChosenBidders: []string{"a", "c"},
@@ -229,6 +229,115 @@ func TestChooserChoose(t *testing.T) {
 	}
 }
 
+func TestChooserChoosePriority(t *testing.T) {
+	fakeSyncerA := fakeSyncer{key: "keyA", supportsIFrame: true}
+	fakeSyncerB := fakeSyncer{key: "keyB", supportsIFrame: true, priority: 10}
+	fakeSyncerC := fakeSyncer{key: "keyC", supportsIFrame: true, priority: 5}
+	bidderSyncerLookup := map[string]Syncer{"a": fakeSyncerA, "b": fakeSyncerB, "c": fakeSyncerC}
+	privacy := fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true}
+	syncTypeFilter := SyncTypeFilter{
+		IFrame:   NewUniformBidderFilter(BidderFilterModeInclude),
+		Redirect: NewUniformBidderFilter(BidderFilterModeExclude)}
+
+	request := Request{
+		Bidders:        []string{"a", "b", "c", "unknown"},
+		Limit:          2,
+		Privacy:        privacy,
+		SyncTypeFilter: syncTypeFilter,
+	}
+
+	chooser := standardChooser{
+		bidderSyncerLookup: bidderSyncerLookup,
+		biddersAvailable:   []string{"a", "b", "c"},
+		bidderChooser:      standardBidderChooser{shuffler: reverseShuffler{}, bidderSyncerLookup: bidderSyncerLookup},
+	}
+
+	result := chooser.Choose(request, &Cookie{})
+	assert.Equal(t, []BidderEvaluation{{Bidder: "b", Status: StatusOK}, {Bidder: "c", Status: StatusOK}}, result.BiddersEvaluated)
+	assert.Equal(t, []SyncerChoice{{Bidder: "b", Syncer: fakeSyncerB}, {Bidder: "c", Syncer: fakeSyncerC}}, result.SyncersChosen)
+}
+
+func TestChooserChoosePriorityCooperative(t *testing.T) {
+	fakeSyncerA := fakeSyncer{key: "keyA", supportsIFrame: true}
+	fakeSyncerB := fakeSyncer{key: "keyB", supportsIFrame: true, priority: 10}
+	fakeSyncerC := fakeSyncer{key: "keyC", supportsIFrame: true, priority: 5}
+	fakeSyncerD := fakeSyncer{key: "keyD", supportsIFrame: true}
+	bidderSyncerLookup := map[string]Syncer{"a": fakeSyncerA, "b": fakeSyncerB, "c": fakeSyncerC, "d": fakeSyncerD}
+	privacy := fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true}
+	syncTypeFilter := SyncTypeFilter{
+		IFrame:   NewUniformBidderFilter(BidderFilterModeInclude),
+		Redirect: NewUniformBidderFilter(BidderFilterModeExclude)}
+
+	request := Request{
+		Bidders:        []string{"a"},
+		Cooperative:    Cooperative{Enabled: true, PriorityGroups: [][]string{{"d", "c"}}},
+		Limit:          3,
+		Privacy:        privacy,
+		SyncTypeFilter: syncTypeFilter,
+	}
+
+	chooser := standardChooser{
+		bidderSyncerLookup: bidderSyncerLookup,
+		biddersAvailable:   []string{"a", "b", "c", "d"},
+		bidderChooser:      standardBidderChooser{shuffler: reverseShuffler{}, bidderSyncerLookup: bidderSyncerLookup},
+	}
+
+	// the requested bidder comes first and the priority group second, even though other bidders have a
+	// higher priority
+	result := chooser.Choose(request, &Cookie{})
+	assert.Equal(t, []SyncerChoice{
+		{Bidder: "a", Syncer: fakeSyncerA},
+		{Bidder: "c", Syncer: fakeSyncerC},
+		{Bidder: "d", Syncer: fakeSyncerD},
+	}, result.SyncersChosen)
+}
+
+func TestChooserChooseAccountRules(t *testing.T) {
+	fakeSyncerA := fakeSyncer{key: "keyA", supportsIFrame: true}
+	fakeSyncerB := fakeSyncer{key: "keyB", supportsIFrame: true, priority: 10}
+	fakeSyncerC := fakeSyncer{key: "keyC", supportsIFrame: true}
+	fakeSyncerD := fakeSyncer{key: "keyD", supportsIFrame: true}
+	bidderSyncerLookup := map[string]Syncer{"a": fakeSyncerA, "b": fakeSyncerB, "c": fakeSyncerC, "d": fakeSyncerD}
+	privacy := fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true}
+	syncTypeFilter := SyncTypeFilter{
+		IFrame:   NewUniformBidderFilter(BidderFilterModeInclude),
+		Redirect: NewUniformBidderFilter(BidderFilterModeExclude)}
+
+	request := Request{
+		Bidders:               []string{"a", "b", "c"},
+		Limit:                 3,
+		Privacy:               privacy,
+		SyncTypeFilter:        syncTypeFilter,
+		AccountFilter:         NewSpecificBidderFilter([]string{"c"}, BidderFilterModeExclude),
+		AccountPriorityGroups: [][]string{{"c", "d"}, {"a"}},
+	}
+
+	mockBidderChooser := &mockBidderChooser{}
+	mockBidderChooser.
+		On("choose", request.Bidders, []string{"a", "b", "c", "d"}, Cooperative{}).
+		Return([]string{"b", "a", "c"})
+
+	chooser := standardChooser{
+		bidderSyncerLookup: bidderSyncerLookup,
+		biddersAvailable:   []string{"a", "b", "c", "d"},
+		bidderChooser:      mockBidderChooser,
+		shuffler:           reverseShuffler{},
+	}
+
+	result := chooser.Choose(request, &Cookie{})
+	assert.Equal(t, []BidderEvaluation{
+		{Bidder: "d", Status: StatusOK},
+		{Bidder: "c", Status: StatusBlockedByAccount},
+		{Bidder: "a", Status: StatusOK},
+		{Bidder: "b", Status: StatusOK},
+	}, result.BiddersEvaluated)
+	assert.Equal(t, []SyncerChoice{
+		{Bidder: "d", Syncer: fakeSyncerD},
+		{Bidder: "a", Syncer: fakeSyncerA},
+		{Bidder: "b", Syncer: fakeSyncerB},
+	}, result.SyncersChosen)
+}
+
 func TestChooserEvaluate(t *testing.T) {
 	fakeSyncerA := fakeSyncer{key: "keyA", supportsIFrame: true}
 	fakeSyncerB := fakeSyncer{key: "keyB", supportsIFrame: false}
@@ -242,20 +351,21 @@ func TestChooserEvaluate(t *testing.T) {
 	cookieAlreadyHasSyncForB := Cookie{uids: map[string]uidWithExpiry{"keyB": {Expires: time.Now().Add(time.Duration(24) * time.Hour)}}}
 
 	testCases := []struct {
-		description      string
-		givenBidder      string
-		givenSyncersSeen map[string]struct{}
-		givenPrivacy     Privacy
-		givenCookie      Cookie
-		expectedSyncer   Syncer
-		expectedBidder   string
-		expectedStatus   Status
+		description        string
+		givenBidder        string
+		givenSyncersSeen   map[string]struct{}
+		givenAccountFilter BidderFilter
+		givenPrivacy       Privacy
+		givenCookie        Cookie
+		expectedSyncer     Syncer
+		expectedBidder     string
+		expectedStatus     Status
 	}{
 		{
 			description:      "Valid",
 			givenBidder:      "a",
 			givenSyncersSeen: map[string]struct{}{},
-			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true},
+			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 			givenCookie:      cookieNeedsSync,
 			expectedSyncer:   fakeSyncerA,
 			expectedBidder:   "a",
@@ -265,7 +375,7 @@ func TestChooserEvaluate(t *testing.T) {
 			description:      "Unknown Bidder",
 			givenBidder:      "unknown",
 			givenSyncersSeen: map[string]struct{}{},
-			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true},
+			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 			givenCookie:      cookieNeedsSync,
 			expectedSyncer:   nil,
 			expectedBidder:   "unknown",
@@ -275,7 +385,7 @@ func TestChooserEvaluate(t *testing.T) {
 			description:      "Duplicate Syncer",
 			givenBidder:      "a",
 			givenSyncersSeen: map[string]struct{}{"keyA": {}},
-			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true},
+			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 			givenCookie:      cookieNeedsSync,
 			expectedSyncer:   nil,
 			expectedBidder:   "a",
@@ -285,7 +395,7 @@ func TestChooserEvaluate(t *testing.T) {
 			description:      "Incompatible Kind",
 			givenBidder:      "b",
 			givenSyncersSeen: map[string]struct{}{},
-			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true},
+			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 			givenCookie:      cookieNeedsSync,
 			expectedSyncer:   nil,
 			expectedBidder:   "b",
@@ -295,7 +405,7 @@ func TestChooserEvaluate(t *testing.T) {
 			description:      "Already Synced",
 			givenBidder:      "a",
 			givenSyncersSeen: map[string]struct{}{},
-			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true},
+			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 			givenCookie:      cookieAlreadyHasSyncForA,
 			expectedSyncer:   nil,
 			expectedBidder:   "a",
@@ -305,7 +415,7 @@ func TestChooserEvaluate(t *testing.T) {
 			description:      "Different Bidder Already Synced",
 			givenBidder:      "a",
 			givenSyncersSeen: map[string]struct{}{},
-			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true},
+			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 			givenCookie:      cookieAlreadyHasSyncForB,
 			expectedSyncer:   fakeSyncerA,
 			expectedBidder:   "a",
@@ -315,7 +425,7 @@ func TestChooserEvaluate(t *testing.T) {
 			description:      "Blocked By GDPR",
 			givenBidder:      "a",
 			givenSyncersSeen: map[string]struct{}{},
-			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: false, ccpaAllowsBidderSync: true},
+			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: false, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 			givenCookie:      cookieNeedsSync,
 			expectedSyncer:   nil,
 			expectedBidder:   "a",
@@ -325,17 +435,59 @@ func TestChooserEvaluate(t *testing.T) {
 			description:      "Blocked By CCPA",
 			givenBidder:      "a",
 			givenSyncersSeen: map[string]struct{}{},
-			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: false},
+			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: false, gppAllowsBidderSync: true, activityAllowsUserSync: true},
 			givenCookie:      cookieNeedsSync,
 			expectedSyncer:   nil,
 			expectedBidder:   "a",
 			expectedStatus:   StatusBlockedByCCPA,
 		},
+		{
+			description:      "Blocked By GPP",
+			givenBidder:      "a",
+			givenSyncersSeen: map[string]struct{}{},
+			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: false, activityAllowsUserSync: true},
+			givenCookie:      cookieNeedsSync,
+			expectedSyncer:   nil,
+			expectedBidder:   "a",
+			expectedStatus:   StatusBlockedByGPP,
+		},
+		{
+			description:      "Blocked By Activity Control",
+			givenBidder:      "a",
+			givenSyncersSeen: map[string]struct{}{},
+			givenPrivacy:     fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: false},
+			givenCookie:      cookieNeedsSync,
+			expectedSyncer:   nil,
+			expectedBidder:   "a",
+			expectedStatus:   StatusBlockedByActivity,
+		},
+		{
+			description:        "Allowed By Account",
+			givenBidder:        "a",
+			givenSyncersSeen:   map[string]struct{}{},
+			givenAccountFilter: NewSpecificBidderFilter([]string{"a"}, BidderFilterModeInclude),
+			givenPrivacy:       fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
+			givenCookie:        cookieNeedsSync,
+			expectedSyncer:     fakeSyncerA,
+			expectedBidder:     "a",
+			expectedStatus:     StatusOK,
+		},
+		{
+			description:        "Blocked By Account",
+			givenBidder:        "a",
+			givenSyncersSeen:   map[string]struct{}{},
+			givenAccountFilter: NewSpecificBidderFilter([]string{"a"}, BidderFilterModeExclude),
+			givenPrivacy:       fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, gppAllowsBidderSync: true, activityAllowsUserSync: true},
+			givenCookie:        cookieNeedsSync,
+			expectedSyncer:     nil,
+			expectedBidder:     "a",
+			expectedStatus:     StatusBlockedByAccount,
+		},
 	}
 
 	for _, test := range testCases {
 		chooser, _ := NewChooser(bidderSyncerLookup).(standardChooser)
-		sync, evaluation := chooser.evaluate(test.givenBidder, test.givenSyncersSeen, syncTypeFilter, test.givenPrivacy, &test.givenCookie)
+		sync, evaluation := chooser.evaluate(test.givenBidder, test.givenSyncersSeen, test.givenAccountFilter, syncTypeFilter, test.givenPrivacy, &test.givenCookie)
 
 		assert.Equal(t, test.expectedSyncer, sync, test.description+":syncer")
 
@@ -357,6 +509,7 @@ type fakeSyncer struct {
 	key              string
 	supportsIFrame   bool
 	supportsRedirect bool
+	priority         int
 }
 
 func (s fakeSyncer) Key() string {
@@ -383,10 +536,20 @@ func (fakeSyncer) GetSync(syncTypes []SyncType, privacyPolicies privacy.Policies
 	return Sync{}, nil
 }
 
+func (fakeSyncer) UIDTTL() time.Duration {
+	return uidTTL
+}
+
+func (s fakeSyncer) Priority() int {
+	return s.priority
+}
+
 type fakePrivacy struct {
-	gdprAllowsHostCookie bool
-	gdprAllowsBidderSync bool
-	ccpaAllowsBidderSync bool
+	gdprAllowsHostCookie   bool
+	gdprAllowsBidderSync   bool
+	ccpaAllowsBidderSync   bool
+	gppAllowsBidderSync    bool
+	activityAllowsUserSync bool
 }
 
 func (p fakePrivacy) GDPRAllowsHostCookie() bool {
@@ -400,3 +563,11 @@ func (p fakePrivacy) GDPRAllowsBidderSync(bidder string) bool {
 func (p fakePrivacy) CCPAAllowsBidderSync(bidder string) bool {
 	return p.ccpaAllowsBidderSync
 }
+
+func (p fakePrivacy) GPPAllowsBidderSync(bidder string) bool {
+	return p.gppAllowsBidderSync
+}
+
+func (p fakePrivacy) ActivityAllowsUserSync(bidder string) bool {
+	return p.activityAllowsUserSync
+}
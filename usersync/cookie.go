@@ -4,8 +4,9 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
-	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/prebid/prebid-server/config"
@@ -18,6 +19,20 @@ const uidCookieName = "uids"
 // separate from the cookie ttl.
 const uidTTL = 14 * 24 * time.Hour
 
+// SyncerPriorities maps syncer keys to the priority of their syncer. The UIDs of the syncers with the
+// lowest priority are the first ones removed from a cookie which is too large. Unlisted syncers have a
+// priority of 0.
+type SyncerPriorities map[string]int
+
+// NewSyncerPriorities returns the priorities of the syncers, keyed by syncer key.
+func NewSyncerPriorities(syncersByBidder map[string]Syncer) SyncerPriorities {
+	priorities := make(SyncerPriorities, len(syncersByBidder))
+	for _, syncer := range syncersByBidder {
+		priorities[syncer.Key()] = syncer.Priority()
+	}
+	return priorities
+}
+
 // Cookie is the cookie used in Prebid Server.
 //
 // To get an instance of this from a request, use ParseCookieFromRequest.
@@ -48,9 +63,8 @@ func ParseCookieFromRequest(r *http.Request, cookie *config.HostCookie) *Cookie
 		}
 	}
 	var parsed *Cookie
-	uidCookie, err2 := r.Cookie(uidCookieName)
-	if err2 == nil {
-		parsed = ParseCookie(uidCookie)
+	if value, ok := readCookieValue(r, cookie.MaxCookies); ok {
+		parsed = ParseCookie(&http.Cookie{Name: uidCookieName, Value: value})
 	} else {
 		parsed = NewCookie()
 	}
@@ -63,6 +77,33 @@ func ParseCookieFromRequest(r *http.Request, cookie *config.HostCookie) *Cookie
 	return parsed
 }
 
+// readCookieValue joins the values of the uids, uids2, uids3... cookies the UserSync cookie was split across.
+// It stops at the first missing cookie or after maxCookies of them, since the cookies beyond that are never
+// written nor expired. The second returned value is false if there was no uids cookie at all.
+func readCookieValue(r *http.Request, maxCookies int) (string, bool) {
+	if maxCookies < 1 {
+		maxCookies = 1
+	}
+
+	var value strings.Builder
+	for i := 1; i <= maxCookies; i++ {
+		httpCookie, err := r.Cookie(uidCookieNameAt(i))
+		if err != nil {
+			return value.String(), i > 1
+		}
+		value.WriteString(httpCookie.Value)
+	}
+	return value.String(), true
+}
+
+// uidCookieNameAt returns the name of the i-th cookie the UserSync cookie is split across, starting at 1.
+func uidCookieNameAt(i int) string {
+	if i <= 1 {
+		return uidCookieName
+	}
+	return uidCookieName + strconv.Itoa(i)
+}
+
 // ParseCookie parses the UserSync cookie from a raw HTTP cookie.
 func ParseCookie(httpCookie *http.Cookie) *Cookie {
 	jsonValue, err := base64.URLEncoding.DecodeString(httpCookie.Value)
@@ -104,7 +145,17 @@ func (cookie *Cookie) SetOptOut(optOut bool) {
 
 // Gets an HTTP cookie containing all the data from this UserSyncMap. This is a snapshot--not a live view.
 func (cookie *Cookie) ToHTTPCookie(ttl time.Duration) *http.Cookie {
-	j, _ := json.Marshal(cookie)
+	return cookie.toHTTPCookie(ttl, false)
+}
+
+// toHTTPCookie is ToHTTPCookie with the data written in the compact format when compact is true.
+func (cookie *Cookie) toHTTPCookie(ttl time.Duration, compact bool) *http.Cookie {
+	var j []byte
+	if compact {
+		j, _ = cookie.marshalCompactJSON()
+	} else {
+		j, _ = json.Marshal(cookie)
+	}
 	b64 := base64.URLEncoding.EncodeToString(j)
 
 	return &http.Cookie{
@@ -130,6 +181,17 @@ func (cookie *Cookie) GetUID(key string) (string, bool, bool) {
 	return "", false, false
 }
 
+// GetUIDExpiries returns when this user's ID expires for all the bidders
+func (cookie *Cookie) GetUIDExpiries() map[string]time.Time {
+	expiries := make(map[string]time.Time)
+	if cookie != nil {
+		for bidderName, uidWithExpiry := range cookie.uids {
+			expiries[bidderName] = uidWithExpiry.Expires
+		}
+	}
+	return expiries
+}
+
 // GetUIDs returns this user's ID for all the bidders
 func (cookie *Cookie) GetUIDs() map[string]string {
 	uids := make(map[string]string)
@@ -142,39 +204,123 @@ func (cookie *Cookie) GetUIDs() map[string]string {
 	return uids
 }
 
-// SetCookieOnResponse is a shortcut for "ToHTTPCookie(); cookie.setDomain(domain); setCookie(w, cookie)"
-func (cookie *Cookie) SetCookieOnResponse(w http.ResponseWriter, setSiteCookie bool, cfg *config.HostCookie, ttl time.Duration) {
-	httpCookie := cookie.ToHTTPCookie(ttl)
-	var domain string = cfg.Domain
+// SetCookieOnResponse writes this cookie on the response with the cookie attributes. It is split across the uids,
+// uids2, uids3... cookies when it exceeds cfg.MaxCookieSizeBytes, up to cfg.MaxCookies of them. UIDs are only
+// dropped when they don't fit in all of those cookies: the expired ones first, then the ones of the syncers with
+// the lowest priority. The cookie is written in the compact format when cfg.CompactFormat is enabled.
+func (cookie *Cookie) SetCookieOnResponse(w http.ResponseWriter, attributes CookieAttributes, cfg *config.HostCookie, ttl time.Duration, priorities SyncerPriorities) {
+	template := cookie.toHTTPCookie(ttl, cfg.CompactFormat)
+	attributes.apply(template)
+
+	maxCookies := cfg.MaxCookies
+	if maxCookies < 1 {
+		maxCookies = 1
+	}
 
-	if domain != "" {
-		httpCookie.Domain = domain
+	httpCookies, ok := splitHTTPCookie(template, attributes, cfg.MaxCookieSizeBytes, maxCookies)
+	for !ok && len(cookie.uids) > 0 {
+		delete(cookie.uids, cookie.uidToEject(priorities))
+		template.Value = cookie.toHTTPCookie(ttl, cfg.CompactFormat).Value
+		httpCookies, ok = splitHTTPCookie(template, attributes, cfg.MaxCookieSizeBytes, maxCookies)
+	}
+	if !ok {
+		// Even an empty cookie doesn't fit, so write it as a single cookie
+		httpCookies = []*http.Cookie{template}
 	}
 
-	var currSize int = len([]byte(httpCookie.String()))
-	for cfg.MaxCookieSizeBytes > 0 && currSize > cfg.MaxCookieSizeBytes && len(cookie.uids) > 0 {
-		var oldestElem string = ""
-		var oldestDate int64 = math.MaxInt64
-		for key, value := range cookie.uids {
-			timeUntilExpiration := time.Until(value.Expires)
-			if timeUntilExpiration < time.Duration(oldestDate) {
-				oldestElem = key
-				oldestDate = int64(timeUntilExpiration)
-			}
+	for _, httpCookie := range httpCookies {
+		attributes.setCookie(w, httpCookie)
+	}
+
+	// Expire the numbered cookies left over from a previous, larger, cookie
+	for i := len(httpCookies) + 1; i <= maxCookies && i > 1; i++ {
+		expired := *template
+		expired.Name = uidCookieNameAt(i)
+		expired.Value = ""
+		expired.Expires = time.Time{}
+		expired.MaxAge = -1
+		attributes.setCookie(w, &expired)
+	}
+}
+
+// uidToEject returns the key of the UID to remove from a cookie which is too large. Expired UIDs go first, then
+// the ones of the syncers with the lowest priority. Ties are broken by the UID which expires the soonest.
+func (cookie *Cookie) uidToEject(priorities SyncerPriorities) string {
+	now := time.Now()
+
+	var ejectedKey string
+	var ejectedUID uidWithExpiry
+	for key, uid := range cookie.uids {
+		if ejectedKey == "" || ejectBefore(key, uid, ejectedKey, ejectedUID, priorities, now) {
+			ejectedKey = key
+			ejectedUID = uid
+		}
+	}
+	return ejectedKey
+}
+
+func ejectBefore(key string, uid uidWithExpiry, otherKey string, otherUID uidWithExpiry, priorities SyncerPriorities, now time.Time) bool {
+	expired := !now.Before(uid.Expires)
+	otherExpired := !now.Before(otherUID.Expires)
+	if expired != otherExpired {
+		return expired
+	}
+
+	if !expired && priorities[key] != priorities[otherKey] {
+		return priorities[key] < priorities[otherKey]
+	}
+
+	return uid.Expires.Before(otherUID.Expires)
+}
+
+// splitHTTPCookie splits the value of the cookie across numbered cookies which are at most maxSize bytes long once
+// serialized. The second returned value is false if the value doesn't fit in maxCookies cookies. A maxSize of 0 or
+// less means the size is unlimited.
+func splitHTTPCookie(template *http.Cookie, attributes CookieAttributes, maxSize int, maxCookies int) ([]*http.Cookie, bool) {
+	if maxSize <= 0 {
+		return []*http.Cookie{template}, true
+	}
+
+	value := template.Value
+	httpCookies := make([]*http.Cookie, 0, 1)
+	for i := 1; i <= maxCookies; i++ {
+		httpCookie := *template
+		httpCookie.Name = uidCookieNameAt(i)
+		httpCookie.Value = ""
+
+		room := maxSize - len(attributes.cookieString(&httpCookie))
+		if room <= 0 {
+			return httpCookies, false
+		}
+		if room > len(value) {
+			room = len(value)
 		}
-		delete(cookie.uids, oldestElem)
-		httpCookie = cookie.ToHTTPCookie(ttl)
-		if domain != "" {
-			httpCookie.Domain = domain
+		httpCookie.Value = value[:room]
+		value = value[room:]
+		httpCookies = append(httpCookies, &httpCookie)
+
+		if len(value) == 0 {
+			return httpCookies, true
 		}
-		currSize = len([]byte(httpCookie.String()))
 	}
+	return httpCookies, false
+}
 
-	if setSiteCookie {
-		httpCookie.Secure = true
-		httpCookie.SameSite = http.SameSiteNoneMode
+// Merge adds the UIDs of other to this cookie. When both have a UID for the same syncer key, the one which
+// expires the latest is kept. Nothing is added if the user has opted out.
+func (cookie *Cookie) Merge(other *Cookie) {
+	if !cookie.AllowSyncs() || other == nil {
+		return
+	}
+
+	if cookie.uids == nil {
+		cookie.uids = make(map[string]uidWithExpiry, len(other.uids))
+	}
+	for key, uid := range other.uids {
+		if existing, ok := cookie.uids[key]; !ok || uid.Expires.After(existing.Expires) {
+			cookie.uids[key] = uid
+		}
 	}
-	w.Header().Add("Set-Cookie", httpCookie.String())
 }
 
 // Unsync removes the user's ID for the given syncer key from this cookie.
@@ -201,8 +347,15 @@ func (cookie *Cookie) HasAnyLiveSyncs() bool {
 	return false
 }
 
-// TrySync tries to set the UID for some syncer key. It returns an error if the set didn't happen.
+// TrySync tries to set the UID for some syncer key, valid for the default UID TTL. It returns an error if
+// the set didn't happen.
 func (cookie *Cookie) TrySync(key string, uid string) error {
+	return cookie.TrySyncWithTTL(key, uid, uidTTL)
+}
+
+// TrySyncWithTTL tries to set the UID for some syncer key, valid for the given amount of time. It returns an
+// error if the set didn't happen.
+func (cookie *Cookie) TrySyncWithTTL(key string, uid string, ttl time.Duration) error {
 	if !cookie.AllowSyncs() {
 		return errors.New("The user has opted out of prebid server cookie syncs.")
 	}
@@ -215,7 +368,7 @@ func (cookie *Cookie) TrySync(key string, uid string) error {
 
 	cookie.uids[key] = uidWithExpiry{
 		UID:     uid,
-		Expires: time.Now().Add(uidTTL),
+		Expires: time.Now().Add(ttl),
 	}
 
 	return nil
@@ -225,13 +378,27 @@ func (cookie *Cookie) TrySync(key string, uid string) error {
 //
 // This exists so that Cookie (which is public) can have private fields, and the rest of
 // the code doesn't have to worry about the cookie data storage format.
+//
+// Cookies are written in the verbose format unless the host enables the compact format, with short keys and
+// expiration dates in epoch seconds, so that more UIDs fit in a cookie. Both formats are read.
 type cookieJson struct {
+	CompactUIDs     map[string]compactUID `json:"u,omitempty"`
+	CompactOptOut   bool                  `json:"o,omitempty"`
+	CompactBirthday int64                 `json:"b,omitempty"`
+
 	LegacyUIDs map[string]string        `json:"uids,omitempty"`
 	UIDs       map[string]uidWithExpiry `json:"tempUIDs,omitempty"`
 	OptOut     bool                     `json:"optout,omitempty"`
 	Birthday   *time.Time               `json:"bday,omitempty"`
 }
 
+// compactUID is the compact storage format of an uidWithExpiry.
+type compactUID struct {
+	UID string `json:"i"`
+	// Expires is the expiration date in seconds since the epoch
+	Expires int64 `json:"e"`
+}
+
 func (cookie *Cookie) MarshalJSON() ([]byte, error) {
 	return json.Marshal(cookieJson{
 		UIDs:     cookie.uids,
@@ -240,6 +407,27 @@ func (cookie *Cookie) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// marshalCompactJSON writes the cookie in the compact format, which versions of Prebid Server older than the
+// compact format can't read.
+func (cookie *Cookie) marshalCompactJSON() ([]byte, error) {
+	cookieContract := cookieJson{
+		CompactOptOut: cookie.optOut,
+	}
+	if len(cookie.uids) > 0 {
+		cookieContract.CompactUIDs = make(map[string]compactUID, len(cookie.uids))
+		for key, uid := range cookie.uids {
+			cookieContract.CompactUIDs[key] = compactUID{
+				UID:     uid.UID,
+				Expires: uid.Expires.Unix(),
+			}
+		}
+	}
+	if cookie.birthday != nil {
+		cookieContract.CompactBirthday = cookie.birthday.Unix()
+	}
+	return json.Marshal(cookieContract)
+}
+
 // UnmarshalJSON holds some transition code.
 //
 // "Legacy" cookies had UIDs *without* expiration dates, and recognized "0" as a legitimate UID for audienceNetwork.
@@ -248,12 +436,18 @@ func (cookie *Cookie) MarshalJSON() ([]byte, error) {
 // This Unmarshal method interprets both data formats, and does some conversions on legacy data to make it current.
 // If you're seeing this message after March 2018, it's safe to assume that all the legacy cookies have been
 // updated and remove the legacy logic.
+//
+// Cookies in the verbose format are read as well as the ones in the compact format.
 func (cookie *Cookie) UnmarshalJSON(b []byte) error {
 	var cookieContract cookieJson
 	err := json.Unmarshal(b, &cookieContract)
 	if err == nil {
-		cookie.optOut = cookieContract.OptOut
+		cookie.optOut = cookieContract.OptOut || cookieContract.CompactOptOut
 		cookie.birthday = cookieContract.Birthday
+		if cookieContract.CompactBirthday != 0 {
+			birthday := time.Unix(cookieContract.CompactBirthday, 0)
+			cookie.birthday = &birthday
+		}
 
 		if cookie.optOut {
 			cookie.uids = make(map[string]uidWithExpiry)
@@ -261,7 +455,14 @@ func (cookie *Cookie) UnmarshalJSON(b []byte) error {
 			cookie.uids = cookieContract.UIDs
 
 			if cookie.uids == nil {
-				cookie.uids = make(map[string]uidWithExpiry, len(cookieContract.LegacyUIDs))
+				cookie.uids = make(map[string]uidWithExpiry, len(cookieContract.CompactUIDs)+len(cookieContract.LegacyUIDs))
+			}
+
+			for bidder, uid := range cookieContract.CompactUIDs {
+				cookie.uids[bidder] = uidWithExpiry{
+					UID:     uid.UID,
+					Expires: time.Unix(uid.Expires, 0),
+				}
 			}
 
 			// Interpret "legacy" UIDs as having been expired already.
@@ -467,7 +467,7 @@ func newSampleCookie() *Cookie {
 func writeThenRead(cookie *Cookie, maxCookieSize int) *Cookie {
 	w := httptest.NewRecorder()
 	hostCookie := &config.HostCookie{Domain: "mock-domain", MaxCookieSizeBytes: maxCookieSize}
-	cookie.SetCookieOnResponse(w, false, hostCookie, 90*24*time.Hour)
+	cookie.SetCookieOnResponse(w, CookieAttributes{Domain: "mock-domain"}, hostCookie, 90*24*time.Hour, nil)
 	writtenCookie := w.HeaderMap.Get("Set-Cookie")
 
 	header := http.Header{}
@@ -483,7 +483,7 @@ func TestSetCookieOnResponseForSameSiteNone(t *testing.T) {
 	ua := "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_14_0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/75.0.3770.142 Safari/537.36"
 	req.Header.Set("User-Agent", ua)
 	hostCookie := &config.HostCookie{Domain: "mock-domain", MaxCookieSizeBytes: 0}
-	cookie.SetCookieOnResponse(w, true, hostCookie, 90*24*time.Hour)
+	cookie.SetCookieOnResponse(w, CookieAttributes{Domain: "mock-domain", SameSite: http.SameSiteNoneMode, Secure: true}, hostCookie, 90*24*time.Hour, nil)
 	writtenCookie := w.HeaderMap.Get("Set-Cookie")
 	t.Log("Set-Cookie is: ", writtenCookie)
 	if !strings.Contains(writtenCookie, "; Secure;") {
@@ -498,10 +498,273 @@ func TestSetCookieOnResponseForOlderChromeVersion(t *testing.T) {
 	ua := "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_14_0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/65.0.3770.142 Safari/537.36"
 	req.Header.Set("User-Agent", ua)
 	hostCookie := &config.HostCookie{Domain: "mock-domain", MaxCookieSizeBytes: 0}
-	cookie.SetCookieOnResponse(w, false, hostCookie, 90*24*time.Hour)
+	cookie.SetCookieOnResponse(w, CookieAttributes{Domain: "mock-domain"}, hostCookie, 90*24*time.Hour, nil)
 	writtenCookie := w.HeaderMap.Get("Set-Cookie")
 	t.Log("Set-Cookie is: ", writtenCookie)
 	if strings.Contains(writtenCookie, "SameSite=none") {
 		t.Error("Set-Cookie should not contain SameSite=none")
 	}
 }
+
+func TestSplitCookieAcrossMultipleCookies(t *testing.T) {
+	testCases := []struct {
+		description         string
+		maxCookieSize       int
+		maxCookies          int
+		expectedCookieNames []string
+		expectedKeys        []string
+	}{
+		{
+			description:         "Fits in one cookie",
+			maxCookieSize:       2000,
+			maxCookies:          3,
+			expectedCookieNames: []string{"uids", "uids2", "uids3"},
+			expectedKeys:        []string{"k1", "k2", "k3", "k4", "k5", "k6", "k7"},
+		},
+		{
+			description:         "Split across two cookies",
+			maxCookieSize:       500,
+			maxCookies:          3,
+			expectedCookieNames: []string{"uids", "uids2", "uids3"},
+			expectedKeys:        []string{"k1", "k2", "k3", "k4", "k5", "k6", "k7"},
+		},
+		{
+			description:         "Trimmed to fit in three cookies",
+			maxCookieSize:       300,
+			maxCookies:          3,
+			expectedCookieNames: []string{"uids", "uids2", "uids3"},
+			expectedKeys:        []string{"k1", "k3", "k4", "k5", "k6", "k7"},
+		},
+		{
+			description:         "Trimmed to fit in two cookies",
+			maxCookieSize:       300,
+			maxCookies:          2,
+			expectedCookieNames: []string{"uids", "uids2"},
+			expectedKeys:        []string{"k1", "k3", "k4"},
+		},
+		{
+			description:         "Max cookies unset",
+			maxCookieSize:       500,
+			maxCookies:          0,
+			expectedCookieNames: []string{"uids"},
+			expectedKeys:        []string{"k1", "k3", "k4"},
+		},
+	}
+
+	for _, test := range testCases {
+		cookie := newLargeCookie()
+		hostCookie := &config.HostCookie{Domain: "mock-domain", MaxCookieSizeBytes: test.maxCookieSize, MaxCookies: test.maxCookies, CompactFormat: true}
+
+		w := httptest.NewRecorder()
+		cookie.SetCookieOnResponse(w, CookieAttributes{Domain: "mock-domain", SameSite: http.SameSiteNoneMode, Secure: true}, hostCookie, 90*24*time.Hour, nil)
+
+		request := http.Request{Header: http.Header{}}
+		cookieNames := make([]string, 0, len(test.expectedCookieNames))
+		for _, httpCookie := range w.Result().Cookies() {
+			cookieNames = append(cookieNames, httpCookie.Name)
+			assert.LessOrEqual(t, len(httpCookie.String()), test.maxCookieSize, test.description+":size")
+			assert.True(t, httpCookie.Secure, test.description+":secure")
+			if httpCookie.MaxAge >= 0 {
+				request.AddCookie(httpCookie)
+			}
+		}
+		assert.Equal(t, test.expectedCookieNames, cookieNames, test.description+":names")
+
+		parsed := ParseCookieFromRequest(&request, hostCookie)
+		actualKeys := make([]string, 0, len(parsed.uids))
+		for key := range parsed.uids {
+			actualKeys = append(actualKeys, key)
+		}
+		assert.ElementsMatch(t, test.expectedKeys, actualKeys, test.description+":keys")
+	}
+}
+
+func TestSplitCookieExpiresUnusedCookies(t *testing.T) {
+	hostCookie := &config.HostCookie{Domain: "mock-domain", MaxCookieSizeBytes: 2000, MaxCookies: 3}
+
+	w := httptest.NewRecorder()
+	newSampleCookie().SetCookieOnResponse(w, CookieAttributes{Domain: "mock-domain"}, hostCookie, 90*24*time.Hour, nil)
+
+	httpCookies := w.Result().Cookies()
+	if assert.Len(t, httpCookies, 3) {
+		assert.Equal(t, "uids", httpCookies[0].Name)
+		assert.Equal(t, 0, httpCookies[0].MaxAge)
+		assert.Equal(t, "uids2", httpCookies[1].Name)
+		assert.Equal(t, -1, httpCookies[1].MaxAge)
+		assert.Equal(t, "uids3", httpCookies[2].Name)
+		assert.Equal(t, -1, httpCookies[2].MaxAge)
+	}
+}
+
+func TestParseCookieFromRequestIgnoresCookiesAfterAGap(t *testing.T) {
+	value := newSampleCookie().ToHTTPCookie(time.Hour).Value
+
+	request := http.Request{Header: http.Header{}}
+	request.AddCookie(&http.Cookie{Name: "uids", Value: value[:10]})
+	request.AddCookie(&http.Cookie{Name: "uids2", Value: value[10:]})
+	request.AddCookie(&http.Cookie{Name: "uids4", Value: "garbage"})
+
+	parsed := ParseCookieFromRequest(&request, &config.HostCookie{MaxCookies: 4})
+	assert.Equal(t, map[string]string{"adnxs": "123", "rubicon": "456"}, parsed.GetUIDs())
+}
+
+func TestParseCookieFromRequestIgnoresCookiesBeyondMaxCookies(t *testing.T) {
+	value := newSampleCookie().ToHTTPCookie(time.Hour).Value
+
+	request := http.Request{Header: http.Header{}}
+	request.AddCookie(&http.Cookie{Name: "uids", Value: value[:10]})
+	request.AddCookie(&http.Cookie{Name: "uids2", Value: value[10:]})
+	request.AddCookie(&http.Cookie{Name: "uids3", Value: "garbage"})
+
+	parsed := ParseCookieFromRequest(&request, &config.HostCookie{MaxCookies: 2})
+	assert.Equal(t, map[string]string{"adnxs": "123", "rubicon": "456"}, parsed.GetUIDs())
+}
+
+func TestVerboseCookieRead(t *testing.T) {
+	expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	verboseJson := `{"tempUIDs":{"adnxs":{"uid":"123","expires":"` + expires.Format(time.RFC3339) + `"}},"bday":"2017-08-03T21:04:52Z"}`
+
+	var cookie Cookie
+	assert.NoError(t, json.Unmarshal([]byte(verboseJson), &cookie))
+
+	uid, exists, isLive := cookie.GetUID("adnxs")
+	assert.Equal(t, "123", uid)
+	assert.True(t, exists)
+	assert.True(t, isLive)
+	assert.True(t, cookie.AllowSyncs())
+	assert.Equal(t, time.Date(2017, 8, 3, 21, 4, 52, 0, time.UTC), *cookie.birthday)
+
+	verboseOptOutJson := `{"optout":true,"bday":"2017-08-03T21:04:52Z"}`
+	var optOutCookie Cookie
+	assert.NoError(t, json.Unmarshal([]byte(verboseOptOutJson), &optOutCookie))
+	assert.False(t, optOutCookie.AllowSyncs())
+}
+
+func TestCookieWriteFormats(t *testing.T) {
+	expires := time.Unix(1700000000, 0)
+	birthday := time.Unix(1600000000, 0)
+	cookie := &Cookie{
+		uids:     map[string]uidWithExpiry{"adnxs": {UID: "123", Expires: expires}},
+		birthday: &birthday,
+	}
+
+	verboseJson, err := json.Marshal(cookie)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"tempUIDs":{"adnxs":{"uid":"123","expires":"`+expires.Format(time.RFC3339Nano)+`"}},"bday":"`+birthday.Format(time.RFC3339Nano)+`"}`, string(verboseJson))
+
+	compactJson, err := cookie.marshalCompactJSON()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"u":{"adnxs":{"i":"123","e":1700000000}},"b":1600000000}`, string(compactJson))
+
+	var parsed Cookie
+	assert.NoError(t, json.Unmarshal(compactJson, &parsed))
+	assert.True(t, expires.Equal(parsed.uids["adnxs"].Expires))
+	assert.True(t, birthday.Equal(*parsed.birthday))
+}
+
+func newLargeCookie() *Cookie {
+	return &Cookie{
+		uids: map[string]uidWithExpiry{
+			"k1": newTempId("12345678901234567890123456789012345678901234567890", 7),
+			"k2": newTempId("abcdefghijklmnopqrstuvwxyz", 1),
+			"k3": newTempId("ABCDEFGHIJKLMNOPQRSTUVWXYZ", 6),
+			"k4": newTempId("12345678901234567890123456789612345678901234567890", 5),
+			"k5": newTempId("aAbBcCdDeEfFgGhHiIjJkKlLmMnNoOpPqQrRsStTuUvVwWxXyYzZ", 4),
+			"k6": newTempId("12345678901234567890123456789012345678901234567890", 3),
+			"k7": newTempId("abcdefghijklmnopqrstuvwxyz", 2),
+		},
+		birthday: timestamp(),
+	}
+}
+
+func TestTrimCookiesByPriority(t *testing.T) {
+	cookieToSend := &Cookie{
+		uids: map[string]uidWithExpiry{
+			"expired":  newTempId("12345678901234567890123456789012345678901234567890", -1),
+			"low":      newTempId("abcdefghijklmnopqrstuvwxyz", 10),
+			"high":     newTempId("ABCDEFGHIJKLMNOPQRSTUVWXYZ", 1),
+			"medium":   newTempId("12345678901234567890123456789612345678901234567890", 2),
+			"unlisted": newTempId("aAbBcCdDeEfFgGhHiIjJkKlLmMnNoOpPqQrRsStTuUvVwWxXyYzZ", 3),
+		},
+		birthday: timestamp(),
+	}
+	priorities := SyncerPriorities{"expired": 10, "low": -1, "high": 10, "medium": 5}
+
+	testCases := []struct {
+		maxCookieSize int
+		expectedKeys  []string
+	}{
+		{maxCookieSize: 0, expectedKeys: []string{"expired", "low", "high", "medium", "unlisted"}},
+		{maxCookieSize: 550, expectedKeys: []string{"low", "high", "medium", "unlisted"}},
+		{maxCookieSize: 450, expectedKeys: []string{"high", "medium", "unlisted"}},
+		{maxCookieSize: 380, expectedKeys: []string{"high", "medium"}},
+		{maxCookieSize: 250, expectedKeys: []string{"high"}},
+	}
+
+	for _, test := range testCases {
+		w := httptest.NewRecorder()
+		hostCookie := &config.HostCookie{Domain: "mock-domain", MaxCookieSizeBytes: test.maxCookieSize, CompactFormat: true}
+		cookieToSend.SetCookieOnResponse(w, CookieAttributes{Domain: "mock-domain"}, hostCookie, 90*24*time.Hour, priorities)
+
+		header := http.Header{}
+		header.Add("Cookie", w.Header().Get("Set-Cookie"))
+		processedCookie := ParseCookieFromRequest(&http.Request{Header: header}, hostCookie)
+
+		assert.ElementsMatch(t, test.expectedKeys, mapKeys(processedCookie.uids), "Max cookie size %d", test.maxCookieSize)
+	}
+}
+
+func TestTrySyncWithTTL(t *testing.T) {
+	cookie := NewCookie()
+	assert.NoError(t, cookie.TrySyncWithTTL("adnxs", "123", time.Hour))
+	assert.NoError(t, cookie.TrySync("rubicon", "456"))
+
+	expiries := cookie.GetUIDExpiries()
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiries["adnxs"], time.Minute)
+	assert.WithinDuration(t, time.Now().Add(uidTTL), expiries["rubicon"], time.Minute)
+}
+
+func TestNewSyncerPriorities(t *testing.T) {
+	syncersByBidder := map[string]Syncer{
+		"a":     fakeSyncer{key: "keyA", priority: 1},
+		"alias": fakeSyncer{key: "keyA", priority: 1},
+		"b":     fakeSyncer{key: "keyB"},
+	}
+
+	assert.Equal(t, SyncerPriorities{"keyA": 1, "keyB": 0}, NewSyncerPriorities(syncersByBidder))
+}
+
+func mapKeys(uids map[string]uidWithExpiry) []string {
+	keys := make([]string, 0, len(uids))
+	for key := range uids {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestCookieMerge(t *testing.T) {
+	cookie := &Cookie{
+		uids: map[string]uidWithExpiry{
+			"adnxs":   newTempId("cookie-adnxs", 10),
+			"rubicon": newTempId("cookie-rubicon", -10),
+		},
+	}
+	stored := &Cookie{
+		uids: map[string]uidWithExpiry{
+			"adnxs":   newTempId("stored-adnxs", 5),
+			"rubicon": newTempId("stored-rubicon", 5),
+			"openx":   newTempId("stored-openx", 5),
+		},
+	}
+
+	cookie.Merge(stored)
+	assert.Equal(t, map[string]string{"adnxs": "cookie-adnxs", "rubicon": "stored-rubicon", "openx": "stored-openx"}, cookie.GetUIDs())
+
+	optOut := &Cookie{optOut: true}
+	optOut.Merge(stored)
+	assert.Empty(t, optOut.GetUIDs(), "Opt out")
+
+	empty := &Cookie{}
+	empty.Merge(stored)
+	assert.Len(t, empty.GetUIDs(), 3, "Empty")
+}
@@ -0,0 +1,163 @@
+package usersync
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+)
+
+const (
+	chromeStr       = "Chrome/"
+	chromeiOSStr    = "CriOS/"
+	chromeMinVer    = 67
+	chromeStrLen    = len(chromeStr)
+	chromeiOSStrLen = len(chromeiOSStr)
+)
+
+// CookieAttributes are the attributes of the cookies Prebid Server sets: the uids cookies, the host cookie and
+// the opt-out cookie all get the same ones.
+type CookieAttributes struct {
+	Domain      string
+	SameSite    http.SameSite
+	Secure      bool
+	Partitioned bool
+}
+
+// NewCookieAttributes resolves the cookie attributes of the host config, overridden by the account config,
+// for the browser with the user agent.
+func NewCookieAttributes(host *config.HostCookie, account config.AccountCookie, userAgent string) CookieAttributes {
+	attributes := CookieAttributes{
+		Domain:      host.Domain,
+		Partitioned: host.Partitioned,
+	}
+	if account.Domain != "" {
+		attributes.Domain = account.Domain
+	}
+	if account.Partitioned != nil {
+		attributes.Partitioned = *account.Partitioned
+	}
+
+	sameSite := host.SameSite
+	if account.SameSite != "" {
+		sameSite = account.SameSite
+	}
+
+	switch sameSite {
+	case config.SameSiteNone:
+		attributes.SameSite = http.SameSiteNoneMode
+		attributes.Secure = true
+	case config.SameSiteLax:
+		attributes.SameSite = http.SameSiteLaxMode
+	case config.SameSiteStrict:
+		attributes.SameSite = http.SameSiteStrictMode
+	default:
+		if SupportsSameSiteNone(userAgent) {
+			attributes.SameSite = http.SameSiteNoneMode
+			attributes.Secure = true
+		}
+	}
+
+	// Partitioned cookies are only useful in third-party contexts, where browsers only send SameSite=None
+	// cookies, and browsers reject the ones which aren't secure
+	if attributes.Partitioned {
+		attributes.SameSite = http.SameSiteNoneMode
+		attributes.Secure = true
+	}
+	return attributes
+}
+
+// apply sets the attributes on the cookie.
+func (attributes CookieAttributes) apply(httpCookie *http.Cookie) {
+	if attributes.Domain != "" {
+		httpCookie.Domain = attributes.Domain
+	}
+	httpCookie.SameSite = attributes.SameSite
+	httpCookie.Secure = attributes.Secure
+}
+
+// cookieString serializes the cookie for a Set-Cookie header. net/http doesn't support the Partitioned attribute
+// yet, so it is appended here.
+func (attributes CookieAttributes) cookieString(httpCookie *http.Cookie) string {
+	if attributes.Partitioned {
+		return httpCookie.String() + "; Partitioned"
+	}
+	return httpCookie.String()
+}
+
+// setCookie applies the attributes to the cookie and adds it to the response.
+func (attributes CookieAttributes) setCookie(w http.ResponseWriter, httpCookie *http.Cookie) {
+	attributes.apply(httpCookie)
+	w.Header().Add("Set-Cookie", attributes.cookieString(httpCookie))
+}
+
+// SetHostCookieOnResponse sets the host cookie of the request again, so that it gets the cookie attributes and
+// the ttl of the uids cookie. Nothing is set unless the host enables cfg.RefreshHostCookie, nor if the request
+// doesn't have a host cookie.
+func SetHostCookieOnResponse(w http.ResponseWriter, r *http.Request, cfg *config.HostCookie, attributes CookieAttributes, ttl time.Duration) {
+	if !cfg.RefreshHostCookie || cfg.CookieName == "" {
+		return
+	}
+	hostCookie, err := r.Cookie(cfg.CookieName)
+	if err != nil || hostCookie.Value == "" {
+		return
+	}
+
+	attributes.setCookie(w, &http.Cookie{
+		Name:    cfg.CookieName,
+		Value:   hostCookie.Value,
+		Expires: time.Now().Add(ttl),
+		Path:    "/",
+	})
+}
+
+// SetOptOutCookieOnResponse sets the opt-out cookie of the host config when the user opts out, and expires it
+// when they opt in. Nothing is set if the host doesn't configure one.
+func SetOptOutCookieOnResponse(w http.ResponseWriter, cfg *config.HostCookie, attributes CookieAttributes, ttl time.Duration, optOut bool) {
+	if cfg.OptOutCookie.Name == "" {
+		return
+	}
+
+	httpCookie := &http.Cookie{
+		Name: cfg.OptOutCookie.Name,
+		Path: "/",
+	}
+	if optOut {
+		httpCookie.Value = cfg.OptOutCookie.Value
+		httpCookie.Expires = time.Now().Add(ttl)
+	} else {
+		httpCookie.MaxAge = -1
+	}
+	attributes.setCookie(w, httpCookie)
+}
+
+// SupportsSameSiteNone scans the input User Agent string to check if browser is Chrome and browser version is greater than the minimum version for adding the SameSite cookie attribute
+func SupportsSameSiteNone(ua string) bool {
+	result := false
+
+	index := strings.Index(ua, chromeStr)
+	criOSIndex := strings.Index(ua, chromeiOSStr)
+	if index != -1 {
+		result = checkChromeBrowserVersion(ua, index, chromeStrLen)
+	} else if criOSIndex != -1 {
+		result = checkChromeBrowserVersion(ua, criOSIndex, chromeiOSStrLen)
+	}
+
+	return result
+}
+
+func checkChromeBrowserVersion(ua string, index int, chromeStrLength int) bool {
+	result := false
+	vIndex := index + chromeStrLength
+	dotIndex := strings.Index(ua[vIndex:], ".")
+	if dotIndex == -1 {
+		dotIndex = len(ua[vIndex:])
+	}
+	version, _ := strconv.Atoi(ua[vIndex : vIndex+dotIndex])
+	if version >= chromeMinVer {
+		result = true
+	}
+	return result
+}
@@ -0,0 +1,271 @@
+package usersync
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	modernChromeUA  = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_14_0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/75.0.3770.142 Safari/537.36"
+	oldChromeUA     = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_14_0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/65.0.3770.142 Safari/537.36"
+	chromeIOSUA     = "Mozilla/5.0 (iPhone; CPU iPhone OS 16_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/114.0.5735.124 Mobile/15E148 Safari/604.1"
+	safariUA        = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.5 Safari/605.1.15"
+	nonBrowserUA    = "curl/8.1.2"
+	emptyUserAgent  = ""
+	partitionedAttr = "; Partitioned"
+)
+
+func TestSupportsSameSiteNone(t *testing.T) {
+	testCases := []struct {
+		ua             string
+		expectedResult bool
+		description    string
+	}{
+		{
+			ua:             modernChromeUA,
+			expectedResult: true,
+			description:    "Should return true for a valid chrome version",
+		},
+		{
+			ua:             oldChromeUA,
+			expectedResult: false,
+			description:    "Should return false for chrome version below than the supported min version",
+		},
+		{
+			ua:             chromeIOSUA,
+			expectedResult: true,
+			description:    "Should return true for a valid chrome on iOS version",
+		},
+		{
+			ua:             safariUA,
+			expectedResult: false,
+			description:    "Should return false for other browsers",
+		},
+		{
+			ua:             emptyUserAgent,
+			expectedResult: false,
+			description:    "Should return false without a user agent",
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expectedResult, SupportsSameSiteNone(test.ua), test.description)
+	}
+}
+
+func TestNewCookieAttributes(t *testing.T) {
+	enabled, disabled := true, false
+
+	testCases := []struct {
+		description string
+		host        config.HostCookie
+		account     config.AccountCookie
+		ua          string
+		expected    CookieAttributes
+	}{
+		{
+			description: "Auto, modern chrome",
+			host:        config.HostCookie{Domain: "host.com", SameSite: "auto"},
+			ua:          modernChromeUA,
+			expected:    CookieAttributes{Domain: "host.com", SameSite: http.SameSiteNoneMode, Secure: true},
+		},
+		{
+			description: "Auto, chrome on iOS",
+			host:        config.HostCookie{SameSite: "auto"},
+			ua:          chromeIOSUA,
+			expected:    CookieAttributes{SameSite: http.SameSiteNoneMode, Secure: true},
+		},
+		{
+			description: "Auto, old chrome",
+			host:        config.HostCookie{SameSite: "auto"},
+			ua:          oldChromeUA,
+			expected:    CookieAttributes{},
+		},
+		{
+			description: "Auto, other browser",
+			host:        config.HostCookie{SameSite: "auto"},
+			ua:          safariUA,
+			expected:    CookieAttributes{},
+		},
+		{
+			description: "Unset behaves like auto",
+			ua:          modernChromeUA,
+			expected:    CookieAttributes{SameSite: http.SameSiteNoneMode, Secure: true},
+		},
+		{
+			description: "None, old chrome",
+			host:        config.HostCookie{SameSite: "none"},
+			ua:          oldChromeUA,
+			expected:    CookieAttributes{SameSite: http.SameSiteNoneMode, Secure: true},
+		},
+		{
+			description: "None, non browser",
+			host:        config.HostCookie{SameSite: "none"},
+			ua:          nonBrowserUA,
+			expected:    CookieAttributes{SameSite: http.SameSiteNoneMode, Secure: true},
+		},
+		{
+			description: "Lax, modern chrome",
+			host:        config.HostCookie{SameSite: "lax"},
+			ua:          modernChromeUA,
+			expected:    CookieAttributes{SameSite: http.SameSiteLaxMode},
+		},
+		{
+			description: "Strict, other browser",
+			host:        config.HostCookie{SameSite: "strict"},
+			ua:          safariUA,
+			expected:    CookieAttributes{SameSite: http.SameSiteStrictMode},
+		},
+		{
+			description: "Partitioned, old chrome",
+			host:        config.HostCookie{SameSite: "auto", Partitioned: true},
+			ua:          oldChromeUA,
+			expected:    CookieAttributes{SameSite: http.SameSiteNoneMode, Secure: true, Partitioned: true},
+		},
+		{
+			description: "Partitioned forces SameSite None",
+			host:        config.HostCookie{SameSite: "strict", Partitioned: true},
+			ua:          modernChromeUA,
+			expected:    CookieAttributes{SameSite: http.SameSiteNoneMode, Secure: true, Partitioned: true},
+		},
+		{
+			description: "Partitioned, modern chrome",
+			host:        config.HostCookie{SameSite: "auto", Partitioned: true},
+			ua:          modernChromeUA,
+			expected:    CookieAttributes{SameSite: http.SameSiteNoneMode, Secure: true, Partitioned: true},
+		},
+		{
+			description: "Account overrides",
+			host:        config.HostCookie{Domain: "host.com", SameSite: "auto", Partitioned: true},
+			account:     config.AccountCookie{Domain: "publisher.host.com", SameSite: "lax", Partitioned: &disabled},
+			ua:          modernChromeUA,
+			expected:    CookieAttributes{Domain: "publisher.host.com", SameSite: http.SameSiteLaxMode},
+		},
+		{
+			description: "Account enables partitioned",
+			host:        config.HostCookie{Domain: "host.com", SameSite: "none"},
+			account:     config.AccountCookie{Partitioned: &enabled},
+			ua:          safariUA,
+			expected:    CookieAttributes{Domain: "host.com", SameSite: http.SameSiteNoneMode, Secure: true, Partitioned: true},
+		},
+	}
+
+	for _, test := range testCases {
+		attributes := NewCookieAttributes(&test.host, test.account, test.ua)
+		assert.Equal(t, test.expected, attributes, test.description)
+	}
+}
+
+func TestSetCookieOnResponsePartitioned(t *testing.T) {
+	hostCookie := &config.HostCookie{MaxCookieSizeBytes: 500, MaxCookies: 3}
+	attributes := CookieAttributes{Domain: "mock-domain", SameSite: http.SameSiteNoneMode, Secure: true, Partitioned: true}
+
+	w := httptest.NewRecorder()
+	newLargeCookie().SetCookieOnResponse(w, attributes, hostCookie, 90*24*time.Hour, nil)
+
+	setCookies := w.Header().Values("Set-Cookie")
+	if assert.Len(t, setCookies, 3) {
+		for _, setCookie := range setCookies {
+			assert.True(t, strings.HasSuffix(setCookie, partitionedAttr), setCookie)
+			assert.Contains(t, setCookie, "; Secure; SameSite=None", setCookie)
+			assert.Contains(t, setCookie, "; Domain=mock-domain", setCookie)
+			assert.LessOrEqual(t, len(setCookie), hostCookie.MaxCookieSizeBytes, setCookie)
+		}
+	}
+}
+
+func TestSetCookieOnResponseAttributesOfExpiredCookies(t *testing.T) {
+	hostCookie := &config.HostCookie{MaxCookieSizeBytes: 2000, MaxCookies: 2}
+	attributes := CookieAttributes{SameSite: http.SameSiteLaxMode, Secure: true, Partitioned: true}
+
+	w := httptest.NewRecorder()
+	newSampleCookie().SetCookieOnResponse(w, attributes, hostCookie, 90*24*time.Hour, nil)
+
+	setCookies := w.Header().Values("Set-Cookie")
+	if assert.Len(t, setCookies, 2) {
+		assert.True(t, strings.HasPrefix(setCookies[1], "uids2=;"), setCookies[1])
+		assert.Contains(t, setCookies[1], "; Secure; SameSite=Lax; Partitioned")
+	}
+}
+
+func TestSetHostCookieOnResponse(t *testing.T) {
+	attributes := CookieAttributes{Domain: "host.com", SameSite: http.SameSiteNoneMode, Secure: true, Partitioned: true}
+
+	testCases := []struct {
+		description       string
+		hostCookie        config.HostCookie
+		requestCookie     *http.Cookie
+		expectedSetCookie bool
+	}{
+		{
+			description:       "Host cookie",
+			hostCookie:        config.HostCookie{CookieName: "host", RefreshHostCookie: true},
+			requestCookie:     &http.Cookie{Name: "host", Value: "123"},
+			expectedSetCookie: true,
+		},
+		{
+			description:   "Refresh not enabled",
+			hostCookie:    config.HostCookie{CookieName: "host"},
+			requestCookie: &http.Cookie{Name: "host", Value: "123"},
+		},
+		{
+			description:   "No host cookie in the request",
+			hostCookie:    config.HostCookie{CookieName: "host", RefreshHostCookie: true},
+			requestCookie: &http.Cookie{Name: "other", Value: "123"},
+		},
+		{
+			description:   "No host cookie configured",
+			hostCookie:    config.HostCookie{RefreshHostCookie: true},
+			requestCookie: &http.Cookie{Name: "host", Value: "123"},
+		},
+	}
+
+	for _, test := range testCases {
+		r := httptest.NewRequest("GET", "http://prebid-server.com/setuid", nil)
+		r.AddCookie(test.requestCookie)
+		w := httptest.NewRecorder()
+
+		SetHostCookieOnResponse(w, r, &test.hostCookie, attributes, time.Hour)
+
+		setCookies := w.Header().Values("Set-Cookie")
+		if !test.expectedSetCookie {
+			assert.Empty(t, setCookies, test.description)
+		} else if assert.Len(t, setCookies, 1, test.description) {
+			assert.True(t, strings.HasPrefix(setCookies[0], "host=123; Path=/; Domain=host.com; Expires="), test.description)
+			assert.True(t, strings.HasSuffix(setCookies[0], "; Secure; SameSite=None; Partitioned"), test.description)
+		}
+	}
+}
+
+func TestSetOptOutCookieOnResponse(t *testing.T) {
+	hostCookie := &config.HostCookie{OptOutCookie: config.Cookie{Name: "optout", Value: "true"}}
+	attributes := CookieAttributes{SameSite: http.SameSiteStrictMode}
+
+	w := httptest.NewRecorder()
+	SetOptOutCookieOnResponse(w, hostCookie, attributes, time.Hour, true)
+	httpCookies := w.Result().Cookies()
+	if assert.Len(t, httpCookies, 1, "opt out") {
+		assert.Equal(t, "optout", httpCookies[0].Name, "opt out")
+		assert.Equal(t, "true", httpCookies[0].Value, "opt out")
+		assert.Equal(t, http.SameSiteStrictMode, httpCookies[0].SameSite, "opt out")
+		assert.Equal(t, 0, httpCookies[0].MaxAge, "opt out")
+	}
+
+	w = httptest.NewRecorder()
+	SetOptOutCookieOnResponse(w, hostCookie, attributes, time.Hour, false)
+	httpCookies = w.Result().Cookies()
+	if assert.Len(t, httpCookies, 1, "opt in") {
+		assert.Equal(t, "optout", httpCookies[0].Name, "opt in")
+		assert.Equal(t, -1, httpCookies[0].MaxAge, "opt in")
+	}
+
+	w = httptest.NewRecorder()
+	SetOptOutCookieOnResponse(w, &config.HostCookie{}, attributes, time.Hour, true)
+	assert.Empty(t, w.Header().Values("Set-Cookie"), "no opt-out cookie configured")
+}
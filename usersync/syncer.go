@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strings"
 	"text/template"
+	"time"
 
 	validator "github.com/asaskevich/govalidator"
 	"github.com/prebid/prebid-server/config"
@@ -36,6 +37,13 @@ type Syncer interface {
 	// GetSync returns a user sync for the user's device to perform, or an error if the none of the
 	// sync types are supported or if macro substitution fails.
 	GetSync(syncTypes []SyncType, privacyPolicies privacy.Policies) (Sync, error)
+
+	// UIDTTL is the amount of time a user id stored for this syncer is considered valid.
+	UIDTTL() time.Duration
+
+	// Priority ranks this syncer against the others when choosing syncers and when trimming the cookie.
+	// Higher values come first.
+	Priority() int
 }
 
 // Sync represents a user sync to be performed by the user's device.
@@ -51,6 +59,8 @@ type standardSyncer struct {
 	iframe          *template.Template
 	redirect        *template.Template
 	supportCORS     bool
+	uidTTL          time.Duration
+	priority        int
 }
 
 const (
@@ -60,6 +70,7 @@ const (
 
 var ErrSyncerEndpointRequired = errors.New("at least one endpoint (iframe and/or redirect) is required")
 var ErrSyncerKeyRequired = errors.New("key is required")
+var ErrSyncerUIDTTLNegative = errors.New("uid ttl days must not be negative")
 
 // NewSyncer creates a new Syncer from the provided configuration, or return an error if macro substition
 // fails or an endpoint url is invalid.
@@ -72,10 +83,16 @@ func NewSyncer(hostConfig config.UserSync, syncerConfig config.Syncer) (Syncer,
 		return nil, ErrSyncerEndpointRequired
 	}
 
+	if syncerConfig.UIDTTLDays < 0 {
+		return nil, ErrSyncerUIDTTLNegative
+	}
+
 	syncer := standardSyncer{
 		key:             syncerConfig.Key,
 		defaultSyncType: resolveDefaultSyncType(syncerConfig),
 		supportCORS:     syncerConfig.SupportCORS != nil && *syncerConfig.SupportCORS,
+		uidTTL:          resolveUIDTTL(syncerConfig),
+		priority:        syncerConfig.Priority,
 	}
 
 	if syncerConfig.IFrame != nil {
@@ -110,6 +127,13 @@ func resolveDefaultSyncType(syncerConfig config.Syncer) SyncType {
 	return SyncTypeRedirect
 }
 
+func resolveUIDTTL(syncerConfig config.Syncer) time.Duration {
+	if syncerConfig.UIDTTLDays > 0 {
+		return time.Duration(syncerConfig.UIDTTLDays) * 24 * time.Hour
+	}
+	return uidTTL
+}
+
 // macro substitution regex
 var (
 	macroRegexExternalHost = regexp.MustCompile(`{{\s*\.ExternalURL\s*}}`)
@@ -172,6 +196,8 @@ var templateTestValues = macros.UserSyncTemplateParams{
 	GDPR:        "anyGDPR",
 	GDPRConsent: "anyGDPRConsent",
 	USPrivacy:   "anyCCPAConsent",
+	GPP:         "anyGPPConsent",
+	GPPSID:      "anyGPPSID",
 }
 
 func validateTemplate(template *template.Template) error {
@@ -229,6 +255,8 @@ func (s standardSyncer) GetSync(syncTypes []SyncType, privacyPolicies privacy.Po
 		GDPR:        privacyPolicies.GDPR.Signal,
 		GDPRConsent: privacyPolicies.GDPR.Consent,
 		USPrivacy:   privacyPolicies.CCPA.Consent,
+		GPP:         privacyPolicies.GPP.Consent,
+		GPPSID:      privacyPolicies.GPP.SIDString(),
 	})
 	if err != nil {
 		return Sync{}, err
@@ -242,6 +270,14 @@ func (s standardSyncer) GetSync(syncTypes []SyncType, privacyPolicies privacy.Po
 	return sync, nil
 }
 
+func (s standardSyncer) UIDTTL() time.Duration {
+	return s.uidTTL
+}
+
+func (s standardSyncer) Priority() int {
+	return s.priority
+}
+
 func (s standardSyncer) chooseSyncType(syncTypes []SyncType) (SyncType, error) {
 	if len(syncTypes) == 0 {
 		return SyncTypeUnknown, errNoSyncTypesProvided
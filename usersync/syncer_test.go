@@ -3,12 +3,14 @@ package usersync
 import (
 	"testing"
 	"text/template"
+	"time"
 
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/macros"
 	"github.com/prebid/prebid-server/privacy"
 	"github.com/prebid/prebid-server/privacy/ccpa"
 	"github.com/prebid/prebid-server/privacy/gdpr"
+	"github.com/prebid/prebid-server/privacy/gpp"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -141,6 +143,58 @@ func TestNewSyncer(t *testing.T) {
 	}
 }
 
+func TestNewSyncerUIDTTLAndPriority(t *testing.T) {
+	hostConfig := config.UserSync{ExternalURL: "http://host.com", RedirectURL: "{{.ExternalURL}}/host"}
+	redirectConfig := &config.SyncerEndpoint{URL: "https://bidder.com/redirect?redirect={{.RedirectURL}}"}
+
+	testCases := []struct {
+		description      string
+		givenUIDTTLDays  int
+		givenPriority    int
+		expectedError    string
+		expectedUIDTTL   time.Duration
+		expectedPriority int
+	}{
+		{
+			description:      "Default",
+			expectedUIDTTL:   14 * 24 * time.Hour,
+			expectedPriority: 0,
+		},
+		{
+			description:      "Specified",
+			givenUIDTTLDays:  30,
+			givenPriority:    10,
+			expectedUIDTTL:   30 * 24 * time.Hour,
+			expectedPriority: 10,
+		},
+		{
+			description:     "Negative UID TTL",
+			givenUIDTTLDays: -1,
+			expectedError:   "uid ttl days must not be negative",
+		},
+	}
+
+	for _, test := range testCases {
+		syncerConfig := config.Syncer{
+			Key:        "a",
+			Redirect:   redirectConfig,
+			UIDTTLDays: test.givenUIDTTLDays,
+			Priority:   test.givenPriority,
+		}
+
+		result, err := NewSyncer(hostConfig, syncerConfig)
+
+		if test.expectedError == "" {
+			if assert.NoError(t, err, test.description+":err") {
+				assert.Equal(t, test.expectedUIDTTL, result.UIDTTL(), test.description+":uid_ttl")
+				assert.Equal(t, test.expectedPriority, result.Priority(), test.description+":priority")
+			}
+		} else {
+			assert.EqualError(t, err, test.expectedError, test.description+":err")
+		}
+	}
+}
+
 func TestResolveDefaultSyncType(t *testing.T) {
 	anyEndpoint := &config.SyncerEndpoint{}
 
@@ -645,6 +699,13 @@ func TestSyncerGetSync(t *testing.T) {
 			givenPrivacyPolicies: privacy.Policies{GDPR: gdpr.Policy{Signal: "A", Consent: "B"}, CCPA: ccpa.Policy{Consent: "C"}},
 			expectedSync:         Sync{URL: "redirect,gdpr:A,gdprconsent:B,ccpa:C", Type: SyncTypeRedirect, SupportCORS: false},
 		},
+		{
+			description:          "GPP",
+			givenSyncer:          standardSyncer{iframe: template.Must(template.New("test").Parse("iframe,gpp:{{.GPP}},gppsid:{{.GPPSID}}"))},
+			givenSyncTypes:       []SyncType{SyncTypeIFrame},
+			givenPrivacyPolicies: privacy.Policies{GPP: gpp.Policy{Consent: "D", SID: []int8{7, 8}}},
+			expectedSync:         Sync{URL: "iframe,gpp:D,gppsid:7,8", Type: SyncTypeIFrame, SupportCORS: false},
+		},
 		{
 			description:          "Macro Error",
 			givenSyncer:          standardSyncer{iframe: malformedTemplate},
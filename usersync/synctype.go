@@ -20,6 +20,8 @@ const (
 type SyncTypeFilter struct {
 	IFrame   BidderFilter
 	Redirect BidderFilter
+	// Preferred is the sync type used instead of the syncer's default when both are permitted and supported.
+	Preferred SyncType
 }
 
 // ForBidder returns a slice of sync types the bidder is permitted to use.
@@ -36,3 +38,19 @@ func (t SyncTypeFilter) ForBidder(bidder string) []SyncType {
 
 	return syncTypes
 }
+
+// ForSyncer returns the sync types the bidder is permitted to use with the syncer. Only the preferred sync type
+// is returned when it's permitted and the syncer supports it, so that it's chosen over the syncer's default.
+func (t SyncTypeFilter) ForSyncer(bidder string, syncer Syncer) []SyncType {
+	syncTypes := t.ForBidder(bidder)
+	if t.Preferred == SyncTypeUnknown {
+		return syncTypes
+	}
+
+	for _, syncType := range syncTypes {
+		if syncType == t.Preferred && syncer.SupportsType([]SyncType{syncType}) {
+			return []SyncType{syncType}
+		}
+	}
+	return syncTypes
+}
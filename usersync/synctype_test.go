@@ -50,3 +50,57 @@ func TestSyncTypeFilter(t *testing.T) {
 		assert.ElementsMatch(t, test.expectedSyncTypes, syncTypes, test.description)
 	}
 }
+
+func TestSyncTypeFilterForSyncer(t *testing.T) {
+	bidder := "foo"
+
+	bidderFilterAllowed := NewUniformBidderFilter(BidderFilterModeInclude)
+	bidderFilterNotAllowed := NewUniformBidderFilter(BidderFilterModeExclude)
+
+	testCases := []struct {
+		description         string
+		givenIFrameFilter   BidderFilter
+		givenRedirectFilter BidderFilter
+		givenPreferred      SyncType
+		givenSyncer         Syncer
+		expectedSyncTypes   []SyncType
+	}{
+		{
+			description:         "No Preference",
+			givenIFrameFilter:   bidderFilterAllowed,
+			givenRedirectFilter: bidderFilterAllowed,
+			givenSyncer:         fakeSyncer{supportsIFrame: true, supportsRedirect: true},
+			expectedSyncTypes:   []SyncType{SyncTypeIFrame, SyncTypeRedirect},
+		},
+		{
+			description:         "Preferred Supported",
+			givenIFrameFilter:   bidderFilterAllowed,
+			givenRedirectFilter: bidderFilterAllowed,
+			givenPreferred:      SyncTypeRedirect,
+			givenSyncer:         fakeSyncer{supportsIFrame: true, supportsRedirect: true},
+			expectedSyncTypes:   []SyncType{SyncTypeRedirect},
+		},
+		{
+			description:         "Preferred Not Supported By Syncer",
+			givenIFrameFilter:   bidderFilterAllowed,
+			givenRedirectFilter: bidderFilterAllowed,
+			givenPreferred:      SyncTypeRedirect,
+			givenSyncer:         fakeSyncer{supportsIFrame: true},
+			expectedSyncTypes:   []SyncType{SyncTypeIFrame, SyncTypeRedirect},
+		},
+		{
+			description:         "Preferred Not Permitted",
+			givenIFrameFilter:   bidderFilterAllowed,
+			givenRedirectFilter: bidderFilterNotAllowed,
+			givenPreferred:      SyncTypeRedirect,
+			givenSyncer:         fakeSyncer{supportsIFrame: true, supportsRedirect: true},
+			expectedSyncTypes:   []SyncType{SyncTypeIFrame},
+		},
+	}
+
+	for _, test := range testCases {
+		syncTypeFilter := SyncTypeFilter{IFrame: test.givenIFrameFilter, Redirect: test.givenRedirectFilter, Preferred: test.givenPreferred}
+		syncTypes := syncTypeFilter.ForSyncer(bidder, test.givenSyncer)
+		assert.Equal(t, test.expectedSyncTypes, syncTypes, test.description)
+	}
+}
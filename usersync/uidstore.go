@@ -0,0 +1,223 @@
+package usersync
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/util/task"
+	"github.com/prebid/prebid-server/util/timeutil"
+)
+
+// UIDStore keeps the user syncs on the server, keyed by the host cookie of the user, for browsers which
+// drop the uids cookie.
+type UIDStore interface {
+	// Get returns the user syncs stored for the id, or nil if there are none.
+	Get(id string) (*Cookie, error)
+
+	// Set stores the user syncs for the id, replacing the previous ones.
+	Set(id string, cookie *Cookie) error
+
+	// Delete removes the user syncs stored for the id.
+	Delete(id string) error
+}
+
+// NewUIDStore creates the UID store of the host config, or returns nil if it isn't enabled. The expired user
+// syncs are removed from the store every cfg.CleanupIntervalSeconds.
+func NewUIDStore(cfg config.UIDStore) (UIDStore, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	ttl := time.Duration(cfg.TTLDays) * 24 * time.Hour
+	var store cleanableUIDStore
+	switch cfg.Type {
+	case config.UIDStoreTypeMemory:
+		store = newMemoryUIDStore(ttl, cfg.MaxEntries)
+	case config.UIDStoreTypeFile:
+		fileStore, err := newFileUIDStore(cfg.Path, ttl, cfg.MaxEntries)
+		if err != nil {
+			return nil, err
+		}
+		store = fileStore
+	default:
+		return nil, fmt.Errorf("unknown uid store type %s", cfg.Type)
+	}
+
+	task.NewTickerTask(time.Duration(cfg.CleanupIntervalSeconds)*time.Second, store).Start()
+	return store, nil
+}
+
+// cleanableUIDStore is a UID store whose expired user syncs are removed when it runs.
+type cleanableUIDStore interface {
+	UIDStore
+	task.Runner
+}
+
+// UIDStoreHostCookieID returns the id the user syncs of the request are stored under for the host cookie, or an
+// empty string if the user doesn't have one.
+func UIDStoreHostCookieID(r *http.Request, cfg *config.HostCookie) string {
+	if cfg.CookieName != "" {
+		if hostCookie, err := r.Cookie(cfg.CookieName); err == nil && hostCookie.Value != "" {
+			return "host:" + hostCookie.Value
+		}
+	}
+	return ""
+}
+
+// UpdateUIDStore applies update to the user syncs stored for the id and stores the result.
+func UpdateUIDStore(store UIDStore, id string, update func(cookie *Cookie)) error {
+	cookie, err := store.Get(id)
+	if err != nil {
+		return err
+	}
+	if cookie == nil {
+		cookie = NewCookie()
+	}
+
+	update(cookie)
+	return store.Set(id, cookie)
+}
+
+// uidStoreEntry holds the user syncs of an id, in the cookie format, and when they were stored.
+type uidStoreEntry struct {
+	id      string
+	data    []byte
+	updated time.Time
+}
+
+// uidStoreEntries keeps the entries of a store in the order they were stored, so that the expired entries and
+// the ones to evict when the store is full are the first ones. It isn't safe for concurrent use.
+type uidStoreEntries struct {
+	ttl        time.Duration
+	maxEntries int
+	byID       map[string]*list.Element
+	byAge      *list.List
+}
+
+func newUIDStoreEntries(ttl time.Duration, maxEntries int) *uidStoreEntries {
+	return &uidStoreEntries{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		byID:       make(map[string]*list.Element),
+		byAge:      list.New(),
+	}
+}
+
+func (e *uidStoreEntries) get(id string, now time.Time) ([]byte, bool) {
+	element, ok := e.byID[id]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*uidStoreEntry)
+	if e.expired(entry, now) {
+		return nil, false
+	}
+	return entry.data, true
+}
+
+// set stores the entry as the newest one and evicts the oldest entries beyond maxEntries.
+func (e *uidStoreEntries) set(id string, data []byte, updated time.Time) {
+	e.delete(id)
+	e.byID[id] = e.byAge.PushBack(&uidStoreEntry{id: id, data: data, updated: updated})
+
+	for e.maxEntries > 0 && e.byAge.Len() > e.maxEntries {
+		e.delete(e.byAge.Front().Value.(*uidStoreEntry).id)
+	}
+}
+
+func (e *uidStoreEntries) delete(id string) bool {
+	element, ok := e.byID[id]
+	if !ok {
+		return false
+	}
+	e.byAge.Remove(element)
+	delete(e.byID, id)
+	return true
+}
+
+// removeExpired removes the entries stored more than ttl ago.
+func (e *uidStoreEntries) removeExpired(now time.Time) {
+	for element := e.byAge.Front(); element != nil; element = e.byAge.Front() {
+		entry := element.Value.(*uidStoreEntry)
+		if !e.expired(entry, now) {
+			return
+		}
+		e.delete(entry.id)
+	}
+}
+
+func (e *uidStoreEntries) expired(entry *uidStoreEntry, now time.Time) bool {
+	return e.ttl > 0 && !now.Before(entry.updated.Add(e.ttl))
+}
+
+type memoryUIDStore struct {
+	lock    sync.RWMutex
+	entries *uidStoreEntries
+	time    timeutil.Time
+}
+
+// NewMemoryUIDStore returns a UID store which keeps the user syncs in memory, for ttl after they were stored and
+// for up to maxEntries ids. They are lost on restart.
+func NewMemoryUIDStore(ttl time.Duration, maxEntries int) UIDStore {
+	return newMemoryUIDStore(ttl, maxEntries)
+}
+
+func newMemoryUIDStore(ttl time.Duration, maxEntries int) *memoryUIDStore {
+	return &memoryUIDStore{
+		entries: newUIDStoreEntries(ttl, maxEntries),
+		time:    &timeutil.RealTime{},
+	}
+}
+
+func (s *memoryUIDStore) Get(id string) (*Cookie, error) {
+	s.lock.RLock()
+	data, ok := s.entries.get(id, s.time.Now())
+	s.lock.RUnlock()
+
+	if !ok {
+		return nil, nil
+	}
+	return unmarshalStoredCookie(data)
+}
+
+func (s *memoryUIDStore) Set(id string, cookie *Cookie) error {
+	data, err := json.Marshal(cookie)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	s.entries.set(id, data, s.time.Now())
+	s.lock.Unlock()
+	return nil
+}
+
+func (s *memoryUIDStore) Delete(id string) error {
+	s.lock.Lock()
+	s.entries.delete(id)
+	s.lock.Unlock()
+	return nil
+}
+
+// Run removes the expired user syncs.
+func (s *memoryUIDStore) Run() error {
+	s.lock.Lock()
+	s.entries.removeExpired(s.time.Now())
+	s.lock.Unlock()
+	return nil
+}
+
+// unmarshalStoredCookie parses the user syncs of a store. Stores keep them in the cookie format, so that
+// callers can't modify the stored user syncs without calling Set.
+func unmarshalStoredCookie(data []byte) (*Cookie, error) {
+	var cookie Cookie
+	if err := json.Unmarshal(data, &cookie); err != nil {
+		return nil, err
+	}
+	return &cookie, nil
+}
@@ -0,0 +1,191 @@
+package usersync
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/util/timeutil"
+)
+
+// fileUIDStoreRecord is a line of the file UID store. The file is a log of these records, so the last record
+// of an id wins. A record without a cookie deletes the id.
+type fileUIDStoreRecord struct {
+	ID      string          `json:"id"`
+	Cookie  json.RawMessage `json:"cookie,omitempty"`
+	Updated int64           `json:"updated,omitempty"`
+}
+
+type fileUIDStore struct {
+	lock    sync.RWMutex
+	path    string
+	entries *uidStoreEntries
+	file    *os.File
+	time    timeutil.Time
+}
+
+// NewFileUIDStore returns a UID store which keeps the user syncs in memory and in the file at path, from which
+// they are loaded again on restart. They are kept for ttl after they were stored and for up to maxEntries ids.
+// Writes are appended to the file, which is compacted when the store is opened and when it runs.
+func NewFileUIDStore(path string, ttl time.Duration, maxEntries int) (UIDStore, error) {
+	return newFileUIDStore(path, ttl, maxEntries)
+}
+
+func newFileUIDStore(path string, ttl time.Duration, maxEntries int) (*fileUIDStore, error) {
+	store := &fileUIDStore{
+		path:    path,
+		entries: newUIDStoreEntries(ttl, maxEntries),
+		time:    &timeutil.RealTime{},
+	}
+
+	if err := store.read(); err != nil {
+		return nil, err
+	}
+	if err := store.compact(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// read loads the entries of the file, without the expired ones.
+func (s *fileUIDStore) read() error {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	now := s.time.Now()
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// a line without a newline is an interrupted write, which is dropped
+			s.entries.removeExpired(now)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var record fileUIDStoreRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return fmt.Errorf("invalid uid store file %s: %v", s.path, err)
+		}
+		if len(record.Cookie) == 0 {
+			s.entries.delete(record.ID)
+			continue
+		}
+
+		s.entries.set(record.ID, record.Cookie, time.Unix(record.Updated, 0))
+	}
+}
+
+// compact replaces the file with one record per entry, oldest first, and opens it for appending.
+func (s *fileUIDStore) compact() error {
+	tempPath := s.path + ".tmp"
+	file, err := os.OpenFile(tempPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	for element := s.entries.byAge.Front(); element != nil; element = element.Next() {
+		entry := element.Value.(*uidStoreEntry)
+		record := fileUIDStoreRecord{ID: entry.id, Cookie: entry.data, Updated: entry.updated.Unix()}
+		if err := writeFileUIDStoreRecord(writer, record); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tempPath, s.path); err != nil {
+		return err
+	}
+
+	appendFile, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file = appendFile
+	return nil
+}
+
+func writeFileUIDStoreRecord(w io.Writer, record fileUIDStoreRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(line, '\n'))
+	return err
+}
+
+func (s *fileUIDStore) Get(id string) (*Cookie, error) {
+	s.lock.RLock()
+	data, ok := s.entries.get(id, s.time.Now())
+	s.lock.RUnlock()
+
+	if !ok {
+		return nil, nil
+	}
+	return unmarshalStoredCookie(data)
+}
+
+func (s *fileUIDStore) Set(id string, cookie *Cookie) error {
+	data, err := json.Marshal(cookie)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.time.Now()
+	if err := writeFileUIDStoreRecord(s.file, fileUIDStoreRecord{ID: id, Cookie: data, Updated: now.Unix()}); err != nil {
+		return err
+	}
+	s.entries.set(id, data, now)
+	return nil
+}
+
+func (s *fileUIDStore) Delete(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.entries.byID[id]; !ok {
+		return nil
+	}
+	if err := writeFileUIDStoreRecord(s.file, fileUIDStoreRecord{ID: id}); err != nil {
+		return err
+	}
+	s.entries.delete(id)
+	return nil
+}
+
+// Run removes the expired user syncs and compacts the file, which drops the records of the ids which were
+// overwritten, deleted or evicted since the last compaction.
+func (s *fileUIDStore) Run() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.entries.removeExpired(s.time.Now())
+	return s.compact()
+}
@@ -0,0 +1,250 @@
+package usersync
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUIDStore(t *testing.T) {
+	store, err := NewUIDStore(config.UIDStore{Enabled: false, Type: config.UIDStoreTypeMemory})
+	assert.NoError(t, err, "Disabled")
+	assert.Nil(t, store, "Disabled")
+
+	store, err = NewUIDStore(config.UIDStore{Enabled: true, Type: config.UIDStoreTypeMemory})
+	assert.NoError(t, err, "Memory")
+	assert.IsType(t, &memoryUIDStore{}, store, "Memory")
+
+	store, err = NewUIDStore(config.UIDStore{Enabled: true, Type: config.UIDStoreTypeFile, Path: filepath.Join(t.TempDir(), "uids")})
+	assert.NoError(t, err, "File")
+	assert.IsType(t, &fileUIDStore{}, store, "File")
+
+	_, err = NewUIDStore(config.UIDStore{Enabled: true, Type: "redis"})
+	assert.EqualError(t, err, "unknown uid store type redis", "Unknown")
+}
+
+func TestUIDStores(t *testing.T) {
+	fileStore, err := NewFileUIDStore(filepath.Join(t.TempDir(), "uids"), time.Hour, 10)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	stores := map[string]UIDStore{
+		"memory": NewMemoryUIDStore(time.Hour, 10),
+		"file":   fileStore,
+	}
+
+	for name, store := range stores {
+		stored, err := store.Get("user")
+		assert.NoError(t, err, name+":get_missing")
+		assert.Nil(t, stored, name+":get_missing")
+
+		cookie := NewCookie()
+		cookie.TrySync("adnxs", "123")
+		assert.NoError(t, store.Set("user", cookie), name+":set")
+
+		// changes are only stored with Set
+		cookie.TrySync("rubicon", "456")
+
+		stored, err = store.Get("user")
+		assert.NoError(t, err, name+":get")
+		if assert.NotNil(t, stored, name+":get") {
+			assert.Equal(t, map[string]string{"adnxs": "123"}, stored.GetUIDs(), name+":get")
+		}
+
+		assert.NoError(t, store.Delete("user"), name+":delete")
+		assert.NoError(t, store.Delete("missing"), name+":delete_missing")
+
+		stored, err = store.Get("user")
+		assert.NoError(t, err, name+":get_deleted")
+		assert.Nil(t, stored, name+":get_deleted")
+	}
+}
+
+func TestFileUIDStoreReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uids")
+
+	store, err := NewFileUIDStore(path, time.Hour, 10)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	first := NewCookie()
+	first.TrySync("adnxs", "123")
+	second := NewCookie()
+	second.TrySync("rubicon", "456")
+	assert.NoError(t, store.Set("first", first))
+	assert.NoError(t, store.Set("second", second))
+	second.TrySync("adnxs", "789")
+	assert.NoError(t, store.Set("second", second))
+	assert.NoError(t, store.Delete("first"))
+
+	// simulate a write interrupted by a crash
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if assert.NoError(t, err) {
+		file.WriteString(`{"id":"third","cookie":`)
+		file.Close()
+	}
+
+	reloaded, err := NewFileUIDStore(path, time.Hour, 10)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	stored, err := reloaded.Get("first")
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+
+	stored, err = reloaded.Get("second")
+	assert.NoError(t, err)
+	if assert.NotNil(t, stored) {
+		assert.Equal(t, map[string]string{"adnxs": "789", "rubicon": "456"}, stored.GetUIDs())
+	}
+
+	stored, err = reloaded.Get("third")
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+}
+
+func TestFileUIDStoreInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uids")
+	if err := os.WriteFile(path, []byte("not json\n"), 0600); err != nil {
+		t.Fatalf("Failed to write the uid store file: %v", err)
+	}
+
+	_, err := NewFileUIDStore(path, time.Hour, 10)
+	assert.EqualError(t, err, "invalid uid store file "+path+": invalid character 'o' in literal null (expecting 'u')")
+}
+
+func TestUIDStoreHostCookieID(t *testing.T) {
+	testCases := []struct {
+		description        string
+		cookies            []*http.Cookie
+		hostCookieConfig   *config.HostCookie
+		expectedUIDStoreID string
+	}{
+		{
+			description:        "Host cookie",
+			cookies:            []*http.Cookie{{Name: "khaos", Value: "host-id"}},
+			hostCookieConfig:   &config.HostCookie{CookieName: "khaos"},
+			expectedUIDStoreID: "host:host-id",
+		},
+		{
+			description:        "No host cookie",
+			hostCookieConfig:   &config.HostCookie{CookieName: "khaos"},
+			expectedUIDStoreID: "",
+		},
+		{
+			description:        "Host cookie not configured",
+			cookies:            []*http.Cookie{{Name: "khaos", Value: "host-id"}},
+			hostCookieConfig:   &config.HostCookie{},
+			expectedUIDStoreID: "",
+		},
+	}
+
+	for _, test := range testCases {
+		request := http.Request{Header: http.Header{}}
+		for _, cookie := range test.cookies {
+			request.AddCookie(cookie)
+		}
+
+		assert.Equal(t, test.expectedUIDStoreID, UIDStoreHostCookieID(&request, test.hostCookieConfig), test.description)
+	}
+}
+
+func TestUpdateUIDStore(t *testing.T) {
+	store := NewMemoryUIDStore(time.Hour, 10)
+
+	assert.NoError(t, UpdateUIDStore(store, "user", func(cookie *Cookie) { cookie.TrySync("adnxs", "123") }))
+	assert.NoError(t, UpdateUIDStore(store, "user", func(cookie *Cookie) { cookie.TrySyncWithTTL("rubicon", "456", time.Hour) }))
+
+	stored, err := store.Get("user")
+	assert.NoError(t, err)
+	if assert.NotNil(t, stored) {
+		assert.Equal(t, map[string]string{"adnxs": "123", "rubicon": "456"}, stored.GetUIDs())
+	}
+}
+
+func TestUIDStoresExpiryAndEviction(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeTime{time: now}
+
+	memoryStore := newMemoryUIDStore(time.Hour, 2)
+	memoryStore.time = clock
+	fileStore, err := newFileUIDStore(filepath.Join(t.TempDir(), "uids"), time.Hour, 2)
+	if !assert.NoError(t, err) {
+		return
+	}
+	fileStore.time = clock
+
+	stores := map[string]cleanableUIDStore{
+		"memory": memoryStore,
+		"file":   fileStore,
+	}
+
+	for name, store := range stores {
+		clock.time = now
+		assert.NoError(t, store.Set("first", NewCookie()), name)
+		clock.time = now.Add(30 * time.Minute)
+		assert.NoError(t, store.Set("second", NewCookie()), name)
+		assert.NoError(t, store.Set("third", NewCookie()), name)
+
+		stored, err := store.Get("first")
+		assert.NoError(t, err, name+":evicted")
+		assert.Nil(t, stored, name+":evicted")
+
+		clock.time = now.Add(90 * time.Minute)
+		stored, err = store.Get("second")
+		assert.NoError(t, err, name+":expired")
+		assert.Nil(t, stored, name+":expired")
+
+		assert.NoError(t, store.Run(), name+":run")
+		clock.time = now.Add(30 * time.Minute)
+		stored, err = store.Get("second")
+		assert.NoError(t, err, name+":removed")
+		assert.Nil(t, stored, name+":removed")
+	}
+}
+
+func TestFileUIDStoreRunCompacts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uids")
+	store, err := newFileUIDStore(path, time.Hour, 10)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, store.Set("first", NewCookie()))
+	assert.NoError(t, store.Set("first", NewCookie()))
+	assert.NoError(t, store.Set("second", NewCookie()))
+	assert.NoError(t, store.Delete("second"))
+	assert.NoError(t, store.Run())
+
+	data, err := os.ReadFile(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, strings.Count(string(data), "\n"), "One record per entry")
+		assert.Contains(t, string(data), `"id":"first"`)
+	}
+
+	// the store keeps appending to the compacted file
+	assert.NoError(t, store.Set("third", NewCookie()))
+	reloaded, err := NewFileUIDStore(path, time.Hour, 10)
+	if assert.NoError(t, err) {
+		stored, err := reloaded.Get("third")
+		assert.NoError(t, err)
+		assert.NotNil(t, stored)
+	}
+}
+
+type fakeTime struct {
+	time time.Time
+}
+
+func (c *fakeTime) Now() time.Time {
+	return c.time
+}